@@ -17,6 +17,7 @@ package v1
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	secretmanager "cloud.google.com/go/secretmanager/apiv1"
@@ -97,6 +98,50 @@ type Workspace struct {
 
 	// Context contains workspace-level configurations, such as runtimes, topologies, and metadata, etc.
 	Context GenericConfig `yaml:"context,omitempty" json:"context,omitempty"`
+
+	// ModuleTrust is the policy to verify the signatures of the modules before executing them. The modules are
+	// not verified if it's nil.
+	ModuleTrust *ModuleTrustPolicy `yaml:"moduleTrust,omitempty" json:"moduleTrust,omitempty"`
+}
+
+// ModuleTrustPolicy is the policy to verify the signatures of the modules before executing them.
+//
+// Example:
+//
+//	moduleTrust:
+//	  registries:
+//	    ghcr.io/kusionstack:
+//	      keylessIdentities:
+//	        - issuer: https://token.actions.githubusercontent.com
+//	          subject: https://github.com/KusionStack/catalog/.github/workflows/release.yaml@refs/heads/main
+//	    registry.example.com:
+//	      publicKeys:
+//	        - /etc/kusion/cosign.pub
+type ModuleTrustPolicy struct {
+	// Registries are the trusted signers of the modules, keyed by the registry host optionally followed by a
+	// repository prefix, e.g. ghcr.io or ghcr.io/kusionstack. The entry with the longest matching key applies.
+	Registries map[string]*ModuleSigners `yaml:"registries,omitempty" json:"registries,omitempty"`
+
+	// AllowUnlisted allows executing the modules from the registries not listed without verification.
+	AllowUnlisted bool `yaml:"allowUnlisted,omitempty" json:"allowUnlisted,omitempty"`
+}
+
+// ModuleSigners are the trusted signers of the modules, a module is trusted if it's signed by any of them.
+type ModuleSigners struct {
+	// PublicKeys are the cosign public keys, each of which is a file path, a URL or a KMS URI supported by cosign.
+	PublicKeys []string `yaml:"publicKeys,omitempty" json:"publicKeys,omitempty"`
+
+	// KeylessIdentities are the identities of the keyless signatures issued by Sigstore.
+	KeylessIdentities []*KeylessIdentity `yaml:"keylessIdentities,omitempty" json:"keylessIdentities,omitempty"`
+}
+
+// KeylessIdentity is the identity in the certificate of a keyless signature.
+type KeylessIdentity struct {
+	// Issuer is the OIDC issuer of the identity, e.g. https://token.actions.githubusercontent.com.
+	Issuer string `yaml:"issuer" json:"issuer"`
+
+	// Subject is the certificate identity, e.g. the email or the workflow URL of the signer.
+	Subject string `yaml:"subject" json:"subject"`
 }
 
 type Accessory map[string]interface{}
@@ -228,34 +273,79 @@ type JSONPatcher struct {
 	Payload []byte `json:"payload" yaml:"payload"`
 }
 
-const ConfigBackends = "backends"
+const (
+	ConfigBackends  = "backends"
+	ConfigTemplates = "templates"
+)
 
 // Config contains configurations for kusion cli, which stores in ${KUSION_HOME}/config.yaml.
 type Config struct {
 	// Backends contains the configurations for multiple backends.
 	Backends *BackendConfigs `yaml:"backends,omitempty" json:"backends,omitempty"`
+
+	// Templates contains the registries of the project templates, whose key is the registry name.
+	Templates map[string]*TemplateRegistryConfig `yaml:"templates,omitempty" json:"templates,omitempty"`
+}
+
+const (
+	TemplateRegistrySource  = "source"
+	TemplateRegistryVersion = "version"
+)
+
+// TemplateRegistryConfig is the config of a registry of the project templates, which is a git repository, an
+// OCI artifact or a local directory containing the templates.
+type TemplateRegistryConfig struct {
+	// Source is the location of the registry, such as https://github.com/org/templates.git, which can be
+	// followed by a subdirectory like https://github.com/org/templates.git//path, oci://ghcr.io/org/templates,
+	// or a local directory.
+	Source string `yaml:"source,omitempty" json:"source,omitempty"`
+
+	// Version is the git tag or branch, or the OCI tag of the registry. The default branch of the git
+	// repository or the latest OCI tag is used if empty.
+	Version string `yaml:"version,omitempty" json:"version,omitempty"`
 }
 
 const (
 	DefaultBackendName = "default"
 
-	BackendCurrent            = "current"
-	BackendType               = "type"
-	BackendConfigItems        = "configs"
-	BackendLocalPath          = "path"
-	BackendGenericOssEndpoint = "endpoint"
-	BackendGenericOssAK       = "accessKeyID"
-	BackendGenericOssSK       = "accessKeySecret"
-	BackendGenericOssBucket   = "bucket"
-	BackendGenericOssPrefix   = "prefix"
-	BackendS3Region           = "region"
-	BackendS3ForcePathStyle   = "forcePathStyle"
-	BackendGoogleCredentials  = "credentials"
-
-	BackendTypeLocal  = "local"
-	BackendTypeOss    = "oss"
-	BackendTypeS3     = "s3"
-	BackendTypeGoogle = "google"
+	BackendCurrent              = "current"
+	BackendType                 = "type"
+	BackendConfigItems          = "configs"
+	BackendLocalPath            = "path"
+	BackendGenericOssEndpoint   = "endpoint"
+	BackendGenericOssAK         = "accessKeyID"
+	BackendGenericOssSK         = "accessKeySecret"
+	BackendGenericOssBucket     = "bucket"
+	BackendGenericOssPrefix     = "prefix"
+	BackendS3Region             = "region"
+	BackendS3ForcePathStyle     = "forcePathStyle"
+	BackendGoogleCredentials    = "credentials"
+	BackendAzureAccountName     = "accountName"
+	BackendAzureAccountKey      = "accountKey"
+	BackendAzureContainer       = "container"
+	BackendHTTPAddress          = "address"
+	BackendHTTPUsername         = "username"
+	BackendHTTPPassword         = "password"
+	BackendKubernetesKubeconfig = "kubeconfig"
+	BackendKubernetesContext    = "context"
+	BackendKubernetesNamespace  = "namespace"
+
+	BackendEncryptionKeyProvider        = "encryptionKeyProvider"
+	BackendEncryptionKeyProviderOptions = "encryptionKeyProviderOptions"
+
+	BackendReleaseRetentionKeepLast   = "releaseRetentionKeepLast"
+	BackendReleaseRetentionKeepWithin = "releaseRetentionKeepWithin"
+
+	BackendTypeLocal      = "local"
+	BackendTypeOss        = "oss"
+	BackendTypeS3         = "s3"
+	BackendTypeGoogle     = "google"
+	BackendTypeAzure      = "azure"
+	BackendTypeHTTP       = "http"
+	BackendTypeKubernetes = "kubernetes"
+
+	// DefaultBackendKubernetesNamespace is the namespace to store the files if not specified.
+	DefaultBackendKubernetesNamespace = "kusion"
 
 	EnvOssAccessKeyID             = "OSS_ACCESS_KEY_ID"
 	EnvOssAccessKeySecret         = "OSS_ACCESS_KEY_SECRET"
@@ -271,6 +361,16 @@ const (
 	EnvViettelCloudProjectID      = "VIETTEL_CLOUD_PROJECT_ID"
 	EnvGoogleCloudCredentials     = "GOOGLE_CLOUD_CREDENTIALS"
 	EnvGoogleCloudCredentialsPath = "GOOGLE_CLOUD_CREDENTIALS_PATH"
+	EnvAzureStorageAccount        = "AZURE_STORAGE_ACCOUNT"
+	EnvAzureStorageKey            = "AZURE_STORAGE_KEY"
+
+	// ProviderEnvs is the key of the context holding the environment variables
+	// passed to the Terraform providers, such as their credentials.
+	ProviderEnvs = "providerEnvs"
+
+	// ReleaseRetentionKey is the key of the workspace context holding the ReleaseRetention of the
+	// Releases in the workspace, which overrides the one of the backend.
+	ReleaseRetentionKey = "releaseRetention"
 
 	FieldImportedResources = "importedResources"
 	FieldHealthPolicy      = "healthPolicy"
@@ -291,7 +391,8 @@ type BackendConfigs struct {
 
 // BackendConfig contains the type and configs of a backend, which is used to store Spec, State and Workspace.
 type BackendConfig struct {
-	// Type is the backend type, supports BackendTypeLocal, BackendTypeOss, BackendTypeS3.
+	// Type is the backend type, supports BackendTypeLocal, BackendTypeOss, BackendTypeS3, BackendTypeGoogle,
+	// BackendTypeAzure, BackendTypeHTTP and BackendTypeKubernetes.
 	Type string `yaml:"type,omitempty" json:"type,omitempty"`
 
 	// Configs contains config items of the backend, whose keys differ from different backend types.
@@ -303,6 +404,9 @@ type BackendConfig struct {
 type BackendLocalConfig struct {
 	// Path of the directory to store the files.
 	Path string `yaml:"path,omitempty" json:"path,omitempty"`
+
+	// Encryption is the config of the client-side encryption of the files, nil means not encrypted.
+	Encryption *BackendEncryptionConfig `yaml:"encryption,omitempty" json:"encryption,omitempty"`
 }
 
 // BackendOssConfig contains the config of using OSS as backend, which can be converted from BackendConfig
@@ -332,6 +436,61 @@ type BackendGoogleConfig struct {
 	Region string `yaml:"region,omitempty" json:"region,omitempty"`
 }
 
+// BackendAzureConfig contains the config of using Azure Blob Storage as backend, which can be converted from
+// BackendConfig if Type is BackendTypeAzure.
+type BackendAzureConfig struct {
+	// AccountName of the storage account.
+	AccountName string `yaml:"accountName,omitempty" json:"accountName,omitempty"`
+
+	// AccountKey of the storage account.
+	AccountKey string `yaml:"accountKey,omitempty" json:"accountKey,omitempty"`
+
+	// Container to store the files.
+	Container string `yaml:"container" json:"container"`
+
+	// Prefix of the blob names to store the files.
+	Prefix string `yaml:"prefix,omitempty" json:"prefix,omitempty"`
+
+	// Endpoint is the base URL of the storage service, such as "core.chinacloudapi.cn", the public one is used
+	// if empty.
+	Endpoint string `yaml:"endpoint,omitempty" json:"endpoint,omitempty"`
+
+	// Encryption is the config of the client-side encryption of the files, nil means not encrypted.
+	Encryption *BackendEncryptionConfig `yaml:"encryption,omitempty" json:"encryption,omitempty"`
+}
+
+// BackendHTTPConfig contains the config of using a generic HTTP server as backend, which can be converted
+// from BackendConfig if Type is BackendTypeHTTP.
+type BackendHTTPConfig struct {
+	// Address of the HTTP server, under which the files are stored.
+	Address string `yaml:"address" json:"address"`
+
+	// Username of the basic auth, the basic auth is not used if empty.
+	Username string `yaml:"username,omitempty" json:"username,omitempty"`
+
+	// Password of the basic auth.
+	Password string `yaml:"password,omitempty" json:"password,omitempty"`
+
+	// Encryption is the config of the client-side encryption of the files, nil means not encrypted.
+	Encryption *BackendEncryptionConfig `yaml:"encryption,omitempty" json:"encryption,omitempty"`
+}
+
+// BackendKubernetesConfig contains the config of using a Kubernetes cluster as backend, which stores the
+// files as Secrets, and can be converted from BackendConfig if Type is BackendTypeKubernetes.
+type BackendKubernetesConfig struct {
+	// Kubeconfig is the path of the kubeconfig file, the default loading rules are used if empty.
+	Kubeconfig string `yaml:"kubeconfig,omitempty" json:"kubeconfig,omitempty"`
+
+	// Context of the kubeconfig to use, the current context is used if empty.
+	Context string `yaml:"context,omitempty" json:"context,omitempty"`
+
+	// Namespace to store the Secrets.
+	Namespace string `yaml:"namespace,omitempty" json:"namespace,omitempty"`
+
+	// Encryption is the config of the client-side encryption of the files, nil means not encrypted.
+	Encryption *BackendEncryptionConfig `yaml:"encryption,omitempty" json:"encryption,omitempty"`
+}
+
 // GenericBackendObjectStorageConfig contains generic configs which can be reused by BackendOssConfig and
 // BackendS3Config.
 type GenericBackendObjectStorageConfig struct {
@@ -352,6 +511,35 @@ type GenericBackendObjectStorageConfig struct {
 
 	// ForcePathStyle indicates whether to use path-style access for all operations.
 	ForcePathStyle bool `yaml:"forcePathStyle,omitempty" json:"forcePathStyle,omitempty"`
+
+	// Encryption is the config of the client-side encryption of the files, nil means not encrypted.
+	Encryption *BackendEncryptionConfig `yaml:"encryption,omitempty" json:"encryption,omitempty"`
+}
+
+// BackendEncryptionConfig contains the config of the client-side envelope encryption of the release and
+// workspace files, which is available for all the backend types.
+type BackendEncryptionConfig struct {
+	// KeyProvider is the name of the key provider wrapping the data keys, such as "local" and "env".
+	KeyProvider string `yaml:"keyProvider" json:"keyProvider"`
+
+	// KeyProviderOptions are the options of the key provider, such as the path of the key file.
+	KeyProviderOptions map[string]string `yaml:"keyProviderOptions,omitempty" json:"keyProviderOptions,omitempty"`
+
+	// DecryptionKeyProviders are the options of the other key providers keyed by their names, which only
+	// decrypt the files encrypted by them, such as after switching from the local key provider to a KMS.
+	DecryptionKeyProviders map[string]map[string]string `yaml:"decryptionKeyProviders,omitempty" json:"decryptionKeyProviders,omitempty"`
+}
+
+// ReleaseRetention is the retention policy of the Releases of a Project in a Workspace, under which the
+// Releases neither of the last KeepLast nor created within KeepWithin get pruned. The latest Release, and
+// the latest Release of each Stack are always kept.
+type ReleaseRetention struct {
+	// KeepLast is the number of the latest Releases to keep, 0 means not limited by the number.
+	KeepLast int `yaml:"keepLast,omitempty" json:"keepLast,omitempty"`
+
+	// KeepWithin is the duration within which the Releases created are kept, such as "720h", empty
+	// means not limited by the creation time.
+	KeepWithin string `yaml:"keepWithin,omitempty" json:"keepWithin,omitempty"`
 }
 
 // ToLocalBackend converts BackendConfig to structured BackendLocalConfig, works only when the Type
@@ -362,7 +550,48 @@ func (b *BackendConfig) ToLocalBackend() *BackendLocalConfig {
 	}
 	path, _ := b.Configs[BackendLocalPath].(string)
 	return &BackendLocalConfig{
-		Path: path,
+		Path:       path,
+		Encryption: b.ToEncryptionConfig(),
+	}
+}
+
+// ToEncryptionConfig converts the encryption config items of BackendConfig to structured BackendEncryptionConfig,
+// returns nil if no key provider is configured, which means the files are not encrypted.
+func (b *BackendConfig) ToEncryptionConfig() *BackendEncryptionConfig {
+	keyProvider, _ := b.Configs[BackendEncryptionKeyProvider].(string)
+	if keyProvider == "" {
+		return nil
+	}
+	var options map[string]string
+	if items, ok := b.Configs[BackendEncryptionKeyProviderOptions].(map[string]any); ok {
+		options = make(map[string]string, len(items))
+		for k, v := range items {
+			options[k] = fmt.Sprint(v)
+		}
+	}
+	return &BackendEncryptionConfig{
+		KeyProvider:        keyProvider,
+		KeyProviderOptions: options,
+	}
+}
+
+// ToReleaseRetention converts the release retention config items of BackendConfig to structured
+// ReleaseRetention, returns nil if neither is configured, which means all the Releases are kept.
+func (b *BackendConfig) ToReleaseRetention() *ReleaseRetention {
+	keepWithin, _ := b.Configs[BackendReleaseRetentionKeepWithin].(string)
+	var keepLast int
+	switch v := b.Configs[BackendReleaseRetentionKeepLast].(type) {
+	case int:
+		keepLast = v
+	case float64:
+		keepLast = int(v)
+	}
+	if keepLast <= 0 && keepWithin == "" {
+		return nil
+	}
+	return &ReleaseRetention{
+		KeepLast:   keepLast,
+		KeepWithin: keepWithin,
 	}
 }
 
@@ -384,6 +613,7 @@ func (b *BackendConfig) ToOssBackend() *BackendOssConfig {
 			AccessKeySecret: accessKeySecret,
 			Bucket:          bucket,
 			Prefix:          prefix,
+			Encryption:      b.ToEncryptionConfig(),
 		},
 	}
 }
@@ -409,6 +639,7 @@ func (b *BackendConfig) ToS3Backend() *BackendS3Config {
 			Bucket:          bucket,
 			Prefix:          prefix,
 			ForcePathStyle:  forcePathStyle,
+			Encryption:      b.ToEncryptionConfig(),
 		},
 		Region: region,
 	}
@@ -436,13 +667,69 @@ func (b *BackendConfig) ToGoogleBackend() *BackendGoogleConfig {
 	}
 	return &BackendGoogleConfig{
 		GenericBackendObjectStorageConfig: &GenericBackendObjectStorageConfig{
-			Bucket: bucket,
-			Prefix: prefix,
+			Bucket:     bucket,
+			Prefix:     prefix,
+			Encryption: b.ToEncryptionConfig(),
 		},
 		Credentials: creds,
 	}
 }
 
+// ToAzureBackend converts BackendConfig to structured BackendAzureConfig, works only when the Type is
+// BackendTypeAzure, and the Configs are with correct type, or return nil.
+func (b *BackendConfig) ToAzureBackend() *BackendAzureConfig {
+	if b.Type != BackendTypeAzure {
+		return nil
+	}
+	accountName, _ := b.Configs[BackendAzureAccountName].(string)
+	accountKey, _ := b.Configs[BackendAzureAccountKey].(string)
+	container, _ := b.Configs[BackendAzureContainer].(string)
+	prefix, _ := b.Configs[BackendGenericOssPrefix].(string)
+	endpoint, _ := b.Configs[BackendGenericOssEndpoint].(string)
+	return &BackendAzureConfig{
+		AccountName: accountName,
+		AccountKey:  accountKey,
+		Container:   container,
+		Prefix:      prefix,
+		Endpoint:    endpoint,
+		Encryption:  b.ToEncryptionConfig(),
+	}
+}
+
+// ToHTTPBackend converts BackendConfig to structured BackendHTTPConfig, works only when the Type is
+// BackendTypeHTTP, and the Configs are with correct type, or return nil.
+func (b *BackendConfig) ToHTTPBackend() *BackendHTTPConfig {
+	if b.Type != BackendTypeHTTP {
+		return nil
+	}
+	address, _ := b.Configs[BackendHTTPAddress].(string)
+	username, _ := b.Configs[BackendHTTPUsername].(string)
+	password, _ := b.Configs[BackendHTTPPassword].(string)
+	return &BackendHTTPConfig{
+		Address:    address,
+		Username:   username,
+		Password:   password,
+		Encryption: b.ToEncryptionConfig(),
+	}
+}
+
+// ToKubernetesBackend converts BackendConfig to structured BackendKubernetesConfig, works only when the Type
+// is BackendTypeKubernetes, and the Configs are with correct type, or return nil.
+func (b *BackendConfig) ToKubernetesBackend() *BackendKubernetesConfig {
+	if b.Type != BackendTypeKubernetes {
+		return nil
+	}
+	kubeconfig, _ := b.Configs[BackendKubernetesKubeconfig].(string)
+	kubeContext, _ := b.Configs[BackendKubernetesContext].(string)
+	namespace, _ := b.Configs[BackendKubernetesNamespace].(string)
+	return &BackendKubernetesConfig{
+		Kubeconfig: kubeconfig,
+		Context:    kubeContext,
+		Namespace:  namespace,
+		Encryption: b.ToEncryptionConfig(),
+	}
+}
+
 // ModuleConfigs is a set of multiple ModuleConfig, whose key is the module name.
 type ModuleConfigs map[string]*ModuleConfig
 
@@ -720,6 +1007,9 @@ type Spec struct {
 	SecretStore *SecretStore `yaml:"secretStore" json:"secretStore"`
 	// Context contains workspace-level configurations, such as runtimes, topologies, and metadata, etc.
 	Context GenericConfig `yaml:"context" json:"context"`
+	// SecretEnvs are the environment variables of the Terraform providers holding secrets, which are
+	// only passed to the runtimes and never persisted or printed with the Spec.
+	SecretEnvs map[string]string `yaml:"-" json:"-"`
 }
 
 // State is a record of an operation's result. It is a mapping between resources in KCL and the actual
@@ -783,6 +1073,118 @@ type Release struct {
 
 	// ModifiedTime is the time that the Release is modified.
 	ModifiedTime time.Time `yaml:"modifiedTime" json:"modifiedTime"`
+
+	// RollbackOf is the Revision of the failed Release which this Release rolls back, empty if the
+	// Release is not a rollback.
+	RollbackOf uint64 `yaml:"rollbackOf,omitempty" json:"rollbackOf,omitempty"`
+
+	// Provenance records who and what produced the Release, empty for the Releases created before it
+	// is introduced.
+	Provenance *ReleaseProvenance `yaml:"provenance,omitempty" json:"provenance,omitempty"`
+
+	// Actions are the numbers of the Resources of each action applied by the Release, empty if the
+	// Release doesn't reach the applying or destroying phase.
+	Actions *ReleaseActions `yaml:"actions,omitempty" json:"actions,omitempty"`
+}
+
+// ReleaseActions are the numbers of the Resources of each action applied by a Release.
+type ReleaseActions struct {
+	Create    int `yaml:"create" json:"create"`
+	Update    int `yaml:"update" json:"update"`
+	Delete    int `yaml:"delete" json:"delete"`
+	UnChanged int `yaml:"unchanged" json:"unchanged"`
+}
+
+// ReleaseSummary is the synopsis of a Release, which is kept in the metadata of the Releases so that the
+// Releases can be listed without reading each of them.
+type ReleaseSummary struct {
+	// Revision of the Release.
+	Revision uint64 `yaml:"revision" json:"revision"`
+
+	// Stack name of the Release.
+	Stack string `yaml:"stack" json:"stack"`
+
+	// Phase is the current phase of the Release.
+	Phase ReleasePhase `yaml:"phase,omitempty" json:"phase,omitempty"`
+
+	// CreateTime is the time that the Release is created.
+	CreateTime time.Time `yaml:"createTime,omitempty" json:"createTime,omitempty"`
+
+	// ModifiedTime is the time that the Release is modified.
+	ModifiedTime time.Time `yaml:"modifiedTime,omitempty" json:"modifiedTime,omitempty"`
+
+	// Operator is the identity of the user who triggered the Release.
+	Operator string `yaml:"operator,omitempty" json:"operator,omitempty"`
+
+	// Commit is the git commit SHA of the stack source.
+	Commit string `yaml:"commit,omitempty" json:"commit,omitempty"`
+
+	// Dirty indicates whether there were uncommitted changes in the stack source.
+	Dirty bool `yaml:"dirty,omitempty" json:"dirty,omitempty"`
+
+	// Actions are the numbers of the Resources of each action applied by the Release.
+	Actions *ReleaseActions `yaml:"actions,omitempty" json:"actions,omitempty"`
+}
+
+// Summary returns the ReleaseSummary of the Release.
+func (r *Release) Summary() *ReleaseSummary {
+	s := &ReleaseSummary{
+		Revision:     r.Revision,
+		Stack:        r.Stack,
+		Phase:        r.Phase,
+		CreateTime:   r.CreateTime,
+		ModifiedTime: r.ModifiedTime,
+		Actions:      r.Actions,
+	}
+	if r.Provenance != nil {
+		s.Operator = r.Provenance.Operator
+		if r.Provenance.Git != nil {
+			s.Commit = r.Provenance.Git.Commit
+			s.Dirty = r.Provenance.Git.Dirty
+		}
+	}
+	return s
+}
+
+// ReleaseProvenance is the provenance metadata of a Release for auditing.
+type ReleaseProvenance struct {
+	// Operator is the identity of the user who triggered the Release.
+	Operator string `yaml:"operator,omitempty" json:"operator,omitempty"`
+
+	// Git is the git information of the stack source, empty if the stack is not in a git repository.
+	Git *ReleaseGitInfo `yaml:"git,omitempty" json:"git,omitempty"`
+
+	// KusionVersion is the version of Kusion which produced the Release.
+	KusionVersion string `yaml:"kusionVersion,omitempty" json:"kusionVersion,omitempty"`
+
+	// Modules are the versions of the Kusion modules resolved from the kcl.mod of the stack, keyed by
+	// the module names.
+	Modules map[string]string `yaml:"modules,omitempty" json:"modules,omitempty"`
+
+	// Providers are the versions of the terraform providers of the resources, keyed by the provider
+	// sources.
+	Providers map[string]string `yaml:"providers,omitempty" json:"providers,omitempty"`
+
+	// RunID is the ID of the Run which triggered the Release, empty if not triggered by a Run.
+	RunID uint `yaml:"runID,omitempty" json:"runID,omitempty"`
+
+	// Notes are the free-form change notes of the Release.
+	Notes string `yaml:"notes,omitempty" json:"notes,omitempty"`
+}
+
+// ReleaseGitInfo is the git information of the stack source of a Release.
+type ReleaseGitInfo struct {
+	// RemoteURL is the URL of the origin remote.
+	RemoteURL string `yaml:"remoteURL,omitempty" json:"remoteURL,omitempty"`
+
+	// Commit is the commit SHA of the HEAD.
+	Commit string `yaml:"commit,omitempty" json:"commit,omitempty"`
+
+	// Branch is the current branch, empty if the HEAD is detached.
+	Branch string `yaml:"branch,omitempty" json:"branch,omitempty"`
+
+	// Dirty indicates whether there are uncommitted changes in the working tree.
+	Dirty bool `yaml:"dirty,omitempty" json:"dirty,omitempty"`
 }
 
 const (
@@ -55,7 +55,10 @@ func NewBackend(name string) (Backend, error) {
 		if err = storages.CompleteLocalConfig(bkConfig); err != nil {
 			return nil, fmt.Errorf("complete local config failed, %w", err)
 		}
-		return storages.NewLocalStorage(bkConfig), nil
+		storage, err = storages.NewLocalStorage(bkConfig)
+		if err != nil {
+			return nil, fmt.Errorf("new local storage of backend %s failed, %w", name, err)
+		}
 	case v1.BackendTypeOss:
 		bkConfig := bkCfg.ToOssBackend()
 		storages.CompleteOssConfig(bkConfig)
@@ -83,6 +86,32 @@ func NewBackend(name string) (Backend, error) {
 		if err != nil {
 			return nil, fmt.Errorf("new google storage of backend %s failed, %w", name, err)
 		}
+	case v1.BackendTypeAzure:
+		bkConfig := bkCfg.ToAzureBackend()
+		storages.CompleteAzureConfig(bkConfig)
+		if err = storages.ValidateAzureConfig(bkConfig); err != nil {
+			return nil, fmt.Errorf("invalid config of backend %s, %w", name, err)
+		}
+		storage, err = storages.NewAzureStorage(bkConfig)
+		if err != nil {
+			return nil, fmt.Errorf("new azure storage of backend %s failed, %w", name, err)
+		}
+	case v1.BackendTypeHTTP:
+		bkConfig := bkCfg.ToHTTPBackend()
+		if err = storages.ValidateHTTPConfig(bkConfig); err != nil {
+			return nil, fmt.Errorf("invalid config of backend %s, %w", name, err)
+		}
+		storage, err = storages.NewHTTPStorage(bkConfig)
+		if err != nil {
+			return nil, fmt.Errorf("new http storage of backend %s failed, %w", name, err)
+		}
+	case v1.BackendTypeKubernetes:
+		bkConfig := bkCfg.ToKubernetesBackend()
+		storages.CompleteKubernetesConfig(bkConfig)
+		storage, err = storages.NewKubernetesStorage(bkConfig)
+		if err != nil {
+			return nil, fmt.Errorf("new kubernetes storage of backend %s failed, %w", name, err)
+		}
 	default:
 		return nil, fmt.Errorf("invalid type %s of backend %s", bkCfg.Type, name)
 	}
@@ -0,0 +1,32 @@
+package storages
+
+import (
+	"github.com/Azure/azure-sdk-for-go/storage"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/infra/objectstore"
+)
+
+// AzureStorage is an implementation of backend.Backend which uses Azure Blob Storage as storage.
+type AzureStorage struct {
+	*ObjectStoreStorage
+}
+
+func NewAzureStorage(config *v1.BackendAzureConfig) (*AzureStorage, error) {
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = storage.DefaultBaseURL
+	}
+	client, err := storage.NewClient(config.AccountName, config.AccountKey, endpoint, storage.DefaultAPIVersion, true)
+	if err != nil {
+		return nil, err
+	}
+	blobService := client.GetBlobService()
+	container := blobService.GetContainerReference(config.Container)
+	s, err := NewObjectStoreStorage(objectstore.NewAzureStore(container), config.Prefix, config.Encryption)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AzureStorage{ObjectStoreStorage: s}, nil
+}
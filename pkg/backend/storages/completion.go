@@ -51,3 +51,23 @@ func CompleteS3Config(config *v1.BackendS3Config) {
 		config.Region = region
 	}
 }
+
+// CompleteAzureConfig fulfills the whole azure config from environment variables if set.
+func CompleteAzureConfig(config *v1.BackendAzureConfig) {
+	accountName := os.Getenv(v1.EnvAzureStorageAccount)
+	accountKey := os.Getenv(v1.EnvAzureStorageKey)
+
+	if accountName != "" {
+		config.AccountName = accountName
+	}
+	if accountKey != "" {
+		config.AccountKey = accountKey
+	}
+}
+
+// CompleteKubernetesConfig sets default value of namespace if not set.
+func CompleteKubernetesConfig(config *v1.BackendKubernetesConfig) {
+	if config.Namespace == "" {
+		config.Namespace = v1.DefaultBackendKubernetesNamespace
+	}
+}
@@ -0,0 +1,29 @@
+package storages
+
+import (
+	"fmt"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/infra/encryption"
+)
+
+// NewEncrypter creates the encrypter of the release and workspace files with the encryption config of the
+// backend, returns nil if the encryption is not enabled.
+func NewEncrypter(config *v1.BackendEncryptionConfig) (*encryption.Encrypter, error) {
+	if config == nil || config.KeyProvider == "" {
+		return nil, nil
+	}
+	provider, err := encryption.NewKeyProvider(config.KeyProvider, config.KeyProviderOptions)
+	if err != nil {
+		return nil, fmt.Errorf("new key provider of the backend encryption failed: %w", err)
+	}
+	var others []encryption.KeyProvider
+	for name, options := range config.DecryptionKeyProviders {
+		other, err := encryption.NewKeyProvider(name, options)
+		if err != nil {
+			return nil, fmt.Errorf("new decryption key provider %s of the backend encryption failed: %w", name, err)
+		}
+		others = append(others, other)
+	}
+	return encryption.NewEncrypter(provider, others...), nil
+}
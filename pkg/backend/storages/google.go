@@ -11,6 +11,7 @@ import (
 	releasestorages "kusionstack.io/kusion/pkg/engine/release/storages"
 	"kusionstack.io/kusion/pkg/engine/resource/graph"
 	graphstorages "kusionstack.io/kusion/pkg/engine/resource/graph/storages"
+	"kusionstack.io/kusion/pkg/infra/encryption"
 	projectstorages "kusionstack.io/kusion/pkg/project/storages"
 	"kusionstack.io/kusion/pkg/workspace"
 	workspacestorages "kusionstack.io/kusion/pkg/workspace/storages"
@@ -22,6 +23,9 @@ type GoogleStorage struct {
 
 	// prefix will be added to the object storage key, so that all the files are stored under the prefix.
 	prefix string
+
+	// encrypter encrypts the release and workspace files, nil means the encryption is not enabled.
+	encrypter *encryption.Encrypter
 }
 
 func NewGoogleStorage(config *v1.BackendGoogleConfig) (*GoogleStorage, error) {
@@ -30,23 +34,43 @@ func NewGoogleStorage(config *v1.BackendGoogleConfig) (*GoogleStorage, error) {
 		return nil, err
 	}
 	bucket := client.Bucket(config.Bucket)
+	encrypter, err := NewEncrypter(config.Encryption)
+	if err != nil {
+		return nil, err
+	}
 
 	return &GoogleStorage{
-		bucket: bucket,
-		prefix: config.Prefix,
+		bucket:    bucket,
+		prefix:    config.Prefix,
+		encrypter: encrypter,
 	}, nil
 }
 
 func (s *GoogleStorage) WorkspaceStorage() (workspace.Storage, error) {
-	return workspacestorages.NewGoogleStorage(s.bucket, workspacestorages.GenGenericOssWorkspacePrefixKey(s.prefix))
+	storage, err := workspacestorages.NewGoogleStorage(s.bucket, workspacestorages.GenGenericOssWorkspacePrefixKey(s.prefix))
+	if err != nil {
+		return nil, err
+	}
+	storage.SetEncrypter(s.encrypter)
+	return storage, nil
 }
 
 func (s *GoogleStorage) ReleaseStorage(project, workspace string) (release.Storage, error) {
-	return releasestorages.NewGoogleStorage(s.bucket, releasestorages.GenGenericOssReleasePrefixKey(s.prefix, project, workspace))
+	storage, err := releasestorages.NewGoogleStorage(s.bucket, releasestorages.GenGenericOssReleasePrefixKey(s.prefix, project, workspace))
+	if err != nil {
+		return nil, err
+	}
+	storage.SetEncrypter(s.encrypter)
+	return storage, nil
 }
 
 func (s *GoogleStorage) StateStorageWithPath(path string) (release.Storage, error) {
-	return releasestorages.NewGoogleStorage(s.bucket, releasestorages.GenReleasePrefixKeyWithPath(s.prefix, path))
+	storage, err := releasestorages.NewGoogleStorage(s.bucket, releasestorages.GenReleasePrefixKeyWithPath(s.prefix, path))
+	if err != nil {
+		return nil, err
+	}
+	storage.SetEncrypter(s.encrypter)
+	return storage, nil
 }
 
 func (s *GoogleStorage) GraphStorage(project, workspace string) (graph.Storage, error) {
@@ -0,0 +1,24 @@
+package storages
+
+import (
+	"net/http"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/infra/objectstore"
+)
+
+// HTTPStorage is an implementation of backend.Backend which uses a generic HTTP server as storage, see
+// objectstore.HTTPStore for the semantics the server needs to support.
+type HTTPStorage struct {
+	*ObjectStoreStorage
+}
+
+func NewHTTPStorage(config *v1.BackendHTTPConfig) (*HTTPStorage, error) {
+	store := objectstore.NewHTTPStore(http.DefaultClient, config.Address, config.Username, config.Password)
+	s, err := NewObjectStoreStorage(store, "", config.Encryption)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HTTPStorage{ObjectStoreStorage: s}, nil
+}
@@ -0,0 +1,40 @@
+package storages
+
+import (
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/infra/objectstore"
+)
+
+// KubernetesStorage is an implementation of backend.Backend which uses a Kubernetes cluster as storage, where
+// the files are stored as Secrets in the namespace.
+type KubernetesStorage struct {
+	*ObjectStoreStorage
+}
+
+func NewKubernetesStorage(config *v1.BackendKubernetesConfig) (*KubernetesStorage, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = config.Kubeconfig
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: config.Context}
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return newKubernetesStorage(client, config)
+}
+
+func newKubernetesStorage(client kubernetes.Interface, config *v1.BackendKubernetesConfig) (*KubernetesStorage, error) {
+	s, err := NewObjectStoreStorage(objectstore.NewKubernetesStore(client, config.Namespace), "", config.Encryption)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KubernetesStorage{ObjectStoreStorage: s}, nil
+}
@@ -0,0 +1,48 @@
+package storages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func TestKubernetesStorage(t *testing.T) {
+	s, err := newKubernetesStorage(fake.NewSimpleClientset(), &v1.BackendKubernetesConfig{Namespace: "kusion"})
+	require.NoError(t, err)
+
+	wsStorage, err := s.WorkspaceStorage()
+	require.NoError(t, err)
+	require.NoError(t, wsStorage.Create(&v1.Workspace{Name: "dev"}))
+	names, err := wsStorage.GetNames()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default", "dev"}, names)
+
+	releaseStorage, err := s.ReleaseStorage("foo", "dev")
+	require.NoError(t, err)
+	require.NoError(t, releaseStorage.Create(&v1.Release{Project: "foo", Workspace: "dev", Revision: 1, Spec: &v1.Spec{}, State: &v1.State{}}))
+	releaseStorage, err = s.ReleaseStorage("foo", "dev")
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{1}, releaseStorage.GetRevisions())
+
+	graphStorage, err := s.GraphStorage("foo", "dev")
+	require.NoError(t, err)
+	assert.False(t, graphStorage.CheckGraphStorageExistence())
+	require.NoError(t, graphStorage.Create(&v1.Graph{Project: "foo", Workspace: "dev", Resources: &v1.GraphResources{}}))
+	g, err := graphStorage.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "foo", g.Project)
+
+	projects, err := s.ProjectStorage()
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"dev": {"foo"}}, projects)
+}
+
+func TestCompleteKubernetesConfig(t *testing.T) {
+	config := &v1.BackendKubernetesConfig{}
+	CompleteKubernetesConfig(config)
+	assert.Equal(t, v1.DefaultBackendKubernetesNamespace, config.Namespace)
+}
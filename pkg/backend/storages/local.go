@@ -6,6 +6,7 @@ import (
 	releasestorages "kusionstack.io/kusion/pkg/engine/release/storages"
 	"kusionstack.io/kusion/pkg/engine/resource/graph"
 	graphstorages "kusionstack.io/kusion/pkg/engine/resource/graph/storages"
+	"kusionstack.io/kusion/pkg/infra/encryption"
 	projectstorages "kusionstack.io/kusion/pkg/project/storages"
 	"kusionstack.io/kusion/pkg/workspace"
 	workspacestorages "kusionstack.io/kusion/pkg/workspace/storages"
@@ -16,22 +17,44 @@ type LocalStorage struct {
 	// path is the directory to store the files. If empty, use the default storage path, which depends on
 	// the object it's used to store.
 	path string
+
+	// encrypter encrypts the release and workspace files, nil means the encryption is not enabled.
+	encrypter *encryption.Encrypter
 }
 
-func NewLocalStorage(config *v1.BackendLocalConfig) *LocalStorage {
-	return &LocalStorage{path: config.Path}
+func NewLocalStorage(config *v1.BackendLocalConfig) (*LocalStorage, error) {
+	encrypter, err := NewEncrypter(config.Encryption)
+	if err != nil {
+		return nil, err
+	}
+	return &LocalStorage{path: config.Path, encrypter: encrypter}, nil
 }
 
 func (s *LocalStorage) WorkspaceStorage() (workspace.Storage, error) {
-	return workspacestorages.NewLocalStorage(workspacestorages.GenWorkspaceDirPath(s.path))
+	storage, err := workspacestorages.NewLocalStorage(workspacestorages.GenWorkspaceDirPath(s.path))
+	if err != nil {
+		return nil, err
+	}
+	storage.SetEncrypter(s.encrypter)
+	return storage, nil
 }
 
 func (s *LocalStorage) ReleaseStorage(project, workspace string) (release.Storage, error) {
-	return releasestorages.NewLocalStorage(releasestorages.GenReleaseDirPath(s.path, project, workspace))
+	storage, err := releasestorages.NewLocalStorage(releasestorages.GenReleaseDirPath(s.path, project, workspace))
+	if err != nil {
+		return nil, err
+	}
+	storage.SetEncrypter(s.encrypter)
+	return storage, nil
 }
 
 func (s *LocalStorage) StateStorageWithPath(path string) (release.Storage, error) {
-	return releasestorages.NewLocalStorage(releasestorages.GenReleasePrefixKeyWithPath(s.path, path))
+	storage, err := releasestorages.NewLocalStorage(releasestorages.GenReleasePrefixKeyWithPath(s.path, path))
+	if err != nil {
+		return nil, err
+	}
+	storage.SetEncrypter(s.encrypter)
+	return storage, nil
 }
 
 func (s *LocalStorage) GraphStorage(project, workspace string) (graph.Storage, error) {
@@ -28,7 +28,8 @@ func TestNewLocalStorage(t *testing.T) {
 
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
-			storage := NewLocalStorage(tc.config)
+			storage, err := NewLocalStorage(tc.config)
+			assert.NoError(t, err)
 			assert.Equal(t, tc.storage, storage)
 		})
 	}
@@ -0,0 +1,70 @@
+package storages
+
+import (
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/engine/release"
+	releasestorages "kusionstack.io/kusion/pkg/engine/release/storages"
+	"kusionstack.io/kusion/pkg/engine/resource/graph"
+	graphstorages "kusionstack.io/kusion/pkg/engine/resource/graph/storages"
+	"kusionstack.io/kusion/pkg/infra/encryption"
+	"kusionstack.io/kusion/pkg/infra/objectstore"
+	projectstorages "kusionstack.io/kusion/pkg/project/storages"
+	"kusionstack.io/kusion/pkg/workspace"
+	workspacestorages "kusionstack.io/kusion/pkg/workspace/storages"
+)
+
+// ObjectStoreStorage is an implementation of backend.Backend which uses a generic object store as storage,
+// which is shared by AzureStorage, HTTPStorage and KubernetesStorage.
+type ObjectStoreStorage struct {
+	store objectstore.Store
+
+	// prefix will be added to the object key, so that all the files are stored under the prefix.
+	prefix string
+
+	// encrypter encrypts the release and workspace files, nil means the encryption is not enabled.
+	encrypter *encryption.Encrypter
+}
+
+func NewObjectStoreStorage(store objectstore.Store, prefix string, encryptionConfig *v1.BackendEncryptionConfig) (*ObjectStoreStorage, error) {
+	encrypter, err := NewEncrypter(encryptionConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ObjectStoreStorage{store: store, prefix: prefix, encrypter: encrypter}, nil
+}
+
+func (s *ObjectStoreStorage) WorkspaceStorage() (workspace.Storage, error) {
+	storage, err := workspacestorages.NewObjectStoreStorage(s.store, workspacestorages.GenGenericOssWorkspacePrefixKey(s.prefix))
+	if err != nil {
+		return nil, err
+	}
+	storage.SetEncrypter(s.encrypter)
+	return storage, nil
+}
+
+func (s *ObjectStoreStorage) ReleaseStorage(project, workspace string) (release.Storage, error) {
+	storage, err := releasestorages.NewObjectStoreStorage(s.store, releasestorages.GenGenericOssReleasePrefixKey(s.prefix, project, workspace))
+	if err != nil {
+		return nil, err
+	}
+	storage.SetEncrypter(s.encrypter)
+	return storage, nil
+}
+
+func (s *ObjectStoreStorage) StateStorageWithPath(path string) (release.Storage, error) {
+	storage, err := releasestorages.NewObjectStoreStorage(s.store, releasestorages.GenReleasePrefixKeyWithPath(s.prefix, path))
+	if err != nil {
+		return nil, err
+	}
+	storage.SetEncrypter(s.encrypter)
+	return storage, nil
+}
+
+func (s *ObjectStoreStorage) GraphStorage(project, workspace string) (graph.Storage, error) {
+	return graphstorages.NewObjectStoreStorage(s.store, graphstorages.GenGenericOssResourcePrefixKey(s.prefix, project, workspace))
+}
+
+func (s *ObjectStoreStorage) ProjectStorage() (map[string][]string, error) {
+	return projectstorages.NewObjectStoreStorage(s.store, projectstorages.GenGenericOssReleasePrefixKey(s.prefix)).Get()
+}
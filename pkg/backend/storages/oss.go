@@ -8,6 +8,7 @@ import (
 	releasestorages "kusionstack.io/kusion/pkg/engine/release/storages"
 	"kusionstack.io/kusion/pkg/engine/resource/graph"
 	graphstorages "kusionstack.io/kusion/pkg/engine/resource/graph/storages"
+	"kusionstack.io/kusion/pkg/infra/encryption"
 	projectstorages "kusionstack.io/kusion/pkg/project/storages"
 	"kusionstack.io/kusion/pkg/workspace"
 	workspacestorages "kusionstack.io/kusion/pkg/workspace/storages"
@@ -19,6 +20,9 @@ type OssStorage struct {
 
 	// prefix will be added to the object storage key, so that all the files are stored under the prefix.
 	prefix string
+
+	// encrypter encrypts the release and workspace files, nil means the encryption is not enabled.
+	encrypter *encryption.Encrypter
 }
 
 func NewOssStorage(config *v1.BackendOssConfig) (*OssStorage, error) {
@@ -30,20 +34,39 @@ func NewOssStorage(config *v1.BackendOssConfig) (*OssStorage, error) {
 	if err != nil {
 		return nil, err
 	}
+	encrypter, err := NewEncrypter(config.Encryption)
+	if err != nil {
+		return nil, err
+	}
 
-	return &OssStorage{bucket: bucket, prefix: config.Prefix}, nil
+	return &OssStorage{bucket: bucket, prefix: config.Prefix, encrypter: encrypter}, nil
 }
 
 func (s *OssStorage) WorkspaceStorage() (workspace.Storage, error) {
-	return workspacestorages.NewOssStorage(s.bucket, workspacestorages.GenGenericOssWorkspacePrefixKey(s.prefix))
+	storage, err := workspacestorages.NewOssStorage(s.bucket, workspacestorages.GenGenericOssWorkspacePrefixKey(s.prefix))
+	if err != nil {
+		return nil, err
+	}
+	storage.SetEncrypter(s.encrypter)
+	return storage, nil
 }
 
 func (s *OssStorage) ReleaseStorage(project, workspace string) (release.Storage, error) {
-	return releasestorages.NewOssStorage(s.bucket, releasestorages.GenGenericOssReleasePrefixKey(s.prefix, project, workspace))
+	storage, err := releasestorages.NewOssStorage(s.bucket, releasestorages.GenGenericOssReleasePrefixKey(s.prefix, project, workspace))
+	if err != nil {
+		return nil, err
+	}
+	storage.SetEncrypter(s.encrypter)
+	return storage, nil
 }
 
 func (s *OssStorage) StateStorageWithPath(path string) (release.Storage, error) {
-	return releasestorages.NewOssStorage(s.bucket, releasestorages.GenReleasePrefixKeyWithPath(s.prefix, path))
+	storage, err := releasestorages.NewOssStorage(s.bucket, releasestorages.GenReleasePrefixKeyWithPath(s.prefix, path))
+	if err != nil {
+		return nil, err
+	}
+	storage.SetEncrypter(s.encrypter)
+	return storage, nil
 }
 
 func (s *OssStorage) GraphStorage(project, workspace string) (graph.Storage, error) {
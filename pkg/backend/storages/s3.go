@@ -11,6 +11,7 @@ import (
 	releasestorages "kusionstack.io/kusion/pkg/engine/release/storages"
 	"kusionstack.io/kusion/pkg/engine/resource/graph"
 	graphstorages "kusionstack.io/kusion/pkg/engine/resource/graph/storages"
+	"kusionstack.io/kusion/pkg/infra/encryption"
 	projectstorages "kusionstack.io/kusion/pkg/project/storages"
 	"kusionstack.io/kusion/pkg/workspace"
 	workspacestorages "kusionstack.io/kusion/pkg/workspace/storages"
@@ -23,6 +24,9 @@ type S3Storage struct {
 
 	// prefix will be added to the object storage key, so that all the files are stored under the prefix.
 	prefix string
+
+	// encrypter encrypts the release and workspace files, nil means the encryption is not enabled.
+	encrypter *encryption.Encrypter
 }
 
 func NewS3Storage(config *v1.BackendS3Config) (*S3Storage, error) {
@@ -39,24 +43,44 @@ func NewS3Storage(config *v1.BackendS3Config) (*S3Storage, error) {
 	if err != nil {
 		return nil, err
 	}
+	encrypter, err := NewEncrypter(config.Encryption)
+	if err != nil {
+		return nil, err
+	}
 
 	return &S3Storage{
-		s3:     s3.New(sess),
-		bucket: config.Bucket,
-		prefix: config.Prefix,
+		s3:        s3.New(sess),
+		bucket:    config.Bucket,
+		prefix:    config.Prefix,
+		encrypter: encrypter,
 	}, nil
 }
 
 func (s *S3Storage) WorkspaceStorage() (workspace.Storage, error) {
-	return workspacestorages.NewS3Storage(s.s3, s.bucket, workspacestorages.GenGenericOssWorkspacePrefixKey(s.prefix))
+	storage, err := workspacestorages.NewS3Storage(s.s3, s.bucket, workspacestorages.GenGenericOssWorkspacePrefixKey(s.prefix))
+	if err != nil {
+		return nil, err
+	}
+	storage.SetEncrypter(s.encrypter)
+	return storage, nil
 }
 
 func (s *S3Storage) ReleaseStorage(project, workspace string) (release.Storage, error) {
-	return releasestorages.NewS3Storage(s.s3, s.bucket, releasestorages.GenGenericOssReleasePrefixKey(s.prefix, project, workspace))
+	storage, err := releasestorages.NewS3Storage(s.s3, s.bucket, releasestorages.GenGenericOssReleasePrefixKey(s.prefix, project, workspace))
+	if err != nil {
+		return nil, err
+	}
+	storage.SetEncrypter(s.encrypter)
+	return storage, nil
 }
 
 func (s *S3Storage) StateStorageWithPath(path string) (release.Storage, error) {
-	return releasestorages.NewS3Storage(s.s3, s.bucket, releasestorages.GenReleasePrefixKeyWithPath(s.prefix, path))
+	storage, err := releasestorages.NewS3Storage(s.s3, s.bucket, releasestorages.GenReleasePrefixKeyWithPath(s.prefix, path))
+	if err != nil {
+		return nil, err
+	}
+	storage.SetEncrypter(s.encrypter)
+	return storage, nil
 }
 
 func (s *S3Storage) GraphStorage(project, workspace string) (graph.Storage, error) {
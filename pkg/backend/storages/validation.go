@@ -3,6 +3,7 @@ package storages
 import (
 	"errors"
 	"fmt"
+	"net/url"
 
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
 )
@@ -13,6 +14,11 @@ var (
 	ErrEmptyAccessKeySecret = errors.New("empty access key secret")
 	ErrEmptyOssEndpoint     = errors.New("empty oss endpoint")
 	ErrEmptyS3Region        = errors.New("empty s3 region")
+	ErrEmptyAzureAccount    = errors.New("empty azure storage account name")
+	ErrEmptyAzureAccountKey = errors.New("empty azure storage account key")
+	ErrEmptyAzureContainer  = errors.New("empty azure container")
+	ErrEmptyHTTPAddress     = errors.New("empty http address")
+	ErrInvalidHTTPAddress   = errors.New("http address must be an absolute http or https url")
 )
 
 // ValidateOssConfig is used to validate v1.BackendOssConfig is valid or not, where all the items are included.
@@ -63,6 +69,42 @@ func ValidateS3ConfigFromFile(config *v1.BackendS3Config) error {
 	return nil
 }
 
+// ValidateAzureConfig is used to validate v1.BackendAzureConfig is valid or not, where all the items are included.
+// If valid, the config contains all valid items to new an azure client.
+func ValidateAzureConfig(config *v1.BackendAzureConfig) error {
+	if err := ValidateAzureConfigFromFile(config); err != nil {
+		return err
+	}
+	if config.AccountName == "" {
+		return ErrEmptyAzureAccount
+	}
+	if config.AccountKey == "" {
+		return ErrEmptyAzureAccountKey
+	}
+	return nil
+}
+
+// ValidateAzureConfigFromFile is used to validate the v1.BackendAzureConfig parsed from config file is valid or not,
+// where the sensitive data items set as environment variables are not included.
+func ValidateAzureConfigFromFile(config *v1.BackendAzureConfig) error {
+	if config.Container == "" {
+		return ErrEmptyAzureContainer
+	}
+	return nil
+}
+
+// ValidateHTTPConfig is used to validate v1.BackendHTTPConfig is valid or not.
+func ValidateHTTPConfig(config *v1.BackendHTTPConfig) error {
+	if config.Address == "" {
+		return ErrEmptyHTTPAddress
+	}
+	u, err := url.Parse(config.Address)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidHTTPAddress
+	}
+	return nil
+}
+
 func validateGenericObjectStorageBucket(bucket string) error {
 	if bucket == "" {
 		return ErrEmptyBucket
@@ -162,3 +162,75 @@ func TestValidateS3ConfigFromFile(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateAzureConfig(t *testing.T) {
+	testcases := []struct {
+		name    string
+		success bool
+		config  *v1.BackendAzureConfig
+	}{
+		{
+			name:    "valid azure config",
+			success: true,
+			config: &v1.BackendAzureConfig{
+				AccountName: "kusion",
+				AccountKey:  "fake-key",
+				Container:   "state",
+			},
+		},
+		{
+			name:    "invalid azure config empty container",
+			success: false,
+			config: &v1.BackendAzureConfig{
+				AccountName: "kusion",
+				AccountKey:  "fake-key",
+			},
+		},
+		{
+			name:    "invalid azure config empty account key",
+			success: false,
+			config: &v1.BackendAzureConfig{
+				AccountName: "kusion",
+				Container:   "state",
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateAzureConfig(tc.config)
+			assert.Equal(t, tc.success, err == nil)
+		})
+	}
+}
+
+func TestValidateHTTPConfig(t *testing.T) {
+	testcases := []struct {
+		name    string
+		success bool
+		config  *v1.BackendHTTPConfig
+	}{
+		{
+			name:    "valid http config",
+			success: true,
+			config:  &v1.BackendHTTPConfig{Address: "https://state.example.com/kusion"},
+		},
+		{
+			name:    "invalid http config empty address",
+			success: false,
+			config:  &v1.BackendHTTPConfig{},
+		},
+		{
+			name:    "invalid http config unsupported scheme",
+			success: false,
+			config:  &v1.BackendHTTPConfig{Address: "ftp://state.example.com"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateHTTPConfig(tc.config)
+			assert.Equal(t, tc.success, err == nil)
+		})
+	}
+}
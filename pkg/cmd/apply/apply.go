@@ -22,7 +22,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
-	"reflect"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -44,7 +44,9 @@ import (
 	"kusionstack.io/kusion/pkg/engine"
 	"kusionstack.io/kusion/pkg/engine/operation"
 	"kusionstack.io/kusion/pkg/engine/operation/models"
+	"kusionstack.io/kusion/pkg/engine/plan"
 	"kusionstack.io/kusion/pkg/engine/printers"
+	"kusionstack.io/kusion/pkg/engine/printers/status"
 	"kusionstack.io/kusion/pkg/engine/release"
 	"kusionstack.io/kusion/pkg/engine/resource/graph"
 	"kusionstack.io/kusion/pkg/engine/runtime"
@@ -55,6 +57,7 @@ import (
 	"kusionstack.io/kusion/pkg/util/pretty"
 	"kusionstack.io/kusion/pkg/util/signal"
 	"kusionstack.io/kusion/pkg/util/terminal"
+	"kusionstack.io/kusion/pkg/version"
 )
 
 var (
@@ -75,6 +78,9 @@ var (
 		# Apply with specifying spec file
 		kusion apply --spec-file spec.yaml
 
+		# Apply exactly the changes saved by kusion preview --out plan
+		kusion apply plan
+
 		# Skip interactive approval of preview details before applying
 		kusion apply --yes
 		
@@ -87,6 +93,18 @@ var (
 		# Apply with the specified timeout duration for kusion apply command, measured in second(s)
 		kusion apply --timeout=120
 
+		# Roll back to the prior release if any resource fails to apply or reconcile
+		kusion apply --atomic
+
+		# Keep applying the resources not depending on the failed ones, and write the result of each resource as JUnit XML
+		kusion apply --continue-on-error --report junit --report-file report.xml
+
+		# Apply and print the result of each resource in the versioned YAML schema
+		kusion apply --yes -o yaml
+
+		# Apply and record the change notes in the release
+		kusion apply --notes "Bump the image to v1.2.0"
+
 		# Apply with localhost port forwarding
 		kusion apply --port-forward=8080`)
 )
@@ -101,6 +119,9 @@ var (
 	releaseCreated = false
 	releaseStorage release.Storage
 	portForwarded  = false
+	applyStarted   = false
+	// startLock guards applyStarted and portForwarded against the cancellation of the run.
+	startLock = &sync.Mutex{}
 )
 
 var errExit = errors.New("receive SIGTERM or SIGINT, exit cmd")
@@ -112,11 +133,16 @@ var errExit = errors.New("receive SIGTERM or SIGINT, exit cmd")
 type ApplyFlags struct {
 	*preview.PreviewFlags
 
-	Yes         bool
-	DryRun      bool
-	Watch       bool
-	Timeout     int
-	PortForward int
+	Yes             bool
+	DryRun          bool
+	Watch           bool
+	Timeout         int
+	PortForward     int
+	Atomic          bool
+	ContinueOnError bool
+	ReportFormat    string
+	ReportFile      string
+	Notes           string
 
 	genericiooptions.IOStreams
 }
@@ -125,12 +151,24 @@ type ApplyFlags struct {
 type ApplyOptions struct {
 	*preview.PreviewOptions
 
-	SpecFile    string
-	Yes         bool
-	DryRun      bool
-	Watch       bool
-	Timeout     int
-	PortForward int
+	SpecFile        string
+	PlanFile        string
+	Yes             bool
+	DryRun          bool
+	Watch           bool
+	Timeout         int
+	PortForward     int
+	Atomic          bool
+	ContinueOnError bool
+	ReportFormat    string
+	ReportFile      string
+	Notes           string
+
+	// Plan is the plan read from PlanFile, if any.
+	Plan *plan.Plan
+
+	// noReport skips printing the report and the output, e.g. when rolling back.
+	noReport bool
 
 	genericiooptions.IOStreams
 }
@@ -148,7 +186,7 @@ func NewCmdApply(ui *terminal.UI, ioStreams genericiooptions.IOStreams) *cobra.C
 	flags := NewApplyFlags(ui, ioStreams)
 
 	cmd := &cobra.Command{
-		Use:     "apply",
+		Use:     "apply [plan]",
 		Short:   "Apply the operational intent of various resources to multiple runtimes",
 		Long:    templates.LongDesc(applyLong),
 		Example: templates.Examples(applyExample),
@@ -177,6 +215,16 @@ func (f *ApplyFlags) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVarP(&f.Watch, "watch", "", true, i18n.T("After creating/updating/deleting the requested object, watch for changes"))
 	cmd.Flags().IntVarP(&f.Timeout, "timeout", "", 0, i18n.T("The timeout duration for kusion apply command, measured in second(s)"))
 	cmd.Flags().IntVarP(&f.PortForward, "port-forward", "", 0, i18n.T("Forward the specified port from local to service"))
+	cmd.Flags().BoolVarP(&f.Atomic, "rollback-on-failure", "", false, i18n.T("Roll back to the prior release if applying or watching any resource fails or times out"))
+	cmd.Flags().BoolVarP(&f.Atomic, "atomic", "", false, i18n.T("Alias of --rollback-on-failure"))
+	cmd.Flags().BoolVarP(&f.ContinueOnError, "continue-on-error", "", false, i18n.T("Keep applying the resources not depending on the failed ones"))
+	cmd.Flags().StringVarP(&f.ReportFormat, "report", "", "", i18n.T("Print the result of each resource after applying in the specified format, one of table, json, yaml and junit"))
+	cmd.Flags().StringVarP(&f.ReportFile, "report-file", "", "", i18n.T("Write the report to the file instead of the standard output"))
+	cmd.Flags().StringVarP(&f.Notes, "notes", "", "", i18n.T("The change notes recorded in the release"))
+
+	// The output of apply is the result of the resources rather than the changes.
+	cmd.Flags().Lookup("output").Usage = i18n.T("Print the result of each resource in the specified format to the standard output, one of json and yaml, " +
+		"while the progress is printed to the standard error")
 }
 
 // ToOptions converts from CLI inputs to runtime inputs.
@@ -188,14 +236,19 @@ func (f *ApplyFlags) ToOptions() (*ApplyOptions, error) {
 	}
 
 	o := &ApplyOptions{
-		PreviewOptions: previewOptions,
-		SpecFile:       f.SpecFile,
-		Yes:            f.Yes,
-		DryRun:         f.DryRun,
-		Watch:          f.Watch,
-		Timeout:        f.Timeout,
-		PortForward:    f.PortForward,
-		IOStreams:      f.IOStreams,
+		PreviewOptions:  previewOptions,
+		SpecFile:        f.SpecFile,
+		Yes:             f.Yes,
+		DryRun:          f.DryRun,
+		Watch:           f.Watch,
+		Timeout:         f.Timeout,
+		PortForward:     f.PortForward,
+		Atomic:          f.Atomic,
+		ContinueOnError: f.ContinueOnError,
+		ReportFormat:    f.ReportFormat,
+		ReportFile:      f.ReportFile,
+		Notes:           f.Notes,
+		IOStreams:       f.IOStreams,
 	}
 
 	return o, nil
@@ -203,14 +256,47 @@ func (f *ApplyFlags) ToOptions() (*ApplyOptions, error) {
 
 // Validate verifies if ApplyOptions are valid and without conflicts.
 func (o *ApplyOptions) Validate(cmd *cobra.Command, args []string) error {
-	if len(args) != 0 {
+	if len(args) > 1 {
 		return cmdutil.UsageErrorf(cmd, "Unexpected args: %v", args)
 	}
 
+	if len(args) == 1 {
+		if o.SpecFile != "" || len(o.Values) != 0 {
+			return cmdutil.UsageErrorf(cmd, "--spec-file and --argument can not be used when applying a plan")
+		}
+		o.PlanFile = args[0]
+		p, err := plan.ReadFile(o.PlanFile, plan.SigningKey())
+		if err != nil {
+			return fmt.Errorf("failed to read the plan file %s: %w", o.PlanFile, err)
+		}
+		o.Plan = p
+	}
+
 	if o.PortForward < 0 || o.PortForward > 65535 {
 		return cmdutil.UsageErrorf(cmd, "Invalid port number to forward: %d, must be between 1 and 65535", o.PortForward)
 	}
 
+	// The structured output of apply is the report of the resources in the versioned schema, which is the only
+	// thing printed to the standard output, so the diffs can't be approved interactively.
+	if o.Output != "" {
+		if !slices.Contains(models.ApplyOutputFormats, o.Output) {
+			return cmdutil.UsageErrorf(cmd, "Invalid output format: %s, must be one of %s", o.Output, strings.Join(models.ApplyOutputFormats, ", "))
+		}
+		if !o.Yes && o.PlanFile == "" {
+			return cmdutil.UsageErrorf(cmd, "--output can only be used with --yes or a plan")
+		}
+		if o.ReportFormat != "" && o.ReportFile == "" {
+			return cmdutil.UsageErrorf(cmd, "--report must be written to --report-file when --output is set")
+		}
+	}
+
+	if o.ReportFormat != "" && !slices.Contains(models.ReportFormats, o.ReportFormat) {
+		return cmdutil.UsageErrorf(cmd, "Invalid report format: %s, must be one of %s", o.ReportFormat, strings.Join(models.ReportFormats, ", "))
+	}
+	if o.ReportFile != "" && o.ReportFormat == "" {
+		return cmdutil.UsageErrorf(cmd, "--report-file can only be used with --report")
+	}
+
 	if o.SpecFile != "" {
 		absSF, _ := filepath.Abs(o.SpecFile)
 		fi, err := os.Stat(absSF)
@@ -251,22 +337,36 @@ func (o *ApplyOptions) Run() (err error) {
 			release.UpdateReleasePhase(rel, apiv1.ReleasePhaseFailed, relLock)
 			// Join the errors if update apply release failed.
 			err = errors.Join([]error{err, release.UpdateApplyRelease(releaseStorage, rel, o.DryRun, relLock)}...)
+			// Roll back the resources changed by the failed release, unless interrupted.
+			if o.Atomic && !o.DryRun && applyStarted && !errors.Is(err, errExit) {
+				err = errors.Join(err, o.rollback())
+			}
 		} else {
 			release.UpdateReleasePhase(rel, apiv1.ReleasePhaseSucceeded, relLock)
 			err = release.UpdateApplyRelease(releaseStorage, rel, o.DryRun, relLock)
 		}
 	}()
 
-	// set no style
-	if o.NoStyle {
+	// set no style, and print the progress to the standard error if the output is printed to the standard output
+	if o.NoStyle || o.Output != "" {
 		pterm.DisableStyling()
 	}
+	if o.Output != "" {
+		o.UI = o.UI.WithWriter(o.IOStreams.ErrOut)
+	}
 
 	// create release
 	releaseStorage, err = o.Backend.ReleaseStorage(o.RefProject.Name, o.RefWorkspace.Name)
 	if err != nil {
 		return
 	}
+	// refuse to apply the plan if a release has been created since the preview
+	if o.Plan != nil {
+		err = o.Plan.CheckBase(o.RefProject.Name, o.RefStack.Name, o.RefWorkspace.Name, releaseStorage.GetLatestRevision())
+		if err != nil {
+			return
+		}
+	}
 	rel, err = release.NewApplyRelease(releaseStorage, o.RefProject.Name, o.RefStack.Name, o.RefWorkspace.Name)
 	if err != nil {
 		return
@@ -282,8 +382,12 @@ func (o *ApplyOptions) Run() (err error) {
 	// Fixme: adopt a more centralized approach to manage the gracefully exit interrupted by
 	// the SIGINT or SIGTERM, instead of scattering them across different go-routines.
 	var timer <-chan time.Time
-	errCh := make(chan error, 1)
-	defer close(errCh)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// The error channel is buffered for both of the senders below and never closed, since the signal
+	// may arrive at any time.
+	errCh := make(chan error, 2)
+	done := make(chan struct{})
 
 	// Wait for the SIGTERM or SIGINT.
 	go func() {
@@ -293,7 +397,8 @@ func (o *ApplyOptions) Run() (err error) {
 	}()
 
 	go func() {
-		errCh <- o.run(rel, releaseStorage)
+		defer close(done)
+		errCh <- o.run(ctx, rel, releaseStorage)
 	}()
 
 	// Check whether the kusion apply command has timed out.
@@ -307,11 +412,11 @@ func (o *ApplyOptions) Run() (err error) {
 			return err
 		case <-timer:
 			err = fmt.Errorf("failed to execute kusion apply as: timeout for %d seconds", o.Timeout)
-			if !releaseCreated {
-				return
+			// Stop applying the resources, and wait for the apply in progress to finish before the
+			// release is updated or rolled back.
+			if cancelRun(cancel) {
+				<-done
 			}
-			release.UpdateReleasePhase(rel, apiv1.ReleasePhaseFailed, relLock)
-			err = errors.Join([]error{err, release.UpdateApplyRelease(releaseStorage, rel, o.DryRun, relLock)}...)
 			return err
 		}
 	} else {
@@ -325,7 +430,7 @@ func (o *ApplyOptions) Run() (err error) {
 }
 
 // run executes the apply cmd after the release is created.
-func (o *ApplyOptions) run(rel *apiv1.Release, releaseStorage release.Storage) (err error) {
+func (o *ApplyOptions) run(ctx context.Context, rel *apiv1.Release, releaseStorage release.Storage) (err error) {
 	defer func() {
 		if !releaseCreated {
 			return
@@ -343,11 +448,14 @@ func (o *ApplyOptions) run(rel *apiv1.Release, releaseStorage release.Storage) (
 		parameters[parts[0]] = parts[1]
 	}
 
-	// generate Spec
+	// generate Spec, or use the spec in the plan as is
 	var spec *apiv1.Spec
-	if o.SpecFile != "" {
+	switch {
+	case o.Plan != nil:
+		spec = o.Plan.Spec
+	case o.SpecFile != "":
 		spec, err = generate.SpecFromFile(o.SpecFile)
-	} else {
+	default:
 		spec, err = generate.GenerateSpecWithSpinner(o.RefProject, o.RefStack, o.RefWorkspace, parameters, o.UI, o.NoStyle)
 	}
 	if err != nil {
@@ -356,12 +464,13 @@ func (o *ApplyOptions) run(rel *apiv1.Release, releaseStorage release.Storage) (
 
 	// return immediately if no resource found in stack
 	if spec == nil || len(spec.Resources) == 0 {
-		fmt.Println(pretty.GreenBold("\nNo resource found in this stack."))
-		return nil
+		fmt.Fprintln(o.out(), pretty.GreenBold("\nNo resource found in this stack."))
+		return o.printReport(nil, nil)
 	}
 
 	// update release phase to previewing
 	rel.Spec = spec
+	rel.Provenance = release.NewProvenance(version.ReleaseVersion(), o.RefStack, spec, release.CurrentOperator(), o.Notes)
 	release.UpdateReleasePhase(rel, apiv1.ReleasePhasePreviewing, relLock)
 	if err = release.UpdateApplyRelease(releaseStorage, rel, o.DryRun, relLock); err != nil {
 		return
@@ -373,24 +482,35 @@ func (o *ApplyOptions) run(rel *apiv1.Release, releaseStorage release.Storage) (
 		return
 	}
 
+	// refuse to apply the plan if the live state has changed since the preview,
+	// so that exactly the planned changes are applied
+	if o.Plan != nil {
+		if err = o.Plan.CheckChanges(changes.ChangeOrder); err != nil {
+			return
+		}
+	}
+
 	if allUnChange(changes) {
-		fmt.Println("All resources are reconciled. No diff found")
-		return nil
+		fmt.Fprintln(o.out(), "All resources are reconciled. No diff found")
+		return o.printReport(nil, changes)
 	}
 
 	// summary preview table
-	changes.Summary(o.IOStreams.Out, o.NoStyle)
+	changes.Summary(o.out(), o.NoStyle)
+
+	// the changes in a plan have been approved when previewing
+	approved := o.Yes || o.Plan != nil
 
 	// detail detection
-	if o.Detail && o.All {
+	if o.Detail && o.All && o.Output == "" {
 		changes.OutputDiff("all")
-		if !o.Yes {
+		if !approved {
 			return nil
 		}
 	}
 
 	// prompt
-	if !o.Yes {
+	if !approved {
 		for {
 			var input string
 			input, err = prompt(o.UI)
@@ -407,67 +527,48 @@ func (o *ApplyOptions) run(rel *apiv1.Release, releaseStorage release.Storage) (
 				}
 				changes.OutputDiff(target)
 			} else {
-				fmt.Println("Operation apply canceled")
+				fmt.Fprintln(o.out(), "Operation apply canceled")
 				return nil
 			}
 		}
 	}
 
 	// update release phase to applying
+	rel.Actions = changes.Actions()
 	release.UpdateReleasePhase(rel, apiv1.ReleasePhaseApplying, relLock)
 	if err = release.UpdateApplyRelease(releaseStorage, rel, o.DryRun, relLock); err != nil {
 		return
 	}
 
-	// Get graph storage directory, create if not exist
-	graphStorage, err := o.Backend.GraphStorage(o.RefProject.Name, o.RefWorkspace.Name)
-	if err != nil {
-		return err
-	}
-
-	// Try to get existing graph, use the graph if exists
-	if graphStorage.CheckGraphStorageExistence() {
-		gph, err = graphStorage.Get()
-		if err != nil {
-			return err
-		}
-		err = graph.ValidateGraph(gph)
-		if err != nil {
-			return err
-		}
-		// Put new resources from the generated spec to graph
-		gph, err = graph.GenerateGraph(spec.Resources, gph)
-	} else {
-		// Create a new graph to be used globally if no graph is stored in the storage
-		gph = &apiv1.Graph{
-			Project:   o.RefProject.Name,
-			Workspace: o.RefWorkspace.Name,
-		}
-		gph, err = graph.GenerateGraph(spec.Resources, gph)
-	}
+	gph, err = o.prepareGraph(spec)
 	if err != nil {
 		return err
 	}
 
 	// start applying
-	fmt.Printf("\nStart applying diffs ...\n")
+	fmt.Fprintf(o.out(), "\nStart applying diffs ...\n")
 
 	// NOTE: release should be updated in the process of apply, so as to avoid the problem
 	// of being unable to update after being terminated by SIGINT or SIGTERM.
-	_, err = Apply(o, releaseStorage, rel, gph, changes)
+	if err = markStarted(ctx, &applyStarted); err != nil {
+		return
+	}
+	_, err = Apply(ctx, o, releaseStorage, rel, gph, changes)
 	if err != nil {
 		return
 	}
 
 	// if dry run, print the hint
 	if o.DryRun {
-		fmt.Printf("\nNOTE: Currently running in the --dry-run mode, the above configuration does not really take effect\n")
+		fmt.Fprintf(o.out(), "\nNOTE: Currently running in the --dry-run mode, the above configuration does not really take effect\n")
 		return nil
 	}
 
 	if o.PortForward > 0 {
-		fmt.Printf("\nStart port-forwarding ...\n")
-		portForwarded = true
+		if err = markStarted(ctx, &portForwarded); err != nil {
+			return
+		}
+		fmt.Fprintf(o.out(), "\nStart port-forwarding ...\n")
 		if err = PortForward(o, rel.Spec); err != nil {
 			return
 		}
@@ -476,9 +577,59 @@ func (o *ApplyOptions) run(rel *apiv1.Release, releaseStorage release.Storage) (
 	return
 }
 
+// markStarted marks the stage of the run as started, unless the run has been cancelled.
+func markStarted(ctx context.Context, started *bool) error {
+	startLock.Lock()
+	defer startLock.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	*started = true
+	return nil
+}
+
+// cancelRun cancels the run, and returns whether the apply has started and not been followed by the port
+// forwarding, which should be waited for since it's still updating the release and graph.
+func cancelRun(cancel context.CancelFunc) bool {
+	startLock.Lock()
+	defer startLock.Unlock()
+	cancel()
+	return applyStarted && !portForwarded
+}
+
+// prepareGraph gets the graph of the resources from the graph storage, or creates a new one if not exists,
+// and puts the resources of the spec into it.
+func (o *ApplyOptions) prepareGraph(spec *apiv1.Spec) (*apiv1.Graph, error) {
+	// Get graph storage directory, create if not exist
+	graphStorage, err := o.Backend.GraphStorage(o.RefProject.Name, o.RefWorkspace.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Try to get existing graph, use the graph if exists
+	if graphStorage.CheckGraphStorageExistence() {
+		g, err := graphStorage.Get()
+		if err != nil {
+			return nil, err
+		}
+		if err = graph.ValidateGraph(g); err != nil {
+			return nil, err
+		}
+		// Put new resources from the generated spec to graph
+		return graph.GenerateGraph(spec.Resources, g)
+	}
+	// Create a new graph to be used globally if no graph is stored in the storage
+	return graph.GenerateGraph(spec.Resources, &apiv1.Graph{
+		Project:   o.RefProject.Name,
+		Workspace: o.RefWorkspace.Name,
+	})
+}
+
 // The Apply function will apply the resources changes through the execution kusion engine.
 // You can customize the runtime of engine and the release releaseStorage through `runtime` and `releaseStorage` parameters.
+// The resources not applied yet are skipped once the context is done.
 func Apply(
+	ctx context.Context,
 	o *ApplyOptions,
 	releaseStorage release.Storage,
 	rel *apiv1.Release,
@@ -541,13 +692,23 @@ func Apply(
 		}
 	}()
 
+	// Get the readiness policies configured in the workspace, before any resource is applied.
+	var policies status.Policies
+	if o.Watch && !o.DryRun {
+		if policies, err = status.PoliciesFromContext(rel.Spec.Context); err != nil {
+			return nil, fmt.Errorf("invalid readiness policies: %w", err)
+		}
+	}
+
 	// construct the apply operation
 	ac := &operation.ApplyOperation{
 		Operation: models.Operation{
-			Stack:          changes.Stack(),
-			ReleaseStorage: releaseStorage,
-			MsgCh:          make(chan models.Message),
-			IgnoreFields:   o.IgnoreFields,
+			Stack:           changes.Stack(),
+			ReleaseStorage:  releaseStorage,
+			MsgCh:           make(chan models.Message),
+			IgnoreFields:    o.IgnoreFields,
+			ContinueOnError: o.ContinueOnError,
+			Ctx:             ctx,
 		},
 	}
 
@@ -617,10 +778,13 @@ func Apply(
 		gph.Resources,
 	)
 
-	watchErrCh := make(chan error)
+	// The watch error channel is buffered so that the watching go-routine never blocks on it, even if
+	// the apply fails and returns without waiting for the watch.
+	watchErrCh := make(chan error, 1)
 	// Apply while watching the resources.
 	if o.Watch && !o.DryRun {
 		Watch(
+			ctx,
 			ac,
 			changes,
 			&err,
@@ -629,12 +793,17 @@ func Apply(
 			multi,
 			changesWriterMap,
 			gph,
+			o.Atomic,
+			policies,
 		)
 	}
 
 	var updatedRel *apiv1.Release
+	var report *models.ApplyReport
 	if o.DryRun {
+		report = models.NewApplyReport(rel.Stack)
 		for _, r := range rel.Spec.Resources {
+			report.Record(r.ResourceKey(), models.Undefined, models.Success, nil, 0)
 			ac.MsgCh <- models.Message{
 				ResourceID: r.ResourceKey(),
 				OpResult:   models.Success,
@@ -652,9 +821,15 @@ func Apply(
 			Release: rel,
 			Graph:   gph,
 		})
+		if rsp != nil {
+			report = rsp.Report
+		}
 		if v1.IsErr(st) {
 			errWriter.(*bytes.Buffer).Reset()
 			err = fmt.Errorf("apply failed, status:\n%v", st)
+			// Report the result of each resource after all the messages are printed.
+			wg.Wait()
+			err = errors.Join(err, o.printReport(report, changes))
 			return nil, err
 		}
 		// Update the release with that in the apply response if not dryrun.
@@ -667,25 +842,62 @@ func Apply(
 	wg.Wait()
 	// Wait for watchWg closed if need to perform watching.
 	if o.Watch && !o.DryRun {
-		shouldBreak := false
-		for !shouldBreak {
-			select {
-			case watchErr := <-watchErrCh:
-				if watchErr != nil {
-					return nil, watchErr
-				}
-				shouldBreak = true
-			default:
-				continue
-			}
+		if watchErr := <-watchErrCh; watchErr != nil {
+			return nil, errors.Join(watchErr, o.printReport(report, changes))
 		}
 	}
 
 	// print summary
 	pterm.Fprintln(pbWriter, fmt.Sprintf("\nApply complete! Resources: %d created, %d updated, %d deleted.", ls.created, ls.updated, ls.deleted))
+	if err = o.printReport(report, changes); err != nil {
+		return nil, err
+	}
 	return updatedRel, nil
 }
 
+// out returns the writer of the human-readable output, which is the standard error if the structured output
+// is printed to the standard output.
+func (o *ApplyOptions) out() io.Writer {
+	if o.Output != "" {
+		return o.IOStreams.ErrOut
+	}
+	return o.IOStreams.Out
+}
+
+// printReport prints the result of each resource in the report format, to the report file if specified, and
+// in the output format to the standard output. An empty report is printed if nothing is applied, so that the
+// structured output is always there.
+func (o *ApplyOptions) printReport(report *models.ApplyReport, changes *models.Changes) error {
+	if o.noReport || (o.ReportFormat == "" && o.Output == "") {
+		return nil
+	}
+	if report == nil {
+		report = models.NewApplyReport(o.RefStack.Name)
+	}
+	if changes != nil {
+		report.SetActions(changes.ChangeOrder)
+	}
+
+	if o.ReportFormat != "" {
+		w := o.IOStreams.Out
+		if o.ReportFile != "" {
+			f, err := os.Create(o.ReportFile)
+			if err != nil {
+				return fmt.Errorf("failed to create the report file %s: %w", o.ReportFile, err)
+			}
+			defer f.Close()
+			w = f
+		}
+		if err := report.Print(w, o.ReportFormat, o.NoStyle); err != nil {
+			return err
+		}
+	}
+	if o.Output != "" {
+		return report.Print(o.IOStreams.Out, o.Output, true)
+	}
+	return nil
+}
+
 // PrintApplyDetails function will receive the messages of the apply operation and print the details.
 // Fixme: abstract the input variables into a struct.
 func PrintApplyDetails(
@@ -730,6 +942,12 @@ func PrintApplyDetails(
 			// Update the progressbar and spinner printer according to the operation result.
 			switch msg.OpResult {
 			case models.Success, models.Skip:
+				// The resource is not applied because of the failed resources.
+				if msg.OpResult == models.Skip && msg.OpErr != nil {
+					changesWriterMap[msg.ResourceID].Warning(fmt.Sprintf("Skipped %s as: %s", pterm.Bold.Sprint(changeStep.ID), msg.OpErr.Error()))
+					progressbar.Increment()
+					break
+				}
 				var title string
 				if changeStep.Action == models.UnChanged {
 					title = fmt.Sprintf("Skipped %s", pterm.Bold.Sprint(changeStep.ID))
@@ -787,6 +1005,7 @@ func PrintApplyDetails(
 // Watch function will watch the changed Kubernetes and Terraform resources.
 // Fixme: abstract the input variables into a struct.
 func Watch(
+	ctx context.Context,
 	ac *operation.ApplyOperation,
 	changes *models.Changes,
 	err *error,
@@ -795,6 +1014,8 @@ func Watch(
 	multi *pterm.MultiPrinter,
 	changesWriterMap map[string]*pterm.SpinnerPrinter,
 	gph *apiv1.Graph,
+	atomic bool,
+	policies status.Policies,
 ) {
 	resourceMap := make(map[string]apiv1.Resource)
 	ioWriterMap := make(map[string]io.Writer)
@@ -814,10 +1035,7 @@ func Watch(
 				cmdutil.RecoverErr(err)
 				log.Error(*err)
 			}
-			if *err != nil {
-				if !releaseCreated {
-					return
-				}
+			if *err != nil && releaseCreated {
 				release.UpdateReleasePhase(rel, apiv1.ReleasePhaseFailed, relLock)
 				_ = release.UpdateApplyRelease(releaseStorage, rel, dryRun, relLock)
 			}
@@ -830,14 +1048,19 @@ func Watch(
 			panic(fmt.Errorf("failed to init runtimes: %s", s.String()))
 		}
 
-		// Prepare the tables for printing the details of the resources.
+		// Prepare the tables for printing the details of the resources, which
+		// are rendered by a single ticker.
 		tables := make(map[string]*printers.Table, len(toBeWatched))
 		ticker := time.NewTicker(time.Millisecond * 100)
 		defer ticker.Stop()
 
-		// Record the watched and finished resources.
+		// Record the watched and finished resources, which are reported by the
+		// watching go-routines, so that no polling is needed.
 		watchedIDs := []string{}
+		done := make(chan string, len(toBeWatched))
 		finished := make(map[string]bool)
+		// The resources failed to reconcile, which fail the apply in the atomic mode.
+		var unready []string
 
 		for !(len(finished) == len(toBeWatched)) {
 			select {
@@ -845,13 +1068,14 @@ func Watch(
 			case id := <-ac.WatchCh:
 				res := resourceMap[id]
 				// Set the timeout duration for watch context, here we set an experiential value of 60 minutes.
-				ctx, cancel := context.WithTimeout(context.Background(), time.Minute*time.Duration(60))
+				watchCtx, cancel := context.WithTimeout(ctx, time.Minute*time.Duration(60))
 				defer cancel()
 
 				// Get the event channel for watching the resource.
-				rsp := runtimes[res.Type].Watch(ctx, &runtime.WatchRequest{Resource: &res})
+				rsp := runtimes[res.Type].Watch(watchCtx, &runtime.WatchRequest{Resource: &res})
 				if rsp == nil {
 					log.Debug("unsupported resource type: %s", res.Type)
+					done <- id
 					continue
 				}
 				if v1.IsErr(rsp.Status) {
@@ -860,54 +1084,80 @@ func Watch(
 
 				w := rsp.Watchers
 				table := printers.NewTable(w.IDs)
-				tables[id] = table
 
 				// Setup a go-routine to concurrently watch K8s and TF resources.
 				if res.Type == apiv1.Kubernetes {
 					healthPolicy, kind := getResourceInfo(&res)
-					go watchK8sResources(id, kind, w.Watchers, table, tables, gph, dryRun, healthPolicy)
+					go func() {
+						defer func() { done <- id }()
+						watchK8sResources(watchCtx, id, kind, w.Watchers, table, gph, dryRun, healthPolicy, policies)
+					}()
 				} else if res.Type == apiv1.Terraform {
-					go watchTFResources(id, w.TFWatcher, table, dryRun)
+					go func() {
+						defer func() { done <- id }()
+						watchTFResources(id, w.TFWatcher, table, dryRun)
+					}()
 				} else {
 					log.Debug("unsupported resource type to watch: %s", string(res.Type))
+					done <- id
 					continue
 				}
+				tables[id] = table
 
 				// Record the io writer related to the resource ID.
 				ioWriterMap[id] = multi.NewWriter()
 				watchedIDs = append(watchedIDs, id)
 
-			// Refresh the tables printing details of the resources to be watched.
-			default:
-				for _, id := range watchedIDs {
-					w, ok := ioWriterMap[id]
-					if !ok {
-						panic(fmt.Errorf("failed to get io writer while watching %s", id))
-					}
-					printTable(&w, id, tables)
+			// Record the resource finished watching.
+			case id := <-done:
+				finished[id] = true
+				// The resources not supported to watch have no table to print.
+				if _, ok := tables[id]; !ok {
+					continue
 				}
-				for id, table := range tables {
-					if finished[id] {
-						continue
-					}
+				printWatchedTables(ioWriterMap, watchedIDs, tables)
+				if !tables[id].AllCompleted() {
+					changesWriterMap[id].Fail(fmt.Sprintf("Failed to reconcile %s", pterm.Bold.Sprint(id)))
+					unready = append(unready, id)
+					continue
+				}
+				changesWriterMap[id].Success(fmt.Sprintf("Succeeded %s", pterm.Bold.Sprint(id)))
 
-					if table.AllCompleted() {
-						finished[id] = true
-						changesWriterMap[id].Success(fmt.Sprintf("Succeeded %s", pterm.Bold.Sprint(id)))
+				// Update resource status to reconciled.
+				resource := graph.FindGraphResourceByID(gph.Resources, id)
+				if resource != nil {
+					resource.Status = apiv1.Reconciled
+				}
 
-						// Update resource status to reconciled.
-						resource := graph.FindGraphResourceByID(gph.Resources, id)
-						if resource != nil {
-							resource.Status = apiv1.Reconciled
-						}
-					}
+			// Refresh the tables printing details of the resources to be watched.
+			case <-ticker.C:
+				printWatchedTables(ioWriterMap, watchedIDs, tables)
+
+			// Stop watching once the apply is cancelled or has returned on failure, since the
+			// resources failed or skipped never arrive to be watched.
+			case <-ctx.Done():
+				if *err == nil {
+					*err = fmt.Errorf("watch cancelled: %w", ctx.Err())
 				}
-				<-ticker.C
+				return
 			}
 		}
+		if atomic && len(unready) > 0 {
+			*err = fmt.Errorf("failed to reconcile resources: %s", strings.Join(unready, ", "))
+		}
 	}()
 }
 
+func printWatchedTables(ioWriterMap map[string]io.Writer, watchedIDs []string, tables map[string]*printers.Table) {
+	for _, id := range watchedIDs {
+		w, ok := ioWriterMap[id]
+		if !ok {
+			panic(fmt.Errorf("failed to get io writer while watching %s", id))
+		}
+		printTable(&w, id, tables)
+	}
+}
+
 // PortForward function will forward the specified port from local to the project Kubernetes Service.
 //
 // Example:
@@ -931,7 +1181,7 @@ func PortForward(
 	spec *apiv1.Spec,
 ) error {
 	if o.DryRun {
-		fmt.Println("NOTE: Portforward doesn't work in DryRun mode")
+		fmt.Fprintln(o.out(), "NOTE: Portforward doesn't work in DryRun mode")
 		return nil
 	}
 
@@ -944,7 +1194,7 @@ func PortForward(
 		return err
 	}
 
-	fmt.Println("Portforward has been completed!")
+	fmt.Fprintln(o.out(), "Portforward has been completed!")
 	return nil
 }
 
@@ -997,13 +1247,14 @@ func prompt(ui *terminal.UI) (string, error) {
 }
 
 func watchK8sResources(
+	ctx context.Context,
 	id, kind string,
 	chs []<-chan watch.Event,
 	table *printers.Table,
-	tables map[string]*printers.Table,
 	gph *apiv1.Graph,
 	dryRun bool,
 	healthPolicy interface{},
+	policies status.Policies,
 ) {
 	defer func() {
 		var err error
@@ -1026,63 +1277,43 @@ func watchK8sResources(
 		resource.Status = apiv1.ReconcileFail
 	}
 
-	// Resources selects
-	cases := createSelectCases(chs)
-	// Default select
-	cases = append(cases, reflect.SelectCase{
-		Dir:  reflect.SelectDefault,
-		Chan: reflect.Value{},
-		Send: reflect.Value{},
-	})
-
-	for {
-		chosen, recv, recvOK := reflect.Select(cases)
-		if cases[chosen].Dir == reflect.SelectDefault {
-			continue
-		}
-		if recvOK {
-			e := recv.Interface().(watch.Event)
-			o := e.Object.(*unstructured.Unstructured)
-			var detail string
-			var ready bool
-			if e.Type == watch.Deleted {
-				detail = fmt.Sprintf("%s has beed deleted", o.GetName())
-				ready = true
-			} else {
-				// Restore to actual type
-				target := printers.Convert(o)
-				// Check reconcile status with customized health policy for specific resource
-				if healthPolicy != nil && kind == o.GetObjectKind().GroupVersionKind().Kind {
-					if code, ok := kcl.ConvertKCLCode(healthPolicy); ok {
-						resByte, err := yaml.Marshal(o.Object)
-						if err != nil {
-							log.Error(err)
-							return
-						}
-						detail, ready = printers.PrintCustomizedHealthCheck(code, resByte)
-					} else {
-						detail, ready = printers.Generate(target)
+	// Block on the events until all the objects are ready or the watch times out.
+	for e := range runtime.MergeEvents(ctx, chs...) {
+		o := e.Object.(*unstructured.Unstructured)
+		var detail string
+		var ready bool
+		if e.Type == watch.Deleted {
+			detail = fmt.Sprintf("%s has beed deleted", o.GetName())
+			ready = true
+		} else {
+			// Check reconcile status with customized health policy for specific resource
+			if healthPolicy != nil && kind == o.GetObjectKind().GroupVersionKind().Kind {
+				if code, ok := kcl.ConvertKCLCode(healthPolicy); ok {
+					resByte, err := yaml.Marshal(o.Object)
+					if err != nil {
+						log.Error(err)
+						return
 					}
+					detail, ready = printers.PrintCustomizedHealthCheck(code, resByte)
 				} else {
-					// Check reconcile status with default setup
-					detail, ready = printers.Generate(target)
+					detail, ready = printers.Status(o, policies)
 				}
+			} else {
+				// Check reconcile status with default setup
+				detail, ready = printers.Status(o, policies)
 			}
+		}
 
-			// Mark ready for breaking loop
-			if ready {
-				e.Type = printers.READY
-			}
-
-			// Save watched msg
-			table.Update(
-				engine.BuildIDForKubernetes(o),
-				printers.NewRow(e.Type, o.GetKind(), o.GetName(), detail))
-
-			// Write back
-			tables[id] = table
+		// Mark ready for breaking loop
+		if ready {
+			e.Type = printers.READY
 		}
 
+		// Save watched msg
+		table.Update(
+			engine.BuildIDForKubernetes(o),
+			printers.NewRow(e.Type, o.GetKind(), o.GetName(), detail))
+
 		// Break when completed
 		if table.AllCompleted() {
 			break
@@ -1144,17 +1375,6 @@ func watchTFResources(
 	}
 }
 
-func createSelectCases(chs []<-chan watch.Event) []reflect.SelectCase {
-	cases := make([]reflect.SelectCase, 0, len(chs))
-	for _, ch := range chs {
-		cases = append(cases, reflect.SelectCase{
-			Dir:  reflect.SelectRecv,
-			Chan: reflect.ValueOf(ch),
-		})
-	}
-	return cases
-}
-
 func printTable(w *io.Writer, id string, tables map[string]*printers.Table) {
 	// Reset the buffer for live flushing.
 	(*w).(*bytes.Buffer).Reset()
@@ -17,7 +17,9 @@ package apply
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"testing"
 	"time"
@@ -28,6 +30,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
 
 	apiv1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
 	v1 "kusionstack.io/kusion/pkg/apis/status/v1"
@@ -38,11 +41,13 @@ import (
 	"kusionstack.io/kusion/pkg/engine"
 	"kusionstack.io/kusion/pkg/engine/operation"
 	"kusionstack.io/kusion/pkg/engine/operation/models"
+	"kusionstack.io/kusion/pkg/engine/plan"
 	"kusionstack.io/kusion/pkg/engine/printers"
 	releasestorages "kusionstack.io/kusion/pkg/engine/release/storages"
 	"kusionstack.io/kusion/pkg/engine/resource/graph"
 	graphstorages "kusionstack.io/kusion/pkg/engine/resource/graph/storages"
 	"kusionstack.io/kusion/pkg/engine/runtime"
+	runtimeinit "kusionstack.io/kusion/pkg/engine/runtime/init"
 	"kusionstack.io/kusion/pkg/engine/runtime/kubernetes"
 	"kusionstack.io/kusion/pkg/util/terminal"
 	workspacestorages "kusionstack.io/kusion/pkg/workspace/storages"
@@ -140,30 +145,41 @@ func mockPatchOperationPreview() *mockey.Mocker {
 		*operation.PreviewRequest,
 	) (rsp *operation.PreviewResponse, s v1.Status) {
 		return &operation.PreviewResponse{
-			Order: &models.ChangeOrder{
-				StepKeys: []string{sa1.ID, sa2.ID, sa3.ID},
-				ChangeSteps: map[string]*models.ChangeStep{
-					sa1.ID: {
-						ID:     sa1.ID,
-						Action: models.Create,
-						From:   &sa1,
-					},
-					sa2.ID: {
-						ID:     sa2.ID,
-						Action: models.UnChanged,
-						From:   &sa2,
-					},
-					sa3.ID: {
-						ID:     sa3.ID,
-						Action: models.Undefined,
-						From:   &sa1,
-					},
-				},
-			},
+			Order: mockChangeOrder(),
 		}, nil
 	}).Build()
 }
 
+func mockChangeOrder() *models.ChangeOrder {
+	return &models.ChangeOrder{
+		StepKeys: []string{sa1.ID, sa2.ID, sa3.ID},
+		ChangeSteps: map[string]*models.ChangeStep{
+			sa1.ID: {
+				ID:     sa1.ID,
+				Action: models.Create,
+				From:   &sa1,
+			},
+			sa2.ID: {
+				ID:     sa2.ID,
+				Action: models.UnChanged,
+				From:   &sa2,
+			},
+			sa3.ID: {
+				ID:     sa3.ID,
+				Action: models.Undefined,
+				From:   &sa1,
+			},
+		},
+	}
+}
+
+func mockPlan(t *testing.T, baseRevision uint64) *plan.Plan {
+	p, err := plan.New(proj.Name, stack.Name, workspace.Name, baseRevision,
+		&apiv1.Spec{Resources: []apiv1.Resource{sa1, sa2, sa3}}, mockChangeOrder())
+	assert.Nil(t, err)
+	return p
+}
+
 func mockWorkspaceStorage() {
 	mockey.Mock((*storages.LocalStorage).WorkspaceStorage).Return(&workspacestorages.LocalStorage{}, nil).Build()
 }
@@ -208,6 +224,33 @@ func TestApplyOptions_Run(t *testing.T) {
 		err := o.Run()
 		assert.Nil(t, err)
 	})
+
+	mockey.PatchConvey("Apply a plan", t, func() {
+		mockPatchNewKubernetesRuntime()
+		mockPatchOperationPreview()
+		mockWorkspaceStorage()
+		mockReleaseStorage()
+		mockOperationApply(models.Success)
+
+		o := newApplyOptions()
+		o.DryRun = true
+		o.Plan = mockPlan(t, 0)
+		err := o.Run()
+		assert.Nil(t, err)
+	})
+
+	mockey.PatchConvey("Apply a stale plan", t, func() {
+		mockPatchNewKubernetesRuntime()
+		mockPatchOperationPreview()
+		mockWorkspaceStorage()
+		mockReleaseStorage()
+
+		o := newApplyOptions()
+		o.DryRun = true
+		o.Plan = mockPlan(t, 1)
+		err := o.Run()
+		assert.ErrorIs(t, err, plan.ErrBaseRevisionMismatch)
+	})
 }
 
 const (
@@ -237,6 +280,21 @@ func newSA(name string) apiv1.Resource {
 	}
 }
 
+func TestCancelRun(t *testing.T) {
+	defer func() {
+		applyStarted, portForwarded = false, false
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	assert.Nil(t, markStarted(ctx, &applyStarted))
+	assert.True(t, cancelRun(cancel))
+	assert.ErrorIs(t, markStarted(ctx, &portForwarded), context.Canceled)
+	assert.False(t, portForwarded)
+
+	applyStarted = false
+	assert.False(t, cancelRun(cancel))
+}
+
 func TestApply(t *testing.T) {
 	loc, _ := time.LoadLocation("Asia/Shanghai")
 	mockey.PatchConvey("dry run", t, func() {
@@ -267,7 +325,7 @@ func TestApply(t *testing.T) {
 		graph := &apiv1.Graph{}
 		o := newApplyOptions()
 		o.DryRun = true
-		_, err := Apply(o, &releasestorages.LocalStorage{}, rel, graph, changes)
+		_, err := Apply(context.Background(), o, &releasestorages.LocalStorage{}, rel, graph, changes)
 		assert.Nil(t, err)
 	})
 	mockey.PatchConvey("apply success", t, func() {
@@ -310,7 +368,7 @@ func TestApply(t *testing.T) {
 			Workspace: rel.Workspace,
 		}
 		graph.GenerateGraph(rel.Spec.Resources, gph)
-		_, err := Apply(o, &releasestorages.LocalStorage{}, rel, gph, changes)
+		_, err := Apply(context.Background(), o, &releasestorages.LocalStorage{}, rel, gph, changes)
 		assert.Nil(t, err)
 	})
 	mockey.PatchConvey("apply failed", t, func() {
@@ -342,7 +400,7 @@ func TestApply(t *testing.T) {
 		changes := models.NewChanges(proj, stack, order)
 		gph := &apiv1.Graph{}
 		graph.GenerateGraph(rel.Spec.Resources, gph)
-		_, err := Apply(o, &releasestorages.LocalStorage{}, rel, gph, changes)
+		_, err := Apply(context.Background(), o, &releasestorages.LocalStorage{}, rel, gph, changes)
 		assert.NotNil(t, err)
 	})
 }
@@ -438,9 +496,6 @@ func TestWatchK8sResources(t *testing.T) {
 			IDs:  []string{id},
 			Rows: map[string]*printers.Row{},
 		}
-		tables := map[string]*printers.Table{
-			id: table,
-		}
 		resource := &apiv1.GraphResource{
 			ID:              id,
 			Type:            "",
@@ -461,7 +516,7 @@ func TestWatchK8sResources(t *testing.T) {
 			},
 		}
 		graph.UpdateResourceIndex(gph.Resources)
-		watchK8sResources(id, "", chs, table, tables, gph, true, nil)
+		watchK8sResources(context.Background(), id, "", chs, table, gph, true, nil, nil)
 
 		assert.Equal(t, true, table.AllCompleted())
 	})
@@ -494,9 +549,6 @@ func TestWatchK8sResources(t *testing.T) {
 			IDs:  []string{id},
 			Rows: map[string]*printers.Row{},
 		}
-		tables := map[string]*printers.Table{
-			id: table,
-		}
 		var policyInterface interface{}
 		healthPolicy := map[string]interface{}{
 			"health.kcl": "assert res.metadata.generation == 1",
@@ -522,7 +574,7 @@ func TestWatchK8sResources(t *testing.T) {
 			},
 		}
 		graph.UpdateResourceIndex(gph.Resources)
-		watchK8sResources(id, "Deployment", chs, table, tables, gph, false, policyInterface)
+		watchK8sResources(context.Background(), id, "Deployment", chs, table, gph, false, policyInterface, nil)
 
 		assert.Equal(t, true, table.AllCompleted())
 	})
@@ -554,6 +606,57 @@ func TestWatchTFResources(t *testing.T) {
 	})
 }
 
+func TestWatch(t *testing.T) {
+	newWatchRelease := func() *apiv1.Release {
+		return &apiv1.Release{
+			Spec:  &apiv1.Spec{Resources: []apiv1.Resource{sa1}},
+			State: &apiv1.State{},
+		}
+	}
+	changes := models.NewChanges(proj, stack, &models.ChangeOrder{
+		StepKeys: []string{sa1.ID},
+		ChangeSteps: map[string]*models.ChangeStep{
+			sa1.ID: {ID: sa1.ID, Action: models.Create, From: &sa1},
+		},
+	})
+
+	mockey.PatchConvey("finish the resources not supported to watch", t, func() {
+		mockey.Mock(runtimeinit.Runtimes).Return(map[apiv1.Type]runtime.Runtime{runtime.Kubernetes: &fakerRuntime{}}, nil).Build()
+		rel = newWatchRelease()
+		ac := &operation.ApplyOperation{Operation: models.Operation{WatchCh: make(chan string, 1)}}
+		ac.WatchCh <- sa1.ID
+
+		var err error
+		watchErrCh := make(chan error, 1)
+		Watch(context.Background(), ac, changes, &err, false, watchErrCh, nil, nil, &apiv1.Graph{}, false, nil)
+		select {
+		case watchErr := <-watchErrCh:
+			assert.NoError(t, watchErr)
+		case <-time.After(10 * time.Second):
+			t.Fatal("watch is blocked")
+		}
+	})
+
+	mockey.PatchConvey("stop watching once cancelled", t, func() {
+		mockey.Mock(runtimeinit.Runtimes).Return(map[apiv1.Type]runtime.Runtime{runtime.Kubernetes: &fakerRuntime{}}, nil).Build()
+		rel = newWatchRelease()
+		// The resource never arrives to be watched, as if it failed to apply.
+		ac := &operation.ApplyOperation{Operation: models.Operation{WatchCh: make(chan string, 1)}}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var err error
+		watchErrCh := make(chan error, 1)
+		Watch(ctx, ac, changes, &err, false, watchErrCh, nil, nil, &apiv1.Graph{}, false, nil)
+		select {
+		case watchErr := <-watchErrCh:
+			assert.ErrorIs(t, watchErr, context.Canceled)
+		case <-time.After(10 * time.Second):
+			t.Fatal("watch is blocked")
+		}
+	})
+}
+
 func TestPrintTable(t *testing.T) {
 	w := io.Writer(bytes.NewBufferString(""))
 	id := "fake-resource-id"
@@ -646,3 +749,37 @@ func TestGetResourceInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyOptions_PrintReport(t *testing.T) {
+	t.Run("print an empty report as the output if nothing applied", func(t *testing.T) {
+		streams, _, out, errOut := genericiooptions.NewTestIOStreams()
+		o := &ApplyOptions{
+			PreviewOptions: &preview.PreviewOptions{
+				MetaOptions: &meta.MetaOptions{RefStack: &apiv1.Stack{Name: "dev"}},
+				Output:      models.OutputJSON,
+			},
+			IOStreams: streams,
+		}
+		fmt.Fprintln(o.out(), "All resources are reconciled. No diff found")
+		assert.NoError(t, o.printReport(nil, nil))
+
+		var output map[string]any
+		assert.NoError(t, json.Unmarshal(out.Bytes(), &output))
+		assert.Equal(t, "dev", output["stack"])
+		assert.Contains(t, errOut.String(), "No diff found")
+	})
+
+	t.Run("print nothing when rolling back", func(t *testing.T) {
+		streams, _, out, _ := genericiooptions.NewTestIOStreams()
+		o := &ApplyOptions{
+			PreviewOptions: &preview.PreviewOptions{
+				MetaOptions: &meta.MetaOptions{RefStack: &apiv1.Stack{Name: "dev"}},
+				Output:      models.OutputYAML,
+			},
+			IOStreams: streams,
+			noReport:  true,
+		}
+		assert.NoError(t, o.printReport(models.NewApplyReport("dev"), nil))
+		assert.Empty(t, out.String())
+	})
+}
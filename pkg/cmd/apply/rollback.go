@@ -0,0 +1,82 @@
+// Copyright 2024 KusionStack Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apply
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apiv1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/cmd/preview"
+	"kusionstack.io/kusion/pkg/engine/release"
+	"kusionstack.io/kusion/pkg/util/pretty"
+)
+
+// rollback rolls back the resources changed by the failed release to the state of the release before it. The
+// rollback is recorded as a new release, and the failed release is kept as is.
+func (o *ApplyOptions) rollback() (err error) {
+	relLock.Lock()
+	failed := *rel
+	relLock.Unlock()
+
+	fmt.Fprintln(o.out(), pretty.YellowBold("\nRolling back the failed release %d ...", failed.Revision))
+	rollbackRel, err := release.CreateRollbackRelease(releaseStorage, &failed)
+	if err != nil {
+		return fmt.Errorf("failed to roll back release %d: %w", failed.Revision, err)
+	}
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("failed to roll back release %d: %w", failed.Revision, err)
+			release.UpdateReleasePhase(rollbackRel, apiv1.ReleasePhaseFailed, relLock)
+		} else {
+			release.UpdateReleasePhase(rollbackRel, apiv1.ReleasePhaseSucceeded, relLock)
+		}
+		err = errors.Join(err, release.UpdateApplyRelease(releaseStorage, rollbackRel, false, relLock))
+	}()
+
+	// compute the changes back to the prior state
+	changes, err := preview.Preview(o.PreviewOptions, releaseStorage, rollbackRel.Spec, rollbackRel.State, o.RefProject, o.RefStack)
+	if err != nil {
+		return err
+	}
+	if allUnChange(changes) {
+		fmt.Fprintln(o.out(), "No resource changed by the failed release, nothing to roll back")
+		return nil
+	}
+	changes.Summary(o.out(), o.NoStyle)
+
+	release.UpdateReleasePhase(rollbackRel, apiv1.ReleasePhaseApplying, relLock)
+	if err = release.UpdateApplyRelease(releaseStorage, rollbackRel, false, relLock); err != nil {
+		return err
+	}
+	rollbackGph, err := o.prepareGraph(rollbackRel.Spec)
+	if err != nil {
+		return err
+	}
+
+	// The resources are not watched when rolling back, since the watch of the failed release may be still in
+	// progress, and the rollback has nothing to fall back to anyway.
+	ro := *o
+	ro.Watch = false
+	ro.PortForward = 0
+	ro.noReport = true
+	if _, err = Apply(context.Background(), &ro, releaseStorage, rollbackRel, rollbackGph, changes); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(o.out(), pretty.GreenBold("\nRolled back the failed release %d with release %d", failed.Revision, rollbackRel.Revision))
+	return nil
+}
@@ -0,0 +1,30 @@
+package backendcmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	cmdutil "kusionstack.io/kusion/pkg/cmd/util"
+	"kusionstack.io/kusion/pkg/util/i18n"
+)
+
+var backendLong = i18n.T(`
+		Commands for operating the Kusion backends.
+
+		These commands help you operate the files stored in the backends, such as migrating them between backends.`)
+
+// NewCmdBackend returns an initialized Command instance for 'backend' sub command.
+func NewCmdBackend(streams genericiooptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "backend",
+		DisableFlagsInUseLine: true,
+		Short:                 "Operate the Kusion backends",
+		Long:                  templates.LongDesc(backendLong),
+		Run:                   cmdutil.DefaultSubCommandRun(streams.ErrOut),
+	}
+
+	cmd.AddCommand(NewCmdMigrate(streams))
+
+	return cmd
+}
@@ -0,0 +1,447 @@
+package backendcmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/backend"
+	cmdutil "kusionstack.io/kusion/pkg/cmd/util"
+	"kusionstack.io/kusion/pkg/util/i18n"
+	workspacestorages "kusionstack.io/kusion/pkg/workspace/storages"
+)
+
+var (
+	migrateShort = i18n.T("Migrate the workspaces, releases and graphs from one backend to another")
+
+	migrateLong = i18n.T(`
+	Migrate the workspaces, releases and resource graphs from one backend to another.
+
+	All the workspaces, the releases of every project and workspace, and the resource graphs are copied from
+	the source backend to the target backend. The revision numbers and the stacks of the releases are preserved,
+	and the current workspace of the target backend is set to the one of the source backend.
+
+	Every copied file is read back from the target backend and verified against the checksum of the source.
+	The migration is resumable: the files which already exist in the target backend with the same checksum
+	are skipped, so an interrupted migration can be continued by running the command again. The migration
+	stops if a file exists in the target backend with different content.
+	`)
+
+	migrateExample = i18n.T(`# Preview the migration from the local backend to the backend named s3-prod.
+	kusion backend migrate --from default --to s3-prod --dry-run
+
+	# Migrate from the local backend to the backend named s3-prod.
+	kusion backend migrate --from default --to s3-prod
+`)
+)
+
+var ErrMigrationConflict = errors.New("the file exists in the target backend with different content")
+
+// MigrateFlags reflects the information that CLI is gathering via flags,
+// which will be converted into MigrateOptions.
+type MigrateFlags struct {
+	From   *string
+	To     *string
+	DryRun *bool
+
+	genericiooptions.IOStreams
+}
+
+// MigrateOptions defines the configuration parameters for the `kusion backend migrate` command.
+type MigrateOptions struct {
+	FromName string
+	ToName   string
+	From     backend.Backend
+	To       backend.Backend
+	DryRun   bool
+
+	genericiooptions.IOStreams
+}
+
+// migrateStats counts the migrated and skipped files of each kind.
+type migrateStats struct {
+	migrated map[string]int
+	skipped  map[string]int
+}
+
+const (
+	kindWorkspace = "workspace"
+	kindRelease   = "release"
+	kindGraph     = "graph"
+)
+
+// NewMigrateFlags returns a default MigrateFlags.
+func NewMigrateFlags(streams genericiooptions.IOStreams) *MigrateFlags {
+	from, to := "", ""
+	dryRun := false
+	return &MigrateFlags{
+		From:      &from,
+		To:        &to,
+		DryRun:    &dryRun,
+		IOStreams: streams,
+	}
+}
+
+// NewCmdMigrate creates the `kusion backend migrate` command.
+func NewCmdMigrate(streams genericiooptions.IOStreams) *cobra.Command {
+	flags := NewMigrateFlags(streams)
+
+	cmd := &cobra.Command{
+		Use:     "migrate",
+		Short:   migrateShort,
+		Long:    templates.LongDesc(migrateLong),
+		Example: templates.Examples(migrateExample),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			o, err := flags.ToOptions()
+			defer cmdutil.RecoverErr(&err)
+			cmdutil.CheckErr(err)
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run())
+
+			return
+		},
+	}
+
+	flags.AddFlags(cmd)
+
+	return cmd
+}
+
+// AddFlags registers flags for the CLI.
+func (f *MigrateFlags) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(f.From, "from", "", "", i18n.T("The name of the source backend"))
+	cmd.Flags().StringVarP(f.To, "to", "", "", i18n.T("The name of the target backend"))
+	cmd.Flags().BoolVarP(f.DryRun, "dry-run", "", false, i18n.T("Print the files to migrate without writing the target backend"))
+}
+
+// ToOptions converts from CLI inputs to runtime inputs.
+func (f *MigrateFlags) ToOptions() (*MigrateOptions, error) {
+	if *f.From == "" || *f.To == "" {
+		return nil, fmt.Errorf("both the source and target backends must be specified by --from and --to")
+	}
+	if *f.From == *f.To {
+		return nil, fmt.Errorf("the source and target backends are both %s", *f.From)
+	}
+
+	from, err := backend.NewBackend(*f.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := backend.NewBackend(*f.To)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MigrateOptions{
+		FromName:  *f.From,
+		ToName:    *f.To,
+		From:      from,
+		To:        to,
+		DryRun:    *f.DryRun,
+		IOStreams: f.IOStreams,
+	}, nil
+}
+
+// Validate verifies if MigrateOptions are valid and without conflicts.
+func (o *MigrateOptions) Validate(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmdutil.UsageErrorf(cmd, "Unexpected args: %v", args)
+	}
+
+	return nil
+}
+
+// Run executes the `kusion backend migrate` command.
+func (o *MigrateOptions) Run() error {
+	stats := &migrateStats{migrated: map[string]int{}, skipped: map[string]int{}}
+
+	if err := o.migrateWorkspaces(stats); err != nil {
+		return err
+	}
+
+	projects, err := o.From.ProjectStorage()
+	if err != nil {
+		return err
+	}
+	workspaces := make([]string, 0, len(projects))
+	for ws := range projects {
+		workspaces = append(workspaces, ws)
+	}
+	sort.Strings(workspaces)
+	for _, ws := range workspaces {
+		names := append([]string(nil), projects[ws]...)
+		sort.Strings(names)
+		for _, project := range names {
+			if err = o.migrateReleases(project, ws, stats); err != nil {
+				return err
+			}
+			if err = o.migrateGraph(project, ws, stats); err != nil {
+				return err
+			}
+		}
+	}
+
+	verb := "Migrated"
+	if o.DryRun {
+		verb = "Would migrate"
+	}
+	fmt.Fprintf(o.Out, "%s %d workspaces, %d releases and %d graphs from backend %s to %s, skipped %d files already migrated\n",
+		verb, stats.migrated[kindWorkspace], stats.migrated[kindRelease], stats.migrated[kindGraph], o.FromName, o.ToName,
+		stats.skipped[kindWorkspace]+stats.skipped[kindRelease]+stats.skipped[kindGraph])
+	return nil
+}
+
+// migrateWorkspaces copies all the workspaces and sets the current workspace of the target backend.
+func (o *MigrateOptions) migrateWorkspaces(stats *migrateStats) error {
+	src, err := o.From.WorkspaceStorage()
+	if err != nil {
+		return err
+	}
+	dst, err := o.To.WorkspaceStorage()
+	if err != nil {
+		return err
+	}
+	names, err := src.GetNames()
+	if err != nil {
+		return err
+	}
+	existing, err := dst.GetNames()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		item := fmt.Sprintf("workspace %s", name)
+		ws, err := src.Get(name)
+		if err != nil {
+			return fmt.Errorf("get %s from backend %s failed: %w", item, o.FromName, err)
+		}
+
+		exists := contains(existing, name)
+		if exists {
+			target, err := dst.Get(name)
+			if err != nil {
+				return fmt.Errorf("get %s from backend %s failed: %w", item, o.ToName, err)
+			}
+			same, err := sameChecksum(ws, target)
+			if err != nil {
+				return err
+			}
+			if same {
+				o.skip(item, kindWorkspace, stats)
+				continue
+			}
+			// The default workspace is created empty with the target backend, which gets overwritten.
+			if name != workspacestorages.DefaultWorkspace || !reflect.DeepEqual(target, &v1.Workspace{Name: name}) {
+				return fmt.Errorf("migrate %s failed: %w", item, ErrMigrationConflict)
+			}
+		}
+
+		if !o.DryRun {
+			if exists {
+				err = dst.Update(ws)
+			} else {
+				err = dst.Create(ws)
+			}
+			if err != nil {
+				return fmt.Errorf("write %s to backend %s failed: %w", item, o.ToName, err)
+			}
+			target, err := dst.Get(name)
+			if err = verify(item, ws, target, err); err != nil {
+				return err
+			}
+		}
+		o.migrate(item, kindWorkspace, stats)
+	}
+
+	current, err := src.GetCurrent()
+	if err != nil {
+		return err
+	}
+	if current != "" && !o.DryRun {
+		if err = dst.SetCurrent(current); err != nil {
+			return fmt.Errorf("set current workspace of backend %s failed: %w", o.ToName, err)
+		}
+	}
+	return nil
+}
+
+// migrateReleases copies the releases of the project and workspace in the order of the revisions, so that the
+// revision numbers and the latest revision are preserved.
+func (o *MigrateOptions) migrateReleases(project, workspace string, stats *migrateStats) error {
+	src, err := o.From.ReleaseStorage(project, workspace)
+	if err != nil {
+		return err
+	}
+	dst, err := o.To.ReleaseStorage(project, workspace)
+	if err != nil {
+		return err
+	}
+	existing := dst.GetRevisions()
+
+	revisions := src.GetRevisions()
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i] < revisions[j] })
+	for _, revision := range revisions {
+		item := fmt.Sprintf("release of project %s, workspace %s, revision %d", project, workspace, revision)
+		r, err := src.Get(revision)
+		if err != nil {
+			return fmt.Errorf("get %s from backend %s failed: %w", item, o.FromName, err)
+		}
+
+		if containsRevision(existing, revision) {
+			target, err := dst.Get(revision)
+			if err != nil {
+				return fmt.Errorf("get %s from backend %s failed: %w", item, o.ToName, err)
+			}
+			same, err := sameChecksum(r, target)
+			if err != nil {
+				return err
+			}
+			if !same {
+				return fmt.Errorf("migrate %s failed: %w", item, ErrMigrationConflict)
+			}
+			o.skip(item, kindRelease, stats)
+			continue
+		}
+
+		if !o.DryRun {
+			if err = dst.Create(r); err != nil {
+				return fmt.Errorf("write %s to backend %s failed: %w", item, o.ToName, err)
+			}
+			target, err := dst.Get(revision)
+			if err = verify(item, r, target, err); err != nil {
+				return err
+			}
+		}
+		o.migrate(item, kindRelease, stats)
+	}
+	return nil
+}
+
+// migrateGraph copies the resource graph of the project and workspace if it exists.
+func (o *MigrateOptions) migrateGraph(project, workspace string, stats *migrateStats) error {
+	src, err := o.From.GraphStorage(project, workspace)
+	if err != nil {
+		return err
+	}
+	if !src.CheckGraphStorageExistence() {
+		return nil
+	}
+	dst, err := o.To.GraphStorage(project, workspace)
+	if err != nil {
+		return err
+	}
+
+	item := fmt.Sprintf("graph of project %s, workspace %s", project, workspace)
+	g, err := src.Get()
+	if err != nil {
+		return fmt.Errorf("get %s from backend %s failed: %w", item, o.FromName, err)
+	}
+	if dst.CheckGraphStorageExistence() {
+		target, err := dst.Get()
+		if err != nil {
+			return fmt.Errorf("get %s from backend %s failed: %w", item, o.ToName, err)
+		}
+		same, err := sameChecksum(g, target)
+		if err != nil {
+			return err
+		}
+		if !same {
+			return fmt.Errorf("migrate %s failed: %w", item, ErrMigrationConflict)
+		}
+		o.skip(item, kindGraph, stats)
+		return nil
+	}
+
+	if !o.DryRun {
+		if err = dst.Create(g); err != nil {
+			return fmt.Errorf("write %s to backend %s failed: %w", item, o.ToName, err)
+		}
+		target, err := dst.Get()
+		if err = verify(item, g, target, err); err != nil {
+			return err
+		}
+	}
+	o.migrate(item, kindGraph, stats)
+	return nil
+}
+
+func (o *MigrateOptions) migrate(item, kind string, stats *migrateStats) {
+	stats.migrated[kind]++
+	if o.DryRun {
+		fmt.Fprintf(o.Out, "Would migrate %s\n", item)
+	} else {
+		fmt.Fprintf(o.Out, "Migrated %s\n", item)
+	}
+}
+
+func (o *MigrateOptions) skip(item, kind string, stats *migrateStats) {
+	stats.skipped[kind]++
+	fmt.Fprintf(o.Out, "Skipped %s, already migrated\n", item)
+}
+
+// verify compares the checksum of the file read back from the target backend with the source, where readErr
+// is the error of reading back.
+func verify(item string, source, target any, readErr error) error {
+	if err := readErr; err != nil {
+		return fmt.Errorf("read back %s failed: %w", item, err)
+	}
+	same, err := sameChecksum(source, target)
+	if err != nil {
+		return err
+	}
+	if !same {
+		return fmt.Errorf("checksum of %s mismatches after migration", item)
+	}
+	return nil
+}
+
+// sameChecksum compares the checksums of the json encoding of the two objects, which are independent of the
+// file format and the encryption of the backends.
+func sameChecksum(a, b any) (bool, error) {
+	sumA, err := checksum(a)
+	if err != nil {
+		return false, err
+	}
+	sumB, err := checksum(b)
+	if err != nil {
+		return false, err
+	}
+	return sumA == sumB, nil
+}
+
+func checksum(v any) (string, error) {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("json marshal for checksum failed: %w", err)
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func containsRevision(revisions []uint64, revision uint64) bool {
+	for _, r := range revisions {
+		if r == revision {
+			return true
+		}
+	}
+	return false
+}
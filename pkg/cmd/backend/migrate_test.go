@@ -0,0 +1,117 @@
+package backendcmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/backend/storages"
+)
+
+func newLocalBackend(t *testing.T) *storages.LocalStorage {
+	s, err := storages.NewLocalStorage(&v1.BackendLocalConfig{Path: t.TempDir()})
+	require.NoError(t, err)
+	return s
+}
+
+func prepareSourceBackend(t *testing.T, s *storages.LocalStorage) {
+	wsStorage, err := s.WorkspaceStorage()
+	require.NoError(t, err)
+	require.NoError(t, wsStorage.Update(&v1.Workspace{Name: "default", Context: map[string]any{"env": "default"}}))
+	require.NoError(t, wsStorage.Create(&v1.Workspace{Name: "dev", Context: map[string]any{"env": "dev"}}))
+	require.NoError(t, wsStorage.SetCurrent("dev"))
+
+	releaseStorage, err := s.ReleaseStorage("foo", "dev")
+	require.NoError(t, err)
+	for _, revision := range []uint64{1, 2, 3} {
+		require.NoError(t, releaseStorage.Create(&v1.Release{
+			Project:   "foo",
+			Workspace: "dev",
+			Revision:  revision,
+			Stack:     "dev",
+			Spec:      &v1.Spec{},
+			State:     &v1.State{},
+			Phase:     v1.ReleasePhaseSucceeded,
+		}))
+	}
+
+	graphStorage, err := s.GraphStorage("foo", "dev")
+	require.NoError(t, err)
+	require.NoError(t, graphStorage.Create(&v1.Graph{Project: "foo", Workspace: "dev", Resources: &v1.GraphResources{}}))
+}
+
+func TestMigrateOptions_Validate(t *testing.T) {
+	opts := &MigrateOptions{}
+	cmd := NewCmdMigrate(genericiooptions.IOStreams{})
+
+	assert.NoError(t, opts.Validate(cmd, []string{}))
+	assert.Error(t, opts.Validate(cmd, []string{"invalid-args"}))
+}
+
+func TestMigrateFlags_ToOptions(t *testing.T) {
+	flags := NewMigrateFlags(genericiooptions.IOStreams{})
+	_, err := flags.ToOptions()
+	assert.Error(t, err)
+
+	*flags.From, *flags.To = "dev", "dev"
+	_, err = flags.ToOptions()
+	assert.Error(t, err)
+}
+
+func TestMigrateOptions_Run(t *testing.T) {
+	from, to := newLocalBackend(t), newLocalBackend(t)
+	prepareSourceBackend(t, from)
+
+	out := &bytes.Buffer{}
+	o := &MigrateOptions{FromName: "local", ToName: "s3", From: from, To: to, DryRun: true}
+	o.Out = out
+	require.NoError(t, o.Run())
+	assert.Contains(t, out.String(), "Would migrate 2 workspaces, 3 releases and 1 graphs")
+	releaseStorage, err := to.ReleaseStorage("foo", "dev")
+	require.NoError(t, err)
+	assert.Empty(t, releaseStorage.GetRevisions())
+
+	// Migrate a part of the releases to simulate an interrupted migration.
+	src, err := from.ReleaseStorage("foo", "dev")
+	require.NoError(t, err)
+	r, err := src.Get(1)
+	require.NoError(t, err)
+	require.NoError(t, releaseStorage.Create(r))
+
+	out.Reset()
+	o.DryRun = false
+	require.NoError(t, o.Run())
+	assert.Contains(t, out.String(), "Migrated 2 workspaces, 2 releases and 1 graphs from backend local to s3, skipped 1 files")
+
+	releaseStorage, err = to.ReleaseStorage("foo", "dev")
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2, 3}, releaseStorage.GetRevisions())
+	assert.Equal(t, uint64(3), releaseStorage.GetLatestRevision())
+	assert.Equal(t, []uint64{1, 2, 3}, releaseStorage.GetStackBoundRevisions("dev"))
+	wsStorage, err := to.WorkspaceStorage()
+	require.NoError(t, err)
+	current, err := wsStorage.GetCurrent()
+	require.NoError(t, err)
+	assert.Equal(t, "dev", current)
+	ws, err := wsStorage.Get("default")
+	require.NoError(t, err)
+	assert.Equal(t, "default", ws.Context["env"])
+	graphStorage, err := to.GraphStorage("foo", "dev")
+	require.NoError(t, err)
+	assert.True(t, graphStorage.CheckGraphStorageExistence())
+
+	// Running again skips all the migrated files.
+	out.Reset()
+	require.NoError(t, o.Run())
+	assert.Contains(t, out.String(), "Migrated 0 workspaces, 0 releases and 0 graphs from backend local to s3, skipped 6 files")
+
+	// A file with different content in the target backend stops the migration.
+	wsStorage, err = to.WorkspaceStorage()
+	require.NoError(t, err)
+	require.NoError(t, wsStorage.Update(&v1.Workspace{Name: "dev", Context: map[string]any{"env": "prod"}}))
+	assert.ErrorIs(t, o.Run(), ErrMigrationConflict)
+}
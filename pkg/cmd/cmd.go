@@ -10,9 +10,11 @@ import (
 	"k8s.io/kubectl/pkg/util/templates"
 
 	"kusionstack.io/kusion/pkg/cmd/apply"
+	backendcmd "kusionstack.io/kusion/pkg/cmd/backend"
 	"kusionstack.io/kusion/pkg/cmd/config"
 	"kusionstack.io/kusion/pkg/cmd/destroy"
 	"kusionstack.io/kusion/pkg/cmd/generate"
+	importcmd "kusionstack.io/kusion/pkg/cmd/import"
 	cmdinit "kusionstack.io/kusion/pkg/cmd/init"
 	"kusionstack.io/kusion/pkg/cmd/mod"
 	"kusionstack.io/kusion/pkg/cmd/preview"
@@ -117,6 +119,7 @@ Find more information at: https://www.kusionstack.io`),
 			Message: "Configuration Management Commands:",
 			Commands: []*cobra.Command{
 				config.NewCmd(),
+				backendcmd.NewCmdBackend(o.IOStreams),
 				workspace.NewCmd(),
 				project.NewCmd(),
 				stack.NewCmd(),
@@ -129,6 +132,7 @@ Find more information at: https://www.kusionstack.io`),
 				preview.NewCmdPreview(o.UI, o.IOStreams),
 				apply.NewCmdApply(o.UI, o.IOStreams),
 				destroy.NewCmdDestroy(o.UI, o.IOStreams),
+				importcmd.NewCmdImport(o.UI, o.IOStreams),
 			},
 		},
 		{
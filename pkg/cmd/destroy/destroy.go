@@ -37,9 +37,11 @@ import (
 	"kusionstack.io/kusion/pkg/engine/release"
 	"kusionstack.io/kusion/pkg/engine/runtime/terraform"
 	"kusionstack.io/kusion/pkg/log"
+	"kusionstack.io/kusion/pkg/util/mask"
 	"kusionstack.io/kusion/pkg/util/pretty"
 	"kusionstack.io/kusion/pkg/util/signal"
 	"kusionstack.io/kusion/pkg/util/terminal"
+	"kusionstack.io/kusion/pkg/version"
 )
 
 var (
@@ -66,6 +68,7 @@ type DeleteFlags struct {
 	Yes      bool
 	Detail   bool
 	NoStyle  bool
+	Notes    string
 
 	UI *terminal.UI
 
@@ -79,6 +82,7 @@ type DestroyOptions struct {
 	Yes     bool
 	Detail  bool
 	NoStyle bool
+	Notes   string
 
 	UI *terminal.UI
 
@@ -126,6 +130,7 @@ func (flags *DeleteFlags) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVarP(&flags.Yes, "yes", "y", false, i18n.T("Automatically approve and perform the update after previewing it"))
 	cmd.Flags().BoolVarP(&flags.Detail, "detail", "d", false, i18n.T("Automatically show preview details after previewing it"))
 	cmd.Flags().BoolVarP(&flags.NoStyle, "no-style", "", false, i18n.T("no-style sets to RawOutput mode and disables all of styling"))
+	cmd.Flags().StringVarP(&flags.Notes, "notes", "", "", i18n.T("The change notes recorded in the release"))
 }
 
 // ToOptions converts from CLI inputs to runtime inputs.
@@ -141,6 +146,7 @@ func (flags *DeleteFlags) ToOptions() (*DestroyOptions, error) {
 		Detail:      flags.Detail,
 		Yes:         flags.Yes,
 		NoStyle:     flags.NoStyle,
+		Notes:       flags.Notes,
 		UI:          flags.UI,
 		IOStreams:   flags.IOStreams,
 	}
@@ -189,6 +195,7 @@ func (o *DestroyOptions) Run() (err error) {
 		pterm.Println(pterm.Green("No managed resources to destroy"))
 		return
 	}
+	rel.Provenance = release.NewProvenance(version.ReleaseVersion(), o.RefStack, rel.Spec, release.CurrentOperator(), o.Notes)
 	releaseCreated = true
 
 	errCh := make(chan error, 1)
@@ -291,6 +298,7 @@ func (o *DestroyOptions) run(rel *apiv1.Release, storage release.Storage) (err e
 	}
 
 	// update release phase to destroying
+	rel.Actions = changes.Actions()
 	rel.Phase = apiv1.ReleasePhaseDestroying
 	if err = release.UpdateDestroyRelease(storage, rel); err != nil {
 		return
@@ -319,12 +327,19 @@ func (o *DestroyOptions) preview(
 ) (*models.Changes, error) {
 	log.Info("Start compute preview changes ...")
 
+	// Mask the sensitive data in the changes shown following the rules of the workspace.
+	policy, err := mask.PolicyFromContext(planResources.Context)
+	if err != nil {
+		return nil, err
+	}
+	mask.SetDefault(policy)
+
 	// check and install terraform executable binary for
 	// resources with the type of Terraform.
 	tfInstaller := terraform.CLIInstaller{
 		Intent: planResources,
 	}
-	if err := tfInstaller.CheckAndInstall(); err != nil {
+	if err = tfInstaller.CheckAndInstall(); err != nil {
 		return nil, err
 	}
 
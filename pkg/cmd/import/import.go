@@ -0,0 +1,633 @@
+// Copyright 2024 KusionStack Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importcmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/liu-hm19/pterm"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	apiv1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	v1 "kusionstack.io/kusion/pkg/apis/status/v1"
+	"kusionstack.io/kusion/pkg/cmd/generate"
+	"kusionstack.io/kusion/pkg/cmd/meta"
+	cmdutil "kusionstack.io/kusion/pkg/cmd/util"
+	"kusionstack.io/kusion/pkg/engine"
+	"kusionstack.io/kusion/pkg/engine/operation/models"
+	"kusionstack.io/kusion/pkg/engine/release"
+	"kusionstack.io/kusion/pkg/engine/runtime"
+	runtimeinit "kusionstack.io/kusion/pkg/engine/runtime/init"
+	"kusionstack.io/kusion/pkg/engine/runtime/terraform/tfops"
+	"kusionstack.io/kusion/pkg/util/diff"
+	"kusionstack.io/kusion/pkg/util/i18n"
+	"kusionstack.io/kusion/pkg/util/pretty"
+	"kusionstack.io/kusion/pkg/util/terminal"
+)
+
+var (
+	importLong = i18n.T(`
+		Import the existing Kubernetes and cloud resources into the stack.
+
+		Adopt the resources already existing in the actual infrastructure as the resources defined in the stack,
+		so that they are managed by Kusion afterwards. The resources to import are specified by a mapping file
+		from the Kusion resource IDs to the Kubernetes or cloud resource IDs, discovered by the label selector in
+		a namespace, or selected interactively.
+
+		Kusion previews the resources to adopt with the differences between their live and defined attributes,
+		and records the adoption in a new release without modifying the actual infrastructure. The differences
+		take effect at the next apply.`)
+
+	importExample = i18n.T(`
+		# Import the resources in the mapping file, such as
+		#   v1:ConfigMap:default:foo: default/foo
+		#   hashicorp:aws:aws_s3_bucket:foo: foo-bucket
+		kusion import -f mapping.yaml
+
+		# Import the Kubernetes resources of the stack found by the label selector in the namespace
+		kusion import --discover -n default -l app=foo
+
+		# Select the resources to import interactively
+		kusion import
+
+		# Preview the resources to import without recording the adoption
+		kusion import -f mapping.yaml --dry-run
+
+		# Skip interactive approval of the preview before importing
+		kusion import -f mapping.yaml --yes`)
+)
+
+// ImportFlags directly reflect the information that CLI is gathering via flags. They will be converted to
+// ImportOptions, which reflect the runtime requirements for the command.
+//
+// This structure reduces the transformation to wiring and makes the logic itself easy to unit test.
+type ImportFlags struct {
+	MetaFlags *meta.MetaFlags
+
+	File      string
+	Discover  bool
+	Namespace string
+	Selector  string
+	SpecFile  string
+	Values    []string
+	Yes       bool
+	DryRun    bool
+	NoStyle   bool
+
+	UI *terminal.UI
+
+	genericiooptions.IOStreams
+}
+
+// ImportOptions defines flags and other configuration parameters for the `import` command.
+type ImportOptions struct {
+	*meta.MetaOptions
+
+	File      string
+	Discover  bool
+	Namespace string
+	Selector  string
+	SpecFile  string
+	Values    []string
+	Yes       bool
+	DryRun    bool
+	NoStyle   bool
+
+	// Mapping is the mapping from the Kusion resource IDs to the IDs in the actual infrastructure read from File.
+	Mapping map[string]string
+
+	UI *terminal.UI
+
+	genericiooptions.IOStreams
+}
+
+// importedResource is a resource to adopt, with its definition in the stack and its live attributes.
+type importedResource struct {
+	importID string
+	planned  *apiv1.Resource
+	live     *apiv1.Resource
+}
+
+// NewImportFlags returns a default ImportFlags
+func NewImportFlags(ui *terminal.UI, streams genericiooptions.IOStreams) *ImportFlags {
+	return &ImportFlags{
+		MetaFlags: meta.NewMetaFlags(),
+		UI:        ui,
+		IOStreams: streams,
+	}
+}
+
+// NewCmdImport creates the `import` command.
+func NewCmdImport(ui *terminal.UI, ioStreams genericiooptions.IOStreams) *cobra.Command {
+	flags := NewImportFlags(ui, ioStreams)
+
+	cmd := &cobra.Command{
+		Use:     "import",
+		Short:   "Import the existing Kubernetes and cloud resources into the stack",
+		Long:    templates.LongDesc(importLong),
+		Example: templates.Examples(importExample),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			o, err := flags.ToOptions()
+			defer cmdutil.RecoverErr(&err)
+			cmdutil.CheckErr(err)
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run())
+			return
+		},
+	}
+
+	flags.AddFlags(cmd)
+
+	return cmd
+}
+
+// AddFlags registers flags for a cli.
+func (f *ImportFlags) AddFlags(cmd *cobra.Command) {
+	// bind flag structs
+	f.MetaFlags.AddFlags(cmd)
+
+	cmd.Flags().StringVarP(&f.File, "file", "f", "", i18n.T("The mapping file from the Kusion resource IDs to the Kubernetes or cloud resource IDs to import"))
+	cmd.Flags().BoolVarP(&f.Discover, "discover", "", false, i18n.T("Discover the existing Kubernetes resources of the stack to import"))
+	cmd.Flags().StringVarP(&f.Namespace, "namespace", "n", "", i18n.T("The namespace to discover the resources in, all namespaces if not specified"))
+	cmd.Flags().StringVarP(&f.Selector, "selector", "l", "", i18n.T("The label selector to discover the resources by"))
+	cmd.Flags().StringVarP(&f.SpecFile, "spec-file", "", "", i18n.T("Specify the spec file path as input, and the spec file must be located in the working directory or its subdirectories"))
+	cmd.Flags().StringArrayVarP(&f.Values, "argument", "D", []string{}, i18n.T("Specify arguments on the command line"))
+	cmd.Flags().BoolVarP(&f.Yes, "yes", "y", false, i18n.T("Automatically approve and import the resources after previewing them"))
+	cmd.Flags().BoolVarP(&f.DryRun, "dry-run", "", false, i18n.T("Preview the resources to import without recording the adoption"))
+	cmd.Flags().BoolVarP(&f.NoStyle, "no-style", "", false, i18n.T("no-style sets to RawOutput mode and disables all of styling"))
+}
+
+// ToOptions converts from CLI inputs to runtime inputs.
+func (f *ImportFlags) ToOptions() (*ImportOptions, error) {
+	// Convert meta options
+	metaOptions, err := f.MetaFlags.ToOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	o := &ImportOptions{
+		MetaOptions: metaOptions,
+		File:        f.File,
+		Discover:    f.Discover,
+		Namespace:   f.Namespace,
+		Selector:    f.Selector,
+		SpecFile:    f.SpecFile,
+		Values:      f.Values,
+		Yes:         f.Yes,
+		DryRun:      f.DryRun,
+		NoStyle:     f.NoStyle,
+		UI:          f.UI,
+		IOStreams:   f.IOStreams,
+	}
+
+	return o, nil
+}
+
+// Validate verifies if ImportOptions are valid and without conflicts.
+func (o *ImportOptions) Validate(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmdutil.UsageErrorf(cmd, "Unexpected args: %v", args)
+	}
+
+	if o.File != "" && o.Discover {
+		return cmdutil.UsageErrorf(cmd, "--file and --discover can not be used together")
+	}
+	if !o.Discover && (o.Namespace != "" || o.Selector != "") {
+		return cmdutil.UsageErrorf(cmd, "--namespace and --selector can only be used with --discover")
+	}
+
+	for _, value := range o.Values {
+		if parts := strings.SplitN(value, "=", 2); len(parts) != 2 {
+			return cmdutil.UsageErrorf(cmd, "value %s is invalid format", value)
+		}
+	}
+
+	if o.File != "" {
+		mapping, err := readMapping(o.File)
+		if err != nil {
+			return err
+		}
+		o.Mapping = mapping
+	}
+
+	if o.SpecFile != "" {
+		absSF, _ := filepath.Abs(o.SpecFile)
+		fi, err := os.Stat(absSF)
+		if err != nil {
+			return fmt.Errorf("spec file not exist: %s", absSF)
+		}
+		if fi.IsDir() || !fi.Mode().IsRegular() {
+			return fmt.Errorf("spec file must be a regular file: %s", absSF)
+		}
+		absWD, _ := filepath.Abs(o.RefStack.Path)
+
+		// calculate the relative path between absWD and absSF,
+		// if absSF is not located in the directory or subdirectory specified by absWD,
+		// an error will be returned.
+		rel, err := filepath.Rel(absWD, absSF)
+		if err != nil {
+			return err
+		}
+		if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("the spec file must be located in the working directory or its subdirectories of the stack")
+		}
+	}
+
+	return nil
+}
+
+// Run executes the `import` command.
+func (o *ImportOptions) Run() error {
+	// set no style
+	if o.NoStyle {
+		pterm.DisableStyling()
+	}
+
+	// build parameters
+	parameters := make(map[string]string)
+	for _, value := range o.Values {
+		parts := strings.SplitN(value, "=", 2)
+		parameters[parts[0]] = parts[1]
+	}
+
+	// generate Spec
+	var spec *apiv1.Spec
+	var err error
+	if o.SpecFile != "" {
+		spec, err = generate.SpecFromFile(o.SpecFile)
+	} else {
+		spec, err = generate.GenerateSpecWithSpinner(o.RefProject, o.RefStack, o.RefWorkspace, parameters, o.UI, o.NoStyle)
+	}
+	if err != nil {
+		return err
+	}
+	if spec == nil || len(spec.Resources) == 0 {
+		fmt.Fprintln(o.Out, pretty.GreenBold("\nNo resource found in this stack."))
+		return nil
+	}
+
+	// get the resources already managed by the stack
+	releaseStorage, err := o.Backend.ReleaseStorage(o.RefProject.Name, o.RefWorkspace.Name)
+	if err != nil {
+		return err
+	}
+	priorState, err := release.GetLatestState(releaseStorage)
+	if err != nil {
+		return err
+	}
+	if priorState == nil {
+		priorState = &apiv1.State{}
+	}
+
+	runtimes, s := runtimeinit.Runtimes(*spec, *priorState)
+	if v1.IsErr(s) {
+		return fmt.Errorf("init runtimes failed: %s", s.Message())
+	}
+
+	// determine the resources to import
+	candidates := unmanagedResources(spec, priorState)
+	var mapping map[string]string
+	switch {
+	case o.Mapping != nil:
+		mapping = o.Mapping
+	case o.Discover:
+		mapping, err = o.discover(runtimes, candidates)
+	default:
+		mapping, err = o.selectResources(candidates)
+	}
+	if err != nil {
+		return err
+	}
+	if len(mapping) == 0 {
+		fmt.Fprintln(o.Out, "No resource to import")
+		return nil
+	}
+
+	imported, err := o.read(runtimes, spec, priorState, mapping)
+	if err != nil {
+		return err
+	}
+
+	// preview the resources to adopt with the differences of the attributes
+	if err = o.preview(imported); err != nil {
+		return err
+	}
+	if o.DryRun {
+		fmt.Fprintf(o.Out, "\nNOTE: Currently running in the --dry-run mode, the above resources are not imported\n")
+		return nil
+	}
+
+	if !o.Yes {
+		input, err := o.UI.InteractiveSelectPrinter.
+			WithFilter(false).
+			WithDefaultText(`Do you want to import these resources?`).
+			WithOptions([]string{"yes", "no"}).
+			WithDefaultOption("yes").
+			Show()
+		if err != nil {
+			return err
+		}
+		if input != "yes" {
+			fmt.Fprintln(o.Out, "Operation import canceled")
+			return nil
+		}
+	}
+
+	planned := make(apiv1.Resources, 0, len(imported))
+	live := make(apiv1.Resources, 0, len(imported))
+	for _, res := range imported {
+		planned = append(planned, *res.planned)
+		live = append(live, *res.live)
+	}
+	rel, err := release.CreateImportRelease(releaseStorage, o.RefProject.Name, o.RefStack.Name, o.RefWorkspace.Name, spec, planned, live)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.Out, "Imported %d resources into release %d\n", len(imported), rel.Revision)
+	return nil
+}
+
+// discover lists the existing Kubernetes resources of the candidates by the label selector in the namespace, and
+// returns the mapping of the ones defined in the stack.
+func (o *ImportOptions) discover(runtimes map[apiv1.Type]runtime.Runtime, candidates apiv1.Resources) (map[string]string, error) {
+	var resources apiv1.Resources
+	defined := make(map[string]bool)
+	for _, res := range candidates {
+		if res.Type == apiv1.Kubernetes {
+			resources = append(resources, res)
+			defined[res.ID] = true
+		}
+	}
+	if len(resources) == 0 {
+		return nil, nil
+	}
+
+	discoverer, ok := runtimes[apiv1.Kubernetes].(runtime.Discoverer)
+	if !ok {
+		return nil, fmt.Errorf("the %s runtime does not support discovering resources", apiv1.Kubernetes)
+	}
+	response := discoverer.Discover(context.Background(), &runtime.DiscoverRequest{
+		Resources:     resources,
+		Namespace:     o.Namespace,
+		LabelSelector: o.Selector,
+	})
+	if v1.IsErr(response.Status) {
+		return nil, fmt.Errorf("discover resources failed: %s", response.Status.Message())
+	}
+
+	var found []string
+	for _, res := range response.Resources {
+		if !defined[res.ID] {
+			fmt.Fprintf(o.Out, "Skipped %s, not defined in the stack\n", res.ID)
+			continue
+		}
+		found = append(found, res.ID)
+	}
+	if len(found) == 0 {
+		return nil, nil
+	}
+
+	// select among the discovered resources unless approved
+	if !o.Yes {
+		selected, err := o.UI.InteractiveMultiselectPrinter.
+			WithDefaultText("Select the discovered resources to import").
+			WithOptions(found).
+			WithDefaultOptions(found).
+			Show()
+		if err != nil {
+			return nil, err
+		}
+		found = selected
+	}
+	return kubernetesMapping(found), nil
+}
+
+// selectResources prompts to select the resources to import among the candidates, and to input the IDs of the
+// selected cloud resources.
+func (o *ImportOptions) selectResources(candidates apiv1.Resources) (map[string]string, error) {
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	if o.Yes {
+		return nil, fmt.Errorf("specify the resources to import by --file or --discover when --yes is set")
+	}
+
+	ids := make([]string, 0, len(candidates))
+	types := make(map[string]apiv1.Type, len(candidates))
+	for _, res := range candidates {
+		ids = append(ids, res.ID)
+		types[res.ID] = res.Type
+	}
+	selected, err := o.UI.InteractiveMultiselectPrinter.
+		WithDefaultText("Select the resources to import").
+		WithOptions(ids).
+		Show()
+	if err != nil {
+		return nil, err
+	}
+
+	mapping := make(map[string]string, len(selected))
+	for _, id := range selected {
+		if types[id] == apiv1.Kubernetes {
+			mapping[id] = kubernetesImportID(id)
+			continue
+		}
+		importID, err := o.UI.InteractiveTextInputPrinter.
+			WithDefaultText(fmt.Sprintf("Input the cloud resource ID of %s", id)).
+			Show()
+		if err != nil {
+			return nil, err
+		}
+		if importID = strings.TrimSpace(importID); importID == "" {
+			return nil, fmt.Errorf("empty cloud resource ID of %s", id)
+		}
+		mapping[id] = importID
+	}
+	return mapping, nil
+}
+
+// read reads the live resources to import in the order of the resources in the spec.
+func (o *ImportOptions) read(
+	runtimes map[apiv1.Type]runtime.Runtime,
+	spec *apiv1.Spec,
+	priorState *apiv1.State,
+	mapping map[string]string,
+) ([]importedResource, error) {
+	defined := make(map[string]bool, len(spec.Resources))
+	for _, res := range spec.Resources {
+		defined[res.ID] = true
+	}
+	for id := range mapping {
+		if !defined[id] {
+			return nil, fmt.Errorf("resource %s is not defined in the stack", id)
+		}
+	}
+	managed := make(map[string]bool, len(priorState.Resources))
+	for _, res := range priorState.Resources {
+		managed[res.ID] = true
+	}
+
+	var imported []importedResource
+	for i := range spec.Resources {
+		importID, ok := mapping[spec.Resources[i].ID]
+		if !ok {
+			continue
+		}
+		planned, err := spec.Resources[i].DeepCopy()
+		if err != nil {
+			return nil, err
+		}
+		if managed[planned.ID] {
+			return nil, fmt.Errorf("resource %s is already managed by the stack", planned.ID)
+		}
+		request, err := importRequest(planned, importID)
+		if err != nil {
+			return nil, err
+		}
+
+		rt, ok := runtimes[planned.Type]
+		if !ok {
+			return nil, fmt.Errorf("no runtime found for resource %s of type %s", planned.ID, planned.Type)
+		}
+		response := rt.Import(context.Background(), &runtime.ImportRequest{
+			PlanResource: request,
+			Stack:        o.RefStack,
+		})
+		if v1.IsErr(response.Status) {
+			return nil, fmt.Errorf("import resource %s failed: %s", planned.ID, response.Status.Message())
+		}
+		if response.Resource == nil {
+			return nil, fmt.Errorf("resource %s with ID %s not found", planned.ID, importID)
+		}
+
+		live, err := response.Resource.DeepCopy()
+		if err != nil {
+			return nil, err
+		}
+		delete(live.Extensions, tfops.ImportIDKey)
+		delete(planned.Extensions, tfops.ImportIDKey)
+		imported = append(imported, importedResource{importID: importID, planned: planned, live: live})
+	}
+	return imported, nil
+}
+
+// preview prints the differences between the live and defined attributes of the resources to import.
+func (o *ImportOptions) preview(imported []importedResource) error {
+	for _, res := range imported {
+		step := &models.ChangeStep{
+			ID:     res.planned.ID,
+			Action: models.UnChanged,
+			From:   res.live.Attributes,
+			To:     res.planned.Attributes,
+		}
+		report, err := diff.ToReport(step.From, step.To)
+		if err != nil {
+			return err
+		}
+		if len(report.Diffs) != 0 {
+			step.Action = models.Update
+		}
+		out, err := step.Diff(o.NoStyle)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "Import %s from %s\n%s\n", res.planned.ID, res.importID, out)
+	}
+	return nil
+}
+
+// importRequest returns the resource to read the live one by the import ID. The Kubernetes resources are read
+// by their IDs, so the import ID must match the namespace and name in the ID, and the cloud resources are read
+// by the import ID set in the extensions.
+func importRequest(planned *apiv1.Resource, importID string) (*apiv1.Resource, error) {
+	request, err := planned.DeepCopy()
+	if err != nil {
+		return nil, err
+	}
+	if planned.Type == apiv1.Kubernetes {
+		if expected := kubernetesImportID(planned.ID); importID != "" && importID != expected {
+			return nil, fmt.Errorf("import ID %s of resource %s must be %s, importing a Kubernetes resource under another name is not supported",
+				importID, planned.ID, expected)
+		}
+		return request, nil
+	}
+
+	if importID == "" {
+		return nil, fmt.Errorf("empty import ID of resource %s", planned.ID)
+	}
+	if request.Extensions == nil {
+		request.Extensions = make(map[string]any)
+	}
+	request.Extensions[tfops.ImportIDKey] = importID
+	return request, nil
+}
+
+// unmanagedResources returns the resources in the spec which are not in the state.
+func unmanagedResources(spec *apiv1.Spec, state *apiv1.State) apiv1.Resources {
+	managed := make(map[string]bool, len(state.Resources))
+	for _, res := range state.Resources {
+		managed[res.ID] = true
+	}
+	var resources apiv1.Resources
+	for _, res := range spec.Resources {
+		if !managed[res.ID] {
+			resources = append(resources, res)
+		}
+	}
+	return resources
+}
+
+// kubernetesImportID returns the namespace/name, or the name of the cluster-scoped resource, in the Kubernetes
+// resource ID formatted as apiVersion:kind:namespace:name.
+func kubernetesImportID(id string) string {
+	parts := strings.Split(id, engine.Separator)
+	if len(parts) == 4 {
+		return parts[2] + "/" + parts[3]
+	}
+	return parts[len(parts)-1]
+}
+
+// kubernetesMapping returns the mapping of the Kubernetes resources to import by their IDs.
+func kubernetesMapping(ids []string) map[string]string {
+	mapping := make(map[string]string, len(ids))
+	for _, id := range ids {
+		mapping[id] = kubernetesImportID(id)
+	}
+	return mapping
+}
+
+// readMapping reads the mapping file from the Kusion resource IDs to the IDs in the actual infrastructure.
+func readMapping(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read mapping file %s failed: %w", path, err)
+	}
+	mapping := make(map[string]string)
+	if err = yaml.Unmarshal(content, &mapping); err != nil {
+		return nil, fmt.Errorf("unmarshal mapping file %s failed: %w", path, err)
+	}
+	if len(mapping) == 0 {
+		return nil, fmt.Errorf("no resource found in mapping file %s", path)
+	}
+	return mapping, nil
+}
@@ -0,0 +1,192 @@
+// Copyright 2024 KusionStack Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importcmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	apiv1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/cmd/meta"
+	"kusionstack.io/kusion/pkg/engine/runtime"
+	"kusionstack.io/kusion/pkg/engine/runtime/terraform/tfops"
+	"kusionstack.io/kusion/pkg/util/terminal"
+)
+
+var _ runtime.Runtime = (*fakerRuntime)(nil)
+
+// fakerRuntime returns the live resources by their IDs for import.
+type fakerRuntime struct {
+	live map[string]*apiv1.Resource
+}
+
+func (f *fakerRuntime) Import(_ context.Context, request *runtime.ImportRequest) *runtime.ImportResponse {
+	res, ok := f.live[request.PlanResource.ID]
+	if !ok {
+		return &runtime.ImportResponse{}
+	}
+	res.Extensions = request.PlanResource.Extensions
+	return &runtime.ImportResponse{Resource: res}
+}
+
+func (f *fakerRuntime) Apply(_ context.Context, _ *runtime.ApplyRequest) *runtime.ApplyResponse {
+	return nil
+}
+
+func (f *fakerRuntime) Read(_ context.Context, _ *runtime.ReadRequest) *runtime.ReadResponse {
+	return nil
+}
+
+func (f *fakerRuntime) Delete(_ context.Context, _ *runtime.DeleteRequest) *runtime.DeleteResponse {
+	return nil
+}
+
+func (f *fakerRuntime) Watch(_ context.Context, _ *runtime.WatchRequest) *runtime.WatchResponse {
+	return nil
+}
+
+var (
+	configMap = apiv1.Resource{
+		ID:         "v1:ConfigMap:default:foo",
+		Type:       apiv1.Kubernetes,
+		Attributes: map[string]interface{}{"data": map[string]interface{}{"foo": "bar"}},
+	}
+	bucket = apiv1.Resource{
+		ID:         "hashicorp:aws:aws_s3_bucket:foo",
+		Type:       apiv1.Terraform,
+		Attributes: map[string]interface{}{"bucket": "foo"},
+	}
+)
+
+func newImportOptions(out *bytes.Buffer) *ImportOptions {
+	return &ImportOptions{
+		MetaOptions: &meta.MetaOptions{
+			RefProject:   &apiv1.Project{Name: "fake-proj"},
+			RefStack:     &apiv1.Stack{Name: "fake-stack"},
+			RefWorkspace: &apiv1.Workspace{Name: "fake-workspace"},
+		},
+		NoStyle:   true,
+		UI:        terminal.DefaultUI(),
+		IOStreams: genericiooptions.IOStreams{Out: out},
+	}
+}
+
+func TestImportOptions_Validate(t *testing.T) {
+	mappingFile := filepath.Join(t.TempDir(), "mapping.yaml")
+	require.NoError(t, os.WriteFile(mappingFile, []byte("v1:ConfigMap:default:foo: default/foo\n"), 0o644))
+	cmd := NewCmdImport(terminal.DefaultUI(), genericiooptions.IOStreams{})
+
+	o := newImportOptions(&bytes.Buffer{})
+	assert.Error(t, o.Validate(cmd, []string{"invalid-args"}))
+
+	o.File, o.Discover = mappingFile, true
+	assert.Error(t, o.Validate(cmd, []string{}))
+
+	o.File, o.Discover, o.Selector = "", false, "app=foo"
+	assert.Error(t, o.Validate(cmd, []string{}))
+
+	o.File, o.Selector = mappingFile, ""
+	assert.NoError(t, o.Validate(cmd, []string{}))
+	assert.Equal(t, map[string]string{configMap.ID: "default/foo"}, o.Mapping)
+
+	o.Values = []string{"foo"}
+	assert.Error(t, o.Validate(cmd, []string{}))
+	o.Values = []string{"foo=bar"}
+	assert.NoError(t, o.Validate(cmd, []string{}))
+
+	o.SpecFile = "not-exist.yaml"
+	assert.Error(t, o.Validate(cmd, []string{}))
+
+	// The spec file must be located in the directory of the stack.
+	o.RefStack.Path = t.TempDir()
+	specFile := filepath.Join(o.RefStack.Path, "spec.yaml")
+	require.NoError(t, os.WriteFile(specFile, []byte("resources: []\n"), 0o644))
+	o.SpecFile = specFile
+	assert.NoError(t, o.Validate(cmd, []string{}))
+	o.SpecFile = mappingFile
+	assert.Error(t, o.Validate(cmd, []string{}))
+}
+
+func TestImportOptions_read(t *testing.T) {
+	liveConfigMap := configMap
+	liveConfigMap.Attributes = map[string]interface{}{"data": map[string]interface{}{"foo": "baz"}}
+	liveBucket := bucket
+	runtimes := map[apiv1.Type]runtime.Runtime{
+		apiv1.Kubernetes: &fakerRuntime{live: map[string]*apiv1.Resource{configMap.ID: &liveConfigMap}},
+		apiv1.Terraform:  &fakerRuntime{live: map[string]*apiv1.Resource{bucket.ID: &liveBucket}},
+	}
+	spec := &apiv1.Spec{Resources: apiv1.Resources{configMap, bucket}}
+
+	out := &bytes.Buffer{}
+	o := newImportOptions(out)
+	imported, err := o.read(runtimes, spec, &apiv1.State{}, map[string]string{bucket.ID: "foo-bucket", configMap.ID: "default/foo"})
+	require.NoError(t, err)
+	require.Len(t, imported, 2)
+	assert.Equal(t, configMap.ID, imported[0].planned.ID)
+	assert.Equal(t, "foo-bucket", imported[1].importID)
+	assert.NotContains(t, imported[1].live.Extensions, tfops.ImportIDKey)
+	assert.NotContains(t, imported[1].planned.Extensions, tfops.ImportIDKey)
+
+	require.NoError(t, o.preview(imported))
+	assert.Contains(t, out.String(), "Import v1:ConfigMap:default:foo from default/foo")
+	assert.Contains(t, out.String(), "Plan: Update")
+	assert.Contains(t, out.String(), "Plan: UnChanged")
+
+	_, err = o.read(runtimes, spec, &apiv1.State{}, map[string]string{"v1:Secret:default:foo": "default/foo"})
+	assert.ErrorContains(t, err, "not defined in the stack")
+
+	_, err = o.read(runtimes, spec, &apiv1.State{Resources: apiv1.Resources{configMap}}, map[string]string{configMap.ID: ""})
+	assert.ErrorContains(t, err, "already managed")
+
+	_, err = o.read(runtimes, spec, &apiv1.State{}, map[string]string{configMap.ID: "default/bar"})
+	assert.ErrorContains(t, err, "must be default/foo")
+
+	delete(runtimes[apiv1.Terraform].(*fakerRuntime).live, bucket.ID)
+	_, err = o.read(runtimes, spec, &apiv1.State{}, map[string]string{bucket.ID: "foo-bucket"})
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestImportRequest(t *testing.T) {
+	request, err := importRequest(&bucket, "foo-bucket")
+	require.NoError(t, err)
+	assert.Equal(t, "foo-bucket", request.Extensions[tfops.ImportIDKey])
+	assert.Nil(t, bucket.Extensions)
+
+	_, err = importRequest(&bucket, "")
+	assert.Error(t, err)
+
+	request, err = importRequest(&configMap, "")
+	require.NoError(t, err)
+	assert.Equal(t, configMap.ID, request.ID)
+}
+
+func TestKubernetesImportID(t *testing.T) {
+	assert.Equal(t, "default/foo", kubernetesImportID("v1:ConfigMap:default:foo"))
+	assert.Equal(t, "foo", kubernetesImportID("v1:Namespace:foo"))
+	assert.Equal(t, "bar", kubernetesImportID("rbac.authorization.k8s.io/v1:ClusterRole:bar"))
+}
+
+func TestUnmanagedResources(t *testing.T) {
+	spec := &apiv1.Spec{Resources: apiv1.Resources{configMap, bucket}}
+	state := &apiv1.State{Resources: apiv1.Resources{configMap}}
+	assert.Equal(t, apiv1.Resources{bucket}, unmanagedResources(spec, state))
+}
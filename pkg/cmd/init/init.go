@@ -9,11 +9,16 @@ import (
 
 func NewCmd() *cobra.Command {
 	var (
-		short = i18n.T(`Initialize the scaffolding for a demo project`)
+		short = i18n.T(`Initialize the scaffolding for a demo project or a project from a template`)
 
 		long = i18n.T(`
 		This command initializes the scaffolding for a demo project with the name of the current directory to help users quickly get started.
 
+		A project can also be initialized from a template, which is either <registry>/<template> referring to a template
+		of the template registries configured by "kusion config set templates.<registry>.source", or the source of a
+		single template, i.e. a git repository, an OCI artifact or a local directory. The template parameters are
+		specified by --set, and the ones not specified are prompted for unless --no-prompt is set.
+
 		Note that target directory needs to be an empty directory.`)
 
 		example = i18n.T(`
@@ -22,7 +27,17 @@ func NewCmd() *cobra.Command {
 		kusion init
 
 		# Initialize the demo project in a different target directory
-		kusion init --target projects/my-demo-project`)
+		kusion init --target projects/my-demo-project
+
+		# List the templates of the configured template registries
+		kusion config set templates.golden.source https://github.com/my-org/kusion-templates.git
+		kusion init --list-templates
+
+		# Initialize a project from a template of the registry with the template parameters
+		kusion init --template golden/web-service --set image=nginx:1.25 --set port=80
+
+		# Initialize a project from a specified version of a template in an OCI repository
+		kusion init --template oci://ghcr.io/my-org/templates/web-service --template-version 1.0.0`)
 	)
 
 	o := NewOptions()
@@ -44,6 +59,16 @@ func NewCmd() *cobra.Command {
 
 	cmd.Flags().StringVarP(&o.ProjectDir, "target", "t", "",
 		i18n.T("specify the target directory"))
+	cmd.Flags().StringVarP(&o.Template, "template", "", "",
+		i18n.T("specify the template, either <registry>/<template> or the source of a template"))
+	cmd.Flags().StringVarP(&o.TemplateVersion, "template-version", "", "",
+		i18n.T("specify the version of the template, which overrides the version of the template registry"))
+	cmd.Flags().StringArrayVarP(&o.Set, "set", "", nil,
+		i18n.T("specify the value of a template parameter in the format of key=value"))
+	cmd.Flags().BoolVarP(&o.NoPrompt, "no-prompt", "", false,
+		i18n.T("use the default values of the template parameters not specified instead of prompting for them"))
+	cmd.Flags().BoolVarP(&o.ListTemplates, "list-templates", "", false,
+		i18n.T("list the built-in template and the templates of the configured template registries"))
 
 	return cmd
 }
@@ -6,22 +6,32 @@ import (
 	"path/filepath"
 
 	"kusionstack.io/kusion/pkg/cmd/init/util"
+	"kusionstack.io/kusion/pkg/config"
 	"kusionstack.io/kusion/pkg/scaffold"
+	"kusionstack.io/kusion/pkg/util/terminal"
 )
 
 var ErrNotEmptyArgs = errors.New("no args accepted")
 
 type Options struct {
-	Name string
+	Name   string
+	Values map[string]string
+	UI     *terminal.UI
 	Flags
 }
 
 type Flags struct {
-	ProjectDir string
+	ProjectDir      string
+	Template        string
+	TemplateVersion string
+	Set             []string
+	NoPrompt        bool
+	ListTemplates   bool
 }
 
 func NewOptions() *Options {
 	return &Options{
+		UI: terminal.DefaultUI(),
 		Flags: Flags{
 			ProjectDir: "",
 		},
@@ -51,10 +61,19 @@ func (o *Options) Complete(args []string) error {
 		o.Name = filepath.Base(o.ProjectDir)
 	}
 
+	if o.Values, err = scaffold.ParseValues(o.Set); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func (o *Options) Validate() error {
+	// Listing the templates does not initialize any project.
+	if o.ListTemplates {
+		return nil
+	}
+
 	if err := util.ValidateProjectDir(o.ProjectDir); err != nil {
 		return err
 	}
@@ -67,11 +86,62 @@ func (o *Options) Validate() error {
 }
 
 func (o *Options) Run() error {
-	if err := scaffold.GenDemoProject(o.ProjectDir, o.Name); err != nil {
+	if o.ListTemplates {
+		return o.listTemplates()
+	}
+
+	if o.Template == "" || o.Template == scaffold.QuickstartTemplate {
+		if err := scaffold.GenDemoProject(o.ProjectDir, o.Name); err != nil {
+			return err
+		}
+
+		fmt.Printf("Initiated demo project '%s' successfully\n", o.Name)
+
+		return nil
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+	var prompt scaffold.PromptFunc
+	if !o.NoPrompt {
+		prompt = scaffold.TextInputPrompt(o.UI)
+	}
+	if err = scaffold.GenTemplateProject(o.Template, o.TemplateVersion, cfg.Templates, o.ProjectDir, o.Name, o.Values, prompt); err != nil {
+		return err
+	}
+
+	fmt.Printf("Initiated project '%s' from template '%s' successfully\n", o.Name, o.Template)
+
+	return nil
+}
+
+// listTemplates prints the built-in template and the templates of the configured registries.
+func (o *Options) listTemplates() error {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+	templates, err := scaffold.ListRegistryTemplates(cfg.Templates)
+	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Initiated demo project '%s' successfully\n", o.Name)
+	fmt.Printf("%-40s %-12s %s\n", "Template", "Version", "Description")
+	for _, t := range templates {
+		name, version := t.Name, t.Version
+		if t.Registry != "" {
+			name = t.Registry + "/" + t.Name
+		}
+		if version == "" {
+			version = t.RegistryVersion
+		}
+		if version == "" {
+			version = "-"
+		}
+		fmt.Printf("%-40s %-12s %s\n", name, version, t.Description)
+	}
 
 	return nil
 }
@@ -6,7 +6,10 @@ import (
 
 	"github.com/bytedance/mockey"
 	"github.com/stretchr/testify/assert"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
 	"kusionstack.io/kusion/pkg/cmd/init/util"
+	"kusionstack.io/kusion/pkg/config"
 	"kusionstack.io/kusion/pkg/scaffold"
 )
 
@@ -48,6 +51,21 @@ func TestOptions_Complete(t *testing.T) {
 			assert.Equal(t, "/dir/to/my-project", opts.ProjectDir)
 		})
 	})
+
+	t.Run("failed to parse template parameter values", func(t *testing.T) {
+		mockey.PatchConvey("mock util.GetDirAndName", t, func() {
+			mockey.Mock(util.GetDirAndName).To(func() (dir, name string, err error) {
+				return "/dir/to/quickstart", "quickstart", nil
+			}).Build()
+
+			opts := NewOptions()
+			opts.Flags.Set = []string{"image"}
+			args := []string{}
+
+			err := opts.Complete(args)
+			assert.ErrorContains(t, err, "key=value")
+		})
+	})
 }
 
 func TestOptions_Validate(t *testing.T) {
@@ -74,6 +92,18 @@ func TestOptions_Validate(t *testing.T) {
 		})
 	})
 
+	t.Run("skip validating when listing templates", func(t *testing.T) {
+		mockey.PatchConvey("mock util.ValidateProjectDir", t, func() {
+			mockey.Mock(util.ValidateProjectDir).
+				Return(errors.New("failed to validate project directory")).Build()
+
+			opts := NewOptions()
+			opts.Flags.ListTemplates = true
+			err := opts.Validate()
+			assert.Nil(t, err)
+		})
+	})
+
 	t.Run("successfully validate the options", func(t *testing.T) {
 		mockey.PatchConvey("mock util.ValidateProjectDir and util.ValidateProjectName", t, func() {
 			mockey.Mock(util.ValidateProjectDir).Return(nil).Build()
@@ -107,4 +137,30 @@ func TestOptions_Run(t *testing.T) {
 			assert.Nil(t, err)
 		})
 	})
+
+	t.Run("successfully initiate the project from template", func(t *testing.T) {
+		mockey.PatchConvey("mock config.GetConfig and scaffold.GenTemplateProject", t, func() {
+			mockey.Mock(config.GetConfig).Return(&v1.Config{}, nil).Build()
+			mockey.Mock(scaffold.GenTemplateProject).Return(nil).Build()
+
+			opts := NewOptions()
+			opts.Flags.Template = "golden/web-service"
+			opts.Flags.NoPrompt = true
+			err := opts.Run()
+			assert.Nil(t, err)
+		})
+	})
+
+	t.Run("failed to initiate the project from template", func(t *testing.T) {
+		mockey.PatchConvey("mock config.GetConfig and scaffold.GenTemplateProject", t, func() {
+			mockey.Mock(config.GetConfig).Return(&v1.Config{}, nil).Build()
+			mockey.Mock(scaffold.GenTemplateProject).
+				Return(errors.New("template golden/web-service not found")).Build()
+
+			opts := NewOptions()
+			opts.Flags.Template = "golden/web-service"
+			err := opts.Run()
+			assert.ErrorContains(t, err, "not found")
+		})
+	})
 }
@@ -31,6 +31,9 @@ func NewCmdMod(streams genericiooptions.IOStreams) *cobra.Command {
 	cmd.AddCommand(NewCmdAdd(streams))
 	cmd.AddCommand(NewCmdLogin(streams))
 	cmd.AddCommand(NewCmdPull(streams))
+	cmd.AddCommand(NewCmdTest(streams))
+	cmd.AddCommand(NewCmdOutdated(streams))
+	cmd.AddCommand(NewCmdUpgrade(streams))
 
 	return cmd
 }
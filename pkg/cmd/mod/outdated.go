@@ -0,0 +1,339 @@
+package mod
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+
+	orderedmap "github.com/elliotchance/orderedmap/v2"
+	"github.com/liu-hm19/pterm"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/kubectl/pkg/util/templates"
+	"kcl-lang.io/kpm/pkg/client"
+	"kcl-lang.io/kpm/pkg/downloader"
+	pkg "kcl-lang.io/kpm/pkg/package"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/backend"
+	"kusionstack.io/kusion/pkg/cmd/meta"
+	"kusionstack.io/kusion/pkg/cmd/util"
+	"kusionstack.io/kusion/pkg/oci"
+	ociclient "kusionstack.io/kusion/pkg/oci/client"
+	"kusionstack.io/kusion/pkg/project"
+	"kusionstack.io/kusion/pkg/util/i18n"
+)
+
+var outdatedExample = i18n.T(`# Show the outdated modules of the current workspace and the current stack
+  kusion mod outdated
+
+  # Show the outdated modules of a specified workspace and all the projects in the work directory
+  kusion mod outdated --workspace=dev --all
+
+  # Take the prerelease versions into account
+  kusion mod outdated --prerelease
+`)
+var outdatedShort = i18n.T("Show the modules with newer versions in the OCI registry")
+
+const (
+	moduleStatusUpToDate = "up-to-date"
+	moduleStatusOutdated = "outdated"
+	moduleStatusUnknown  = "unknown"
+)
+
+// ModuleVersionFlags are the flags shared by `mod outdated` and `mod upgrade` to locate the modules.
+type ModuleVersionFlags struct {
+	meta.MetaFlags
+	// All indicates to check the stacks of all the projects in the work directory, instead of the current stack.
+	All bool
+	// Prerelease indicates to take the prerelease versions into account.
+	Prerelease bool
+}
+
+// AddFlags registers flags for a cli.
+func (f *ModuleVersionFlags) AddFlags(cmd *cobra.Command) {
+	f.MetaFlags.AddFlags(cmd)
+	cmd.Flags().BoolVar(&f.All, "all", false, i18n.T("Check the stacks of all the projects found in the work directory."))
+	cmd.Flags().BoolVar(&f.Prerelease, "prerelease", false, i18n.T("Take the prerelease versions into account."))
+}
+
+// toModuleVersionOptions parses the workspace, and the stacks whose kcl.mod files are checked.
+func (f *ModuleVersionFlags) toModuleVersionOptions(io genericiooptions.IOStreams) (*ModuleVersionOptions, error) {
+	storageBackend, err := f.ParseBackend()
+	if err != nil {
+		return nil, err
+	}
+	workspace, err := f.ParseWorkspace(storageBackend)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := ""
+	if f.WorkDir != nil {
+		dir = *f.WorkDir
+	}
+	if dir == "" {
+		if dir, err = os.Getwd(); err != nil {
+			return nil, err
+		}
+	}
+
+	var stacks []*v1.Stack
+	if f.All {
+		projects, err := project.FindAllProjectsFrom(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range projects {
+			stacks = append(stacks, p.Stacks...)
+		}
+	} else if project.IsStack(dir) {
+		_, stack, err := project.DetectProjectAndStackFrom(dir)
+		if err != nil {
+			return nil, err
+		}
+		stacks = append(stacks, stack)
+	}
+
+	return &ModuleVersionOptions{
+		Workspace:      workspace,
+		StorageBackend: storageBackend,
+		Stacks:         stacks,
+		Prerelease:     f.Prerelease,
+		Tags:           ociclient.NewClient(),
+		IO:             io,
+	}, nil
+}
+
+// TagLister lists the tags of the OCI repository.
+type TagLister interface {
+	ListTags(ctx context.Context, ociURL string) ([]string, error)
+}
+
+// ModuleVersionOptions are the options shared by `mod outdated` and `mod upgrade`.
+type ModuleVersionOptions struct {
+	// Workspace referenced the workspace for this CLI invocation.
+	Workspace *v1.Workspace
+	// StorageBackend referenced the target storage backend for this CLI invocation.
+	StorageBackend backend.Backend
+	// Stacks referenced the stacks whose kcl.mod files are checked.
+	Stacks []*v1.Stack
+	// Prerelease indicates to take the prerelease versions into account.
+	Prerelease bool
+	// Tags lists the tags of the module repositories.
+	Tags TagLister
+	// IOStreams referenced the target IOStreams for this CLI invocation.
+	IO genericiooptions.IOStreams
+}
+
+// moduleUsage is a reference to a module with a specified version, either in the workspace or in the kcl.mod
+// of a stack.
+type moduleUsage struct {
+	// Name is the name of the module.
+	Name string
+	// URL is the OCI repository of the module, e.g. oci://ghcr.io/kusionstack/mysql.
+	URL string
+	// Version is the version of the module referenced.
+	Version string
+	// Stack is the stack whose kcl.mod references the module, or nil if it's referenced by the workspace.
+	Stack *v1.Stack
+	// Latest is the latest version in the OCI repository, or empty if unknown.
+	Latest string
+}
+
+// location returns where the module is referenced.
+func (u *moduleUsage) location(workspace *v1.Workspace) string {
+	if u.Stack != nil {
+		return u.Stack.Path
+	}
+	return "workspace " + workspace.Name
+}
+
+// status returns whether the module is outdated.
+func (u *moduleUsage) status() string {
+	switch {
+	case u.Latest == "":
+		return moduleStatusUnknown
+	case ociclient.IsNewerVersion(u.Latest, u.Version):
+		return moduleStatusOutdated
+	default:
+		return moduleStatusUpToDate
+	}
+}
+
+// collectModuleUsages returns the OCI modules referenced by the workspace and the kcl.mod of the stacks,
+// where the modules with local paths are skipped.
+func (o *ModuleVersionOptions) collectModuleUsages() ([]*moduleUsage, error) {
+	var usages []*moduleUsage
+	if o.Workspace != nil {
+		usages = append(usages, workspaceModuleUsages(o.Workspace)...)
+	}
+	for _, stack := range o.Stacks {
+		kclPkg, err := loadStackPkg(stack)
+		if err != nil {
+			return nil, err
+		}
+		usages = append(usages, stackModuleUsages(stack, kclPkg.ModFile.Dependencies.Deps)...)
+	}
+	return usages, nil
+}
+
+// resolveLatest fills the latest versions of the modules, where the tags of each repository are listed once.
+// The latest version is left empty if the tags of the repository are not available.
+func (o *ModuleVersionOptions) resolveLatest(ctx context.Context, usages []*moduleUsage) {
+	latest := make(map[string]string)
+	for _, u := range usages {
+		v, ok := latest[u.URL]
+		if !ok {
+			tags, err := o.Tags.ListTags(ctx, u.URL)
+			if err != nil {
+				fmt.Fprintf(o.IO.ErrOut, "failed to list the versions of module %s: %v\n", u.Name, err)
+			}
+			v = ociclient.LatestVersion(tags, o.Prerelease)
+			latest[u.URL] = v
+		}
+		u.Latest = v
+	}
+}
+
+// workspaceModuleUsages returns the OCI modules of the workspace sorted by name.
+func workspaceModuleUsages(workspace *v1.Workspace) []*moduleUsage {
+	names := make([]string, 0, len(workspace.Modules))
+	for name := range workspace.Modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var usages []*moduleUsage
+	for _, name := range names {
+		m := workspace.Modules[name]
+		if m == nil || !strings.HasPrefix(m.Path, oci.OCIRepositoryPrefix) {
+			continue
+		}
+		usages = append(usages, &moduleUsage{
+			Name:    name,
+			URL:     strings.TrimSuffix(m.Path, "/"),
+			Version: m.Version,
+		})
+	}
+	return usages
+}
+
+// stackModuleUsages returns the OCI dependencies in the kcl.mod of the stack.
+func stackModuleUsages(stack *v1.Stack, deps *orderedmap.OrderedMap[string, pkg.Dependency]) []*moduleUsage {
+	if deps == nil {
+		return nil
+	}
+	var usages []*moduleUsage
+	for el := deps.Front(); el != nil; el = el.Next() {
+		dep := el.Value
+		if dep.Source.Oci == nil || dep.Source.Oci.Reg == "" || dep.Source.Oci.Repo == "" {
+			continue
+		}
+		usages = append(usages, &moduleUsage{
+			Name:    el.Key,
+			URL:     oci.OCIRepositoryPrefix + dep.Source.Oci.Reg + "/" + strings.Trim(dep.Source.Oci.Repo, "/"),
+			Version: dep.Version,
+			Stack:   stack,
+		})
+	}
+	return usages
+}
+
+// ociSource returns the kcl.mod source of the OCI repository with the specified tag.
+func ociSource(ociURL, tag string) (*downloader.Oci, error) {
+	u, err := url.Parse(ociURL)
+	if err != nil || u.Host == "" || u.Path == "" {
+		return nil, fmt.Errorf("invalid module path: %s", ociURL)
+	}
+	return &downloader.Oci{
+		Reg:  u.Host,
+		Repo: strings.TrimPrefix(u.Path, "/"),
+		Tag:  tag,
+	}, nil
+}
+
+// newKpmClient returns a kpm client downloading the dependencies of the current platform.
+func newKpmClient() (*client.KpmClient, error) {
+	cli, err := client.NewKpmClient()
+	if err != nil {
+		return nil, err
+	}
+	cli.DepDownloader = downloader.NewOciDownloader(runtime.GOOS + "/" + runtime.GOARCH)
+	return cli, nil
+}
+
+// loadStackPkg loads the kcl package of the stack.
+func loadStackPkg(stack *v1.Stack) (*pkg.KclPkg, error) {
+	cli, err := newKpmClient()
+	if err != nil {
+		return nil, err
+	}
+	kclPkg, err := cli.LoadPkgFromPath(stack.Path)
+	if err != nil {
+		return nil, fmt.Errorf("load kcl.mod of stack %s failed: %w", stack.Path, err)
+	}
+	return kclPkg, nil
+}
+
+type OutdatedOptions struct {
+	ModuleVersionOptions
+}
+
+func (o *OutdatedOptions) Run() error {
+	usages, err := o.collectModuleUsages()
+	if err != nil {
+		return err
+	}
+	if len(usages) == 0 {
+		fmt.Fprintln(o.IO.Out, "No OCI modules found")
+		return nil
+	}
+	o.resolveLatest(context.Background(), usages)
+
+	tableHeader := []string{"Name", "Location", "Current", "Latest", "Status"}
+	tableData := pterm.TableData{tableHeader}
+	for _, u := range usages {
+		latest := u.Latest
+		if latest == "" {
+			latest = "-"
+		}
+		tableData = append(tableData, []string{u.Name, u.location(o.Workspace), u.Version, latest, u.status()})
+	}
+	_ = pterm.DefaultTable.WithHasHeader().
+		WithHeaderStyle(&pterm.ThemeDefault.TableHeaderStyle).
+		WithLeftAlignment(true).
+		WithSeparator("  ").
+		WithData(tableData).
+		WithWriter(o.IO.Out).
+		Render()
+	return nil
+}
+
+func NewCmdOutdated(io genericiooptions.IOStreams) *cobra.Command {
+	f := &ModuleVersionFlags{
+		MetaFlags: *meta.NewMetaFlags(),
+	}
+
+	cmd := &cobra.Command{
+		Use:     "outdated [--workspace WORKSPACE] [--all] [--prerelease]",
+		Short:   outdatedShort,
+		Example: templates.Examples(outdatedExample),
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			defer util.RecoverErr(&err)
+
+			o, err := f.toModuleVersionOptions(io)
+			util.CheckErr(err)
+			util.CheckErr((&OutdatedOptions{ModuleVersionOptions: *o}).Run())
+			return
+		},
+	}
+	f.AddFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,99 @@
+package mod
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	orderedmap "github.com/elliotchance/orderedmap/v2"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"kcl-lang.io/kpm/pkg/downloader"
+	pkg "kcl-lang.io/kpm/pkg/package"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+type fakeTagLister struct {
+	tags  map[string][]string
+	calls int
+}
+
+func (l *fakeTagLister) ListTags(_ context.Context, ociURL string) ([]string, error) {
+	l.calls++
+	tags, ok := l.tags[ociURL]
+	if !ok {
+		return nil, errors.New("repository not found")
+	}
+	return tags, nil
+}
+
+func TestWorkspaceModuleUsages(t *testing.T) {
+	ws := &v1.Workspace{
+		Name: "dev",
+		Modules: map[string]*v1.ModuleConfig{
+			"service": {Path: "oci://ghcr.io/kusionstack/service", Version: "0.1.0"},
+			"mysql":   {Path: "oci://ghcr.io/kusionstack/mysql/", Version: "0.2.0"},
+			"local":   {Path: "/path/to/local", Version: "0.1.0"},
+		},
+	}
+
+	usages := workspaceModuleUsages(ws)
+	assert.Len(t, usages, 2)
+	assert.Equal(t, "mysql", usages[0].Name)
+	assert.Equal(t, "oci://ghcr.io/kusionstack/mysql", usages[0].URL)
+	assert.Equal(t, "0.2.0", usages[0].Version)
+	assert.Equal(t, "workspace dev", usages[0].location(ws))
+	assert.Equal(t, "service", usages[1].Name)
+}
+
+func TestStackModuleUsages(t *testing.T) {
+	stack := &v1.Stack{Name: "dev", Path: "/path/to/project/dev"}
+	deps := orderedmap.NewOrderedMap[string, pkg.Dependency]()
+	deps.Set("service", pkg.Dependency{
+		Name:    "service",
+		Version: "0.1.0",
+		Source: downloader.Source{
+			Oci: &downloader.Oci{Reg: "ghcr.io", Repo: "kusionstack/service", Tag: "0.1.0"},
+		},
+	})
+	deps.Set("local", pkg.Dependency{
+		Name: "local",
+		Source: downloader.Source{
+			Local: &downloader.Local{Path: "../local"},
+		},
+	})
+
+	usages := stackModuleUsages(stack, deps)
+	assert.Len(t, usages, 1)
+	assert.Equal(t, "service", usages[0].Name)
+	assert.Equal(t, "oci://ghcr.io/kusionstack/service", usages[0].URL)
+	assert.Equal(t, "/path/to/project/dev", usages[0].location(nil))
+	assert.Nil(t, stackModuleUsages(stack, nil))
+}
+
+func TestModuleVersionOptions_ResolveLatest(t *testing.T) {
+	errOut := &bytes.Buffer{}
+	lister := &fakeTagLister{tags: map[string][]string{
+		"oci://ghcr.io/kusionstack/service": {"0.1.0", "0.10.0", "0.9.0", "1.0.0-rc.1"},
+	}}
+	o := &ModuleVersionOptions{
+		Tags: lister,
+		IO:   genericiooptions.IOStreams{ErrOut: errOut},
+	}
+	usages := []*moduleUsage{
+		{Name: "service", URL: "oci://ghcr.io/kusionstack/service", Version: "0.1.0"},
+		{Name: "service", URL: "oci://ghcr.io/kusionstack/service", Version: "0.10.0"},
+		{Name: "mysql", URL: "oci://ghcr.io/kusionstack/mysql", Version: "0.1.0"},
+	}
+
+	o.resolveLatest(context.Background(), usages)
+	assert.Equal(t, 2, lister.calls)
+	assert.Equal(t, "0.10.0", usages[0].Latest)
+	assert.Equal(t, moduleStatusOutdated, usages[0].status())
+	assert.Equal(t, moduleStatusUpToDate, usages[1].status())
+	assert.Equal(t, "", usages[2].Latest)
+	assert.Equal(t, moduleStatusUnknown, usages[2].status())
+	assert.Contains(t, errOut.String(), "mysql")
+}
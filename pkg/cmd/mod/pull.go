@@ -10,13 +10,20 @@ import (
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/kubectl/pkg/util/templates"
 	"kusionstack.io/kusion-module-framework/pkg/module/registry"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/backend"
 	cmdutil "kusionstack.io/kusion/pkg/cmd/util"
+	"kusionstack.io/kusion/pkg/engine/api/generate/generator"
 	"kusionstack.io/kusion/pkg/util/i18n"
 )
 
 var (
 	pullLong = i18n.T(`
-	The pull command downloads the kusion modules declared in the kcl.mod file.`)
+	The pull command downloads the kusion modules declared in the kcl.mod file.
+
+	The pulled modules are verified with the module trust policy of the workspace if configured, which is the
+	current workspace unless specified.`)
 
 	pullExample = i18n.T(`
 	# Pull the kusion modules declared in the kcl.mod file under current directory
@@ -24,6 +31,9 @@ var (
 	
 	# Pull the kusion modules declared in the kcl.mod file under specified directory
 	kusion mod pull /dir/to/kcl.mod
+
+	# Pull the kusion modules and verify them with the module trust policy of the specified workspace
+	kusion mod pull --workspace prod
 	
 	# Pull the kusion modules with private oci registry
 	kusion mod pull --host ghcr.io/kusion-module-registry --username username --password password
@@ -40,9 +50,10 @@ var (
 //
 // This structure reduces the transformation to wiring and makes the logic itself easy to unit test.
 type PullModFlags struct {
-	Host     string
-	Username string
-	Password string
+	Host      string
+	Username  string
+	Password  string
+	Workspace string
 
 	genericiooptions.IOStreams
 }
@@ -50,10 +61,11 @@ type PullModFlags struct {
 // PullModOptions is a set of options that allows you to push module. This is the object reflects the
 // runtime needs of a `mod pull` command, making the logic itself easy to unit test.
 type PullModOptions struct {
-	Dir      string
-	Host     string
-	Username string
-	Password string
+	Dir       string
+	Host      string
+	Username  string
+	Password  string
+	Workspace *v1.Workspace
 
 	genericiooptions.IOStreams
 }
@@ -95,6 +107,7 @@ func (flags *PullModFlags) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&flags.Host, "host", "", "The host of kusion module oci registry.")
 	cmd.Flags().StringVar(&flags.Username, "username", "", "The username of kusion module oci registry.")
 	cmd.Flags().StringVar(&flags.Password, "password", "", "The password of kusion module oci registry.")
+	cmd.Flags().StringVar(&flags.Workspace, "workspace", "", "The workspace whose module trust policy verifies the modules.")
 }
 
 // ToOptions converts from CLI inputs to runtime inputs.
@@ -133,14 +146,41 @@ func (flags *PullModFlags) ToOptions(args []string, ioStreams genericiooptions.I
 		password = os.Getenv("KUSION_MODULE_REGISTRY_PASSWORD")
 	}
 
+	workspace, err := trustWorkspace(flags.Workspace)
+	if err != nil {
+		return nil, err
+	}
+
 	return &PullModOptions{
-		Dir:      dir,
-		Host:     host,
-		Username: username,
-		Password: password,
+		Dir:       dir,
+		Host:      host,
+		Username:  username,
+		Password:  password,
+		Workspace: workspace,
 	}, nil
 }
 
+// trustWorkspace returns the workspace whose module trust policy verifies the pulled modules. The current
+// workspace is used if the name is empty, and it's skipped if not available.
+func trustWorkspace(name string) (*v1.Workspace, error) {
+	storageBackend, err := backend.NewBackend("")
+	if err != nil {
+		return nil, err
+	}
+	storage, err := storageBackend.WorkspaceStorage()
+	if err != nil {
+		return nil, err
+	}
+	workspace, err := storage.Get(name)
+	if err != nil {
+		if name != "" {
+			return nil, err
+		}
+		return nil, nil
+	}
+	return workspace, nil
+}
+
 // Validate verifies if PullModOptions is valid and without conflicts.
 func (o *PullModOptions) Validate() error {
 	if _, err := os.Stat(filepath.Join([]string{o.Dir, "kcl.mod"}...)); err != nil {
@@ -167,7 +207,12 @@ func (o *PullModOptions) Run() (err error) {
 	}
 
 	// Download the Kusion Module Dependencies in `kcl.mod` file.
-	err = kusionModRegCli.DownloadKusionModules(o.Dir)
+	if err = kusionModRegCli.DownloadKusionModules(o.Dir); err != nil {
+		return
+	}
+
+	// Verify the downloaded modules with the module trust policy of the workspace.
+	err = generator.VerifyDependentModules(o.Dir, o.Workspace)
 
 	return
 }
@@ -33,7 +33,11 @@ import (
 var (
 	pushLong = i18n.T(`
 		The push command packages the module as an OCI artifact and pushes it to the
-		OCI registry using the version as the image tag.`)
+		OCI registry using the version as the image tag.
+
+		The module binary is built for each of the specified OS archs, and the artifacts of all
+		the OS archs are bundled into an OCI image index, from which the artifact matching the
+		platform is selected automatically when the module is pulled.`)
 
 	pushExample = i18n.T(`
 		# Push a module of current OS arch to an OCI Registry using a token
@@ -41,6 +45,9 @@ var (
 
 		# Push a module of specific OS arch to an OCI Registry using a token
 		kusion mod push /path/to/my-module oci://ghcr.io/org --os-arch=darwin/arm64 --creds <YOUR_TOKEN>
+
+		# Push a module bundling the binaries of multiple OS archs into one artifact
+		kusion mod push /path/to/my-module oci://ghcr.io/org --os-arch=darwin/arm64,linux/amd64 --creds <YOUR_TOKEN>
 		
 		# Push a module to an OCI Registry using a credentials in <YOUR_USERNAME>:<YOUR_TOKEN> format. 
 		kusion mod push /path/to/my-module oci://ghcr.io/org --creds <YOUR_USERNAME>:<YOUR_TOKEN>
@@ -68,7 +75,7 @@ const LatestVersion = "latest"
 // This structure reduces the transformation to wiring and makes the logic itself easy to unit test.
 type PushModFlags struct {
 	Latest           bool
-	OSArch           string
+	OSArch           []string
 	Annotations      []string
 	Credentials      string
 	Sign             string
@@ -84,7 +91,7 @@ type PushModOptions struct {
 	ModulePath string
 	OCIUrl     string
 	Latest     bool
-	OSArch     string
+	Platforms  []*v1.Platform
 	Name       string
 	Version    string
 	Sign       string
@@ -132,7 +139,8 @@ func NewCmdPush(ioStreams genericiooptions.IOStreams) *cobra.Command {
 
 // AddFlags registers flags for a cli.
 func (flags *PushModFlags) AddFlags(cmd *cobra.Command) {
-	cmd.Flags().StringVar(&flags.OSArch, "os-arch", "", "The os arch of the module e.g. 'darwin/arm64', 'linux/amd64'.")
+	cmd.Flags().StringSliceVar(&flags.OSArch, "os-arch", flags.OSArch,
+		"The os archs of the module e.g. 'darwin/arm64', 'linux/amd64', separated by comma to bundle multiple ones.")
 	cmd.Flags().BoolVar(&flags.Latest, "latest", true, "Tags the current version as the latest stable module version.")
 	cmd.Flags().StringVar(&flags.Credentials, "creds", flags.Credentials,
 		"The credentials token for the OCI registry in <YOUR_TOKEN> or <YOUR_USERNAME>:<YOUR_TOKEN> format.")
@@ -186,21 +194,20 @@ func (flags *PushModFlags) ToOptions(args []string, ioStreams genericiooptions.I
 	}
 
 	// os arch
-	if flags.OSArch == "" {
+	if len(flags.OSArch) == 0 {
 		// set as the current OS arch
-		flags.OSArch = runtime.GOOS + "/" + runtime.GOARCH
+		flags.OSArch = []string{runtime.GOOS + "/" + runtime.GOARCH}
+	}
+	platforms, err := ociclient.ParsePlatforms(flags.OSArch)
+	if err != nil {
+		return nil, err
 	}
-	osArch := strings.Split(flags.OSArch, "/")
 
 	meta := metadata.Metadata{
 		Created:     info.CommitDate,
 		Source:      info.RemoteURL,
 		Revision:    info.Commit,
 		Annotations: annotations,
-		Platform: &v1.Platform{
-			OS:           osArch[0],
-			Architecture: osArch[1],
-		},
 	}
 	if len(meta.Created) == 0 {
 		ct := time.Now().UTC()
@@ -212,7 +219,6 @@ func (flags *PushModFlags) ToOptions(args []string, ioStreams genericiooptions.I
 		ociclient.WithUserAgent(oci.UserAgent),
 		ociclient.WithCredentials(flags.Credentials),
 		ociclient.WithInsecure(flags.InsecureRegistry),
-		ociclient.WithPlatform(meta.Platform),
 	}
 	client := ociclient.NewClient(opts...)
 
@@ -227,7 +233,7 @@ func (flags *PushModFlags) ToOptions(args []string, ioStreams genericiooptions.I
 		Name:       name,
 		Version:    version,
 		IOStreams:  ioStreams,
-		OSArch:     flags.OSArch,
+		Platforms:  platforms,
 	}
 	return opt, nil
 }
@@ -253,50 +259,49 @@ func (o *PushModOptions) Run() error {
 		_ = sp.Stop()
 	}()
 
-	targetDir, err := o.buildModule()
-	defer os.RemoveAll(targetDir)
-	if err != nil {
-		return err
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
-	defer cancel()
+	// Build the module binary and package the module for each platform
+	sources := make([]ociclient.PlatformSource, 0, len(o.Platforms))
+	for _, platform := range o.Platforms {
+		targetDir, err := o.buildModule(platform)
+		defer os.RemoveAll(targetDir)
+		if err != nil {
+			return err
+		}
 
-	// Copy to temp module dir and push artifact to OCI repository
-	err = ioutil.CopyDir(targetDir, o.ModulePath, func(path string) bool {
-		skipDirs := []string{filepath.Join(o.ModulePath, ".git"), filepath.Join(o.ModulePath, ".idea"), filepath.Join(o.ModulePath, "src")}
+		// Copy to temp module dir and push artifact to OCI repository
+		err = ioutil.CopyDir(targetDir, o.ModulePath, func(path string) bool {
+			skipDirs := []string{filepath.Join(o.ModulePath, ".git"), filepath.Join(o.ModulePath, ".idea"), filepath.Join(o.ModulePath, "src")}
 
-		// skip files in skipDirs
-		for _, dir := range skipDirs {
-			if strings.HasPrefix(path, dir) {
-				return true
+			// skip files in skipDirs
+			for _, dir := range skipDirs {
+				if strings.HasPrefix(path, dir) {
+					return true
+				}
 			}
+			return false
+		})
+		if err != nil {
+			return err
 		}
-		return false
-	})
-	if err != nil {
-		return err
+		sources = append(sources, ociclient.PlatformSource{Platform: platform, SourceDir: targetDir})
 	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
 	sp.Info("pushing the module...")
-	idxDigestURL, imgDigestURL, err := o.Client.Push(ctx, o.OCIUrl, o.Version, targetDir, o.Metadata, nil)
+	idxDigestURL, imgDigestURLs, err := o.Client.PushPlatforms(ctx, o.OCIUrl, o.Version, sources, o.Metadata, nil)
 	if err != nil {
 		return err
 	}
 
 	// Tag version
-	if err = o.Client.Tag(ctx, imgDigestURL, o.Version); err != nil {
-		return fmt.Errorf("tagging module image version as latest failed: %w", err)
-	}
 	if err = o.Client.Tag(ctx, idxDigestURL, o.Version); err != nil {
-		return fmt.Errorf("tagging module index version as latest failed: %w", err)
+		return fmt.Errorf("tagging module index version failed: %w", err)
 	}
 
 	// Tag latest version if required
 	if o.Latest {
-		if err = o.Client.Tag(ctx, imgDigestURL, LatestVersion); err != nil {
-			return fmt.Errorf("tagging module image version as latest failed: %w", err)
-		}
 		if err = o.Client.Tag(ctx, idxDigestURL, LatestVersion); err != nil {
 			return fmt.Errorf("tagging module index version as latest failed: %w", err)
 		}
@@ -304,9 +309,11 @@ func (o *PushModOptions) Run() error {
 
 	// Signs the module with specific provider
 	if len(o.Sign) != 0 {
-		err = oci.SignArtifact(o.Sign, imgDigestURL, o.CosignKey)
-		if err != nil {
-			return err
+		for _, imgDigestURL := range imgDigestURLs {
+			err = oci.SignArtifact(o.Sign, imgDigestURL, o.CosignKey)
+			if err != nil {
+				return err
+			}
 		}
 		err = oci.SignArtifact(o.Sign, idxDigestURL, o.CosignKey)
 		if err != nil {
@@ -321,7 +328,7 @@ func (o *PushModOptions) Run() error {
 }
 
 // build target os arch module binary
-func (o *PushModOptions) buildModule() (string, error) {
+func (o *PushModOptions) buildModule(platform *v1.Platform) (string, error) {
 	// First build executable binary via compilation
 	// Create temp module dir for later tar operation
 	targetDir, err := os.MkdirTemp("", filepath.Base(o.ModulePath))
@@ -333,11 +340,11 @@ func (o *PushModOptions) buildModule() (string, error) {
 	goFileSearchPattern := filepath.Join(moduleSrc, "*.go")
 
 	// prepare platform
-	if o.Metadata.Platform == nil {
-		return "", fmt.Errorf("platform is not set in metadata")
+	if platform == nil {
+		return "", fmt.Errorf("platform is not set")
 	}
-	pOS := o.Metadata.Platform.OS
-	pArch := o.Metadata.Platform.Architecture
+	pOS := platform.OS
+	pArch := platform.Architecture
 
 	if matches, err := filepath.Glob(goFileSearchPattern); err != nil || len(matches) == 0 {
 		return "", fmt.Errorf("no go source code files found for 'go build' matching %s", goFileSearchPattern)
@@ -349,7 +356,7 @@ func (o *PushModOptions) buildModule() (string, error) {
 	}
 
 	output := filepath.Join(targetDir, "_dist", pOS, pArch, "kusion-module-"+o.Name+"_"+o.Version)
-	if strings.Contains(o.OSArch, "windows") {
+	if pOS == "windows" {
 		output += ".exe"
 	}
 
@@ -0,0 +1,483 @@
+package mod
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/metadata"
+	yamlv2 "gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/kubectl/pkg/util/templates"
+	"kcl-lang.io/kpm/pkg/api"
+	"kusionstack.io/kusion-module-framework/pkg/module"
+	"kusionstack.io/kusion-module-framework/pkg/module/proto"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	cmdutil "kusionstack.io/kusion/pkg/cmd/util"
+	"kusionstack.io/kusion/pkg/util/diff"
+	"kusionstack.io/kusion/pkg/util/executable"
+	"kusionstack.io/kusion/pkg/util/i18n"
+	"kusionstack.io/kusion/pkg/util/kfile"
+)
+
+var (
+	testShort = i18n.T("Test a module with the request fixtures and the golden files")
+
+	testLong = i18n.T(`
+		The test command builds the module binary, launches it as a module plugin in the same way as
+		kusion generate, and feeds it the generator request fixtures in the tests directory of the module.
+
+		Each fixture file tests/<suite>.yaml contains a list of test cases, each of which specifies the
+		project, stack and app names, the workload, the dev config, the platform config, the workspace
+		context and the secret store of the request. The resources and the patcher generated by the module
+		are compared with the golden file tests/golden/<suite>/<case>.yaml, which is written by --update.`)
+
+	testExample = i18n.T(`
+		# Test the module in the current directory
+		kusion mod test
+
+		# Test the module in the specified directory, only the cases whose names match the regexp
+		kusion mod test /path/to/my-module --run 'mysql-.*'
+
+		# Update the golden files with the generated resources and patcher
+		kusion mod test --update`)
+)
+
+const (
+	// moduleTestDir is the directory of the fixtures in the module.
+	moduleTestDir = "tests"
+	// moduleGoldenDir is the directory of the golden files in moduleTestDir.
+	moduleGoldenDir = "golden"
+	// moduleTestNamespace is the namespace of the module key when testing the module.
+	moduleTestNamespace = "kusion-mod-test"
+	// moduleNameMetadataKey is the grpc metadata key of the module name, the same as kusion generate.
+	moduleNameMetadataKey = "kusion_module_name"
+)
+
+var ErrModuleTestFailed = errors.New("module test failed")
+
+// ModuleTestSuite is a fixture file, which contains the test cases of a module.
+type ModuleTestSuite struct {
+	// Name is the name of the suite, which is the base name of the fixture file.
+	Name string `yaml:"-" json:"-"`
+
+	// Cases are the test cases of the suite.
+	Cases []*ModuleTestCase `yaml:"cases" json:"cases"`
+}
+
+// ModuleTestCase is a test case of the module, which specifies the generator request.
+type ModuleTestCase struct {
+	Name           string           `yaml:"name" json:"name"`
+	Project        string           `yaml:"project,omitempty" json:"project,omitempty"`
+	Stack          string           `yaml:"stack,omitempty" json:"stack,omitempty"`
+	App            string           `yaml:"app,omitempty" json:"app,omitempty"`
+	Workload       v1.Accessory     `yaml:"workload,omitempty" json:"workload,omitempty"`
+	DevConfig      v1.Accessory     `yaml:"devConfig,omitempty" json:"devConfig,omitempty"`
+	PlatformConfig v1.GenericConfig `yaml:"platformConfig,omitempty" json:"platformConfig,omitempty"`
+	Context        v1.GenericConfig `yaml:"context,omitempty" json:"context,omitempty"`
+	SecretStore    *v1.SecretStore  `yaml:"secretStore,omitempty" json:"secretStore,omitempty"`
+}
+
+// ModuleTestOutput is the output of the module for a test case, which is kept in the golden file.
+type ModuleTestOutput struct {
+	Resources []v1.Resource `yaml:"resources,omitempty" json:"resources,omitempty"`
+	Patcher   *v1.Patcher   `yaml:"patcher,omitempty" json:"patcher,omitempty"`
+}
+
+// TestModFlags directly reflect the information that CLI is gathering via flags. They will be converted to
+// TestModOptions, which reflect the runtime requirements for the command.
+type TestModFlags struct {
+	Update bool
+	Run    string
+
+	genericiooptions.IOStreams
+}
+
+// TestModOptions is a set of options that allows you to test module. This is the object reflects the
+// runtime needs of a `mod test` command, making the logic itself easy to unit test.
+type TestModOptions struct {
+	ModulePath string
+	Name       string
+	Version    string
+	Update     bool
+	Run        *regexp.Regexp
+
+	genericiooptions.IOStreams
+}
+
+// NewTestModFlags returns a default TestModFlags.
+func NewTestModFlags(ioStreams genericiooptions.IOStreams) *TestModFlags {
+	return &TestModFlags{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdTest returns an initialized Command instance for the 'mod test' sub command.
+func NewCmdTest(ioStreams genericiooptions.IOStreams) *cobra.Command {
+	flags := NewTestModFlags(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   "test [MODULE_PATH] [--update] [--run REGEXP]",
+		DisableFlagsInUseLine: true,
+		Short:                 testShort,
+		Long:                  templates.LongDesc(testLong),
+		Example:               templates.Examples(testExample),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			o, err := flags.ToOptions(args, flags.IOStreams)
+			defer cmdutil.RecoverErr(&err)
+			cmdutil.CheckErr(err)
+			cmdutil.CheckErr(o.Validate())
+			cmdutil.CheckErr(o.Run())
+			return
+		},
+	}
+
+	flags.AddFlags(cmd)
+
+	return cmd
+}
+
+// AddFlags registers flags for a cli.
+func (flags *TestModFlags) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&flags.Update, "update", false, "Update the golden files with the output of the module.")
+	cmd.Flags().StringVar(&flags.Run, "run", "", "Only run the test cases whose names match the regexp.")
+}
+
+// ToOptions converts from CLI inputs to runtime inputs.
+func (flags *TestModFlags) ToOptions(args []string, ioStreams genericiooptions.IOStreams) (*TestModOptions, error) {
+	if len(args) > 1 {
+		return nil, fmt.Errorf("at most one module path is accepted")
+	}
+	modulePath := "."
+	if len(args) == 1 {
+		modulePath = args[0]
+	}
+
+	kclPkg, err := api.GetKclPackage(modulePath)
+	if err != nil {
+		return nil, err
+	}
+	name := kclPkg.GetPkgName()
+	if name == "" {
+		return nil, fmt.Errorf("module name is empty")
+	}
+
+	var run *regexp.Regexp
+	if flags.Run != "" {
+		if run, err = regexp.Compile(flags.Run); err != nil {
+			return nil, fmt.Errorf("invalid regexp of --run: %w", err)
+		}
+	}
+
+	return &TestModOptions{
+		ModulePath: modulePath,
+		Name:       name,
+		Version:    kclPkg.GetVersion(),
+		Update:     flags.Update,
+		Run:        run,
+		IOStreams:  ioStreams,
+	}, nil
+}
+
+// Validate verifies if TestModOptions are valid and without conflicts.
+func (o *TestModOptions) Validate() error {
+	if fileInfo, err := os.Stat(o.ModulePath); err != nil || !fileInfo.IsDir() {
+		return fmt.Errorf("no module found at path %s", o.ModulePath)
+	}
+	if o.Version == "" {
+		return fmt.Errorf("module version is empty")
+	}
+	return nil
+}
+
+// Run executes the `mod test` command.
+func (o *TestModOptions) Run() (err error) {
+	suites, err := LoadModuleTestSuites(filepath.Join(o.ModulePath, moduleTestDir))
+	if err != nil {
+		return err
+	}
+	if len(suites) == 0 {
+		return fmt.Errorf("no test fixtures found in %s", filepath.Join(o.ModulePath, moduleTestDir))
+	}
+
+	// Build the module binary into a temporary kusion home, and launch it from there as kusion generate does,
+	// so that the modules installed in the kusion home are not affected.
+	home, err := os.MkdirTemp("", "kusion-mod-test")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(home)
+	if err = o.buildModule(home); err != nil {
+		return err
+	}
+
+	originalHome, homeSet := os.LookupEnv(kfile.EnvKusionHome)
+	if err = os.Setenv(kfile.EnvKusionHome, home); err != nil {
+		return err
+	}
+	defer func() {
+		if homeSet {
+			_ = os.Setenv(kfile.EnvKusionHome, originalHome)
+		} else {
+			_ = os.Unsetenv(kfile.EnvKusionHome)
+		}
+	}()
+
+	plugin, err := module.NewPlugin(fmt.Sprintf("%s/%s@%s", moduleTestNamespace, o.Name, o.Version), home)
+	if err != nil {
+		return fmt.Errorf("launch module %s failed: %w", o.Name, err)
+	}
+	if plugin == nil {
+		return fmt.Errorf("init plugin for module %s failed", o.Name)
+	}
+	defer func() {
+		if killErr := plugin.KillPluginClient(); killErr != nil {
+			err = errors.Join(err, killErr)
+		}
+	}()
+
+	var passed, failed int
+	for _, suite := range suites {
+		for _, tc := range suite.Cases {
+			id := suite.Name + "/" + tc.Name
+			if o.Run != nil && !o.Run.MatchString(tc.Name) {
+				continue
+			}
+			msg, caseErr := o.runCase(plugin, suite, tc)
+			if caseErr != nil {
+				failed++
+				fmt.Fprintf(o.Out, "--- FAIL: %s\n%s\n", id, indent(caseErr.Error()))
+				continue
+			}
+			passed++
+			fmt.Fprintf(o.Out, "--- PASS: %s%s\n", id, msg)
+		}
+	}
+
+	fmt.Fprintf(o.Out, "\n%d passed, %d failed\n", passed, failed)
+	if failed != 0 {
+		return ErrModuleTestFailed
+	}
+	return nil
+}
+
+// runCase invokes the module with the request of the test case, and compares the output with the golden file,
+// or updates the golden file if required.
+func (o *TestModOptions) runCase(plugin *module.Plugin, suite *ModuleTestSuite, tc *ModuleTestCase) (string, error) {
+	request, err := tc.Request()
+	if err != nil {
+		return "", err
+	}
+	ctx := metadata.AppendToOutgoingContext(context.Background(), moduleNameMetadataKey, plugin.ModuleName)
+	response, err := plugin.Module.Generate(ctx, request)
+	if err != nil {
+		return "", fmt.Errorf("invoke module failed: %w", err)
+	}
+	if response == nil {
+		return "", fmt.Errorf("empty response from module")
+	}
+	output, err := NewModuleTestOutput(response.Resources, response.Patcher)
+	if err != nil {
+		return "", err
+	}
+
+	goldenPath := filepath.Join(o.ModulePath, moduleTestDir, moduleGoldenDir, suite.Name, tc.Name+".yaml")
+	if o.Update {
+		if err = WriteGoldenFile(goldenPath, output); err != nil {
+			return "", err
+		}
+		return " (updated)", nil
+	}
+	return "", CompareGoldenFile(goldenPath, output)
+}
+
+// buildModule builds the module binary of the current platform into the kusion home, in the path where
+// module.NewPlugin looks for the binary of the module key.
+func (o *TestModOptions) buildModule(home string) error {
+	moduleSrc := filepath.Join(o.ModulePath, "src")
+	goFileSearchPattern := filepath.Join(moduleSrc, "*.go")
+	if matches, err := filepath.Glob(goFileSearchPattern); err != nil || len(matches) == 0 {
+		return fmt.Errorf("no go source code files found for 'go build' matching %s", goFileSearchPattern)
+	}
+
+	goBin, err := executable.FindExecutable("go")
+	if err != nil {
+		return fmt.Errorf("unable to find executable 'go' binary: %w", err)
+	}
+
+	output := filepath.Join(home, "modules", moduleTestNamespace, o.Name, o.Version, runtime.GOOS, runtime.GOARCH,
+		"kusion-module-"+o.Name+"_"+o.Version)
+	if runtime.GOOS == "windows" {
+		output += ".exe"
+	}
+	if err = os.MkdirAll(filepath.Dir(output), os.ModePerm); err != nil {
+		return err
+	}
+	if _, err = buildBinary(goBin, runtime.GOOS, runtime.GOARCH, moduleSrc, output, o.IOStreams); err != nil {
+		return fmt.Errorf("failed to build the module %w", err)
+	}
+	return nil
+}
+
+// LoadModuleTestSuites loads the fixture files in the directory, sorted by the suite name.
+func LoadModuleTestSuites(dir string) ([]*ModuleTestSuite, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	suites := make([]*ModuleTestSuite, 0, len(files))
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		suite := &ModuleTestSuite{}
+		if err = yaml.Unmarshal(content, suite); err != nil {
+			return nil, fmt.Errorf("unmarshal fixture file %s failed: %w", file, err)
+		}
+		suite.Name = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+
+		names := make(map[string]bool, len(suite.Cases))
+		for _, tc := range suite.Cases {
+			if tc == nil || tc.Name == "" {
+				return nil, fmt.Errorf("empty test case name in fixture file %s", file)
+			}
+			if names[tc.Name] {
+				return nil, fmt.Errorf("duplicate test case %s in fixture file %s", tc.Name, file)
+			}
+			names[tc.Name] = true
+		}
+		suites = append(suites, suite)
+	}
+	return suites, nil
+}
+
+// Request returns the generator request of the test case, which is serialized in the same way as kusion generate.
+func (tc *ModuleTestCase) Request() (*proto.GeneratorRequest, error) {
+	var workloadConfig, secretStoreConfig, devConfig, platformConfig, ctx []byte
+	var err error
+	// Attention: the same as kusion generate, we MUST yaml.v2 to serialize the workload and the secret store.
+	if tc.Workload != nil {
+		if workloadConfig, err = yamlv2.Marshal(tc.Workload); err != nil {
+			return nil, fmt.Errorf("marshal workload config failed. %w", err)
+		}
+	}
+	if tc.SecretStore != nil {
+		if secretStoreConfig, err = yamlv2.Marshal(tc.SecretStore); err != nil {
+			return nil, fmt.Errorf("marshal secret store config failed. %w", err)
+		}
+	}
+	if tc.DevConfig != nil {
+		if devConfig, err = yaml.Marshal(tc.DevConfig); err != nil {
+			return nil, fmt.Errorf("marshal dev module config failed. %w", err)
+		}
+	}
+	if tc.PlatformConfig != nil {
+		if platformConfig, err = yaml.Marshal(tc.PlatformConfig); err != nil {
+			return nil, fmt.Errorf("marshal platform module config failed. %w", err)
+		}
+	}
+	if tc.Context != nil {
+		if ctx, err = yaml.Marshal(tc.Context); err != nil {
+			return nil, fmt.Errorf("marshal context config failed. %w", err)
+		}
+	}
+
+	return &proto.GeneratorRequest{
+		Project:        tc.Project,
+		Stack:          tc.Stack,
+		App:            tc.App,
+		Workload:       workloadConfig,
+		DevConfig:      devConfig,
+		PlatformConfig: platformConfig,
+		Context:        ctx,
+		SecretStore:    secretStoreConfig,
+	}, nil
+}
+
+// NewModuleTestOutput parses the resources and the patcher in the generator response.
+func NewModuleTestOutput(resources [][]byte, patcher []byte) (*ModuleTestOutput, error) {
+	output := &ModuleTestOutput{}
+	for _, res := range resources {
+		r := v1.Resource{}
+		if err := yaml.Unmarshal(res, &r); err != nil {
+			return nil, fmt.Errorf("unmarshal resource failed: %w", err)
+		}
+		output.Resources = append(output.Resources, r)
+	}
+	if patcher != nil {
+		output.Patcher = &v1.Patcher{}
+		if err := yaml.Unmarshal(patcher, output.Patcher); err != nil {
+			return nil, fmt.Errorf("unmarshal patcher failed: %w", err)
+		}
+	}
+	return output, nil
+}
+
+// WriteGoldenFile writes the output into the golden file.
+func WriteGoldenFile(path string, output *ModuleTestOutput) error {
+	content, err := yaml.Marshal(output)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0o644)
+}
+
+// CompareGoldenFile compares the output with the golden file, returns an error with the diff if mismatched.
+func CompareGoldenFile(path string, output *ModuleTestOutput) error {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("golden file %s not found, run with --update to create it", path)
+	}
+	if err != nil {
+		return err
+	}
+	golden := &ModuleTestOutput{}
+	if err = yaml.Unmarshal(content, golden); err != nil {
+		return fmt.Errorf("unmarshal golden file %s failed: %w", path, err)
+	}
+
+	// Compare the normalized YAML of both, so that the formatting of the golden file does not matter.
+	expected, err := yaml.Marshal(golden)
+	if err != nil {
+		return err
+	}
+	actual, err := yaml.Marshal(output)
+	if err != nil {
+		return err
+	}
+	if string(expected) == string(actual) {
+		return nil
+	}
+
+	var expectedData, actualData any
+	_ = yaml.Unmarshal(expected, &expectedData)
+	_ = yaml.Unmarshal(actual, &actualData)
+	diffString, err := diff.ToUnifiedString(expectedData, actualData, path, "actual")
+	if err != nil {
+		return fmt.Errorf("output mismatches golden file %s", path)
+	}
+	return fmt.Errorf("output mismatches golden file %s:\n%s", path, diffString)
+}
+
+func indent(s string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = "    " + line
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,107 @@
+package mod
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+const testFixture = `cases:
+  - name: default
+    project: helloworld
+    stack: dev
+    app: web
+    workload:
+      type: Service
+    devConfig:
+      type: mysql
+      version: "8.0"
+    platformConfig:
+      size: 10
+  - name: empty
+`
+
+func TestLoadModuleTestSuites(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "mysql.yaml"), []byte(testFixture), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("ignored"), 0o644))
+
+	suites, err := LoadModuleTestSuites(dir)
+	assert.NoError(t, err)
+	assert.Len(t, suites, 1)
+	assert.Equal(t, "mysql", suites[0].Name)
+	assert.Len(t, suites[0].Cases, 2)
+	assert.Equal(t, "helloworld", suites[0].Cases[0].Project)
+	assert.Equal(t, "mysql", suites[0].Cases[0].DevConfig["type"])
+
+	t.Run("duplicate test cases", func(t *testing.T) {
+		dir := t.TempDir()
+		content := "cases:\n  - name: default\n  - name: default\n"
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "mysql.yaml"), []byte(content), 0o644))
+		_, err := LoadModuleTestSuites(dir)
+		assert.ErrorContains(t, err, "duplicate test case")
+	})
+
+	t.Run("empty test case name", func(t *testing.T) {
+		dir := t.TempDir()
+		content := "cases:\n  - project: helloworld\n"
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "mysql.yaml"), []byte(content), 0o644))
+		_, err := LoadModuleTestSuites(dir)
+		assert.ErrorContains(t, err, "empty test case name")
+	})
+}
+
+func TestModuleTestCase_Request(t *testing.T) {
+	tc := &ModuleTestCase{
+		Name:      "default",
+		Project:   "helloworld",
+		Stack:     "dev",
+		App:       "web",
+		Workload:  v1.Accessory{"type": "Service"},
+		DevConfig: v1.Accessory{"type": "mysql"},
+	}
+
+	request, err := tc.Request()
+	assert.NoError(t, err)
+	assert.Equal(t, "helloworld", request.Project)
+	assert.Equal(t, "dev", request.Stack)
+	assert.Equal(t, "web", request.App)
+	assert.Equal(t, "type: Service\n", string(request.Workload))
+	assert.Equal(t, "type: mysql\n", string(request.DevConfig))
+	assert.Nil(t, request.PlatformConfig)
+	assert.Nil(t, request.Context)
+	assert.Nil(t, request.SecretStore)
+}
+
+func TestGoldenFile(t *testing.T) {
+	resource := v1.Resource{
+		ID:   "v1:Service:default:web",
+		Type: v1.Kubernetes,
+		Attributes: map[string]any{
+			"kind": "Service",
+		},
+	}
+	content, err := yaml.Marshal(resource)
+	assert.NoError(t, err)
+	output, err := NewModuleTestOutput([][]byte{content}, []byte("labels:\n  app: web\n"))
+	assert.NoError(t, err)
+	assert.Len(t, output.Resources, 1)
+	assert.Equal(t, map[string]string{"app": "web"}, output.Patcher.Labels)
+
+	path := filepath.Join(t.TempDir(), "golden", "mysql", "default.yaml")
+	err = CompareGoldenFile(path, output)
+	assert.ErrorContains(t, err, "--update")
+
+	assert.NoError(t, WriteGoldenFile(path, output))
+	assert.NoError(t, CompareGoldenFile(path, output))
+
+	output.Patcher.Labels["app"] = "api"
+	err = CompareGoldenFile(path, output)
+	assert.ErrorContains(t, err, "mismatches golden file")
+	assert.ErrorContains(t, err, "api")
+}
@@ -0,0 +1,289 @@
+package mod
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/kubectl/pkg/util/templates"
+	pkg "kcl-lang.io/kpm/pkg/package"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/cmd/meta"
+	"kusionstack.io/kusion/pkg/cmd/util"
+	ociclient "kusionstack.io/kusion/pkg/oci/client"
+	"kusionstack.io/kusion/pkg/util/i18n"
+)
+
+var upgradeExample = i18n.T(`# Upgrade all the modules of the current workspace and the current stack to the latest versions
+  kusion mod upgrade
+
+  # Upgrade a module to a specified version in a specified workspace and all the projects in the work directory
+  kusion mod upgrade mysql --version=0.2.0 --workspace=dev --all
+
+  # Show the upgrades without applying them
+  kusion mod upgrade --dry-run
+`)
+var upgradeShort = i18n.T("Upgrade the modules of a workspace and the kcl.mod of the stacks")
+
+type UpgradeFlag struct {
+	ModuleVersionFlags
+	// Version is the version to upgrade to, which is the latest version if not specified.
+	Version string
+	// DryRun indicates to show the upgrades without applying them.
+	DryRun bool
+}
+
+// AddFlags registers flags for a cli.
+func (f *UpgradeFlag) AddFlags(cmd *cobra.Command) {
+	f.ModuleVersionFlags.AddFlags(cmd)
+	cmd.Flags().StringVar(&f.Version, "version", "", i18n.T("The version to upgrade to, only valid with a single module. Defaults to the latest version."))
+	cmd.Flags().BoolVar(&f.DryRun, "dry-run", false, i18n.T("Show the upgrades without applying them."))
+}
+
+func (f *UpgradeFlag) toOption(names []string, io genericiooptions.IOStreams) (*UpgradeOptions, error) {
+	if f.Version != "" && len(names) != 1 {
+		return nil, fmt.Errorf("--version requires exactly one module name")
+	}
+	options, err := f.toModuleVersionOptions(io)
+	if err != nil {
+		return nil, err
+	}
+	return &UpgradeOptions{
+		ModuleVersionOptions: *options,
+		Names:                names,
+		Version:              f.Version,
+		DryRun:               f.DryRun,
+	}, nil
+}
+
+type UpgradeOptions struct {
+	ModuleVersionOptions
+	// Names are the names of the modules to upgrade, or all the modules if empty.
+	Names []string
+	// Version is the version to upgrade to, which is the latest version if empty.
+	Version string
+	// DryRun indicates to show the upgrades without applying them.
+	DryRun bool
+}
+
+// moduleUpgrade is an upgrade of a module referenced by the workspace or the kcl.mod of a stack.
+type moduleUpgrade struct {
+	*moduleUsage
+	// Target is the version to upgrade to.
+	Target string
+}
+
+func (o *UpgradeOptions) Run() error {
+	usages, err := o.collectModuleUsages()
+	if err != nil {
+		return err
+	}
+	if usages, err = filterModuleUsages(usages, o.Names); err != nil {
+		return err
+	}
+	if o.Version == "" {
+		o.resolveLatest(context.Background(), usages)
+	} else if err = o.checkVersion(context.Background(), usages); err != nil {
+		return err
+	}
+
+	upgrades := planUpgrades(usages, o.Version)
+	if len(upgrades) == 0 {
+		fmt.Fprintln(o.IO.Out, "All modules are up to date")
+		return nil
+	}
+	for _, up := range upgrades {
+		fmt.Fprintf(o.IO.Out, "%s  %s  %s -> %s\n", up.Name, up.location(o.Workspace), up.Version, up.Target)
+	}
+	if o.DryRun {
+		return nil
+	}
+
+	// Update the kcl.mod of the stacks before persisting the workspace, so that the workspace is left
+	// unchanged if any of the kcl.mod cannot be updated.
+	if err = o.upgradeStacks(upgrades); err != nil {
+		return err
+	}
+	if err = o.upgradeWorkspace(upgrades); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.IO.Out, "Upgraded %d module references\n", len(upgrades))
+	return nil
+}
+
+// upgradeWorkspace updates the module versions of the workspace.
+func (o *UpgradeOptions) upgradeWorkspace(upgrades []*moduleUpgrade) error {
+	var updated bool
+	for _, up := range upgrades {
+		if up.Stack != nil {
+			continue
+		}
+		o.Workspace.Modules[up.Name].Version = up.Target
+		updated = true
+	}
+	if !updated {
+		return nil
+	}
+
+	if o.StorageBackend == nil {
+		return fmt.Errorf("cannot update workspace %s with empty backend", o.Workspace.Name)
+	}
+	storage, err := o.StorageBackend.WorkspaceStorage()
+	if err != nil {
+		return err
+	}
+	return storage.Update(o.Workspace)
+}
+
+// upgradeStacks updates the dependency versions in the kcl.mod of the stacks, and downloads the dependencies.
+// All the kcl.mod are loaded and checked before any of them is updated.
+func (o *UpgradeOptions) upgradeStacks(upgrades []*moduleUpgrade) error {
+	var stacks []*v1.Stack
+	stackUpgrades := make(map[*v1.Stack][]*moduleUpgrade)
+	for _, up := range upgrades {
+		if up.Stack == nil {
+			continue
+		}
+		if _, ok := stackUpgrades[up.Stack]; !ok {
+			stacks = append(stacks, up.Stack)
+		}
+		stackUpgrades[up.Stack] = append(stackUpgrades[up.Stack], up)
+	}
+	if len(stacks) == 0 {
+		return nil
+	}
+
+	cli, err := newKpmClient()
+	if err != nil {
+		return err
+	}
+	kclPkgs := make([]*pkg.KclPkg, 0, len(stacks))
+	for _, stack := range stacks {
+		kclPkg, err := cli.LoadPkgFromPath(stack.Path)
+		if err != nil {
+			return fmt.Errorf("load kcl.mod of stack %s failed: %w", stack.Path, err)
+		}
+		deps := kclPkg.ModFile.Dependencies.Deps
+		for _, up := range stackUpgrades[stack] {
+			dep, ok := deps.Get(up.Name)
+			if !ok {
+				return fmt.Errorf("module %s not found in kcl.mod of stack %s", up.Name, stack.Path)
+			}
+			source, err := ociSource(up.URL, up.Target)
+			if err != nil {
+				return err
+			}
+			dep.Version = up.Target
+			dep.FullName = up.Name + "_" + up.Target
+			dep.Source.Oci = source
+			deps.Set(up.Name, dep)
+		}
+		kclPkgs = append(kclPkgs, kclPkg)
+	}
+
+	for i, kclPkg := range kclPkgs {
+		if err = cli.UpdateDeps(kclPkg); err != nil {
+			return fmt.Errorf("update kcl.mod of stack %s failed: %w", stacks[i].Path, err)
+		}
+	}
+	return nil
+}
+
+// checkVersion returns an error if the version to upgrade to is not a tag of the repositories of the modules,
+// so that a mistyped version is never written to the workspace or the kcl.mod of the stacks.
+func (o *UpgradeOptions) checkVersion(ctx context.Context, usages []*moduleUsage) error {
+	checked := make(map[string]bool)
+	for _, u := range usages {
+		if checked[u.URL] {
+			continue
+		}
+		checked[u.URL] = true
+		tags, err := o.Tags.ListTags(ctx, u.URL)
+		if err != nil {
+			return fmt.Errorf("failed to list the versions of module %s: %w", u.Name, err)
+		}
+		if !slices.Contains(tags, o.Version) {
+			return fmt.Errorf("version %s of module %s not found in %s", o.Version, u.Name, u.URL)
+		}
+	}
+	return nil
+}
+
+// filterModuleUsages returns the usages of the modules with the names, or all the usages if names is empty.
+func filterModuleUsages(usages []*moduleUsage, names []string) ([]*moduleUsage, error) {
+	if len(names) == 0 {
+		return usages, nil
+	}
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = false
+	}
+	var filtered []*moduleUsage
+	for _, u := range usages {
+		if _, ok := wanted[u.Name]; ok {
+			wanted[u.Name] = true
+			filtered = append(filtered, u)
+		}
+	}
+	for _, name := range names {
+		if !wanted[name] {
+			return nil, fmt.Errorf("module %s not found in the workspace or the stacks", name)
+		}
+	}
+	return filtered, nil
+}
+
+// planUpgrades returns the upgrades of the module usages. All the usages of a module are upgraded to the same
+// version to keep the workspace and the kcl.mod of the stacks consistent, which is the specified version if not
+// empty, or the latest version of the module. Without a specified version, a usage is never downgraded.
+func planUpgrades(usages []*moduleUsage, version string) []*moduleUpgrade {
+	targets := make(map[string]string)
+	for _, u := range usages {
+		if version != "" {
+			targets[u.Name] = version
+		} else if ociclient.IsNewerVersion(u.Latest, targets[u.Name]) {
+			targets[u.Name] = u.Latest
+		}
+	}
+
+	var upgrades []*moduleUpgrade
+	for _, u := range usages {
+		target := targets[u.Name]
+		if target == "" || target == u.Version {
+			continue
+		}
+		if version == "" && !ociclient.IsNewerVersion(target, u.Version) {
+			continue
+		}
+		upgrades = append(upgrades, &moduleUpgrade{moduleUsage: u, Target: target})
+	}
+	return upgrades
+}
+
+func NewCmdUpgrade(io genericiooptions.IOStreams) *cobra.Command {
+	f := &UpgradeFlag{
+		ModuleVersionFlags: ModuleVersionFlags{
+			MetaFlags: *meta.NewMetaFlags(),
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "upgrade [MODULE_NAME...] [--version VERSION] [--workspace WORKSPACE] [--all] [--dry-run]",
+		Short:   upgradeShort,
+		Example: templates.Examples(upgradeExample),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			defer util.RecoverErr(&err)
+
+			o, err := f.toOption(args, io)
+			util.CheckErr(err)
+			util.CheckErr(o.Run())
+			return
+		},
+	}
+	f.AddFlags(cmd)
+
+	return cmd
+}
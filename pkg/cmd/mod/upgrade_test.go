@@ -0,0 +1,87 @@
+package mod
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func TestFilterModuleUsages(t *testing.T) {
+	usages := []*moduleUsage{
+		{Name: "service"},
+		{Name: "mysql"},
+		{Name: "service", Stack: &v1.Stack{Path: "/path/to/dev"}},
+	}
+
+	filtered, err := filterModuleUsages(usages, nil)
+	assert.NoError(t, err)
+	assert.Len(t, filtered, 3)
+
+	filtered, err = filterModuleUsages(usages, []string{"service"})
+	assert.NoError(t, err)
+	assert.Len(t, filtered, 2)
+
+	_, err = filterModuleUsages(usages, []string{"service", "redis"})
+	assert.ErrorContains(t, err, "redis")
+}
+
+func TestPlanUpgrades(t *testing.T) {
+	stack := &v1.Stack{Path: "/path/to/dev"}
+
+	t.Run("upgrade to the latest version consistently", func(t *testing.T) {
+		usages := []*moduleUsage{
+			{Name: "service", Version: "0.1.0", Latest: "0.2.0"},
+			{Name: "service", Version: "0.1.5", Latest: "0.2.0", Stack: stack},
+			{Name: "mysql", Version: "0.3.0", Latest: "0.3.0"},
+			{Name: "redis", Version: "0.1.0"},
+		}
+		upgrades := planUpgrades(usages, "")
+		assert.Len(t, upgrades, 2)
+		assert.Equal(t, "0.2.0", upgrades[0].Target)
+		assert.Nil(t, upgrades[0].Stack)
+		assert.Equal(t, "0.2.0", upgrades[1].Target)
+		assert.Equal(t, stack, upgrades[1].Stack)
+	})
+
+	t.Run("never downgrade without a specified version", func(t *testing.T) {
+		usages := []*moduleUsage{
+			{Name: "service", Version: "0.3.0", Latest: "0.2.0"},
+		}
+		assert.Empty(t, planUpgrades(usages, ""))
+	})
+
+	t.Run("upgrade to the specified version", func(t *testing.T) {
+		usages := []*moduleUsage{
+			{Name: "service", Version: "0.3.0"},
+			{Name: "service", Version: "0.2.0", Stack: stack},
+		}
+		upgrades := planUpgrades(usages, "0.2.0")
+		assert.Len(t, upgrades, 1)
+		assert.Equal(t, "0.3.0", upgrades[0].Version)
+		assert.Equal(t, "0.2.0", upgrades[0].Target)
+	})
+}
+
+func TestUpgradeOptions_CheckVersion(t *testing.T) {
+	lister := &fakeTagLister{tags: map[string][]string{
+		"oci://ghcr.io/kusionstack/service": {"0.1.0", "0.2.0"},
+	}}
+	usages := []*moduleUsage{
+		{Name: "service", URL: "oci://ghcr.io/kusionstack/service", Version: "0.1.0"},
+		{Name: "service", URL: "oci://ghcr.io/kusionstack/service", Version: "0.1.0", Stack: &v1.Stack{Path: "/path/to/dev"}},
+	}
+
+	o := &UpgradeOptions{ModuleVersionOptions: ModuleVersionOptions{Tags: lister}, Version: "0.2.0"}
+	assert.NoError(t, o.checkVersion(context.Background(), usages))
+	assert.Equal(t, 1, lister.calls)
+
+	o.Version = "0.3.0"
+	assert.ErrorContains(t, o.checkVersion(context.Background(), usages), "version 0.3.0 of module service not found")
+
+	usages = append(usages, &moduleUsage{Name: "redis", URL: "oci://ghcr.io/kusionstack/redis"})
+	o.Version = "0.2.0"
+	assert.ErrorContains(t, o.checkVersion(context.Background(), usages), "repository not found")
+}
@@ -15,10 +15,10 @@
 package preview
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/liu-hm19/pterm"
@@ -33,11 +33,12 @@ import (
 	cmdutil "kusionstack.io/kusion/pkg/cmd/util"
 	"kusionstack.io/kusion/pkg/engine/operation"
 	"kusionstack.io/kusion/pkg/engine/operation/models"
+	"kusionstack.io/kusion/pkg/engine/plan"
 	"kusionstack.io/kusion/pkg/engine/release"
 	"kusionstack.io/kusion/pkg/engine/runtime/terraform"
 	"kusionstack.io/kusion/pkg/log"
-	"kusionstack.io/kusion/pkg/util/diff"
 	"kusionstack.io/kusion/pkg/util/i18n"
+	"kusionstack.io/kusion/pkg/util/mask"
 	"kusionstack.io/kusion/pkg/util/pretty"
 	"kusionstack.io/kusion/pkg/util/terminal"
 )
@@ -65,12 +66,19 @@ var (
 		# Preview with json format result
 		kusion preview -o json
 
+		# Preview and render the diffs as Markdown to comment on a pull request
+		kusion preview -o markdown > preview.md
+
+		# Preview and print the diffs as a unified patch
+		kusion preview -o patch
+
+		# Preview and save the plan to apply exactly the previewed changes later
+		kusion preview --out plan
+
 		# Preview without output style and color
 		kusion preview --no-style=true`)
 )
 
-const jsonOutput = "json"
-
 // PreviewFlags directly reflect the information that CLI is gathering via flags. They will be converted to
 // PreviewOptions, which reflect the runtime requirements for the command.
 //
@@ -82,6 +90,7 @@ type PreviewFlags struct {
 	All          bool
 	NoStyle      bool
 	Output       string
+	Out          string
 	SpecFile     string
 	IgnoreFields []string
 	Values       []string
@@ -99,6 +108,7 @@ type PreviewOptions struct {
 	All          bool
 	NoStyle      bool
 	Output       string
+	Out          string
 	SpecFile     string
 	IgnoreFields []string
 	Values       []string
@@ -137,6 +147,9 @@ func NewCmdPreview(ui *terminal.UI, ioStreams genericiooptions.IOStreams) *cobra
 	}
 
 	flags.AddFlags(cmd)
+	// The plan file is only written by preview, so the flag is not shared
+	// with the commands embedding the preview flags.
+	cmd.Flags().StringVarP(&flags.Out, "out", "", "", i18n.T("Save the preview to a plan file, which can be applied by `kusion apply <plan>`"))
 
 	return cmd
 }
@@ -150,7 +163,7 @@ func (f *PreviewFlags) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVarP(&f.All, "all", "a", false, i18n.T("Automatically show all preview details, combined use with flag `--detail`"))
 	cmd.Flags().BoolVarP(&f.NoStyle, "no-style", "", false, i18n.T("no-style sets to RawOutput mode and disables all of styling"))
 	cmd.Flags().StringSliceVarP(&f.IgnoreFields, "ignore-fields", "", f.IgnoreFields, i18n.T("Ignore differences of target fields"))
-	cmd.Flags().StringVarP(&f.Output, "output", "o", f.Output, i18n.T("Specify the output format"))
+	cmd.Flags().StringVarP(&f.Output, "output", "o", f.Output, i18n.T("Specify the output format, one of json, yaml, markdown and patch"))
 	cmd.Flags().StringArrayVarP(&f.Values, "argument", "D", []string{}, i18n.T("Specify arguments on the command line"))
 	cmd.Flags().StringVarP(&f.SpecFile, "spec-file", "", "", i18n.T("Specify the spec file path as input, and the spec file must be located in the working directory or its subdirectories"))
 }
@@ -169,6 +182,7 @@ func (f *PreviewFlags) ToOptions() (*PreviewOptions, error) {
 		All:          f.All,
 		NoStyle:      f.NoStyle,
 		Output:       f.Output,
+		Out:          f.Out,
 		SpecFile:     f.SpecFile,
 		IgnoreFields: f.IgnoreFields,
 		UI:           f.UI,
@@ -185,6 +199,10 @@ func (o *PreviewOptions) Validate(cmd *cobra.Command, args []string) error {
 		return cmdutil.UsageErrorf(cmd, "Unexpected args: %v", args)
 	}
 
+	if o.Output != "" && !slices.Contains(models.PreviewOutputFormats, o.Output) {
+		return cmdutil.UsageErrorf(cmd, "Invalid output format: %s, must be one of %s", o.Output, strings.Join(models.PreviewOutputFormats, ", "))
+	}
+
 	if o.SpecFile != "" {
 		absSF, _ := filepath.Abs(o.SpecFile)
 		fi, err := os.Stat(absSF)
@@ -217,7 +235,7 @@ func (o *PreviewOptions) Validate(cmd *cobra.Command, args []string) error {
 // Run executes the `preview` command.
 func (o *PreviewOptions) Run() error {
 	// set no style
-	if o.NoStyle || o.Output == jsonOutput {
+	if o.NoStyle || o.Output != "" {
 		pterm.DisableStyling()
 	}
 
@@ -241,14 +259,14 @@ func (o *PreviewOptions) Run() error {
 	}
 
 	if spec == nil {
-		if o.Output != jsonOutput {
+		if o.Output == "" {
 			fmt.Println(pretty.YellowBold("\nSpec is nil. Treating as empty spec."))
 		}
 		spec = &apiv1.Spec{}
 	}
 
 	if len(spec.Resources) == 0 {
-		if o.Output != jsonOutput {
+		if o.Output == "" {
 			fmt.Println(pretty.YellowBold("\nNo resources found in the spec."))
 		}
 	}
@@ -258,6 +276,7 @@ func (o *PreviewOptions) Run() error {
 	if err != nil {
 		return err
 	}
+	baseRevision := storage.GetLatestRevision()
 	state, err := release.GetLatestState(storage)
 	if err != nil {
 		return err
@@ -272,22 +291,23 @@ func (o *PreviewOptions) Run() error {
 		return err
 	}
 
-	if o.Output == jsonOutput {
-		var previewChanges []byte
-
-		// Mask sensitive data before printing the preview changes.
-		for _, v := range changes.ChangeSteps {
-			maskedFrom, maskedTo := diff.MaskSensitiveData(v.From, v.To)
-			v.From = maskedFrom
-			v.To = maskedTo
-		}
-
-		previewChanges, err = json.Marshal(changes)
+	// save the plan before the sensitive data in the changes is masked
+	if o.Out != "" {
+		var p *plan.Plan
+		p, err = plan.New(o.RefProject.Name, o.RefStack.Name, o.RefWorkspace.Name, baseRevision, spec, changes.ChangeOrder)
 		if err != nil {
-			return fmt.Errorf("json marshal preview changes failed as %w", err)
+			return err
 		}
-		fmt.Println(string(previewChanges))
-		return nil
+		if err = plan.WriteFile(o.Out, p, plan.SigningKey()); err != nil {
+			return fmt.Errorf("failed to save the plan: %w", err)
+		}
+		if o.Output == "" {
+			fmt.Printf("Plan saved to %s, apply it with: kusion apply %s\n", o.Out, o.Out)
+		}
+	}
+
+	if o.Output != "" {
+		return changes.Print(os.Stdout, o.Output)
 	}
 
 	if changes.AllUnChange() {
@@ -350,12 +370,19 @@ func Preview(
 ) (*models.Changes, error) {
 	log.Info("Start compute preview changes ...")
 
+	// Mask the sensitive data in the changes shown following the rules of the workspace.
+	policy, err := mask.PolicyFromContext(planResources.Context)
+	if err != nil {
+		return nil, err
+	}
+	mask.SetDefault(policy)
+
 	// check and install terraform executable binary for
 	// resources with the type of Terraform.
 	tfInstaller := terraform.CLIInstaller{
 		Intent: planResources,
 	}
-	if err := tfInstaller.CheckAndInstall(); err != nil {
+	if err = tfInstaller.CheckAndInstall(); err != nil {
 		return nil, err
 	}
 
@@ -218,7 +218,7 @@ func TestPreviewOptions_Run(t *testing.T) {
 			mockWorkspaceStorage()
 
 			o := newPreviewOptions()
-			o.Output = jsonOutput
+			o.Output = models.OutputJSON
 			err := o.Run()
 			assert.Nil(t, err)
 		})
@@ -13,6 +13,8 @@ func NewCmd() *cobra.Command {
 
 		long = i18n.T(`
 		This command creates a new project.yaml file under the target directory which by default is the current working directory. 
+
+		If a template is specified, the project is created from the template instead, see "kusion init --help" for the templates.
 		
 		Note that the target directory needs to be an empty directory.`)
 
@@ -22,7 +24,10 @@ func NewCmd() *cobra.Command {
 		kusion project create
 		
 		# Create a new project in a specified target directory
-		kusion project create --target /dir/to/projects/my-project`)
+		kusion project create --target /dir/to/projects/my-project
+
+		# Create a new project from a template of the configured template registry
+		kusion project create --template golden/web-service --set image=nginx:1.25`)
 	)
 
 	o := NewOptions()
@@ -46,6 +51,14 @@ func NewCmd() *cobra.Command {
 
 	cmd.Flags().StringVarP(&o.ProjectDir, "target", "t", "",
 		i18n.T("specify the target directory"))
+	cmd.Flags().StringVarP(&o.Template, "template", "", "",
+		i18n.T("specify the template, either <registry>/<template> or the source of a template"))
+	cmd.Flags().StringVarP(&o.TemplateVersion, "template-version", "", "",
+		i18n.T("specify the version of the template, which overrides the version of the template registry"))
+	cmd.Flags().StringArrayVarP(&o.Set, "set", "", nil,
+		i18n.T("specify the value of a template parameter in the format of key=value"))
+	cmd.Flags().BoolVarP(&o.NoPrompt, "no-prompt", "", false,
+		i18n.T("use the default values of the template parameters not specified instead of prompting for them"))
 
 	return cmd
 }
@@ -7,21 +7,31 @@ import (
 	"path/filepath"
 
 	"kusionstack.io/kusion/pkg/cmd/project/util"
+	"kusionstack.io/kusion/pkg/config"
+	"kusionstack.io/kusion/pkg/scaffold"
+	"kusionstack.io/kusion/pkg/util/terminal"
 )
 
 var ErrNotEmptyArgs = errors.New("no args accepted")
 
 type Options struct {
-	Name string
+	Name   string
+	Values map[string]string
+	UI     *terminal.UI
 	Flags
 }
 
 type Flags struct {
-	ProjectDir string
+	ProjectDir      string
+	Template        string
+	TemplateVersion string
+	Set             []string
+	NoPrompt        bool
 }
 
 func NewOptions() *Options {
 	return &Options{
+		UI: terminal.DefaultUI(),
 		Flags: Flags{
 			ProjectDir: "",
 		},
@@ -51,6 +61,10 @@ func (o *Options) Complete(args []string) error {
 		o.Name = filepath.Base(o.ProjectDir)
 	}
 
+	if o.Values, err = scaffold.ParseValues(o.Set); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -67,6 +81,10 @@ func (o *Options) Validate() error {
 }
 
 func (o *Options) Run() error {
+	if o.Template != "" {
+		return o.runWithTemplate()
+	}
+
 	path := filepath.Join(o.ProjectDir, util.ProjectYAMLFile)
 	content := fmt.Sprintf(util.ProjectYAMLTemplate, o.Name)
 
@@ -78,3 +96,22 @@ func (o *Options) Run() error {
 
 	return nil
 }
+
+// runWithTemplate creates the project from the template.
+func (o *Options) runWithTemplate() error {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+	var prompt scaffold.PromptFunc
+	if !o.NoPrompt {
+		prompt = scaffold.TextInputPrompt(o.UI)
+	}
+	if err = scaffold.GenTemplateProject(o.Template, o.TemplateVersion, cfg.Templates, o.ProjectDir, o.Name, o.Values, prompt); err != nil {
+		return err
+	}
+
+	fmt.Printf("Created project '%s' from template '%s' successfully\n", o.Name, o.Template)
+
+	return nil
+}
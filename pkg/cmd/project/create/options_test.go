@@ -9,7 +9,10 @@ import (
 	"github.com/bytedance/mockey"
 	"github.com/stretchr/testify/assert"
 
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
 	"kusionstack.io/kusion/pkg/cmd/project/util"
+	"kusionstack.io/kusion/pkg/config"
+	"kusionstack.io/kusion/pkg/scaffold"
 )
 
 func TestOptions_Complete(t *testing.T) {
@@ -118,4 +121,17 @@ func TestOptions_Run(t *testing.T) {
 		assert.Nil(t, runErr)
 		assert.Equal(t, true, !os.IsNotExist(statErr))
 	})
+
+	t.Run("successfully create a new project from template", func(t *testing.T) {
+		mockey.PatchConvey("mock config.GetConfig and scaffold.GenTemplateProject", t, func() {
+			mockey.Mock(config.GetConfig).Return(&v1.Config{}, nil).Build()
+			mockey.Mock(scaffold.GenTemplateProject).Return(nil).Build()
+
+			opts := NewOptions()
+			opts.Flags.Template = "golden/web-service"
+			opts.Flags.NoPrompt = true
+			err := opts.Run()
+			assert.Nil(t, err)
+		})
+	})
 }
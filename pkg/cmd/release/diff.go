@@ -0,0 +1,264 @@
+package rel
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/liu-hm19/pterm"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	cmdutil "kusionstack.io/kusion/pkg/cmd/util"
+	"kusionstack.io/kusion/pkg/engine/release"
+	"kusionstack.io/kusion/pkg/project"
+	"kusionstack.io/kusion/pkg/util/i18n"
+)
+
+var (
+	diffShort = i18n.T("Show the differences between two releases of the current or specified stack")
+
+	diffLong = i18n.T(`
+	Show the differences between two releases of the current or specified stack.
+
+	This command compares the spec and state of each resource between two revisions of the releases of the current
+	or specified stack of the current project in the current or a specified workspace, and shows the added, removed
+	and modified resources. The revisions default to the latest two releases of the stack.
+	`)
+
+	diffExample = i18n.T(`
+	# Show the differences between the latest release and the one before it
+	kusion release diff
+
+	# Show the differences between two specific releases
+	kusion release diff --from=12 --to=17
+
+	# Show the differences between the latest two releases of the specified stack
+	kusion release diff --stack=dev
+
+	# Show the differences of the Kubernetes resources whose IDs contain Deployment
+	kusion release diff --from=12 --to=17 --type=Kubernetes --id=Deployment
+
+	# Show the differences as Markdown
+	kusion release diff --from=12 --to=17 --output=markdown
+	`)
+)
+
+// Output formats of the release diff
+const (
+	humanOutput    = "human"
+	markdownOutput = "markdown"
+)
+
+var diffOutputFormats = []string{humanOutput, jsonOutput, markdownOutput}
+
+// DiffFlags reflects the information that CLI is gathering via flags,
+// which will be converted into DiffOptions.
+type DiffFlags struct {
+	From      *uint64
+	To        *uint64
+	Types     []string
+	IDs       []string
+	Stack     *string
+	Project   *string
+	Workspace *string
+	Backend   *string
+	Output    string
+	NoStyle   bool
+
+	genericiooptions.IOStreams
+}
+
+// DiffOptions defines the configuration parameters for the `kusion release diff` command.
+type DiffOptions struct {
+	From           uint64
+	To             uint64
+	Filter         *release.DiffFilter
+	Stack          string
+	Project        string
+	Workspace      string
+	ReleaseStorage release.Storage
+	Output         string
+	NoStyle        bool
+
+	genericiooptions.IOStreams
+}
+
+// NewDiffFlags returns a default DiffFlags.
+func NewDiffFlags(streams genericiooptions.IOStreams) *DiffFlags {
+	from := uint64(0)
+	to := uint64(0)
+	stackName := ""
+	workspace := ""
+	projectName := ""
+	backendName := ""
+	return &DiffFlags{
+		From:      &from,
+		To:        &to,
+		Stack:     &stackName,
+		Project:   &projectName,
+		Workspace: &workspace,
+		Backend:   &backendName,
+		Output:    humanOutput,
+		IOStreams: streams,
+	}
+}
+
+// NewCmdDiff creates the `kusion release diff` command.
+func NewCmdDiff(streams genericiooptions.IOStreams) *cobra.Command {
+	flags := NewDiffFlags(streams)
+
+	cmd := &cobra.Command{
+		Use:     "diff",
+		Short:   diffShort,
+		Long:    templates.LongDesc(diffLong),
+		Example: templates.Examples(diffExample),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			o, err := flags.ToOptions()
+			defer cmdutil.RecoverErr(&err)
+			cmdutil.CheckErr(err)
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run())
+
+			return
+		},
+	}
+
+	flags.AddFlags(cmd)
+
+	return cmd
+}
+
+// AddFlags adds flags for a DiffOptions struct to the specified command.
+func (f *DiffFlags) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().Uint64VarP(f.From, "from", "", 0, i18n.T("The revision to compare from, default to the release of the stack before the revision to compare to"))
+	cmd.Flags().Uint64VarP(f.To, "to", "", 0, i18n.T("The revision to compare to, default to the latest release of the stack"))
+	cmd.Flags().StringSliceVarP(&f.Types, "type", "", nil, i18n.T("Only compare the resources of the types, such as Kubernetes and Terraform"))
+	cmd.Flags().StringSliceVarP(&f.IDs, "id", "", nil, i18n.T("Only compare the resources whose IDs contain any of the values"))
+	cmd.Flags().StringVarP(f.Stack, "stack", "", "", i18n.T("The stack name, default to the current stack"))
+	cmd.Flags().StringVarP(f.Project, "project", "", "", i18n.T("The project name"))
+	cmd.Flags().StringVarP(f.Workspace, "workspace", "", "", i18n.T("The workspace name"))
+	cmd.Flags().StringVarP(f.Backend, "backend", "", "", i18n.T("The backend to use, supports 'local', 'oss' and 's3'"))
+	cmd.Flags().StringVarP(&f.Output, "output", "o", f.Output, i18n.T("Specify the output format, one of human, json and markdown"))
+	cmd.Flags().BoolVarP(&f.NoStyle, "no-style", "", false, i18n.T("no-style sets to RawOutput mode and disables all of styling"))
+}
+
+// ToOptions converts DiffFlags to DiffOptions.
+func (f *DiffFlags) ToOptions() (*DiffOptions, error) {
+	storage, projectName, workspaceName, err := releaseStorage(f.Backend, f.Project, f.Workspace)
+	if err != nil {
+		return nil, err
+	}
+	stackName, err := releaseStack(f.Stack)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DiffOptions{
+		From:           *f.From,
+		To:             *f.To,
+		Filter:         &release.DiffFilter{Types: f.Types, IDs: f.IDs},
+		Stack:          stackName,
+		Project:        projectName,
+		Workspace:      workspaceName,
+		ReleaseStorage: storage,
+		Output:         f.Output,
+		NoStyle:        f.NoStyle,
+		IOStreams:      f.IOStreams,
+	}, nil
+}
+
+// Validate checks the provided options for the `kusion release diff` command.
+func (o *DiffOptions) Validate(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmdutil.UsageErrorf(cmd, "Unexpected args: %v", args)
+	}
+	if !slices.Contains(diffOutputFormats, o.Output) {
+		return cmdutil.UsageErrorf(cmd, "Invalid output format: %s, must be one of %s", o.Output, strings.Join(diffOutputFormats, ", "))
+	}
+
+	return nil
+}
+
+// Run executes the `kusion release diff` command.
+func (o *DiffOptions) Run() error {
+	if o.NoStyle || o.Output != humanOutput {
+		pterm.DisableStyling()
+	}
+
+	// Default to the latest release of the stack and the one before it.
+	revisions := o.ReleaseStorage.GetStackBoundRevisions(o.Stack)
+	to := o.To
+	if to == 0 && len(revisions) > 0 {
+		to = revisions[len(revisions)-1]
+	}
+	from := o.From
+	if from == 0 {
+		for _, revision := range revisions {
+			if revision < to {
+				from = revision
+			}
+		}
+	}
+	if from == 0 || to == 0 {
+		return fmt.Errorf("no two releases to compare found for project: %s, workspace: %s, stack: %s", o.Project, o.Workspace, o.Stack)
+	}
+
+	fromRelease, err := o.ReleaseStorage.Get(from)
+	if err != nil {
+		return fmt.Errorf("no release found for revision %d of project: %s, workspace: %s: %w", from, o.Project, o.Workspace, err)
+	}
+	toRelease, err := o.ReleaseStorage.Get(to)
+	if err != nil {
+		return fmt.Errorf("no release found for revision %d of project: %s, workspace: %s: %w", to, o.Project, o.Workspace, err)
+	}
+
+	for _, rel := range []*v1.Release{fromRelease, toRelease} {
+		if rel.Stack != o.Stack {
+			return fmt.Errorf("release of revision %d belongs to stack %s instead of %s", rel.Revision, rel.Stack, o.Stack)
+		}
+	}
+
+	d, err := release.Diff(fromRelease, toRelease, o.Filter)
+	if err != nil {
+		return err
+	}
+	return printDiff(o.Out, d, o.Output, o.NoStyle)
+}
+
+// releaseStack returns the name of the specified or current stack.
+func releaseStack(stackName *string) (string, error) {
+	if stackName != nil && *stackName != "" {
+		return *stackName, nil
+	}
+	_, currentStack, err := project.DetectProjectAndStacks()
+	if err != nil {
+		return "", err
+	}
+	return currentStack.Name, nil
+}
+
+// printDiff prints the differences in the format, which is one of diffOutputFormats.
+func printDiff(w io.Writer, d *release.ReleaseDiff, format string, noStyle bool) error {
+	var data string
+	var err error
+	switch format {
+	case jsonOutput:
+		var b []byte
+		b, err = json.MarshalIndent(d, "", "    ")
+		data = string(b) + "\n"
+	case markdownOutput:
+		data, err = d.Markdown()
+	default:
+		data, err = d.Human(noStyle)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, data)
+	return err
+}
@@ -0,0 +1,100 @@
+package rel
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/engine/release"
+	"kusionstack.io/kusion/pkg/engine/release/storages"
+)
+
+func newDiffOptions(t *testing.T) (*DiffOptions, *bytes.Buffer) {
+	storage, err := storages.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	// The release of revision 3 belongs to another stack.
+	for revision, data := range []string{"v1", "v2", "prod", "v3"} {
+		stack := "dev"
+		if data == "prod" {
+			stack = "prod"
+		}
+		cm := v1.Resource{ID: "v1:ConfigMap:default:foo", Type: v1.Kubernetes, Attributes: map[string]interface{}{"data": data}}
+		require.NoError(t, storage.Create(&v1.Release{
+			Project: "mock-project", Workspace: "mock-workspace", Revision: uint64(revision + 1), Stack: stack,
+			Spec: &v1.Spec{Resources: v1.Resources{cm}}, State: &v1.State{Resources: v1.Resources{cm}},
+			Phase: v1.ReleasePhaseSucceeded,
+		}))
+	}
+
+	out := &bytes.Buffer{}
+	return &DiffOptions{
+		Filter:         &release.DiffFilter{},
+		Stack:          "dev",
+		Project:        "mock-project",
+		Workspace:      "mock-workspace",
+		ReleaseStorage: storage,
+		Output:         humanOutput,
+		NoStyle:        true,
+		IOStreams:      genericiooptions.IOStreams{Out: out},
+	}, out
+}
+
+func TestDiffOptions_Validate(t *testing.T) {
+	cmd := NewCmdDiff(genericiooptions.IOStreams{})
+	opts := &DiffOptions{Output: humanOutput}
+	assert.NoError(t, opts.Validate(cmd, []string{}))
+	assert.Error(t, opts.Validate(cmd, []string{"invalid-args"}))
+
+	opts.Output = "yaml"
+	assert.Error(t, opts.Validate(cmd, []string{}))
+}
+
+func TestDiffOptions_Run(t *testing.T) {
+	t.Run("diff the latest two releases of the stack", func(t *testing.T) {
+		opts, out := newDiffOptions(t)
+		require.NoError(t, opts.Run())
+		assert.Contains(t, out.String(), "revision 2 -> 4")
+		assert.Contains(t, out.String(), "0 added, 0 removed, 1 modified, 0 unchanged")
+	})
+
+	t.Run("diff in json", func(t *testing.T) {
+		opts, out := newDiffOptions(t)
+		opts.From, opts.To, opts.Output = 1, 4, jsonOutput
+		require.NoError(t, opts.Run())
+		d := &release.ReleaseDiff{}
+		require.NoError(t, json.Unmarshal(out.Bytes(), d))
+		assert.Equal(t, uint64(1), d.From)
+		assert.Equal(t, release.DiffModified, d.Resources[0].Action)
+	})
+
+	t.Run("diff in markdown with filter", func(t *testing.T) {
+		opts, out := newDiffOptions(t)
+		opts.From, opts.Output = 1, markdownOutput
+		opts.Filter = &release.DiffFilter{Types: []string{"Terraform"}}
+		require.NoError(t, opts.Run())
+		assert.Contains(t, out.String(), "No diff found.")
+	})
+
+	t.Run("release of another stack", func(t *testing.T) {
+		opts, _ := newDiffOptions(t)
+		opts.From = 3
+		assert.ErrorContains(t, opts.Run(), "belongs to stack prod")
+	})
+
+	t.Run("no releases of the stack", func(t *testing.T) {
+		opts, _ := newDiffOptions(t)
+		opts.Stack = "staging"
+		assert.Error(t, opts.Run())
+	})
+
+	t.Run("release not found", func(t *testing.T) {
+		opts, _ := newDiffOptions(t)
+		opts.From, opts.To = 1, 5
+		assert.Error(t, opts.Run())
+	})
+}
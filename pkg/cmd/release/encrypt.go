@@ -0,0 +1,204 @@
+package rel
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/kubectl/pkg/util/templates"
+	"kusionstack.io/kusion/pkg/backend"
+	cmdutil "kusionstack.io/kusion/pkg/cmd/util"
+	"kusionstack.io/kusion/pkg/config"
+	"kusionstack.io/kusion/pkg/engine/release"
+	"kusionstack.io/kusion/pkg/util/i18n"
+	"kusionstack.io/kusion/pkg/workspace"
+)
+
+var (
+	encryptShort = i18n.T("Encrypt the release and workspace files in a backend")
+
+	encryptLong = i18n.T(`
+	Encrypt the release and workspace files in the current or a specified backend.
+
+	The client-side encryption of a backend is enabled by setting the key provider of the backend, after which
+	the release and workspace files are encrypted when written. This command rewrites the existing files of the
+	backend which are not encrypted with the current key, so that the files written before the encryption is
+	enabled get encrypted as well.
+
+	The command is also used to rotate the key. After adding a new key as the current one, and keeping the previous
+	keys to decrypt the existing files, run this command to re-encrypt all the files with the current key, then the
+	previous keys can be removed.
+	`)
+
+	encryptExample = i18n.T(`# Enable the encryption of the backend with a local key file, and encrypt the existing files.
+	kusion config set backends.dev.configs.encryptionKeyProvider local
+	kusion config set backends.dev.configs.encryptionKeyProviderOptions '{"keyFile":"/etc/kusion/backend.key"}'
+	kusion release encrypt --backend=dev
+
+	# Re-encrypt the files of the current backend after rotating the key.
+	kusion release encrypt
+`)
+)
+
+// EncryptFlags reflects the information that CLI is gathering via flags,
+// which will be converted into EncryptOptions.
+type EncryptFlags struct {
+	Backend *string
+}
+
+// EncryptOptions defines the configuration parameters for the `kusion release encrypt` command.
+type EncryptOptions struct {
+	Backend backend.Backend
+}
+
+// NewEncryptFlags returns a default EncryptFlags.
+func NewEncryptFlags(streams genericiooptions.IOStreams) *EncryptFlags {
+	backendName := ""
+	return &EncryptFlags{
+		Backend: &backendName,
+	}
+}
+
+// NewCmdEncrypt creates the `kusion release encrypt` command.
+func NewCmdEncrypt(streams genericiooptions.IOStreams) *cobra.Command {
+	flags := NewEncryptFlags(streams)
+
+	cmd := &cobra.Command{
+		Use:     "encrypt",
+		Short:   encryptShort,
+		Long:    templates.LongDesc(encryptLong),
+		Example: templates.Examples(encryptExample),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			o, err := flags.ToOptions()
+			defer cmdutil.RecoverErr(&err)
+			cmdutil.CheckErr(err)
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run())
+
+			return
+		},
+	}
+
+	flags.AddFlags(cmd)
+
+	return cmd
+}
+
+// AddFlags registers flags for the CLI.
+func (f *EncryptFlags) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(f.Backend, "backend", "", "", i18n.T("The backend to use, supports 'local', 'oss' and 's3'."))
+}
+
+// ToOptions converts from CLI inputs to runtime inputs.
+func (f *EncryptFlags) ToOptions() (*EncryptOptions, error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	name := *f.Backend
+	if name == "" {
+		name = cfg.Backends.Current
+	}
+	bkCfg := cfg.Backends.Backends[name]
+	if bkCfg == nil {
+		return nil, fmt.Errorf("config of backend %s does not exist", name)
+	}
+	if bkCfg.ToEncryptionConfig() == nil {
+		return nil, fmt.Errorf("the encryption of backend %s is not enabled, please set the encryptionKeyProvider of the backend first", name)
+	}
+
+	bk, err := backend.NewBackend(name)
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptOptions{Backend: bk}, nil
+}
+
+// Validate verifies if EncryptOptions are valid and without conflicts.
+func (o *EncryptOptions) Validate(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmdutil.UsageErrorf(cmd, "Unexpected args: %v", args)
+	}
+
+	return nil
+}
+
+// Run executes the `kusion release encrypt` command.
+func (o *EncryptOptions) Run() error {
+	// Rewrite the release files of all the projects and workspaces which are not encrypted with the current key.
+	projects, err := o.Backend.ProjectStorage()
+	if err != nil {
+		return err
+	}
+	workspaces := make([]string, 0, len(projects))
+	for ws := range projects {
+		workspaces = append(workspaces, ws)
+	}
+	sort.Strings(workspaces)
+
+	releaseCount, skipped := 0, 0
+	for _, ws := range workspaces {
+		for _, project := range projects[ws] {
+			storage, err := o.Backend.ReleaseStorage(project, ws)
+			if err != nil {
+				return err
+			}
+			for _, revision := range storage.GetRevisions() {
+				if rotator, ok := storage.(release.Rotator); ok {
+					needsRotation, err := rotator.NeedsRotation(revision)
+					if err != nil {
+						return fmt.Errorf("read release of project %s, workspace %s, revision %d failed: %w", project, ws, revision, err)
+					}
+					if !needsRotation {
+						skipped++
+						continue
+					}
+				}
+				r, err := storage.Get(revision)
+				if err != nil {
+					return fmt.Errorf("get release of project %s, workspace %s, revision %d failed: %w", project, ws, revision, err)
+				}
+				if err = storage.Update(r); err != nil {
+					return fmt.Errorf("encrypt release of project %s, workspace %s, revision %d failed: %w", project, ws, revision, err)
+				}
+				releaseCount++
+			}
+		}
+	}
+
+	// Rewrite the workspace files which are not encrypted with the current key.
+	wsStorage, err := o.Backend.WorkspaceStorage()
+	if err != nil {
+		return err
+	}
+	names, err := wsStorage.GetNames()
+	if err != nil {
+		return err
+	}
+	workspaceCount := 0
+	for _, name := range names {
+		if rotator, ok := wsStorage.(workspace.Rotator); ok {
+			needsRotation, err := rotator.NeedsRotation(name)
+			if err != nil {
+				return fmt.Errorf("read workspace %s failed: %w", name, err)
+			}
+			if !needsRotation {
+				skipped++
+				continue
+			}
+		}
+		ws, err := wsStorage.Get(name)
+		if err != nil {
+			return fmt.Errorf("get workspace %s failed: %w", name, err)
+		}
+		if err = wsStorage.Update(ws); err != nil {
+			return fmt.Errorf("encrypt workspace %s failed: %w", name, err)
+		}
+		workspaceCount++
+	}
+
+	fmt.Printf("Successfully encrypted %d release files and %d workspace files, skipped %d files already encrypted with the current key\n",
+		releaseCount, workspaceCount, skipped)
+	return nil
+}
@@ -0,0 +1,77 @@
+package rel
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/backend/storages"
+	"kusionstack.io/kusion/pkg/infra/encryption"
+)
+
+func TestEncryptOptions_Validate(t *testing.T) {
+	opts := &EncryptOptions{}
+	cmd := NewCmdEncrypt(genericiooptions.IOStreams{})
+
+	assert.NoError(t, opts.Validate(cmd, []string{}))
+	assert.Error(t, opts.Validate(cmd, []string{"invalid-args"}))
+}
+
+func TestEncryptOptions_Run(t *testing.T) {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	t.Setenv(encryption.DefaultKeyEnvVar, base64.StdEncoding.EncodeToString(key))
+
+	// Write a release and a workspace before the encryption is enabled.
+	path := t.TempDir()
+	plain, err := storages.NewLocalStorage(&v1.BackendLocalConfig{Path: path})
+	require.NoError(t, err)
+	releaseStorage, err := plain.ReleaseStorage("foo", "dev")
+	require.NoError(t, err)
+	require.NoError(t, releaseStorage.Create(&v1.Release{Project: "foo", Workspace: "dev", Revision: 1, Stack: "dev"}))
+	workspaceStorage, err := plain.WorkspaceStorage()
+	require.NoError(t, err)
+	require.NoError(t, workspaceStorage.Create(&v1.Workspace{Name: "dev"}))
+
+	encrypted, err := storages.NewLocalStorage(&v1.BackendLocalConfig{
+		Path:       path,
+		Encryption: &v1.BackendEncryptionConfig{KeyProvider: encryption.EnvKeyProviderName},
+	})
+	require.NoError(t, err)
+	o := &EncryptOptions{Backend: encrypted}
+	require.NoError(t, o.Run())
+
+	files := []string{
+		filepath.Join(path, "releases", "foo", "dev", "1.yaml"),
+		filepath.Join(path, "workspaces", "dev.yaml"),
+		filepath.Join(path, "workspaces", "default.yaml"),
+	}
+	contents := make(map[string][]byte)
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.True(t, encryption.IsEncrypted(strings.TrimSpace(string(content))), file)
+		contents[file] = content
+	}
+
+	// The files already encrypted with the current key are not rewritten.
+	require.NoError(t, o.Run())
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, contents[file], content, file)
+	}
+
+	releaseStorage, err = encrypted.ReleaseStorage("foo", "dev")
+	require.NoError(t, err)
+	r, err := releaseStorage.Get(1)
+	require.NoError(t, err)
+	assert.Equal(t, "dev", r.Stack)
+}
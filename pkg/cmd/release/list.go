@@ -1,13 +1,22 @@
 package rel
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/kubectl/pkg/util/templates"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
 	"kusionstack.io/kusion/pkg/cmd/meta"
 	cmdutil "kusionstack.io/kusion/pkg/cmd/util"
+	"kusionstack.io/kusion/pkg/engine/release"
 	"kusionstack.io/kusion/pkg/util/i18n"
 )
 
@@ -18,7 +27,11 @@ var (
     List all releases of the current stack.
 
     This command displays information about all releases of the current stack in the current or a specified workspace,
-    including their revision, phase, and creation time.
+    including their revision, stack, phase, creation time, duration, the numbers of the created, updated and deleted
+    resources, operator and the git commit of the stack source.
+
+    The releases can be filtered by stack, phase and creation time, and paginated by limit and offset. The time of
+    --since and --until is either an RFC3339 time, a date such as 2024-01-02, or a duration before now such as 24h.
     `)
 
 	listExample = i18n.T(`
@@ -27,18 +40,47 @@ var (
 
     # List all releases of the current stack in a specified workspace
     kusion release list --workspace=dev
+
+    # List the failed releases of the stack dev created within one week
+    kusion release list --stack=dev --phase=failed --since=168h
+
+    # List the latest 10 releases in json format
+    kusion release list --reverse --limit=10 --output=json
     `)
 )
 
+const yamlOutput = "yaml"
+
 // ListFlags reflects the information that CLI is gathering via flags,
 // which will be converted into ListOptions.
 type ListFlags struct {
 	MetaFlags *meta.MetaFlags
+
+	Stack   string
+	Phases  []string
+	Since   string
+	Until   string
+	Limit   int
+	Offset  int
+	Reverse bool
+	Output  string
 }
 
 // ListOptions defines the configuration parameters for the `kusion release list` command.
 type ListOptions struct {
 	*meta.MetaOptions
+
+	Stack   string
+	Phases  []string
+	Since   string
+	Until   string
+	Limit   int
+	Offset  int
+	Reverse bool
+	Output  string
+
+	// Filter is built from the stack, phases and time range when validating.
+	Filter *release.SummaryFilter
 }
 
 // NewListFlags returns a default ListFlags.
@@ -76,6 +118,15 @@ func NewCmdList(streams genericiooptions.IOStreams) *cobra.Command {
 // AddFlags registers flags for the CLI.
 func (f *ListFlags) AddFlags(cmd *cobra.Command) {
 	f.MetaFlags.AddFlags(cmd)
+
+	cmd.Flags().StringVarP(&f.Stack, "stack", "", "", i18n.T("List the releases of the specified stack only"))
+	cmd.Flags().StringSliceVarP(&f.Phases, "phase", "", nil, i18n.T("List the releases in the specified phases only, such as succeeded,failed"))
+	cmd.Flags().StringVarP(&f.Since, "since", "", "", i18n.T("List the releases created at or after the time, an RFC3339 time, a date or a duration before now"))
+	cmd.Flags().StringVarP(&f.Until, "until", "", "", i18n.T("List the releases created before the time, an RFC3339 time, a date or a duration before now"))
+	cmd.Flags().IntVarP(&f.Limit, "limit", "", 0, i18n.T("The maximum number of releases to list, 0 means no limit"))
+	cmd.Flags().IntVarP(&f.Offset, "offset", "", 0, i18n.T("The number of releases to skip"))
+	cmd.Flags().BoolVarP(&f.Reverse, "reverse", "", false, i18n.T("List the releases from the latest to the earliest"))
+	cmd.Flags().StringVarP(&f.Output, "output", "o", "", i18n.T("Specify the output format, supports 'json' and 'yaml', a table by default"))
 }
 
 // ToOptions converts from CLI inputs to runtime inputs.
@@ -87,6 +138,14 @@ func (f *ListFlags) ToOptions() (*ListOptions, error) {
 
 	o := &ListOptions{
 		MetaOptions: metaOpts,
+		Stack:       f.Stack,
+		Phases:      f.Phases,
+		Since:       f.Since,
+		Until:       f.Until,
+		Limit:       f.Limit,
+		Offset:      f.Offset,
+		Reverse:     f.Reverse,
+		Output:      f.Output,
 	}
 
 	return o, nil
@@ -97,6 +156,30 @@ func (o *ListOptions) Validate(cmd *cobra.Command, args []string) error {
 	if len(args) != 0 {
 		return cmdutil.UsageErrorf(cmd, "Unexpected args: %v", args)
 	}
+	if o.Output != "" && o.Output != jsonOutput && o.Output != yamlOutput {
+		return cmdutil.UsageErrorf(cmd, "Invalid output format %s, supports 'json' and 'yaml'", o.Output)
+	}
+	if o.Limit < 0 || o.Offset < 0 {
+		return cmdutil.UsageErrorf(cmd, "Limit and offset must not be negative")
+	}
+
+	filter := &release.SummaryFilter{Stack: o.Stack}
+	for _, phase := range o.Phases {
+		p := v1.ReleasePhase(strings.ToLower(strings.TrimSpace(phase)))
+		if !validReleasePhase(p) {
+			return cmdutil.UsageErrorf(cmd, "Invalid release phase %s", phase)
+		}
+		filter.Phases = append(filter.Phases, p)
+	}
+	now := time.Now()
+	var err error
+	if filter.Since, err = parseListTime(o.Since, now); err != nil {
+		return cmdutil.UsageErrorf(cmd, "Invalid since: %v", err)
+	}
+	if filter.Until, err = parseListTime(o.Until, now); err != nil {
+		return cmdutil.UsageErrorf(cmd, "Invalid until: %v", err)
+	}
+	o.Filter = filter
 
 	return nil
 }
@@ -109,9 +192,31 @@ func (o *ListOptions) Run() error {
 		return err
 	}
 
-	// Get all releases.
-	releases := storage.GetRevisions()
-	if len(releases) == 0 {
+	// Get the summaries of the selected releases, without reading the releases if the storage keeps them.
+	summaries, err := release.ListSummaries(storage, o.Filter)
+	if err != nil {
+		return err
+	}
+	summaries = paginateSummaries(summaries, o.Reverse, o.Offset, o.Limit)
+
+	switch o.Output {
+	case jsonOutput:
+		data, err := json.MarshalIndent(summaries, "", "    ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	case yamlOutput:
+		data, err := yaml.Marshal(summaries)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(summaries) == 0 {
 		fmt.Printf("No releases found for project: %s, workspace: %s\n",
 			o.RefProject.Name, o.RefWorkspace.Name)
 		return nil
@@ -119,15 +224,102 @@ func (o *ListOptions) Run() error {
 
 	// Print the releases
 	fmt.Printf("Releases for project: %s, workspace: %s\n\n", o.RefProject.Name, o.RefWorkspace.Name)
-	fmt.Printf("%-10s %-15s %-30s\n", "Revision", "Phase", "Creation Time")
-	fmt.Println("------------------------------------------------------")
-	for _, revision := range releases {
-		r, err := storage.Get(revision)
-		if err != nil {
-			return err
+	printSummaries(os.Stdout, summaries)
+
+	return nil
+}
+
+// validReleasePhase returns whether the phase is a phase of the Release.
+func validReleasePhase(phase v1.ReleasePhase) bool {
+	switch phase {
+	case v1.ReleasePhaseGenerating, v1.ReleasePhasePreviewing, v1.ReleasePhaseApplying,
+		v1.ReleasePhaseDestroying, v1.ReleasePhaseSucceeded, v1.ReleasePhaseFailed:
+		return true
+	}
+	return false
+}
+
+// parseListTime parses the value of --since or --until, which is either an RFC3339 time, a date, or a duration
+// before now. An empty value results in the zero time.
+func parseListTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return t, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s is neither an RFC3339 time, a date nor a duration", value)
+	}
+	return now.Add(-d), nil
+}
+
+// paginateSummaries returns the page of the summaries, which are reversed first if required.
+func paginateSummaries(summaries []*v1.ReleaseSummary, reverse bool, offset, limit int) []*v1.ReleaseSummary {
+	if reverse {
+		reversed := make([]*v1.ReleaseSummary, len(summaries))
+		for i, s := range summaries {
+			reversed[len(summaries)-1-i] = s
 		}
-		fmt.Printf("%-10d %-15s %-30s\n", r.Revision, string(r.Phase), r.CreateTime.Format("2006-01-02 15:04:05"))
+		summaries = reversed
+	}
+	if offset >= len(summaries) {
+		return []*v1.ReleaseSummary{}
+	}
+	summaries = summaries[offset:]
+	if limit > 0 && limit < len(summaries) {
+		summaries = summaries[:limit]
 	}
+	return summaries
+}
 
-	return nil
+// printSummaries prints the summaries of the releases as a table.
+func printSummaries(w io.Writer, summaries []*v1.ReleaseSummary) {
+	format := "%-10s %-15s %-12s %-22s %-10s %-16s %-20s %-18s\n"
+	fmt.Fprintf(w, format, "Revision", "Stack", "Phase", "Creation Time", "Duration", "Resources", "Operator", "Commit")
+	fmt.Fprintln(w, strings.Repeat("-", 128))
+	for _, s := range summaries {
+		operator := s.Operator
+		if operator == "" {
+			operator = "-"
+		}
+		fmt.Fprintf(w, format, fmt.Sprint(s.Revision), s.Stack, string(s.Phase), s.CreateTime.Format("2006-01-02 15:04:05"),
+			releaseDuration(s), resourceColumn(s.Actions), operator, shortCommit(s.Commit, s.Dirty))
+	}
+}
+
+// releaseDuration returns the duration from the creation to the completion of the release, "-" if the release
+// is not completed.
+func releaseDuration(s *v1.ReleaseSummary) string {
+	if (s.Phase != v1.ReleasePhaseSucceeded && s.Phase != v1.ReleasePhaseFailed) ||
+		s.CreateTime.IsZero() || s.ModifiedTime.Before(s.CreateTime) {
+		return "-"
+	}
+	return s.ModifiedTime.Sub(s.CreateTime).Round(time.Second).String()
+}
+
+// resourceColumn returns the numbers of the created, updated and deleted resources, such as "+1 ~2 -0".
+func resourceColumn(actions *v1.ReleaseActions) string {
+	if actions == nil {
+		return "-"
+	}
+	return fmt.Sprintf("+%d ~%d -%d", actions.Create, actions.Update, actions.Delete)
+}
+
+// shortCommit returns the short git commit, which is marked if the stack source had uncommitted changes.
+func shortCommit(commit string, dirty bool) string {
+	if commit == "" {
+		return "-"
+	}
+	if len(commit) > 8 {
+		commit = commit[:8]
+	}
+	if dirty {
+		commit += " (dirty)"
+	}
+	return commit
 }
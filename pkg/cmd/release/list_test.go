@@ -1,11 +1,16 @@
 package rel
 
 import (
+	"bytes"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/bytedance/mockey"
 	"github.com/stretchr/testify/assert"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
 	"kusionstack.io/kusion/pkg/cmd/meta"
 	"kusionstack.io/kusion/pkg/engine/release"
@@ -141,3 +146,92 @@ func (f *fakeStorageForList) Update(release *v1.Release) error {
 func (f *fakeStorageForList) GetStackBoundRevisions(stack string) []uint64 {
 	return f.revisions
 }
+
+func TestListOptions_Validate(t *testing.T) {
+	cmd := NewCmdList(genericiooptions.IOStreams{})
+
+	o := &ListOptions{Stack: "dev", Phases: []string{"Succeeded", "failed"}, Since: "2024-01-02T00:00:00Z", Until: "24h"}
+	assert.NoError(t, o.Validate(cmd, nil))
+	assert.Equal(t, "dev", o.Filter.Stack)
+	assert.Equal(t, []v1.ReleasePhase{v1.ReleasePhaseSucceeded, v1.ReleasePhaseFailed}, o.Filter.Phases)
+	assert.True(t, o.Filter.Since.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, o.Filter.Until.IsZero())
+
+	assert.Error(t, (&ListOptions{}).Validate(cmd, []string{"arg"}))
+	assert.Error(t, (&ListOptions{Output: "table"}).Validate(cmd, nil))
+	assert.Error(t, (&ListOptions{Limit: -1}).Validate(cmd, nil))
+	assert.Error(t, (&ListOptions{Phases: []string{"unknown"}}).Validate(cmd, nil))
+	assert.Error(t, (&ListOptions{Since: "yesterday"}).Validate(cmd, nil))
+}
+
+func TestParseListTime(t *testing.T) {
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	got, err := parseListTime("", now)
+	assert.NoError(t, err)
+	assert.True(t, got.IsZero())
+
+	got, err = parseListTime("2h", now)
+	assert.NoError(t, err)
+	assert.True(t, got.Equal(now.Add(-2*time.Hour)))
+
+	got, err = parseListTime("2024-01-01", now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, got.Day())
+
+	_, err = parseListTime("invalid", now)
+	assert.Error(t, err)
+}
+
+func TestPaginateSummaries(t *testing.T) {
+	summaries := []*v1.ReleaseSummary{{Revision: 1}, {Revision: 2}, {Revision: 3}, {Revision: 4}}
+	revisions := func(ss []*v1.ReleaseSummary) []uint64 {
+		var rs []uint64
+		for _, s := range ss {
+			rs = append(rs, s.Revision)
+		}
+		return rs
+	}
+
+	assert.Equal(t, []uint64{1, 2, 3, 4}, revisions(paginateSummaries(summaries, false, 0, 0)))
+	assert.Equal(t, []uint64{2, 3}, revisions(paginateSummaries(summaries, false, 1, 2)))
+	assert.Equal(t, []uint64{4, 3}, revisions(paginateSummaries(summaries, true, 0, 2)))
+	assert.Empty(t, paginateSummaries(summaries, false, 4, 0))
+}
+
+func TestPrintSummaries(t *testing.T) {
+	created := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	summaries := []*v1.ReleaseSummary{
+		{
+			Revision:     1,
+			Stack:        "dev",
+			Phase:        v1.ReleasePhaseSucceeded,
+			CreateTime:   created,
+			ModifiedTime: created.Add(90 * time.Second),
+			Operator:     "alice",
+			Commit:       "3836f8770f8b4c41a1b4e1c9c62b23f3b5c21c4b",
+			Dirty:        true,
+			Actions:      &v1.ReleaseActions{Create: 2, Update: 1},
+		},
+		{
+			Revision:   2,
+			Stack:      "dev",
+			Phase:      v1.ReleasePhaseApplying,
+			CreateTime: created.Add(time.Hour),
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	printSummaries(buf, summaries)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 4)
+	assert.Equal(t, []string{"1", "dev", "succeeded", "2024-01-02", "12:00:00", "1m30s", "+2", "~1", "-0", "alice", "3836f877", "(dirty)"},
+		strings.Fields(lines[2]))
+	assert.Equal(t, []string{"2", "dev", "applying", "2024-01-02", "13:00:00", "-", "-", "-", "-"}, strings.Fields(lines[3]))
+}
+
+func TestShortCommit(t *testing.T) {
+	assert.Equal(t, "-", shortCommit("", true))
+	assert.Equal(t, "3836f877", shortCommit("3836f8770f8b4c41a1b4e1c9c62b23f3b5c21c4b", false))
+	assert.Equal(t, "3836f877 (dirty)", shortCommit("3836f8770f8b4c41a1b4e1c9c62b23f3b5c21c4b", true))
+}
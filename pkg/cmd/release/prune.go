@@ -0,0 +1,259 @@
+package rel
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/backend"
+	cmdutil "kusionstack.io/kusion/pkg/cmd/util"
+	"kusionstack.io/kusion/pkg/config"
+	"kusionstack.io/kusion/pkg/engine/release"
+	"kusionstack.io/kusion/pkg/util/i18n"
+)
+
+var (
+	pruneShort = i18n.T("Prune the release history of the current or specified stack")
+
+	pruneLong = i18n.T(`
+	Prune the release history of the current or specified stack under the retention policy.
+
+	The releases neither of the last N nor created within the duration are deleted, and the metadata of the
+	releases gets compacted. The latest release, the latest release of each stack, and the releases in progress
+	are always kept.
+
+	The retention policy is specified by the flags, or configured in the workspace context with the key
+	releaseRetention, or configured in the backend with the config items releaseRetentionKeepLast and
+	releaseRetentionKeepWithin, in the order of precedence.
+	`)
+
+	pruneExample = i18n.T(`
+	# Preview the releases to prune under the configured retention policy
+	kusion release prune --dry-run
+
+	# Keep the last 20 releases and the ones created within 30 days
+	kusion release prune --keep-last=20 --keep-within=720h
+
+	# Prune the releases of all the projects and workspaces in the backend
+	kusion config set backends.dev.configs.releaseRetentionKeepLast 50
+	kusion release prune --backend=dev --all
+	`)
+)
+
+// PruneFlags reflects the information that CLI is gathering via flags,
+// which will be converted into PruneOptions.
+type PruneFlags struct {
+	KeepLast   int
+	KeepWithin string
+	DryRun     bool
+	All        bool
+	Project    *string
+	Workspace  *string
+	Backend    *string
+
+	genericiooptions.IOStreams
+}
+
+// PruneOptions defines the configuration parameters for the `kusion release prune` command.
+type PruneOptions struct {
+	// Retention is the retention policy specified by the flags, which overrides the configured ones.
+	Retention *v1.ReleaseRetention
+	// BackendRetention is the retention policy configured in the backend.
+	BackendRetention *v1.ReleaseRetention
+	DryRun           bool
+	All              bool
+	Project          string
+	Workspace        string
+	Backend          backend.Backend
+
+	genericiooptions.IOStreams
+}
+
+// NewPruneFlags returns a default PruneFlags.
+func NewPruneFlags(streams genericiooptions.IOStreams) *PruneFlags {
+	workspace := ""
+	projectName := ""
+	backendName := ""
+	return &PruneFlags{
+		Project:   &projectName,
+		Workspace: &workspace,
+		Backend:   &backendName,
+		IOStreams: streams,
+	}
+}
+
+// NewCmdPrune creates the `kusion release prune` command.
+func NewCmdPrune(streams genericiooptions.IOStreams) *cobra.Command {
+	flags := NewPruneFlags(streams)
+
+	cmd := &cobra.Command{
+		Use:     "prune",
+		Short:   pruneShort,
+		Long:    templates.LongDesc(pruneLong),
+		Example: templates.Examples(pruneExample),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			o, err := flags.ToOptions()
+			defer cmdutil.RecoverErr(&err)
+			cmdutil.CheckErr(err)
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run())
+
+			return
+		},
+	}
+
+	flags.AddFlags(cmd)
+
+	return cmd
+}
+
+// AddFlags adds flags for a PruneOptions struct to the specified command.
+func (f *PruneFlags) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVarP(&f.KeepLast, "keep-last", "", 0, i18n.T("The number of the latest releases to keep"))
+	cmd.Flags().StringVarP(&f.KeepWithin, "keep-within", "", "", i18n.T("Keep the releases created within the duration, such as 720h"))
+	cmd.Flags().BoolVarP(&f.DryRun, "dry-run", "", false, i18n.T("Only show the releases to prune without deleting them"))
+	cmd.Flags().BoolVarP(&f.All, "all", "", false, i18n.T("Prune the releases of all the projects and workspaces in the backend"))
+	cmd.Flags().StringVarP(f.Project, "project", "", "", i18n.T("The project name"))
+	cmd.Flags().StringVarP(f.Workspace, "workspace", "", "", i18n.T("The workspace name"))
+	cmd.Flags().StringVarP(f.Backend, "backend", "", "", i18n.T("The backend to use, supports 'local', 'oss' and 's3'"))
+}
+
+// ToOptions converts PruneFlags to PruneOptions.
+func (f *PruneFlags) ToOptions() (*PruneOptions, error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	name := *f.Backend
+	if name == "" {
+		name = cfg.Backends.Current
+	}
+	bkCfg := cfg.Backends.Backends[name]
+	if bkCfg == nil {
+		return nil, fmt.Errorf("config of backend %s does not exist", name)
+	}
+	bk, err := backend.NewBackend(name)
+	if err != nil {
+		return nil, err
+	}
+
+	o := &PruneOptions{
+		BackendRetention: bkCfg.ToReleaseRetention(),
+		DryRun:           f.DryRun,
+		All:              f.All,
+		Backend:          bk,
+		IOStreams:        f.IOStreams,
+	}
+	if f.KeepLast != 0 || f.KeepWithin != "" {
+		o.Retention = &v1.ReleaseRetention{KeepLast: f.KeepLast, KeepWithin: f.KeepWithin}
+	}
+	if !f.All {
+		if o.Project, o.Workspace, err = releaseTarget(bk, f.Project, f.Workspace); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// Validate checks the provided options for the `kusion release prune` command.
+func (o *PruneOptions) Validate(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmdutil.UsageErrorf(cmd, "Unexpected args: %v", args)
+	}
+	if o.Retention != nil {
+		if err := release.ValidateRetention(o.Retention); err != nil {
+			return cmdutil.UsageErrorf(cmd, "Invalid retention policy: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// Run executes the `kusion release prune` command.
+func (o *PruneOptions) Run() error {
+	if !o.All {
+		retention, err := o.retention(o.Workspace)
+		if err != nil {
+			return err
+		}
+		if retention == nil {
+			return fmt.Errorf("no release retention policy found for workspace %s, please specify it by --keep-last or --keep-within", o.Workspace)
+		}
+		return o.prune(o.Project, o.Workspace, retention)
+	}
+
+	projects, err := o.Backend.ProjectStorage()
+	if err != nil {
+		return err
+	}
+	workspaces := make([]string, 0, len(projects))
+	for ws := range projects {
+		workspaces = append(workspaces, ws)
+	}
+	sort.Strings(workspaces)
+
+	for _, ws := range workspaces {
+		retention, err := o.retention(ws)
+		if err != nil {
+			return err
+		}
+		if retention == nil {
+			fmt.Fprintf(o.Out, "Skip workspace %s without release retention policy\n", ws)
+			continue
+		}
+		for _, project := range projects[ws] {
+			if err = o.prune(project, ws, retention); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// retention returns the retention policy of the workspace, which is the one specified by the flags, or configured
+// in the workspace, or configured in the backend, nil if none.
+func (o *PruneOptions) retention(workspace string) (*v1.ReleaseRetention, error) {
+	if o.Retention != nil {
+		return o.Retention, nil
+	}
+	wsStorage, err := o.Backend.WorkspaceStorage()
+	if err != nil {
+		return nil, err
+	}
+	ws, err := wsStorage.Get(workspace)
+	if err != nil {
+		return nil, fmt.Errorf("get workspace %s failed: %w", workspace, err)
+	}
+	retention, err := release.RetentionFromWorkspace(ws)
+	if err != nil || retention != nil {
+		return retention, err
+	}
+	return o.BackendRetention, nil
+}
+
+// prune prunes the releases of the project in the workspace under the retention policy.
+func (o *PruneOptions) prune(project, workspace string, retention *v1.ReleaseRetention) error {
+	storage, err := o.Backend.ReleaseStorage(project, workspace)
+	if err != nil {
+		return err
+	}
+	pruned, err := release.Prune(storage, retention, time.Now(), o.DryRun)
+	if err != nil {
+		return fmt.Errorf("prune releases of project %s, workspace %s failed: %w", project, workspace, err)
+	}
+
+	switch {
+	case len(pruned) == 0:
+		fmt.Fprintf(o.Out, "No releases to prune for project: %s, workspace: %s\n", project, workspace)
+	case o.DryRun:
+		fmt.Fprintf(o.Out, "Releases to prune for project: %s, workspace: %s: %v\n", project, workspace, pruned)
+	default:
+		fmt.Fprintf(o.Out, "Pruned %d releases for project: %s, workspace: %s: %v\n", len(pruned), project, workspace, pruned)
+	}
+	return nil
+}
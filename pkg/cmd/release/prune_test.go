@@ -0,0 +1,93 @@
+package rel
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/backend/storages"
+)
+
+func newPruneOptions(t *testing.T) (*PruneOptions, *bytes.Buffer) {
+	bk, err := storages.NewLocalStorage(&v1.BackendLocalConfig{Path: t.TempDir()})
+	require.NoError(t, err)
+	wsStorage, err := bk.WorkspaceStorage()
+	require.NoError(t, err)
+	require.NoError(t, wsStorage.Create(&v1.Workspace{Name: "dev", Context: v1.GenericConfig{
+		v1.ReleaseRetentionKey: map[string]any{"keepLast": 2},
+	}}))
+	require.NoError(t, wsStorage.Create(&v1.Workspace{Name: "prod"}))
+
+	for _, ws := range []string{"dev", "prod"} {
+		storage, err := bk.ReleaseStorage("mock-project", ws)
+		require.NoError(t, err)
+		for revision := uint64(1); revision <= 4; revision++ {
+			require.NoError(t, storage.Create(&v1.Release{
+				Project: "mock-project", Workspace: ws, Revision: revision, Stack: "dev",
+				Phase: v1.ReleasePhaseSucceeded, CreateTime: time.Now(),
+			}))
+		}
+	}
+
+	out := &bytes.Buffer{}
+	return &PruneOptions{
+		Project:   "mock-project",
+		Workspace: "dev",
+		Backend:   bk,
+		IOStreams: genericiooptions.IOStreams{Out: out},
+	}, out
+}
+
+func TestPruneOptions_Validate(t *testing.T) {
+	cmd := NewCmdPrune(genericiooptions.IOStreams{})
+	opts := &PruneOptions{}
+	assert.NoError(t, opts.Validate(cmd, []string{}))
+	assert.Error(t, opts.Validate(cmd, []string{"invalid-args"}))
+
+	opts.Retention = &v1.ReleaseRetention{KeepWithin: "30d"}
+	assert.Error(t, opts.Validate(cmd, []string{}))
+}
+
+func TestPruneOptions_Run(t *testing.T) {
+	t.Run("prune under the workspace retention", func(t *testing.T) {
+		opts, out := newPruneOptions(t)
+		opts.DryRun = true
+		require.NoError(t, opts.Run())
+		assert.Contains(t, out.String(), "Releases to prune for project: mock-project, workspace: dev: [1 2]")
+
+		opts.DryRun = false
+		require.NoError(t, opts.Run())
+		storage, err := opts.Backend.ReleaseStorage("mock-project", "dev")
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{3, 4}, storage.GetRevisions())
+	})
+
+	t.Run("flags override the configured retention", func(t *testing.T) {
+		opts, out := newPruneOptions(t)
+		opts.Retention = &v1.ReleaseRetention{KeepLast: 1}
+		require.NoError(t, opts.Run())
+		assert.Contains(t, out.String(), "Pruned 3 releases for project: mock-project, workspace: dev: [1 2 3]")
+	})
+
+	t.Run("no retention configured", func(t *testing.T) {
+		opts, _ := newPruneOptions(t)
+		opts.Workspace = "prod"
+		assert.Error(t, opts.Run())
+
+		opts.BackendRetention = &v1.ReleaseRetention{KeepLast: 3}
+		require.NoError(t, opts.Run())
+	})
+
+	t.Run("prune all the projects and workspaces", func(t *testing.T) {
+		opts, out := newPruneOptions(t)
+		opts.All = true
+		require.NoError(t, opts.Run())
+		assert.Contains(t, out.String(), "Pruned 2 releases for project: mock-project, workspace: dev")
+		assert.Contains(t, out.String(), "Skip workspace prod without release retention policy")
+	})
+}
@@ -23,7 +23,8 @@ func NewCmdRel(streams genericiooptions.IOStreams) *cobra.Command {
 		Run:                   cmdutil.DefaultSubCommandRun(streams.ErrOut),
 	}
 
-	cmd.AddCommand(NewCmdUnlock(streams), NewCmdList(streams), NewCmdShow(streams))
+	cmd.AddCommand(NewCmdUnlock(streams), NewCmdList(streams), NewCmdShow(streams), NewCmdDiff(streams), NewCmdEncrypt(streams),
+		NewCmdPrune(streams))
 
 	return cmd
 }
@@ -130,62 +130,77 @@ func (f *ShowFlags) AddFlags(cmd *cobra.Command) {
 
 // ToOptions converts ShowFlags to ShowOptions.
 func (f *ShowFlags) ToOptions() (*ShowOptions, error) {
-	var storageBackend backend.Backend
-	var err error
-	if f.Backend != nil && *f.Backend != "" {
-		storageBackend, err = backend.NewBackend(*f.Backend)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		storageBackend, err = backend.NewBackend("")
-		if err != nil {
-			return nil, err
-		}
+	storage, projectName, workspaceName, err := releaseStorage(f.Backend, f.Project, f.Workspace)
+	if err != nil {
+		return nil, err
 	}
 
-	workspaceName := ""
-	projectName := ""
+	return &ShowOptions{
+		Revision:       f.Revision,
+		Output:         f.Output,
+		Project:        &projectName,
+		Workspace:      &workspaceName,
+		ReleaseStorage: storage,
+	}, nil
+}
+
+// releaseStorage returns the release storage of the specified or current project in the specified or current
+// workspace, together with the names of them.
+func releaseStorage(backendName, projectName, workspaceName *string) (release.Storage, string, string, error) {
+	name := ""
+	if backendName != nil {
+		name = *backendName
+	}
+	storageBackend, err := backend.NewBackend(name)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	proj, workspace, err := releaseTarget(storageBackend, projectName, workspaceName)
+	if err != nil {
+		return nil, "", "", err
+	}
+	storage, err := storageBackend.ReleaseStorage(proj, workspace)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return storage, proj, workspace, nil
+}
+
+// releaseTarget returns the names of the specified or current project and the specified or current workspace
+// in the backend.
+func releaseTarget(storageBackend backend.Backend, projectName, workspaceName *string) (string, string, error) {
+	workspace := ""
+	proj := ""
 
 	workspaceStorage, err := storageBackend.WorkspaceStorage()
 	if err != nil {
-		return nil, err
+		return "", "", err
 	}
-	if f.Workspace != nil && *f.Workspace != "" {
-		refWorkspace, err := workspaceStorage.Get(*f.Workspace)
+	if workspaceName != nil && *workspaceName != "" {
+		refWorkspace, err := workspaceStorage.Get(*workspaceName)
 		if err != nil {
-			return nil, err
+			return "", "", err
 		}
-		workspaceName = refWorkspace.Name
+		workspace = refWorkspace.Name
 	} else {
 		currentWorkspace, err := workspaceStorage.GetCurrent()
 		if err != nil {
-			return nil, err
+			return "", "", err
 		}
-		workspaceName = currentWorkspace
+		workspace = currentWorkspace
 	}
 
-	if f.Project != nil && *f.Project != "" {
-		projectName = *f.Project
+	if projectName != nil && *projectName != "" {
+		proj = *projectName
 	} else {
 		currentProject, _, err := project.DetectProjectAndStacks()
 		if err != nil {
-			return nil, err
+			return "", "", err
 		}
-		projectName = currentProject.Name
-	}
-	storage, err := storageBackend.ReleaseStorage(projectName, workspaceName)
-	if err != nil {
-		return nil, err
+		proj = currentProject.Name
 	}
-
-	return &ShowOptions{
-		Revision:       f.Revision,
-		Output:         f.Output,
-		Project:        &projectName,
-		Workspace:      &workspaceName,
-		ReleaseStorage: storage,
-	}, nil
+	return proj, workspace, nil
 }
 
 // Validate checks the provided options for the `kusion release show` command.
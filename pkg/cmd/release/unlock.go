@@ -22,6 +22,9 @@ var (
 	The phase of the latest release file of the current stack in the current or a specified workspace
 	will be set to 'failed' if it was in the stages of 'generating', 'previewing', 'applying' or 'destroying'. 
 
+	With --force, the lock of the release files held by a crashed process is released as well, so that
+	the releases of the workspace can be updated again.
+
 	Please note that using the 'kusion release unlock' command may cause unexpected concurrent read-write
 	issues with release files, so please use it with caution. 
 	`)
@@ -31,6 +34,9 @@ var (
 
 	# Unlock the latest release file of the current stack in a specified workspace. 
 	kusion release unlock --workspace=dev
+
+	# Release the lock of the release files left by a crashed process as well. 
+	kusion release unlock --force
 `)
 )
 
@@ -38,11 +44,13 @@ var (
 // which will be converted into UnlockOptions.
 type UnlockFlags struct {
 	MetaFlags *meta.MetaFlags
+	Force     bool
 }
 
 // UnlockOptions defines the configuration parameters for the `kusion release unlock` command.
 type UnlockOptions struct {
 	*meta.MetaOptions
+	Force bool
 }
 
 // NewUnlockFlags returns a default UnlockFlags.
@@ -80,6 +88,7 @@ func NewCmdUnlock(streams genericiooptions.IOStreams) *cobra.Command {
 // AddFlags registers flags for the CLI.
 func (f *UnlockFlags) AddFlags(cmd *cobra.Command) {
 	f.MetaFlags.AddFlags(cmd)
+	cmd.Flags().BoolVarP(&f.Force, "force", "", false, i18n.T("Release the lock of the release files held by others, such as a crashed process"))
 }
 
 // ToOptions converts from CLI inputs to runtime inputs.
@@ -91,6 +100,7 @@ func (f *UnlockFlags) ToOptions() (*UnlockOptions, error) {
 
 	o := &UnlockOptions{
 		MetaOptions: metaOpts,
+		Force:       f.Force,
 	}
 
 	return o, nil
@@ -113,6 +123,17 @@ func (o *UnlockOptions) Run() error {
 		return err
 	}
 
+	// Release the lock of the release files by force if supported, so that the latest release can be updated.
+	if o.Force {
+		if unlocker, ok := storage.(release.Unlocker); ok {
+			if err = unlocker.ForceUnlock(); err != nil {
+				return err
+			}
+			fmt.Printf("Successfully released the lock of the release files, project: %s, workspace: %s\n",
+				o.RefProject.Name, o.RefWorkspace.Name)
+		}
+	}
+
 	// Get the latest release.
 	r, err := release.GetLatestRelease(storage)
 	if err != nil {
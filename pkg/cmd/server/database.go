@@ -18,7 +18,11 @@ var _ Options = &DatabaseOptions{}
 type DatabaseOptions struct {
 	DatabaseAccessOptions `json:",inline" yaml:",inline"`
 	// AutoMigrate will attempt to automatically migrate all tables
-	AutoMigrate bool   `json:"autoMigrate,omitempty" yaml:"autoMigrate,omitempty"`
+	AutoMigrate bool `json:"autoMigrate,omitempty" yaml:"autoMigrate,omitempty"`
+	// MigrateFile is a MySQL script executed before the auto migration.
+	//
+	// Deprecated: the auto migration applies the versioned migrations of
+	// the database driver in use.
 	MigrateFile string `json:"migrateFile,omitempty" yaml:"migrateFile,omitempty"`
 }
 
@@ -52,7 +56,7 @@ func (o *DatabaseOptions) ApplyTo(config *server.Config) {
 	config.AutoMigrate = o.AutoMigrate
 
 	// AutoMigrate will attempt to automatically migrate all tables
-	if o.AutoMigrate && len(o.MigrateFile) > 0 {
+	if o.AutoMigrate && len(o.MigrateFile) > 0 && o.driver() == DBDriverMySQL {
 		logrus.Debugf("AutoMigrate will attempt to automatically migrate all tables from [%s]", o.MigrateFile)
 		// Read all content by migrate file
 		migrateSQL, err := os.ReadFile(o.MigrateFile)
@@ -84,6 +88,7 @@ func (o *DatabaseOptions) AddFlags(fs *pflag.FlagSet) {
 
 	fs.BoolVar(&o.AutoMigrate, "auto-migrate", o.AutoMigrate, "Whether to enable automatic migration")
 	fs.StringVar(&o.MigrateFile, "migrate-file", o.MigrateFile, "The migrate sql file")
+	_ = fs.MarkDeprecated("migrate-file", "--auto-migrate applies the migrations of the database driver in use, this flag only takes effect on mysql")
 }
 
 // MarshalJSON is custom marshalling function for masking sensitive field values
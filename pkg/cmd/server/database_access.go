@@ -1,10 +1,14 @@
 package server
 
 import (
+	"net"
+	"net/url"
 	"strconv"
 
+	"github.com/glebarez/sqlite"
 	"github.com/pkg/errors"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"kusionstack.io/kusion/pkg/cmd/server/util"
@@ -13,39 +17,86 @@ import (
 	"github.com/spf13/pflag"
 )
 
+// The database drivers supported by the server.
+const (
+	DBDriverMySQL    = "mysql"
+	DBDriverPostgres = "postgres"
+	DBDriverSQLite   = "sqlite"
+)
+
 var (
 	ErrDBHostNotSpecified = errors.New("--db-host must be specified")
 	ErrDBNameNotSpecified = errors.New("--db-name must be specified")
 	ErrDBUserNotSpecified = errors.New("--db-user must be specified")
 	ErrDBPortNotSpecified = errors.New("--db-port must be specified")
+	ErrDBDriverInvalid    = errors.New("--db-driver must be one of the following: [mysql, postgres, sqlite]")
 )
 
 // DatabaseAccessOptions holds the database access layer configurations.
 type DatabaseAccessOptions struct {
+	DBDriver   string `json:"dbDriver,omitempty" yaml:"dbDriver,omitempty"`
 	DBName     string `json:"dbName,omitempty" yaml:"dbName,omitempty"`
 	DBUser     string `json:"dbUser,omitempty" yaml:"dbUser,omitempty"`
 	DBPassword string `json:"dbPassword,omitempty" yaml:"dbPassword,omitempty"`
 	DBHost     string `json:"dbHost,omitempty" yaml:"dbHost,omitempty"`
 	DBPort     int    `json:"dbPort,omitempty" yaml:"dbPort,omitempty"`
+	DBSSLMode  string `json:"dbSSLMode,omitempty" yaml:"dbSSLMode,omitempty"`
+}
+
+// driver returns the database driver, MySQL by default.
+func (o *DatabaseAccessOptions) driver() string {
+	if o.DBDriver == "" {
+		return DBDriverMySQL
+	}
+	return o.DBDriver
+}
+
+// Dialector returns the gorm dialector of the database driver.
+func (o *DatabaseAccessOptions) Dialector() (gorm.Dialector, error) {
+	switch o.driver() {
+	case DBDriverMySQL:
+		// Generate go-sql-driver.mysql config to format DSN
+		config := gomysql.NewConfig()
+		config.User = o.DBUser
+		config.Passwd = o.DBPassword
+		config.Addr = o.DBHost + ":" + strconv.Itoa(o.DBPort)
+		config.DBName = o.DBName
+		config.Net = "tcp"
+		config.ParseTime = true
+		config.InterpolateParams = true
+		return mysql.Open(config.FormatDSN()), nil
+	case DBDriverPostgres:
+		dsn := url.URL{
+			Scheme: "postgres",
+			User:   url.UserPassword(o.DBUser, o.DBPassword),
+			Host:   net.JoinHostPort(o.DBHost, strconv.Itoa(o.DBPort)),
+			Path:   o.DBName,
+		}
+		if o.DBSSLMode != "" {
+			dsn.RawQuery = url.Values{"sslmode": []string{o.DBSSLMode}}.Encode()
+		}
+		return postgres.Open(dsn.String()), nil
+	case DBDriverSQLite:
+		// The database name is the path of the database file. SQLite is meant
+		// for single-node installs, the WAL journal and busy timeout let the
+		// API handlers and the run queue share the file.
+		return sqlite.Open(o.DBName + "?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)"), nil
+	default:
+		return nil, ErrDBDriverInvalid
+	}
 }
 
 // InstallDB uses the run options to generate and open a db session.
 func (o *DatabaseAccessOptions) InstallDB() (*gorm.DB, error) {
-	// Generate go-sql-driver.mysql config to format DSN
-	config := gomysql.NewConfig()
-	config.User = o.DBUser
-	config.Passwd = o.DBPassword
-	config.Addr = o.DBHost + ":" + strconv.Itoa(o.DBPort)
-	config.DBName = o.DBName
-	config.Net = "tcp"
-	config.ParseTime = true
-	config.InterpolateParams = true
-	dsn := config.FormatDSN()
+	dialector, err := o.Dialector()
+	if err != nil {
+		return nil, err
+	}
 	// silence log output
 	cfg := &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Silent),
 	}
-	return gorm.Open(mysql.Open(dsn), cfg) // todo: add db connection check to healthz check
+	return gorm.Open(dialector, cfg) // todo: add db connection check to healthz check
 }
 
 // ApplyTo uses the run options to generate and open a db session.
@@ -61,6 +112,17 @@ func (o *DatabaseAccessOptions) ApplyTo(db **gorm.DB) error {
 // Validate checks validation of DatabaseAccessOptions
 func (o *DatabaseAccessOptions) Validate() error {
 	var errs []error
+	switch o.driver() {
+	case DBDriverMySQL, DBDriverPostgres:
+	case DBDriverSQLite:
+		// A SQLite database is a local file and only needs its path
+		if len(o.DBName) == 0 {
+			return errors.Wrap(ErrDBNameNotSpecified, "invalid db options")
+		}
+		return nil
+	default:
+		return errors.Wrap(ErrDBDriverInvalid, "invalid db options")
+	}
 	if len(o.DBHost) == 0 {
 		errs = append(errs, ErrDBHostNotSpecified)
 	}
@@ -82,9 +144,11 @@ func (o *DatabaseAccessOptions) Validate() error {
 
 // AddFlags adds flags related to DB to a specified FlagSet
 func (o *DatabaseAccessOptions) AddFlags(fs *pflag.FlagSet) {
-	fs.StringVar(&o.DBName, "db-name", o.DBName, "the database name")
+	fs.StringVar(&o.DBDriver, "db-driver", o.driver(), "the database driver, one of mysql, postgres and sqlite")
+	fs.StringVar(&o.DBName, "db-name", o.DBName, "the database name, or the path of the database file for sqlite")
 	fs.StringVar(&o.DBUser, "db-user", o.DBUser, "the user name used to access database")
 	fs.StringVar(&o.DBPassword, "db-pass", o.DBPassword, "the user password used to access database")
 	fs.StringVar(&o.DBHost, "db-host", o.DBHost, "database host")
 	fs.IntVar(&o.DBPort, "db-port", o.DBPort, "database port")
+	fs.StringVar(&o.DBSSLMode, "db-ssl-mode", o.DBSSLMode, "the SSL mode used to access a postgres database")
 }
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"kusionstack.io/kusion/pkg/infra/encryption"
+	"kusionstack.io/kusion/pkg/server"
+)
+
+var _ Options = &VariableEncryptionOptions{}
+
+// VariableEncryptionOptions holds the configurations of the key provider
+// encrypting the values of CipherText variables.
+type VariableEncryptionOptions struct {
+	// KeyProvider is the name of the key provider, CipherText variables are
+	// rejected if it is empty.
+	KeyProvider string `json:"keyProvider,omitempty" yaml:"keyProvider,omitempty"`
+	// KeyProviderOptions are the options of the key provider, such as the key
+	// file of the local key provider.
+	KeyProviderOptions map[string]string `json:"keyProviderOptions,omitempty" yaml:"keyProviderOptions,omitempty"`
+	// DecryptionKeyProviders are the options of the other key providers keyed
+	// by their names, which only decrypt the values encrypted by them.
+	DecryptionKeyProviders map[string]map[string]string `json:"decryptionKeyProviders,omitempty" yaml:"decryptionKeyProviders,omitempty"`
+}
+
+// Validate checks VariableEncryptionOptions and return a slice of found error(s)
+func (o *VariableEncryptionOptions) Validate() error {
+	if o.KeyProvider == "" {
+		return nil
+	}
+	_, err := o.newEncrypter()
+	return err
+}
+
+// ApplyTo creates the encrypter of CipherText variables with the configured
+// key provider.
+func (o *VariableEncryptionOptions) ApplyTo(config *server.Config) error {
+	if o.KeyProvider == "" {
+		return nil
+	}
+	encrypter, err := o.newEncrypter()
+	if err != nil {
+		return err
+	}
+	config.VariableEncrypter = encrypter
+	return nil
+}
+
+func (o *VariableEncryptionOptions) newEncrypter() (*encryption.Encrypter, error) {
+	provider, err := encryption.NewKeyProvider(o.KeyProvider, o.KeyProviderOptions)
+	if err != nil {
+		return nil, err
+	}
+	var others []encryption.KeyProvider
+	for name, options := range o.DecryptionKeyProviders {
+		other, err := encryption.NewKeyProvider(name, options)
+		if err != nil {
+			return nil, fmt.Errorf("invalid decryption key provider %s: %w", name, err)
+		}
+		others = append(others, other)
+	}
+	return encryption.NewEncrypter(provider, others...), nil
+}
+
+// AddFlags adds flags related to variable encryption to a specified FlagSet
+func (o *VariableEncryptionOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.KeyProvider, "variable-key-provider", o.KeyProvider,
+		"the key provider encrypting CipherText variables, such as local. CipherText variables are rejected if not specified")
+	fs.StringToStringVar(&o.KeyProviderOptions, "variable-key-provider-options", o.KeyProviderOptions,
+		"the options of the variable key provider, such as keyFile=/etc/kusion/variable.key for the local key provider")
+}
@@ -4,6 +4,7 @@ import (
 	"kusionstack.io/kusion/pkg/domain/constant"
 	"kusionstack.io/kusion/pkg/server"
 	"kusionstack.io/kusion/pkg/server/route"
+	"kusionstack.io/kusion/pkg/util/signal"
 )
 
 func NewServerOptions() *ServerOptions {
@@ -19,15 +20,20 @@ func NewServerOptions() *ServerOptions {
 		MaxConcurrent:      constant.MaxConcurrent,
 		MaxAsyncConcurrent: constant.MaxAsyncConcurrent,
 		MaxAsyncBuffer:     constant.MaxAsyncBuffer,
+		RunRecoveryPolicy:  string(constant.RunQueueDefaultRecoveryPolicy),
 		LogFilePath:        constant.DefaultLogFilePath,
 		DevPortalEnabled:   true,
+		VariableEncryption: VariableEncryptionOptions{},
 	}
 }
 
 func (o *ServerOptions) Complete(args []string) {}
 
 func (o *ServerOptions) Validate() error {
-	return nil
+	if _, err := constant.ParseRunRecoveryPolicy(o.RunRecoveryPolicy); err != nil {
+		return err
+	}
+	return o.VariableEncryption.Validate()
 }
 
 func (o *ServerOptions) Config() (*server.Config, error) {
@@ -42,8 +48,12 @@ func (o *ServerOptions) Config() (*server.Config, error) {
 	cfg.MaxConcurrent = o.MaxConcurrent
 	cfg.MaxAsyncConcurrent = o.MaxAsyncConcurrent
 	cfg.MaxAsyncBuffer = o.MaxAsyncBuffer
+	cfg.RunRecoveryPolicy, _ = constant.ParseRunRecoveryPolicy(o.RunRecoveryPolicy)
 	cfg.LogFilePath = o.LogFilePath
 	cfg.DevPortalEnabled = o.DevPortalEnabled
+	if err := o.VariableEncryption.ApplyTo(cfg); err != nil {
+		return nil, err
+	}
 	return cfg, nil
 }
 
@@ -52,6 +62,6 @@ func (o *ServerOptions) Run() error {
 	if err != nil {
 		return err
 	}
-	_, err = route.NewCoreRoute(config)
+	_, err = route.NewCoreRoute(signal.SetupSignalContext(), config)
 	return err
 }
@@ -52,9 +52,12 @@ func (o *ServerOptions) AddServerFlags(cmd *cobra.Command) {
 	cmd.Flags().IntVarP(&o.MaxConcurrent, "max-concurrent", "", 10,
 		i18n.T("Maximum number of concurrent executions including preview, apply and destroy. Default to 10."))
 	cmd.Flags().IntVarP(&o.MaxAsyncBuffer, "max-async-buffer", "", 100,
-		i18n.T("Maximum number of buffer zones during concurrent async executions including generate, preview, apply and destroy. Default to 100."))
+		i18n.T("Deprecated: async executions are queued in the database and no longer limited by a buffer."))
+	cmd.Flags().MarkDeprecated("max-async-buffer", "async executions are queued in the database and no longer limited by a buffer")
 	cmd.Flags().IntVarP(&o.MaxAsyncConcurrent, "max-async-concurrent", "", 10,
-		i18n.T("Maximum number of concurrent async executions including generate, preview, apply and destroy. Default to 10."))
+		i18n.T("Maximum number of concurrent async executions including generate, preview, apply and destroy on this server. Default to 10."))
+	cmd.Flags().StringVarP(&o.RunRecoveryPolicy, "run-recovery-policy", "", string(constant.RunQueueDefaultRecoveryPolicy),
+		i18n.T("How to recover the async runs left in progress by a crashed or restarted server, fail or requeue. Default to fail."))
 	cmd.Flags().StringVarP(&o.LogFilePath, "log-file-path", "", constant.DefaultLogFilePath,
 		i18n.T("File path to write logs to. Default to /home/admin/logs/kusion.log"))
 	cmd.Flags().BoolVarP(&o.DevPortalEnabled, "dev-portal-enabled", "d", true,
@@ -62,4 +65,5 @@ func (o *ServerOptions) AddServerFlags(cmd *cobra.Command) {
 	o.Database.AddFlags(cmd.Flags())
 	o.DefaultBackend.AddFlags(cmd.Flags())
 	o.DefaultSource.AddFlags(cmd.Flags())
+	o.VariableEncryption.AddFlags(cmd.Flags())
 }
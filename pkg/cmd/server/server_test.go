@@ -1,13 +1,16 @@
 package server
 
 import (
+	"encoding/base64"
 	"net/url"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"kusionstack.io/kusion/pkg/domain/constant"
+	"kusionstack.io/kusion/pkg/infra/encryption"
 	"kusionstack.io/kusion/pkg/server"
 )
 
@@ -46,3 +49,83 @@ func TestDefaultSourceOptions_ApplyTo(t *testing.T) {
 	require.Equal(t, expectedProvider, config.DefaultSource.SourceProvider)
 	require.Equal(t, expectedDescription, config.DefaultSource.Description)
 }
+
+func TestDatabaseAccessOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options DatabaseAccessOptions
+		wantErr error
+	}{
+		{
+			name:    "mysql by default",
+			options: DatabaseAccessOptions{DBName: "kusion", DBUser: "root", DBHost: "localhost", DBPort: 3306},
+		},
+		{
+			name:    "postgres without host",
+			options: DatabaseAccessOptions{DBDriver: DBDriverPostgres, DBName: "kusion", DBUser: "root", DBPort: 5432},
+			wantErr: ErrDBHostNotSpecified,
+		},
+		{
+			name:    "sqlite only needs the database file",
+			options: DatabaseAccessOptions{DBDriver: DBDriverSQLite, DBName: "/var/lib/kusion/kusion.db"},
+		},
+		{
+			name:    "sqlite without database file",
+			options: DatabaseAccessOptions{DBDriver: DBDriverSQLite},
+			wantErr: ErrDBNameNotSpecified,
+		},
+		{
+			name:    "unknown driver",
+			options: DatabaseAccessOptions{DBDriver: "oracle"},
+			wantErr: ErrDBDriverInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.Validate()
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tt.wantErr.Error())
+			}
+		})
+	}
+}
+
+func TestDatabaseAccessOptions_Dialector(t *testing.T) {
+	for driver, name := range map[string]string{
+		"":               "mysql",
+		DBDriverMySQL:    "mysql",
+		DBDriverPostgres: "postgres",
+		DBDriverSQLite:   "sqlite",
+	} {
+		options := DatabaseAccessOptions{DBDriver: driver, DBName: "kusion", DBHost: "localhost", DBPort: 5432}
+		dialector, err := options.Dialector()
+		require.NoError(t, err)
+		require.Equal(t, name, dialector.Name())
+	}
+
+	_, err := (&DatabaseAccessOptions{DBDriver: "oracle"}).Dialector()
+	require.ErrorIs(t, err, ErrDBDriverInvalid)
+}
+
+func TestVariableEncryptionOptions_ApplyTo(t *testing.T) {
+	config := &server.Config{}
+	require.NoError(t, (&VariableEncryptionOptions{}).ApplyTo(config))
+	require.Nil(t, config.VariableEncrypter)
+
+	keyFile := filepath.Join(t.TempDir(), "variable.key")
+	require.NoError(t, os.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(make([]byte, 32))), 0o600))
+	options := &VariableEncryptionOptions{
+		KeyProvider:        encryption.LocalKeyProviderName,
+		KeyProviderOptions: map[string]string{encryption.LocalKeyFileOption: keyFile},
+	}
+	require.NoError(t, options.Validate())
+	require.NoError(t, options.ApplyTo(config))
+	require.NotNil(t, config.VariableEncrypter)
+
+	options.KeyProviderOptions = nil
+	require.Error(t, options.Validate())
+	options.KeyProvider = "unknown"
+	require.ErrorIs(t, options.Validate(), encryption.ErrUnknownKeyProvider)
+}
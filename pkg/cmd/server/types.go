@@ -16,8 +16,10 @@ type ServerOptions struct {
 	MaxConcurrent      int
 	MaxAsyncConcurrent int
 	MaxAsyncBuffer     int
+	RunRecoveryPolicy  string
 	LogFilePath        string
 	DevPortalEnabled   bool
+	VariableEncryption VariableEncryptionOptions
 }
 
 type Options interface {
@@ -26,6 +26,7 @@ var (
 	ErrUnsupportedConfigItem     = errors.New("unsupported config item")
 	ErrEmptyBackendName          = errors.New("backend name should not be empty")
 	ErrInvalidBackendNameCurrent = errors.New("backend name should not be current")
+	ErrEmptyTemplateRegistryName = errors.New("template registry name should not be empty")
 )
 
 // operator is used to execute the config management operation.
@@ -305,6 +306,15 @@ func tidyConfig(configAddr **v1.Config) {
 		}
 	}
 
+	for name, registry := range config.Templates {
+		if registry == nil || reflect.ValueOf(*registry).IsZero() {
+			delete(config.Templates, name)
+		}
+	}
+	if len(config.Templates) == 0 {
+		config.Templates = nil
+	}
+
 	*configAddr = config
 }
 
@@ -380,6 +390,10 @@ func convertToRegisteredKey(registeredItems map[string]*itemInfo, key string) (s
 		if registeredKey, err = convertBackendKey(key); err != nil {
 			return "", err
 		}
+	case v1.ConfigTemplates:
+		if registeredKey, err = convertTemplateKey(key); err != nil {
+			return "", err
+		}
 	default:
 		return "", ErrUnsupportedConfigItem
 	}
@@ -409,6 +423,18 @@ func convertBackendKey(key string) (string, error) {
 	return registeredKey, nil
 }
 
+func convertTemplateKey(key string) (string, error) {
+	fields := strings.Split(key, ".")
+	if len(fields) < 2 || len(fields) > 3 {
+		return "", fmt.Errorf("%w, %s", ErrUnsupportedConfigItem, key)
+	}
+	if fields[1] == "" {
+		return "", ErrEmptyTemplateRegistryName
+	}
+	fields[1] = "*"
+	return strings.Join(fields, "."), nil
+}
+
 func convertToCfgMap(config *v1.Config) (cfg map[string]any, err error) {
 	defer func() {
 		if err != nil {
@@ -660,6 +660,16 @@ func TestTidyConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:    "tidy config successfully tidy template registries",
+			success: true,
+			config: &v1.Config{
+				Templates: map[string]*v1.TemplateRegistryConfig{
+					"golden": {},
+				},
+			},
+			expectedConfig: &v1.Config{},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -807,6 +817,18 @@ func TestConvertToRegisteredKey(t *testing.T) {
 			key:           "backends.current.type",
 			registeredKey: "",
 		},
+		{
+			name:          "convert to registered key successfully convert template registry name",
+			success:       true,
+			key:           "templates.golden.source",
+			registeredKey: "templates.*.source",
+		},
+		{
+			name:          "failed to convert to registered key empty template registry name",
+			success:       false,
+			key:           "templates..source",
+			registeredKey: "",
+		},
 	}
 
 	for _, tc := range testcases {
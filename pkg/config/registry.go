@@ -16,6 +16,25 @@ const (
 	backendGenericOssBucket   = backendConfigItems + "." + v1.BackendGenericOssBucket
 	backendGenericOssPrefix   = backendConfigItems + "." + v1.BackendGenericOssPrefix
 	backendS3Region           = backendConfigItems + "." + v1.BackendS3Region
+	backendAzureAccountName   = backendConfigItems + "." + v1.BackendAzureAccountName
+	backendAzureAccountKey    = backendConfigItems + "." + v1.BackendAzureAccountKey
+	backendAzureContainer     = backendConfigItems + "." + v1.BackendAzureContainer
+	backendHTTPAddress        = backendConfigItems + "." + v1.BackendHTTPAddress
+	backendHTTPUsername       = backendConfigItems + "." + v1.BackendHTTPUsername
+	backendHTTPPassword       = backendConfigItems + "." + v1.BackendHTTPPassword
+	backendKubeconfig         = backendConfigItems + "." + v1.BackendKubernetesKubeconfig
+	backendKubernetesContext  = backendConfigItems + "." + v1.BackendKubernetesContext
+	backendKubernetesNS       = backendConfigItems + "." + v1.BackendKubernetesNamespace
+
+	backendEncryptionKeyProvider        = backendConfigItems + "." + v1.BackendEncryptionKeyProvider
+	backendEncryptionKeyProviderOptions = backendConfigItems + "." + v1.BackendEncryptionKeyProviderOptions
+
+	backendReleaseRetentionKeepLast   = backendConfigItems + "." + v1.BackendReleaseRetentionKeepLast
+	backendReleaseRetentionKeepWithin = backendConfigItems + "." + v1.BackendReleaseRetentionKeepWithin
+
+	templateRegistry        = v1.ConfigTemplates + "." + "*"
+	templateRegistrySource  = templateRegistry + "." + v1.TemplateRegistrySource
+	templateRegistryVersion = templateRegistry + "." + v1.TemplateRegistryVersion
 )
 
 func newRegisteredItems() map[string]*itemInfo {
@@ -25,12 +44,31 @@ func newRegisteredItems() map[string]*itemInfo {
 		backendConfigType:         {"", validateSetBackendType, validateUnsetBackendType},
 		backendConfigItems:        {map[string]any{}, validateSetBackendConfigItems, validateUnsetBackendConfigItems},
 		backendLocalPath:          {"", validateSetLocalBackendItem, validateUnsetLocalBackendItem},
-		backendGenericOssEndpoint: {"", validateSetGenericOssBackendItem, nil},
+		backendGenericOssEndpoint: {"", validateSetObjectStorageBackendItem, nil},
 		backendGenericOssAK:       {"", validateSetGenericOssBackendItem, nil},
 		backendGenericOssSK:       {"", validateSetGenericOssBackendItem, nil},
 		backendGenericOssBucket:   {"", validateSetGenericOssBackendItem, nil},
-		backendGenericOssPrefix:   {"", validateSetGenericOssBackendItem, nil},
+		backendGenericOssPrefix:   {"", validateSetObjectStorageBackendItem, nil},
 		backendS3Region:           {"", validateSetS3BackendItem, nil},
+		backendAzureAccountName:   {"", validateSetAzureBackendItem, nil},
+		backendAzureAccountKey:    {"", validateSetAzureBackendItem, nil},
+		backendAzureContainer:     {"", validateSetAzureBackendItem, nil},
+		backendHTTPAddress:        {"", validateSetHTTPBackendItem, nil},
+		backendHTTPUsername:       {"", validateSetHTTPBackendItem, nil},
+		backendHTTPPassword:       {"", validateSetHTTPBackendItem, nil},
+		backendKubeconfig:         {"", validateSetKubernetesBackendItem, nil},
+		backendKubernetesContext:  {"", validateSetKubernetesBackendItem, nil},
+		backendKubernetesNS:       {"", validateSetKubernetesBackendItem, nil},
+
+		backendEncryptionKeyProvider:        {"", validateSetEncryptionKeyProvider, nil},
+		backendEncryptionKeyProviderOptions: {map[string]any{}, validateSetEncryptionKeyProviderOptions, nil},
+
+		backendReleaseRetentionKeepLast:   {0, validateSetReleaseRetentionKeepLast, nil},
+		backendReleaseRetentionKeepWithin: {"", validateSetReleaseRetentionKeepWithin, nil},
+
+		templateRegistry:        {&v1.TemplateRegistryConfig{}, validateSetTemplateRegistry, nil},
+		templateRegistrySource:  {"", nil, validateUnsetTemplateRegistrySource},
+		templateRegistryVersion: {"", validateSetTemplateRegistryVersion, nil},
 	}
 }
 
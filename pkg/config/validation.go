@@ -4,9 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
 	"kusionstack.io/kusion/pkg/backend/storages"
+	"kusionstack.io/kusion/pkg/infra/encryption"
 )
 
 type (
@@ -23,6 +25,8 @@ var (
 	ErrEmptyBackendType           = errors.New("empty backend type")
 	ErrConflictBackendType        = errors.New("conflict backend type")
 	ErrInvalidBackNameDefault     = errors.New("backend name should not be default")
+	ErrEmptyTemplateSource        = errors.New("empty template registry source")
+	ErrUnsetTemplateSource        = errors.New("cannot unset source of template registry with version")
 )
 
 // validateSetCurrentBackend is used to check that setting the current backend is valid or not.
@@ -67,7 +71,9 @@ func validateUnsetBackendConfig(config *v1.Config, key string) error {
 // validateSetBackendType is used to check that setting the backend type is valid or not.
 func validateSetBackendType(config *v1.Config, key string, val any) error {
 	backendType, _ := val.(string)
-	if backendType != v1.BackendTypeLocal && backendType != v1.BackendTypeOss && backendType != v1.BackendTypeS3 {
+	switch backendType {
+	case v1.BackendTypeLocal, v1.BackendTypeOss, v1.BackendTypeS3, v1.BackendTypeAzure, v1.BackendTypeHTTP, v1.BackendTypeKubernetes:
+	default:
 		return ErrUnsupportedBackendType
 	}
 
@@ -142,11 +148,96 @@ func validateSetGenericOssBackendItem(config *v1.Config, key string, _ any) erro
 	return checkBackendTypeForBackendItem(config, key, v1.BackendTypeOss, v1.BackendTypeS3)
 }
 
+// validateSetObjectStorageBackendItem is used to check that setting the endpoint or prefix of oss/s3/azure-type
+// backend is valid or not.
+func validateSetObjectStorageBackendItem(config *v1.Config, key string, _ any) error {
+	return checkBackendTypeForBackendItem(config, key, v1.BackendTypeOss, v1.BackendTypeS3, v1.BackendTypeAzure)
+}
+
 // validateSetS3BackendItem is used to check that setting the bucket of s3-type backend is valid or not.
 func validateSetS3BackendItem(config *v1.Config, key string, _ any) error {
 	return checkBackendTypeForBackendItem(config, key, v1.BackendTypeS3)
 }
 
+// validateSetAzureBackendItem is used to check that setting the config item of azure-type backend is valid or not.
+func validateSetAzureBackendItem(config *v1.Config, key string, _ any) error {
+	return checkBackendTypeForBackendItem(config, key, v1.BackendTypeAzure)
+}
+
+// validateSetHTTPBackendItem is used to check that setting the config item of http-type backend is valid or not.
+func validateSetHTTPBackendItem(config *v1.Config, key string, _ any) error {
+	return checkBackendTypeForBackendItem(config, key, v1.BackendTypeHTTP)
+}
+
+// validateSetKubernetesBackendItem is used to check that setting the config item of kubernetes-type backend is
+// valid or not.
+func validateSetKubernetesBackendItem(config *v1.Config, key string, _ any) error {
+	return checkBackendTypeForBackendItem(config, key, v1.BackendTypeKubernetes)
+}
+
+// validateSetEncryptionKeyProvider is used to check that setting the key provider of the backend encryption is
+// valid or not, which is available for all the backend types.
+func validateSetEncryptionKeyProvider(config *v1.Config, key string, val any) error {
+	if err := checkGenericBackendItem(config, key); err != nil {
+		return err
+	}
+	return checkEncryptionKeyProvider(val)
+}
+
+// validateSetEncryptionKeyProviderOptions is used to check that setting the key provider options of the backend
+// encryption is valid or not.
+func validateSetEncryptionKeyProviderOptions(config *v1.Config, key string, val any) error {
+	if err := checkGenericBackendItem(config, key); err != nil {
+		return err
+	}
+	return checkStringMap(val)
+}
+
+// validateSetReleaseRetentionKeepLast is used to check that setting the number of the latest releases to keep
+// is valid or not, which is available for all the backend types.
+func validateSetReleaseRetentionKeepLast(config *v1.Config, key string, val any) error {
+	if err := checkGenericBackendItem(config, key); err != nil {
+		return err
+	}
+	return checkPositiveInt(val)
+}
+
+// validateSetReleaseRetentionKeepWithin is used to check that setting the duration within which the releases
+// are kept is valid or not.
+func validateSetReleaseRetentionKeepWithin(config *v1.Config, key string, val any) error {
+	if err := checkGenericBackendItem(config, key); err != nil {
+		return err
+	}
+	return checkDuration(val)
+}
+
+// validateSetTemplateRegistry is used to check that setting the template registry is valid or not.
+func validateSetTemplateRegistry(_ *v1.Config, _ string, val any) error {
+	registry, _ := val.(*v1.TemplateRegistryConfig)
+	if registry == nil || registry.Source == "" {
+		return ErrEmptyTemplateSource
+	}
+	return nil
+}
+
+// validateUnsetTemplateRegistrySource is used to check that unsetting the template registry source is valid or not.
+func validateUnsetTemplateRegistrySource(config *v1.Config, key string) error {
+	registry := config.Templates[parseTemplateRegistryName(key)]
+	if registry != nil && registry.Version != "" {
+		return ErrUnsetTemplateSource
+	}
+	return nil
+}
+
+// validateSetTemplateRegistryVersion is used to check that setting the template registry version is valid or not.
+func validateSetTemplateRegistryVersion(config *v1.Config, key string, _ any) error {
+	registry := config.Templates[parseTemplateRegistryName(key)]
+	if registry == nil || registry.Source == "" {
+		return ErrEmptyTemplateSource
+	}
+	return nil
+}
+
 // checkBackendConfig is used to check that setting the backend config is valid or not, which is called
 // validateSetBackendConfig and validateSetBackendConfigItems.
 func checkBackendConfig(config *v1.BackendConfig) error {
@@ -165,6 +256,16 @@ func checkBackendConfig(config *v1.BackendConfig) error {
 		if err := storages.ValidateS3ConfigFromFile(s3Backend); err != nil {
 			return err
 		}
+	case v1.BackendTypeAzure:
+		azureBackend := config.ToAzureBackend()
+		if err := storages.ValidateAzureConfigFromFile(azureBackend); err != nil {
+			return err
+		}
+	case v1.BackendTypeHTTP:
+		httpBackend := config.ToHTTPBackend()
+		if err := storages.ValidateHTTPConfig(httpBackend); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -175,31 +276,84 @@ func checkBasalBackendConfig(config *v1.BackendConfig) error {
 	switch config.Type {
 	case v1.BackendTypeLocal:
 		items := map[string]checkTypeFunc{
-			v1.BackendLocalPath: checkString,
+			v1.BackendLocalPath:                    checkString,
+			v1.BackendEncryptionKeyProvider:        checkEncryptionKeyProvider,
+			v1.BackendEncryptionKeyProviderOptions: checkStringMap,
+			v1.BackendReleaseRetentionKeepLast:     checkPositiveInt,
+			v1.BackendReleaseRetentionKeepWithin:   checkDuration,
 		}
 		if err := checkBasalBackendConfigItems(config, items); err != nil {
 			return err
 		}
 	case v1.BackendTypeOss:
 		items := map[string]checkTypeFunc{
-			v1.BackendGenericOssEndpoint: checkString,
-			v1.BackendGenericOssAK:       checkString,
-			v1.BackendGenericOssSK:       checkString,
-			v1.BackendGenericOssBucket:   checkString,
-			v1.BackendGenericOssPrefix:   checkString,
+			v1.BackendGenericOssEndpoint:           checkString,
+			v1.BackendGenericOssAK:                 checkString,
+			v1.BackendGenericOssSK:                 checkString,
+			v1.BackendGenericOssBucket:             checkString,
+			v1.BackendGenericOssPrefix:             checkString,
+			v1.BackendEncryptionKeyProvider:        checkEncryptionKeyProvider,
+			v1.BackendEncryptionKeyProviderOptions: checkStringMap,
+			v1.BackendReleaseRetentionKeepLast:     checkPositiveInt,
+			v1.BackendReleaseRetentionKeepWithin:   checkDuration,
 		}
 		if err := checkBasalBackendConfigItems(config, items); err != nil {
 			return err
 		}
 	case v1.BackendTypeS3:
 		items := map[string]checkTypeFunc{
-			v1.BackendGenericOssEndpoint: checkString,
-			v1.BackendGenericOssAK:       checkString,
-			v1.BackendGenericOssSK:       checkString,
-			v1.BackendGenericOssBucket:   checkString,
-			v1.BackendGenericOssPrefix:   checkString,
-			v1.BackendS3Region:           checkString,
-			v1.BackendS3ForcePathStyle:   checkBool,
+			v1.BackendGenericOssEndpoint:           checkString,
+			v1.BackendGenericOssAK:                 checkString,
+			v1.BackendGenericOssSK:                 checkString,
+			v1.BackendGenericOssBucket:             checkString,
+			v1.BackendGenericOssPrefix:             checkString,
+			v1.BackendS3Region:                     checkString,
+			v1.BackendS3ForcePathStyle:             checkBool,
+			v1.BackendEncryptionKeyProvider:        checkEncryptionKeyProvider,
+			v1.BackendEncryptionKeyProviderOptions: checkStringMap,
+			v1.BackendReleaseRetentionKeepLast:     checkPositiveInt,
+			v1.BackendReleaseRetentionKeepWithin:   checkDuration,
+		}
+		if err := checkBasalBackendConfigItems(config, items); err != nil {
+			return err
+		}
+	case v1.BackendTypeAzure:
+		items := map[string]checkTypeFunc{
+			v1.BackendAzureAccountName:             checkString,
+			v1.BackendAzureAccountKey:              checkString,
+			v1.BackendAzureContainer:               checkString,
+			v1.BackendGenericOssPrefix:             checkString,
+			v1.BackendGenericOssEndpoint:           checkString,
+			v1.BackendEncryptionKeyProvider:        checkEncryptionKeyProvider,
+			v1.BackendEncryptionKeyProviderOptions: checkStringMap,
+			v1.BackendReleaseRetentionKeepLast:     checkPositiveInt,
+			v1.BackendReleaseRetentionKeepWithin:   checkDuration,
+		}
+		if err := checkBasalBackendConfigItems(config, items); err != nil {
+			return err
+		}
+	case v1.BackendTypeHTTP:
+		items := map[string]checkTypeFunc{
+			v1.BackendHTTPAddress:                  checkString,
+			v1.BackendHTTPUsername:                 checkString,
+			v1.BackendHTTPPassword:                 checkString,
+			v1.BackendEncryptionKeyProvider:        checkEncryptionKeyProvider,
+			v1.BackendEncryptionKeyProviderOptions: checkStringMap,
+			v1.BackendReleaseRetentionKeepLast:     checkPositiveInt,
+			v1.BackendReleaseRetentionKeepWithin:   checkDuration,
+		}
+		if err := checkBasalBackendConfigItems(config, items); err != nil {
+			return err
+		}
+	case v1.BackendTypeKubernetes:
+		items := map[string]checkTypeFunc{
+			v1.BackendKubernetesKubeconfig:         checkString,
+			v1.BackendKubernetesContext:            checkString,
+			v1.BackendKubernetesNamespace:          checkString,
+			v1.BackendEncryptionKeyProvider:        checkEncryptionKeyProvider,
+			v1.BackendEncryptionKeyProviderOptions: checkStringMap,
+			v1.BackendReleaseRetentionKeepLast:     checkPositiveInt,
+			v1.BackendReleaseRetentionKeepWithin:   checkDuration,
 		}
 		if err := checkBasalBackendConfigItems(config, items); err != nil {
 			return err
@@ -245,6 +399,19 @@ func checkBackendTypeForBackendItem(config *v1.Config, key string, backendTypes
 	return nil
 }
 
+// checkEncryptionBackendItem checks the backend when setting the encryption config item, which is available
+// for all the backend types.
+func checkGenericBackendItem(config *v1.Config, key string) error {
+	backendName := parseBackendName(key)
+	if err := checkNotDefaultBackendName(backendName); err != nil {
+		return err
+	}
+	if config.Backends.Backends[backendName] == nil || config.Backends.Backends[backendName].Type == "" {
+		return ErrEmptyBackendType
+	}
+	return nil
+}
+
 // checkNotDefaultBackendName returns error if the backend name is default.
 func checkNotDefaultBackendName(name string) error {
 	if name == v1.DefaultBackendName {
@@ -263,6 +430,16 @@ func parseBackendName(key string) string {
 	return fields[1]
 }
 
+// parseTemplateRegistryName parses the template registry name from the config key, the key is like
+// "templates.golden.source", "templates.golden"
+func parseTemplateRegistryName(key string) string {
+	fields := strings.Split(key, ".")
+	if len(fields) < 2 {
+		return ""
+	}
+	return fields[1]
+}
+
 // parseBackendItem parses the backend config item from the config key, the key is like "backends.dev.configs.bucket"
 func parseBackendItem(key string) string {
 	fields := strings.Split(key, ".")
@@ -275,9 +452,12 @@ func parseBackendItem(key string) string {
 type checkTypeFunc func(val any) error
 
 var (
-	ErrNotBool   = errors.New("not bool type")
-	ErrNotInt    = errors.New("not int type")
-	ErrNotString = errors.New("not string type")
+	ErrNotBool        = errors.New("not bool type")
+	ErrNotInt         = errors.New("not int type")
+	ErrNotPositiveInt = errors.New("not positive int")
+	ErrNotDuration    = errors.New("not duration")
+	ErrNotString      = errors.New("not string type")
+	ErrNotStringMap   = errors.New("not map with string values")
 )
 
 func checkString(val any) error {
@@ -287,6 +467,51 @@ func checkString(val any) error {
 	return nil
 }
 
+func checkStringMap(val any) error {
+	m, ok := val.(map[string]any)
+	if !ok {
+		return ErrNotStringMap
+	}
+	for _, v := range m {
+		if err := checkString(v); err != nil {
+			return ErrNotStringMap
+		}
+	}
+	return nil
+}
+
+// checkEncryptionKeyProvider checks the key provider of the backend encryption is registered.
+func checkEncryptionKeyProvider(val any) error {
+	if err := checkString(val); err != nil {
+		return err
+	}
+	provider, _ := val.(string)
+	for _, name := range encryption.KeyProviders() {
+		if provider == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s, supported key providers are %v", encryption.ErrUnknownKeyProvider, provider, encryption.KeyProviders())
+}
+
+func checkPositiveInt(val any) error {
+	if v, ok := val.(int); !ok || v <= 0 {
+		return ErrNotPositiveInt
+	}
+	return nil
+}
+
+// checkDuration checks the value is a duration string, such as "720h".
+func checkDuration(val any) error {
+	if err := checkString(val); err != nil {
+		return err
+	}
+	if _, err := time.ParseDuration(val.(string)); err != nil {
+		return fmt.Errorf("%w: %v", ErrNotDuration, err)
+	}
+	return nil
+}
+
 func checkBool(val any) error {
 	if _, ok := val.(bool); !ok {
 		return ErrNotBool
@@ -127,6 +127,70 @@ func TestValidateSetBackendConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:    "valid azure backend",
+			success: true,
+			val: &v1.BackendConfig{
+				Type: v1.BackendTypeAzure,
+				Configs: map[string]any{
+					v1.BackendAzureAccountName: "kusion",
+					v1.BackendAzureContainer:   "state",
+					v1.BackendGenericOssPrefix: "dev",
+				},
+			},
+		},
+		{
+			name:    "invalid azure backend empty container",
+			success: false,
+			val: &v1.BackendConfig{
+				Type: v1.BackendTypeAzure,
+				Configs: map[string]any{
+					v1.BackendAzureAccountName: "kusion",
+				},
+			},
+		},
+		{
+			name:    "valid http backend",
+			success: true,
+			val: &v1.BackendConfig{
+				Type: v1.BackendTypeHTTP,
+				Configs: map[string]any{
+					v1.BackendHTTPAddress:  "https://state.example.com/kusion",
+					v1.BackendHTTPUsername: "kusion",
+				},
+			},
+		},
+		{
+			name:    "invalid http backend relative address",
+			success: false,
+			val: &v1.BackendConfig{
+				Type: v1.BackendTypeHTTP,
+				Configs: map[string]any{
+					v1.BackendHTTPAddress: "state.example.com",
+				},
+			},
+		},
+		{
+			name:    "valid kubernetes backend",
+			success: true,
+			val: &v1.BackendConfig{
+				Type: v1.BackendTypeKubernetes,
+				Configs: map[string]any{
+					v1.BackendKubernetesContext:   "prod",
+					v1.BackendKubernetesNamespace: "kusion-system",
+				},
+			},
+		},
+		{
+			name:    "invalid kubernetes backend unsupported item",
+			success: false,
+			val: &v1.BackendConfig{
+				Type: v1.BackendTypeKubernetes,
+				Configs: map[string]any{
+					v1.BackendGenericOssBucket: "kusion",
+				},
+			},
+		},
 		{
 			name:    "invalid backend config invalid backend type",
 			success: false,
@@ -205,6 +269,17 @@ func TestValidateSetBackendType(t *testing.T) {
 			key: "backends.dev.type",
 			val: "s3",
 		},
+		{
+			name:    "valid backend type kubernetes",
+			success: true,
+			config: &v1.Config{
+				Backends: &v1.BackendConfigs{
+					Backends: map[string]*v1.BackendConfig{},
+				},
+			},
+			key: "backends.dev.type",
+			val: "kubernetes",
+		},
 		{
 			name:    "invalid backend type unsupported type",
 			success: false,
@@ -429,3 +504,143 @@ func TestCheckBackendTypeForBackendItem(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateSetEncryptionBackendItems(t *testing.T) {
+	config := &v1.Config{
+		Backends: &v1.BackendConfigs{
+			Backends: map[string]*v1.BackendConfig{
+				"dev":  {Type: v1.BackendTypeLocal},
+				"prod": {},
+			},
+		},
+	}
+
+	testcases := []struct {
+		name     string
+		success  bool
+		key      string
+		val      any
+		validate validateFunc
+	}{
+		{
+			name:     "valid key provider",
+			success:  true,
+			key:      "backends.dev.configs.encryptionKeyProvider",
+			val:      "env",
+			validate: validateSetEncryptionKeyProvider,
+		},
+		{
+			name:     "invalid key provider not registered",
+			success:  false,
+			key:      "backends.dev.configs.encryptionKeyProvider",
+			val:      "unknown",
+			validate: validateSetEncryptionKeyProvider,
+		},
+		{
+			name:     "invalid key provider empty backend type",
+			success:  false,
+			key:      "backends.prod.configs.encryptionKeyProvider",
+			val:      "local",
+			validate: validateSetEncryptionKeyProvider,
+		},
+		{
+			name:     "valid key provider options",
+			success:  true,
+			key:      "backends.dev.configs.encryptionKeyProviderOptions",
+			val:      map[string]any{"keyFile": "/etc/kusion/backend.key"},
+			validate: validateSetEncryptionKeyProviderOptions,
+		},
+		{
+			name:     "invalid key provider options not string",
+			success:  false,
+			key:      "backends.dev.configs.encryptionKeyProviderOptions",
+			val:      map[string]any{"keyFile": 1},
+			validate: validateSetEncryptionKeyProviderOptions,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.validate(config, tc.key, tc.val)
+			assert.Equal(t, tc.success, err == nil)
+		})
+	}
+}
+
+func TestValidateSetReleaseRetentionBackendItems(t *testing.T) {
+	config := &v1.Config{
+		Backends: &v1.BackendConfigs{
+			Backends: map[string]*v1.BackendConfig{
+				"dev":  {Type: v1.BackendTypeS3},
+				"prod": {},
+			},
+		},
+	}
+
+	testcases := []struct {
+		name     string
+		success  bool
+		key      string
+		val      any
+		validate validateFunc
+	}{
+		{
+			name:     "valid keep last",
+			success:  true,
+			key:      "backends.dev.configs.releaseRetentionKeepLast",
+			val:      10,
+			validate: validateSetReleaseRetentionKeepLast,
+		},
+		{
+			name:     "invalid keep last not positive",
+			success:  false,
+			key:      "backends.dev.configs.releaseRetentionKeepLast",
+			val:      0,
+			validate: validateSetReleaseRetentionKeepLast,
+		},
+		{
+			name:     "invalid keep last empty backend type",
+			success:  false,
+			key:      "backends.prod.configs.releaseRetentionKeepLast",
+			val:      10,
+			validate: validateSetReleaseRetentionKeepLast,
+		},
+		{
+			name:     "valid keep within",
+			success:  true,
+			key:      "backends.dev.configs.releaseRetentionKeepWithin",
+			val:      "720h",
+			validate: validateSetReleaseRetentionKeepWithin,
+		},
+		{
+			name:     "invalid keep within not duration",
+			success:  false,
+			key:      "backends.dev.configs.releaseRetentionKeepWithin",
+			val:      "30 days",
+			validate: validateSetReleaseRetentionKeepWithin,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.validate(config, tc.key, tc.val)
+			assert.Equal(t, tc.success, err == nil)
+		})
+	}
+}
+
+func TestValidateTemplateRegistry(t *testing.T) {
+	config := &v1.Config{
+		Templates: map[string]*v1.TemplateRegistryConfig{
+			"golden":  {Source: "https://github.com/org/templates.git", Version: "v1.0.0"},
+			"default": {Source: "oci://ghcr.io/org/templates"},
+		},
+	}
+
+	assert.NoError(t, validateSetTemplateRegistry(config, "templates.local", &v1.TemplateRegistryConfig{Source: "/dir/to/templates"}))
+	assert.ErrorIs(t, validateSetTemplateRegistry(config, "templates.local", &v1.TemplateRegistryConfig{Version: "v1.0.0"}), ErrEmptyTemplateSource)
+	assert.NoError(t, validateSetTemplateRegistryVersion(config, "templates.default.version", "v1.0.0"))
+	assert.ErrorIs(t, validateSetTemplateRegistryVersion(config, "templates.local.version", "v1.0.0"), ErrEmptyTemplateSource)
+	assert.NoError(t, validateUnsetTemplateRegistrySource(config, "templates.default.source"))
+	assert.ErrorIs(t, validateUnsetTemplateRegistrySource(config, "templates.golden.source"), ErrUnsetTemplateSource)
+}
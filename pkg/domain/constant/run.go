@@ -1,10 +1,15 @@
 package constant
 
-import "strings"
+import (
+	"errors"
+	"strings"
+	"time"
+)
 
 type (
-	RunType   string
-	RunStatus string
+	RunType           string
+	RunStatus         string
+	RunRecoveryPolicy string
 )
 
 const (
@@ -22,6 +27,18 @@ const (
 	RunResultCancelled  string    = "{\"result\":\"Operation Cancelled\"}"
 )
 
+// These constants configure the run queue that executes the async runs.
+const (
+	RunRecoveryPolicyFail         RunRecoveryPolicy = "fail"
+	RunRecoveryPolicyRequeue      RunRecoveryPolicy = "requeue"
+	RunQueueDefaultLeaseDuration                    = 1 * time.Minute
+	RunQueueDefaultPollInterval                     = 5 * time.Second
+	RunQueueDefaultMaxAttempts                      = 3
+	RunQueueDefaultRecoveryPolicy                   = RunRecoveryPolicyFail
+)
+
+var ErrInvalidRunRecoveryPolicy = errors.New("run recovery policy should be one of the following: [fail, requeue]")
+
 // ParseRunType parses a string into a RunType.
 // If the string is not a valid RunType, it returns an error.
 func ParseRunType(s string) (RunType, error) {
@@ -59,3 +76,16 @@ func ParseRunStatus(s string) (RunStatus, error) {
 		return RunStatus(""), nil
 	}
 }
+
+// ParseRunRecoveryPolicy parses a string into a RunRecoveryPolicy.
+// If the string is not a valid RunRecoveryPolicy, it returns an error.
+func ParseRunRecoveryPolicy(s string) (RunRecoveryPolicy, error) {
+	switch strings.ToLower(s) {
+	case string(RunRecoveryPolicyFail):
+		return RunRecoveryPolicyFail, nil
+	case string(RunRecoveryPolicyRequeue):
+		return RunRecoveryPolicyRequeue, nil
+	default:
+		return RunRecoveryPolicy(""), ErrInvalidRunRecoveryPolicy
+	}
+}
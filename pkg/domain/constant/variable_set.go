@@ -2,6 +2,14 @@ package constant
 
 import "errors"
 
+// The labels a run is matched against variable sets with, in addition to the
+// labels of its project, stack and workspace.
+const (
+	VariableSetLabelProject   = "project"
+	VariableSetLabelStack     = "stack"
+	VariableSetLabelWorkspace = "workspace"
+)
+
 var (
 	ErrInvalidVariableSetName = errors.New("variable set name can only have alphanumeric characters and underscores with [a-zA-Z0-9_]")
 	ErrEmptyVariableSetLabels = errors.New("variable set labels should not be empty")
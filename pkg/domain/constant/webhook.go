@@ -0,0 +1,93 @@
+package constant
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+type (
+	WebhookEvent          string
+	WebhookFormat         string
+	WebhookDeliveryStatus string
+)
+
+// These constants represent the events a webhook can subscribe to, the payload
+// formats supported by the built-in formatters and the status of a delivery.
+const (
+	WebhookEventPing             WebhookEvent          = "ping"
+	WebhookEventRunQueued        WebhookEvent          = "run.queued"
+	WebhookEventRunSucceeded     WebhookEvent          = "run.succeeded"
+	WebhookEventRunFailed        WebhookEvent          = "run.failed"
+	WebhookEventRunCancelled     WebhookEvent          = "run.cancelled"
+	WebhookEventStackOutOfSync   WebhookEvent          = "stack.outOfSync"
+	WebhookFormatJSON            WebhookFormat         = "json"
+	WebhookFormatSlack           WebhookFormat         = "slack"
+	WebhookDeliverySucceeded     WebhookDeliveryStatus = "Succeeded"
+	WebhookDeliveryFailed        WebhookDeliveryStatus = "Failed"
+	WebhookSignatureHeader                             = "X-Kusion-Signature-256"
+	WebhookEventHeader                                 = "X-Kusion-Event"
+	WebhookDeliveryHeader                              = "X-Kusion-Delivery"
+	WebhookTimestampHeader                             = "X-Kusion-Timestamp"
+	WebhookDefaultMaxAttempts                          = 5
+	WebhookDefaultInitialBackoff                       = 2 * time.Second
+	WebhookDefaultMaxBackoff                           = 2 * time.Minute
+	WebhookDefaultRequestTimeout                       = 10 * time.Second
+	WebhookMaxResponseBodyLength                       = 1024
+)
+
+var (
+	ErrInvalidWebhookName   = errors.New("webhook name can only have alphanumeric characters and underscores with [a-zA-Z0-9_]")
+	ErrInvalidWebhookEvent  = errors.New("webhook event should be one of the following: [run.queued, run.succeeded, run.failed, run.cancelled, stack.outOfSync]")
+	ErrInvalidWebhookFormat = errors.New("webhook format should be one of the following: [json, slack]")
+)
+
+// WebhookEvents lists all the events a webhook can subscribe to.
+var WebhookEvents = []WebhookEvent{
+	WebhookEventRunQueued,
+	WebhookEventRunSucceeded,
+	WebhookEventRunFailed,
+	WebhookEventRunCancelled,
+	WebhookEventStackOutOfSync,
+}
+
+// ParseWebhookEvent parses a string into a WebhookEvent.
+// If the string is not a valid WebhookEvent, it returns an error.
+func ParseWebhookEvent(s string) (WebhookEvent, error) {
+	for _, event := range WebhookEvents {
+		if strings.EqualFold(s, string(event)) {
+			return event, nil
+		}
+	}
+	return WebhookEvent(""), ErrInvalidWebhookEvent
+}
+
+// ParseWebhookFormat parses a string into a WebhookFormat. An empty string
+// falls back to the generic JSON format.
+func ParseWebhookFormat(s string) (WebhookFormat, error) {
+	switch strings.ToLower(s) {
+	case "", string(WebhookFormatJSON):
+		return WebhookFormatJSON, nil
+	case string(WebhookFormatSlack):
+		return WebhookFormatSlack, nil
+	default:
+		return WebhookFormat(""), ErrInvalidWebhookFormat
+	}
+}
+
+// WebhookEventForRunStatus maps the terminal or queued status of a run to the
+// webhook event fired for that transition.
+func WebhookEventForRunStatus(status RunStatus) (WebhookEvent, bool) {
+	switch status {
+	case RunStatusQueued:
+		return WebhookEventRunQueued, true
+	case RunStatusSucceeded:
+		return WebhookEventRunSucceeded, true
+	case RunStatusFailed:
+		return WebhookEventRunFailed, true
+	case RunStatusCancelled:
+		return WebhookEventRunCancelled, true
+	default:
+		return WebhookEvent(""), false
+	}
+}
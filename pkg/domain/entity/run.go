@@ -26,12 +26,32 @@ type Run struct {
 	Trace string `yaml:"trace" json:"trace"`
 	// Logs is the logs of the run.
 	Logs string `yaml:"logs" json:"logs"`
+	// Parameters is the serialized input of the run, used by the run queue
+	// to execute the run on any server replica.
+	Parameters string `yaml:"parameters,omitempty" json:"parameters,omitempty"`
+	// LeaseOwner is the identity of the worker currently executing the run.
+	LeaseOwner string `yaml:"leaseOwner,omitempty" json:"leaseOwner,omitempty"`
+	// LeaseExpiresAt is the time the lease of the worker expires. A run in
+	// progress with an expired lease is considered orphaned.
+	LeaseExpiresAt *time.Time `yaml:"leaseExpiresAt,omitempty" json:"leaseExpiresAt,omitempty"`
+	// Attempts is the number of times the run has been claimed by a worker.
+	Attempts int `yaml:"attempts,omitempty" json:"attempts,omitempty"`
+	// VariableSets are the versions of the variable sets injected into the run.
+	VariableSets []RunVariableSet `yaml:"variableSets,omitempty" json:"variableSets,omitempty"`
 	// CreationTimestamp is the timestamp of the created for the run.
 	CreationTimestamp time.Time `yaml:"creationTimestamp,omitempty" json:"creationTimestamp,omitempty"`
 	// UpdateTimestamp is the timestamp of the updated for the run.
 	UpdateTimestamp time.Time `yaml:"updateTimestamp,omitempty" json:"updateTimestamp,omitempty"`
 }
 
+// RunVariableSet is the version of a variable set a run was executed with.
+type RunVariableSet struct {
+	// Name is the name of the variable set.
+	Name string `yaml:"name" json:"name"`
+	// Version is the version of the variable set.
+	Version uint `yaml:"version" json:"version"`
+}
+
 // RunResult represents the result of the run.
 type RunResult struct {
 	// ExitCode is the exit code of the run.
@@ -27,6 +27,7 @@ type Variable struct {
 type VariableFilter struct {
 	Name        string
 	VariableSet string
+	Type        VariableType
 	Pagination  *Pagination
 	FetchAll    bool
 }
@@ -8,6 +8,9 @@ type VariableSet struct {
 	Name string `yaml:"name,omitempty" json:"name,omitempty"`
 	// Labels clarifies the scope of the variable set.
 	Labels map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	// Version is the version of the variable set, increased whenever the
+	// variable set or any of its variables changes.
+	Version uint `yaml:"version,omitempty" json:"version,omitempty"`
 }
 
 // VariableSetFilter represents the filter conditions to list variable sets.
@@ -0,0 +1,161 @@
+package entity
+
+import (
+	"errors"
+	"net/url"
+	"time"
+
+	"kusionstack.io/kusion/pkg/domain/constant"
+)
+
+// Webhook represents an outbound subscription to run lifecycle events. A
+// webhook can be scoped to an organization, a project or a stack, and a zero
+// scope ID matches any value.
+type Webhook struct {
+	// ID is the id of the webhook.
+	ID uint `yaml:"id" json:"id"`
+	// Name is the name of the webhook.
+	Name string `yaml:"name" json:"name"`
+	// Description is a human-readable description of the webhook.
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	// URL is the receiver address the events are posted to.
+	URL string `yaml:"url" json:"url"`
+	// Secret is the key used to sign the payloads with HMAC-SHA256.
+	Secret string `yaml:"secret,omitempty" json:"secret,omitempty"`
+	// Format is the payload format of the receiver, json or slack.
+	Format constant.WebhookFormat `yaml:"format" json:"format"`
+	// Events is the list of subscribed events. Empty means all events.
+	Events []string `yaml:"events,omitempty" json:"events,omitempty"`
+	// OrganizationID restricts the webhook to the runs of an organization.
+	OrganizationID uint `yaml:"organizationID,omitempty" json:"organizationID,omitempty"`
+	// ProjectID restricts the webhook to the runs of a project.
+	ProjectID uint `yaml:"projectID,omitempty" json:"projectID,omitempty"`
+	// StackID restricts the webhook to the runs of a stack.
+	StackID uint `yaml:"stackID,omitempty" json:"stackID,omitempty"`
+	// Enabled indicates whether the events are delivered to the webhook.
+	Enabled bool `yaml:"enabled" json:"enabled"`
+	// CreationTimestamp is the timestamp of the created for the webhook.
+	CreationTimestamp time.Time `yaml:"creationTimestamp,omitempty" json:"creationTimestamp,omitempty"`
+	// UpdateTimestamp is the timestamp of the updated for the webhook.
+	UpdateTimestamp time.Time `yaml:"updateTimestamp,omitempty" json:"updateTimestamp,omitempty"`
+}
+
+// WebhookFilter represents the filter conditions to list webhooks.
+type WebhookFilter struct {
+	OrganizationID uint
+	ProjectID      uint
+	StackID        uint
+	Enabled        *bool
+	Pagination     *Pagination
+}
+
+// WebhookListResult represents the result of listing webhooks.
+type WebhookListResult struct {
+	Webhooks []*Webhook
+	Total    int
+}
+
+// WebhookDelivery records a single delivery of an event to a webhook,
+// including every retry attempt.
+type WebhookDelivery struct {
+	// ID is the id of the delivery.
+	ID uint `yaml:"id" json:"id"`
+	// WebhookID is the id of the webhook the event is delivered to.
+	WebhookID uint `yaml:"webhookID" json:"webhookID"`
+	// Event is the event that triggered the delivery.
+	Event constant.WebhookEvent `yaml:"event" json:"event"`
+	// RunID is the id of the run that triggered the delivery, if any.
+	RunID uint `yaml:"runID,omitempty" json:"runID,omitempty"`
+	// Payload is the request body sent to the receiver.
+	Payload string `yaml:"payload" json:"payload"`
+	// Status is the final status of the delivery.
+	Status constant.WebhookDeliveryStatus `yaml:"status" json:"status"`
+	// Attempts is the number of requests sent to the receiver.
+	Attempts int `yaml:"attempts" json:"attempts"`
+	// ResponseCode is the HTTP status code of the last attempt.
+	ResponseCode int `yaml:"responseCode,omitempty" json:"responseCode,omitempty"`
+	// ResponseBody is the truncated response body of the last attempt.
+	ResponseBody string `yaml:"responseBody,omitempty" json:"responseBody,omitempty"`
+	// Error is the error message of the last failed attempt.
+	Error string `yaml:"error,omitempty" json:"error,omitempty"`
+	// Duration is the total time spent delivering the event.
+	Duration time.Duration `yaml:"duration" json:"duration"`
+	// CreationTimestamp is the timestamp of the created for the delivery.
+	CreationTimestamp time.Time `yaml:"creationTimestamp,omitempty" json:"creationTimestamp,omitempty"`
+}
+
+// WebhookDeliveryFilter represents the filter conditions to list deliveries.
+type WebhookDeliveryFilter struct {
+	WebhookID  uint
+	RunID      uint
+	Status     string
+	Pagination *Pagination
+}
+
+// WebhookDeliveryListResult represents the result of listing deliveries.
+type WebhookDeliveryListResult struct {
+	Deliveries []*WebhookDelivery
+	Total      int
+}
+
+// Validate checks if the webhook is valid.
+func (w *Webhook) Validate() error {
+	if w == nil {
+		return errors.New("webhook is nil")
+	}
+
+	if w.Name == "" {
+		return errors.New("empty webhook name")
+	}
+
+	u, err := url.Parse(w.URL)
+	if err != nil || u.Host == "" {
+		return constant.ErrInvalidURL
+	}
+
+	if _, err := constant.ParseWebhookFormat(string(w.Format)); err != nil {
+		return err
+	}
+
+	for _, event := range w.Events {
+		if _, err := constant.ParseWebhookEvent(event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Subscribes reports whether the webhook should receive the event.
+func (w *Webhook) Subscribes(event constant.WebhookEvent) bool {
+	if event == constant.WebhookEventPing || len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if parsed, err := constant.ParseWebhookEvent(e); err == nil && parsed == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches reports whether the webhook scope covers the given stack.
+func (w *Webhook) Matches(stack *Stack) bool {
+	if w.OrganizationID == 0 && w.ProjectID == 0 && w.StackID == 0 {
+		return true
+	}
+	if stack == nil {
+		return false
+	}
+	if w.StackID != 0 && w.StackID != stack.ID {
+		return false
+	}
+	if w.ProjectID != 0 && (stack.Project == nil || w.ProjectID != stack.Project.ID) {
+		return false
+	}
+	if w.OrganizationID != 0 &&
+		(stack.Project == nil || stack.Project.Organization == nil || w.OrganizationID != stack.Project.Organization.ID) {
+		return false
+	}
+	return true
+}
@@ -2,7 +2,9 @@ package repository
 
 import (
 	"context"
+	"time"
 
+	"kusionstack.io/kusion/pkg/domain/constant"
 	"kusionstack.io/kusion/pkg/domain/entity"
 )
 
@@ -151,6 +153,24 @@ type RunRepository interface {
 	Get(ctx context.Context, id uint) (*entity.Run, error)
 	// List retrieves all existing run.
 	List(ctx context.Context, filter *entity.RunFilter, sortOptions *entity.SortOptions) (*entity.RunListResult, error)
+	// Claim leases the oldest pending run whose stack and workspace have no
+	// run in progress to the given owner. It returns nil if no run is claimable.
+	Claim(ctx context.Context, owner string, leaseDuration time.Duration) (*entity.Run, error)
+	// RenewLease extends the lease of a run held by the given owner.
+	RenewLease(ctx context.Context, id uint, owner string, leaseDuration time.Duration) error
+	// ReleaseLease clears the lease of a run held by the given owner.
+	ReleaseLease(ctx context.Context, id uint, owner string) error
+	// ListExpired retrieves the runs in progress whose lease has expired.
+	ListExpired(ctx context.Context, now time.Time) ([]*entity.Run, error)
+	// Recover moves an orphaned run to the given status if its lease is still
+	// held by the given owner and has expired. It reports whether the run moved.
+	Recover(ctx context.Context, id uint, owner string, now time.Time, status constant.RunStatus, result string) (bool, error)
+	// Finish moves a run in progress to the given status if its lease is still
+	// held by the given owner. It reports whether the run moved.
+	Finish(ctx context.Context, id uint, owner string, status constant.RunStatus, result, logs string) (bool, error)
+	// UpdateVariableSets updates the variable sets injected into a run only,
+	// leaving the status, result and logs written concurrently untouched.
+	UpdateVariableSets(ctx context.Context, id uint, variableSets []entity.RunVariableSet) error
 }
 
 // VariableSetRepository is an interface that defines the repository operations
@@ -182,3 +202,28 @@ type VariableRepository interface {
 	// List retrieves existing variable with filter and sort options.
 	List(ctx context.Context, filter *entity.VariableFilter, sortOptions *entity.SortOptions) (*entity.VariableListResult, error)
 }
+
+// WebhookRepository is an interface that defines the repository operations
+// for webhooks. It follows the principles of domain-driven design (DDD).
+type WebhookRepository interface {
+	// Create creates a new webhook.
+	Create(ctx context.Context, webhook *entity.Webhook) error
+	// Delete deletes a webhook by its ID.
+	Delete(ctx context.Context, id uint) error
+	// Update updates an existing webhook.
+	Update(ctx context.Context, webhook *entity.Webhook) error
+	// Get retrieves a webhook by its ID.
+	Get(ctx context.Context, id uint) (*entity.Webhook, error)
+	// List retrieves existing webhooks with filter and sort options.
+	List(ctx context.Context, filter *entity.WebhookFilter, sortOptions *entity.SortOptions) (*entity.WebhookListResult, error)
+}
+
+// WebhookDeliveryRepository is an interface that defines the repository
+// operations for webhook deliveries. It follows the principles of
+// domain-driven design (DDD).
+type WebhookDeliveryRepository interface {
+	// Create records a new webhook delivery.
+	Create(ctx context.Context, delivery *entity.WebhookDelivery) error
+	// List retrieves existing webhook deliveries with filter and sort options.
+	List(ctx context.Context, filter *entity.WebhookDeliveryFilter, sortOptions *entity.SortOptions) (*entity.WebhookDeliveryListResult, error)
+}
@@ -1,11 +1,15 @@
 package request
 
 import (
+	"encoding/json"
 	"net/http"
 )
 
 type StackImportRequest struct {
 	ImportedResources map[string]string `json:"importedResources"`
+	// Plan is the plan file written by `kusion preview --out`. If set, exactly
+	// the planned changes are applied.
+	Plan json.RawMessage `json:"plan,omitempty" swaggertype:"object"`
 }
 
 func (payload *StackImportRequest) Decode(r *http.Request) error {
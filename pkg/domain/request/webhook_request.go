@@ -0,0 +1,108 @@
+package request
+
+import (
+	"net/http"
+
+	"kusionstack.io/kusion/pkg/domain/constant"
+)
+
+// CreateWebhookRequest represents the create request structure for
+// webhook.
+type CreateWebhookRequest struct {
+	// Name is the name of the webhook.
+	Name string `json:"name" binding:"required"`
+	// Description is a human-readable description of the webhook.
+	Description string `json:"description"`
+	// URL is the receiver address the events are posted to.
+	URL string `json:"url" binding:"required"`
+	// Secret is the key used to sign the payloads with HMAC-SHA256.
+	Secret string `json:"secret"`
+	// Format is the payload format of the receiver, json or slack.
+	Format string `json:"format"`
+	// Events is the list of subscribed events. Empty means all events.
+	Events []string `json:"events"`
+	// OrganizationID restricts the webhook to the runs of an organization.
+	OrganizationID uint `json:"organizationID"`
+	// ProjectID restricts the webhook to the runs of a project.
+	ProjectID uint `json:"projectID"`
+	// StackID restricts the webhook to the runs of a stack.
+	StackID uint `json:"stackID"`
+	// Enabled indicates whether the events are delivered to the webhook.
+	Enabled bool `json:"enabled"`
+}
+
+// UpdateWebhookRequest represents the update request structure for
+// webhook.
+type UpdateWebhookRequest struct {
+	// ID is the id of the webhook.
+	ID uint `json:"id" binding:"required"`
+	// Name is the name of the webhook.
+	Name string `json:"name"`
+	// Description is a human-readable description of the webhook.
+	Description string `json:"description"`
+	// URL is the receiver address the events are posted to.
+	URL string `json:"url"`
+	// Secret is the key used to sign the payloads with HMAC-SHA256.
+	Secret string `json:"secret"`
+	// Format is the payload format of the receiver, json or slack.
+	Format string `json:"format"`
+	// Events is the list of subscribed events.
+	Events []string `json:"events"`
+	// OrganizationID restricts the webhook to the runs of an organization.
+	OrganizationID *uint `json:"organizationID"`
+	// ProjectID restricts the webhook to the runs of a project.
+	ProjectID *uint `json:"projectID"`
+	// StackID restricts the webhook to the runs of a stack.
+	StackID *uint `json:"stackID"`
+	// Enabled indicates whether the events are delivered to the webhook.
+	Enabled *bool `json:"enabled"`
+}
+
+func (payload *CreateWebhookRequest) Validate() error {
+	// Validate webhook name
+	if validName(payload.Name) {
+		return constant.ErrInvalidWebhookName
+	}
+
+	if err := validURL(payload.URL); err != nil {
+		return err
+	}
+
+	return validateWebhookFormatAndEvents(payload.Format, payload.Events)
+}
+
+func (payload *UpdateWebhookRequest) Validate() error {
+	if payload.Name != "" && validName(payload.Name) {
+		return constant.ErrInvalidWebhookName
+	}
+
+	if payload.URL != "" {
+		if err := validURL(payload.URL); err != nil {
+			return err
+		}
+	}
+
+	return validateWebhookFormatAndEvents(payload.Format, payload.Events)
+}
+
+func (payload *CreateWebhookRequest) Decode(r *http.Request) error {
+	return decode(r, payload)
+}
+
+func (payload *UpdateWebhookRequest) Decode(r *http.Request) error {
+	return decode(r, payload)
+}
+
+func validateWebhookFormatAndEvents(format string, events []string) error {
+	if _, err := constant.ParseWebhookFormat(format); err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		if _, err := constant.ParseWebhookEvent(event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -8,3 +8,12 @@ type PaginatedVariableResponse struct {
 	CurrentPage int                `json:"currentPage"`
 	PageSize    int                `json:"pageSize"`
 }
+
+// VariableKeyRotationResponse is the result of re-encrypting the CipherText
+// variables with the current key.
+type VariableKeyRotationResponse struct {
+	// Rotated is the number of the variables re-encrypted.
+	Rotated int `json:"rotated"`
+	// Total is the number of the CipherText variables.
+	Total int `json:"total"`
+}
@@ -0,0 +1,17 @@
+package response
+
+import "kusionstack.io/kusion/pkg/domain/entity"
+
+type PaginatedWebhookResponse struct {
+	Webhooks    []*entity.Webhook `json:"webhooks"`
+	Total       int               `json:"total"`
+	CurrentPage int               `json:"currentPage"`
+	PageSize    int               `json:"pageSize"`
+}
+
+type PaginatedWebhookDeliveryResponse struct {
+	Deliveries  []*entity.WebhookDelivery `json:"deliveries"`
+	Total       int                       `json:"total"`
+	CurrentPage int                       `json:"currentPage"`
+	PageSize    int                       `json:"pageSize"`
+}
@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"reflect"
 	"strings"
 	"sync"
 	"time"
@@ -21,6 +20,7 @@ import (
 	"kusionstack.io/kusion/pkg/engine/operation"
 	"kusionstack.io/kusion/pkg/engine/operation/models"
 	"kusionstack.io/kusion/pkg/engine/printers"
+	"kusionstack.io/kusion/pkg/engine/printers/status"
 	"kusionstack.io/kusion/pkg/engine/release"
 	"kusionstack.io/kusion/pkg/engine/resource/graph"
 	"kusionstack.io/kusion/pkg/engine/runtime"
@@ -61,14 +61,24 @@ func Apply(
 	runLogger := logutil.GetRunLogger(ctx)
 	var err error
 
+	// Get the readiness policies configured in the workspace, before any resource is applied.
+	var policies status.Policies
+	if o.Watch && !o.DryRun {
+		if policies, err = status.PoliciesFromContext(rel.Spec.Context); err != nil {
+			return nil, fmt.Errorf("invalid readiness policies: %w", err)
+		}
+	}
+
 	// construct the apply operation
 	ac := &operation.ApplyOperation{
 		Operation: models.Operation{
-			Stack:          changes.Stack(),
-			ReleaseStorage: storage,
-			MsgCh:          make(chan models.Message),
-			IgnoreFields:   o.IgnoreFields,
-			Sem:            semaphore.New(int64(o.MaxConcurrent)),
+			Stack:           changes.Stack(),
+			ReleaseStorage:  storage,
+			MsgCh:           make(chan models.Message),
+			IgnoreFields:    o.IgnoreFields,
+			Sem:             semaphore.New(int64(o.MaxConcurrent)),
+			ContinueOnError: o.ContinueOnError,
+			Ctx:             ctx,
 		},
 	}
 
@@ -101,7 +111,9 @@ func Apply(
 		rel,
 	)
 
-	watchErrCh := make(chan error)
+	// The watch error channel is buffered so that the watching go-routine never blocks on it, even if
+	// the apply fails and returns without waiting for the watch.
+	watchErrCh := make(chan error, 1)
 	// Apply while watching the resources.
 	if o.Watch && !o.DryRun {
 		logutil.LogToAll(sysLogger, runLogger, "Info", fmt.Sprintf("Start watching resources with timeout %d seconds ...", o.WatchTimeout))
@@ -115,6 +127,8 @@ func Apply(
 			o.WatchTimeout,
 			gph,
 			rel,
+			o.Atomic,
+			policies,
 		)
 		logutil.LogToAll(sysLogger, runLogger, "Info", "Watch started ...")
 	}
@@ -141,6 +155,10 @@ func Apply(
 		})
 		if rsp != nil {
 			upRel = rsp.Release
+			if rsp.Report != nil && rsp.Report.HasFailures() {
+				logutil.LogToAll(sysLogger, runLogger, "Error", fmt.Sprintf("Apply failed! Resources: %d succeeded, %d failed, %d skipped.",
+					rsp.Report.Count(models.Success), rsp.Report.Count(models.Failed), rsp.Report.Count(models.Skip)))
+			}
 		}
 		if v1.IsErr(st) {
 			return nil, fmt.Errorf("apply failed, status:\n%v", st)
@@ -151,17 +169,8 @@ func Apply(
 	wg.Wait()
 	// Wait for watchWg closed if need to perform watching.
 	if o.Watch && !o.DryRun {
-		shouldBreak := false
-		for !shouldBreak {
-			select {
-			case watchErr := <-watchErrCh:
-				if watchErr != nil {
-					return nil, watchErr
-				}
-				shouldBreak = true
-			default:
-				continue
-			}
+		if watchErr := <-watchErrCh; watchErr != nil {
+			return nil, watchErr
 		}
 	}
 
@@ -198,6 +207,8 @@ func Watch(
 	watchTimeout int,
 	gph *apiv1.Graph,
 	rel *apiv1.Release,
+	atomic bool,
+	policies status.Policies,
 ) {
 	resourceMap := make(map[string]apiv1.Resource)
 	toBeWatched := apiv1.Resources{}
@@ -216,10 +227,7 @@ func Watch(
 				cmdutil.RecoverErr(err)
 				log.Error(*err)
 			}
-			if *err != nil {
-				if !releaseCreated {
-					return
-				}
+			if *err != nil && releaseCreated {
 				release.UpdateReleasePhase(rel, apiv1.ReleasePhaseFailed, relLock)
 				_ = release.UpdateApplyRelease(releaseStorage, rel, dryRun, relLock)
 			}
@@ -234,13 +242,14 @@ func Watch(
 			panic(fmt.Errorf("failed to init runtimes: %s", s.String()))
 		}
 
-		// Prepare the tables for printing the details of the resources.
-		ticker := time.NewTicker(time.Millisecond * 1000)
-		defer ticker.Stop()
-
-		// Record the watched and finished resources.
-		watching := make(map[string]bool)
+		// Record the finished resources, which are reported by the watching
+		// go-routines, so that no polling is needed.
+		done := make(chan string, len(toBeWatched))
 		finished := make(map[string]bool)
+		// Record the watch context of each resource to tell the timed out ones,
+		// which fail the apply in the atomic mode.
+		watchCtxs := make(map[string]context.Context, len(toBeWatched))
+		var timedOut []string
 
 		logutil.LogToAll(sysLogger, runLogger, "Info", "Total resources to watch: ", len(toBeWatched))
 		for !(len(finished) == len(toBeWatched)) {
@@ -248,17 +257,19 @@ func Watch(
 			// Get the resource ID to be watched.
 			case id := <-ac.WatchCh:
 				res := resourceMap[id]
-				// Set the timeout duration for watch context, here we set an experiential value of 60 minutes.
-				ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(watchTimeout))
-				ctx = context.WithValue(ctx, middleware.APILoggerKey, sysLogger)
-				ctx = context.WithValue(ctx, middleware.RunLoggerKey, runLogger)
+				// Set the timeout duration for watch context.
+				watchCtx, cancel := context.WithTimeout(ctx, time.Second*time.Duration(watchTimeout))
+				watchCtx = context.WithValue(watchCtx, middleware.APILoggerKey, sysLogger)
+				watchCtx = context.WithValue(watchCtx, middleware.RunLoggerKey, runLogger)
 				defer cancel()
+				watchCtxs[id] = watchCtx
 
 				// Get the event channel for watching the resource.
-				rsp := runtimes[res.Type].Watch(ctx, &runtime.WatchRequest{Resource: &res})
+				rsp := runtimes[res.Type].Watch(watchCtx, &runtime.WatchRequest{Resource: &res})
 				logutil.LogToAll(sysLogger, runLogger, "Info", fmt.Sprintf("Watching resource rsp: %v", rsp))
 				if rsp == nil {
 					log.Debug("unsupported resource type: %s", res.Type)
+					done <- id
 					continue
 				}
 				if v1.IsErr(rsp.Status) {
@@ -266,36 +277,39 @@ func Watch(
 				}
 
 				w := rsp.Watchers
-				logutil.LogToAll(sysLogger, runLogger, "Info", "setting finished to false...", "id", id, "timeElapsed", time.Now().String())
-				watching[id] = false
+				logutil.LogToAll(sysLogger, runLogger, "Info", "watching resource...", "id", id, "timeElapsed", time.Now().String())
 
 				// Setup a go-routine to concurrently watch K8s and TF resources.
 				if res.Type == apiv1.Kubernetes {
 					healthPolicy, kind := getHealthPolicy(&res)
-					go watchK8sResources(ctx, id, kind, w.Watchers, watching, gph, dryRun, healthPolicy, rel)
+					go watchK8sResources(watchCtx, id, kind, w, done, gph, dryRun, healthPolicy, policies, rel)
 				} else if res.Type == apiv1.Terraform {
-					go watchTFResources(ctx, id, w.TFWatcher, watching, dryRun, rel)
+					go watchTFResources(watchCtx, id, w.TFWatcher, done, gph, dryRun, rel)
 				} else {
 					log.Debug("unsupported resource type to watch: %s", string(res.Type))
+					done <- id
 					continue
 				}
-			// Refresh the tables printing details of the resources to be watched.
-			default:
-				for id := range watching {
-					logutil.LogToAll(sysLogger, runLogger, "Info", "watching resource...", "id", id, "timeElapsed", time.Now().String())
-					if watching[id] {
-						finished[id] = true
-						logutil.LogToAll(sysLogger, runLogger, "Info", "finished watching. resource is reconciled...", "id", id, "timeElapsed", time.Now().String())
-						delete(watching, id)
-						resource := graph.FindGraphResourceByID(gph.Resources, id)
-						if resource != nil {
-							resource.Status = apiv1.Reconciled
-						}
-					}
+			case id := <-done:
+				finished[id] = true
+				if watchCtx, ok := watchCtxs[id]; ok && errors.Is(watchCtx.Err(), context.DeadlineExceeded) {
+					timedOut = append(timedOut, id)
 				}
-				<-ticker.C
+				logutil.LogToAll(sysLogger, runLogger, "Info", "finished watching...", "id", id, "timeElapsed", time.Now().String())
+
+			// Stop watching once the apply is cancelled or has returned on failure, since the
+			// resources failed or skipped never arrive to be watched.
+			case <-ctx.Done():
+				if *err == nil {
+					*err = fmt.Errorf("watch cancelled: %w", ctx.Err())
+				}
+				logutil.LogToAll(sysLogger, runLogger, "Info", "watch cancelled", "error", ctx.Err())
+				return
 			}
 		}
+		if atomic && len(timedOut) > 0 {
+			*err = fmt.Errorf("watch timed out before resources reconciled: %s", strings.Join(timedOut, ", "))
+		}
 	}()
 }
 
@@ -358,6 +372,11 @@ func ProcessApplyDetails(
 			// Update the progressbar and spinner printer according to the operation result.
 			switch msg.OpResult {
 			case models.Success, models.Skip:
+				// The resource is not applied because of the failed resources.
+				if msg.OpResult == models.Skip && msg.OpErr != nil {
+					logutil.LogToAll(sysLogger, runLogger, "Warn", fmt.Sprintf("Skipped %s as: %s", msg.ResourceID, msg.OpErr.Error()))
+					break
+				}
 				var title string
 				if changeStep.Action == models.UnChanged {
 					title = fmt.Sprintf("Skipped %s", changeStep.ID)
@@ -419,11 +438,12 @@ func getHealthPolicy(res *apiv1.Resource) (healthPolicy interface{}, kind string
 func watchK8sResources(
 	ctx context.Context,
 	id, kind string,
-	chs []<-chan watch.Event,
-	watching map[string]bool,
+	w *runtime.SequentialWatchers,
+	done chan<- string,
 	gph *apiv1.Graph,
 	dryRun bool,
 	healthPolicy interface{},
+	policies status.Policies,
 	rel *apiv1.Release,
 ) {
 	defer func() {
@@ -440,6 +460,9 @@ func watchK8sResources(
 			_ = release.UpdateApplyRelease(releaseStorage, rel, dryRun, relLock)
 		}
 	}()
+	// Report the resource as finished, even if it failed, so that the watch
+	// does not wait on it forever.
+	defer func() { done <- id }()
 
 	sysLogger := logutil.GetLogger(ctx)
 	runLogger := logutil.GetRunLogger(ctx)
@@ -450,81 +473,62 @@ func watchK8sResources(
 		resource.Status = apiv1.ReconcileFail
 	}
 
-	// Resources selects
-	cases := createSelectCases(chs)
-	// Default select
-	cases = append(cases, reflect.SelectCase{
-		Dir:  reflect.SelectDefault,
-		Chan: reflect.Value{},
-		Send: reflect.Value{},
-	})
-
 	// when both are ready, break the loop
 	ready := map[string]bool{}
 
-	for {
-		chosen, recv, recvOK := reflect.Select(cases)
-		if cases[chosen].Dir == reflect.SelectDefault {
-			// handle timeout
-			select {
-			case <-ctx.Done():
-				logutil.LogToAll(sysLogger, runLogger, "Info", "Watch timeout reached. Setting watching to true...", "id", id, "timeElapsed", time.Now().String())
-				watching[id] = true
-				return
-			default:
-				continue
-			}
-		}
-		if recvOK {
-			e := recv.Interface().(watch.Event)
-			o := e.Object.(*unstructured.Unstructured)
-			// var detail string
-			if e.Type == watch.Deleted {
-				ready["custom"] = true
-			} else {
-				// Restore to actual type
-				target := printers.Convert(o)
-				// Check reconcile status with customized health policy for specific resource
-				if healthPolicy != nil && kind == o.GetObjectKind().GroupVersionKind().Kind {
-					ready["custom"] = false
-					if code, ok := kcl.ConvertKCLCode(healthPolicy); ok {
-						resByte, err := yaml.Marshal(o.Object)
-						if err != nil {
-							log.Error(err)
-							return
-						}
-						kclResp, kclReady := printers.PrintCustomizedHealthCheck(code, resByte)
-						if kclReady {
-							ready["custom"] = true
-							logutil.LogToAll(sysLogger, runLogger, "Info", "Customized health check ready: ", "kclResp", kclResp, "timeElapsed", time.Now().String(), "id", id)
-						}
-					} else {
-						// Check reconcile status with default setup
-						ready["default"] = false
-						_, defaultReady := printers.Generate(target)
-						if defaultReady {
-							ready["default"] = true
-							logutil.LogToAll(sysLogger, runLogger, "Info", "Customized health check had a problem. Default health check ready: ", "timeElapsed", time.Now().String(), "id", id)
-						}
-						continue
+	// Block on the events until the resource is ready or the watch times out.
+	for e := range w.Events(ctx) {
+		o := e.Object.(*unstructured.Unstructured)
+		if e.Type == watch.Deleted {
+			ready["custom"] = true
+		} else {
+			// Check reconcile status with customized health policy for specific resource
+			if healthPolicy != nil && kind == o.GetObjectKind().GroupVersionKind().Kind {
+				ready["custom"] = false
+				if code, ok := kcl.ConvertKCLCode(healthPolicy); ok {
+					resByte, err := yaml.Marshal(o.Object)
+					if err != nil {
+						log.Error(err)
+						return
+					}
+					kclResp, kclReady := printers.PrintCustomizedHealthCheck(code, resByte)
+					if kclReady {
+						ready["custom"] = true
+						logutil.LogToAll(sysLogger, runLogger, "Info", "Customized health check ready: ", "kclResp", kclResp, "timeElapsed", time.Now().String(), "id", id)
 					}
 				} else {
 					// Check reconcile status with default setup
 					ready["default"] = false
-					_, defaultReady := printers.Generate(target)
+					_, defaultReady := printers.Status(o, policies)
 					if defaultReady {
 						ready["default"] = true
-						logutil.LogToAll(sysLogger, runLogger, "Info", "default health check ready: ", "timeElapsed", time.Now().String(), "id", id)
+						logutil.LogToAll(sysLogger, runLogger, "Info", "Customized health check had a problem. Default health check ready: ", "timeElapsed", time.Now().String(), "id", id)
 					}
-					continue
+				}
+			} else {
+				// Check reconcile status with default setup
+				ready["default"] = false
+				_, defaultReady := printers.Status(o, policies)
+				if defaultReady {
+					ready["default"] = true
+					logutil.LogToAll(sysLogger, runLogger, "Info", "default health check ready: ", "timeElapsed", time.Now().String(), "id", id)
 				}
 			}
 		}
 		// Mark ready for breaking loop
 		if allReady(ready) {
 			logutil.LogToAll(sysLogger, runLogger, "Info", "Kubernetes resource reconciled. Setting finished to true...", "id", id, "timeElapsed", time.Now().String())
-			watching[id] = true
-			break
+			if resource != nil {
+				resource.Status = apiv1.Reconciled
+			}
+			return
+		}
+	}
+
+	if ctx.Err() != nil {
+		logutil.LogToAll(sysLogger, runLogger, "Info", "Watch timeout reached. Setting finished to true...", "id", id, "timeElapsed", time.Now().String())
+		if resource != nil {
+			resource.Status = apiv1.Reconciled
 		}
 	}
 }
@@ -533,7 +537,8 @@ func watchTFResources(
 	ctx context.Context,
 	id string,
 	ch <-chan runtime.TFEvent,
-	watching map[string]bool,
+	done chan<- string,
+	gph *apiv1.Graph,
 	dryRun bool,
 	rel *apiv1.Release,
 ) {
@@ -551,45 +556,38 @@ func watchTFResources(
 			_ = release.UpdateApplyRelease(releaseStorage, rel, dryRun, relLock)
 		}
 	}()
+	// Report the resource as finished, even if it failed, so that the watch
+	// does not wait on it forever.
+	defer func() { done <- id }()
+
 	sysLogger := logutil.GetLogger(ctx)
 	runLogger := logutil.GetRunLogger(ctx)
 
-	var ready bool
-	for {
-		parts := strings.Split(id, engine.Separator)
-		// A valid Terraform resource ID should consist of 4 parts, including the information of the provider type
-		// and resource name, for example: hashicorp:random:random_password:example-dev-kawesome.
-		if len(parts) != 4 {
-			panic(fmt.Errorf("invalid Terraform resource id: %s", id))
-		}
-
-		tfEvent := <-ch
-		if tfEvent == runtime.TFApplying {
-			continue
-		} else {
-			ready = true
-		}
+	parts := strings.Split(id, engine.Separator)
+	// A valid Terraform resource ID should consist of 4 parts, including the information of the provider type
+	// and resource name, for example: hashicorp:random:random_password:example-dev-kawesome.
+	if len(parts) != 4 {
+		panic(fmt.Errorf("invalid Terraform resource id: %s", id))
+	}
 
-		// Mark ready for breaking loop
-		if ready {
-			watching[id] = true
+	for {
+		select {
+		case tfEvent, ok := <-ch:
+			if ok && tfEvent == runtime.TFApplying {
+				continue
+			}
 			logutil.LogToAll(sysLogger, runLogger, "Info", "Terraform resource apply completed. Setting finished to true...", "id", id, "timeElapsed", time.Now().String())
-			break
+			if resource := graph.FindGraphResourceByID(gph.Resources, id); resource != nil {
+				resource.Status = apiv1.Reconciled
+			}
+			return
+		case <-ctx.Done():
+			logutil.LogToAll(sysLogger, runLogger, "Info", "Watch timeout reached. Setting finished to true...", "id", id, "timeElapsed", time.Now().String())
+			return
 		}
 	}
 }
 
-func createSelectCases(chs []<-chan watch.Event) []reflect.SelectCase {
-	cases := make([]reflect.SelectCase, 0, len(chs))
-	for _, ch := range chs {
-		cases = append(cases, reflect.SelectCase{
-			Dir:  reflect.SelectRecv,
-			Chan: reflect.ValueOf(ch),
-		})
-	}
-	return cases
-}
-
 func allReady(ready map[string]bool) bool {
 	for _, r := range ready {
 		if !r {
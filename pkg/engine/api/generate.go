@@ -60,8 +60,9 @@ const JSONOutput = "json"
 // 	return i, nil
 // }
 
-// GenerateSpecWithSpinner calls generator to generate versioned Spec. Add a method wrapper for testing purposes.
-func GenerateSpecWithSpinner(project *v1.Project, stack *v1.Stack, workspace *v1.Workspace, noStyle bool) (*v1.Spec, error) {
+// GenerateSpecWithSpinner calls generator to generate versioned Spec, passing the parameters as
+// the arguments of the configuration code. Add a method wrapper for testing purposes.
+func GenerateSpecWithSpinner(project *v1.Project, stack *v1.Stack, workspace *v1.Workspace, parameters map[string]string, noStyle bool) (*v1.Spec, error) {
 	// Construct generator instance
 	defaultGenerator := &generator.DefaultGenerator{
 		Project:   project,
@@ -81,7 +82,7 @@ func GenerateSpecWithSpinner(project *v1.Project, stack *v1.Stack, workspace *v1
 	// style means color and prompt here. Currently, sp will be nil only when o.NoStyle is true
 	style := !noStyle && sp != nil
 
-	versionedSpec, err := defaultGenerator.Generate(stack.Path, nil)
+	versionedSpec, err := defaultGenerator.Generate(stack.Path, parameters)
 	if err != nil {
 		if style {
 			sp.Fail()
@@ -15,10 +15,12 @@
 package generator
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 
 	"gopkg.in/yaml.v2"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -29,6 +31,9 @@ import (
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
 	"kusionstack.io/kusion/pkg/engine/api/builders"
 	"kusionstack.io/kusion/pkg/engine/api/generate/run"
+	"kusionstack.io/kusion/pkg/oci"
+	"kusionstack.io/kusion/pkg/oci/client"
+	"kusionstack.io/kusion/pkg/oci/trust"
 	"kusionstack.io/kusion/pkg/util/io"
 	"kusionstack.io/kusion/pkg/util/kfile"
 )
@@ -60,7 +65,7 @@ func (g *DefaultGenerator) Generate(workDir string, params map[string]string) (*
 	}
 
 	// Copy dependent modules before call builder
-	err = CopyDependentModules(workDir)
+	err = CopyDependentModules(workDir, g.Workspace)
 	if err != nil {
 		return nil, err
 	}
@@ -85,18 +90,27 @@ func (g *DefaultGenerator) Generate(workDir string, params map[string]string) (*
 	return builder.Build(kclPkg, g.Project, g.Stack)
 }
 
-// CopyDependentModules copies dependent Kusion modules' generators to destination.
-func CopyDependentModules(workDir string) error {
+// dependentModule is a Kusion module declared as an OCI dependency in kcl.mod.
+type dependentModule struct {
+	dep pkg.Dependency
+	// source is the module binary downloaded with the kcl package.
+	source string
+	// dest is the module binary in the $KUSION_HOME modules directory to execute.
+	dest string
+}
+
+// dependentModules returns the Kusion modules declared as OCI dependencies in kcl.mod under the working directory.
+func dependentModules(workDir string) ([]*dependentModule, error) {
 	modFile := &pkg.ModFile{}
 	err := modFile.LoadModFile(filepath.Join(workDir, pkg.MOD_FILE))
 	if err != nil {
-		return fmt.Errorf("load kcl.mod failed: %v", err)
+		return nil, fmt.Errorf("load kcl.mod failed: %v", err)
 	}
 
 	absPkgPath, _ := env.GetAbsPkgPath()
 	kusionHomePath, _ := kfile.KusionDataFolder()
 
-	var allErrs []error
+	var modules []*dependentModule
 	for _, name := range modFile.Deps.Keys() {
 		dep, _ := modFile.Deps.Get(name)
 		if dep.Source.Oci != nil {
@@ -109,16 +123,73 @@ func CopyDependentModules(workDir string) error {
 				source = fmt.Sprintf("%s.exe", source)
 				dest = fmt.Sprintf("%s.exe", dest)
 			}
-			// copy the module binary to the $KUSION_HOME modules directory
-			// todo: replace with symlink
-			err = io.CopyFile(source, dest)
-			if err == nil {
-				// mark the dest file as executable
-				err = os.Chmod(dest, 0o755)
-			}
-			allErrs = append(allErrs, err)
+			modules = append(modules, &dependentModule{dep: dep, source: source, dest: dest})
 		}
 	}
+	return modules, nil
+}
+
+// VerifyDependentModules verifies the signatures and the digests of the dependent Kusion modules with the module
+// trust policy of the workspace, and returns an error on the untrusted modules.
+func VerifyDependentModules(workDir string, workspace *v1.Workspace) error {
+	if workspace == nil || workspace.ModuleTrust == nil {
+		return nil
+	}
+	modules, err := dependentModules(workDir)
+	if err != nil {
+		return err
+	}
+	verifier, err := trust.NewVerifier(workspace.ModuleTrust)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range modules {
+		if _, err = os.Stat(m.source); os.IsNotExist(err) {
+			return missingPlatformError(m.dep)
+		}
+		info := m.dep.Source.Oci
+		err = verifier.Verify(context.Background(), &trust.Module{
+			Name:       m.dep.Name,
+			Repository: info.Reg + "/" + strings.Trim(info.Repo, "/"),
+			Tag:        info.Tag,
+			Binary:     m.source,
+		})
+		if err != nil {
+			return fmt.Errorf("verify module with the trust policy of workspace %s failed: %w", workspace.Name, err)
+		}
+	}
+	return nil
+}
+
+// CopyDependentModules copies dependent Kusion modules' generators to destination, after verifying them with the
+// module trust policy of the workspace.
+func CopyDependentModules(workDir string, workspace *v1.Workspace) error {
+	if err := VerifyDependentModules(workDir, workspace); err != nil {
+		return err
+	}
+	modules, err := dependentModules(workDir)
+	if err != nil {
+		return err
+	}
+
+	var allErrs []error
+	for _, m := range modules {
+		// the pulled package has no binary if the module is not built for the current platform
+		if _, err = os.Stat(m.source); os.IsNotExist(err) {
+			allErrs = append(allErrs, missingPlatformError(m.dep))
+			continue
+		}
+
+		// copy the module binary to the $KUSION_HOME modules directory
+		// todo: replace with symlink
+		err = io.CopyFile(m.source, m.dest)
+		if err == nil {
+			// mark the dest file as executable
+			err = os.Chmod(m.dest, 0o755)
+		}
+		allErrs = append(allErrs, err)
+	}
 
 	if allErrs != nil {
 		return utilerrors.NewAggregate(allErrs)
@@ -126,3 +197,17 @@ func CopyDependentModules(workDir string) error {
 
 	return nil
 }
+
+// missingPlatformError returns the error of the module without a binary for the current platform, listing the
+// platforms available in the OCI registry.
+func missingPlatformError(dep pkg.Dependency) error {
+	info := dep.Source.Oci
+	ociURL := fmt.Sprintf("%s%s/%s:%s", oci.OCIRepositoryPrefix, info.Reg, strings.Trim(info.Repo, "/"), info.Tag)
+	err := fmt.Errorf("module %s has no binary for platform %s/%s", dep.Name, runtime.GOOS, runtime.GOARCH)
+
+	platforms, listErr := client.NewClient(client.WithUserAgent(oci.UserAgent)).Platforms(context.Background(), ociURL)
+	if listErr != nil || len(platforms) == 0 {
+		return err
+	}
+	return fmt.Errorf("%w, available platforms: %s", err, strings.Join(platforms, ", "))
+}
@@ -22,6 +22,12 @@ type APIOptions struct {
 	MaxConcurrent int
 	Watch         bool
 	WatchTimeout  int
+	// Atomic fails the apply if any resource is not reconciled before the
+	// watch times out, so that the release can be rolled back.
+	Atomic bool
+	// ContinueOnError keeps applying the resources not depending on the
+	// failed ones.
+	ContinueOnError bool
 }
 
 func NewAPIOptions() APIOptions {
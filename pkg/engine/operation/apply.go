@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/jinzhu/copier"
 
@@ -20,6 +21,12 @@ import (
 	"kusionstack.io/kusion/third_party/terraform/tfdiags"
 )
 
+var (
+	errSkippedOnFailure = errors.New("skipped since another resource failed")
+	errUpstreamFailed   = errors.New("skipped since the resources it depends on failed")
+	errCancelled        = errors.New("skipped since the operation is cancelled")
+)
+
 type ApplyOperation struct {
 	models.Operation
 }
@@ -33,6 +40,8 @@ type ApplyRequest struct {
 type ApplyResponse struct {
 	Release *apiv1.Release
 	Graph   *apiv1.Graph
+	// Report is the result of each resource, including the ones skipped because of the failed resources.
+	Report *models.ApplyReport
 }
 
 // Apply means turn all actual infra resources into the desired state described in the request by invoking a specified Runtime.
@@ -47,6 +56,7 @@ func (ao *ApplyOperation) Apply(req *ApplyRequest) (rsp *ApplyResponse, s v1.Sta
 	// keep release state
 	rsp = &ApplyResponse{
 		Release: req.Release,
+		Report:  models.NewApplyReport(req.Release.Stack),
 	}
 
 	defer func() {
@@ -119,6 +129,9 @@ func (ao *ApplyOperation) Apply(req *ApplyRequest) (rsp *ApplyResponse, s v1.Sta
 			Lock:                    &sync.Mutex{},
 			Release:                 rel,
 			Sem:                     o.Sem,
+			ContinueOnError:         o.ContinueOnError,
+			Report:                  rsp.Report,
+			Ctx:                     o.Ctx,
 		},
 	}
 
@@ -128,6 +141,7 @@ func (ao *ApplyOperation) Apply(req *ApplyRequest) (rsp *ApplyResponse, s v1.Sta
 	if diags := w.Wait(); diags.HasErrors() {
 		s = v1.NewErrorStatus(diags.Err())
 	}
+	reportUpstreamFailed(&applyOperation.Operation, applyGraph)
 	rsp.Release = applyOperation.Release
 	rsp.Graph = resourceGraph
 	return rsp, s
@@ -192,16 +206,30 @@ func applyWalkFun(o *models.Operation, v dag.Vertex) (diags tfdiags.Diagnostics)
 
 	if node, ok := v.(graph.ExecutableNode); ok {
 		if rn, ok2 := v.(*graph.ResourceNode); ok2 {
-			o.MsgCh <- models.Message{ResourceID: rn.Hashcode().(string)}
+			id := rn.Hashcode().(string)
+			// Skip the resources not applied yet once the operation is cancelled.
+			if o.Ctx != nil && o.Ctx.Err() != nil {
+				o.Report.Record(id, rn.Action, models.Skip, errCancelled, 0)
+				o.MsgCh <- models.Message{ResourceID: id, OpResult: models.Skip, OpErr: errCancelled}
+				return diags.Append(fmt.Errorf("apply %s failed: %w", id, o.Ctx.Err()))
+			}
+			// Skip the resources not applied yet once any resource fails, unless continue on error.
+			if !o.ContinueOnError && o.Report.HasFailures() {
+				o.Report.Record(id, rn.Action, models.Skip, errSkippedOnFailure, 0)
+				o.MsgCh <- models.Message{ResourceID: id, OpResult: models.Skip, OpErr: errSkippedOnFailure}
+				return nil
+			}
+			o.MsgCh <- models.Message{ResourceID: id}
 
+			start := time.Now()
 			s = node.Execute(o)
 			if v1.IsErr(s) {
-				o.MsgCh <- models.Message{
-					ResourceID: rn.Hashcode().(string), OpResult: models.Failed,
-					OpErr: fmt.Errorf("node execte failed, status:\n%v", s),
-				}
+				opErr := fmt.Errorf("node execte failed, status:\n%v", s)
+				o.Report.Record(id, rn.Action, models.Failed, opErr, time.Since(start))
+				o.MsgCh <- models.Message{ResourceID: id, OpResult: models.Failed, OpErr: opErr}
 			} else {
-				o.MsgCh <- models.Message{ResourceID: rn.Hashcode().(string), OpResult: models.Success}
+				o.Report.Record(id, rn.Action, models.Success, nil, time.Since(start))
+				o.MsgCh <- models.Message{ResourceID: id, OpResult: models.Success}
 			}
 		} else {
 			s = node.Execute(o)
@@ -213,6 +241,22 @@ func applyWalkFun(o *models.Operation, v dag.Vertex) (diags tfdiags.Diagnostics)
 	return diags
 }
 
+// reportUpstreamFailed reports the resources never executed since their dependencies failed as skipped.
+func reportUpstreamFailed(o *models.Operation, applyGraph *dag.AcyclicGraph) {
+	for _, v := range applyGraph.Vertices() {
+		rn, ok := v.(*graph.ResourceNode)
+		if !ok {
+			continue
+		}
+		id := rn.Hashcode().(string)
+		if o.Report == nil || o.Report.Get(id) != nil {
+			continue
+		}
+		o.Report.Record(id, rn.Action, models.Skip, errUpstreamFailed, 0)
+		o.MsgCh <- models.Message{ResourceID: id, OpResult: models.Skip, OpErr: errUpstreamFailed}
+	}
+}
+
 // populateResourceGraph populate dependents and dependencies of each resource in resource graph with acyclicGraph
 func populateResourceGraph(applyGraph *dag.AcyclicGraph, resourceGraph *apiv1.Graph) *apiv1.Graph {
 	for _, vertex := range applyGraph.Vertices() {
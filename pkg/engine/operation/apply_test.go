@@ -1,6 +1,7 @@
 package operation
 
 import (
+	"context"
 	"reflect"
 	"sync"
 	"testing"
@@ -158,7 +159,9 @@ func TestApplyOperation_Apply(t *testing.T) {
 			}).Build()
 			mockey.Mock(populateResourceGraph).Return(fakeGraph).Build()
 			rsp, status := ao.Apply(tc.args.applyRequest)
-			assert.Equal(t, tc.expectedResponse, rsp)
+			assert.Equal(t, tc.expectedResponse.Release, rsp.Release)
+			assert.Equal(t, tc.expectedResponse.Graph, rsp.Graph)
+			assert.Equal(t, models.Success, rsp.Report.Get("mock-id").Result)
 			assert.Equal(t, tc.expectedStatus, status)
 		})
 	}
@@ -275,3 +278,65 @@ func Test_populateResourceGraph(t *testing.T) {
 		})
 	}
 }
+
+func Test_applyWalkFunCancelled(t *testing.T) {
+	node, s := graph.NewResourceNode("mock-id", &apiv1.Resource{ID: "mock-id"}, models.Create)
+	assert.Nil(t, s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	o := &models.Operation{
+		MsgCh:  make(chan models.Message, 1),
+		Report: models.NewApplyReport("dev"),
+		Ctx:    ctx,
+	}
+
+	diags := applyWalkFun(o, node)
+	assert.True(t, diags.HasErrors())
+	assert.ErrorContains(t, diags.Err(), context.Canceled.Error())
+	assert.Equal(t, models.Skip, (<-o.MsgCh).OpResult)
+	assert.Equal(t, models.Skip, o.Report.Get("mock-id").Result)
+}
+
+func Test_applyWalkFunOnFailure(t *testing.T) {
+	testcases := []struct {
+		name            string
+		continueOnError bool
+		expectedResult  models.OpResult
+		expectedError   string
+	}{
+		{
+			name:            "skip the resources not applied yet by default",
+			continueOnError: false,
+			expectedResult:  models.Skip,
+			expectedError:   errSkippedOnFailure.Error(),
+		},
+		{
+			name:            "keep applying the independent resources if continue on error",
+			continueOnError: true,
+			expectedResult:  models.Success,
+		},
+	}
+
+	for _, tc := range testcases {
+		mockey.PatchConvey(tc.name, t, func() {
+			mockey.Mock((*graph.ResourceNode).Execute).Return(nil).Build()
+
+			node, s := graph.NewResourceNode("mock-id", &apiv1.Resource{ID: "mock-id"}, models.Create)
+			assert.Nil(t, s)
+			o := &models.Operation{
+				MsgCh:           make(chan models.Message, 2),
+				Report:          models.NewApplyReport("dev"),
+				ContinueOnError: tc.continueOnError,
+			}
+			o.Report.Record("failed-id", models.Create, models.Failed, assert.AnError, 0)
+
+			diags := applyWalkFun(o, node)
+			assert.False(t, diags.HasErrors())
+			got := o.Report.Get("mock-id")
+			assert.Equal(t, tc.expectedResult, got.Result)
+			assert.Equal(t, tc.expectedError, got.Error)
+			assert.Equal(t, models.Failed, o.Report.Get("failed-id").Result)
+		})
+	}
+}
@@ -313,8 +313,10 @@ func removeNestedField(obj interface{}, fields ...string) {
 }
 
 func (rn *ResourceNode) applyResource(operation *models.Operation, prior, planed, live *apiv1.Resource) v1.Status {
-	log.Infof("operation:%v, prior:%v, plan:%v, live:%v", rn.Action, json.Marshal2String(prior),
-		json.Marshal2String(planed), json.Marshal2String(live))
+	// Mask the sensitive data before logging the resources.
+	policy := operation.MaskPolicy()
+	log.Infof("operation:%v, prior:%v, plan:%v, live:%v", rn.Action, json.Marshal2String(policy.MaskResource(prior)),
+		json.Marshal2String(policy.MaskResource(planed)), json.Marshal2String(policy.MaskResource(live)))
 
 	var res *apiv1.Resource
 	var s v1.Status
@@ -328,7 +330,10 @@ func (rn *ResourceNode) applyResource(operation *models.Operation, prior, planed
 		response := rt.Apply(ctx, &runtime.ApplyRequest{PriorResource: prior, PlanResource: planed, Stack: operation.Stack})
 		res = response.Resource
 		s = response.Status
-		log.Debugf("apply resource:%s, response: %v", planed.ID, json.Marshal2String(response))
+		log.Debugf("apply resource:%s, response: %v", planed.ID, json.Marshal2String(&runtime.ApplyResponse{
+			Resource: policy.MaskResource(response.Resource),
+			Status:   response.Status,
+		}))
 	case models.Delete:
 		response := rt.Delete(context.Background(), &runtime.DeleteRequest{Resource: prior, Stack: operation.Stack})
 		s = response.Status
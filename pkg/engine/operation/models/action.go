@@ -2,6 +2,9 @@ package models
 
 import (
 	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
 
 	"kusionstack.io/kusion/pkg/util/pretty"
 )
@@ -32,6 +35,36 @@ func (t ActionType) MarshalJSON() ([]byte, error) {
 	return json.Marshal(t.String())
 }
 
+func (t *ActionType) UnmarshalJSON(data []byte) error {
+	var label string
+	if err := json.Unmarshal(data, &label); err != nil {
+		return err
+	}
+	return t.parse(label)
+}
+
+func (t ActionType) MarshalYAML() (interface{}, error) {
+	return t.String(), nil
+}
+
+func (t *ActionType) UnmarshalYAML(value *yaml.Node) error {
+	var label string
+	if err := value.Decode(&label); err != nil {
+		return err
+	}
+	return t.parse(label)
+}
+
+func (t *ActionType) parse(label string) error {
+	for _, action := range []ActionType{Undefined, UnChanged, Create, Update, Delete} {
+		if action.String() == label {
+			*t = action
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown action type: %s", label)
+}
+
 func (t ActionType) Ing() string {
 	switch t {
 	case Create:
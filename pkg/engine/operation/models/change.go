@@ -219,6 +219,24 @@ func (p *Changes) AllUnChange() bool {
 	return true
 }
 
+// Actions returns the numbers of the change steps of each action.
+func (p *Changes) Actions() *v1.ReleaseActions {
+	actions := &v1.ReleaseActions{}
+	for _, v := range p.ChangeSteps {
+		switch v.Action {
+		case Create:
+			actions.Create++
+		case Update:
+			actions.Update++
+		case Delete:
+			actions.Delete++
+		case UnChanged:
+			actions.UnChanged++
+		}
+	}
+	return actions
+}
+
 func (p *Changes) Summary(writer io.Writer, noStyle bool) {
 	// Create a fork of the default table, fill it with data and print it.
 	// Data can also be generated and inserted later.
@@ -189,6 +189,22 @@ func TestChanges_Get(t *testing.T) {
 	}
 }
 
+func TestChanges_Actions(t *testing.T) {
+	p := &Changes{
+		ChangeOrder: &ChangeOrder{
+			StepKeys: TestStepKeys,
+			ChangeSteps: map[string]*ChangeStep{
+				"test-key-1": TestChangeStepOpCreate,
+				"test-key-2": TestChangeStepOpDelete,
+				"test-key-3": TestChangeStepOpUpdate,
+				"test-key-4": TestChangeStepOpUnChange,
+				"test-key-5": TestChangeStepOpCreate,
+			},
+		},
+	}
+	assert.Equal(t, &apiv1.ReleaseActions{Create: 2, Update: 1, Delete: 1, UnChanged: 1}, p.Actions())
+}
+
 func TestChanges_Values(t *testing.T) {
 	type fields struct {
 		order   *ChangeOrder
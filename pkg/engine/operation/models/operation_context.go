@@ -1,6 +1,7 @@
 package models
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strconv"
@@ -12,6 +13,7 @@ import (
 	"kusionstack.io/kusion/pkg/engine/runtime"
 	"kusionstack.io/kusion/pkg/infra/util/semaphore"
 	"kusionstack.io/kusion/pkg/log"
+	"kusionstack.io/kusion/pkg/util/mask"
 )
 
 // Operation is the base model for all operations
@@ -63,6 +65,29 @@ type Operation struct {
 
 	// Release is the release updated in this operation, and saved in the ReleaseStorage
 	Release *apiv1.Release
+
+	// ContinueOnError keeps applying the resources not depending on the failed ones, instead of
+	// skipping all the resources not applied yet once any resource fails
+	ContinueOnError bool
+
+	// Report records the result of each resource applied in this operation
+	Report *ApplyReport
+
+	// Ctx cancels the operation, the resources not applied yet are skipped once it's done
+	Ctx context.Context
+}
+
+// MaskPolicy returns the masking policy of the workspace the release in this operation belongs to,
+// or the default policy if unknown.
+func (o *Operation) MaskPolicy() *mask.Policy {
+	if o.Release != nil && o.Release.Spec != nil {
+		policy, err := mask.PolicyFromContext(o.Release.Spec.Context)
+		if err == nil {
+			return policy
+		}
+		log.Warnf("failed to read the masking policy, use the default one: %v", err)
+	}
+	return mask.Default()
 }
 
 type Message struct {
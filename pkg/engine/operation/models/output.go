@@ -0,0 +1,249 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"kusionstack.io/kusion/pkg/util/diff"
+)
+
+// Output format values of the preview and apply results
+const (
+	OutputJSON     = "json"
+	OutputYAML     = "yaml"
+	OutputMarkdown = "markdown"
+	OutputPatch    = "patch"
+)
+
+// OutputAPIVersion is the version of the output schemas, which is bumped on
+// any incompatible change of them.
+const OutputAPIVersion = "kusion.io/v1"
+
+// Kinds of the output documents
+const (
+	PreviewOutputKind = "Preview"
+	ApplyOutputKind   = "Apply"
+)
+
+var (
+	// PreviewOutputFormats are the supported output formats of preview.
+	PreviewOutputFormats = []string{OutputJSON, OutputYAML, OutputMarkdown, OutputPatch}
+	// ApplyOutputFormats are the supported output formats of apply.
+	ApplyOutputFormats = []string{OutputJSON, OutputYAML}
+)
+
+// ChangesSummary is the number of the change steps of each action.
+type ChangesSummary struct {
+	Create    int `json:"create" yaml:"create"`
+	Update    int `json:"update" yaml:"update"`
+	Delete    int `json:"delete" yaml:"delete"`
+	UnChanged int `json:"unchanged" yaml:"unchanged"`
+}
+
+// PreviewOutput is the versioned schema of the preview output. The change
+// order is inlined, so that the output stays compatible with the raw changes.
+type PreviewOutput struct {
+	APIVersion  string         `json:"apiVersion" yaml:"apiVersion"`
+	Kind        string         `json:"kind" yaml:"kind"`
+	Project     string         `json:"project,omitempty" yaml:"project,omitempty"`
+	Stack       string         `json:"stack,omitempty" yaml:"stack,omitempty"`
+	Summary     ChangesSummary `json:"summary" yaml:"summary"`
+	ChangeOrder `json:",inline" yaml:",inline"`
+}
+
+// ApplySummary is the number of the resources of each result.
+type ApplySummary struct {
+	Succeeded int `json:"succeeded" yaml:"succeeded"`
+	Failed    int `json:"failed" yaml:"failed"`
+	Skipped   int `json:"skipped" yaml:"skipped"`
+}
+
+// ApplyOutput is the versioned schema of the apply output.
+type ApplyOutput struct {
+	APIVersion string            `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string            `json:"kind" yaml:"kind"`
+	Stack      string            `json:"stack,omitempty" yaml:"stack,omitempty"`
+	Summary    ApplySummary      `json:"summary" yaml:"summary"`
+	Resources  []*ResourceReport `json:"resources" yaml:"resources"`
+}
+
+// Summarize counts the change steps of each action.
+func (o *ChangeOrder) Summarize() ChangesSummary {
+	summary := ChangesSummary{}
+	for _, step := range o.Values() {
+		switch step.Action {
+		case Create:
+			summary.Create++
+		case Update:
+			summary.Update++
+		case Delete:
+			summary.Delete++
+		case UnChanged:
+			summary.UnChanged++
+		}
+	}
+	return summary
+}
+
+// Masked returns a copy of the change order with the sensitive data in the
+// change steps masked, which is safe to print.
+func (o *ChangeOrder) Masked() *ChangeOrder {
+	masked := &ChangeOrder{
+		StepKeys:    append([]string{}, o.StepKeys...),
+		ChangeSteps: make(map[string]*ChangeStep, len(o.ChangeSteps)),
+	}
+	for key, step := range o.ChangeSteps {
+		from, to := diff.MaskSensitiveData(step.From, step.To)
+		masked.ChangeSteps[key] = NewChangeStep(step.ID, step.Action, from, to)
+	}
+	return masked
+}
+
+// Output returns the versioned output document of the changes, with the
+// sensitive data masked.
+func (p *Changes) Output() *PreviewOutput {
+	out := &PreviewOutput{
+		APIVersion:  OutputAPIVersion,
+		Kind:        PreviewOutputKind,
+		Summary:     p.Summarize(),
+		ChangeOrder: *p.Masked(),
+	}
+	if p.project != nil {
+		out.Project = p.project.Name
+	}
+	if p.stack != nil {
+		out.Stack = p.stack.Name
+	}
+	return out
+}
+
+// Print prints the changes in the format, which is one of PreviewOutputFormats.
+func (p *Changes) Print(w io.Writer, format string) error {
+	var data []byte
+	var err error
+	switch format {
+	case OutputJSON:
+		data, err = json.Marshal(p.Output())
+		data = append(data, '\n')
+	case OutputYAML:
+		data, err = yaml.Marshal(p.Output())
+	case OutputMarkdown:
+		var md string
+		md, err = p.Markdown()
+		data = []byte(md)
+	case OutputPatch:
+		var patch string
+		patch, err = p.Patch()
+		data = []byte(patch)
+	default:
+		return fmt.Errorf("unsupported output format: %s, supported: %s", format, strings.Join(PreviewOutputFormats, ", "))
+	}
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// Patch returns the differences between the From and To of the change step
+// as a unified diff, with the sensitive data masked.
+func (cs *ChangeStep) Patch() (string, error) {
+	fromFile, toFile := "a/"+cs.ID, "b/"+cs.ID
+	switch cs.Action {
+	case Create:
+		fromFile = "/dev/null"
+	case Delete:
+		toFile = "/dev/null"
+	}
+	return diff.ToUnifiedString(cs.From, cs.To, fromFile, toFile)
+}
+
+// Patch returns the unified diff of all the change steps in order, which can
+// be viewed by any diff tool.
+func (o *ChangeOrder) Patch() (string, error) {
+	buf := &bytes.Buffer{}
+	for _, step := range o.Values() {
+		patch, err := step.Patch()
+		if err != nil {
+			return "", fmt.Errorf("failed to generate the patch of %s: %w", step.ID, err)
+		}
+		buf.WriteString(patch)
+	}
+	return buf.String(), nil
+}
+
+// Markdown renders the changes as Markdown to be posted on pull requests, with
+// a summary table and a collapsible section of the diff of each changed resource.
+func (p *Changes) Markdown() (string, error) {
+	buf := &bytes.Buffer{}
+	title := "Kusion Preview"
+	if p.stack != nil {
+		title = fmt.Sprintf("Kusion Preview: `%s`", p.stack.Name)
+	}
+	if p.project != nil {
+		title = fmt.Sprintf("%s of `%s`", title, p.project.Name)
+	}
+	fmt.Fprintf(buf, "### %s\n\n", title)
+
+	summary := p.Summarize()
+	fmt.Fprintf(buf, "**Plan:** %d to create, %d to update, %d to delete, %d unchanged.\n\n",
+		summary.Create, summary.Update, summary.Delete, summary.UnChanged)
+	if p.AllUnChange() {
+		buf.WriteString("All resources are reconciled. No diff found.\n")
+		return buf.String(), nil
+	}
+
+	buf.WriteString("| ID | Action |\n| --- | --- |\n")
+	for _, step := range p.Values() {
+		fmt.Fprintf(buf, "| `%s` | %s |\n", escapeMarkdownTable(step.ID), step.Action)
+	}
+
+	for _, step := range p.Values(func(c *ChangeStep) bool { return c.Action != UnChanged }) {
+		patch, err := step.Patch()
+		if err != nil {
+			return "", fmt.Errorf("failed to generate the patch of %s: %w", step.ID, err)
+		}
+		fmt.Fprintf(buf, "\n<details>\n<summary>%s <code>%s</code></summary>\n\n", step.Action, escapeMarkdownHTML(step.ID))
+		// Use a longer fence in case the diff contains backticks.
+		buf.WriteString("````diff\n")
+		buf.WriteString(patch)
+		buf.WriteString("````\n\n</details>\n")
+	}
+	return buf.String(), nil
+}
+
+// Output returns the versioned output document of the apply report.
+func (r *ApplyReport) Output() *ApplyOutput {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	out := &ApplyOutput{
+		APIVersion: OutputAPIVersion,
+		Kind:       ApplyOutputKind,
+		Stack:      r.Stack,
+		Resources:  r.Resources,
+	}
+	for _, rr := range r.Resources {
+		switch rr.Result {
+		case Success:
+			out.Summary.Succeeded++
+		case Failed:
+			out.Summary.Failed++
+		case Skip:
+			out.Summary.Skipped++
+		}
+	}
+	return out
+}
+
+func escapeMarkdownTable(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
+
+func escapeMarkdownHTML(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
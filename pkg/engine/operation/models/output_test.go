@@ -0,0 +1,119 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	apiv1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func newTestOutputChanges() *Changes {
+	secret := func(value string) *apiv1.Resource {
+		return &apiv1.Resource{
+			ID:   "v1:Secret:foo:bar",
+			Type: apiv1.Kubernetes,
+			Attributes: map[string]interface{}{
+				"kind": "Secret",
+				"data": map[string]interface{}{"key": value},
+			},
+		}
+	}
+	namespace := &apiv1.Resource{
+		ID:         "v1:Namespace:foo",
+		Type:       apiv1.Kubernetes,
+		Attributes: map[string]interface{}{"kind": "Namespace"},
+	}
+	order := &ChangeOrder{
+		StepKeys: []string{"v1:Namespace:foo", "v1:Secret:foo:bar"},
+		ChangeSteps: map[string]*ChangeStep{
+			"v1:Namespace:foo":  NewChangeStep("v1:Namespace:foo", Create, nil, namespace),
+			"v1:Secret:foo:bar": NewChangeStep("v1:Secret:foo:bar", Update, secret("dmFsdWUK"), secret("dmFsdWUtY2hhbmdlZAo=")),
+		},
+	}
+	return NewChanges(&apiv1.Project{Name: "foo"}, &apiv1.Stack{Name: "dev"}, order)
+}
+
+func TestChanges_Print(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, newTestOutputChanges().Print(buf, OutputJSON))
+		assert.NotContains(t, buf.String(), "dmFsdWUK")
+
+		got := &PreviewOutput{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), got))
+		assert.Equal(t, OutputAPIVersion, got.APIVersion)
+		assert.Equal(t, PreviewOutputKind, got.Kind)
+		assert.Equal(t, "foo", got.Project)
+		assert.Equal(t, "dev", got.Stack)
+		assert.Equal(t, ChangesSummary{Create: 1, Update: 1}, got.Summary)
+		assert.Equal(t, []string{"v1:Namespace:foo", "v1:Secret:foo:bar"}, got.StepKeys)
+		assert.Equal(t, Update, got.ChangeSteps["v1:Secret:foo:bar"].Action)
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, newTestOutputChanges().Print(buf, OutputYAML))
+		assert.Contains(t, buf.String(), "action: Update\n")
+		assert.NotContains(t, buf.String(), "dmFsdWUK")
+
+		got := &PreviewOutput{}
+		require.NoError(t, yaml.Unmarshal(buf.Bytes(), got))
+		assert.Equal(t, OutputAPIVersion, got.APIVersion)
+		assert.Equal(t, ChangesSummary{Create: 1, Update: 1}, got.Summary)
+		assert.Equal(t, Create, got.ChangeSteps["v1:Namespace:foo"].Action)
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, newTestOutputChanges().Print(buf, OutputMarkdown))
+		md := buf.String()
+		assert.Contains(t, md, "### Kusion Preview: `dev` of `foo`\n")
+		assert.Contains(t, md, "**Plan:** 1 to create, 1 to update, 0 to delete, 0 unchanged.")
+		assert.Contains(t, md, "| `v1:Secret:foo:bar` | Update |\n")
+		assert.Equal(t, 2, bytes.Count(buf.Bytes(), []byte("<details>")))
+		assert.Contains(t, md, "+++ b/v1:Secret:foo:bar\n")
+		assert.NotContains(t, md, "dmFsdWUK")
+	})
+
+	t.Run("patch", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, newTestOutputChanges().Print(buf, OutputPatch))
+		assert.Contains(t, buf.String(), "--- /dev/null\n+++ b/v1:Namespace:foo\n")
+		assert.Contains(t, buf.String(), "--- a/v1:Secret:foo:bar\n+++ b/v1:Secret:foo:bar\n")
+		assert.NotContains(t, buf.String(), "dmFsdWUK")
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		assert.Error(t, newTestOutputChanges().Print(&bytes.Buffer{}, "csv"))
+	})
+}
+
+func TestChanges_Markdown_AllUnChange(t *testing.T) {
+	order := &ChangeOrder{
+		StepKeys:    []string{"id"},
+		ChangeSteps: map[string]*ChangeStep{"id": TestChangeStepOpUnChange},
+	}
+	md, err := NewChanges(nil, nil, order).Markdown()
+	require.NoError(t, err)
+	assert.Contains(t, md, "No diff found.")
+	assert.NotContains(t, md, "<details>")
+}
+
+func TestApplyReport_Output(t *testing.T) {
+	buf := &bytes.Buffer{}
+	require.NoError(t, newTestReport().Print(buf, ReportFormatYAML, true))
+
+	got := &ApplyOutput{}
+	require.NoError(t, yaml.Unmarshal(buf.Bytes(), got))
+	assert.Equal(t, OutputAPIVersion, got.APIVersion)
+	assert.Equal(t, ApplyOutputKind, got.Kind)
+	assert.Equal(t, "dev", got.Stack)
+	assert.Equal(t, ApplySummary{Succeeded: 1, Failed: 1, Skipped: 1}, got.Summary)
+	require.Len(t, got.Resources, 3)
+	assert.Equal(t, Create, got.Resources[1].Action)
+}
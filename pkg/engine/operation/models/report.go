@@ -0,0 +1,267 @@
+package models
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/liu-hm19/pterm"
+	"gopkg.in/yaml.v3"
+)
+
+// ReportFormat values
+const (
+	ReportFormatTable = "table"
+	ReportFormatJSON  = "json"
+	ReportFormatYAML  = "yaml"
+	ReportFormatJUnit = "junit"
+)
+
+// ReportFormats are the supported formats of the apply report.
+var ReportFormats = []string{ReportFormatTable, ReportFormatJSON, ReportFormatYAML, ReportFormatJUnit}
+
+// ResourceReport is the result of applying a resource.
+type ResourceReport struct {
+	ID              string     `json:"id" yaml:"id"`
+	Action          ActionType `json:"action" yaml:"action"`
+	Result          OpResult   `json:"result" yaml:"result"`
+	Error           string     `json:"error,omitempty" yaml:"error,omitempty"`
+	DurationSeconds float64    `json:"durationSeconds" yaml:"durationSeconds"`
+}
+
+// ApplyReport records the result of every resource in an apply, including the
+// ones skipped because of the failed resources, in the order they finished.
+type ApplyReport struct {
+	Stack     string            `json:"stack" yaml:"stack"`
+	Resources []*ResourceReport `json:"resources" yaml:"resources"`
+
+	lock     sync.Mutex
+	index    map[string]*ResourceReport
+	failures int
+}
+
+func NewApplyReport(stack string) *ApplyReport {
+	return &ApplyReport{
+		Stack:     stack,
+		Resources: []*ResourceReport{},
+		index:     map[string]*ResourceReport{},
+	}
+}
+
+// Record records the result of the resource, replacing the previous one if any.
+// It is a no-op on a nil report.
+func (r *ApplyReport) Record(id string, action ActionType, result OpResult, err error, duration time.Duration) {
+	if r == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	rr := &ResourceReport{
+		ID:              id,
+		Action:          action,
+		Result:          result,
+		DurationSeconds: duration.Seconds(),
+	}
+	if err != nil {
+		rr.Error = err.Error()
+	}
+	if result == Failed {
+		r.failures++
+	}
+	if prev, ok := r.index[id]; ok {
+		if prev.Result == Failed {
+			r.failures--
+		}
+		*prev = *rr
+		return
+	}
+	if r.index == nil {
+		r.index = map[string]*ResourceReport{}
+	}
+	r.index[id] = rr
+	r.Resources = append(r.Resources, rr)
+}
+
+// Get returns the result of the resource, or nil if not recorded.
+func (r *ApplyReport) Get(id string) *ResourceReport {
+	if r == nil {
+		return nil
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.index[id]
+}
+
+// HasFailures returns true if any resource failed.
+func (r *ApplyReport) HasFailures() bool {
+	if r == nil {
+		return false
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.failures > 0
+}
+
+// SetActions sets the actions of the resources with those in the change order,
+// which are more accurate for the resources never executed.
+func (r *ApplyReport) SetActions(order *ChangeOrder) {
+	if order == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for _, rr := range r.Resources {
+		if step := order.Get(rr.ID); step != nil {
+			rr.Action = step.Action
+		}
+	}
+}
+
+// Count returns the number of the resources with the result.
+func (r *ApplyReport) Count(result OpResult) int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	count := 0
+	for _, rr := range r.Resources {
+		if rr.Result == result {
+			count++
+		}
+	}
+	return count
+}
+
+// Print prints the report in the format, which is one of ReportFormats. The
+// JSON and YAML reports are in the versioned schema of ApplyOutput.
+func (r *ApplyReport) Print(w io.Writer, format string, noStyle bool) error {
+	switch format {
+	case ReportFormatTable:
+		return r.printTable(w, noStyle)
+	case ReportFormatJSON:
+		data, err := json.MarshalIndent(r.Output(), "", "    ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case ReportFormatYAML:
+		data, err := yaml.Marshal(r.Output())
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	case ReportFormatJUnit:
+		return r.printJUnit(w)
+	default:
+		return fmt.Errorf("unsupported report format: %s, supported: %v", format, ReportFormats)
+	}
+}
+
+func (r *ApplyReport) printTable(w io.Writer, noStyle bool) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if noStyle {
+		pterm.DisableStyling()
+	}
+	tableData := pterm.TableData{{fmt.Sprintf("Stack: %s\nID", r.Stack), "\nAction", "\nResult", "\nDuration", "\nError"}}
+	for _, rr := range r.Resources {
+		duration := time.Duration(rr.DurationSeconds * float64(time.Second)).Round(time.Millisecond)
+		tableData = append(tableData, []string{rr.ID, rr.Action.String(), string(rr.Result), duration.String(), rr.Error})
+	}
+	if err := pterm.DefaultTable.WithHasHeader().
+		WithHeaderStyle(&pterm.ThemeDefault.TableHeaderStyle).
+		WithLeftAlignment(true).
+		WithSeparator("  ").
+		WithData(tableData).
+		WithWriter(w).
+		Render(); err != nil {
+		return err
+	}
+	pterm.Fprintln(w)
+	return nil
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// printJUnit prints the report as JUnit XML, with a test case per resource,
+// so that CI systems can annotate the failed resources.
+func (r *ApplyReport) printJUnit(w io.Writer) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	suite := junitTestSuite{Name: r.Stack, Tests: len(r.Resources)}
+	total := 0.0
+	for _, rr := range r.Resources {
+		tc := junitTestCase{
+			ClassName: r.Stack,
+			Name:      rr.ID,
+			Time:      formatSeconds(rr.DurationSeconds),
+		}
+		switch rr.Result {
+		case Failed:
+			suite.Failures++
+			tc.Failure = &junitMessage{Message: rr.Error, Type: rr.Action.String(), Text: rr.Error}
+		case Skip:
+			suite.Skipped++
+			tc.Skipped = &junitMessage{Message: rr.Error}
+		}
+		total += rr.DurationSeconds
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+	suite.Time = formatSeconds(total)
+	suites := junitTestSuites{
+		Name:     "kusion apply",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}
+
+	data, err := xml.MarshalIndent(suites, "", "    ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s%s\n", xml.Header, data)
+	return err
+}
+
+func formatSeconds(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
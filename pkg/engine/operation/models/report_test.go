@@ -0,0 +1,92 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestReport() *ApplyReport {
+	r := NewApplyReport("dev")
+	r.Record("v1:Namespace:foo", Create, Success, nil, time.Second)
+	r.Record("apps/v1:Deployment:foo:bar", Create, Failed, errors.New("quota exceeded"), 2*time.Second)
+	r.Record("v1:Service:foo:bar", Update, Skip, errors.New("skipped since the resources it depends on failed"), 0)
+	return r
+}
+
+func TestApplyReport_Record(t *testing.T) {
+	r := newTestReport()
+	assert.True(t, r.HasFailures())
+	assert.Equal(t, 1, r.Count(Success))
+	assert.Equal(t, 1, r.Count(Failed))
+	assert.Equal(t, 1, r.Count(Skip))
+
+	// Record again to replace the result
+	r.Record("apps/v1:Deployment:foo:bar", Create, Success, nil, time.Second)
+	assert.False(t, r.HasFailures())
+	assert.Len(t, r.Resources, 3)
+	assert.Empty(t, r.Get("apps/v1:Deployment:foo:bar").Error)
+	assert.Nil(t, r.Get("not-exist"))
+
+	r.SetActions(&ChangeOrder{
+		StepKeys:    []string{"v1:Service:foo:bar"},
+		ChangeSteps: map[string]*ChangeStep{"v1:Service:foo:bar": NewChangeStep("v1:Service:foo:bar", Create, nil, nil)},
+	})
+	assert.Equal(t, Create, r.Get("v1:Service:foo:bar").Action)
+
+	// A nil report records nothing
+	var nilReport *ApplyReport
+	nilReport.Record("v1:Namespace:foo", Create, Failed, errors.New("failed"), time.Second)
+	assert.False(t, nilReport.HasFailures())
+	assert.Nil(t, nilReport.Get("v1:Namespace:foo"))
+}
+
+func TestApplyReport_Print(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, newTestReport().Print(buf, ReportFormatJSON, true))
+
+		got := &ApplyReport{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), got))
+		assert.Equal(t, "dev", got.Stack)
+		require.Len(t, got.Resources, 3)
+		assert.Equal(t, Failed, got.Resources[1].Result)
+		assert.Equal(t, Create, got.Resources[1].Action)
+		assert.Equal(t, "quota exceeded", got.Resources[1].Error)
+		assert.Equal(t, 2.0, got.Resources[1].DurationSeconds)
+	})
+
+	t.Run("junit", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, newTestReport().Print(buf, ReportFormatJUnit, true))
+
+		got := &junitTestSuites{}
+		require.NoError(t, xml.Unmarshal(buf.Bytes(), got))
+		assert.Equal(t, 3, got.Tests)
+		assert.Equal(t, 1, got.Failures)
+		assert.Equal(t, 1, got.Skipped)
+		assert.Equal(t, "3.000", got.Time)
+		require.Len(t, got.Suites, 1)
+		require.Len(t, got.Suites[0].TestCases, 3)
+		assert.Nil(t, got.Suites[0].TestCases[0].Failure)
+		assert.Equal(t, "quota exceeded", got.Suites[0].TestCases[1].Failure.Message)
+		assert.NotNil(t, got.Suites[0].TestCases[2].Skipped)
+	})
+
+	t.Run("table", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, newTestReport().Print(buf, ReportFormatTable, true))
+		assert.Contains(t, buf.String(), "apps/v1:Deployment:foo:bar")
+		assert.Contains(t, buf.String(), "quota exceeded")
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		assert.Error(t, newTestReport().Print(&bytes.Buffer{}, "csv", true))
+	})
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"reflect"
 	"time"
 
 	"github.com/howieyuen/uilive"
@@ -17,6 +16,7 @@ import (
 	"kusionstack.io/kusion/pkg/engine"
 	"kusionstack.io/kusion/pkg/engine/operation/models"
 	"kusionstack.io/kusion/pkg/engine/printers"
+	"kusionstack.io/kusion/pkg/engine/printers/status"
 	"kusionstack.io/kusion/pkg/engine/release"
 	"kusionstack.io/kusion/pkg/engine/runtime"
 	runtimeinit "kusionstack.io/kusion/pkg/engine/runtime/init"
@@ -49,6 +49,12 @@ func (wo *WatchOperation) Watch(req *WatchRequest) error {
 	}
 	wo.RuntimeMap = runtimes
 
+	// Get the readiness policies configured in the workspace
+	policies, err := status.PoliciesFromContext(req.Spec.Context)
+	if err != nil {
+		return err
+	}
+
 	// Result channels
 	msgChs := make(map[string]*runtime.SequentialWatchers, len(resources))
 	// Keep sorted
@@ -86,10 +92,9 @@ func (wo *WatchOperation) Watch(req *WatchRequest) error {
 	ticker := time.NewTicker(time.Second)
 	defer ticker.Stop()
 
-	// Counting completed resource
-	finished := make(map[string]bool)
-
-	// Start go routine for each table
+	// Start go routine for each table, which blocks on the events of the
+	// watchers until all the objects are ready
+	done := make(chan string)
 	for _, id := range ids {
 		sw, ok := msgChs[id]
 		if !ok { // Terraform resource, skip
@@ -100,54 +105,43 @@ func (wo *WatchOperation) Watch(req *WatchRequest) error {
 		// Save tables first
 		tables[id] = table
 		// Start watching resource
-		go func(id string, chs []<-chan watch.Event, table *printers.Table) {
-			// Resources selects
-			cases := createSelectCases(chs)
-			// Default select
-			cases = append(cases, reflect.SelectCase{
-				Dir:  reflect.SelectDefault,
-				Chan: reflect.Value{},
-				Send: reflect.Value{},
-			})
-			for {
-				chosen, recv, recvOK := reflect.Select(cases)
-				if cases[chosen].Dir == reflect.SelectDefault {
-					continue
+		go func(id string, sw *runtime.SequentialWatchers, table *printers.Table) {
+			watchCtx, watchCancel := context.WithCancel(ctx)
+			defer watchCancel()
+			defer func() {
+				select {
+				case done <- id:
+				case <-ctx.Done():
 				}
-				if recvOK {
-					e := recv.Interface().(watch.Event)
-					o := e.Object.(*unstructured.Unstructured)
-					var detail string
-					var ready bool
-					if e.Type == watch.Deleted {
-						detail = fmt.Sprintf("%s has beed deleted", o.GetName())
-						ready = true
-					} else {
-						// Restore to actual type
-						target := printers.Convert(o)
-						detail, ready = printers.Generate(target)
-					}
-
-					// Mark ready for breaking loop
-					if ready {
-						e.Type = printers.READY
-					}
-
-					// Save watched msg
-					table.Update(
-						engine.BuildIDForKubernetes(o),
-						printers.NewRow(e.Type, o.GetKind(), o.GetName(), detail))
-
-					// Write back
-					tables[id] = table
+			}()
+
+			for e := range sw.Events(watchCtx) {
+				o := e.Object.(*unstructured.Unstructured)
+				var detail string
+				var ready bool
+				if e.Type == watch.Deleted {
+					detail = fmt.Sprintf("%s has beed deleted", o.GetName())
+					ready = true
+				} else {
+					detail, ready = printers.Status(o, policies)
 				}
 
+				// Mark ready for breaking loop
+				if ready {
+					e.Type = printers.READY
+				}
+
+				// Save watched msg
+				table.Update(
+					engine.BuildIDForKubernetes(o),
+					printers.NewRow(e.Type, o.GetKind(), o.GetName(), detail))
+
 				// Break when completed
 				if table.AllCompleted() {
-					break
+					return
 				}
 			}
-		}(id, sw.Watchers, table)
+		}(id, sw, table)
 	}
 
 	// No k8s resources
@@ -156,25 +150,17 @@ func (wo *WatchOperation) Watch(req *WatchRequest) error {
 		return nil
 	}
 
-	// Waiting for all tables completed
-	for {
-		// Finish watch
-		if len(finished) == len(tables) {
-			break
+	// Waiting for all tables completed, rendering the tables every second
+	// with a single ticker
+	for finished := 0; finished < len(tables); {
+		select {
+		case <-done:
+			finished++
+		case <-ticker.C:
+			wo.printTables(writer, ids, tables)
 		}
-
-		// Range tables
-		for id, table := range tables {
-			// All channels are isCompleted
-			if table.AllCompleted() {
-				finished[id] = true
-			}
-		}
-
-		// Render table every 1s
-		<-ticker.C
-		wo.printTables(writer, ids, tables)
 	}
+	wo.printTables(writer, ids, tables)
 	return nil
 }
 
@@ -211,14 +197,3 @@ func validateWatchRequest(req *WatchRequest) error {
 	}
 	return nil
 }
-
-func createSelectCases(chs []<-chan watch.Event) []reflect.SelectCase {
-	cases := make([]reflect.SelectCase, 0, len(chs))
-	for _, ch := range chs {
-		cases = append(cases, reflect.SelectCase{
-			Dir:  reflect.SelectRecv,
-			Chan: reflect.ValueOf(ch),
-		})
-	}
-	return cases
-}
@@ -0,0 +1,272 @@
+// Copyright 2024 KusionStack Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package plan implements the saved plans written by `kusion preview --out`
+// and applied by `kusion apply <plan>`, so that exactly the changes approved
+// in a preview are applied.
+package plan
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	apiv1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/engine/operation/models"
+	"kusionstack.io/kusion/pkg/util/mask"
+)
+
+const (
+	APIVersion = "kusion.io/v1"
+	Kind       = "Plan"
+
+	// SigningKeyEnv is the environment variable holding the key to sign the
+	// plans with. If it is set, only the plans signed with the same key are
+	// applied.
+	SigningKeyEnv = "KUSION_PLAN_SIGNING_KEY"
+
+	digestPrefix = "sha256:"
+)
+
+var (
+	ErrInvalidPlan          = errors.New("invalid plan file")
+	ErrDigestMismatch       = errors.New("the plan file has been modified after it was written")
+	ErrUnsignedPlan         = errors.New("the plan file is not signed, but " + SigningKeyEnv + " is set")
+	ErrSignatureMismatch    = errors.New("the signature of the plan file does not match " + SigningKeyEnv)
+	ErrPlanTargetMismatch   = errors.New("the plan is not for the current project, stack and workspace")
+	ErrBaseRevisionMismatch = errors.New("the release has moved on since the plan was created, please preview again")
+	ErrLiveStateChanged     = errors.New("the live state of the resources has changed since the plan was created, please preview again")
+)
+
+// Plan is a saved preview of the changes of a stack. It is applied as long as
+// neither the latest release nor the live state of the resources has moved on
+// since the preview.
+type Plan struct {
+	// Project is the name of the project previewed.
+	Project string `json:"project"`
+	// Stack is the name of the stack previewed.
+	Stack string `json:"stack"`
+	// Workspace is the name of the workspace previewed.
+	Workspace string `json:"workspace"`
+	// BaseRevision is the revision of the latest release when previewing, 0
+	// if there was no release yet.
+	BaseRevision uint64 `json:"baseRevision"`
+	// Spec is the spec previewed, which is applied without regenerating it.
+	Spec *apiv1.Spec `json:"spec"`
+	// ChangeOrder is the changes previewed, with the sensitive data masked.
+	ChangeOrder *models.ChangeOrder `json:"changeOrder"`
+	// Fingerprints are the digests of the live state of the resources read
+	// when previewing, keyed by the resource IDs.
+	Fingerprints map[string]string `json:"fingerprints"`
+	// CreateTime is the time the plan was created.
+	CreateTime time.Time `json:"createTime"`
+}
+
+// file is the format of the plan files. The digest and the signature are
+// computed over the compact encoding of the plan as written, so that they do
+// not depend on how the plan is re-encoded.
+type file struct {
+	APIVersion string          `json:"apiVersion"`
+	Kind       string          `json:"kind"`
+	Digest     string          `json:"digest"`
+	Signature  string          `json:"signature,omitempty"`
+	Plan       json.RawMessage `json:"plan"`
+}
+
+// New creates a plan with the previewed spec and changes.
+func New(project, stack, workspace string, baseRevision uint64, spec *apiv1.Spec, order *models.ChangeOrder) (*Plan, error) {
+	fingerprints, err := Fingerprints(order)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := mask.PolicyFromContext(spec.Context)
+	if err != nil {
+		return nil, err
+	}
+	masked := &models.ChangeOrder{
+		StepKeys:    append([]string{}, order.StepKeys...),
+		ChangeSteps: make(map[string]*models.ChangeStep, len(order.ChangeSteps)),
+	}
+	for key, step := range order.ChangeSteps {
+		from, to := policy.Mask(step.From, step.To)
+		masked.ChangeSteps[key] = models.NewChangeStep(step.ID, step.Action, from, to)
+	}
+
+	return &Plan{
+		Project:      project,
+		Stack:        stack,
+		Workspace:    workspace,
+		BaseRevision: baseRevision,
+		Spec:         spec,
+		ChangeOrder:  masked,
+		Fingerprints: fingerprints,
+		CreateTime:   time.Now(),
+	}, nil
+}
+
+// Fingerprints returns the digests of the live state of the resources, which
+// the change steps are computed from.
+func Fingerprints(order *models.ChangeOrder) (map[string]string, error) {
+	fingerprints := make(map[string]string, len(order.ChangeSteps))
+	for key, step := range order.ChangeSteps {
+		live, err := json.Marshal(step.From)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fingerprint the live state of %s: %w", key, err)
+		}
+		fingerprints[key] = digest(live)
+	}
+	return fingerprints, nil
+}
+
+// Encode encodes the plan into a plan file, which is signed if the signing key
+// is not empty.
+func Encode(p *Plan, signingKey []byte) ([]byte, error) {
+	raw, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	f := &file{
+		APIVersion: APIVersion,
+		Kind:       Kind,
+		Digest:     digest(raw),
+		Plan:       raw,
+	}
+	if len(signingKey) > 0 {
+		f.Signature = sign(signingKey, raw)
+	}
+	return json.MarshalIndent(f, "", "  ")
+}
+
+// Decode decodes a plan file after verifying its digest. If the signing key
+// is not empty, the plan file must be signed with it.
+func Decode(data, signingKey []byte) (*Plan, error) {
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPlan, err)
+	}
+	if f.APIVersion != APIVersion || f.Kind != Kind || len(f.Plan) == 0 {
+		return nil, fmt.Errorf("%w: expect a %s of %s", ErrInvalidPlan, Kind, APIVersion)
+	}
+	var raw bytes.Buffer
+	if err := json.Compact(&raw, f.Plan); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPlan, err)
+	}
+	if f.Digest != digest(raw.Bytes()) {
+		return nil, ErrDigestMismatch
+	}
+	if len(signingKey) > 0 {
+		if f.Signature == "" {
+			return nil, ErrUnsignedPlan
+		}
+		if !hmac.Equal([]byte(f.Signature), []byte(sign(signingKey, raw.Bytes()))) {
+			return nil, ErrSignatureMismatch
+		}
+	}
+
+	var p Plan
+	if err := json.Unmarshal(raw.Bytes(), &p); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPlan, err)
+	}
+	if p.Spec == nil || p.ChangeOrder == nil {
+		return nil, fmt.Errorf("%w: the spec and changes are required", ErrInvalidPlan)
+	}
+	return &p, nil
+}
+
+// WriteFile writes the plan to the file, which is only readable by the
+// current user since the spec may contain sensitive data.
+func WriteFile(path string, p *Plan, signingKey []byte) error {
+	data, err := Encode(p, signingKey)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// ReadFile reads the plan from the file.
+func ReadFile(path string, signingKey []byte) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Decode(data, signingKey)
+}
+
+// SigningKey returns the key to sign and verify the plans with, which is empty
+// if SigningKeyEnv is not set.
+func SigningKey() []byte {
+	return []byte(os.Getenv(SigningKeyEnv))
+}
+
+// CheckBase checks that the plan is for the given project, stack and workspace,
+// and that no release has been created since the plan was created.
+func (p *Plan) CheckBase(project, stack, workspace string, latestRevision uint64) error {
+	if p.Project != project || p.Stack != stack || p.Workspace != workspace {
+		return fmt.Errorf("%w: the plan is for project %s, stack %s and workspace %s",
+			ErrPlanTargetMismatch, p.Project, p.Stack, p.Workspace)
+	}
+	if p.BaseRevision != latestRevision {
+		return fmt.Errorf("%w: the plan is based on revision %d, but the latest revision is %d",
+			ErrBaseRevisionMismatch, p.BaseRevision, latestRevision)
+	}
+	return nil
+}
+
+// CheckChanges checks that the changes computed right before applying are the
+// same as the planned ones, namely the same action of every resource computed
+// from the same live state.
+func (p *Plan) CheckChanges(order *models.ChangeOrder) error {
+	fingerprints, err := Fingerprints(order)
+	if err != nil {
+		return err
+	}
+
+	var drifted []string
+	for key, step := range order.ChangeSteps {
+		planned := p.ChangeOrder.Get(key)
+		if planned == nil || planned.Action != step.Action || p.Fingerprints[key] != fingerprints[key] {
+			drifted = append(drifted, key)
+		}
+	}
+	for key := range p.ChangeOrder.ChangeSteps {
+		if order.Get(key) == nil {
+			drifted = append(drifted, key)
+		}
+	}
+	if len(drifted) > 0 {
+		sort.Strings(drifted)
+		return fmt.Errorf("%w, changed resources: %s", ErrLiveStateChanged, strings.Join(drifted, ", "))
+	}
+	return nil
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return digestPrefix + hex.EncodeToString(sum[:])
+}
+
+func sign(key, data []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,118 @@
+package plan
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apiv1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/engine/operation/models"
+)
+
+const deploymentID = "apps/v1:Deployment:default:nginx"
+
+func mockResource(replicas int) *apiv1.Resource {
+	return &apiv1.Resource{
+		ID:   deploymentID,
+		Type: apiv1.Kubernetes,
+		Attributes: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"spec":       map[string]interface{}{"replicas": replicas},
+		},
+	}
+}
+
+func mockChangeOrder(live, planned *apiv1.Resource, action models.ActionType) *models.ChangeOrder {
+	return &models.ChangeOrder{
+		StepKeys: []string{deploymentID},
+		ChangeSteps: map[string]*models.ChangeStep{
+			deploymentID: models.NewChangeStep(deploymentID, action, live, planned),
+		},
+	}
+}
+
+func mockPlan(t *testing.T) *Plan {
+	t.Helper()
+	spec := &apiv1.Spec{Resources: apiv1.Resources{*mockResource(2)}}
+	p, err := New("project", "dev", "prod", 3, spec, mockChangeOrder(mockResource(1), mockResource(2), models.Update))
+	require.NoError(t, err)
+	return p
+}
+
+func TestEncodeAndDecode(t *testing.T) {
+	p := mockPlan(t)
+
+	t.Run("round trip", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "plan")
+		require.NoError(t, WriteFile(path, p, nil))
+		decoded, err := ReadFile(path, nil)
+		require.NoError(t, err)
+		require.Equal(t, p.BaseRevision, decoded.BaseRevision)
+		require.Equal(t, p.Fingerprints, decoded.Fingerprints)
+		require.Equal(t, models.Update, decoded.ChangeOrder.Get(deploymentID).Action)
+		require.Equal(t, deploymentID, decoded.Spec.Resources[0].ID)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		data, err := Encode(p, nil)
+		require.NoError(t, err)
+		tampered := strings.Replace(string(data), `"replicas": 2`, `"replicas": 20`, 1)
+		require.NotEqual(t, string(data), tampered)
+		_, err = Decode([]byte(tampered), nil)
+		require.ErrorIs(t, err, ErrDigestMismatch)
+	})
+
+	t.Run("signed", func(t *testing.T) {
+		key := []byte("s3cr3t")
+		unsigned, err := Encode(p, nil)
+		require.NoError(t, err)
+		_, err = Decode(unsigned, key)
+		require.ErrorIs(t, err, ErrUnsignedPlan)
+
+		signed, err := Encode(p, key)
+		require.NoError(t, err)
+		_, err = Decode(signed, key)
+		require.NoError(t, err)
+		_, err = Decode(signed, []byte("another"))
+		require.ErrorIs(t, err, ErrSignatureMismatch)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := Decode([]byte(`{"kind": "Spec"}`), nil)
+		require.ErrorIs(t, err, ErrInvalidPlan)
+	})
+}
+
+func TestCheckBase(t *testing.T) {
+	p := mockPlan(t)
+	require.NoError(t, p.CheckBase("project", "dev", "prod", 3))
+	require.ErrorIs(t, p.CheckBase("project", "dev", "staging", 3), ErrPlanTargetMismatch)
+	require.ErrorIs(t, p.CheckBase("project", "dev", "prod", 4), ErrBaseRevisionMismatch)
+}
+
+func TestCheckChanges(t *testing.T) {
+	p := mockPlan(t)
+
+	t.Run("unchanged", func(t *testing.T) {
+		require.NoError(t, p.CheckChanges(mockChangeOrder(mockResource(1), mockResource(2), models.Update)))
+	})
+
+	t.Run("live state changed", func(t *testing.T) {
+		err := p.CheckChanges(mockChangeOrder(mockResource(3), mockResource(2), models.Update))
+		require.ErrorIs(t, err, ErrLiveStateChanged)
+		require.ErrorContains(t, err, deploymentID)
+	})
+
+	t.Run("action changed", func(t *testing.T) {
+		err := p.CheckChanges(mockChangeOrder(nil, mockResource(2), models.Create))
+		require.ErrorIs(t, err, ErrLiveStateChanged)
+	})
+
+	t.Run("resource removed", func(t *testing.T) {
+		err := p.CheckChanges(&models.ChangeOrder{ChangeSteps: map[string]*models.ChangeStep{}})
+		require.ErrorIs(t, err, ErrLiveStateChanged)
+	})
+}
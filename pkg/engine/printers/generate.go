@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"strings"
 
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"kusionstack.io/kusion/pkg/engine/printers/printer"
+	"kusionstack.io/kusion/pkg/engine/printers/status"
 	"kusionstack.io/kusion/pkg/util/kcl"
 )
 
@@ -20,6 +22,20 @@ func Generate(obj runtime.Object) (string, bool) {
 	return tg.GenerateTable(obj)
 }
 
+// Status returns the status detail of the resource and whether it is ready.
+// The resources with a readiness policy in the workspace are evaluated by the
+// policy, the kinds with a dedicated printer by the printer, and the others,
+// such as custom resources, by their status conditions.
+func Status(o *unstructured.Unstructured, policies status.Policies) (string, bool) {
+	if policy := policies.Get(o); policy != nil {
+		return status.Evaluate(o, policy)
+	}
+	if target := Convert(o); target != nil && tg.Supports(target) {
+		return tg.GenerateTable(target)
+	}
+	return status.Evaluate(o, nil)
+}
+
 // PrintCustomizedHealthCheck prints customized health check result defined in the `extensions` field of the resource in the Spec.
 func PrintCustomizedHealthCheck(healthPolicyCode string, resource []byte) (string, bool) {
 	// Skip when health policy is empty
@@ -81,6 +81,12 @@ func (h *HumanReadableGenerator) GenerateTable(obj runtime.Object) (string, bool
 	return results[0].String(), results[1].Bool()
 }
 
+// Supports returns whether a printer is registered for the type of the object.
+func (h *HumanReadableGenerator) Supports(obj runtime.Object) bool {
+	_, ok := h.handlerMap[reflect.TypeOf(obj)]
+	return ok
+}
+
 // TableHandler adds a print handler with a given set of columns to HumanReadableGenerator instance.
 // See ValidateRowPrintHandlerFunc for required method signature.
 func (h *HumanReadableGenerator) TableHandler(printFunc interface{}) error {
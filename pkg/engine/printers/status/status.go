@@ -0,0 +1,232 @@
+// Copyright 2024 KusionStack Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package status evaluates the readiness of arbitrary Kubernetes resources,
+// such as custom resources, from the conventions most controllers follow:
+// the status conditions and the observed generation, in the spirit of kstatus.
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	apiv1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+// ReadinessContextKey is the key of the workspace context holding the
+// readiness policies, keyed by `<group>/<kind>`, or `<kind>` for all groups.
+//
+// Example:
+//
+//	context:
+//	  readiness:
+//	    cert-manager.io/Certificate:
+//	      conditions: [Ready]
+//	    argoproj.io/Rollout:
+//	      phases: [Healthy]
+//	    Lease:
+//	      skip: true
+const ReadinessContextKey = "readiness"
+
+const (
+	conditionReady       = "Ready"
+	conditionAvailable   = "Available"
+	conditionProgressing = "Progressing"
+	conditionReconciling = "Reconciling"
+	conditionStalled     = "Stalled"
+	conditionFailed      = "Failed"
+	conditionDegraded    = "Degraded"
+
+	conditionTrue  = "True"
+	conditionFalse = "False"
+)
+
+// readyPhases are the values of `status.phase` indicating the resource is ready.
+var readyPhases = map[string]bool{
+	"Active":    true,
+	"Available": true,
+	"Bound":     true,
+	"Healthy":   true,
+	"Ready":     true,
+	"Running":   true,
+	"Succeeded": true,
+}
+
+// Policy overrides how the readiness of a kind of resources is evaluated.
+type Policy struct {
+	// Skip marks the resources as ready as soon as they are applied.
+	Skip bool `json:"skip,omitempty" yaml:"skip,omitempty"`
+	// Conditions are the types of the status conditions which must all be
+	// True for the resources to be ready.
+	Conditions []string `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+	// Phases are the values of `status.phase` which indicate the resources
+	// are ready.
+	Phases []string `json:"phases,omitempty" yaml:"phases,omitempty"`
+}
+
+// Policies are the readiness policies keyed by `<group>/<kind>` or `<kind>`.
+type Policies map[string]*Policy
+
+// PoliciesFromContext reads the readiness policies from the workspace context
+// carried by the spec.
+func PoliciesFromContext(ctx apiv1.GenericConfig) (Policies, error) {
+	raw, ok := ctx[ReadinessContextKey]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var policies Policies
+	if err = json.Unmarshal(data, &policies); err != nil {
+		return nil, fmt.Errorf("invalid %s in the workspace context: %w", ReadinessContextKey, err)
+	}
+	return policies, nil
+}
+
+// Get returns the policy of the resource, preferring the one of its group and
+// kind to the one of its kind.
+func (p Policies) Get(o *unstructured.Unstructured) *Policy {
+	if len(p) == 0 {
+		return nil
+	}
+	gvk := o.GroupVersionKind()
+	if policy, ok := p[gvk.Group+"/"+gvk.Kind]; ok {
+		return policy
+	}
+	return p[gvk.Kind]
+}
+
+// Evaluate returns a message describing the status of the resource and
+// whether it is ready, following the policy if not nil.
+//
+// Without a policy, a resource is ready once its controller has observed the
+// latest generation and its Ready condition, or its Available condition
+// otherwise, is True. A resource reporting no conditions nor phase is ready
+// as soon as it exists, since there is nothing to wait on.
+func Evaluate(o *unstructured.Unstructured, policy *Policy) (string, bool) {
+	if policy != nil && policy.Skip {
+		return "Readiness check skipped", true
+	}
+	if o.GetDeletionTimestamp() != nil {
+		return "Terminating", false
+	}
+
+	// The status is stale until the controller observes the latest generation.
+	observed, found, err := unstructured.NestedInt64(o.Object, "status", "observedGeneration")
+	if err == nil && found && observed < o.GetGeneration() {
+		return fmt.Sprintf("Waiting for generation %d to be observed, observed: %d", o.GetGeneration(), observed), false
+	}
+
+	conditions := getConditions(o)
+	phase, _, _ := unstructured.NestedString(o.Object, "status", "phase")
+
+	if policy != nil && (len(policy.Conditions) > 0 || len(policy.Phases) > 0) {
+		return evaluatePolicy(policy, conditions, phase)
+	}
+
+	// Abnormal-true conditions indicate the resource is not ready regardless
+	// of the others.
+	for _, t := range []string{conditionStalled, conditionFailed, conditionDegraded} {
+		if c, ok := conditions[t]; ok && c.Status == conditionTrue {
+			return c.describe(), false
+		}
+	}
+	if c, ok := conditions[conditionReconciling]; ok && c.Status == conditionTrue {
+		return c.describe(), false
+	}
+
+	for _, t := range []string{conditionReady, conditionAvailable} {
+		if c, ok := conditions[t]; ok {
+			return c.describe(), c.Status == conditionTrue
+		}
+	}
+	if c, ok := conditions[conditionProgressing]; ok && c.Status == conditionFalse {
+		return c.describe(), false
+	}
+
+	if phase != "" {
+		return fmt.Sprintf("Phase: %s", phase), readyPhases[phase]
+	}
+	if len(conditions) > 0 {
+		return "No Ready condition, considered ready", true
+	}
+	return "No status conditions, considered ready", true
+}
+
+func evaluatePolicy(policy *Policy, conditions map[string]condition, phase string) (string, bool) {
+	for _, t := range policy.Conditions {
+		c, ok := conditions[t]
+		if !ok {
+			return fmt.Sprintf("Waiting for condition %s", t), false
+		}
+		if c.Status != conditionTrue {
+			return c.describe(), false
+		}
+	}
+	if len(policy.Phases) > 0 {
+		for _, p := range policy.Phases {
+			if p == phase {
+				return fmt.Sprintf("Phase: %s", phase), true
+			}
+		}
+		if phase == "" {
+			return fmt.Sprintf("Waiting for phase %s", strings.Join(policy.Phases, " or ")), false
+		}
+		return fmt.Sprintf("Phase: %s", phase), false
+	}
+	return fmt.Sprintf("Conditions %s are True", strings.Join(policy.Conditions, ", ")), true
+}
+
+type condition struct {
+	Type    string
+	Status  string
+	Reason  string
+	Message string
+}
+
+func (c condition) describe() string {
+	detail := fmt.Sprintf("%s: %s", c.Type, c.Status)
+	if c.Reason != "" {
+		detail += ", Reason: " + c.Reason
+	}
+	if c.Message != "" {
+		detail += ", Message: " + c.Message
+	}
+	return detail
+}
+
+func getConditions(o *unstructured.Unstructured) map[string]condition {
+	items, _, _ := unstructured.NestedSlice(o.Object, "status", "conditions")
+	conditions := make(map[string]condition, len(items))
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		c := condition{}
+		c.Type, _ = m["type"].(string)
+		c.Status, _ = m["status"].(string)
+		c.Reason, _ = m["reason"].(string)
+		c.Message, _ = m["message"].(string)
+		if c.Type != "" {
+			conditions[c.Type] = c
+		}
+	}
+	return conditions
+}
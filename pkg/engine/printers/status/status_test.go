@@ -0,0 +1,152 @@
+package status
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	apiv1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func newObject(apiVersion, kind string, generation int64, status map[string]interface{}) *unstructured.Unstructured {
+	o := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"name":       "foo",
+			"namespace":  "default",
+			"generation": generation,
+		},
+	}}
+	if status != nil {
+		o.Object["status"] = status
+	}
+	return o
+}
+
+func conditions(pairs ...string) []interface{} {
+	var items []interface{}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		items = append(items, map[string]interface{}{"type": pairs[i], "status": pairs[i+1]})
+	}
+	return items
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name   string
+		obj    *unstructured.Unstructured
+		policy *Policy
+		ready  bool
+	}{
+		{
+			name:  "no status",
+			obj:   newObject("example.com/v1", "Widget", 1, nil),
+			ready: true,
+		},
+		{
+			name: "certificate ready",
+			obj: newObject("cert-manager.io/v1", "Certificate", 2, map[string]interface{}{
+				"observedGeneration": int64(2),
+				"conditions":         conditions("Ready", "True"),
+			}),
+			ready: true,
+		},
+		{
+			name: "generation not observed",
+			obj: newObject("cert-manager.io/v1", "Certificate", 3, map[string]interface{}{
+				"observedGeneration": int64(2),
+				"conditions":         conditions("Ready", "True"),
+			}),
+			ready: false,
+		},
+		{
+			name: "knative service not ready",
+			obj: newObject("serving.knative.dev/v1", "Service", 1, map[string]interface{}{
+				"conditions": conditions("ConfigurationsReady", "True", "Ready", "Unknown"),
+			}),
+			ready: false,
+		},
+		{
+			name: "available",
+			obj: newObject("example.com/v1", "Widget", 1, map[string]interface{}{
+				"conditions": conditions("Progressing", "True", "Available", "True"),
+			}),
+			ready: true,
+		},
+		{
+			name: "stalled",
+			obj: newObject("example.com/v1", "Widget", 1, map[string]interface{}{
+				"conditions": conditions("Ready", "True", "Stalled", "True"),
+			}),
+			ready: false,
+		},
+		{
+			name: "progressing false",
+			obj: newObject("example.com/v1", "Widget", 1, map[string]interface{}{
+				"conditions": conditions("Progressing", "False"),
+			}),
+			ready: false,
+		},
+		{
+			name: "rollout phase",
+			obj: newObject("argoproj.io/v1alpha1", "Rollout", 1, map[string]interface{}{
+				"phase": "Progressing",
+			}),
+			ready: false,
+		},
+		{
+			name: "policy conditions",
+			obj: newObject("example.com/v1", "Widget", 1, map[string]interface{}{
+				"conditions": conditions("Ready", "True", "Synced", "False"),
+			}),
+			policy: &Policy{Conditions: []string{"Ready", "Synced"}},
+			ready:  false,
+		},
+		{
+			name: "policy phases",
+			obj: newObject("argoproj.io/v1alpha1", "Rollout", 1, map[string]interface{}{
+				"phase": "Healthy",
+			}),
+			policy: &Policy{Phases: []string{"Healthy"}},
+			ready:  true,
+		},
+		{
+			name: "policy skip",
+			obj: newObject("example.com/v1", "Widget", 1, map[string]interface{}{
+				"conditions": conditions("Ready", "False"),
+			}),
+			policy: &Policy{Skip: true},
+			ready:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detail, ready := Evaluate(tt.obj, tt.policy)
+			require.Equal(t, tt.ready, ready, detail)
+		})
+	}
+}
+
+func TestPoliciesFromContext(t *testing.T) {
+	policies, err := PoliciesFromContext(apiv1.GenericConfig{
+		ReadinessContextKey: map[string]interface{}{
+			"argoproj.io/Rollout": map[string]interface{}{"phases": []interface{}{"Healthy"}},
+			"Rollout":             map[string]interface{}{"skip": true},
+			"Certificate":         map[string]interface{}{"conditions": []interface{}{"Ready"}},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Healthy"}, policies.Get(newObject("argoproj.io/v1alpha1", "Rollout", 1, nil)).Phases)
+	require.True(t, policies.Get(newObject("example.com/v1", "Rollout", 1, nil)).Skip)
+	require.Equal(t, []string{"Ready"}, policies.Get(newObject("cert-manager.io/v1", "Certificate", 1, nil)).Conditions)
+	require.Nil(t, policies.Get(newObject("apps/v1", "Deployment", 1, nil)))
+
+	policies, err = PoliciesFromContext(nil)
+	require.NoError(t, err)
+	require.Nil(t, policies.Get(newObject("apps/v1", "Deployment", 1, nil)))
+
+	_, err = PoliciesFromContext(apiv1.GenericConfig{ReadinessContextKey: "invalid"})
+	require.Error(t, err)
+}
@@ -1,14 +1,20 @@
 package printers
 
 import (
+	"sync"
+
 	k8swatch "k8s.io/apimachinery/pkg/watch"
 
 	"kusionstack.io/kusion/pkg/util/pretty"
 )
 
+// Table holds the latest status of the watched objects, one row per object.
+// It is safe to update it while it is rendered.
 type Table struct {
 	IDs  []string
 	Rows map[string]*Row
+
+	mu sync.RWMutex
 }
 
 type Row struct {
@@ -37,10 +43,14 @@ func NewRow(t k8swatch.EventType, kind, name, detail string) *Row {
 const READY k8swatch.EventType = "READY"
 
 func (t *Table) Update(id string, row *Row) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 	t.Rows[id] = row
 }
 
 func (t *Table) AllCompleted() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
 	if len(t.Rows) < len(t.IDs) {
 		return false
 	}
@@ -53,6 +63,8 @@ func (t *Table) AllCompleted() bool {
 }
 
 func (t *Table) Print() [][]string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
 	data := [][]string{{"Type", "Kind", "Name", "Detail"}}
 	for _, id := range t.IDs {
 		var eventType k8swatch.EventType
@@ -0,0 +1,308 @@
+package release
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/util/diff"
+	"kusionstack.io/kusion/pkg/util/mask"
+	"kusionstack.io/kusion/pkg/util/pretty"
+)
+
+// DiffAction is the change of a resource between two releases.
+type DiffAction string
+
+const (
+	DiffAdded     DiffAction = "added"
+	DiffRemoved   DiffAction = "removed"
+	DiffModified  DiffAction = "modified"
+	DiffUnchanged DiffAction = "unchanged"
+)
+
+// DiffFilter selects the resources to compare. An empty field selects all the resources.
+type DiffFilter struct {
+	// Types are the resource types to compare, such as Kubernetes and Terraform, matched case-insensitively.
+	Types []string
+	// IDs are the resources to compare, whose IDs contain any of them.
+	IDs []string
+}
+
+// ReleaseDiff is the differences of the resources between two releases.
+type ReleaseDiff struct {
+	Project   string          `json:"project" yaml:"project"`
+	Workspace string          `json:"workspace" yaml:"workspace"`
+	From      uint64          `json:"from" yaml:"from"`
+	To        uint64          `json:"to" yaml:"to"`
+	Summary   DiffSummary     `json:"summary" yaml:"summary"`
+	Resources []*ResourceDiff `json:"resources" yaml:"resources"`
+}
+
+// DiffSummary is the number of the resources of each action.
+type DiffSummary struct {
+	Added     int `json:"added" yaml:"added"`
+	Removed   int `json:"removed" yaml:"removed"`
+	Modified  int `json:"modified" yaml:"modified"`
+	Unchanged int `json:"unchanged" yaml:"unchanged"`
+}
+
+// ResourceDiff is the differences of a resource in the spec and state between two releases.
+type ResourceDiff struct {
+	ID     string     `json:"id" yaml:"id"`
+	Type   v1.Type    `json:"type" yaml:"type"`
+	Action DiffAction `json:"action" yaml:"action"`
+	Spec   *ValueDiff `json:"spec" yaml:"spec"`
+	State  *ValueDiff `json:"state" yaml:"state"`
+}
+
+// ValueDiff is the resource in the spec or state of the two releases, with the sensitive data masked.
+type ValueDiff struct {
+	Changed bool        `json:"changed" yaml:"changed"`
+	From    interface{} `json:"from,omitempty" yaml:"from,omitempty"`
+	To      interface{} `json:"to,omitempty" yaml:"to,omitempty"`
+}
+
+// Match returns whether the resource is selected by the filter.
+func (f *DiffFilter) Match(res *v1.Resource) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.Types) != 0 {
+		matched := false
+		for _, t := range f.Types {
+			if strings.EqualFold(t, string(res.Type)) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(f.IDs) != 0 {
+		for _, id := range f.IDs {
+			if strings.Contains(res.ID, id) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Diff compares the spec and state of each resource selected by the filter between the two releases, in the
+// order of the resources in the to release followed by the ones removed from the from release.
+func Diff(from, to *v1.Release, filter *DiffFilter) (*ReleaseDiff, error) {
+	d := &ReleaseDiff{
+		Project:   to.Project,
+		Workspace: to.Workspace,
+		From:      from.Revision,
+		To:        to.Revision,
+		Resources: []*ResourceDiff{},
+	}
+
+	// Mask the sensitive data with the rules in the workspace context of the to release.
+	var ctx v1.GenericConfig
+	if to.Spec != nil {
+		ctx = to.Spec.Context
+	}
+	policy, err := mask.PolicyFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fromSpec, fromState := indexResources(from)
+	toSpec, toState := indexResources(to)
+
+	var ids []string
+	seen := make(map[string]bool)
+	resources := make(map[string]*v1.Resource)
+	for _, rel := range []*v1.Release{to, from} {
+		for _, res := range releaseResources(rel) {
+			if seen[res.ID] {
+				continue
+			}
+			seen[res.ID] = true
+			if filter.Match(res) {
+				ids = append(ids, res.ID)
+				resources[res.ID] = res
+			}
+		}
+	}
+
+	for _, id := range ids {
+		specDiff, err := diffValue(policy, fromSpec[id], toSpec[id])
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare the spec of resource %s: %w", id, err)
+		}
+		stateDiff, err := diffValue(policy, fromState[id], toState[id])
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare the state of resource %s: %w", id, err)
+		}
+
+		rd := &ResourceDiff{ID: id, Type: resources[id].Type, Spec: specDiff, State: stateDiff}
+		switch {
+		case fromSpec[id] == nil && fromState[id] == nil:
+			rd.Action = DiffAdded
+			d.Summary.Added++
+		case toSpec[id] == nil && toState[id] == nil:
+			rd.Action = DiffRemoved
+			d.Summary.Removed++
+		case specDiff.Changed || stateDiff.Changed:
+			rd.Action = DiffModified
+			d.Summary.Modified++
+		default:
+			rd.Action = DiffUnchanged
+			d.Summary.Unchanged++
+		}
+		d.Resources = append(d.Resources, rd)
+	}
+	return d, nil
+}
+
+// Human renders the differences of the changed resources for the terminal.
+func (d *ReleaseDiff) Human(noStyle bool) (string, error) {
+	bold := func(format string, a ...interface{}) string {
+		if noStyle {
+			return fmt.Sprintf(format, a...)
+		}
+		return pretty.GreenBold(format, a...)
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "Release diff of project %s, workspace %s: revision %d -> %d\n", d.Project, d.Workspace, d.From, d.To)
+	fmt.Fprintf(buf, "%d added, %d removed, %d modified, %d unchanged\n",
+		d.Summary.Added, d.Summary.Removed, d.Summary.Modified, d.Summary.Unchanged)
+	for _, rd := range d.Resources {
+		if rd.Action == DiffUnchanged {
+			continue
+		}
+		fmt.Fprintf(buf, "\n%s%s (%s)\n", bold("ID: "), rd.ID, rd.Action)
+		for _, part := range []struct {
+			name  string
+			value *ValueDiff
+		}{{"Spec", rd.Spec}, {"State", rd.State}} {
+			if !part.value.Changed {
+				continue
+			}
+			report, err := diff.ToReport(part.value.From, part.value.To)
+			if err != nil {
+				return "", err
+			}
+			human, err := diff.ToHumanString(diff.NewHumanReport(report))
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(buf, "%s\n%s\n", bold("%s:", part.name), strings.TrimSpace(human))
+		}
+	}
+	return buf.String(), nil
+}
+
+// Markdown renders the differences as Markdown with a summary table and a collapsible section of the unified
+// diff of each changed resource.
+func (d *ReleaseDiff) Markdown() (string, error) {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "### Kusion Release Diff: `%s` of `%s`, revision %d -> %d\n\n", d.Workspace, d.Project, d.From, d.To)
+	fmt.Fprintf(buf, "**Summary:** %d added, %d removed, %d modified, %d unchanged.\n\n",
+		d.Summary.Added, d.Summary.Removed, d.Summary.Modified, d.Summary.Unchanged)
+	if d.Summary.Added+d.Summary.Removed+d.Summary.Modified == 0 {
+		buf.WriteString("No diff found.\n")
+		return buf.String(), nil
+	}
+
+	buf.WriteString("| ID | Type | Action | Spec | State |\n| --- | --- | --- | --- | --- |\n")
+	for _, rd := range d.Resources {
+		fmt.Fprintf(buf, "| `%s` | %s | %s | %s | %s |\n", strings.ReplaceAll(rd.ID, "|", "\\|"), rd.Type, rd.Action,
+			changedMark(rd.Spec), changedMark(rd.State))
+	}
+
+	escape := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	for _, rd := range d.Resources {
+		if rd.Action == DiffUnchanged {
+			continue
+		}
+		fmt.Fprintf(buf, "\n<details>\n<summary>%s <code>%s</code></summary>\n", rd.Action, escape.Replace(rd.ID))
+		for _, part := range []struct {
+			name  string
+			value *ValueDiff
+		}{{"spec", rd.Spec}, {"state", rd.State}} {
+			if !part.value.Changed {
+				continue
+			}
+			fromFile := fmt.Sprintf("%s/%d/%s", part.name, d.From, rd.ID)
+			toFile := fmt.Sprintf("%s/%d/%s", part.name, d.To, rd.ID)
+			if part.value.From == nil {
+				fromFile = "/dev/null"
+			}
+			if part.value.To == nil {
+				toFile = "/dev/null"
+			}
+			patch, err := diff.ToUnifiedString(part.value.From, part.value.To, fromFile, toFile)
+			if err != nil {
+				return "", fmt.Errorf("failed to generate the patch of %s: %w", rd.ID, err)
+			}
+			// Use a longer fence in case the diff contains backticks.
+			fmt.Fprintf(buf, "\n````diff\n%s````\n", patch)
+		}
+		buf.WriteString("\n</details>\n")
+	}
+	return buf.String(), nil
+}
+
+func changedMark(v *ValueDiff) string {
+	if v.Changed {
+		return "changed"
+	}
+	return "-"
+}
+
+// diffValue compares the resources, and masks the sensitive data of them with the policy.
+func diffValue(policy *mask.Policy, from, to *v1.Resource) (*ValueDiff, error) {
+	var fromData, toData interface{}
+	if from != nil {
+		fromData = from
+	}
+	if to != nil {
+		toData = to
+	}
+	report, err := diff.ToReport(fromData, toData)
+	if err != nil {
+		return nil, err
+	}
+	maskedFrom, maskedTo := policy.Mask(fromData, toData)
+	return &ValueDiff{Changed: len(report.Diffs) != 0, From: maskedFrom, To: maskedTo}, nil
+}
+
+// indexResources returns the resources in the spec and state of the release by ID.
+func indexResources(rel *v1.Release) (map[string]*v1.Resource, map[string]*v1.Resource) {
+	spec, state := make(map[string]*v1.Resource), make(map[string]*v1.Resource)
+	if rel.Spec != nil {
+		for i := range rel.Spec.Resources {
+			spec[rel.Spec.Resources[i].ID] = &rel.Spec.Resources[i]
+		}
+	}
+	if rel.State != nil {
+		for i := range rel.State.Resources {
+			state[rel.State.Resources[i].ID] = &rel.State.Resources[i]
+		}
+	}
+	return spec, state
+}
+
+// releaseResources returns the resources in the spec and then the state of the release.
+func releaseResources(rel *v1.Release) []*v1.Resource {
+	var resources []*v1.Resource
+	if rel.Spec != nil {
+		for i := range rel.Spec.Resources {
+			resources = append(resources, &rel.Spec.Resources[i])
+		}
+	}
+	if rel.State != nil {
+		for i := range rel.State.Resources {
+			resources = append(resources, &rel.State.Resources[i])
+		}
+	}
+	return resources
+}
@@ -0,0 +1,112 @@
+package release
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func newDiffReleases() (*v1.Release, *v1.Release) {
+	cm := func(data string) v1.Resource {
+		return v1.Resource{ID: "v1:ConfigMap:default:foo", Type: v1.Kubernetes, Attributes: map[string]interface{}{"data": data}}
+	}
+	sa := v1.Resource{ID: "v1:ServiceAccount:default:foo", Type: v1.Kubernetes, Attributes: map[string]interface{}{"name": "foo"}}
+	bucket := v1.Resource{ID: "hashicorp:aws:aws_s3_bucket:foo", Type: v1.Terraform, Attributes: map[string]interface{}{"bucket": "foo"}}
+	secret := v1.Resource{ID: "v1:Secret:default:foo", Type: v1.Kubernetes, Attributes: map[string]interface{}{
+		"apiVersion": "v1", "kind": "Secret", "data": map[string]interface{}{"password": "MTIzNDU2"},
+	}}
+
+	from := &v1.Release{
+		Project: "project", Workspace: "dev", Revision: 12,
+		Spec:  &v1.Spec{Resources: v1.Resources{cm("v1"), sa, bucket}},
+		State: &v1.State{Resources: v1.Resources{cm("v1"), sa, bucket}},
+	}
+	to := &v1.Release{
+		Project: "project", Workspace: "dev", Revision: 17,
+		Spec:  &v1.Spec{Resources: v1.Resources{cm("v2"), bucket, secret}},
+		State: &v1.State{Resources: v1.Resources{cm("v2-live"), bucket, secret}},
+	}
+	return from, to
+}
+
+func TestDiff(t *testing.T) {
+	from, to := newDiffReleases()
+
+	d, err := Diff(from, to, nil)
+	require.NoError(t, err)
+	assert.Equal(t, DiffSummary{Added: 1, Removed: 1, Modified: 1, Unchanged: 1}, d.Summary)
+	actions := map[string]DiffAction{}
+	for _, rd := range d.Resources {
+		actions[rd.ID] = rd.Action
+	}
+	assert.Equal(t, map[string]DiffAction{
+		"v1:ConfigMap:default:foo":        DiffModified,
+		"hashicorp:aws:aws_s3_bucket:foo": DiffUnchanged,
+		"v1:Secret:default:foo":           DiffAdded,
+		"v1:ServiceAccount:default:foo":   DiffRemoved,
+	}, actions)
+	assert.Equal(t, "v1:ServiceAccount:default:foo", d.Resources[3].ID)
+
+	// the sensitive data is masked
+	data, err := json.Marshal(d)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "MTIzNDU2")
+
+	human, err := d.Human(true)
+	require.NoError(t, err)
+	assert.Contains(t, human, "revision 12 -> 17")
+	assert.Contains(t, human, "ID: v1:ConfigMap:default:foo (modified)")
+	assert.NotContains(t, human, "aws_s3_bucket")
+
+	md, err := d.Markdown()
+	require.NoError(t, err)
+	assert.Contains(t, md, "1 added, 1 removed, 1 modified, 1 unchanged")
+	assert.Contains(t, md, "+++ spec/17/v1:ConfigMap:default:foo")
+	assert.Contains(t, md, "--- /dev/null")
+	assert.NotContains(t, md, "MTIzNDU2")
+}
+
+func TestDiffWithWorkspaceMaskingRules(t *testing.T) {
+	from, to := newDiffReleases()
+	to.Spec.Context = v1.GenericConfig{
+		"masking": map[string]interface{}{
+			"rules": []interface{}{
+				map[string]interface{}{"type": "Kubernetes", "paths": []interface{}{"attributes.data"}},
+			},
+		},
+	}
+
+	d, err := Diff(from, to, nil)
+	require.NoError(t, err)
+	data, err := json.Marshal(d)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "v2-live")
+	assert.Equal(t, DiffSummary{Added: 1, Removed: 1, Modified: 1, Unchanged: 1}, d.Summary)
+
+	to.Spec.Context = v1.GenericConfig{"masking": "invalid"}
+	_, err = Diff(from, to, nil)
+	assert.Error(t, err)
+}
+
+func TestDiffFilter(t *testing.T) {
+	from, to := newDiffReleases()
+
+	d, err := Diff(from, to, &DiffFilter{Types: []string{"terraform"}})
+	require.NoError(t, err)
+	require.Len(t, d.Resources, 1)
+	assert.Equal(t, "hashicorp:aws:aws_s3_bucket:foo", d.Resources[0].ID)
+
+	d, err = Diff(from, to, &DiffFilter{Types: []string{"Kubernetes"}, IDs: []string{"ConfigMap", "Secret"}})
+	require.NoError(t, err)
+	assert.Equal(t, DiffSummary{Added: 1, Modified: 1}, d.Summary)
+
+	d, err = Diff(from, from, nil)
+	require.NoError(t, err)
+	md, err := d.Markdown()
+	require.NoError(t, err)
+	assert.Contains(t, md, "No diff found.")
+}
@@ -0,0 +1,136 @@
+package release
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/log"
+	"kusionstack.io/kusion/pkg/util/gitutil"
+)
+
+const (
+	// EnvOperator is the environment variable to specify the operator recorded in the release provenance,
+	// which defaults to the current OS user.
+	EnvOperator = "KUSION_OPERATOR"
+
+	kclModFile = "kcl.mod"
+)
+
+// NewProvenance returns the provenance metadata of a release of the stack with the spec produced by the Kusion
+// version, which records the operator, the git information of the stack source, the versions of the modules and
+// the terraform providers, and the notes. The metadata that can't be collected is left empty.
+func NewProvenance(kusionVersion string, stack *v1.Stack, spec *v1.Spec, operator, notes string) *v1.ReleaseProvenance {
+	p := &v1.ReleaseProvenance{
+		Operator:      operator,
+		KusionVersion: kusionVersion,
+		Providers:     providerVersions(spec),
+		Notes:         notes,
+	}
+	if stack != nil && stack.Path != "" {
+		if info := gitutil.Get(stack.Path); info.Commit != "" {
+			p.Git = &v1.ReleaseGitInfo{
+				RemoteURL: info.RemoteURL,
+				Commit:    info.Commit,
+				Branch:    info.Branch,
+				Dirty:     info.Dirty,
+			}
+		}
+		modules, err := moduleVersions(filepath.Join(stack.Path, kclModFile))
+		if err != nil {
+			log.Warnf("failed to read the module versions of stack %s: %v", stack.Name, err)
+		}
+		p.Modules = modules
+	}
+	return p
+}
+
+// CurrentOperator returns the operator specified by the environment variable, or the current OS user.
+func CurrentOperator() string {
+	if operator := os.Getenv(EnvOperator); operator != "" {
+		return operator
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
+
+// moduleVersions returns the versions of the dependencies in the kcl.mod file, which is either the version
+// string or the tag or version of the source, such as
+//
+//	[dependencies]
+//	kam = { git = "https://github.com/KusionStack/kam.git", tag = "0.2.0" }
+//	service = { oci = "oci://ghcr.io/kusionstack/service", tag = "0.1.0" }
+//	k8s = "1.28"
+func moduleVersions(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	mod := struct {
+		Dependencies map[string]interface{} `toml:"dependencies"`
+	}{}
+	if err = toml.Unmarshal(content, &mod); err != nil {
+		return nil, err
+	}
+
+	var versions map[string]string
+	for name, dep := range mod.Dependencies {
+		v := ""
+		switch d := dep.(type) {
+		case string:
+			v = d
+		case map[string]interface{}:
+			for _, key := range []string{"tag", "version", "commit", "path"} {
+				if s, ok := d[key].(string); ok && s != "" {
+					v = s
+					break
+				}
+			}
+		}
+		if v == "" {
+			continue
+		}
+		if versions == nil {
+			versions = make(map[string]string)
+		}
+		versions[name] = v
+	}
+	return versions, nil
+}
+
+// providerVersions returns the versions of the terraform providers of the resources in the spec, whose
+// provider extension is formatted as registry.terraform.io/hashicorp/aws/5.0.1.
+func providerVersions(spec *v1.Spec) map[string]string {
+	if spec == nil {
+		return nil
+	}
+	var versions map[string]string
+	for _, res := range spec.Resources {
+		if res.Type != v1.Terraform {
+			continue
+		}
+		provider, ok := res.Extensions["provider"].(string)
+		if !ok {
+			continue
+		}
+		i := strings.LastIndex(provider, "/")
+		if i <= 0 {
+			continue
+		}
+		if versions == nil {
+			versions = make(map[string]string)
+		}
+		versions[provider[:i]] = provider[i+1:]
+	}
+	return versions
+}
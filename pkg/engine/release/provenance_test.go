@@ -0,0 +1,46 @@
+package release
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func TestNewProvenance(t *testing.T) {
+	stackPath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(stackPath, kclModFile), []byte(`[package]
+name = "foo"
+
+[dependencies]
+kam = { git = "https://github.com/KusionStack/kam.git", tag = "0.2.0" }
+service = { oci = "oci://ghcr.io/kusionstack/service", tag = "0.1.0" }
+k8s = "1.28"
+local = { path = "../local" }
+`), 0o644))
+	spec := &v1.Spec{Resources: v1.Resources{
+		{ID: "hashicorp:aws:aws_s3_bucket:foo", Type: v1.Terraform, Extensions: map[string]interface{}{"provider": "registry.terraform.io/hashicorp/aws/5.0.1"}},
+		{ID: "v1:ConfigMap:default:foo", Type: v1.Kubernetes},
+	}}
+
+	p := NewProvenance("v0.14.0", &v1.Stack{Name: "dev", Path: stackPath}, spec, "alice", "rotate the keys")
+	assert.Equal(t, "alice", p.Operator)
+	assert.Equal(t, "v0.14.0", p.KusionVersion)
+	assert.Equal(t, "rotate the keys", p.Notes)
+	assert.Nil(t, p.Git)
+	assert.Equal(t, map[string]string{"kam": "0.2.0", "service": "0.1.0", "k8s": "1.28", "local": "../local"}, p.Modules)
+	assert.Equal(t, map[string]string{"registry.terraform.io/hashicorp/aws": "5.0.1"}, p.Providers)
+
+	p = NewProvenance("v0.14.0", nil, nil, "", "")
+	assert.Nil(t, p.Modules)
+	assert.Nil(t, p.Providers)
+}
+
+func TestCurrentOperator(t *testing.T) {
+	t.Setenv(EnvOperator, "bob")
+	assert.Equal(t, "bob", CurrentOperator())
+}
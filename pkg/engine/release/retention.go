@@ -0,0 +1,125 @@
+package release
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+var (
+	ErrPruneNotSupported = errors.New("the release storage does not support pruning releases")
+	ErrReleaseInProgress = errors.New("the latest release is in progress")
+)
+
+// RetentionFromWorkspace returns the release retention configured in the workspace context, nil if not
+// configured.
+func RetentionFromWorkspace(ws *v1.Workspace) (*v1.ReleaseRetention, error) {
+	if ws == nil || ws.Context[v1.ReleaseRetentionKey] == nil {
+		return nil, nil
+	}
+	content, err := json.Marshal(ws.Context[v1.ReleaseRetentionKey])
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s of workspace %s: %w", v1.ReleaseRetentionKey, ws.Name, err)
+	}
+	retention := &v1.ReleaseRetention{}
+	if err = json.Unmarshal(content, retention); err != nil {
+		return nil, fmt.Errorf("invalid %s of workspace %s: %w", v1.ReleaseRetentionKey, ws.Name, err)
+	}
+	if err = ValidateRetention(retention); err != nil {
+		return nil, fmt.Errorf("invalid %s of workspace %s: %w", v1.ReleaseRetentionKey, ws.Name, err)
+	}
+	return retention, nil
+}
+
+// ValidateRetention checks the release retention is valid or not.
+func ValidateRetention(retention *v1.ReleaseRetention) error {
+	if retention.KeepLast < 0 {
+		return fmt.Errorf("keepLast must not be negative, got %d", retention.KeepLast)
+	}
+	if retention.KeepWithin != "" {
+		if _, err := time.ParseDuration(retention.KeepWithin); err != nil {
+			return fmt.Errorf("invalid keepWithin: %w", err)
+		}
+	}
+	if retention.KeepLast == 0 && retention.KeepWithin == "" {
+		return errors.New("either keepLast or keepWithin must be set")
+	}
+	return nil
+}
+
+// PruneCandidates returns the Revisions of the Releases to prune under the retention at the time now, in the
+// ascending order. The latest Release, the latest Release of each Stack, and the Releases in progress are
+// always kept.
+func PruneCandidates(storage Storage, retention *v1.ReleaseRetention, now time.Time) ([]uint64, error) {
+	if err := ValidateRetention(retention); err != nil {
+		return nil, err
+	}
+	var keepWithin time.Duration
+	if retention.KeepWithin != "" {
+		keepWithin, _ = time.ParseDuration(retention.KeepWithin)
+	}
+
+	revisions := storage.GetRevisions()
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i] > revisions[j]
+	})
+	latest := storage.GetLatestRevision()
+
+	var candidates []uint64
+	stacks := make(map[string]bool)
+	for i, revision := range revisions {
+		r, err := storage.Get(revision)
+		if err != nil {
+			return nil, fmt.Errorf("get release of revision %d failed: %w", revision, err)
+		}
+
+		keep := revision == latest ||
+			!stacks[r.Stack] ||
+			(r.Phase != v1.ReleasePhaseSucceeded && r.Phase != v1.ReleasePhaseFailed) ||
+			(retention.KeepLast > 0 && i < retention.KeepLast) ||
+			(keepWithin > 0 && now.Sub(r.CreateTime) < keepWithin)
+		stacks[r.Stack] = true
+		if !keep {
+			candidates = append(candidates, revision)
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i] < candidates[j]
+	})
+	return candidates, nil
+}
+
+// Prune deletes the Releases not kept under the retention at the time now, and compacts the metadata of the
+// Releases, returns the Revisions of the pruned Releases. If dryRun is true, nothing gets deleted. It refuses
+// to prune while the latest Release is in progress, whose apply or destroy may be writing the metadata.
+func Prune(storage Storage, retention *v1.ReleaseRetention, now time.Time, dryRun bool) ([]uint64, error) {
+	pruner, ok := storage.(Pruner)
+	if !ok {
+		return nil, ErrPruneNotSupported
+	}
+	if latest := storage.GetLatestRevision(); latest != 0 {
+		r, err := storage.Get(latest)
+		if err != nil {
+			return nil, fmt.Errorf("get the latest release failed: %w", err)
+		}
+		if r.Phase != v1.ReleasePhaseSucceeded && r.Phase != v1.ReleasePhaseFailed {
+			return nil, fmt.Errorf("%w: revision %d is %s", ErrReleaseInProgress, latest, r.Phase)
+		}
+	}
+	candidates, err := PruneCandidates(storage, retention, now)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return candidates, nil
+	}
+	if err = pruner.Delete(candidates...); err != nil {
+		return nil, fmt.Errorf("delete releases failed: %w", err)
+	}
+	return candidates, nil
+}
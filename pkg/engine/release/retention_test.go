@@ -0,0 +1,94 @@
+package release
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/engine/release/storages"
+)
+
+func newRetentionStorage(t *testing.T, now time.Time) Storage {
+	storage, err := storages.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	// Revision 1 is the only release of stack prod, and revision 6 is the latest one.
+	stacks := []string{"prod", "dev", "dev", "dev", "dev", "dev"}
+	for i, stack := range stacks {
+		revision := uint64(i + 1)
+		require.NoError(t, storage.Create(&v1.Release{
+			Project: "project", Workspace: "dev", Revision: revision, Stack: stack,
+			Phase:      v1.ReleasePhaseSucceeded,
+			CreateTime: now.Add(-time.Duration(len(stacks)-i) * 24 * time.Hour),
+		}))
+	}
+	return storage
+}
+
+func TestPruneCandidates(t *testing.T) {
+	now := time.Now()
+	storage := newRetentionStorage(t, now)
+
+	candidates, err := PruneCandidates(storage, &v1.ReleaseRetention{KeepLast: 2}, now)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{2, 3, 4}, candidates)
+
+	candidates, err = PruneCandidates(storage, &v1.ReleaseRetention{KeepWithin: "60h"}, now)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{2, 3, 4}, candidates)
+
+	candidates, err = PruneCandidates(storage, &v1.ReleaseRetention{KeepLast: 3, KeepWithin: "60h"}, now)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{2, 3}, candidates)
+
+	_, err = PruneCandidates(storage, &v1.ReleaseRetention{}, now)
+	assert.Error(t, err)
+	_, err = PruneCandidates(storage, &v1.ReleaseRetention{KeepWithin: "30d"}, now)
+	assert.Error(t, err)
+}
+
+func TestPrune(t *testing.T) {
+	now := time.Now()
+	storage := newRetentionStorage(t, now)
+
+	pruned, err := Prune(storage, &v1.ReleaseRetention{KeepLast: 1}, now, true)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{2, 3, 4, 5}, pruned)
+	assert.Len(t, storage.GetRevisions(), 6)
+
+	pruned, err = Prune(storage, &v1.ReleaseRetention{KeepLast: 1}, now, false)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{2, 3, 4, 5}, pruned)
+	assert.Equal(t, []uint64{1, 6}, storage.GetRevisions())
+
+	// The latest release in progress.
+	latest, err := storage.Get(6)
+	require.NoError(t, err)
+	latest.Phase = v1.ReleasePhaseApplying
+	require.NoError(t, storage.Update(latest))
+	_, err = Prune(storage, &v1.ReleaseRetention{KeepLast: 1}, now, false)
+	assert.ErrorIs(t, err, ErrReleaseInProgress)
+
+	// The storage not implementing Pruner.
+	_, err = Prune(struct{ Storage }{storage}, &v1.ReleaseRetention{KeepLast: 1}, now, false)
+	assert.ErrorIs(t, err, ErrPruneNotSupported)
+}
+
+func TestRetentionFromWorkspace(t *testing.T) {
+	retention, err := RetentionFromWorkspace(&v1.Workspace{Name: "dev"})
+	require.NoError(t, err)
+	assert.Nil(t, retention)
+
+	retention, err = RetentionFromWorkspace(&v1.Workspace{Name: "dev", Context: v1.GenericConfig{
+		v1.ReleaseRetentionKey: map[string]any{"keepLast": 10, "keepWithin": "720h"},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, &v1.ReleaseRetention{KeepLast: 10, KeepWithin: "720h"}, retention)
+
+	_, err = RetentionFromWorkspace(&v1.Workspace{Name: "dev", Context: v1.GenericConfig{
+		v1.ReleaseRetentionKey: map[string]any{"keepLast": -1},
+	}})
+	assert.Error(t, err)
+}
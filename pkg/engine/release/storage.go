@@ -25,3 +25,32 @@ type Storage interface {
 	// Update updates an existing Release in the Storage.
 	Update(release *v1.Release) error
 }
+
+// Summarizer is implemented by the Storages which keep the summaries of the Releases in the metadata, so
+// that the Releases can be listed without reading each of them.
+type Summarizer interface {
+	// GetSummary returns the summary of the Release of the Revision, nil if not kept, such as the Releases
+	// written before the summaries are kept.
+	GetSummary(revision uint64) *v1.ReleaseSummary
+}
+
+// Pruner is implemented by the Storages which support deleting Releases.
+type Pruner interface {
+	// Delete deletes the Releases of the Revisions, and compacts the metadata of the Releases. The latest
+	// Release cannot be deleted, and only the metadata gets compacted if no Revision is specified.
+	Delete(revisions ...uint64) error
+}
+
+// Unlocker is implemented by the Storages which lock the metadata of the Releases while updating it.
+type Unlocker interface {
+	// ForceUnlock releases the lock of the metadata of the Releases whoever holds it, which is used to release
+	// the lock left by a crashed process.
+	ForceUnlock() error
+}
+
+// Rotator is implemented by the Storages encrypting the Release files, so that the files already encrypted with
+// the current key are not rewritten when rotating the key.
+type Rotator interface {
+	// NeedsRotation reports whether the Release file of the Revision is not encrypted with the current key.
+	NeedsRotation(revision uint64) (bool, error)
+}
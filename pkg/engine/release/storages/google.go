@@ -2,14 +2,19 @@ package storages
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
 
 	"gopkg.in/yaml.v3"
 
 	googlestorage "cloud.google.com/go/storage"
+	"google.golang.org/api/googleapi"
 
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/infra/encryption"
+	"kusionstack.io/kusion/pkg/infra/objectstore"
 )
 
 // GoogleStorage is an implementation of release.Storage which uses google cloud as storage.
@@ -20,6 +25,9 @@ type GoogleStorage struct {
 	prefix string
 
 	meta *releasesMetaData
+
+	// encrypter encrypts the release files, nil means the encryption is not enabled.
+	encrypter *encryption.Encrypter
 }
 
 // NewGoogleStorage news google cloud release storage, and derives metadata.
@@ -35,6 +43,24 @@ func NewGoogleStorage(bucket *googlestorage.BucketHandle, prefix string) (*Googl
 }
 
 func (s *GoogleStorage) Get(revision uint64) (*v1.Release, error) {
+	content, err := s.readRelease(revision)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRelease(s.encrypter, content)
+}
+
+// NeedsRotation reports whether the release file of the revision is not encrypted with the current key.
+func (s *GoogleStorage) NeedsRotation(revision uint64) (bool, error) {
+	content, err := s.readRelease(revision)
+	if err != nil {
+		return false, err
+	}
+	return s.encrypter.FileNeedsRotation(content), nil
+}
+
+// readRelease reads the content of the release file of the revision.
+func (s *GoogleStorage) readRelease(revision uint64) ([]byte, error) {
 	ctx := context.Background()
 	if !checkRevisionExistence(s.meta, revision) {
 		return nil, ErrReleaseNotExist
@@ -50,12 +76,7 @@ func (s *GoogleStorage) Get(revision uint64) (*v1.Release, error) {
 	if err != nil {
 		return nil, fmt.Errorf("read release failed: %w", err)
 	}
-
-	rel := &v1.Release{}
-	if err = yaml.Unmarshal(content, rel); err != nil {
-		return nil, fmt.Errorf("yaml unmarshal release failed: %w", err)
-	}
-	return rel, nil
+	return content, nil
 }
 
 func (s *GoogleStorage) GetRevisions() []uint64 {
@@ -70,25 +91,85 @@ func (s *GoogleStorage) GetLatestRevision() uint64 {
 	return s.meta.LatestRevision
 }
 
+func (s *GoogleStorage) GetSummary(revision uint64) *v1.ReleaseSummary {
+	return getSummary(s.meta, revision)
+}
+
+// SetEncrypter enables the client-side encryption of the release files with the encrypter. The release
+// files written before are still readable, and get encrypted when updated.
+func (s *GoogleStorage) SetEncrypter(encrypter *encryption.Encrypter) {
+	s.encrypter = encrypter
+}
+
 func (s *GoogleStorage) Create(r *v1.Release) error {
-	if checkRevisionExistence(s.meta, r.Revision) {
-		return ErrReleaseAlreadyExist
-	}
+	// Lock the metadata, and read the latest one, so that the concurrent writes do not overwrite each other.
+	return withMetaLock(s, func() error {
+		if err := s.readMeta(); err != nil {
+			return err
+		}
+		if checkRevisionExistence(s.meta, r.Revision) {
+			return ErrReleaseAlreadyExist
+		}
 
-	if err := s.writeRelease(r); err != nil {
-		return err
-	}
+		if err := s.writeRelease(r); err != nil {
+			return err
+		}
 
-	addLatestReleaseMetaData(s.meta, r.Revision, r.Stack)
-	return s.writeMeta()
+		addLatestReleaseMetaData(s.meta, r.Revision, r.Stack)
+		setReleaseSummary(s.meta, r)
+		return s.writeMeta()
+	})
 }
 
 func (s *GoogleStorage) Update(r *v1.Release) error {
-	if !checkRevisionExistence(s.meta, r.Revision) {
-		return ErrReleaseNotExist
-	}
+	return withMetaLock(s, func() error {
+		if err := s.readMeta(); err != nil {
+			return err
+		}
+		if !checkRevisionExistence(s.meta, r.Revision) {
+			return ErrReleaseNotExist
+		}
+
+		if err := s.writeRelease(r); err != nil {
+			return err
+		}
+
+		setReleaseSummary(s.meta, r)
+		return s.writeMeta()
+	})
+}
+
+// Delete deletes the releases of the revisions, and compacts the metadata. The latest release cannot be
+// deleted, and only the metadata gets compacted if no revision is specified.
+func (s *GoogleStorage) Delete(revisions ...uint64) error {
+	return withMetaLock(s, func() error {
+		if err := s.readMeta(); err != nil {
+			return err
+		}
+		if err := checkReleasesDeletable(s.meta, revisions); err != nil {
+			return err
+		}
 
-	return s.writeRelease(r)
+		compactMetaData(s.meta, revisions)
+		if err := s.writeMeta(); err != nil {
+			return err
+		}
+		for _, revision := range revisions {
+			err := s.bucket.Object(fmt.Sprintf("%s/%d%s", s.prefix, revision, yamlSuffix)).Delete(context.Background())
+			if err != nil && err != googlestorage.ErrObjectNotExist {
+				return fmt.Errorf("delete release from google failed: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// ForceUnlock deletes the lock file of the releases metadata, which is left by a crashed process.
+func (s *GoogleStorage) ForceUnlock() error {
+	if err := s.deleteLock(); err != nil {
+		return fmt.Errorf("delete lock of releases metadata failed: %w", err)
+	}
+	return nil
 }
 
 func (s *GoogleStorage) readMeta() error {
@@ -141,9 +222,9 @@ func (s *GoogleStorage) writeMeta() error {
 }
 
 func (s *GoogleStorage) writeRelease(r *v1.Release) error {
-	content, err := yaml.Marshal(r)
+	content, err := encodeRelease(s.encrypter, r)
 	if err != nil {
-		return fmt.Errorf("yaml marshal release failed: %w", err)
+		return err
 	}
 
 	obj := s.bucket.Object(fmt.Sprintf("%s/%d%s", s.prefix, r.Revision, yamlSuffix))
@@ -157,3 +238,41 @@ func (s *GoogleStorage) writeRelease(r *v1.Release) error {
 	}
 	return nil
 }
+
+func (s *GoogleStorage) createLock(content []byte) error {
+	// Only create the lock object if it does not exist.
+	obj := s.bucket.Object(s.prefix + "/" + metadataLockFile).If(googlestorage.Conditions{DoesNotExist: true})
+	writer := obj.NewWriter(context.Background())
+	if _, err := writer.Write(content); err != nil {
+		_ = writer.Close()
+		return fmt.Errorf("write releases metadata lock failed: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		var apiErr *googleapi.Error
+		if errors.As(err, &apiErr) && apiErr.Code == http.StatusPreconditionFailed {
+			return objectstore.ErrLocked
+		}
+		return fmt.Errorf("close writer failed: %w", err)
+	}
+	return nil
+}
+
+func (s *GoogleStorage) readLock() ([]byte, error) {
+	reader, err := s.bucket.Object(s.prefix + "/" + metadataLockFile).NewReader(context.Background())
+	if err != nil {
+		if err == googlestorage.ErrObjectNotExist {
+			return nil, objectstore.ErrNotFound
+		}
+		return nil, fmt.Errorf("get releases metadata lock from google failed: %w", err)
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+func (s *GoogleStorage) deleteLock() error {
+	err := s.bucket.Object(s.prefix + "/" + metadataLockFile).Delete(context.Background())
+	if err != nil && err != googlestorage.ErrObjectNotExist {
+		return err
+	}
+	return nil
+}
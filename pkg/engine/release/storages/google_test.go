@@ -50,6 +50,15 @@ func mockGoogleStorage() *GoogleStorage {
 	}
 }
 
+func mockGoogleStorageLock() {
+	mockey.Mock((*GoogleStorage).createLock).Return(nil).Build()
+	mockey.Mock((*GoogleStorage).deleteLock).Return(nil).Build()
+}
+
+func mockGoogleStorageReadMeta() {
+	mockey.Mock((*GoogleStorage).readMeta).Return(nil).Build()
+}
+
 func mockGoogleStorageWriteMeta() {
 	mockey.Mock((*GoogleStorage).writeMeta).Return(nil).Build()
 }
@@ -177,6 +186,8 @@ func TestGoogleStorage_Create(t *testing.T) {
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
 			mockey.PatchConvey("mock google storage operation", t, func() {
+				mockGoogleStorageLock()
+				mockGoogleStorageReadMeta()
 				mockGoogleStorageWriteMeta()
 				mockGoogleStorageWriteRelease()
 				err := mockGoogleStorage().Create(tc.r)
@@ -208,6 +219,9 @@ func TestGoogleStorage_Update(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			mockey.PatchConvey("mock google storage operation", t, func() {
 				mockGoogleStorageWriteRelease()
+				mockGoogleStorageLock()
+				mockGoogleStorageReadMeta()
+				mockGoogleStorageWriteMeta()
 				err := mockGoogleStorage().Update(tc.r)
 				assert.Equal(t, tc.success, err == nil)
 			})
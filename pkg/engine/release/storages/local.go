@@ -8,6 +8,9 @@ import (
 	"gopkg.in/yaml.v3"
 
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/infra/encryption"
+	"kusionstack.io/kusion/pkg/infra/objectstore"
+	ioutil "kusionstack.io/kusion/pkg/util/io"
 )
 
 // LocalStorage is an implementation of release.Storage which uses local filesystem as storage.
@@ -16,6 +19,9 @@ type LocalStorage struct {
 	path string
 
 	meta *releasesMetaData
+
+	// encrypter encrypts the release files, nil means the encryption is not enabled.
+	encrypter *encryption.Encrypter
 }
 
 // NewLocalStorage news local release storage, and derives metadata.
@@ -35,6 +41,24 @@ func NewLocalStorage(path string) (*LocalStorage, error) {
 }
 
 func (s *LocalStorage) Get(revision uint64) (*v1.Release, error) {
+	content, err := s.readRelease(revision)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRelease(s.encrypter, content)
+}
+
+// NeedsRotation reports whether the release file of the revision is not encrypted with the current key.
+func (s *LocalStorage) NeedsRotation(revision uint64) (bool, error) {
+	content, err := s.readRelease(revision)
+	if err != nil {
+		return false, err
+	}
+	return s.encrypter.FileNeedsRotation(content), nil
+}
+
+// readRelease reads the content of the release file of the revision.
+func (s *LocalStorage) readRelease(revision uint64) ([]byte, error) {
 	if !checkRevisionExistence(s.meta, revision) {
 		return nil, ErrReleaseNotExist
 	}
@@ -43,12 +67,7 @@ func (s *LocalStorage) Get(revision uint64) (*v1.Release, error) {
 	if err != nil {
 		return nil, fmt.Errorf("read release file failed: %w", err)
 	}
-
-	r := &v1.Release{}
-	if err = yaml.Unmarshal(content, r); err != nil {
-		return nil, fmt.Errorf("yaml unmarshal release failed: %w", err)
-	}
-	return r, nil
+	return content, nil
 }
 
 func (s *LocalStorage) GetRevisions() []uint64 {
@@ -63,25 +82,86 @@ func (s *LocalStorage) GetLatestRevision() uint64 {
 	return s.meta.LatestRevision
 }
 
-func (s *LocalStorage) Create(r *v1.Release) error {
-	if checkRevisionExistence(s.meta, r.Revision) {
-		return ErrReleaseAlreadyExist
-	}
+func (s *LocalStorage) GetSummary(revision uint64) *v1.ReleaseSummary {
+	return getSummary(s.meta, revision)
+}
 
-	if err := s.writeRelease(r); err != nil {
-		return err
-	}
+// SetEncrypter enables the client-side encryption of the release files with the encrypter. The release
+// files written before are still readable, and get encrypted when updated.
+func (s *LocalStorage) SetEncrypter(encrypter *encryption.Encrypter) {
+	s.encrypter = encrypter
+}
 
-	addLatestReleaseMetaData(s.meta, r.Revision, r.Stack)
-	return s.writeMeta()
+func (s *LocalStorage) Create(r *v1.Release) error {
+	// Lock the metadata, and read the latest one, so that the concurrent writes do not overwrite each other.
+	return withMetaLock(s, func() error {
+		if err := s.readMeta(); err != nil {
+			return err
+		}
+		if checkRevisionExistence(s.meta, r.Revision) {
+			return ErrReleaseAlreadyExist
+		}
+
+		if err := s.writeRelease(r); err != nil {
+			return err
+		}
+
+		addLatestReleaseMetaData(s.meta, r.Revision, r.Stack)
+		setReleaseSummary(s.meta, r)
+		return s.writeMeta()
+	})
 }
 
 func (s *LocalStorage) Update(r *v1.Release) error {
-	if !checkRevisionExistence(s.meta, r.Revision) {
-		return ErrReleaseNotExist
-	}
+	return withMetaLock(s, func() error {
+		if err := s.readMeta(); err != nil {
+			return err
+		}
+		if !checkRevisionExistence(s.meta, r.Revision) {
+			return ErrReleaseNotExist
+		}
+
+		if err := s.writeRelease(r); err != nil {
+			return err
+		}
+
+		setReleaseSummary(s.meta, r)
+		return s.writeMeta()
+	})
+}
 
-	return s.writeRelease(r)
+// Delete deletes the releases of the revisions, and compacts the metadata. The latest release cannot be
+// deleted, and only the metadata gets compacted if no revision is specified.
+func (s *LocalStorage) Delete(revisions ...uint64) error {
+	return withMetaLock(s, func() error {
+		if err := s.readMeta(); err != nil {
+			return err
+		}
+		if err := checkReleasesDeletable(s.meta, revisions); err != nil {
+			return err
+		}
+
+		// Update the metadata first, so that the releases are invisible even if failing to delete the files.
+		compactMetaData(s.meta, revisions)
+		if err := s.writeMeta(); err != nil {
+			return err
+		}
+		for _, revision := range revisions {
+			err := os.Remove(filepath.Join(s.path, fmt.Sprintf("%d%s", revision, yamlSuffix)))
+			if err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("delete release file failed: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// ForceUnlock deletes the lock file of the releases metadata, which is left by a crashed process.
+func (s *LocalStorage) ForceUnlock() error {
+	if err := s.deleteLock(); err != nil {
+		return fmt.Errorf("delete lock of releases metadata failed: %w", err)
+	}
+	return nil
 }
 
 func (s *LocalStorage) readMeta() error {
@@ -107,20 +187,50 @@ func (s *LocalStorage) writeMeta() error {
 		return fmt.Errorf("yaml marshal releases metadata failed: %w", err)
 	}
 
-	if err = os.WriteFile(filepath.Join(s.path, metadataFile), content, os.ModePerm); err != nil {
+	if err = ioutil.WriteFileAtomic(filepath.Join(s.path, metadataFile), content, 0o644); err != nil {
 		return fmt.Errorf("write releases metadata file failed: %w", err)
 	}
 	return nil
 }
 
 func (s *LocalStorage) writeRelease(r *v1.Release) error {
-	content, err := yaml.Marshal(r)
+	content, err := encodeRelease(s.encrypter, r)
 	if err != nil {
-		return fmt.Errorf("yaml marshal release failed: %w", err)
+		return err
 	}
 
-	if err = os.WriteFile(filepath.Join(s.path, fmt.Sprintf("%d%s", r.Revision, yamlSuffix)), content, os.ModePerm); err != nil {
+	if err = ioutil.WriteFileAtomic(filepath.Join(s.path, fmt.Sprintf("%d%s", r.Revision, yamlSuffix)), content, 0o644); err != nil {
 		return fmt.Errorf("write release file failed: %w", err)
 	}
 	return nil
 }
+
+func (s *LocalStorage) createLock(content []byte) error {
+	f, err := os.OpenFile(filepath.Join(s.path, metadataLockFile), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if os.IsExist(err) {
+		return objectstore.ErrLocked
+	} else if err != nil {
+		return fmt.Errorf("create releases metadata lock file failed: %w", err)
+	}
+	defer f.Close()
+	if _, err = f.Write(content); err != nil {
+		return fmt.Errorf("write releases metadata lock file failed: %w", err)
+	}
+	return nil
+}
+
+func (s *LocalStorage) readLock() ([]byte, error) {
+	content, err := os.ReadFile(filepath.Join(s.path, metadataLockFile))
+	if os.IsNotExist(err) {
+		return nil, objectstore.ErrNotFound
+	}
+	return content, err
+}
+
+func (s *LocalStorage) deleteLock() error {
+	err := os.Remove(filepath.Join(s.path, metadataLockFile))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
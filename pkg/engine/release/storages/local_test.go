@@ -1,6 +1,8 @@
 package storages
 
 import (
+	"crypto/rand"
+	"encoding/base64"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,6 +13,7 @@ import (
 	"gopkg.in/yaml.v3"
 
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/infra/encryption"
 )
 
 func testDataFolder(releasePath string) string {
@@ -18,6 +21,20 @@ func testDataFolder(releasePath string) string {
 	return filepath.Join(pwd, "testdata", releasePath, "test_project", "test_ws")
 }
 
+// copyTestDataFolder copies the test data folder to a temporary directory, which is used by the tests
+// writing the releases.
+func copyTestDataFolder(t *testing.T, releasePath string) string {
+	dir := t.TempDir()
+	entries, err := os.ReadDir(testDataFolder(releasePath))
+	assert.NoError(t, err)
+	for _, entry := range entries {
+		content, err := os.ReadFile(filepath.Join(testDataFolder(releasePath), entry.Name()))
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, entry.Name()), content, 0o644))
+	}
+	return dir
+}
+
 func mockRelease(revision uint64) *v1.Release {
 	loc, _ := time.LoadLocation("Asia/Shanghai")
 	return &v1.Release{
@@ -395,10 +412,102 @@ func TestLocalStorage_Update(t *testing.T) {
 
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
-			s, err := NewLocalStorage(testDataFolder("releases"))
+			path := copyTestDataFolder(t, "releases")
+			s, err := NewLocalStorage(path)
 			assert.NoError(t, err)
 			err = s.Update(mockRelease(tc.revision))
 			assert.Equal(t, tc.success, err == nil)
+			if tc.success {
+				s, err = NewLocalStorage(path)
+				assert.NoError(t, err)
+				summary := s.GetSummary(tc.revision)
+				assert.Equal(t, tc.revision, summary.Revision)
+				assert.Equal(t, v1.ReleasePhaseSucceeded, summary.Phase)
+				assert.True(t, mockRelease(tc.revision).CreateTime.Equal(summary.CreateTime))
+			}
 		})
 	}
 }
+
+func TestLocalStorage_Encryption(t *testing.T) {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	t.Setenv(encryption.DefaultKeyEnvVar, base64.StdEncoding.EncodeToString(key))
+	provider, err := encryption.NewKeyProvider(encryption.EnvKeyProviderName, nil)
+	assert.NoError(t, err)
+	encrypter := encryption.NewEncrypter(provider)
+
+	path := t.TempDir()
+	s, err := NewLocalStorage(path)
+	assert.NoError(t, err)
+	assert.NoError(t, s.Create(mockRelease(1)))
+
+	// The release written before the encryption is enabled is still readable.
+	s.SetEncrypter(encrypter)
+	r, err := s.Get(1)
+	assert.NoError(t, err)
+	assert.Equal(t, mockRelease(1).Spec.Resources, r.Spec.Resources)
+
+	assert.NoError(t, s.Update(r))
+	assert.NoError(t, s.Create(mockRelease(2)))
+	for _, revision := range []uint64{1, 2} {
+		content, err := os.ReadFile(filepath.Join(path, fmt.Sprintf("%d%s", revision, yamlSuffix)))
+		assert.NoError(t, err)
+		assert.NotContains(t, string(content), "PodTransitionRule")
+		assert.False(t, encrypter.FileNeedsRotation(content))
+
+		r, err = s.Get(revision)
+		assert.NoError(t, err)
+		assert.Equal(t, revision, r.Revision)
+		assert.Equal(t, mockRelease(revision).Spec.Resources, r.Spec.Resources)
+	}
+
+	// The encrypted releases are not readable without the key.
+	s, err = NewLocalStorage(path)
+	assert.NoError(t, err)
+	_, err = s.Get(1)
+	assert.ErrorIs(t, err, encryption.ErrEncryptionNotEnabled)
+}
+
+func TestLocalStorage_Delete(t *testing.T) {
+	path := t.TempDir()
+	s, err := NewLocalStorage(path)
+	assert.NoError(t, err)
+	for revision := uint64(1); revision <= 3; revision++ {
+		assert.NoError(t, s.Create(mockRelease(revision)))
+	}
+
+	assert.ErrorIs(t, s.Delete(1, 3), ErrDeleteLatestRelease)
+	assert.Equal(t, []uint64{1, 2, 3}, s.GetRevisions())
+
+	assert.NoError(t, s.Delete(1, 2))
+	_, err = os.Stat(filepath.Join(path, "1"+yamlSuffix))
+	assert.True(t, os.IsNotExist(err))
+
+	// The metadata is persisted.
+	s, err = NewLocalStorage(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{3}, s.GetRevisions())
+	assert.Equal(t, uint64(3), s.GetLatestRevision())
+	_, err = s.Get(1)
+	assert.ErrorIs(t, err, ErrReleaseNotExist)
+}
+
+func TestLocalStorage_UpdateConcurrently(t *testing.T) {
+	path := t.TempDir()
+	s, err := NewLocalStorage(path)
+	assert.NoError(t, err)
+	assert.NoError(t, s.Create(mockRelease(1)))
+
+	// Another storage on the same path creates a release after s reads the metadata.
+	other, err := NewLocalStorage(path)
+	assert.NoError(t, err)
+	assert.NoError(t, other.Create(mockRelease(2)))
+
+	// Updating the stale storage keeps the release created by the other one.
+	assert.NoError(t, s.Update(mockRelease(1)))
+	s, err = NewLocalStorage(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2}, s.GetRevisions())
+	assert.Equal(t, uint64(2), s.GetLatestRevision())
+}
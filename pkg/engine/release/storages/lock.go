@@ -0,0 +1,84 @@
+package storages
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"kusionstack.io/kusion/pkg/infra/objectstore"
+)
+
+// metadataLockFile is the lock file of the releases metadata, for the storages whose backend has no native
+// locking. It's created exclusively, and holds the time it's created at, so that the lock left by a crashed
+// process expires.
+const metadataLockFile = metadataFile + ".lock"
+
+var (
+	// metaLockTimeout is how long to wait for the lock of the metadata held by others.
+	metaLockTimeout = 30 * time.Second
+	// metaLockRetryInterval is the interval to retry locking the metadata.
+	metaLockRetryInterval = 200 * time.Millisecond
+	// metaLockExpiration is the age after which the lock of the metadata is considered left by a crashed
+	// process, which is far longer than any read-modify-write of the metadata.
+	metaLockExpiration = 5 * time.Minute
+)
+
+// metaLocker is implemented by the storages locking the metadata with the lock file.
+type metaLocker interface {
+	// createLock creates the lock file with the content, or returns objectstore.ErrLocked if it exists.
+	createLock(content []byte) error
+	// readLock returns the content of the lock file, or objectstore.ErrNotFound if it does not exist.
+	readLock() ([]byte, error)
+	// deleteLock deletes the lock file, it's not an error if the lock file does not exist.
+	deleteLock() error
+}
+
+// withMetaLock runs fn with the metadata locked, so that the read-modify-write of the metadata by the
+// concurrent processes do not overwrite each other. It waits for the lock held by others until
+// metaLockTimeout, and breaks the lock expired.
+func withMetaLock(locker metaLocker, fn func() error) (err error) {
+	deadline := time.Now().Add(metaLockTimeout)
+	for {
+		err = locker.createLock([]byte(time.Now().UTC().Format(time.RFC3339Nano)))
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, objectstore.ErrLocked) {
+			return fmt.Errorf("lock releases metadata failed: %w", err)
+		}
+		if metaLockExpired(locker) {
+			if err = locker.deleteLock(); err != nil {
+				return fmt.Errorf("delete expired lock of releases metadata failed: %w", err)
+			}
+			continue
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%w: releases metadata is locked by others, delete %s if it's left by a crashed process",
+				objectstore.ErrLocked, metadataLockFile)
+		}
+		time.Sleep(metaLockRetryInterval)
+	}
+
+	defer func() {
+		if unlockErr := locker.deleteLock(); unlockErr != nil && err == nil {
+			err = fmt.Errorf("unlock releases metadata failed: %w", unlockErr)
+		}
+	}()
+	return fn()
+}
+
+// metaLockExpired returns true if the lock file has been deleted, or is older than metaLockExpiration. The
+// lock file of unknown content is never considered expired.
+func metaLockExpired(locker metaLocker) bool {
+	content, err := locker.readLock()
+	if errors.Is(err, objectstore.ErrNotFound) {
+		return true
+	} else if err != nil {
+		return false
+	}
+	lockedAt, err := time.Parse(time.RFC3339Nano, string(content))
+	if err != nil {
+		return false
+	}
+	return time.Since(lockedAt) > metaLockExpiration
+}
@@ -0,0 +1,56 @@
+package storages
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"kusionstack.io/kusion/pkg/infra/objectstore"
+)
+
+func TestWithMetaLock(t *testing.T) {
+	timeout, interval := metaLockTimeout, metaLockRetryInterval
+	metaLockTimeout, metaLockRetryInterval = 100*time.Millisecond, 10*time.Millisecond
+	t.Cleanup(func() {
+		metaLockTimeout, metaLockRetryInterval = timeout, interval
+	})
+
+	t.Run("lock and unlock", func(t *testing.T) {
+		path := t.TempDir()
+		s, err := NewLocalStorage(path)
+		assert.NoError(t, err)
+		assert.NoError(t, withMetaLock(s, func() error {
+			_, err := os.Stat(filepath.Join(path, metadataLockFile))
+			return err
+		}))
+		_, err = os.Stat(filepath.Join(path, metadataLockFile))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("locked by others", func(t *testing.T) {
+		s, err := NewLocalStorage(t.TempDir())
+		assert.NoError(t, err)
+		assert.NoError(t, s.createLock([]byte(time.Now().UTC().Format(time.RFC3339Nano))))
+		assert.ErrorIs(t, s.Create(mockRelease(1)), objectstore.ErrLocked)
+		assert.Empty(t, s.GetRevisions())
+
+		// The lock of others is kept until it's released by force.
+		_, err = s.readLock()
+		assert.NoError(t, err)
+		assert.NoError(t, s.ForceUnlock())
+		assert.NoError(t, s.Create(mockRelease(1)))
+	})
+
+	t.Run("break expired lock", func(t *testing.T) {
+		s, err := NewLocalStorage(t.TempDir())
+		assert.NoError(t, err)
+		assert.NoError(t, s.createLock([]byte(time.Now().Add(-2*metaLockExpiration).UTC().Format(time.RFC3339Nano))))
+		assert.NoError(t, s.Create(mockRelease(1)))
+		assert.Equal(t, []uint64{1}, s.GetRevisions())
+		_, err = s.readLock()
+		assert.ErrorIs(t, err, objectstore.ErrNotFound)
+	})
+}
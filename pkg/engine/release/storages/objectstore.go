@@ -0,0 +1,209 @@
+package storages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/infra/encryption"
+	"kusionstack.io/kusion/pkg/infra/objectstore"
+)
+
+// ObjectStoreStorage is an implementation of release.Storage which uses a generic object store as storage,
+// such as the Azure Blob Storage, a HTTP server or a Kubernetes cluster.
+type ObjectStoreStorage struct {
+	store objectstore.Store
+
+	// The prefix to store the release files.
+	prefix string
+
+	meta *releasesMetaData
+
+	// encrypter encrypts the release files, nil means the encryption is not enabled.
+	encrypter *encryption.Encrypter
+}
+
+// NewObjectStoreStorage news object store release storage, and derives metadata.
+func NewObjectStoreStorage(store objectstore.Store, prefix string) (*ObjectStoreStorage, error) {
+	s := &ObjectStoreStorage{
+		store:  store,
+		prefix: prefix,
+	}
+	if err := s.readMeta(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *ObjectStoreStorage) Get(revision uint64) (*v1.Release, error) {
+	content, err := s.readRelease(revision)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRelease(s.encrypter, content)
+}
+
+// NeedsRotation reports whether the release file of the revision is not encrypted with the current key.
+func (s *ObjectStoreStorage) NeedsRotation(revision uint64) (bool, error) {
+	content, err := s.readRelease(revision)
+	if err != nil {
+		return false, err
+	}
+	return s.encrypter.FileNeedsRotation(content), nil
+}
+
+// readRelease reads the content of the release file of the revision.
+func (s *ObjectStoreStorage) readRelease(revision uint64) ([]byte, error) {
+	if !checkRevisionExistence(s.meta, revision) {
+		return nil, ErrReleaseNotExist
+	}
+
+	content, err := s.store.Get(context.Background(), fmt.Sprintf("%s/%d%s", s.prefix, revision, yamlSuffix))
+	if err != nil {
+		return nil, fmt.Errorf("get release from object store failed: %w", err)
+	}
+	return content, nil
+}
+
+func (s *ObjectStoreStorage) GetRevisions() []uint64 {
+	return getRevisions(s.meta)
+}
+
+func (s *ObjectStoreStorage) GetStackBoundRevisions(stack string) []uint64 {
+	return getStackBoundRevisions(s.meta, stack)
+}
+
+func (s *ObjectStoreStorage) GetLatestRevision() uint64 {
+	return s.meta.LatestRevision
+}
+
+func (s *ObjectStoreStorage) GetSummary(revision uint64) *v1.ReleaseSummary {
+	return getSummary(s.meta, revision)
+}
+
+// SetEncrypter enables the client-side encryption of the release files with the encrypter. The release
+// files written before are still readable, and get encrypted when updated.
+func (s *ObjectStoreStorage) SetEncrypter(encrypter *encryption.Encrypter) {
+	s.encrypter = encrypter
+}
+
+func (s *ObjectStoreStorage) Create(r *v1.Release) error {
+	// Lock the metadata if supported, and read the latest one, so that the concurrent creations do not
+	// overwrite each other.
+	return objectstore.WithLock(context.Background(), s.store, s.prefix+"/"+metadataFile, func() error {
+		if err := s.readMeta(); err != nil {
+			return err
+		}
+		if checkRevisionExistence(s.meta, r.Revision) {
+			return ErrReleaseAlreadyExist
+		}
+
+		if err := s.writeRelease(r); err != nil {
+			return err
+		}
+
+		addLatestReleaseMetaData(s.meta, r.Revision, r.Stack)
+		setReleaseSummary(s.meta, r)
+		return s.writeMeta()
+	})
+}
+
+func (s *ObjectStoreStorage) Update(r *v1.Release) error {
+	return objectstore.WithLock(context.Background(), s.store, s.prefix+"/"+metadataFile, func() error {
+		if err := s.readMeta(); err != nil {
+			return err
+		}
+		if !checkRevisionExistence(s.meta, r.Revision) {
+			return ErrReleaseNotExist
+		}
+
+		if err := s.writeRelease(r); err != nil {
+			return err
+		}
+
+		setReleaseSummary(s.meta, r)
+		return s.writeMeta()
+	})
+}
+
+// Delete deletes the releases of the revisions, and compacts the metadata. The latest release cannot be
+// deleted, and only the metadata gets compacted if no revision is specified.
+func (s *ObjectStoreStorage) Delete(revisions ...uint64) error {
+	return objectstore.WithLock(context.Background(), s.store, s.prefix+"/"+metadataFile, func() error {
+		if err := s.readMeta(); err != nil {
+			return err
+		}
+		if err := checkReleasesDeletable(s.meta, revisions); err != nil {
+			return err
+		}
+
+		compactMetaData(s.meta, revisions)
+		if err := s.writeMeta(); err != nil {
+			return err
+		}
+		for _, revision := range revisions {
+			err := s.store.Delete(context.Background(), fmt.Sprintf("%s/%d%s", s.prefix, revision, yamlSuffix))
+			if err != nil && !errors.Is(err, objectstore.ErrNotFound) {
+				return fmt.Errorf("delete release from object store failed: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// ForceUnlock releases the lock of the releases metadata whoever holds it, which is left by a crashed process.
+// It's a no-op if the store does not support locking.
+func (s *ObjectStoreStorage) ForceUnlock() error {
+	locker, ok := s.store.(objectstore.Locker)
+	if !ok {
+		return nil
+	}
+	if err := locker.ForceUnlock(context.Background(), s.prefix+"/"+metadataFile); err != nil {
+		return fmt.Errorf("unlock releases metadata failed: %w", err)
+	}
+	return nil
+}
+
+func (s *ObjectStoreStorage) readMeta() error {
+	content, err := s.store.Get(context.Background(), s.prefix+"/"+metadataFile)
+	if errors.Is(err, objectstore.ErrNotFound) {
+		s.meta = &releasesMetaData{}
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("get releases metadata from object store failed: %w", err)
+	}
+
+	meta := &releasesMetaData{}
+	if err = yaml.Unmarshal(content, meta); err != nil {
+		return fmt.Errorf("yaml unmarshal releases metadata failed: %w", err)
+	}
+	s.meta = meta
+	return nil
+}
+
+func (s *ObjectStoreStorage) writeMeta() error {
+	content, err := yaml.Marshal(s.meta)
+	if err != nil {
+		return fmt.Errorf("yaml marshal releases metadata failed: %w", err)
+	}
+
+	if err = s.store.Put(context.Background(), s.prefix+"/"+metadataFile, content); err != nil {
+		return fmt.Errorf("put releases metadata to object store failed: %w", err)
+	}
+	return nil
+}
+
+func (s *ObjectStoreStorage) writeRelease(r *v1.Release) error {
+	content, err := encodeRelease(s.encrypter, r)
+	if err != nil {
+		return err
+	}
+
+	if err = s.store.Put(context.Background(), fmt.Sprintf("%s/%d%s", s.prefix, r.Revision, yamlSuffix), content); err != nil {
+		return fmt.Errorf("put release to object store failed: %w", err)
+	}
+	return nil
+}
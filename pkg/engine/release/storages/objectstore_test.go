@@ -0,0 +1,65 @@
+package storages
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/infra/objectstore"
+)
+
+func TestObjectStoreStorageOperation(t *testing.T) {
+	store := objectstore.NewMemoryStore()
+	s, err := NewObjectStoreStorage(store, "releases/test_project/test_ws")
+	assert.NoError(t, err)
+	assert.Empty(t, s.GetRevisions())
+
+	assert.NoError(t, s.Create(mockRelease(1)))
+	assert.NoError(t, s.Create(mockRelease(2)))
+	assert.ErrorIs(t, s.Create(mockRelease(2)), ErrReleaseAlreadyExist)
+	assert.ErrorIs(t, s.Update(mockRelease(3)), ErrReleaseNotExist)
+
+	// The metadata is persisted in the store.
+	s, err = NewObjectStoreStorage(store, "releases/test_project/test_ws")
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2}, s.GetRevisions())
+	assert.Equal(t, []uint64{1, 2}, s.GetStackBoundRevisions("test_stack"))
+	assert.Equal(t, uint64(2), s.GetLatestRevision())
+
+	r, err := s.Get(2)
+	assert.NoError(t, err)
+	assert.Equal(t, mockRelease(2).Spec.Resources, r.Spec.Resources)
+	assert.Equal(t, uint64(2), r.Revision)
+	_, err = s.Get(3)
+	assert.ErrorIs(t, err, ErrReleaseNotExist)
+
+	updated := mockRelease(2)
+	updated.Phase = v1.ReleasePhaseSucceeded
+	assert.NoError(t, s.Update(updated))
+	r, err = s.Get(2)
+	assert.NoError(t, err)
+	assert.Equal(t, updated.Phase, r.Phase)
+
+	assert.ErrorIs(t, s.Delete(2), ErrDeleteLatestRelease)
+	assert.NoError(t, s.Delete(1))
+	_, err = store.Get(context.Background(), "releases/test_project/test_ws/1.yaml")
+	assert.ErrorIs(t, err, objectstore.ErrNotFound)
+	s, err = NewObjectStoreStorage(store, "releases/test_project/test_ws")
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{2}, s.GetRevisions())
+}
+
+func TestObjectStoreStorageForceUnlock(t *testing.T) {
+	store := objectstore.NewMemoryStore()
+	s, err := NewObjectStoreStorage(store, "releases/test_project/test_ws")
+	assert.NoError(t, err)
+
+	// The lock left by a crashed process blocks the updates until it's released by force.
+	assert.NoError(t, store.Lock(context.Background(), "releases/test_project/test_ws/"+metadataFile, objectstore.NewLockInfo()))
+	assert.ErrorIs(t, s.Create(mockRelease(1)), objectstore.ErrLocked)
+	assert.NoError(t, s.ForceUnlock())
+	assert.NoError(t, s.Create(mockRelease(1)))
+	assert.Equal(t, []uint64{1}, s.GetRevisions())
+}
@@ -4,11 +4,14 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"net/http"
 
 	"github.com/aliyun/aliyun-oss-go-sdk/oss"
 	"gopkg.in/yaml.v3"
 
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/infra/encryption"
+	"kusionstack.io/kusion/pkg/infra/objectstore"
 )
 
 // OssStorage is an implementation of release.Storage which uses oss as storage.
@@ -19,6 +22,9 @@ type OssStorage struct {
 	prefix string
 
 	meta *releasesMetaData
+
+	// encrypter encrypts the release files, nil means the encryption is not enabled.
+	encrypter *encryption.Encrypter
 }
 
 // NewOssStorage news oss release storage, and derives metadata.
@@ -35,6 +41,24 @@ func NewOssStorage(bucket *oss.Bucket, prefix string) (*OssStorage, error) {
 }
 
 func (s *OssStorage) Get(revision uint64) (*v1.Release, error) {
+	content, err := s.readRelease(revision)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRelease(s.encrypter, content)
+}
+
+// NeedsRotation reports whether the release file of the revision is not encrypted with the current key.
+func (s *OssStorage) NeedsRotation(revision uint64) (bool, error) {
+	content, err := s.readRelease(revision)
+	if err != nil {
+		return false, err
+	}
+	return s.encrypter.FileNeedsRotation(content), nil
+}
+
+// readRelease reads the content of the release file of the revision.
+func (s *OssStorage) readRelease(revision uint64) ([]byte, error) {
 	if !checkRevisionExistence(s.meta, revision) {
 		return nil, ErrReleaseNotExist
 	}
@@ -50,12 +74,7 @@ func (s *OssStorage) Get(revision uint64) (*v1.Release, error) {
 	if err != nil {
 		return nil, fmt.Errorf("read release failed: %w", err)
 	}
-
-	r := &v1.Release{}
-	if err = yaml.Unmarshal(content, r); err != nil {
-		return nil, fmt.Errorf("yaml unmarshal release failed: %w", err)
-	}
-	return r, nil
+	return content, nil
 }
 
 func (s *OssStorage) GetRevisions() []uint64 {
@@ -70,25 +89,84 @@ func (s *OssStorage) GetLatestRevision() uint64 {
 	return s.meta.LatestRevision
 }
 
+func (s *OssStorage) GetSummary(revision uint64) *v1.ReleaseSummary {
+	return getSummary(s.meta, revision)
+}
+
+// SetEncrypter enables the client-side encryption of the release files with the encrypter. The release
+// files written before are still readable, and get encrypted when updated.
+func (s *OssStorage) SetEncrypter(encrypter *encryption.Encrypter) {
+	s.encrypter = encrypter
+}
+
 func (s *OssStorage) Create(r *v1.Release) error {
-	if checkRevisionExistence(s.meta, r.Revision) {
-		return ErrReleaseAlreadyExist
-	}
+	// Lock the metadata, and read the latest one, so that the concurrent writes do not overwrite each other.
+	return withMetaLock(s, func() error {
+		if err := s.readMeta(); err != nil {
+			return err
+		}
+		if checkRevisionExistence(s.meta, r.Revision) {
+			return ErrReleaseAlreadyExist
+		}
 
-	if err := s.writeRelease(r); err != nil {
-		return err
-	}
+		if err := s.writeRelease(r); err != nil {
+			return err
+		}
 
-	addLatestReleaseMetaData(s.meta, r.Revision, r.Stack)
-	return s.writeMeta()
+		addLatestReleaseMetaData(s.meta, r.Revision, r.Stack)
+		setReleaseSummary(s.meta, r)
+		return s.writeMeta()
+	})
 }
 
 func (s *OssStorage) Update(r *v1.Release) error {
-	if !checkRevisionExistence(s.meta, r.Revision) {
-		return ErrReleaseNotExist
-	}
+	return withMetaLock(s, func() error {
+		if err := s.readMeta(); err != nil {
+			return err
+		}
+		if !checkRevisionExistence(s.meta, r.Revision) {
+			return ErrReleaseNotExist
+		}
+
+		if err := s.writeRelease(r); err != nil {
+			return err
+		}
+
+		setReleaseSummary(s.meta, r)
+		return s.writeMeta()
+	})
+}
 
-	return s.writeRelease(r)
+// Delete deletes the releases of the revisions, and compacts the metadata. The latest release cannot be
+// deleted, and only the metadata gets compacted if no revision is specified.
+func (s *OssStorage) Delete(revisions ...uint64) error {
+	return withMetaLock(s, func() error {
+		if err := s.readMeta(); err != nil {
+			return err
+		}
+		if err := checkReleasesDeletable(s.meta, revisions); err != nil {
+			return err
+		}
+
+		compactMetaData(s.meta, revisions)
+		if err := s.writeMeta(); err != nil {
+			return err
+		}
+		for _, revision := range revisions {
+			if err := s.bucket.DeleteObject(fmt.Sprintf("%s/%d%s", s.prefix, revision, yamlSuffix)); err != nil {
+				return fmt.Errorf("delete release from oss failed: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// ForceUnlock deletes the lock file of the releases metadata, which is left by a crashed process.
+func (s *OssStorage) ForceUnlock() error {
+	if err := s.deleteLock(); err != nil {
+		return fmt.Errorf("delete lock of releases metadata failed: %w", err)
+	}
+	return nil
 }
 
 func (s *OssStorage) readMeta() error {
@@ -136,9 +214,9 @@ func (s *OssStorage) writeMeta() error {
 }
 
 func (s *OssStorage) writeRelease(r *v1.Release) error {
-	content, err := yaml.Marshal(r)
+	content, err := encodeRelease(s.encrypter, r)
 	if err != nil {
-		return fmt.Errorf("yaml marshal release failed: %w", err)
+		return err
 	}
 
 	key := fmt.Sprintf("%s/%d%s", s.prefix, r.Revision, yamlSuffix)
@@ -147,3 +225,35 @@ func (s *OssStorage) writeRelease(r *v1.Release) error {
 	}
 	return nil
 }
+
+func (s *OssStorage) createLock(content []byte) error {
+	// Only create the lock object if it does not exist.
+	err := s.bucket.PutObject(s.prefix+"/"+metadataLockFile, bytes.NewReader(content), oss.ForbidOverWrite(true))
+	if err != nil {
+		ossErr, ok := err.(oss.ServiceError)
+		if ok && ossErr.StatusCode == http.StatusConflict {
+			return objectstore.ErrLocked
+		}
+		return fmt.Errorf("put releases metadata lock to oss failed: %w", err)
+	}
+	return nil
+}
+
+func (s *OssStorage) readLock() ([]byte, error) {
+	body, err := s.bucket.GetObject(s.prefix + "/" + metadataLockFile)
+	if err != nil {
+		ossErr, ok := err.(oss.ServiceError)
+		if ok && ossErr.StatusCode == http.StatusNotFound {
+			return nil, objectstore.ErrNotFound
+		}
+		return nil, fmt.Errorf("get releases metadata lock from oss failed: %w", err)
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+	return io.ReadAll(body)
+}
+
+func (s *OssStorage) deleteLock() error {
+	return s.bucket.DeleteObject(s.prefix + "/" + metadataLockFile)
+}
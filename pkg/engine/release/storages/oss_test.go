@@ -17,6 +17,15 @@ func mockOssStorage() *OssStorage {
 	return &OssStorage{bucket: &oss.Bucket{}, meta: mockReleasesMeta()}
 }
 
+func mockOssStorageLock() {
+	mockey.Mock((*OssStorage).createLock).Return(nil).Build()
+	mockey.Mock((*OssStorage).deleteLock).Return(nil).Build()
+}
+
+func mockOssStorageReadMeta() {
+	mockey.Mock((*OssStorage).readMeta).Return(nil).Build()
+}
+
 func mockOssStorageWriteMeta() {
 	mockey.Mock((*OssStorage).writeMeta).Return(nil).Build()
 }
@@ -145,6 +154,8 @@ func TestOssStorage_Create(t *testing.T) {
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
 			mockey.PatchConvey("mock oss operation", t, func() {
+				mockOssStorageLock()
+				mockOssStorageReadMeta()
 				mockOssStorageWriteMeta()
 				mockOssStorageWriteRelease()
 				err := mockOssStorage().Create(tc.r)
@@ -176,6 +187,9 @@ func TestOssStorage_Update(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			mockey.PatchConvey("mock oss operation", t, func() {
 				mockOssStorageWriteRelease()
+				mockOssStorageLock()
+				mockOssStorageReadMeta()
+				mockOssStorageWriteMeta()
 				err := mockOssStorage().Update(tc.r)
 				assert.Equal(t, tc.success, err == nil)
 			})
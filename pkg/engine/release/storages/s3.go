@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"net/http"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -11,6 +12,8 @@ import (
 	"gopkg.in/yaml.v3"
 
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/infra/encryption"
+	"kusionstack.io/kusion/pkg/infra/objectstore"
 )
 
 // S3Storage is an implementation of release.Storage which uses s3 as storage.
@@ -22,6 +25,9 @@ type S3Storage struct {
 	prefix string
 
 	meta *releasesMetaData
+
+	// encrypter encrypts the release files, nil means the encryption is not enabled.
+	encrypter *encryption.Encrypter
 }
 
 // NewS3Storage news s3 release storage, and derives metadata.
@@ -38,6 +44,24 @@ func NewS3Storage(s3 *s3.S3, bucket, prefix string) (*S3Storage, error) {
 }
 
 func (s *S3Storage) Get(revision uint64) (*v1.Release, error) {
+	content, err := s.readRelease(revision)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRelease(s.encrypter, content)
+}
+
+// NeedsRotation reports whether the release file of the revision is not encrypted with the current key.
+func (s *S3Storage) NeedsRotation(revision uint64) (bool, error) {
+	content, err := s.readRelease(revision)
+	if err != nil {
+		return false, err
+	}
+	return s.encrypter.FileNeedsRotation(content), nil
+}
+
+// readRelease reads the content of the release file of the revision.
+func (s *S3Storage) readRelease(revision uint64) ([]byte, error) {
 	if !checkRevisionExistence(s.meta, revision) {
 		return nil, ErrReleaseNotExist
 	}
@@ -58,12 +82,7 @@ func (s *S3Storage) Get(revision uint64) (*v1.Release, error) {
 	if err != nil {
 		return nil, fmt.Errorf("read release failed: %w", err)
 	}
-
-	r := &v1.Release{}
-	if err = yaml.Unmarshal(content, r); err != nil {
-		return nil, fmt.Errorf("yaml unmarshal release failed: %w", err)
-	}
-	return r, nil
+	return content, nil
 }
 
 func (s *S3Storage) GetRevisions() []uint64 {
@@ -78,25 +97,88 @@ func (s *S3Storage) GetLatestRevision() uint64 {
 	return s.meta.LatestRevision
 }
 
+func (s *S3Storage) GetSummary(revision uint64) *v1.ReleaseSummary {
+	return getSummary(s.meta, revision)
+}
+
+// SetEncrypter enables the client-side encryption of the release files with the encrypter. The release
+// files written before are still readable, and get encrypted when updated.
+func (s *S3Storage) SetEncrypter(encrypter *encryption.Encrypter) {
+	s.encrypter = encrypter
+}
+
 func (s *S3Storage) Create(r *v1.Release) error {
-	if checkRevisionExistence(s.meta, r.Revision) {
-		return ErrReleaseAlreadyExist
-	}
+	// Lock the metadata, and read the latest one, so that the concurrent writes do not overwrite each other.
+	return withMetaLock(s, func() error {
+		if err := s.readMeta(); err != nil {
+			return err
+		}
+		if checkRevisionExistence(s.meta, r.Revision) {
+			return ErrReleaseAlreadyExist
+		}
 
-	if err := s.writeRelease(r); err != nil {
-		return err
-	}
+		if err := s.writeRelease(r); err != nil {
+			return err
+		}
 
-	addLatestReleaseMetaData(s.meta, r.Revision, r.Stack)
-	return s.writeMeta()
+		addLatestReleaseMetaData(s.meta, r.Revision, r.Stack)
+		setReleaseSummary(s.meta, r)
+		return s.writeMeta()
+	})
 }
 
 func (s *S3Storage) Update(r *v1.Release) error {
-	if !checkRevisionExistence(s.meta, r.Revision) {
-		return ErrReleaseNotExist
-	}
+	return withMetaLock(s, func() error {
+		if err := s.readMeta(); err != nil {
+			return err
+		}
+		if !checkRevisionExistence(s.meta, r.Revision) {
+			return ErrReleaseNotExist
+		}
+
+		if err := s.writeRelease(r); err != nil {
+			return err
+		}
 
-	return s.writeRelease(r)
+		setReleaseSummary(s.meta, r)
+		return s.writeMeta()
+	})
+}
+
+// Delete deletes the releases of the revisions, and compacts the metadata. The latest release cannot be
+// deleted, and only the metadata gets compacted if no revision is specified.
+func (s *S3Storage) Delete(revisions ...uint64) error {
+	return withMetaLock(s, func() error {
+		if err := s.readMeta(); err != nil {
+			return err
+		}
+		if err := checkReleasesDeletable(s.meta, revisions); err != nil {
+			return err
+		}
+
+		compactMetaData(s.meta, revisions)
+		if err := s.writeMeta(); err != nil {
+			return err
+		}
+		for _, revision := range revisions {
+			input := &s3.DeleteObjectInput{
+				Bucket: aws.String(s.bucket),
+				Key:    aws.String(fmt.Sprintf("%s/%d%s", s.prefix, revision, yamlSuffix)),
+			}
+			if _, err := s.s3.DeleteObject(input); err != nil {
+				return fmt.Errorf("delete release from s3 failed: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// ForceUnlock deletes the lock file of the releases metadata, which is left by a crashed process.
+func (s *S3Storage) ForceUnlock() error {
+	if err := s.deleteLock(); err != nil {
+		return fmt.Errorf("delete lock of releases metadata failed: %w", err)
+	}
+	return nil
 }
 
 func (s *S3Storage) readMeta() error {
@@ -153,9 +235,9 @@ func (s *S3Storage) writeMeta() error {
 }
 
 func (s *S3Storage) writeRelease(r *v1.Release) error {
-	content, err := yaml.Marshal(r)
+	content, err := encodeRelease(s.encrypter, r)
 	if err != nil {
-		return fmt.Errorf("yaml marshal release failed: %w", err)
+		return err
 	}
 
 	input := &s3.PutObjectInput{
@@ -168,3 +250,47 @@ func (s *S3Storage) writeRelease(r *v1.Release) error {
 	}
 	return nil
 }
+
+func (s *S3Storage) createLock(content []byte) error {
+	req, _ := s.s3.PutObjectRequest(&s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + "/" + metadataLockFile),
+		Body:   bytes.NewReader(content),
+	})
+	// Only create the lock object if it does not exist.
+	req.HTTPRequest.Header.Set("If-None-Match", "*")
+	if err := req.Send(); err != nil {
+		reqErr, ok := err.(awserr.RequestFailure)
+		if ok && (reqErr.StatusCode() == http.StatusPreconditionFailed || reqErr.StatusCode() == http.StatusConflict) {
+			return objectstore.ErrLocked
+		}
+		return fmt.Errorf("put releases metadata lock to s3 failed: %w", err)
+	}
+	return nil
+}
+
+func (s *S3Storage) readLock() ([]byte, error) {
+	output, err := s.s3.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + "/" + metadataLockFile),
+	})
+	if err != nil {
+		awsErr, ok := err.(awserr.Error)
+		if ok && awsErr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, objectstore.ErrNotFound
+		}
+		return nil, fmt.Errorf("get releases metadata lock from s3 failed: %w", err)
+	}
+	defer func() {
+		_ = output.Body.Close()
+	}()
+	return io.ReadAll(output.Body)
+}
+
+func (s *S3Storage) deleteLock() error {
+	_, err := s.s3.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + "/" + metadataLockFile),
+	})
+	return err
+}
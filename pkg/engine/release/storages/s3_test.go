@@ -17,6 +17,15 @@ func mockS3Storage() *S3Storage {
 	return &S3Storage{s3: &s3.S3{}, meta: mockReleasesMeta()}
 }
 
+func mockS3StorageLock() {
+	mockey.Mock((*S3Storage).createLock).Return(nil).Build()
+	mockey.Mock((*S3Storage).deleteLock).Return(nil).Build()
+}
+
+func mockS3StorageReadMeta() {
+	mockey.Mock((*S3Storage).readMeta).Return(nil).Build()
+}
+
 func mockS3StorageWriteMeta() {
 	mockey.Mock((*S3Storage).writeMeta).Return(nil).Build()
 }
@@ -147,6 +156,8 @@ func TestS3Storage_Create(t *testing.T) {
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
 			mockey.PatchConvey("mock s3 operation", t, func() {
+				mockS3StorageLock()
+				mockS3StorageReadMeta()
 				mockS3StorageWriteMeta()
 				mockS3StorageWriteRelease()
 				err := mockS3Storage().Create(tc.r)
@@ -178,6 +189,9 @@ func TestS3Storage_Update(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			mockey.PatchConvey("mock s3 operation", t, func() {
 				mockS3StorageWriteRelease()
+				mockS3StorageLock()
+				mockS3StorageReadMeta()
+				mockS3StorageWriteMeta()
 				err := mockS3Storage().Update(tc.r)
 				assert.Equal(t, tc.success, err == nil)
 			})
@@ -1,10 +1,17 @@
 package storages
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/infra/encryption"
 )
 
 const (
@@ -16,6 +23,7 @@ const (
 var (
 	ErrReleaseNotExist     = errors.New("release does not exist")
 	ErrReleaseAlreadyExist = errors.New("release has already existed")
+	ErrDeleteLatestRelease = errors.New("the latest release cannot be deleted")
 )
 
 // GenReleaseDirPath generates the release dir path, which is used for LocalStorage.
@@ -58,6 +66,9 @@ type releaseMetaData struct {
 
 	// Stack of the Release.
 	Stack string
+
+	// Summary of the Release, nil for the Releases written before the summaries are kept.
+	Summary *v1.ReleaseSummary `yaml:"summary,omitempty" json:"summary,omitempty"`
 }
 
 // checkRevisionExistence returns the workspace exists or not.
@@ -102,3 +113,82 @@ func addLatestReleaseMetaData(meta *releasesMetaData, revision uint64, stack str
 	}
 	meta.ReleaseMetaDatas = append(meta.ReleaseMetaDatas, metaData)
 }
+
+// setReleaseSummary sets the summary of the release in the metadata, called by the storage.Create and
+// storage.Update.
+func setReleaseSummary(meta *releasesMetaData, r *v1.Release) {
+	for _, metaData := range meta.ReleaseMetaDatas {
+		if metaData != nil && metaData.Revision == r.Revision {
+			metaData.Summary = r.Summary()
+			return
+		}
+	}
+}
+
+// getSummary returns the summary of the release kept in the metadata, nil if not kept.
+func getSummary(meta *releasesMetaData, revision uint64) *v1.ReleaseSummary {
+	for _, metaData := range meta.ReleaseMetaDatas {
+		if metaData != nil && metaData.Revision == revision {
+			return metaData.Summary
+		}
+	}
+	return nil
+}
+
+// checkReleasesDeletable returns error if the latest revision is in the revisions to delete.
+func checkReleasesDeletable(meta *releasesMetaData, revisions []uint64) error {
+	for _, revision := range revisions {
+		if revision == meta.LatestRevision {
+			return fmt.Errorf("%w: revision %d", ErrDeleteLatestRelease, revision)
+		}
+	}
+	return nil
+}
+
+// compactMetaData removes the deleted revisions from the metadata, and compacts it by dropping the empty and
+// duplicated entries and sorting the rest by revision, called by the storage.Delete.
+func compactMetaData(meta *releasesMetaData, deleted []uint64) {
+	skipped := make(map[uint64]bool, len(deleted))
+	for _, revision := range deleted {
+		skipped[revision] = true
+	}
+	metaDatas := make([]*releaseMetaData, 0, len(meta.ReleaseMetaDatas))
+	for _, metaData := range meta.ReleaseMetaDatas {
+		if metaData == nil || skipped[metaData.Revision] {
+			continue
+		}
+		skipped[metaData.Revision] = true
+		metaDatas = append(metaDatas, metaData)
+	}
+	sort.Slice(metaDatas, func(i, j int) bool {
+		return metaDatas[i].Revision < metaDatas[j].Revision
+	})
+	meta.ReleaseMetaDatas = metaDatas
+}
+
+// encodeRelease marshals the release to the content of the release file, which is encrypted if the
+// encrypter is not nil.
+func encodeRelease(encrypter *encryption.Encrypter, r *v1.Release) ([]byte, error) {
+	content, err := yaml.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("yaml marshal release failed: %w", err)
+	}
+	if content, err = encrypter.EncryptFile(context.Background(), content); err != nil {
+		return nil, fmt.Errorf("encrypt release failed: %w", err)
+	}
+	return content, nil
+}
+
+// decodeRelease unmarshals the release from the content of the release file, which is decrypted first
+// if encrypted.
+func decodeRelease(encrypter *encryption.Encrypter, content []byte) (*v1.Release, error) {
+	content, err := encrypter.DecryptFile(context.Background(), content)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt release failed: %w", err)
+	}
+	r := &v1.Release{}
+	if err = yaml.Unmarshal(content, r); err != nil {
+		return nil, fmt.Errorf("yaml unmarshal release failed: %w", err)
+	}
+	return r, nil
+}
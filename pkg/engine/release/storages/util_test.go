@@ -169,3 +169,28 @@ func TestAddLatestReleaseMetaData(t *testing.T) {
 		})
 	}
 }
+
+func TestCompactMetaData(t *testing.T) {
+	meta := &releasesMetaData{
+		LatestRevision: 4,
+		ReleaseMetaDatas: []*releaseMetaData{
+			{Revision: 2, Stack: "pre"},
+			nil,
+			{Revision: 1, Stack: "dev"},
+			{Revision: 4, Stack: "pre"},
+			{Revision: 2, Stack: "pre"},
+			{Revision: 3, Stack: "dev"},
+		},
+	}
+	assert.ErrorIs(t, checkReleasesDeletable(meta, []uint64{1, 4}), ErrDeleteLatestRelease)
+	assert.NoError(t, checkReleasesDeletable(meta, []uint64{1, 3}))
+
+	compactMetaData(meta, []uint64{1, 3})
+	assert.Equal(t, &releasesMetaData{
+		LatestRevision: 4,
+		ReleaseMetaDatas: []*releaseMetaData{
+			{Revision: 2, Stack: "pre"},
+			{Revision: 4, Stack: "pre"},
+		},
+	}, meta)
+}
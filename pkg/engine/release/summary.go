@@ -0,0 +1,85 @@
+package release
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+// SummaryFilter selects the Releases to list. An empty field selects all the Releases.
+type SummaryFilter struct {
+	// Stack is the Stack of the Releases.
+	Stack string
+	// Phases are the phases of the Releases.
+	Phases []v1.ReleasePhase
+	// Since selects the Releases created at or after it.
+	Since time.Time
+	// Until selects the Releases created before it.
+	Until time.Time
+}
+
+// Match returns whether the Release of the summary is selected by the filter.
+func (f *SummaryFilter) Match(s *v1.ReleaseSummary) bool {
+	if f == nil {
+		return true
+	}
+	if f.Stack != "" && s.Stack != f.Stack {
+		return false
+	}
+	if len(f.Phases) != 0 {
+		matched := false
+		for _, phase := range f.Phases {
+			if phase == s.Phase {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if !f.Since.IsZero() && s.CreateTime.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !s.CreateTime.Before(f.Until) {
+		return false
+	}
+	return true
+}
+
+// ListSummaries returns the summaries of the Releases selected by the filter, in the ascending order of the
+// Revision. The summaries kept in the metadata are used if the Storage is a Summarizer, so that only the
+// Releases without the kept summaries get read.
+func ListSummaries(storage Storage, filter *SummaryFilter) ([]*v1.ReleaseSummary, error) {
+	var revisions []uint64
+	if filter != nil && filter.Stack != "" {
+		revisions = storage.GetStackBoundRevisions(filter.Stack)
+	} else {
+		revisions = storage.GetRevisions()
+	}
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i] < revisions[j]
+	})
+
+	summarizer, _ := storage.(Summarizer)
+	summaries := make([]*v1.ReleaseSummary, 0, len(revisions))
+	for _, revision := range revisions {
+		var summary *v1.ReleaseSummary
+		if summarizer != nil {
+			summary = summarizer.GetSummary(revision)
+		}
+		if summary == nil {
+			r, err := storage.Get(revision)
+			if err != nil {
+				return nil, fmt.Errorf("get release of revision %d failed: %w", revision, err)
+			}
+			summary = r.Summary()
+		}
+		if filter.Match(summary) {
+			summaries = append(summaries, summary)
+		}
+	}
+	return summaries, nil
+}
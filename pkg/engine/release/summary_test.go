@@ -0,0 +1,58 @@
+package release
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/engine/release/storages"
+)
+
+func TestListSummaries(t *testing.T) {
+	now := time.Now()
+	storage, err := storages.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	for i, stack := range []string{"dev", "prod", "dev", "dev"} {
+		phase := v1.ReleasePhaseSucceeded
+		if i == 2 {
+			phase = v1.ReleasePhaseFailed
+		}
+		require.NoError(t, storage.Create(&v1.Release{
+			Project: "project", Workspace: "dev", Revision: uint64(i + 1), Stack: stack, Phase: phase,
+			CreateTime: now.Add(time.Duration(i) * time.Hour),
+			Provenance: &v1.ReleaseProvenance{Operator: "alice", Git: &v1.ReleaseGitInfo{Commit: "3836f877"}},
+			Actions:    &v1.ReleaseActions{Create: i},
+		}))
+	}
+
+	summaries, err := ListSummaries(storage, nil)
+	require.NoError(t, err)
+	require.Len(t, summaries, 4)
+	assert.Equal(t, "alice", summaries[0].Operator)
+	assert.Equal(t, "3836f877", summaries[0].Commit)
+	assert.Equal(t, 3, summaries[3].Actions.Create)
+
+	summaries, err = ListSummaries(storage, &SummaryFilter{Stack: "dev", Phases: []v1.ReleasePhase{v1.ReleasePhaseSucceeded}})
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{1, 4}, revisionsOf(summaries))
+
+	summaries, err = ListSummaries(storage, &SummaryFilter{Since: now.Add(time.Hour), Until: now.Add(3 * time.Hour)})
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{2, 3}, revisionsOf(summaries))
+
+	// The releases without the kept summaries are read.
+	summaries, err = ListSummaries(struct{ Storage }{storage}, &SummaryFilter{Stack: "prod"})
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{2}, revisionsOf(summaries))
+}
+
+func revisionsOf(summaries []*v1.ReleaseSummary) []uint64 {
+	revisions := make([]uint64, 0, len(summaries))
+	for _, s := range summaries {
+		revisions = append(revisions, s.Revision)
+	}
+	return revisions
+}
@@ -151,6 +151,154 @@ func CreateDestroyRelease(storage Storage, project, stack, workspace string) (*v
 	return rel, nil
 }
 
+// CreateRollbackRelease creates a release object in the storage for rolling back the failed release, which must
+// be the latest one, to the state of the release before it. The spec of the rollback release consists of the
+// resources in that state, preferring their definitions in its spec, so the changes back are computed by an
+// ordinary preview against the state left by the failed release.
+func CreateRollbackRelease(storage Storage, failed *v1.Release) (*v1.Release, error) {
+	revision := storage.GetLatestRevision()
+	if failed.Revision != revision {
+		return nil, fmt.Errorf("cannot roll back release %d of project %s, workspace %s, which is not the latest release %d",
+			failed.Revision, failed.Project, failed.Workspace, revision)
+	}
+	if failed.Phase != v1.ReleasePhaseFailed {
+		return nil, fmt.Errorf("cannot roll back release %d of project %s, workspace %s in phase %s",
+			failed.Revision, failed.Project, failed.Workspace, failed.Phase)
+	}
+
+	// Roll back to no resources if the failed release is the first one.
+	prior := &v1.Release{State: &v1.State{}}
+	if failed.Revision > 1 {
+		var err error
+		prior, err = storage.Get(failed.Revision - 1)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	defined := make(map[string]v1.Resource)
+	if prior.Spec != nil {
+		for _, res := range prior.Spec.Resources {
+			defined[res.ID] = res
+		}
+	}
+	var resources v1.Resources
+	if prior.State != nil {
+		resources = make(v1.Resources, 0, len(prior.State.Resources))
+		for _, res := range prior.State.Resources {
+			if def, ok := defined[res.ID]; ok {
+				res = def
+			}
+			resources = append(resources, res)
+		}
+	}
+
+	spec := &v1.Spec{Resources: resources}
+	if failed.Spec != nil {
+		spec.SecretStore = failed.Spec.SecretStore
+		spec.Context = failed.Spec.Context
+		spec.SecretEnvs = failed.Spec.SecretEnvs
+	}
+	state := failed.State
+	if state == nil {
+		state = &v1.State{}
+	}
+
+	currentTime := time.Now()
+	rel := &v1.Release{
+		Project:      failed.Project,
+		Workspace:    failed.Workspace,
+		Revision:     revision + 1,
+		Stack:        failed.Stack,
+		Spec:         spec,
+		State:        state,
+		Phase:        v1.ReleasePhasePreviewing,
+		CreateTime:   currentTime,
+		ModifiedTime: currentTime,
+		RollbackOf:   failed.Revision,
+	}
+
+	if err := storage.Create(rel); err != nil {
+		return nil, fmt.Errorf("create rollback release of project %s workspace %s revision %d failed: %w",
+			rel.Project, rel.Workspace, rel.Revision, err)
+	}
+
+	return rel, nil
+}
+
+// CreateImportRelease creates a succeeded release object in the storage which records the adoption of the imported
+// resources, without any change to the actual infrastructure. The imported resources are merged by ID into the spec
+// and state of the latest release, with the planned definitions in the spec and the live ones in the state. The
+// context and secret store of the spec are taken from the given spec.
+func CreateImportRelease(storage Storage, project, stack, workspace string, spec *v1.Spec, planned, live v1.Resources) (*v1.Release, error) {
+	revision := storage.GetLatestRevision()
+
+	lastSpec, lastState := &v1.Spec{}, &v1.State{}
+	if revision != 0 {
+		lastRelease, err := storage.Get(revision)
+		if err != nil {
+			return nil, err
+		}
+		if lastRelease.Phase != v1.ReleasePhaseSucceeded && lastRelease.Phase != v1.ReleasePhaseFailed {
+			return nil, fmt.Errorf("cannot create import release of project %s, workspace %s cause there is release in progress", project, workspace)
+		}
+		if lastRelease.Spec != nil {
+			lastSpec = lastRelease.Spec
+		}
+		if lastRelease.State != nil {
+			lastState = lastRelease.State
+		}
+	}
+
+	newSpec := &v1.Spec{Resources: mergeResources(lastSpec.Resources, planned)}
+	if spec != nil {
+		newSpec.SecretStore = spec.SecretStore
+		newSpec.Context = spec.Context
+	}
+	newState := *lastState
+	newState.Resources = mergeResources(lastState.Resources, live)
+
+	currentTime := time.Now()
+	rel := &v1.Release{
+		Project:      project,
+		Workspace:    workspace,
+		Revision:     revision + 1,
+		Stack:        stack,
+		Spec:         newSpec,
+		State:        &newState,
+		Phase:        v1.ReleasePhaseSucceeded,
+		CreateTime:   currentTime,
+		ModifiedTime: currentTime,
+	}
+
+	if err := storage.Create(rel); err != nil {
+		return nil, fmt.Errorf("create import release of project %s workspace %s revision %d failed: %w",
+			project, workspace, rel.Revision, err)
+	}
+
+	return rel, nil
+}
+
+// mergeResources returns a copy of the resources in which the ones with the same ID are replaced by the merged
+// resources, and the others of the merged resources are appended.
+func mergeResources(resources, merged v1.Resources) v1.Resources {
+	index := make(map[string]int, len(resources))
+	result := make(v1.Resources, len(resources), len(resources)+len(merged))
+	copy(result, resources)
+	for i, res := range result {
+		index[res.ID] = i
+	}
+	for _, res := range merged {
+		if i, ok := index[res.ID]; ok {
+			result[i] = res
+			continue
+		}
+		index[res.ID] = len(result)
+		result = append(result, res)
+	}
+	return result
+}
+
 // UpdateDestroyRelease updates the release in the storage. If release phase is failed, only logging with
 // no error return.
 func UpdateDestroyRelease(storage Storage, rel *v1.Release) error {
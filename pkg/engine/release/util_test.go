@@ -47,3 +47,74 @@ func TestGetLatestState(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateRollbackRelease(t *testing.T) {
+	storage, err := storages.NewLocalStorage(t.TempDir())
+	assert.NoError(t, err)
+
+	cm := func(data string) v1.Resource {
+		return v1.Resource{ID: "v1:ConfigMap:default:foo", Type: v1.Kubernetes, Attributes: map[string]interface{}{"data": data}}
+	}
+	sa := v1.Resource{ID: "v1:ServiceAccount:default:foo", Type: v1.Kubernetes}
+	succeeded := &v1.Release{
+		Project: "project", Workspace: "dev", Stack: "stack", Revision: 1, Phase: v1.ReleasePhaseSucceeded,
+		Spec:  &v1.Spec{Resources: v1.Resources{cm("v1")}},
+		State: &v1.State{Resources: v1.Resources{cm("v1-live")}},
+	}
+	failed := &v1.Release{
+		Project: "project", Workspace: "dev", Stack: "stack", Revision: 2, Phase: v1.ReleasePhaseApplying,
+		Spec:  &v1.Spec{Resources: v1.Resources{cm("v2"), sa}, Context: v1.GenericConfig{"foo": "bar"}},
+		State: &v1.State{Resources: v1.Resources{cm("v2"), sa}},
+	}
+	assert.NoError(t, storage.Create(succeeded))
+	assert.NoError(t, storage.Create(failed))
+
+	_, err = CreateRollbackRelease(storage, failed)
+	assert.ErrorContains(t, err, "in phase applying")
+	_, err = CreateRollbackRelease(storage, succeeded)
+	assert.ErrorContains(t, err, "not the latest release")
+
+	failed.Phase = v1.ReleasePhaseFailed
+	assert.NoError(t, storage.Update(failed))
+	rel, err := CreateRollbackRelease(storage, failed)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), rel.Revision)
+	assert.Equal(t, uint64(2), rel.RollbackOf)
+	assert.Equal(t, v1.Resources{cm("v1")}, rel.Spec.Resources)
+	assert.Equal(t, failed.Spec.Context, rel.Spec.Context)
+	assert.Equal(t, failed.State, rel.State)
+	assert.Equal(t, uint64(3), storage.GetLatestRevision())
+}
+
+func TestCreateImportRelease(t *testing.T) {
+	storage, err := storages.NewLocalStorage(t.TempDir())
+	assert.NoError(t, err)
+
+	cm := func(data string) v1.Resource {
+		return v1.Resource{ID: "v1:ConfigMap:default:foo", Type: v1.Kubernetes, Attributes: map[string]interface{}{"data": data}}
+	}
+	sa := func(name string) v1.Resource {
+		return v1.Resource{ID: "v1:ServiceAccount:default:" + name, Type: v1.Kubernetes, Attributes: map[string]interface{}{"name": name}}
+	}
+	spec := &v1.Spec{Context: v1.GenericConfig{"foo": "bar"}}
+
+	rel, err := CreateImportRelease(storage, "project", "stack", "dev", spec, v1.Resources{cm("v1")}, v1.Resources{cm("v1-live")})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), rel.Revision)
+	assert.Equal(t, v1.ReleasePhaseSucceeded, rel.Phase)
+	assert.Equal(t, v1.Resources{cm("v1")}, rel.Spec.Resources)
+	assert.Equal(t, v1.Resources{cm("v1-live")}, rel.State.Resources)
+	assert.Equal(t, spec.Context, rel.Spec.Context)
+
+	rel, err = CreateImportRelease(storage, "project", "stack", "dev", spec,
+		v1.Resources{cm("v2"), sa("bar")}, v1.Resources{cm("v2-live"), sa("bar")})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), rel.Revision)
+	assert.Equal(t, v1.Resources{cm("v2"), sa("bar")}, rel.Spec.Resources)
+	assert.Equal(t, v1.Resources{cm("v2-live"), sa("bar")}, rel.State.Resources)
+
+	rel.Phase = v1.ReleasePhaseApplying
+	assert.NoError(t, storage.Update(rel))
+	_, err = CreateImportRelease(storage, "project", "stack", "dev", spec, v1.Resources{sa("baz")}, v1.Resources{sa("baz")})
+	assert.ErrorContains(t, err, "in progress")
+}
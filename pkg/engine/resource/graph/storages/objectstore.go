@@ -0,0 +1,98 @@
+package storages
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/engine/resource/graph"
+	"kusionstack.io/kusion/pkg/infra/objectstore"
+)
+
+// ObjectStoreStorage is an implementation of graph.Storage which uses a generic object store as storage,
+// such as the Azure Blob Storage, a HTTP server or a Kubernetes cluster.
+type ObjectStoreStorage struct {
+	store objectstore.Store
+
+	// The prefix to store the graph files.
+	prefix string
+}
+
+// NewObjectStoreStorage news object store graph storage.
+func NewObjectStoreStorage(store objectstore.Store, prefix string) (*ObjectStoreStorage, error) {
+	s := &ObjectStoreStorage{
+		store:  store,
+		prefix: prefix,
+	}
+
+	return s, nil
+}
+
+// Get gets the graph from the object store.
+func (s *ObjectStoreStorage) Get() (*v1.Graph, error) {
+	content, err := s.store.Get(context.Background(), s.key())
+	if err != nil {
+		return nil, fmt.Errorf("get resource graph from object store failed: %w", err)
+	}
+
+	r := &v1.Graph{}
+	if err = json.Unmarshal(content, r); err != nil {
+		return nil, fmt.Errorf("json unmarshal graph failed: %w", err)
+	}
+	// Index is not stored in the object store, so we need to rebuild it.
+	graph.UpdateResourceIndex(r.Resources)
+
+	return r, nil
+}
+
+// Create creates the graph in the object store.
+func (s *ObjectStoreStorage) Create(r *v1.Graph) error {
+	if s.CheckGraphStorageExistence() {
+		return ErrGraphAlreadyExist
+	}
+
+	return s.writeGraph(r)
+}
+
+// Update updates the graph in the object store.
+func (s *ObjectStoreStorage) Update(r *v1.Graph) error {
+	if !s.CheckGraphStorageExistence() {
+		return ErrGraphNotExist
+	}
+
+	return s.writeGraph(r)
+}
+
+// Delete deletes the graph in the object store.
+func (s *ObjectStoreStorage) Delete() error {
+	if err := s.store.Delete(context.Background(), s.key()); err != nil {
+		return fmt.Errorf("remove graph in object store failed: %w", err)
+	}
+
+	return nil
+}
+
+// CheckGraphStorageExistence checks whether the graph storage exists.
+func (s *ObjectStoreStorage) CheckGraphStorageExistence() bool {
+	_, err := s.store.Get(context.Background(), s.key())
+	return err == nil
+}
+
+// writeGraph writes the graph to the object store.
+func (s *ObjectStoreStorage) writeGraph(r *v1.Graph) error {
+	content, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("json marshal graph failed: %w", err)
+	}
+
+	if err = s.store.Put(context.Background(), s.key(), content); err != nil {
+		return fmt.Errorf("put graph to object store failed: %w", err)
+	}
+
+	return nil
+}
+
+func (s *ObjectStoreStorage) key() string {
+	return fmt.Sprintf("%s/%s", s.prefix, graphFileName)
+}
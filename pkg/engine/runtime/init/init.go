@@ -39,6 +39,7 @@ func Runtimes(spec apiv1.Spec, state apiv1.State) (map[apiv1.Type]runtime.Runtim
 	if err := parseContextSecretRef(&spec); err != nil {
 		return nil, v1.NewErrorStatus(err)
 	}
+	injectSecretEnvs(&spec)
 	resources := spec.Resources
 	resources = append(resources, state.Resources...)
 	runtimesMap := map[apiv1.Type]runtime.Runtime{}
@@ -133,3 +134,36 @@ func parseContextSecretRef(spec *apiv1.Spec) error {
 
 	return nil
 }
+
+// injectSecretEnvs adds the secret environment variables of Spec to the provider
+// environment variables in the Context, which is a copy only seen by the runtimes.
+// The provider environment variables configured in the Context take precedence.
+func injectSecretEnvs(spec *apiv1.Spec) {
+	if len(spec.SecretEnvs) == 0 {
+		return
+	}
+
+	providerEnvs := map[string]any{}
+	switch existing := spec.Context[apiv1.ProviderEnvs].(type) {
+	case apiv1.GenericConfig:
+		for k, v := range existing {
+			providerEnvs[k] = v
+		}
+	case map[string]any:
+		for k, v := range existing {
+			providerEnvs[k] = v
+		}
+	}
+	for name, value := range spec.SecretEnvs {
+		if _, ok := providerEnvs[name]; !ok {
+			providerEnvs[name] = value
+		}
+	}
+
+	parsedContext := apiv1.GenericConfig{}
+	for k, v := range spec.Context {
+		parsedContext[k] = v
+	}
+	parsedContext[apiv1.ProviderEnvs] = providerEnvs
+	spec.Context = parsedContext
+}
@@ -97,3 +97,18 @@ func TestValidResources(t *testing.T) {
 		})
 	}
 }
+
+func TestInjectSecretEnvs(t *testing.T) {
+	spec := &apiv1.Spec{
+		Context: apiv1.GenericConfig{
+			apiv1.ProviderEnvs: apiv1.GenericConfig{"REGION": "us-east-1", "TOKEN": "workspace"},
+		},
+		SecretEnvs: map[string]string{"TOKEN": "secret", "PASSWORD": "s3cr3t"},
+	}
+	original := spec.Context
+
+	injectSecretEnvs(spec)
+	assert.Equal(t, map[string]any{"REGION": "us-east-1", "TOKEN": "workspace", "PASSWORD": "s3cr3t"}, spec.Context[apiv1.ProviderEnvs])
+	// The Context of the Spec to be persisted is left untouched.
+	assert.Equal(t, apiv1.GenericConfig{"REGION": "us-east-1", "TOKEN": "workspace"}, original[apiv1.ProviderEnvs])
+}
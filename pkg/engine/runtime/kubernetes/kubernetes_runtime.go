@@ -41,7 +41,10 @@ import (
 	"kusionstack.io/kusion/pkg/workspace"
 )
 
-var _ runtime.Runtime = (*KubernetesRuntime)(nil)
+var (
+	_ runtime.Runtime    = (*KubernetesRuntime)(nil)
+	_ runtime.Discoverer = (*KubernetesRuntime)(nil)
+)
 
 type KubernetesRuntime struct {
 	client dynamic.Interface
@@ -268,6 +271,61 @@ func (k *KubernetesRuntime) Import(ctx context.Context, request *runtime.ImportR
 	}
 }
 
+// Discover lists the existing kubernetes Resources of the same kinds as the requested ones, which are in the
+// requested namespace and selected by the label selector
+func (k *KubernetesRuntime) Discover(ctx context.Context, request *runtime.DiscoverRequest) *runtime.DiscoverResponse {
+	var discovered apiv1.Resources
+	listed := map[schema.GroupVersionKind]bool{}
+	for i := range request.Resources {
+		rYaml, err := yamlv2.Marshal(request.Resources[i].Attributes)
+		if err != nil {
+			return &runtime.DiscoverResponse{Status: v1.NewErrorStatus(err)}
+		}
+		_, gvk, err := convertString2Unstructured(rYaml)
+		if err != nil {
+			return &runtime.DiscoverResponse{Status: v1.NewErrorStatus(err)}
+		}
+		if listed[*gvk] {
+			continue
+		}
+		listed[*gvk] = true
+
+		mapping, err := k.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			// Ignore no match error, cause target apiVersion or kind is not installed yet
+			if meta.IsNoMatchError(err) {
+				log.Infof("%v, ignore", err)
+				continue
+			}
+			return &runtime.DiscoverResponse{Status: v1.NewErrorStatus(err)}
+		}
+		var resource dynamic.ResourceInterface = k.client.Resource(mapping.Resource)
+		namespaced := mapping.Scope.Name() == meta.RESTScopeNameNamespace
+		if namespaced && request.Namespace != "" {
+			resource = k.client.Resource(mapping.Resource).Namespace(request.Namespace)
+		}
+
+		list, err := resource.List(ctx, metav1.ListOptions{LabelSelector: request.LabelSelector})
+		if err != nil {
+			return &runtime.DiscoverResponse{Status: v1.NewErrorStatus(err)}
+		}
+		for j := range list.Items {
+			item := &list.Items[j]
+			normalizeServerSideFields(item)
+			id := strings.Join([]string{gvk.GroupVersion().String(), gvk.Kind, item.GetName()}, engine.Separator)
+			if namespaced {
+				id = strings.Join([]string{gvk.GroupVersion().String(), gvk.Kind, item.GetNamespace(), item.GetName()}, engine.Separator)
+			}
+			discovered = append(discovered, apiv1.Resource{
+				ID:         id,
+				Type:       runtime.Kubernetes,
+				Attributes: item.Object,
+			})
+		}
+	}
+	return &runtime.DiscoverResponse{Resources: discovered}
+}
+
 // normalize fields added by K8s that will cause a perpetual diff
 func normalizeServerSideFields(ur *unstructured.Unstructured) {
 	const metadata = "metadata"
@@ -612,41 +670,30 @@ func (k *KubernetesRuntime) WatchByRelation(
 func doWatch(ctx context.Context, watcher k8swatch.Interface, amount int, checker func(watched *unstructured.Unstructured) bool) []KubernetesWatchEvent {
 	events := []KubernetesWatchEvent{}
 	eventMap := make(map[string]chan k8swatch.Event) // eventMap to store the watch channels to use the same watch channel for each resource
-	signal := make(chan struct{})
-	timeoutCh := make(chan struct{})
-	first := false
-	// No resource amount is specified
-	if amount == -1 {
-		go func() {
-			// Wait for 2 seconds after receiving the first event
-			for {
-				if first {
-					log.Infof("dependent amount is not specified, will return after timeout")
-					time.Sleep(2 * time.Second)
-					timeoutCh <- struct{}{}
-				}
-			}
-		}()
-	}
+	// Buffered, so that the dispatching goroutine never blocks on them after
+	// this function returns
+	signal := make(chan []KubernetesWatchEvent, 1)
+	timeoutCh := make(chan []KubernetesWatchEvent, 1)
 
 	go func() {
 		defer watcher.Stop()
+		var timeout <-chan time.Time
 		for {
 			select {
-			case e := <-watcher.ResultChan():
+			case e, ok := <-watcher.ResultChan():
+				if !ok {
+					return
+				}
 				dependent, ok := e.Object.(*unstructured.Unstructured)
 				if !ok {
 					break
 				}
-				if !first {
-					first = true
-				}
 				// Check
 				if checker == nil || checker != nil && checker(dependent) {
 					resName := dependent.GetName()
 					ch, ok := eventMap[resName]
 					if ok && ch != nil {
-						ch <- e
+						sendLatest(ch, e)
 					} else {
 						// Buffered channel, put new event into the event map
 						ch := make(chan k8swatch.Event, 1)
@@ -656,10 +703,18 @@ func doWatch(ctx context.Context, watcher k8swatch.Interface, amount int, checke
 						events = append(events, KubernetesWatchEvent{Event: ch, Resource: dependent})
 						// Got enough events
 						if len(events) == amount {
-							signal <- struct{}{}
+							signal <- append([]KubernetesWatchEvent{}, events...)
+						}
+						// No resource amount is specified, wait for 2 seconds after receiving the first event
+						if amount == -1 && timeout == nil {
+							log.Infof("dependent amount is not specified, will return after timeout")
+							timeout = time.After(2 * time.Second)
 						}
 					}
 				}
+			case <-timeout:
+				timeout = nil
+				timeoutCh <- append([]KubernetesWatchEvent{}, events...)
 			case <-ctx.Done():
 				return
 			}
@@ -669,17 +724,33 @@ func doWatch(ctx context.Context, watcher k8swatch.Interface, amount int, checke
 	// Return the watch events channels and their belonging resources
 	select {
 	// Got enough events
-	case <-signal:
+	case events := <-signal:
 		return events
 	// Context done
 	case <-ctx.Done():
 		return nil
 	// No specified amount of events, return after timeout
-	case <-timeoutCh:
+	case events := <-timeoutCh:
 		return events
 	}
 }
 
+// sendLatest sends the event to the channel with a buffer of one event, and
+// replaces the pending event if it has not been consumed yet, since only the
+// latest state of a resource matters. It keeps the memory bounded and never
+// blocks the watch of the other resources on a slow consumer.
+func sendLatest(ch chan k8swatch.Event, e k8swatch.Event) {
+	select {
+	case ch <- e:
+	default:
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- e
+	}
+}
+
 // Judge dependent is default service account
 func isDefaultServiceAccount(dependent, owner *unstructured.Unstructured) bool {
 	return dependent.GetName() == "default" && dependent.GetNamespace() == owner.GetName()
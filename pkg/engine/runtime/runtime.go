@@ -54,6 +54,13 @@ type Runtime interface {
 	Watch(ctx context.Context, request *WatchRequest) *WatchResponse
 }
 
+// Discoverer is an optional interface of the Runtime, which lists the resources already existing in the actual
+// infrastructure as the candidates to import.
+type Discoverer interface {
+	// Discover lists the existing resources of the same kinds as the resources in the request.
+	Discover(ctx context.Context, request *DiscoverRequest) *DiscoverResponse
+}
+
 type ApplyRequest struct {
 	// PriorResource is the last applied resource saved in state storage
 	PriorResource *apiv1.Resource
@@ -111,6 +118,25 @@ type ImportResponse struct {
 	Status v1.Status
 }
 
+type DiscoverRequest struct {
+	// Resources are the resources whose kinds are to discover
+	Resources apiv1.Resources
+
+	// Namespace is the namespace to discover the resources in, empty means all the namespaces
+	Namespace string
+
+	// LabelSelector selects the resources to discover by labels, empty means all the resources
+	LabelSelector string
+}
+
+type DiscoverResponse struct {
+	// Resources are the discovered resources, whose IDs are in the same format as the resources in the request
+	Resources apiv1.Resources
+
+	// Status contains messages will show to users
+	Status v1.Status
+}
+
 type DeleteRequest struct {
 	// Resource represents the resource we want to delete from the actual infra
 	Resource *apiv1.Resource
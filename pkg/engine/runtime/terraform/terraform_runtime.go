@@ -17,6 +17,7 @@ import (
 	"kusionstack.io/kusion/pkg/engine/runtime"
 	"kusionstack.io/kusion/pkg/engine/runtime/terraform/tfops"
 	"kusionstack.io/kusion/pkg/log"
+	"kusionstack.io/kusion/pkg/util/mask"
 )
 
 var _ runtime.Runtime = &Runtime{}
@@ -79,7 +80,7 @@ func (t *Runtime) Apply(ctx context.Context, request *runtime.ApplyRequest) *run
 					Type:       plan.Type,
 					Attributes: readResponse.Resource.Attributes,
 					DependsOn:  plan.DependsOn,
-					Extensions: plan.Extensions,
+					Extensions: withSensitiveAttributes(plan.Extensions, mask.SensitiveAttributes(readResponse.Resource)),
 				},
 				Status: nil,
 			}
@@ -100,7 +101,7 @@ func (t *Runtime) Apply(ctx context.Context, request *runtime.ApplyRequest) *run
 				Type:       plan.Type,
 				Attributes: module.Resources[0].AttributeValues,
 				DependsOn:  plan.DependsOn,
-				Extensions: plan.Extensions,
+				Extensions: withSensitiveAttributes(plan.Extensions, module.Resources[0].SensitivePaths()),
 			},
 			Status: nil,
 		}
@@ -177,12 +178,33 @@ func (t *Runtime) Apply(ctx context.Context, request *runtime.ApplyRequest) *run
 			Type:       plan.Type,
 			Attributes: r.Attributes,
 			DependsOn:  plan.DependsOn,
-			Extensions: plan.Extensions,
+			Extensions: withSensitiveAttributes(plan.Extensions, mask.SensitiveAttributes(&r)),
 		},
 		Status: nil,
 	}
 }
 
+// withSensitiveAttributes returns a copy of the extensions recording the paths of
+// the sensitive attributes marked by the provider schema, so that they are
+// masked wherever the resource is shown.
+func withSensitiveAttributes(extensions map[string]interface{}, paths []string) map[string]interface{} {
+	if len(paths) == 0 {
+		if _, ok := extensions[mask.SensitiveAttributesKey]; !ok {
+			return extensions
+		}
+	}
+	result := make(map[string]interface{}, len(extensions)+1)
+	for k, v := range extensions {
+		result[k] = v
+	}
+	if len(paths) == 0 {
+		delete(result, mask.SensitiveAttributesKey)
+	} else {
+		result[mask.SensitiveAttributesKey] = paths
+	}
+	return result
+}
+
 func buildTFCacheDir(stackPath string, key string) string {
 	// replace ':' with '_' to comply with Windows directory naming conventions.
 	return filepath.Join(stackPath, "."+strings.ReplaceAll(key, ":", "_"))
@@ -254,7 +276,7 @@ func (t *Runtime) Read(ctx context.Context, request *runtime.ReadRequest) *runti
 					Type:       planResource.Type,
 					Attributes: r.Attributes,
 					DependsOn:  planResource.DependsOn,
-					Extensions: planResource.Extensions,
+					Extensions: withSensitiveAttributes(planResource.Extensions, mask.SensitiveAttributes(&r)),
 				}, Status: nil,
 			}
 		}
@@ -287,7 +309,7 @@ func (t *Runtime) Read(ctx context.Context, request *runtime.ReadRequest) *runti
 			Type:       planResource.Type,
 			Attributes: r.Attributes,
 			DependsOn:  planResource.DependsOn,
-			Extensions: planResource.Extensions,
+			Extensions: withSensitiveAttributes(planResource.Extensions, mask.SensitiveAttributes(&r)),
 		},
 		Status: nil,
 	}
@@ -2,10 +2,13 @@ package tfops
 
 import (
 	"encoding/json"
+	"sort"
+	"strconv"
 
 	"github.com/zclconf/go-cty/cty"
 
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/util/mask"
 )
 
 // StateRepresentation is the top-level representation of the json format of a terraform
@@ -92,6 +95,11 @@ type resource struct {
 	// from absent values.
 	AttributeValues attributeValues `json:"values,omitempty"`
 
+	// SensitiveValues is similar to AttributeValues, but with all sensitive
+	// leaf values, marked by the provider schema, replaced with true, and all
+	// non-sensitive leaf values omitted.
+	SensitiveValues json.RawMessage `json:"sensitive_values,omitempty"`
+
 	// DependsOn contains a list of the resource's dependencies. The entries are
 	// addresses relative to the containing module.
 	DependsOn []string `json:"depends_on,omitempty"`
@@ -117,6 +125,9 @@ func ConvertTFState(tfState *StateRepresentation, providerAddr string) v1.Resour
 	extension := make(map[string]interface{})
 	extension["resourceType"] = tResource.Type
 	extension["provider"] = providerAddr
+	if paths := tResource.SensitivePaths(); len(paths) != 0 {
+		extension[mask.SensitiveAttributesKey] = paths
+	}
 	r := v1.Resource{
 		ID:         tResource.Name,
 		Type:       "Terraform",
@@ -126,3 +137,42 @@ func ConvertTFState(tfState *StateRepresentation, providerAddr string) v1.Resour
 
 	return r
 }
+
+// SensitivePaths returns the dot-separated paths of the sensitive attributes of
+// the resource, relative to the attribute values, in a stable order.
+func (r resource) SensitivePaths() []string {
+	if len(r.SensitiveValues) == 0 {
+		return nil
+	}
+	var values interface{}
+	if err := json.Unmarshal(r.SensitiveValues, &values); err != nil {
+		return nil
+	}
+	var paths []string
+	collectSensitivePaths(values, "", &paths)
+	sort.Strings(paths)
+	return paths
+}
+
+func collectSensitivePaths(values interface{}, prefix string, paths *[]string) {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+	switch v := values.(type) {
+	case bool:
+		if v && prefix != "" {
+			*paths = append(*paths, prefix)
+		}
+	case map[string]interface{}:
+		for key, value := range v {
+			collectSensitivePaths(value, join(key), paths)
+		}
+	case []interface{}:
+		for i, value := range v {
+			collectSensitivePaths(value, join(strconv.Itoa(i)), paths)
+		}
+	}
+}
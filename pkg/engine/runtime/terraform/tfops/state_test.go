@@ -55,6 +55,45 @@ func TestConvertTFState(t *testing.T) {
 				},
 			},
 		},
+		"sensitive values": {
+			args: StateRepresentation{
+				FormatVersion:    "0.2",
+				TerraformVersion: "1.0.6",
+				Values: &stateValues{
+					RootModule: module{
+						Resources: []resource{
+							{
+								Address:      "local_sensitive_file.test",
+								Mode:         "managed",
+								Type:         "local_sensitive_file",
+								Name:         "test",
+								ProviderName: "registry.terraform.io/hashicorp/local",
+								AttributeValues: attributeValues{
+									"content":  "kusion",
+									"filename": "text.txt",
+									"source":   []interface{}{map[string]interface{}{"token": "foo"}},
+								},
+								SensitiveValues: []byte(`{"content":true,"source":[{"token":true}],"tags":{}}`),
+							},
+						},
+					},
+				},
+			},
+			want: v1.Resource{
+				ID:   "test",
+				Type: "Terraform",
+				Attributes: map[string]interface{}{
+					"content":  "kusion",
+					"filename": "text.txt",
+					"source":   []interface{}{map[string]interface{}{"token": "foo"}},
+				},
+				Extensions: map[string]interface{}{
+					"provider":            "registry.terraform.io/hashicorp/local/2.2.3",
+					"resourceType":        "local_sensitive_file",
+					"sensitiveAttributes": []string{"content", "source.0.token"},
+				},
+			},
+		},
 	}
 
 	for name, tc := range tests {
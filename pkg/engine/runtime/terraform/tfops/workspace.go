@@ -9,6 +9,7 @@ import (
 	"os/exec"
 	"os/user"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 
@@ -600,6 +601,52 @@ func (w *WorkSpace) getEnvProviderInfo() ([]string, error) {
 		envs = append(envs, fmt.Sprintf("%s=%s", v1.EnvViettelCloudProjectID, viettelCloudProjectID))
 	}
 
+	// Get the environment variables of any other provider.
+	providerEnvs, err := getProviderEnvs(context)
+	if err != nil {
+		return nil, err
+	}
+	envs = append(envs, providerEnvs...)
+
+	return envs, nil
+}
+
+// getProviderEnvs returns the environment variables in the providerEnvs map
+// of the context, sorted by name. The map is a GenericConfig when set by the
+// caller, and a plain map once the spec has been read from the release.
+func getProviderEnvs(config v1.GenericConfig) ([]string, error) {
+	value, ok := config[v1.ProviderEnvs]
+	if !ok || value == nil {
+		return nil, nil
+	}
+	var m map[string]any
+	switch v := value.(type) {
+	case v1.GenericConfig:
+		m = v
+	case map[string]any:
+		m = v
+	case map[string]string:
+		m = make(map[string]any, len(v))
+		for k, s := range v {
+			m[k] = s
+		}
+	default:
+		return nil, fmt.Errorf("the value of %s is not map", v1.ProviderEnvs)
+	}
+
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	envs := make([]string, 0, len(names))
+	for _, name := range names {
+		s, ok := m[name].(string)
+		if !ok {
+			return nil, fmt.Errorf("the value of %s.%s is not string", v1.ProviderEnvs, name)
+		}
+		envs = append(envs, fmt.Sprintf("%s=%s", name, s))
+	}
 	return envs, nil
 }
 
@@ -459,3 +459,32 @@ func mockProviderAddr() {
 		}, nil
 	}).Build()
 }
+
+func TestGetEnvProviderInfo(t *testing.T) {
+	for name, providerEnvs := range map[string]any{
+		"generic config": apiv1.GenericConfig{"GOOGLE_CREDENTIALS": "credentials", "ARM_CLIENT_ID": "client"},
+		"plain map":      map[string]any{"GOOGLE_CREDENTIALS": "credentials", "ARM_CLIENT_ID": "client"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := NewWorkSpace(&resourceTest, "", "", &sync.Mutex{}, apiv1.GenericConfig{
+				apiv1.EnvAwsRegion: "us-east-1",
+				apiv1.ProviderEnvs: providerEnvs,
+			})
+			envs, err := w.getEnvProviderInfo()
+			if err != nil {
+				t.Fatalf("getEnvProviderInfo() error = %v", err)
+			}
+			want := []string{"AWS_REGION=us-east-1", "ARM_CLIENT_ID=client", "GOOGLE_CREDENTIALS=credentials"}
+			if diff := cmp.Diff(want, envs); diff != "" {
+				t.Errorf("getEnvProviderInfo() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	w := NewWorkSpace(&resourceTest, "", "", &sync.Mutex{}, apiv1.GenericConfig{
+		apiv1.ProviderEnvs: map[string]any{"ARM_CLIENT_ID": 1},
+	})
+	if _, err := w.getEnvProviderInfo(); err == nil {
+		t.Errorf("getEnvProviderInfo() expected an error for a non-string value")
+	}
+}
@@ -0,0 +1,51 @@
+package runtime
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// MergeEvents fans in the events of the watchers into the returned channel.
+// Unlike selecting over the watchers in a polling loop, it blocks until an
+// event arrives, so an idle watch costs no CPU. The returned channel is closed
+// once all the watchers are closed or the context is done, and it is not
+// buffered, so a slow consumer holds back the watchers instead of piling up
+// the events in memory.
+func MergeEvents(ctx context.Context, watchers ...<-chan watch.Event) <-chan watch.Event {
+	out := make(chan watch.Event)
+	wg := &sync.WaitGroup{}
+	wg.Add(len(watchers))
+	for _, w := range watchers {
+		go func(w <-chan watch.Event) {
+			defer wg.Done()
+			for {
+				select {
+				case e, ok := <-w:
+					if !ok {
+						return
+					}
+					select {
+					case out <- e:
+					case <-ctx.Done():
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(w)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// Events returns the events of all the watchers of the Kubernetes resources
+// as one channel, see MergeEvents.
+func (w *SequentialWatchers) Events(ctx context.Context) <-chan watch.Event {
+	return MergeEvents(ctx, w.Watchers...)
+}
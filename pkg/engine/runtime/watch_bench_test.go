@@ -0,0 +1,103 @@
+//go:build !windows
+
+package runtime
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+const (
+	benchmarkWatchedObjects = 500
+	// benchmarkReconcileTime is how long the objects take to get ready, during
+	// which the watch is idle.
+	benchmarkReconcileTime = 200 * time.Millisecond
+)
+
+// BenchmarkWatch compares the CPU time spent watching hundreds of objects
+// which are reconciled after a while, between polling the watchers with a
+// default select case and blocking on the merged events. The cpu-ms/op metric
+// is the CPU time of the process, and cpu-cores the CPU time per wall time.
+func BenchmarkWatch(b *testing.B) {
+	b.Run("polling", func(b *testing.B) {
+		benchmarkWatch(b, func(ctx context.Context, watchers []<-chan watch.Event, ready func(watch.Event) bool) {
+			cases := make([]reflect.SelectCase, 0, len(watchers)+1)
+			for _, w := range watchers {
+				cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(w)})
+			}
+			cases = append(cases, reflect.SelectCase{Dir: reflect.SelectDefault})
+			for {
+				chosen, recv, recvOK := reflect.Select(cases)
+				if cases[chosen].Dir == reflect.SelectDefault || !recvOK {
+					continue
+				}
+				if ready(recv.Interface().(watch.Event)) {
+					return
+				}
+			}
+		})
+	})
+
+	b.Run("blocking", func(b *testing.B) {
+		benchmarkWatch(b, func(ctx context.Context, watchers []<-chan watch.Event, ready func(watch.Event) bool) {
+			for e := range MergeEvents(ctx, watchers...) {
+				if ready(e) {
+					return
+				}
+			}
+		})
+	})
+}
+
+func benchmarkWatch(b *testing.B, consume func(context.Context, []<-chan watch.Event, func(watch.Event) bool)) {
+	var cpu, wall time.Duration
+	for i := 0; i < b.N; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		watchers := make([]<-chan watch.Event, benchmarkWatchedObjects)
+		wg := &sync.WaitGroup{}
+		wg.Add(benchmarkWatchedObjects)
+		for j := range watchers {
+			ch := make(chan watch.Event, 1)
+			watchers[j] = ch
+			go func() {
+				defer wg.Done()
+				ch <- watch.Event{Type: watch.Added}
+				select {
+				case <-time.After(benchmarkReconcileTime):
+					ch <- watch.Event{Type: watch.Modified}
+				case <-ctx.Done():
+				}
+			}()
+		}
+
+		remaining := benchmarkWatchedObjects
+		startCPU, startWall := cpuTime(b), time.Now()
+		consume(ctx, watchers, func(e watch.Event) bool {
+			if e.Type == watch.Modified {
+				remaining--
+			}
+			return remaining == 0
+		})
+		cpu += cpuTime(b) - startCPU
+		wall += time.Since(startWall)
+
+		cancel()
+		wg.Wait()
+	}
+	b.ReportMetric(float64(cpu.Milliseconds())/float64(b.N), "cpu-ms/op")
+	b.ReportMetric(cpu.Seconds()/wall.Seconds(), "cpu-cores")
+}
+
+func cpuTime(b *testing.B) time.Duration {
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+		b.Fatal(err)
+	}
+	return time.Duration(usage.Utime.Nano() + usage.Stime.Nano())
+}
@@ -0,0 +1,44 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestMergeEvents(t *testing.T) {
+	t.Run("closed watchers", func(t *testing.T) {
+		a, b := make(chan watch.Event, 2), make(chan watch.Event, 1)
+		a <- watch.Event{Type: watch.Added}
+		a <- watch.Event{Type: watch.Modified}
+		b <- watch.Event{Type: watch.Deleted}
+		close(a)
+		close(b)
+
+		var events []watch.Event
+		for e := range MergeEvents(context.Background(), a, b) {
+			events = append(events, e)
+		}
+		require.Len(t, events, 3)
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		events := MergeEvents(ctx, make(chan watch.Event), make(chan watch.Event))
+		cancel()
+		select {
+		case _, ok := <-events:
+			require.False(t, ok)
+		case <-time.After(time.Second):
+			t.Fatal("the events are not closed after the context is done")
+		}
+	})
+
+	t.Run("no watchers", func(t *testing.T) {
+		_, ok := <-MergeEvents(context.Background())
+		require.False(t, ok)
+	})
+}
@@ -0,0 +1,62 @@
+package encryption
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EnvKeyProviderName is the name of the key provider reading the key
+// encryption keys from an environment variable.
+const EnvKeyProviderName = "env"
+
+// EnvKeyVarOption is the option of the env key provider specifying the name of
+// the environment variable, which defaults to DefaultKeyEnvVar.
+const EnvKeyVarOption = "envVar"
+
+// DefaultKeyEnvVar is the environment variable read by the env key provider if
+// not specified in the options.
+const DefaultKeyEnvVar = "KUSION_ENCRYPTION_KEY"
+
+var ErrEmptyKeyEnv = errors.New("no key found in the environment variable")
+
+// EnvKeyProvider wraps the data keys with AES keys read from an environment
+// variable, which suits the CI pipelines injecting the keys as secrets.
+//
+// The keys are in the same format as the lines of the key file of the
+// LocalKeyProvider, separated by newlines or commas, and the last one is the
+// current key.
+type EnvKeyProvider struct {
+	*keyRing
+}
+
+var _ KeyProvider = &EnvKeyProvider{}
+
+// NewEnvKeyProvider creates an env key provider with the keys in the given
+// environment variable.
+func NewEnvKeyProvider(envVar string) (*EnvKeyProvider, error) {
+	value := strings.ReplaceAll(os.Getenv(envVar), ",", "\n")
+	ring, err := parseKeyRing(strings.NewReader(value), "the environment variable "+envVar)
+	if err != nil {
+		return nil, err
+	}
+	if ring.currentID == "" {
+		return nil, fmt.Errorf("%w: %s", ErrEmptyKeyEnv, envVar)
+	}
+	return &EnvKeyProvider{keyRing: ring}, nil
+}
+
+// NewEnvKeyProviderFromOptions creates an env key provider with the environment
+// variable specified in the options.
+func NewEnvKeyProviderFromOptions(options map[string]string) (KeyProvider, error) {
+	envVar := options[EnvKeyVarOption]
+	if envVar == "" {
+		envVar = DefaultKeyEnvVar
+	}
+	return NewEnvKeyProvider(envVar)
+}
+
+func (p *EnvKeyProvider) Name() string {
+	return EnvKeyProviderName
+}
@@ -0,0 +1,188 @@
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// envelopePrefix marks the values encrypted by the Encrypter, the version in
+// the prefix allows changing the envelope format later.
+const envelopePrefix = "kusion:enc:v1:"
+
+// dataKeySize is the size of the data keys, which selects AES-256.
+const dataKeySize = 32
+
+// envelope is an encrypted value along with the data key it is encrypted with.
+type envelope struct {
+	// Provider is the name of the key provider wrapping the data key.
+	Provider string `json:"provider"`
+	// KeyID is the ID of the key encryption key wrapping the data key.
+	KeyID string `json:"keyID"`
+	// WrappedKey is the wrapped data key.
+	WrappedKey []byte `json:"wrappedKey"`
+	// Ciphertext is the value sealed by AES-GCM with the data key, prefixed
+	// with the nonce.
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// Encrypter encrypts values with envelope encryption: every value is sealed
+// with its own random data key, and the data key is wrapped by the key
+// provider.
+//
+// The values are decrypted by the key provider recorded in them, so that the
+// values encrypted before switching to another key provider can still be
+// decrypted and rotated.
+type Encrypter struct {
+	provider KeyProvider
+
+	mu         sync.Mutex
+	decrypters map[string]KeyProvider
+}
+
+// NewEncrypter creates an encrypter wrapping the data keys with the given key
+// provider. The others are the key providers decrypting the values encrypted
+// by them, the ones not given are created by their registered factories with
+// no options when needed.
+func NewEncrypter(provider KeyProvider, others ...KeyProvider) *Encrypter {
+	decrypters := map[string]KeyProvider{}
+	for _, p := range others {
+		decrypters[p.Name()] = p
+	}
+	decrypters[provider.Name()] = provider
+	return &Encrypter{provider: provider, decrypters: decrypters}
+}
+
+// IsEncrypted reports whether the value is encrypted by an Encrypter.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, envelopePrefix)
+}
+
+// Encrypt encrypts the plaintext with a new data key wrapped by the current key
+// encryption key.
+func (e *Encrypter) Encrypt(ctx context.Context, plaintext string) (string, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	ciphertext, err := seal(dataKey, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	wrappedKey, err := e.provider.WrapKey(ctx, dataKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to wrap the data key: %w", err)
+	}
+
+	encoded, err := json.Marshal(&envelope{
+		Provider:   e.provider.Name(),
+		KeyID:      e.provider.KeyID(),
+		WrappedKey: wrappedKey,
+		Ciphertext: ciphertext,
+	})
+	if err != nil {
+		return "", err
+	}
+	return envelopePrefix + base64.StdEncoding.EncodeToString(encoded), nil
+}
+
+// Decrypt decrypts a value encrypted by Encrypt.
+func (e *Encrypter) Decrypt(ctx context.Context, value string) (string, error) {
+	env, err := parseEnvelope(value)
+	if err != nil {
+		return "", err
+	}
+	provider, err := e.decrypter(env.Provider)
+	if err != nil {
+		return "", err
+	}
+	dataKey, err := provider.UnwrapKey(ctx, env.KeyID, env.WrappedKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to unwrap the data key: %w", err)
+	}
+	plaintext, err := open(dataKey, env.Ciphertext)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// NeedsRotation reports whether the value is not encrypted yet, or is encrypted
+// by a key encryption key other than the current one.
+func (e *Encrypter) NeedsRotation(value string) bool {
+	env, err := parseEnvelope(value)
+	if err != nil {
+		return true
+	}
+	return env.Provider != e.provider.Name() || env.KeyID != e.provider.KeyID()
+}
+
+// decrypter returns the key provider of the given name to unwrap the data keys.
+func (e *Encrypter) decrypter(name string) (KeyProvider, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if provider, ok := e.decrypters[name]; ok {
+		return provider, nil
+	}
+	provider, err := NewKeyProvider(name, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrProviderMismatch, name, err)
+	}
+	e.decrypters[name] = provider
+	return provider, nil
+}
+
+func parseEnvelope(value string) (*envelope, error) {
+	if !IsEncrypted(value) {
+		return nil, ErrNotEncrypted
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, envelopePrefix))
+	if err != nil {
+		return nil, ErrInvalidEnvelope
+	}
+	var env envelope
+	if err = json.Unmarshal(decoded, &env); err != nil {
+		return nil, ErrInvalidEnvelope
+	}
+	return &env, nil
+}
+
+// seal encrypts the plaintext by AES-GCM and prefixes it with the nonce.
+func seal(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts the ciphertext sealed by seal.
+func open(key, ciphertext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, ErrInvalidEnvelope
+	}
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, sealed, nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
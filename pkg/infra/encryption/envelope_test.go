@@ -0,0 +1,188 @@
+package encryption
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newKeyFile(t *testing.T, lines ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "variable.key")
+	content := ""
+	for _, line := range lines {
+		content += line + "\n"
+	}
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func newKey(t *testing.T) string {
+	t.Helper()
+	key := make([]byte, dataKeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+// fakeKMSClient is a KMS client wrapping the data keys with local keys.
+type fakeKMSClient struct {
+	keys map[string][]byte
+}
+
+func (c *fakeKMSClient) Encrypt(_ context.Context, keyID string, plaintext []byte) ([]byte, error) {
+	return seal(c.keys[keyID], plaintext)
+}
+
+func (c *fakeKMSClient) Decrypt(_ context.Context, keyID string, ciphertext []byte) ([]byte, error) {
+	return open(c.keys[keyID], ciphertext)
+}
+
+func TestEncrypter(t *testing.T) {
+	ctx := context.Background()
+	oldKey, newKeyValue := newKey(t), newKey(t)
+	oldProvider, err := NewLocalKeyProvider(newKeyFile(t, "old:"+oldKey))
+	require.NoError(t, err)
+	rotatedProvider, err := NewLocalKeyProvider(newKeyFile(t, "# rotated", "old:"+oldKey, "new:"+newKeyValue))
+	require.NoError(t, err)
+
+	t.Run("round trip", func(t *testing.T) {
+		e := NewEncrypter(oldProvider)
+		encrypted, err := e.Encrypt(ctx, "s3cr3t")
+		require.NoError(t, err)
+		require.True(t, IsEncrypted(encrypted))
+		require.NotContains(t, encrypted, "s3cr3t")
+
+		// Every value is encrypted with its own data key.
+		another, err := e.Encrypt(ctx, "s3cr3t")
+		require.NoError(t, err)
+		require.NotEqual(t, encrypted, another)
+
+		decrypted, err := e.Decrypt(ctx, encrypted)
+		require.NoError(t, err)
+		require.Equal(t, "s3cr3t", decrypted)
+		require.False(t, e.NeedsRotation(encrypted))
+	})
+
+	t.Run("key rotation", func(t *testing.T) {
+		encrypted, err := NewEncrypter(oldProvider).Encrypt(ctx, "s3cr3t")
+		require.NoError(t, err)
+
+		e := NewEncrypter(rotatedProvider)
+		require.Equal(t, "new", rotatedProvider.KeyID())
+		require.True(t, e.NeedsRotation(encrypted))
+		decrypted, err := e.Decrypt(ctx, encrypted)
+		require.NoError(t, err)
+		require.Equal(t, "s3cr3t", decrypted)
+
+		reencrypted, err := e.Encrypt(ctx, decrypted)
+		require.NoError(t, err)
+		require.False(t, e.NeedsRotation(reencrypted))
+		_, err = NewEncrypter(oldProvider).Decrypt(ctx, reencrypted)
+		require.ErrorIs(t, err, ErrUnknownKey)
+	})
+
+	t.Run("kms", func(t *testing.T) {
+		client := &fakeKMSClient{keys: map[string][]byte{"alias/kusion": make([]byte, dataKeySize)}}
+		e := NewEncrypter(NewKMSKeyProvider("fake-kms", client, "alias/kusion"))
+		encrypted, err := e.Encrypt(ctx, "s3cr3t")
+		require.NoError(t, err)
+		decrypted, err := e.Decrypt(ctx, encrypted)
+		require.NoError(t, err)
+		require.Equal(t, "s3cr3t", decrypted)
+
+		_, err = NewEncrypter(oldProvider).Decrypt(ctx, encrypted)
+		require.ErrorIs(t, err, ErrProviderMismatch)
+	})
+
+	t.Run("provider rotation", func(t *testing.T) {
+		encrypted, err := NewEncrypter(oldProvider).Encrypt(ctx, "s3cr3t")
+		require.NoError(t, err)
+
+		// The values encrypted by another key provider are decrypted by it,
+		// and encrypted again by the current one.
+		client := &fakeKMSClient{keys: map[string][]byte{"alias/kusion": make([]byte, dataKeySize)}}
+		kmsProvider := NewKMSKeyProvider("fake-kms", client, "alias/kusion")
+		e := NewEncrypter(kmsProvider, oldProvider)
+		require.True(t, e.NeedsRotation(encrypted))
+		decrypted, err := e.Decrypt(ctx, encrypted)
+		require.NoError(t, err)
+		require.Equal(t, "s3cr3t", decrypted)
+		reencrypted, err := e.Encrypt(ctx, decrypted)
+		require.NoError(t, err)
+		require.False(t, e.NeedsRotation(reencrypted))
+
+		// The key providers not given are created from the environment.
+		t.Setenv(DefaultKeyEnvVar, "env:"+newKey(t))
+		envProvider, err := NewKeyProvider(EnvKeyProviderName, nil)
+		require.NoError(t, err)
+		encrypted, err = NewEncrypter(envProvider).Encrypt(ctx, "s3cr3t")
+		require.NoError(t, err)
+		decrypted, err = e.Decrypt(ctx, encrypted)
+		require.NoError(t, err)
+		require.Equal(t, "s3cr3t", decrypted)
+	})
+
+	t.Run("not encrypted", func(t *testing.T) {
+		e := NewEncrypter(oldProvider)
+		require.True(t, e.NeedsRotation("s3cr3t"))
+		_, err := e.Decrypt(ctx, "s3cr3t")
+		require.ErrorIs(t, err, ErrNotEncrypted)
+		_, err = e.Decrypt(ctx, envelopePrefix+"!")
+		require.ErrorIs(t, err, ErrInvalidEnvelope)
+	})
+}
+
+func TestNewKeyProvider(t *testing.T) {
+	keyFile := newKeyFile(t, newKey(t))
+	provider, err := NewKeyProvider(LocalKeyProviderName, map[string]string{LocalKeyFileOption: keyFile})
+	require.NoError(t, err)
+	require.Len(t, provider.KeyID(), 16)
+
+	_, err = NewKeyProvider(LocalKeyProviderName, nil)
+	require.Error(t, err)
+	_, err = NewKeyProvider("unknown", nil)
+	require.ErrorIs(t, err, ErrUnknownKeyProvider)
+
+	require.NoError(t, RegisterKeyProvider("test-kms", func(options map[string]string) (KeyProvider, error) {
+		return NewKMSKeyProvider("test-kms", &fakeKMSClient{}, options["keyID"]), nil
+	}))
+	require.ErrorIs(t, RegisterKeyProvider("test-kms", nil), ErrKeyProviderRegistered)
+	provider, err = NewKeyProvider("test-kms", map[string]string{"keyID": "key"})
+	require.NoError(t, err)
+	require.Equal(t, "key", provider.KeyID())
+}
+
+func TestNewLocalKeyProvider(t *testing.T) {
+	_, err := NewLocalKeyProvider(filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+	_, err = NewLocalKeyProvider(newKeyFile(t, "# no keys"))
+	require.ErrorIs(t, err, ErrEmptyKeyFile)
+	_, err = NewLocalKeyProvider(newKeyFile(t, "short:"+base64.StdEncoding.EncodeToString([]byte("short"))))
+	require.ErrorContains(t, err, "line 1")
+}
+
+func TestNewEnvKeyProvider(t *testing.T) {
+	oldKey, currentKey := newKey(t), newKey(t)
+	t.Setenv(DefaultKeyEnvVar, "old:"+oldKey+",current:"+currentKey)
+	provider, err := NewKeyProvider(EnvKeyProviderName, nil)
+	require.NoError(t, err)
+	require.Equal(t, EnvKeyProviderName, provider.Name())
+	require.Equal(t, "current", provider.KeyID())
+
+	t.Setenv("TEST_KUSION_KEY", currentKey)
+	provider, err = NewKeyProvider(EnvKeyProviderName, map[string]string{EnvKeyVarOption: "TEST_KUSION_KEY"})
+	require.NoError(t, err)
+	require.Len(t, provider.KeyID(), 16)
+
+	_, err = NewEnvKeyProvider("TEST_KUSION_MISSING_KEY")
+	require.ErrorIs(t, err, ErrEmptyKeyEnv)
+	t.Setenv("TEST_KUSION_KEY", "invalid")
+	_, err = NewEnvKeyProvider("TEST_KUSION_KEY")
+	require.ErrorContains(t, err, "TEST_KUSION_KEY")
+}
@@ -0,0 +1,51 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"errors"
+)
+
+var ErrEncryptionNotEnabled = errors.New("the file is encrypted but no key provider is configured")
+
+// EncryptFile encrypts the content of a file stored in a backend, such as a
+// release or a workspace file. A nil Encrypter means the encryption is not
+// enabled, and the content is kept as is.
+func (e *Encrypter) EncryptFile(ctx context.Context, content []byte) ([]byte, error) {
+	if e == nil {
+		return content, nil
+	}
+	encrypted, err := e.Encrypt(ctx, string(content))
+	if err != nil {
+		return nil, err
+	}
+	return []byte(encrypted + "\n"), nil
+}
+
+// DecryptFile decrypts the content of a file encrypted by EncryptFile. The
+// content of a file written before the encryption is enabled is returned as is,
+// so that the existing files stay readable until they are encrypted.
+func (e *Encrypter) DecryptFile(ctx context.Context, content []byte) ([]byte, error) {
+	value := string(bytes.TrimSpace(content))
+	if !IsEncrypted(value) {
+		return content, nil
+	}
+	if e == nil {
+		return nil, ErrEncryptionNotEnabled
+	}
+	plaintext, err := e.Decrypt(ctx, value)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(plaintext), nil
+}
+
+// FileNeedsRotation reports whether the file is not encrypted yet, or is
+// encrypted by a key encryption key other than the current one. A nil
+// Encrypter means the encryption is not enabled, and no file needs rotation.
+func (e *Encrypter) FileNeedsRotation(content []byte) bool {
+	if e == nil {
+		return false
+	}
+	return e.NeedsRotation(string(bytes.TrimSpace(content)))
+}
@@ -0,0 +1,43 @@
+package encryption
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncrypter_File(t *testing.T) {
+	ctx := context.Background()
+	content := []byte("revision: 1\nsecret: s3cr3t\n")
+	oldKey := newKey(t)
+	provider, err := NewLocalKeyProvider(newKeyFile(t, "old:"+oldKey))
+	require.NoError(t, err)
+	rotatedProvider, err := NewLocalKeyProvider(newKeyFile(t, "old:"+oldKey, "new:"+newKey(t)))
+	require.NoError(t, err)
+	e := NewEncrypter(provider)
+
+	encrypted, err := e.EncryptFile(ctx, content)
+	require.NoError(t, err)
+	require.NotContains(t, string(encrypted), "s3cr3t")
+	require.False(t, e.FileNeedsRotation(encrypted))
+	require.True(t, NewEncrypter(rotatedProvider).FileNeedsRotation(encrypted))
+
+	decrypted, err := NewEncrypter(rotatedProvider).DecryptFile(ctx, encrypted)
+	require.NoError(t, err)
+	require.Equal(t, content, decrypted)
+
+	// Plain text files are read as is, with or without the encryption.
+	decrypted, err = e.DecryptFile(ctx, content)
+	require.NoError(t, err)
+	require.Equal(t, content, decrypted)
+	require.True(t, e.FileNeedsRotation(content))
+
+	// The encryption is disabled by a nil encrypter.
+	var disabled *Encrypter
+	plain, err := disabled.EncryptFile(ctx, content)
+	require.NoError(t, err)
+	require.Equal(t, content, plain)
+	_, err = disabled.DecryptFile(ctx, encrypted)
+	require.ErrorIs(t, err, ErrEncryptionNotEnabled)
+}
@@ -0,0 +1,59 @@
+package encryption
+
+import (
+	"context"
+)
+
+// KMSClient is the client of a key management service, such as AWS KMS or
+// Alibaba Cloud KMS, encrypting and decrypting small payloads with a key that
+// never leaves the service.
+type KMSClient interface {
+	// Encrypt encrypts the plaintext with the key of the given ID.
+	Encrypt(ctx context.Context, keyID string, plaintext []byte) ([]byte, error)
+	// Decrypt decrypts the ciphertext encrypted with the key of the given ID.
+	Decrypt(ctx context.Context, keyID string, ciphertext []byte) ([]byte, error)
+}
+
+// KMSKeyProvider wraps the data keys with a key managed by a KMS. Rotating the
+// key is done by switching to a new key ID, the previous keys are still used
+// by the KMS to unwrap the data keys wrapped with them.
+//
+// A KMS is plugged in by registering a factory creating a KMSKeyProvider with
+// its client:
+//
+//	encryption.RegisterKeyProvider("aws-kms", func(options map[string]string) (encryption.KeyProvider, error) {
+//		return encryption.NewKMSKeyProvider("aws-kms", newAWSKMSClient(options), options["keyID"]), nil
+//	})
+type KMSKeyProvider struct {
+	name   string
+	client KMSClient
+	keyID  string
+}
+
+var _ KeyProvider = &KMSKeyProvider{}
+
+// NewKMSKeyProvider creates a key provider with the given name, wrapping the
+// data keys with the key of the given ID in the KMS.
+func NewKMSKeyProvider(name string, client KMSClient, keyID string) *KMSKeyProvider {
+	return &KMSKeyProvider{
+		name:   name,
+		client: client,
+		keyID:  keyID,
+	}
+}
+
+func (p *KMSKeyProvider) Name() string {
+	return p.name
+}
+
+func (p *KMSKeyProvider) KeyID() string {
+	return p.keyID
+}
+
+func (p *KMSKeyProvider) WrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	return p.client.Encrypt(ctx, p.keyID, dataKey)
+}
+
+func (p *KMSKeyProvider) UnwrapKey(ctx context.Context, keyID string, wrappedKey []byte) ([]byte, error) {
+	return p.client.Decrypt(ctx, keyID, wrappedKey)
+}
@@ -0,0 +1,131 @@
+package encryption
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// LocalKeyProviderName is the name of the key provider reading the key
+// encryption keys from a local file.
+const LocalKeyProviderName = "local"
+
+// LocalKeyFileOption is the option of the local key provider specifying the
+// path of the key file.
+const LocalKeyFileOption = "keyFile"
+
+var ErrEmptyKeyFile = errors.New("no key found in the key file")
+
+// LocalKeyProvider wraps the data keys with AES keys read from a local file.
+// It is intended for development, production servers are supposed to use a
+// key provider backed by a KMS.
+//
+// Every non-empty line of the key file which is not a comment is a base64
+// encoded 32-byte key, optionally prefixed with its ID and a colon, such as
+// "2024-01:<key>". Keys without an ID are identified by their fingerprint. The
+// last key is used to wrap new data keys, and the previous ones are kept to
+// unwrap the data keys wrapped before a key rotation.
+type LocalKeyProvider struct {
+	*keyRing
+}
+
+var _ KeyProvider = &LocalKeyProvider{}
+
+// NewLocalKeyProvider creates a local key provider with the keys in the given
+// file.
+func NewLocalKeyProvider(keyFile string) (*LocalKeyProvider, error) {
+	file, err := os.Open(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the key file: %w", err)
+	}
+	defer file.Close()
+
+	ring, err := parseKeyRing(file, "the key file")
+	if err != nil {
+		return nil, err
+	}
+	if ring.currentID == "" {
+		return nil, ErrEmptyKeyFile
+	}
+	return &LocalKeyProvider{keyRing: ring}, nil
+}
+
+// NewLocalKeyProviderFromOptions creates a local key provider with the key file
+// specified in the options.
+func NewLocalKeyProviderFromOptions(options map[string]string) (KeyProvider, error) {
+	keyFile := options[LocalKeyFileOption]
+	if keyFile == "" {
+		return nil, fmt.Errorf("the %s option of the %s key provider must be specified", LocalKeyFileOption, LocalKeyProviderName)
+	}
+	return NewLocalKeyProvider(keyFile)
+}
+
+func (p *LocalKeyProvider) Name() string {
+	return LocalKeyProviderName
+}
+
+// keyRing holds the AES keys of the key providers managing the keys by
+// themselves, the last key is the current one.
+type keyRing struct {
+	keys      map[string][]byte
+	currentID string
+}
+
+// parseKeyRing reads the keys line by line, in the format of the key file.
+func parseKeyRing(r io.Reader, source string) (*keyRing, error) {
+	ring := &keyRing{keys: make(map[string][]byte)}
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		id, encoded, found := strings.Cut(line, ":")
+		if !found {
+			id, encoded = "", line
+		}
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil || len(key) != dataKeySize {
+			return nil, fmt.Errorf("invalid key at line %d of %s, a key must be a base64 encoded %d-byte key", lineNum, source, dataKeySize)
+		}
+		id = strings.TrimSpace(id)
+		if id == "" {
+			id = fingerprint(key)
+		}
+		ring.keys[id] = key
+		ring.currentID = id
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ring, nil
+}
+
+func (r *keyRing) KeyID() string {
+	return r.currentID
+}
+
+func (r *keyRing) WrapKey(_ context.Context, dataKey []byte) ([]byte, error) {
+	return seal(r.keys[r.currentID], dataKey)
+}
+
+func (r *keyRing) UnwrapKey(_ context.Context, keyID string, wrappedKey []byte) ([]byte, error) {
+	key, ok := r.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	return open(key, wrappedKey)
+}
+
+// fingerprint returns a short ID derived from the key.
+func fingerprint(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
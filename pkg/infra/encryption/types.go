@@ -0,0 +1,85 @@
+package encryption
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	ErrNotEncrypted          = errors.New("the value is not encrypted")
+	ErrInvalidEnvelope       = errors.New("the encrypted value is malformed")
+	ErrUnknownKey            = errors.New("the key to decrypt the value is not found")
+	ErrProviderMismatch      = errors.New("the value is encrypted by another key provider")
+	ErrUnknownKeyProvider    = errors.New("unknown key provider")
+	ErrKeyProviderRegistered = errors.New("the key provider is already registered")
+)
+
+// KeyProvider manages the key encryption keys of the envelope encryption. The
+// values are encrypted with a random data key, which is in turn encrypted, or
+// wrapped, by the key provider and stored along with the value.
+//
+// Key providers backed by a KMS implement this interface, so that the key
+// encryption keys never leave the KMS.
+type KeyProvider interface {
+	// Name returns the name of the key provider, which is recorded in the
+	// encrypted values.
+	Name() string
+	// KeyID returns the ID of the key encryption key currently used to wrap
+	// the data keys.
+	KeyID() string
+	// WrapKey encrypts the data key with the current key encryption key.
+	WrapKey(ctx context.Context, dataKey []byte) ([]byte, error)
+	// UnwrapKey decrypts the data key wrapped by the key encryption key of
+	// the given ID, which may not be the current one after a key rotation.
+	UnwrapKey(ctx context.Context, keyID string, wrappedKey []byte) ([]byte, error)
+}
+
+// KeyProviderFactory creates a key provider with the options configured for
+// the server.
+type KeyProviderFactory func(options map[string]string) (KeyProvider, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[string]KeyProviderFactory{
+		LocalKeyProviderName: NewLocalKeyProviderFromOptions,
+		EnvKeyProviderName:   NewEnvKeyProviderFromOptions,
+	}
+)
+
+// RegisterKeyProvider registers the factory of a key provider under the given
+// name, so that it can be selected by the server configuration.
+func RegisterKeyProvider(name string, factory KeyProviderFactory) error {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	if _, ok := factories[name]; ok {
+		return fmt.Errorf("%w: %s", ErrKeyProviderRegistered, name)
+	}
+	factories[name] = factory
+	return nil
+}
+
+// NewKeyProvider creates the key provider registered under the given name.
+func NewKeyProvider(name string, options map[string]string) (KeyProvider, error) {
+	factoriesMu.RLock()
+	factory, ok := factories[name]
+	factoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s, supported key providers are %v", ErrUnknownKeyProvider, name, KeyProviders())
+	}
+	return factory(options)
+}
+
+// KeyProviders returns the names of the registered key providers.
+func KeyProviders() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,159 @@
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/Azure/azure-sdk-for-go/storage"
+)
+
+// azureLockSuffix is the suffix of the blobs holding the locks of the objects.
+const azureLockSuffix = ".lock"
+
+// AzureStore is a Store keeping every object in a block blob of an Azure Blob
+// Storage container. An object is locked by creating the blob of its lock
+// exclusively, which holds the JSON LockInfo.
+type AzureStore struct {
+	container *storage.Container
+}
+
+var (
+	_ Store  = &AzureStore{}
+	_ Locker = &AzureStore{}
+)
+
+// NewAzureStore creates an Azure store with the blobs in the given container.
+func NewAzureStore(container *storage.Container) *AzureStore {
+	return &AzureStore{container: container}
+}
+
+func (s *AzureStore) Get(_ context.Context, key string) ([]byte, error) {
+	body, err := s.container.GetBlobReference(key).Get(nil)
+	if isAzureNotFound(err) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+	} else if err != nil {
+		return nil, fmt.Errorf("get blob %s failed: %w", key, err)
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+	return io.ReadAll(body)
+}
+
+func (s *AzureStore) Put(_ context.Context, key string, content []byte) error {
+	if err := s.container.GetBlobReference(key).CreateBlockBlobFromReader(bytes.NewReader(content), nil); err != nil {
+		return fmt.Errorf("put blob %s failed: %w", key, err)
+	}
+	return nil
+}
+
+func (s *AzureStore) Delete(_ context.Context, key string) error {
+	if _, err := s.container.GetBlobReference(key).DeleteIfExists(nil); err != nil {
+		return fmt.Errorf("delete blob %s failed: %w", key, err)
+	}
+	return nil
+}
+
+func (s *AzureStore) List(_ context.Context, prefix string) ([]string, error) {
+	var keys []string
+	params := storage.ListBlobsParameters{Prefix: prefix}
+	for {
+		resp, err := s.container.ListBlobs(params)
+		if err != nil {
+			return nil, fmt.Errorf("list blobs under %s failed: %w", prefix, err)
+		}
+		for _, blob := range resp.Blobs {
+			keys = append(keys, blob.Name)
+		}
+		if resp.NextMarker == "" {
+			break
+		}
+		params.Marker = resp.NextMarker
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (s *AzureStore) Lock(_ context.Context, key string, info *LockInfo) error {
+	content, err := json.Marshal(info)
+	if err != nil {
+		return fmt.Errorf("marshal lock info failed: %w", err)
+	}
+	err = s.container.GetBlobReference(key+azureLockSuffix).
+		CreateBlockBlobFromReader(bytes.NewReader(content), &storage.PutBlobOptions{IfNoneMatch: "*"})
+	if isAzureStatus(err, http.StatusConflict, http.StatusPreconditionFailed) {
+		held, _, _ := s.readLock(key)
+		return lockedError(key, held)
+	} else if err != nil {
+		return fmt.Errorf("create lock blob of %s failed: %w", key, err)
+	}
+	return nil
+}
+
+func (s *AzureStore) Unlock(_ context.Context, key, id string) error {
+	held, etag, err := s.readLock(key)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if held.ID != id {
+		return lockedError(key, held)
+	}
+	// The lock is deleted only if it's not replaced since it's read.
+	_, err = s.container.GetBlobReference(key + azureLockSuffix).DeleteIfExists(&storage.DeleteBlobOptions{IfMatch: etag})
+	if err != nil {
+		return fmt.Errorf("delete lock blob of %s failed: %w", key, err)
+	}
+	return nil
+}
+
+func (s *AzureStore) ForceUnlock(_ context.Context, key string) error {
+	if _, err := s.container.GetBlobReference(key + azureLockSuffix).DeleteIfExists(nil); err != nil {
+		return fmt.Errorf("delete lock blob of %s failed: %w", key, err)
+	}
+	return nil
+}
+
+// readLock returns the info and the ETag of the lock of the object, or
+// ErrNotFound if the object is not locked.
+func (s *AzureStore) readLock(key string) (*LockInfo, string, error) {
+	blob := s.container.GetBlobReference(key + azureLockSuffix)
+	body, err := blob.Get(nil)
+	if isAzureNotFound(err) {
+		return nil, "", fmt.Errorf("%w: %s", ErrNotFound, key+azureLockSuffix)
+	} else if err != nil {
+		return nil, "", fmt.Errorf("get lock blob of %s failed: %w", key, err)
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+	info := &LockInfo{}
+	if err = json.NewDecoder(body).Decode(info); err != nil {
+		return nil, "", fmt.Errorf("decode lock info of %s failed: %w", key, err)
+	}
+	return info, blob.Properties.Etag, nil
+}
+
+func isAzureNotFound(err error) bool {
+	return isAzureStatus(err, http.StatusNotFound)
+}
+
+func isAzureStatus(err error, codes ...int) bool {
+	var serviceErr storage.AzureStorageServiceError
+	if !errors.As(err, &serviceErr) {
+		return false
+	}
+	for _, code := range codes {
+		if serviceErr.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,200 @@
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const (
+	methodLock   = "LOCK"
+	methodUnlock = "UNLOCK"
+)
+
+// HTTPStore is a Store backed by a generic HTTP server, where every object is
+// a resource under the address of the server:
+//
+//   - GET {address}/{key} returns the content of the object, or 404 if the object
+//     does not exist.
+//   - PUT {address}/{key} creates or overwrites the object with the request body.
+//   - DELETE {address}/{key} deletes the object.
+//   - GET {address}/{prefix}/ returns a JSON array of the keys of the objects
+//     under the prefix.
+//   - LOCK {address}/{key} locks the object with the JSON LockInfo as the request
+//     body, and the server responds with 409 or 423, optionally with the JSON
+//     LockInfo of the holder as the response body, if the object is locked by
+//     others.
+//   - UNLOCK {address}/{key} unlocks the object with the JSON LockInfo carrying
+//     the ID of the lock as the request body, and the server responds with 409 or
+//     423 if the object is locked with another ID. UNLOCK {address}/{key}?force=true
+//     unlocks the object whoever holds it.
+//
+// Locking is required to serialize the updates of the metadata files, so the
+// servers not supporting it, which respond with 405 or 501, are rejected.
+type HTTPStore struct {
+	client   *http.Client
+	address  string
+	username string
+	password string
+}
+
+var (
+	_ Store  = &HTTPStore{}
+	_ Locker = &HTTPStore{}
+)
+
+// NewHTTPStore creates a HTTP store with the address of the server, the basic
+// auth is used if the username is not empty.
+func NewHTTPStore(client *http.Client, address, username, password string) *HTTPStore {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPStore{
+		client:   client,
+		address:  strings.TrimSuffix(address, "/"),
+		username: username,
+		password: password,
+	}
+}
+
+func (s *HTTPStore) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return io.ReadAll(resp.Body)
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+	default:
+		return nil, unexpectedStatus(http.MethodGet, key, resp)
+	}
+}
+
+func (s *HTTPStore) Put(ctx context.Context, key string, content []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, key, content)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return nil
+	default:
+		return unexpectedStatus(http.MethodPut, key, resp)
+	}
+}
+
+func (s *HTTPStore) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusAccepted, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return unexpectedStatus(http.MethodDelete, key, resp)
+	}
+}
+
+func (s *HTTPStore) List(ctx context.Context, prefix string) ([]string, error) {
+	dir := strings.TrimSuffix(prefix, "/") + "/"
+	resp, err := s.do(ctx, http.MethodGet, dir, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, unexpectedStatus(http.MethodGet, dir, resp)
+	}
+	var keys []string
+	if err = json.NewDecoder(resp.Body).Decode(&keys); err != nil {
+		return nil, fmt.Errorf("decode the keys under %s failed: %w", dir, err)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (s *HTTPStore) Lock(ctx context.Context, key string, info *LockInfo) error {
+	return s.lock(ctx, methodLock, key, "", info)
+}
+
+func (s *HTTPStore) Unlock(ctx context.Context, key, id string) error {
+	return s.lock(ctx, methodUnlock, key, "", &LockInfo{ID: id})
+}
+
+func (s *HTTPStore) ForceUnlock(ctx context.Context, key string) error {
+	return s.lock(ctx, methodUnlock, key, "?force=true", nil)
+}
+
+func (s *HTTPStore) lock(ctx context.Context, method, key, query string, info *LockInfo) error {
+	var body []byte
+	if info != nil {
+		var err error
+		if body, err = json.Marshal(info); err != nil {
+			return fmt.Errorf("marshal lock info failed: %w", err)
+		}
+	}
+	resp, err := s.do(ctx, method, key+query, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return nil
+	case http.StatusConflict, http.StatusLocked:
+		// The holder of the lock is optional in the response.
+		held := &LockInfo{}
+		if err = json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(held); err != nil {
+			held = nil
+		}
+		return lockedError(key, held)
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return fmt.Errorf("%s %s failed: the server does not support locking, which is required by the HTTP backend", method, key)
+	default:
+		return unexpectedStatus(method, key, resp)
+	}
+}
+
+func (s *HTTPStore) do(ctx context.Context, method, key string, body []byte) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.address+"/"+strings.TrimPrefix(key, "/"), reader)
+	if err != nil {
+		return nil, err
+	}
+	if s.username != "" {
+		req.SetBasicAuth(s.username, s.password)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s failed: %w", method, key, err)
+	}
+	return resp, nil
+}
+
+func unexpectedStatus(method, key string, resp *http.Response) error {
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("%s %s failed with status %s: %s", method, key, resp.Status, strings.TrimSpace(string(msg)))
+}
@@ -0,0 +1,131 @@
+package objectstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newHTTPServer serves the objects of a memory store with the protocol of the
+// HTTPStore.
+func newHTTPServer(t *testing.T, store *MemoryStore) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, _ := r.BasicAuth(); user != "kusion" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		key := strings.TrimPrefix(r.URL.Path, "/state/")
+		var info LockInfo
+		if r.Method == methodLock || r.Method == methodUnlock {
+			_ = json.NewDecoder(r.Body).Decode(&info)
+		}
+		var err error
+		switch r.Method {
+		case http.MethodGet:
+			if strings.HasSuffix(key, "/") {
+				keys, _ := store.List(r.Context(), key)
+				_ = json.NewEncoder(w).Encode(keys)
+				return
+			}
+			var content []byte
+			if content, err = store.Get(r.Context(), key); err == nil {
+				_, _ = w.Write(content)
+				return
+			}
+		case http.MethodPut:
+			content, _ := io.ReadAll(r.Body)
+			err = store.Put(r.Context(), key, content)
+		case http.MethodDelete:
+			err = store.Delete(r.Context(), key)
+		case methodLock:
+			if err = store.Lock(r.Context(), key, &info); errors.Is(err, ErrLocked) {
+				store.mu.Lock()
+				held := store.locks[key]
+				store.mu.Unlock()
+				w.WriteHeader(http.StatusLocked)
+				_ = json.NewEncoder(w).Encode(held)
+				return
+			}
+		case methodUnlock:
+			if r.URL.Query().Get("force") == "true" {
+				err = store.ForceUnlock(r.Context(), key)
+			} else {
+				err = store.Unlock(r.Context(), key, info.ID)
+			}
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		switch {
+		case errors.Is(err, ErrNotFound):
+			w.WriteHeader(http.StatusNotFound)
+		case errors.Is(err, ErrLocked):
+			w.WriteHeader(http.StatusLocked)
+		case err != nil:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPStore(t *testing.T) {
+	ctx := context.Background()
+	server := newHTTPServer(t, NewMemoryStore())
+	s := NewHTTPStore(server.Client(), server.URL+"/state/", "kusion", "secret")
+
+	_, err := s.Get(ctx, "releases/foo/dev/1.yaml")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, s.Put(ctx, "releases/foo/dev/1.yaml", []byte("revision: 1")))
+	require.NoError(t, s.Put(ctx, "releases/foo/prod/1.yaml", []byte("revision: 1")))
+	require.NoError(t, s.Put(ctx, "workspaces/dev.yaml", []byte("name: dev")))
+	content, err := s.Get(ctx, "releases/foo/dev/1.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "revision: 1", string(content))
+
+	keys, err := s.List(ctx, "releases")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"releases/foo/dev/1.yaml", "releases/foo/prod/1.yaml"}, keys)
+
+	require.NoError(t, s.Delete(ctx, "releases/foo/prod/1.yaml"))
+	require.NoError(t, s.Delete(ctx, "releases/foo/prod/1.yaml"))
+	keys, err = s.List(ctx, "releases/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"releases/foo/dev/1.yaml"}, keys)
+
+	// The lock is exclusive until it's released by its holder.
+	info := NewLockInfo()
+	require.NoError(t, s.Lock(ctx, "releases/foo/dev/.metadata.yml", info))
+	err = s.Lock(ctx, "releases/foo/dev/.metadata.yml", NewLockInfo())
+	require.ErrorIs(t, err, ErrLocked)
+	assert.ErrorContains(t, err, info.ID)
+	require.ErrorIs(t, s.Unlock(ctx, "releases/foo/dev/.metadata.yml", "other"), ErrLocked)
+	require.NoError(t, s.Unlock(ctx, "releases/foo/dev/.metadata.yml", info.ID))
+	require.NoError(t, WithLock(ctx, s, "releases/foo/dev/.metadata.yml", func() error { return nil }))
+
+	// The lock left by others is released by force.
+	require.NoError(t, s.Lock(ctx, "releases/foo/dev/.metadata.yml", NewLockInfo()))
+	require.NoError(t, s.ForceUnlock(ctx, "releases/foo/dev/.metadata.yml"))
+	require.NoError(t, s.Lock(ctx, "releases/foo/dev/.metadata.yml", info))
+
+	// The servers not supporting locking are rejected.
+	unsupported := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	defer unsupported.Close()
+	err = WithLock(ctx, NewHTTPStore(unsupported.Client(), unsupported.URL, "", ""), "workspaces/dev.yaml", func() error { return nil })
+	assert.ErrorContains(t, err, "does not support locking")
+
+	// The requests without the credentials are rejected.
+	_, err = NewHTTPStore(server.Client(), server.URL+"/state", "", "").Get(ctx, "workspaces/dev.yaml")
+	assert.ErrorContains(t, err, "401")
+}
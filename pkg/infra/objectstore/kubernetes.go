@@ -0,0 +1,316 @@
+package objectstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// KubernetesSecretType is the type of the Secrets storing the objects.
+	KubernetesSecretType corev1.SecretType = "kusion.io/backend-object"
+	// KubernetesChunkSecretType is the type of the Secrets storing the chunks
+	// of the objects too large for a single Secret.
+	KubernetesChunkSecretType corev1.SecretType = "kusion.io/backend-object-chunk"
+	// KubernetesLockSecretType is the type of the Secrets holding the locks of
+	// the objects.
+	KubernetesLockSecretType corev1.SecretType = "kusion.io/backend-lock"
+	// KubernetesKeyAnnotation is the annotation of the Secrets recording the
+	// key of the object.
+	KubernetesKeyAnnotation = "kusion.io/object-key"
+	// KubernetesChunksAnnotation is the annotation of the Secrets recording the
+	// number of the chunks of the object, and the generation of the chunks.
+	KubernetesChunksAnnotation = "kusion.io/object-chunks"
+	// KubernetesManagedByLabel is the label selecting the Secrets storing the
+	// objects.
+	KubernetesManagedByLabel = "app.kubernetes.io/managed-by"
+
+	kubernetesManagedBy  = "kusion"
+	kubernetesContentKey = "content"
+	kubernetesLockKey    = "lock"
+)
+
+// kubernetesChunkSize is the maximum size of the content kept in a Secret,
+// which leaves room in the 1 MiB limit of a Secret for the rest of it.
+var kubernetesChunkSize = 1000 * 1024
+
+// KubernetesStore is a Store keeping every object in a Secret of the namespace,
+// so that the workspaces, releases and graphs live inside the cluster. A Secret
+// holds at most 1 MiB of data, so the objects larger than kubernetesChunkSize
+// are split into chunks, the first one is kept in the Secret of the object and
+// the rest in the chunk Secrets. The chunk Secrets are named after the hash of
+// the content, so that a new content never overwrites the chunks of the old
+// one before the Secret of the object is switched to it.
+//
+// The Secrets are named after the hash of the keys, since the keys are not
+// valid object names, and the keys are recorded in the annotations. An object
+// is locked by creating the lock Secret of the object exclusively, which holds
+// the JSON LockInfo.
+type KubernetesStore struct {
+	client    kubernetes.Interface
+	namespace string
+}
+
+var (
+	_ Store  = &KubernetesStore{}
+	_ Locker = &KubernetesStore{}
+)
+
+// NewKubernetesStore creates a Kubernetes store with the Secrets in the given
+// namespace.
+func NewKubernetesStore(client kubernetes.Interface, namespace string) *KubernetesStore {
+	return &KubernetesStore{client: client, namespace: namespace}
+}
+
+func (s *KubernetesStore) Get(ctx context.Context, key string) ([]byte, error) {
+	secrets := s.client.CoreV1().Secrets(s.namespace)
+	secret, err := secrets.Get(ctx, secretName(key), metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+	} else if err != nil {
+		return nil, fmt.Errorf("get secret of %s failed: %w", key, err)
+	}
+
+	content := secret.Data[kubernetesContentKey]
+	chunks, generation, err := parseChunks(secret)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secret of %s: %w", key, err)
+	}
+	for i := 1; i < chunks; i++ {
+		chunk, err := secrets.Get(ctx, chunkSecretName(key, generation, i), metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("get chunk %d of %s failed: %w", i, key, err)
+		}
+		content = append(content, chunk.Data[kubernetesContentKey]...)
+	}
+	return content, nil
+}
+
+func (s *KubernetesStore) Put(ctx context.Context, key string, content []byte) error {
+	secrets := s.client.CoreV1().Secrets(s.namespace)
+
+	// Write the chunks except the first one before switching the Secret of the
+	// object to them.
+	chunks := splitChunks(content)
+	generation := ""
+	if len(chunks) > 1 {
+		sum := sha256.Sum256(content)
+		generation = hex.EncodeToString(sum[:8])
+	}
+	for i := 1; i < len(chunks); i++ {
+		chunk := newSecret(chunkSecretName(key, generation, i), s.namespace, key, KubernetesChunkSecretType)
+		chunk.Data = map[string][]byte{kubernetesContentKey: chunks[i]}
+		if _, err := secrets.Create(ctx, chunk, metav1.CreateOptions{}); k8serrors.IsAlreadyExists(err) {
+			// The same content is written before.
+			continue
+		} else if err != nil {
+			return fmt.Errorf("create chunk %d of %s failed: %w", i, key, err)
+		}
+	}
+
+	secret, err := secrets.Get(ctx, secretName(key), metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		secret = newSecret(secretName(key), s.namespace, key, KubernetesSecretType)
+		setChunks(secret, chunks, generation)
+		if _, err = secrets.Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("create secret of %s failed: %w", key, err)
+		}
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("get secret of %s failed: %w", key, err)
+	}
+
+	// The update is rejected if the Secret is modified since it's read.
+	oldChunks, oldGeneration, _ := parseChunks(secret)
+	setChunks(secret, chunks, generation)
+	if _, err = secrets.Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("update secret of %s failed: %w", key, err)
+	}
+	if oldGeneration != generation {
+		return s.deleteChunks(ctx, key, oldGeneration, oldChunks)
+	}
+	return nil
+}
+
+func (s *KubernetesStore) Delete(ctx context.Context, key string) error {
+	secrets := s.client.CoreV1().Secrets(s.namespace)
+	secret, err := secrets.Get(ctx, secretName(key), metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("get secret of %s failed: %w", key, err)
+	}
+
+	err = secrets.Delete(ctx, secretName(key), metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("delete secret of %s failed: %w", key, err)
+	}
+	chunks, generation, _ := parseChunks(secret)
+	return s.deleteChunks(ctx, key, generation, chunks)
+}
+
+func (s *KubernetesStore) List(ctx context.Context, prefix string) ([]string, error) {
+	secrets, err := s.client.CoreV1().Secrets(s.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: KubernetesManagedByLabel + "=" + kubernetesManagedBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list secrets failed: %w", err)
+	}
+	var keys []string
+	for _, secret := range secrets.Items {
+		if secret.Type != KubernetesSecretType {
+			continue
+		}
+		if key := secret.Annotations[KubernetesKeyAnnotation]; strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (s *KubernetesStore) Lock(ctx context.Context, key string, info *LockInfo) error {
+	content, err := json.Marshal(info)
+	if err != nil {
+		return fmt.Errorf("marshal lock info failed: %w", err)
+	}
+	lock := newSecret(lockSecretName(key), s.namespace, key, KubernetesLockSecretType)
+	lock.Data = map[string][]byte{kubernetesLockKey: content}
+	_, err = s.client.CoreV1().Secrets(s.namespace).Create(ctx, lock, metav1.CreateOptions{})
+	if k8serrors.IsAlreadyExists(err) {
+		held, _, _ := s.readLock(ctx, key)
+		return lockedError(key, held)
+	} else if err != nil {
+		return fmt.Errorf("create lock secret of %s failed: %w", key, err)
+	}
+	return nil
+}
+
+func (s *KubernetesStore) Unlock(ctx context.Context, key, id string) error {
+	held, resourceVersion, err := s.readLock(ctx, key)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if held.ID != id {
+		return lockedError(key, held)
+	}
+
+	// The lock is deleted only if it's not replaced since it's read.
+	err = s.client.CoreV1().Secrets(s.namespace).Delete(ctx, lockSecretName(key), metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{ResourceVersion: &resourceVersion},
+	})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("delete lock secret of %s failed: %w", key, err)
+	}
+	return nil
+}
+
+func (s *KubernetesStore) ForceUnlock(ctx context.Context, key string) error {
+	err := s.client.CoreV1().Secrets(s.namespace).Delete(ctx, lockSecretName(key), metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("delete lock secret of %s failed: %w", key, err)
+	}
+	return nil
+}
+
+// readLock returns the info and the resource version of the lock of the
+// object.
+func (s *KubernetesStore) readLock(ctx context.Context, key string) (*LockInfo, string, error) {
+	lock, err := s.client.CoreV1().Secrets(s.namespace).Get(ctx, lockSecretName(key), metav1.GetOptions{})
+	if err != nil {
+		return nil, "", err
+	}
+	info := &LockInfo{}
+	if err = json.Unmarshal(lock.Data[kubernetesLockKey], info); err != nil {
+		return nil, "", fmt.Errorf("decode lock info of %s failed: %w", key, err)
+	}
+	return info, lock.ResourceVersion, nil
+}
+
+// deleteChunks deletes the chunk Secrets of the generation of the object.
+func (s *KubernetesStore) deleteChunks(ctx context.Context, key, generation string, chunks int) error {
+	for i := 1; i < chunks; i++ {
+		err := s.client.CoreV1().Secrets(s.namespace).Delete(ctx, chunkSecretName(key, generation, i), metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("delete chunk %d of %s failed: %w", i, key, err)
+		}
+	}
+	return nil
+}
+
+// newSecret returns a Secret of the object of the key.
+func newSecret(name, namespace, key string, secretType corev1.SecretType) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Labels:      map[string]string{KubernetesManagedByLabel: kubernetesManagedBy},
+			Annotations: map[string]string{KubernetesKeyAnnotation: key},
+		},
+		Type: secretType,
+	}
+}
+
+// splitChunks splits the content into the chunks of kubernetesChunkSize.
+func splitChunks(content []byte) [][]byte {
+	chunks := [][]byte{content[:min(len(content), kubernetesChunkSize)]}
+	for start := kubernetesChunkSize; start < len(content); start += kubernetesChunkSize {
+		chunks = append(chunks, content[start:min(len(content), start+kubernetesChunkSize)])
+	}
+	return chunks
+}
+
+// setChunks sets the first chunk and the chunks annotation of the Secret of
+// the object.
+func setChunks(secret *corev1.Secret, chunks [][]byte, generation string) {
+	secret.Data = map[string][]byte{kubernetesContentKey: chunks[0]}
+	if len(chunks) > 1 {
+		secret.Annotations[KubernetesChunksAnnotation] = fmt.Sprintf("%d/%s", len(chunks), generation)
+	} else {
+		delete(secret.Annotations, KubernetesChunksAnnotation)
+	}
+}
+
+// parseChunks returns the number and the generation of the chunks of the
+// Secret of the object, which is 1 if the object is not split.
+func parseChunks(secret *corev1.Secret) (int, string, error) {
+	annotation, ok := secret.Annotations[KubernetesChunksAnnotation]
+	if !ok {
+		return 1, "", nil
+	}
+	count, generation, _ := strings.Cut(annotation, "/")
+	chunks, err := strconv.Atoi(count)
+	if err != nil || chunks < 1 || generation == "" {
+		return 0, "", fmt.Errorf("invalid annotation %s: %s", KubernetesChunksAnnotation, annotation)
+	}
+	return chunks, generation, nil
+}
+
+// secretName returns the name of the Secret storing the object of the key.
+func secretName(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return "kusion-" + hex.EncodeToString(sum[:20])
+}
+
+// chunkSecretName returns the name of the Secret storing the chunk of the
+// generation of the object.
+func chunkSecretName(key, generation string, i int) string {
+	return fmt.Sprintf("%s-%s-%d", secretName(key), generation, i)
+}
+
+// lockSecretName returns the name of the Secret holding the lock of the object.
+func lockSecretName(key string) string {
+	return secretName(key) + "-lock"
+}
@@ -0,0 +1,103 @@
+package objectstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestKubernetesStore(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	s := NewKubernetesStore(client, "kusion")
+
+	_, err := s.Get(ctx, "releases/foo/dev/1.yaml")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, s.Put(ctx, "releases/foo/dev/1.yaml", []byte("revision: 1")))
+	require.NoError(t, s.Put(ctx, "releases/foo/dev/1.yaml", []byte("revision: 1\nphase: succeeded")))
+	require.NoError(t, s.Put(ctx, "workspaces/dev.yaml", []byte("name: dev")))
+	content, err := s.Get(ctx, "releases/foo/dev/1.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "revision: 1\nphase: succeeded", string(content))
+
+	secret, err := client.CoreV1().Secrets("kusion").Get(ctx, secretName("workspaces/dev.yaml"), metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, KubernetesSecretType, secret.Type)
+	assert.Equal(t, "workspaces/dev.yaml", secret.Annotations[KubernetesKeyAnnotation])
+
+	keys, err := s.List(ctx, "releases/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"releases/foo/dev/1.yaml"}, keys)
+
+	require.NoError(t, s.Delete(ctx, "releases/foo/dev/1.yaml"))
+	require.NoError(t, s.Delete(ctx, "releases/foo/dev/1.yaml"))
+	_, err = s.Get(ctx, "releases/foo/dev/1.yaml")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestKubernetesStoreChunks(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	s := NewKubernetesStore(client, "kusion")
+	chunkSize := kubernetesChunkSize
+	kubernetesChunkSize = 4
+	defer func() { kubernetesChunkSize = chunkSize }()
+
+	countSecrets := func() int {
+		secrets, err := client.CoreV1().Secrets("kusion").List(ctx, metav1.ListOptions{})
+		require.NoError(t, err)
+		return len(secrets.Items)
+	}
+
+	require.NoError(t, s.Put(ctx, "releases/foo/dev/1.yaml", []byte("revision: 1")))
+	assert.Equal(t, 3, countSecrets())
+	content, err := s.Get(ctx, "releases/foo/dev/1.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "revision: 1", string(content))
+
+	// The chunks of the old content are replaced.
+	require.NoError(t, s.Put(ctx, "releases/foo/dev/1.yaml", []byte("revision: 1\nphase: succeeded")))
+	assert.Equal(t, 7, countSecrets())
+	content, err = s.Get(ctx, "releases/foo/dev/1.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "revision: 1\nphase: succeeded", string(content))
+
+	require.NoError(t, s.Put(ctx, "releases/foo/dev/1.yaml", []byte("rev")))
+	assert.Equal(t, 1, countSecrets())
+
+	// The chunks are not listed, and deleted along with the object.
+	require.NoError(t, s.Put(ctx, "releases/foo/dev/1.yaml", []byte("revision: 1")))
+	keys, err := s.List(ctx, "releases/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"releases/foo/dev/1.yaml"}, keys)
+	require.NoError(t, s.Delete(ctx, "releases/foo/dev/1.yaml"))
+	assert.Equal(t, 0, countSecrets())
+}
+
+func TestKubernetesStoreLock(t *testing.T) {
+	ctx := context.Background()
+	s := NewKubernetesStore(fake.NewSimpleClientset(), "kusion")
+
+	info := NewLockInfo()
+	require.NoError(t, s.Lock(ctx, ".metadata.yml", info))
+	err := s.Lock(ctx, ".metadata.yml", NewLockInfo())
+	require.ErrorIs(t, err, ErrLocked)
+	assert.ErrorContains(t, err, info.ID)
+	require.ErrorIs(t, s.Unlock(ctx, ".metadata.yml", "other"), ErrLocked)
+	require.NoError(t, s.Unlock(ctx, ".metadata.yml", info.ID))
+	require.NoError(t, s.Unlock(ctx, ".metadata.yml", info.ID))
+
+	// The lock is not listed as an object.
+	require.NoError(t, s.Lock(ctx, ".metadata.yml", NewLockInfo()))
+	keys, err := s.List(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	require.NoError(t, s.ForceUnlock(ctx, ".metadata.yml"))
+	require.NoError(t, WithLock(ctx, s, ".metadata.yml", func() error { return nil }))
+}
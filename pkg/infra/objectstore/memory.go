@@ -0,0 +1,94 @@
+package objectstore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MemoryStore is a Store keeping the objects in memory, which is used in tests
+// and as the reference of the Store semantics.
+type MemoryStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	locks   map[string]*LockInfo
+}
+
+var (
+	_ Store  = &MemoryStore{}
+	_ Locker = &MemoryStore{}
+)
+
+// NewMemoryStore creates an empty memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		objects: make(map[string][]byte),
+		locks:   make(map[string]*LockInfo),
+	}
+}
+
+func (s *MemoryStore) Get(_ context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	content, ok := s.objects[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	return append([]byte(nil), content...), nil
+}
+
+func (s *MemoryStore) Put(_ context.Context, key string, content []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[key] = append([]byte(nil), content...)
+	return nil
+}
+
+func (s *MemoryStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, key)
+	return nil
+}
+
+func (s *MemoryStore) List(_ context.Context, prefix string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var keys []string
+	for key := range s.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (s *MemoryStore) Lock(_ context.Context, key string, info *LockInfo) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if held, ok := s.locks[key]; ok {
+		return lockedError(key, held)
+	}
+	s.locks[key] = info
+	return nil
+}
+
+func (s *MemoryStore) Unlock(_ context.Context, key, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if held, ok := s.locks[key]; ok && held.ID != id {
+		return lockedError(key, held)
+	}
+	delete(s.locks, key)
+	return nil
+}
+
+func (s *MemoryStore) ForceUnlock(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.locks, key)
+	return nil
+}
@@ -0,0 +1,104 @@
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNotFound = errors.New("object not found")
+	ErrLocked   = errors.New("object is locked")
+)
+
+// Store is a flat store of objects addressed by slash-separated keys, such as
+// "releases/foo/dev/1.yaml". It backs the storages of the backends which keep
+// the files in a generic object store, such as the Azure Blob Storage, a HTTP
+// server or a Kubernetes cluster.
+type Store interface {
+	// Get returns the content of the object, or ErrNotFound if the object does
+	// not exist.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Put creates or overwrites the object.
+	Put(ctx context.Context, key string, content []byte) error
+	// Delete deletes the object, it's not an error if the object does not exist.
+	Delete(ctx context.Context, key string) error
+	// List returns the keys of all the objects under the prefix in lexical order.
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// LockInfo identifies the holder of the lock of an object.
+type LockInfo struct {
+	// ID is the unique ID of the lock, which is required to unlock the object.
+	ID string `json:"id"`
+	// Owner is the user and host holding the lock, such as "alice@laptop".
+	Owner string `json:"owner,omitempty"`
+	// Created is the time the lock is created.
+	Created time.Time `json:"created"`
+}
+
+// NewLockInfo creates the info of a new lock held by the current user and host.
+func NewLockInfo() *LockInfo {
+	owner := "unknown"
+	if u, err := user.Current(); err == nil {
+		owner = u.Username
+	}
+	if host, err := os.Hostname(); err == nil {
+		owner += "@" + host
+	}
+	return &LockInfo{
+		ID:      uuid.NewString(),
+		Owner:   owner,
+		Created: time.Now().UTC(),
+	}
+}
+
+func (i *LockInfo) String() string {
+	return fmt.Sprintf("lock %s held by %s since %s", i.ID, i.Owner, i.Created.Format(time.RFC3339))
+}
+
+// Locker is implemented by the stores supporting locking an object, which is
+// used to serialize the read-modify-write of the metadata files.
+type Locker interface {
+	// Lock locks the object with the lock info, or returns ErrLocked if it's
+	// locked by others.
+	Lock(ctx context.Context, key string, info *LockInfo) error
+	// Unlock releases the lock of the object with the ID, or returns ErrLocked
+	// if it's locked by others. It's not an error if the object is not locked.
+	Unlock(ctx context.Context, key, id string) error
+	// ForceUnlock releases the lock of the object whoever holds it, which is
+	// used to release the lock left by a crashed process.
+	ForceUnlock(ctx context.Context, key string) error
+}
+
+// WithLock runs fn with the object locked if the store supports locking.
+func WithLock(ctx context.Context, store Store, key string, fn func() error) (err error) {
+	locker, ok := store.(Locker)
+	if !ok {
+		return fn()
+	}
+	info := NewLockInfo()
+	if err = locker.Lock(ctx, key, info); err != nil {
+		return err
+	}
+	defer func() {
+		if unlockErr := locker.Unlock(ctx, key, info.ID); unlockErr != nil && err == nil {
+			err = unlockErr
+		}
+	}()
+	return fn()
+}
+
+// lockedError returns the ErrLocked of the object, with the holder of the lock
+// if known.
+func lockedError(key string, info *LockInfo) error {
+	if info == nil || info.ID == "" {
+		return fmt.Errorf("%w: %s", ErrLocked, key)
+	}
+	return fmt.Errorf("%w: %s, %s", ErrLocked, key, info)
+}
@@ -5,7 +5,6 @@ import (
 	"context"
 	"testing"
 
-	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 
@@ -15,145 +14,107 @@ import (
 )
 
 func TestBackendRepository(t *testing.T) {
-	t.Run("Create", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewBackendRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var (
-			expectedID, expectedRows uint = 1, 1
-			actual                        = entity.Backend{
-				Name: "mockedBackend",
-				BackendConfig: v1.BackendConfig{
-					Type: v1.BackendTypeS3,
-					Configs: map[string]any{
-						"accessKeyID": "mockedAccessKeyID",
-						"secretKeyID": "mockedSecretKeyID",
-					},
+	ctx := context.Background()
+	newBackend := func(name string) *entity.Backend {
+		return &entity.Backend{
+			Name: name,
+			BackendConfig: v1.BackendConfig{
+				Type: v1.BackendTypeS3,
+				Configs: map[string]any{
+					"accessKeyID": "mockedAccessKeyID",
+					"secretKeyID": "mockedSecretKeyID",
 				},
-			}
-		)
-		sqlMock.ExpectBegin()
-		sqlMock.ExpectExec("INSERT").
-			WillReturnResult(sqlmock.NewResult(int64(expectedID), int64(expectedRows)))
-		sqlMock.ExpectCommit()
-		err = repo.Create(context.Background(), &actual)
+			},
+		}
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		db := GetSQLiteDB(t)
+		repo := NewBackendRepository(db)
+		defer CloseDB(t, db)
+
+		actual := newBackend("mockedBackend")
+		err := repo.Create(ctx, actual)
 		require.NoError(t, err)
-		require.Equal(t, expectedID, actual.ID)
+		require.Equal(t, uint(1), actual.ID)
 	})
 
 	t.Run("Delete existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewBackendRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var expectedID uint = 1
-		sqlMock.ExpectBegin()
-		sqlMock.ExpectQuery("SELECT").
-			WillReturnRows(sqlmock.NewRows([]string{"id"}).
-				AddRow(1))
-		sqlMock.ExpectExec("DELETE").WillReturnResult(sqlmock.NewResult(int64(expectedID), int64(0)))
-		sqlMock.ExpectCommit()
-		err = repo.Delete(context.Background(), expectedID)
+		db := GetSQLiteDB(t)
+		repo := NewBackendRepository(db)
+		defer CloseDB(t, db)
+
+		backend := newBackend("mockedBackend")
+		require.NoError(t, repo.Create(ctx, backend))
+		err := repo.Delete(ctx, backend.ID)
 		require.NoError(t, err)
+		_, err = repo.Get(ctx, backend.ID)
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
 	})
 
 	t.Run("Delete not existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewBackendRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		sqlMock.ExpectBegin()
-		sqlMock.ExpectQuery("SELECT").
-			WillReturnRows(sqlmock.NewRows([]string{"id"}))
-		err = repo.Delete(context.Background(), 1)
+		db := GetSQLiteDB(t)
+		repo := NewBackendRepository(db)
+		defer CloseDB(t, db)
+
+		err := repo.Delete(ctx, 1)
 		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
 	})
 
 	t.Run("Update existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
+		db := GetSQLiteDB(t)
+		repo := NewBackendRepository(db)
+		defer CloseDB(t, db)
+
+		backend := newBackend("mockedBackend")
+		require.NoError(t, repo.Create(ctx, backend))
+		backend.Description = "updatedDescription"
+		backend.BackendConfig.Type = v1.BackendTypeOss
+		err := repo.Update(ctx, backend)
 		require.NoError(t, err)
-		repo := NewBackendRepository(fakeGDB)
-
-		var (
-			expectedID, expectedRows uint = 1, 1
-			actual                        = entity.Backend{
-				ID: 1,
-			}
-		)
-		sqlMock.ExpectExec("UPDATE").
-			WillReturnResult(sqlmock.NewResult(int64(expectedID), int64(expectedRows)))
-		err = repo.Update(context.Background(), &actual)
+		updated, err := repo.Get(ctx, backend.ID)
 		require.NoError(t, err)
+		require.Equal(t, "updatedDescription", updated.Description)
+		require.Equal(t, v1.BackendTypeOss, updated.BackendConfig.Type)
 	})
 
 	t.Run("Update not existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewBackendRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
+		db := GetSQLiteDB(t)
+		repo := NewBackendRepository(db)
+		defer CloseDB(t, db)
 
 		actual := entity.Backend{
 			Name: "NonExistentBackend",
 		}
-		err = repo.Update(context.Background(), &actual)
+		err := repo.Update(ctx, &actual)
 		require.ErrorIs(t, err, gorm.ErrMissingWhereClause)
 	})
 
 	t.Run("Get", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewBackendRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var (
-			expectedID   uint = 1
-			expectedName      = "mockedBackend"
-		)
-		sqlMock.ExpectQuery("SELECT .* FROM `backend`").
-			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
-				AddRow(expectedID, expectedName))
-
-		actual, err := repo.Get(context.Background(), expectedID)
+		db := GetSQLiteDB(t)
+		repo := NewBackendRepository(db)
+		defer CloseDB(t, db)
+
+		backend := newBackend("mockedBackend")
+		require.NoError(t, repo.Create(ctx, backend))
+
+		actual, err := repo.Get(ctx, backend.ID)
 		require.NoError(t, err)
-		require.Equal(t, expectedID, actual.ID)
-		require.Equal(t, expectedName, actual.Name)
+		require.Equal(t, backend.ID, actual.ID)
+		require.Equal(t, "mockedBackend", actual.Name)
+		require.Equal(t, backend.BackendConfig, actual.BackendConfig)
 	})
 
 	t.Run("List", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewBackendRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var (
-			expectedIDFirst    uint = 1
-			expectedNameFirst       = "mockedBackend"
-			expectedIDSecond   uint = 2
-			expectedNameSecond      = "mockedBackend2"
-		)
-
-		sqlMock.ExpectQuery("SELECT count(.*) FROM `backend`").
-			WillReturnRows(
-				sqlmock.NewRows([]string{"count"}).
-					AddRow(2))
-
-		sqlMock.ExpectQuery("SELECT .* FROM `backend`").
-			WillReturnRows(
-				sqlmock.NewRows([]string{"id", "name"}).
-					AddRow(expectedIDFirst, expectedNameFirst).
-					AddRow(expectedIDSecond, expectedNameSecond))
-
-		actual, err := repo.List(context.Background(), &entity.BackendFilter{
+		db := GetSQLiteDB(t)
+		repo := NewBackendRepository(db)
+		defer CloseDB(t, db)
+
+		for _, name := range []string{"mockedBackend", "mockedBackend2"} {
+			require.NoError(t, repo.Create(ctx, newBackend(name)))
+		}
+
+		actual, err := repo.List(ctx, &entity.BackendFilter{
 			Pagination: &entity.Pagination{
 				Page:     constant.CommonPageDefault,
 				PageSize: constant.CommonPageSizeDefault,
@@ -163,5 +124,6 @@ func TestBackendRepository(t *testing.T) {
 		})
 		require.NoError(t, err)
 		require.Len(t, actual.Backends, 2)
+		require.Equal(t, 2, actual.Total)
 	})
 }
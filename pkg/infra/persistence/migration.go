@@ -0,0 +1,90 @@
+package persistence
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SchemaMigrationModel records a schema migration applied to the database.
+type SchemaMigrationModel struct {
+	// Version is the unique version of the migration.
+	Version string `gorm:"primaryKey"`
+	// Description is a human-readable description of the migration.
+	Description string
+	// AppliedAt is the time the migration was applied.
+	AppliedAt time.Time
+}
+
+// The TableName method returns the name of the database table that the struct is mapped to.
+func (m *SchemaMigrationModel) TableName() string {
+	return "schema_migrations"
+}
+
+// Migration is a versioned schema change that cannot be expressed by the
+// models, such as dropping or renaming an index. Migrations go through the
+// gorm migrator, which generates the DDL of the dialect in use.
+type Migration struct {
+	Version     string
+	Description string
+	Migrate     func(tx *gorm.DB) error
+}
+
+// migrations lists the versioned migrations in the order they are applied.
+// Append new migrations at the end and never change an applied one.
+var migrations = []Migration{
+	{
+		Version:     "20241019000001",
+		Description: "drop the unique index of stack sharing its name with the unique index of project",
+		Migrate: func(tx *gorm.DB) error {
+			// The index was only ever created on MySQL, where index names are
+			// scoped to the table. PostgreSQL and SQLite scope them to the
+			// schema, so the stack index is now named unique_stack.
+			if tx.Migrator().HasIndex(&StackModel{}, "unique_project") {
+				return tx.Migrator().DropIndex(&StackModel{}, "unique_project")
+			}
+			return nil
+		},
+	},
+}
+
+// Migrate brings the database schema up to date for the dialect in use. It
+// creates the tables and columns of the models and then applies the pending
+// versioned migrations, recording each of them in the schema_migrations table.
+func Migrate(db *gorm.DB) error {
+	if err := AutoMigrate(db); err != nil {
+		return err
+	}
+	if err := db.AutoMigrate(&SchemaMigrationModel{}); err != nil {
+		return err
+	}
+
+	var applied []SchemaMigrationModel
+	if err := db.Find(&applied).Error; err != nil {
+		return err
+	}
+	appliedVersions := make(map[string]bool, len(applied))
+	for _, m := range applied {
+		appliedVersions[m.Version] = true
+	}
+
+	for _, m := range migrations {
+		if appliedVersions[m.Version] {
+			continue
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Migrate(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigrationModel{
+				Version:     m.Version,
+				Description: m.Description,
+				AppliedAt:   time.Now(),
+			}).Error
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
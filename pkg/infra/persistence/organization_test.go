@@ -4,7 +4,6 @@ import (
 	"context"
 	"testing"
 
-	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 	"kusionstack.io/kusion/pkg/domain/constant"
@@ -12,143 +11,105 @@ import (
 )
 
 func TestOrganizationRepository(t *testing.T) {
+	ctx := context.Background()
+
 	t.Run("Create", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewOrganizationRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var (
-			expectedID, expectedRows uint = 1, 1
-			actual                        = entity.Organization{
-				Name:        "mockedOrganization",
-				DisplayName: "mockedDisplayName",
-				Owners:      []string{"hua.li", "xiaoming.li"},
-			}
-		)
-		sqlMock.ExpectBegin()
-		sqlMock.ExpectExec("INSERT").
-			WillReturnResult(sqlmock.NewResult(int64(expectedID), int64(expectedRows)))
-		sqlMock.ExpectCommit()
-		err = repo.Create(context.Background(), &actual)
+		db := GetSQLiteDB(t)
+		repo := NewOrganizationRepository(db)
+		defer CloseDB(t, db)
+
+		actual := entity.Organization{
+			Name:        "mockedOrganization",
+			DisplayName: "mockedDisplayName",
+			Owners:      []string{"hua.li", "xiaoming.li"},
+		}
+		err := repo.Create(ctx, &actual)
 		require.NoError(t, err)
-		require.Equal(t, expectedID, actual.ID)
+		require.Equal(t, uint(1), actual.ID)
+
+		// The name of an organization is unique.
+		duplicated := entity.Organization{Name: "mockedOrganization", Owners: []string{"hua.li"}}
+		require.Error(t, repo.Create(ctx, &duplicated))
 	})
 
 	t.Run("Delete existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewOrganizationRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var expectedID uint = 1
-		sqlMock.ExpectBegin()
-		sqlMock.ExpectQuery("SELECT").
-			WillReturnRows(sqlmock.NewRows([]string{"id"}).
-				AddRow(1))
-		sqlMock.ExpectExec("DELETE").WillReturnResult(sqlmock.NewResult(int64(expectedID), int64(0)))
-		sqlMock.ExpectCommit()
-		err = repo.Delete(context.Background(), expectedID)
+		db := GetSQLiteDB(t)
+		repo := NewOrganizationRepository(db)
+		defer CloseDB(t, db)
+
+		org := &entity.Organization{Name: "mockedOrganization", Owners: []string{"hua.li"}}
+		require.NoError(t, repo.Create(ctx, org))
+		err := repo.Delete(ctx, org.ID)
 		require.NoError(t, err)
+		_, err = repo.Get(ctx, org.ID)
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
 	})
 
 	t.Run("Delete not existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewOrganizationRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		sqlMock.ExpectBegin()
-		sqlMock.ExpectQuery("SELECT").
-			WillReturnRows(sqlmock.NewRows([]string{"id"}))
-		err = repo.Delete(context.Background(), 1)
+		db := GetSQLiteDB(t)
+		repo := NewOrganizationRepository(db)
+		defer CloseDB(t, db)
+
+		err := repo.Delete(ctx, 1)
 		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
 	})
 
 	t.Run("Update existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
+		db := GetSQLiteDB(t)
+		repo := NewOrganizationRepository(db)
+		defer CloseDB(t, db)
+
+		org := &entity.Organization{Name: "mockedOrganization", Owners: []string{"hua.li"}}
+		require.NoError(t, repo.Create(ctx, org))
+		org.Description = "updatedDescription"
+		err := repo.Update(ctx, org)
 		require.NoError(t, err)
-		repo := NewOrganizationRepository(fakeGDB)
-
-		var (
-			expectedID, expectedRows uint = 1, 1
-			actual                        = entity.Organization{
-				ID: 1,
-			}
-		)
-		sqlMock.ExpectExec("UPDATE").
-			WillReturnResult(sqlmock.NewResult(int64(expectedID), int64(expectedRows)))
-		err = repo.Update(context.Background(), &actual)
+		updated, err := repo.Get(ctx, org.ID)
 		require.NoError(t, err)
+		require.Equal(t, "updatedDescription", updated.Description)
 	})
 
 	t.Run("Update not existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewOrganizationRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
+		db := GetSQLiteDB(t)
+		repo := NewOrganizationRepository(db)
+		defer CloseDB(t, db)
 
 		actual := entity.Organization{
 			Name: "NonExistentOrganization",
 		}
-		err = repo.Update(context.Background(), &actual)
+		err := repo.Update(ctx, &actual)
 		require.ErrorIs(t, err, gorm.ErrMissingWhereClause)
 	})
 
 	t.Run("Get", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
+		db := GetSQLiteDB(t)
+		repo := NewOrganizationRepository(db)
+		defer CloseDB(t, db)
+
+		org := &entity.Organization{Name: "mockedOrganization", Description: "mockedDescription", Owners: []string{"hua.li"}}
+		require.NoError(t, repo.Create(ctx, org))
+
+		actual, err := repo.Get(ctx, org.ID)
 		require.NoError(t, err)
-		repo := NewOrganizationRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var (
-			expectedID          uint = 1
-			expectedName             = "mockedOrganization"
-			expectedDisplayName      = "mockedDisplayName"
-		)
-		sqlMock.ExpectQuery("SELECT .* FROM `organization`").
-			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "display_name"}).
-				AddRow(expectedID, expectedName, expectedDisplayName))
-
-		actual, err := repo.Get(context.Background(), expectedID)
+		require.Equal(t, org.ID, actual.ID)
+		require.Equal(t, "mockedOrganization", actual.Name)
+		require.Equal(t, []string{"hua.li"}, actual.Owners)
+
+		actual, err = repo.GetByName(ctx, "mockedOrganization")
 		require.NoError(t, err)
-		require.Equal(t, expectedID, actual.ID)
-		require.Equal(t, expectedName, actual.Name)
+		require.Equal(t, org.ID, actual.ID)
 	})
 
 	t.Run("List", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewOrganizationRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var (
-			expectedIDFirst           uint = 1
-			expectedNameFirst              = "mockedOrganization"
-			expectedDisplayNameFirst       = "mockedDisplayName"
-			expectedIDSecond          uint = 2
-			expectedNameSecond             = "mockedOrganization2"
-			expectedDisplayNameSecond      = "mockedDisplayName2"
-		)
-
-		sqlMock.ExpectQuery("SELECT count(.*) FROM `organization`").
-			WillReturnRows(
-				sqlmock.NewRows([]string{"count"}).
-					AddRow(2))
-
-		sqlMock.ExpectQuery("SELECT .* FROM `organization`").
-			WillReturnRows(
-				sqlmock.NewRows([]string{"id", "name", "display_name"}).
-					AddRow(expectedIDFirst, expectedNameFirst, expectedDisplayNameFirst).
-					AddRow(expectedIDSecond, expectedNameSecond, expectedDisplayNameSecond))
-
-		actual, err := repo.List(context.Background(), &entity.OrganizationFilter{
+		db := GetSQLiteDB(t)
+		repo := NewOrganizationRepository(db)
+		defer CloseDB(t, db)
+
+		for _, name := range []string{"mockedOrganization", "mockedOrganization2"} {
+			require.NoError(t, repo.Create(ctx, &entity.Organization{Name: name, Owners: []string{"hua.li"}}))
+		}
+
+		actual, err := repo.List(ctx, &entity.OrganizationFilter{
 			Pagination: &entity.Pagination{
 				Page:     constant.CommonPageDefault,
 				PageSize: constant.CommonPageSizeDefault,
@@ -158,5 +119,7 @@ func TestOrganizationRepository(t *testing.T) {
 		})
 		require.NoError(t, err)
 		require.Len(t, actual.Organizations, 2)
+		require.Equal(t, 2, actual.Total)
+		require.Equal(t, "mockedOrganization2", actual.Organizations[0].Name)
 	})
 }
@@ -2,10 +2,8 @@ package persistence
 
 import (
 	"context"
-	"net/url"
 	"testing"
 
-	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 	"kusionstack.io/kusion/pkg/domain/constant"
@@ -13,162 +11,109 @@ import (
 )
 
 func TestProjectRepository(t *testing.T) {
-	mockRemote := "https://github.com/mockorg/mockrepo"
-	mockRemoteURL, err := url.Parse(mockRemote)
-	require.NoError(t, err)
+	ctx := context.Background()
 
 	t.Run("Create", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewProjectRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var (
-			expectedID, expectedRows uint = 1, 1
-			actual                        = entity.Project{
-				Name: "mockedProject",
-				Source: &entity.Source{
-					ID:             1,
-					Name:           "mockedSource",
-					SourceProvider: constant.SourceProviderTypeGithub,
-					Remote:         mockRemoteURL,
-				},
-				Organization: &entity.Organization{
-					Name:   "mockedOrg",
-					ID:     1,
-					Owners: []string{"hua.li", "xiaoming.li"},
-				},
-				Path:   "/path/to/project",
-				Labels: []string{"testLabel"},
-				Owners: []string{"hua.li", "xiaoming.li"},
-			}
-		)
-		sqlMock.ExpectBegin()
-		sqlMock.ExpectExec("INSERT").
-			WillReturnResult(sqlmock.NewResult(int64(expectedID), int64(expectedRows)))
-		sqlMock.ExpectCommit()
-		err = repo.Create(context.Background(), &actual)
+		db := GetSQLiteDB(t)
+		repo := NewProjectRepository(db)
+		defer CloseDB(t, db)
+
+		existing := newTestProject(t, db)
+		actual := entity.Project{
+			Name:         "mockedProject",
+			Source:       existing.Source,
+			Organization: existing.Organization,
+			Path:         "/path/to/project",
+			Labels:       []string{"testLabel"},
+			Owners:       []string{"hua.li", "xiaoming.li"},
+		}
+		err := repo.Create(ctx, &actual)
 		require.NoError(t, err)
-		require.Equal(t, expectedID, actual.ID)
+		require.NotZero(t, actual.ID)
+		require.NotEqual(t, existing.ID, actual.ID)
 	})
 
 	t.Run("Delete existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewProjectRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var expectedID uint = 1
-		sqlMock.ExpectBegin()
-		sqlMock.ExpectQuery("SELECT").
-			WillReturnRows(sqlmock.NewRows([]string{"id"}).
-				AddRow(1))
-		sqlMock.ExpectExec("DELETE").WillReturnResult(sqlmock.NewResult(int64(expectedID), int64(0)))
-		sqlMock.ExpectCommit()
-		err = repo.Delete(context.Background(), expectedID)
+		db := GetSQLiteDB(t)
+		repo := NewProjectRepository(db)
+		defer CloseDB(t, db)
+
+		project := newTestProject(t, db)
+		err := repo.Delete(ctx, project.ID)
 		require.NoError(t, err)
+		_, err = repo.Get(ctx, project.ID)
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
 	})
 
 	t.Run("Delete not existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewProjectRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		sqlMock.ExpectBegin()
-		sqlMock.ExpectQuery("SELECT").
-			WillReturnRows(sqlmock.NewRows([]string{"id"}))
-		err = repo.Delete(context.Background(), 1)
+		db := GetSQLiteDB(t)
+		repo := NewProjectRepository(db)
+		defer CloseDB(t, db)
+
+		err := repo.Delete(ctx, 1)
 		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
 	})
 
 	t.Run("Update existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
+		db := GetSQLiteDB(t)
+		repo := NewProjectRepository(db)
+		defer CloseDB(t, db)
+
+		project := newTestProject(t, db)
+		project.Description = "updatedDescription"
+		err := repo.Update(ctx, project)
 		require.NoError(t, err)
-		repo := NewProjectRepository(fakeGDB)
-
-		var (
-			expectedID, expectedRows uint = 1, 1
-			actual                        = entity.Project{
-				ID:   1,
-				Name: "mockedProject",
-			}
-		)
-		sqlMock.ExpectExec("UPDATE").
-			WillReturnResult(sqlmock.NewResult(int64(expectedID), int64(expectedRows)))
-		err = repo.Update(context.Background(), &actual)
+		updated, err := repo.Get(ctx, project.ID)
 		require.NoError(t, err)
+		require.Equal(t, "updatedDescription", updated.Description)
 	})
 
 	t.Run("Update not existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewProjectRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
+		db := GetSQLiteDB(t)
+		repo := NewProjectRepository(db)
+		defer CloseDB(t, db)
 
 		actual := entity.Project{
 			Name: "mockedProject",
 		}
-		err = repo.Update(context.Background(), &actual)
+		err := repo.Update(ctx, &actual)
 		require.ErrorIs(t, err, gorm.ErrMissingWhereClause)
 	})
 
 	t.Run("Get", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
+		db := GetSQLiteDB(t)
+		repo := NewProjectRepository(db)
+		defer CloseDB(t, db)
+
+		project := newTestProject(t, db)
+		actual, err := repo.Get(ctx, project.ID)
 		require.NoError(t, err)
-		repo := NewProjectRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var (
-			expectedID     uint = 1
-			expectedName        = "mockedProject"
-			expectedPath        = "/path/to/project"
-			expectedOwners      = MultiString{"hua.li", "xiaoming.li"}
-		)
-		sqlMock.ExpectQuery("SELECT.*FROM `project`").
-			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "path", "Organization__id", "Organization__name", "Organization__owners", "Source__id", "Source__remote", "Source__source_provider"}).
-				AddRow(expectedID, expectedName, expectedPath, 1, "mockedOrg", expectedOwners, 1, "https://github.com/test/repo", constant.SourceProviderTypeGithub))
-
-		actual, err := repo.Get(context.Background(), expectedID)
+		require.Equal(t, project.ID, actual.ID)
+		require.Equal(t, "project", actual.Name)
+		require.Equal(t, "org", actual.Organization.Name)
+		require.Equal(t, project.Source.Remote.String(), actual.Source.Remote.String())
+
+		actual, err = repo.GetByName(ctx, "project")
 		require.NoError(t, err)
-		require.Equal(t, expectedID, actual.ID)
-		require.Equal(t, expectedName, actual.Name)
+		require.Equal(t, project.ID, actual.ID)
 	})
 
 	t.Run("List", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewProjectRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var (
-			expectedID         uint = 1
-			expectedName            = "mockedProject"
-			expectedPath            = "/path/to/project"
-			expectedOrgOwners       = MultiString{"hua.li", "xiaoming.li"}
-			expectedIDSecond   uint = 2
-			expectedNameSecond      = "mockedProject2"
-			expectedPathSecond      = "/path/to/project/2"
-		)
-
-		sqlMock.ExpectQuery("SELECT count(.*) FROM `project`").
-			WillReturnRows(
-				sqlmock.NewRows([]string{"count"}).
-					AddRow(2))
-
-		sqlMock.ExpectQuery("SELECT .* FROM `project` .* IS NULL .* LIMIT").
-			WillReturnRows(
-				sqlmock.NewRows([]string{"id", "name", "path", "Organization__id", "Organization__name", "Organization__owners", "Source__id", "Source__remote", "Source__source_provider"}).
-					AddRow(expectedID, expectedName, expectedPath, 1, "mockedOrg", expectedOrgOwners, 1, "https://github.com/test/repo", constant.SourceProviderTypeGithub).
-					AddRow(expectedIDSecond, expectedNameSecond, expectedPathSecond, 1, "mockedOrg", expectedOrgOwners, 2, "https://github.com/test/repo2", constant.SourceProviderTypeGithub))
-
-		actual, err := repo.List(context.Background(), &entity.ProjectFilter{
+		db := GetSQLiteDB(t)
+		repo := NewProjectRepository(db)
+		defer CloseDB(t, db)
+
+		existing := newTestProject(t, db)
+		second := &entity.Project{
+			Name:         "mockedProject2",
+			Path:         "/path/to/project/2",
+			Source:       existing.Source,
+			Organization: existing.Organization,
+			Owners:       []string{"hua.li"},
+		}
+		require.NoError(t, repo.Create(ctx, second))
+
+		actual, err := repo.List(ctx, &entity.ProjectFilter{
 			Pagination: &entity.Pagination{
 				Page:     constant.CommonPageDefault,
 				PageSize: constant.CommonPageSizeDefault,
@@ -178,5 +123,21 @@ func TestProjectRepository(t *testing.T) {
 		})
 		require.NoError(t, err)
 		require.Len(t, actual.Projects, 2)
+		require.Equal(t, 2, actual.Total)
+		require.Equal(t, second.ID, actual.Projects[0].ID)
+
+		// The projects are filtered by their names.
+		actual, err = repo.List(ctx, &entity.ProjectFilter{
+			FuzzyName: "mocked",
+			Pagination: &entity.Pagination{
+				Page:     constant.CommonPageDefault,
+				PageSize: constant.CommonPageSizeDefault,
+			},
+		}, &entity.SortOptions{
+			Field: constant.SortByID,
+		})
+		require.NoError(t, err)
+		require.Len(t, actual.Projects, 1)
+		require.Equal(t, 1, actual.Total)
 	})
 }
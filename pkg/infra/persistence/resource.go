@@ -45,7 +45,10 @@ func (r *resourceRepository) Create(ctx context.Context, dataEntityList []*entit
 
 	return r.db.Transaction(func(tx *gorm.DB) error {
 		// Create new record in the store
+		// The conflict target is ignored by MySQL, which upserts on any unique
+		// key, but is required by PostgreSQL and SQLite.
 		err = tx.WithContext(ctx).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "resource_urn"}},
 			UpdateAll: true,
 		}).Create(&dataModelList).Error
 		if err != nil {
@@ -156,10 +159,7 @@ func (r *resourceRepository) List(ctx context.Context, filter *entity.ResourceFi
 	resourceEntityList := make([]*entity.Resource, 0)
 	pattern, args := GetResourceQuery(filter)
 
-	sortArgs := sortOptions.Field
-	if !sortOptions.Ascending {
-		sortArgs += " DESC"
-	}
+	sortArgs := GetSortArgs("resource", sortOptions)
 
 	searchResult := r.db.WithContext(ctx).
 		Preload("Stack").Preload("Stack.Project").Preload("Stack.Project.Organization").Preload("Stack.Project.Source").
@@ -5,83 +5,99 @@ import (
 	"context"
 	"testing"
 
-	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
 	"kusionstack.io/kusion/pkg/domain/constant"
 	"kusionstack.io/kusion/pkg/domain/entity"
 )
 
 func TestResourceRepository(t *testing.T) {
+	ctx := context.Background()
+	// newResources creates a Kubernetes and a Terraform resource in a stack.
+	newResources := func(t *testing.T, db *gorm.DB) []*entity.Resource {
+		stack := newTestStack(t, db)
+		resources := []*entity.Resource{
+			{
+				Stack:            stack,
+				ResourceType:     "Kubernetes",
+				ResourcePlane:    "Kubernetes",
+				ResourceName:     "my-namespace/my-deployment",
+				KusionResourceID: "apps/v1:Deployment:my-namespace:my-deployment",
+				ResourceURN:      "project:stack:workspace:apps/v1:Deployment:my-namespace:my-deployment",
+				Status:           "applied",
+			},
+			{
+				Stack:            stack,
+				ResourceType:     "Terraform",
+				ResourcePlane:    "aws",
+				ResourceName:     "my-bucket",
+				KusionResourceID: "hashicorp:aws:aws_s3_bucket:my-bucket",
+				ResourceURN:      "project:stack:workspace:hashicorp:aws:aws_s3_bucket:my-bucket",
+				Status:           "applied",
+			},
+		}
+		repo := NewResourceRepository(db)
+		require.NoError(t, repo.Create(ctx, resources))
+		// Create doesn't return the IDs of the upserted resources.
+		for _, resource := range resources {
+			created, err := repo.GetByKusionResourceURN(ctx, resource.ResourceURN)
+			require.NoError(t, err)
+			resource.ID = created.ID
+		}
+		return resources
+	}
+
 	t.Run("Get", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewResourceRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var (
-			expectedID   uint = 1
-			expectedType      = "Kubernetes"
-		)
-		sqlMock.ExpectQuery("SELECT .* FROM `resource`").
-			WillReturnRows(sqlmock.NewRows([]string{"id", "resource_type"}).
-				AddRow(expectedID, expectedType))
-
-		actual, err := repo.Get(context.Background(), expectedID)
+		db := GetSQLiteDB(t)
+		repo := NewResourceRepository(db)
+		defer CloseDB(t, db)
+
+		resources := newResources(t, db)
+		actual, err := repo.Get(ctx, resources[0].ID)
 		require.NoError(t, err)
-		require.Equal(t, expectedID, actual.ID)
-		require.Equal(t, expectedType, actual.ResourceType)
+		require.Equal(t, resources[0].ID, actual.ID)
+		require.Equal(t, "Kubernetes", actual.ResourceType)
+		require.Equal(t, "project", actual.Stack.Project.Name)
 	})
 
 	t.Run("GetByKusionResourceID", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewResourceRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var (
-			expectedID                uint = 1
-			expectedType                   = "Kubernetes"
-			expectedKusionResourceID       = "apps/v1:Deployment:my-namespace:my-deployment"
-			expectedKusionResourceURN      = "project:stack:workspace:apps/v1:Deployment:my-namespace:my-deployment"
-		)
-		sqlMock.ExpectQuery("SELECT .* FROM `resource`").
-			WillReturnRows(sqlmock.NewRows([]string{"id", "resource_type", "kusion_resource_id", "resource_urn"}).
-				AddRow(expectedID, expectedType, expectedKusionResourceID, expectedKusionResourceURN))
-
-		actual, err := repo.GetByKusionResourceURN(context.Background(), expectedKusionResourceURN)
+		db := GetSQLiteDB(t)
+		repo := NewResourceRepository(db)
+		defer CloseDB(t, db)
+
+		resources := newResources(t, db)
+		actual, err := repo.GetByKusionResourceURN(ctx, "project:stack:workspace:apps/v1:Deployment:my-namespace:my-deployment")
 		require.NoError(t, err)
-		require.Equal(t, expectedID, actual.ID)
-		require.Equal(t, expectedType, actual.ResourceType)
+		require.Equal(t, resources[0].ID, actual.ID)
+		require.Equal(t, "Kubernetes", actual.ResourceType)
+	})
+
+	t.Run("Create existing record", func(t *testing.T) {
+		db := GetSQLiteDB(t)
+		repo := NewResourceRepository(db)
+		defer CloseDB(t, db)
+
+		// Creating a resource with the same URN updates the existing record.
+		resources := newResources(t, db)
+		again := *resources[0]
+		again.ID = 0
+		again.Status = "updated"
+		require.NoError(t, repo.Create(ctx, []*entity.Resource{&again}))
+
+		var models []ResourceModel
+		require.NoError(t, db.Order("id").Find(&models).Error)
+		require.Len(t, models, 2)
+		require.Equal(t, "updated", models[0].Status)
 	})
 
 	t.Run("List", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewResourceRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var (
-			expectedIDFirst    uint = 1
-			expectedTypeFirst       = "Kubernetes"
-			expectedIDSecond   uint = 2
-			expectedTypeSecond      = "Terraform"
-		)
-
-		sqlMock.ExpectQuery("SELECT count(.*) FROM `resource`").
-			WillReturnRows(
-				sqlmock.NewRows([]string{"count"}).
-					AddRow(2))
-
-		sqlMock.ExpectQuery("SELECT .* FROM `resource`").
-			WillReturnRows(
-				sqlmock.NewRows([]string{"id", "resource_type"}).
-					AddRow(expectedIDFirst, expectedTypeFirst).
-					AddRow(expectedIDSecond, expectedTypeSecond))
-
-		actual, err := repo.List(context.Background(), &entity.ResourceFilter{
+		db := GetSQLiteDB(t)
+		repo := NewResourceRepository(db)
+		defer CloseDB(t, db)
+
+		newResources(t, db)
+		actual, err := repo.List(ctx, &entity.ResourceFilter{
 			Pagination: &entity.Pagination{
 				Page:     constant.CommonPageDefault,
 				PageSize: constant.CommonPageSizeDefault,
@@ -91,67 +107,46 @@ func TestResourceRepository(t *testing.T) {
 		})
 		require.NoError(t, err)
 		require.Len(t, actual.Resources, 2)
+		require.Equal(t, 2, actual.Total)
 	})
 
 	t.Run("Delete existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewResourceRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var expectedID uint = 1
-		sqlMock.ExpectBegin()
-		sqlMock.ExpectQuery("SELECT").
-			WillReturnRows(sqlmock.NewRows([]string{"id"}).
-				AddRow(1))
-		sqlMock.ExpectExec("DELETE").WillReturnResult(sqlmock.NewResult(int64(expectedID), int64(0)))
-		sqlMock.ExpectCommit()
-		err = repo.Delete(context.Background(), expectedID)
+		db := GetSQLiteDB(t)
+		repo := NewResourceRepository(db)
+		defer CloseDB(t, db)
+
+		resources := newResources(t, db)
+		err := repo.Delete(ctx, resources[0].ID)
 		require.NoError(t, err)
+		_, err = repo.Get(ctx, resources[0].ID)
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		require.ErrorIs(t, repo.Delete(ctx, resources[0].ID), gorm.ErrRecordNotFound)
 	})
 
 	t.Run("Batch delete existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewResourceRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
+		db := GetSQLiteDB(t)
+		repo := NewResourceRepository(db)
+		defer CloseDB(t, db)
 
-		expectedResources := []*entity.Resource{
-			{
-				ID:           1,
-				ResourceType: "Kubernetes",
-				Stack:        &entity.Stack{},
-			},
-			{
-				ID:           2,
-				ResourceType: "Terraform",
-				Stack:        &entity.Stack{},
-			},
-		}
-		sqlMock.ExpectBegin()
-		sqlMock.ExpectExec("DELETE").WillReturnResult(sqlmock.NewResult(int64(1), int64(1)))
-		sqlMock.ExpectCommit()
-		err = repo.BatchDelete(context.Background(), expectedResources)
+		resources := newResources(t, db)
+		err := repo.BatchDelete(ctx, resources)
 		require.NoError(t, err)
+		var count int64
+		require.NoError(t, db.Model(&ResourceModel{}).Count(&count).Error)
+		require.Zero(t, count)
 	})
 
 	t.Run("Update existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
+		db := GetSQLiteDB(t)
+		repo := NewResourceRepository(db)
+		defer CloseDB(t, db)
+
+		resources := newResources(t, db)
+		resources[0].Status = "destroyed"
+		err := repo.Update(ctx, resources[0])
 		require.NoError(t, err)
-		repo := NewResourceRepository(fakeGDB)
-
-		var (
-			expectedID, expectedRows uint = 1, 1
-			actual                        = entity.Resource{
-				ID:           1,
-				ResourceType: "Kubernetes",
-			}
-		)
-		sqlMock.ExpectExec("UPDATE").
-			WillReturnResult(sqlmock.NewResult(int64(expectedID), int64(expectedRows)))
-		err = repo.Update(context.Background(), &actual)
+		updated, err := repo.Get(ctx, resources[0].ID)
 		require.NoError(t, err)
+		require.Equal(t, "destroyed", updated.Status)
 	})
 }
@@ -2,12 +2,22 @@ package persistence
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"kusionstack.io/kusion/pkg/domain/constant"
 	"kusionstack.io/kusion/pkg/domain/entity"
 	"kusionstack.io/kusion/pkg/domain/repository"
 )
 
+// claimBatchSize is the number of pending runs inspected by a single claim.
+const claimBatchSize = 100
+
+// pendingRunStatuses are the statuses of the runs waiting for a worker.
+var pendingRunStatuses = []string{string(constant.RunStatusScheduling), string(constant.RunStatusQueued)}
+
 // The runRepository type implements the repository.RunRepository interface.
 // If the runRepository type does not implement all the methods of the interface,
 // the compiler will produce an error.
@@ -73,7 +83,9 @@ func (r *runRepository) Update(ctx context.Context, dataEntity *entity.Run) erro
 		return err
 	}
 
-	err = r.db.WithContext(ctx).Updates(&dataModel).Error
+	// The lease columns are owned by the run queue and only changed through
+	// the conditional updates below, so that a stale read never overwrites them.
+	err = r.db.WithContext(ctx).Omit("LeaseOwner", "LeaseExpiresAt", "Attempts").Updates(&dataModel).Error
 	if err != nil {
 		return err
 	}
@@ -85,7 +97,7 @@ func (r *runRepository) Update(ctx context.Context, dataEntity *entity.Run) erro
 func (r *runRepository) Get(ctx context.Context, id uint) (*entity.Run, error) {
 	var dataModel RunModel
 	err := r.db.WithContext(ctx).
-		Preload("Stack").Preload("Stack.Project").
+		Preload("Stack").Preload("Stack.Project").Preload("Stack.Project.Organization").
 		Joins("JOIN stack ON stack.id = run.stack_id").
 		Joins("JOIN project ON project.id = stack.project_id").
 		First(&dataModel, id).Error
@@ -101,13 +113,10 @@ func (r *runRepository) List(ctx context.Context, filter *entity.RunFilter, sort
 	runEntityList := make([]*entity.Run, 0)
 	pattern, args := GetRunQuery(filter)
 
-	sortArgs := sortOptions.Field
-	if !sortOptions.Ascending {
-		sortArgs += " DESC"
-	}
+	sortArgs := GetSortArgs("run", sortOptions)
 
 	searchResult := r.db.WithContext(ctx).
-		Preload("Stack").Preload("Stack.Project").
+		Preload("Stack").Preload("Stack.Project").Preload("Stack.Project.Organization").
 		Joins("JOIN stack ON stack.id = run.stack_id").
 		Joins("JOIN project ON project.id = stack.project_id").
 		Joins("JOIN workspace ON workspace.name = run.workspace").
@@ -136,3 +145,166 @@ func (r *runRepository) List(ctx context.Context, filter *entity.RunFilter, sort
 		Total: int(totalRows),
 	}, nil
 }
+
+// Claim leases the oldest pending run whose stack and workspace have no run in
+// progress. The lease is taken with a conditional update, so concurrent
+// workers, including the ones on other server replicas, never claim the same
+// run twice. The claims of the runs of a stack are serialized by locking the
+// stack row, otherwise two runs of the same target could both pass the check
+// of the run in progress under READ COMMITTED or REPEATABLE READ.
+func (r *runRepository) Claim(ctx context.Context, owner string, leaseDuration time.Duration) (*entity.Run, error) {
+	var candidates []RunModel
+	err := r.db.WithContext(ctx).
+		Select("id", "stack_id", "workspace").
+		Where("status IN ?", pendingRunStatuses).
+		Order("id").
+		Limit(claimBatchSize).
+		Find(&candidates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	inspected := make(map[string]bool)
+	for _, candidate := range candidates {
+		// Only the oldest pending run of a stack and workspace can be claimed
+		// to keep the runs of the same target in submission order.
+		key := fmt.Sprintf("%d/%s", candidate.StackID, candidate.Workspace)
+		if inspected[key] {
+			continue
+		}
+		inspected[key] = true
+
+		var claimed bool
+		err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			// Lock the stack row to serialize the claims of its runs. SQLite
+			// has no row locks, its write transactions are serialized instead.
+			var stack StackModel
+			err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+				Select("id").Where("id = ?", candidate.StackID).Find(&stack).Error
+			if err != nil {
+				return err
+			}
+
+			// The subquery is wrapped in a derived table because MySQL does not
+			// allow the target table of an update to be referenced directly.
+			result := tx.Model(&RunModel{}).
+				Where("id = ? AND status IN ?", candidate.ID, pendingRunStatuses).
+				Where("NOT EXISTS (SELECT 1 FROM (SELECT id FROM run WHERE stack_id = ? AND workspace = ? AND status = ? AND deleted_at IS NULL) AS busy)",
+					candidate.StackID, candidate.Workspace, string(constant.RunStatusInProgress)).
+				Updates(map[string]any{
+					"status":           string(constant.RunStatusInProgress),
+					"lease_owner":      owner,
+					"lease_expires_at": time.Now().Add(leaseDuration),
+					"attempts":         gorm.Expr("attempts + 1"),
+				})
+			claimed = result.RowsAffected == 1
+			return result.Error
+		})
+		if err != nil {
+			return nil, err
+		}
+		if claimed {
+			return r.Get(ctx, candidate.ID)
+		}
+	}
+
+	return nil, nil
+}
+
+// RenewLease extends the lease of a run held by the given owner. It returns
+// gorm.ErrRecordNotFound if the owner no longer holds the lease.
+func (r *runRepository) RenewLease(ctx context.Context, id uint, owner string, leaseDuration time.Duration) error {
+	result := r.db.WithContext(ctx).Model(&RunModel{}).
+		Where("id = ? AND lease_owner = ? AND status = ?", id, owner, string(constant.RunStatusInProgress)).
+		Update("lease_expires_at", time.Now().Add(leaseDuration))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ReleaseLease clears the lease of a run held by the given owner.
+func (r *runRepository) ReleaseLease(ctx context.Context, id uint, owner string) error {
+	return r.db.WithContext(ctx).Model(&RunModel{}).
+		Where("id = ? AND lease_owner = ?", id, owner).
+		Updates(map[string]any{
+			"lease_owner":      "",
+			"lease_expires_at": nil,
+		}).Error
+}
+
+// ListExpired retrieves the runs in progress whose lease has expired. Runs in
+// progress without a lease were started before the run queue existed and are
+// considered expired as well.
+func (r *runRepository) ListExpired(ctx context.Context, now time.Time) ([]*entity.Run, error) {
+	var dataModel []RunModel
+	err := r.db.WithContext(ctx).
+		Where("status = ?", string(constant.RunStatusInProgress)).
+		Where("lease_expires_at IS NULL OR lease_expires_at < ?", now).
+		Order("id").
+		Find(&dataModel).Error
+	if err != nil {
+		return nil, err
+	}
+
+	runEntityList := make([]*entity.Run, 0, len(dataModel))
+	for _, run := range dataModel {
+		runEntity, err := run.ToEntity()
+		if err != nil {
+			return nil, err
+		}
+		runEntityList = append(runEntityList, runEntity)
+	}
+	return runEntityList, nil
+}
+
+// Recover moves an orphaned run to the given status and clears its lease. The
+// update is conditional on the lease observed by the caller, so a run renewed
+// or recovered concurrently by another replica is left untouched. The runs
+// started before the run queue existed have no lease owner at all.
+func (r *runRepository) Recover(ctx context.Context, id uint, owner string, now time.Time, status constant.RunStatus, result string) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&RunModel{}).
+		Where("id = ? AND status = ?", id, string(constant.RunStatusInProgress)).
+		Where("lease_owner = ? OR lease_owner IS NULL", owner).
+		Where("lease_expires_at IS NULL OR lease_expires_at < ?", now).
+		Updates(map[string]any{
+			"status":           string(status),
+			"result":           result,
+			"lease_owner":      "",
+			"lease_expires_at": nil,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// Finish moves a run in progress to the given status with its result and logs.
+// The update is conditional on the lease, so a run recovered or claimed by
+// another replica after the owner lost the lease is left untouched.
+func (r *runRepository) Finish(ctx context.Context, id uint, owner string, status constant.RunStatus, result, logs string) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&RunModel{}).
+		Where("id = ? AND status = ? AND lease_owner = ?", id, string(constant.RunStatusInProgress), owner).
+		Updates(map[string]any{
+			"status": string(status),
+			"result": result,
+			"logs":   logs,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// UpdateVariableSets updates the variable sets injected into a run only,
+// leaving the status, result and logs written concurrently untouched.
+func (r *runRepository) UpdateVariableSets(ctx context.Context, id uint, variableSets []entity.RunVariableSet) error {
+	return r.db.WithContext(ctx).Model(&RunModel{}).
+		Where("id = ?", id).
+		// Update the column with a model, so that it is serialized to JSON.
+		Select("variable_sets").
+		Updates(&RunModel{VariableSets: variableSets}).Error
+}
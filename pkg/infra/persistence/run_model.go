@@ -1,6 +1,8 @@
 package persistence
 
 import (
+	"time"
+
 	"kusionstack.io/kusion/pkg/domain/constant"
 	"kusionstack.io/kusion/pkg/domain/entity"
 
@@ -19,13 +21,23 @@ type RunModel struct {
 	// Workspace is the target workspace of the run.
 	Workspace string
 	// Status is the status of the run.
-	Status string
+	Status string `gorm:"index"`
 	// Result is the result of the run.
 	Result string
 	// Logs is the logs of the run.
 	Logs string
 	// Trace is the trace of the run.
 	Trace string
+	// Parameters is the serialized input of the run.
+	Parameters string
+	// LeaseOwner is the identity of the worker executing the run.
+	LeaseOwner string
+	// LeaseExpiresAt is the time the lease of the worker expires.
+	LeaseExpiresAt *time.Time `gorm:"index"`
+	// Attempts is the number of times the run has been claimed.
+	Attempts int `gorm:"default:0"`
+	// VariableSets are the versions of the variable sets injected into the run.
+	VariableSets []entity.RunVariableSet `gorm:"serializer:json"`
 }
 
 // The TableName method returns the name of the database table that the struct is mapped to.
@@ -66,6 +78,11 @@ func (m *RunModel) ToEntity() (*entity.Run, error) {
 		Result:            m.Result,
 		Trace:             m.Trace,
 		Logs:              m.Logs,
+		Parameters:        m.Parameters,
+		LeaseOwner:        m.LeaseOwner,
+		LeaseExpiresAt:    m.LeaseExpiresAt,
+		Attempts:          m.Attempts,
+		VariableSets:      m.VariableSets,
 		CreationTimestamp: m.CreatedAt,
 		UpdateTimestamp:   m.UpdatedAt,
 	}, nil
@@ -89,6 +106,11 @@ func (m *RunModel) FromEntity(e *entity.Run) error {
 	m.Result = e.Result
 	m.Logs = e.Logs
 	m.Trace = e.Trace
+	m.Parameters = e.Parameters
+	m.LeaseOwner = e.LeaseOwner
+	m.LeaseExpiresAt = e.LeaseExpiresAt
+	m.Attempts = e.Attempts
+	m.VariableSets = e.VariableSets
 	m.CreatedAt = e.CreationTimestamp
 	m.UpdatedAt = e.UpdateTimestamp
 
@@ -3,128 +3,326 @@ package persistence
 import (
 	"context"
 	"testing"
+	"time"
 
-	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 	"kusionstack.io/kusion/pkg/domain/constant"
 	"kusionstack.io/kusion/pkg/domain/entity"
+	"kusionstack.io/kusion/pkg/domain/repository"
 )
 
 func TestRunRepository(t *testing.T) {
+	ctx := context.Background()
+	// newRun creates a run of the stack in the workspace.
+	newRun := func(t *testing.T, repo repository.RunRepository, stack *entity.Stack, workspace string, status constant.RunStatus) *entity.Run {
+		run := &entity.Run{Type: constant.RunTypeApply, Stack: stack, Workspace: workspace, Status: status}
+		require.NoError(t, repo.Create(ctx, run))
+		return run
+	}
+
 	t.Run("Create", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewRunRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var (
-			expectedID, expectedRows uint = 1, 1
-			actual                        = entity.Run{
-				Type: "Generate",
-				Stack: &entity.Stack{
-					ID: 1,
-				},
-				Workspace: "test",
-			}
-		)
-		sqlMock.ExpectBegin()
-		sqlMock.ExpectExec("INSERT").
-			WillReturnResult(sqlmock.NewResult(int64(expectedID), int64(expectedRows)))
-		sqlMock.ExpectCommit()
-		err = repo.Create(context.Background(), &actual)
-		require.NoError(t, err)
-		require.Equal(t, expectedID, actual.ID)
+		db := GetSQLiteDB(t)
+		repo := NewRunRepository(db)
+		defer CloseDB(t, db)
+
+		actual := entity.Run{
+			Type:      constant.RunTypeGenerate,
+			Stack:     newTestStack(t, db),
+			Workspace: "test",
+		}
+		err := repo.Create(ctx, &actual)
+		require.NoError(t, err)
+		require.Equal(t, uint(1), actual.ID)
 	})
 
 	t.Run("Delete existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewRunRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
+		db := GetSQLiteDB(t)
+		repo := NewRunRepository(db)
+		defer CloseDB(t, db)
 
-		var expectedID uint = 1
-		sqlMock.ExpectBegin()
-		sqlMock.ExpectQuery("SELECT").
-			WillReturnRows(sqlmock.NewRows([]string{"id"}).
-				AddRow(1))
-		sqlMock.ExpectExec("DELETE").WillReturnResult(sqlmock.NewResult(int64(expectedID), int64(0)))
-		sqlMock.ExpectCommit()
-		err = repo.Delete(context.Background(), expectedID)
+		run := newRun(t, repo, newTestStack(t, db), "dev", constant.RunStatusSucceeded)
+		err := repo.Delete(ctx, run.ID)
 		require.NoError(t, err)
+		_, err = repo.Get(ctx, run.ID)
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
 	})
 
 	t.Run("Delete not existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewRunRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
+		db := GetSQLiteDB(t)
+		repo := NewRunRepository(db)
+		defer CloseDB(t, db)
 
-		sqlMock.ExpectBegin()
-		sqlMock.ExpectQuery("SELECT").
-			WillReturnRows(sqlmock.NewRows([]string{"id"}))
-		err = repo.Delete(context.Background(), 1)
+		err := repo.Delete(ctx, 1)
 		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
 	})
 
 	t.Run("Update existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewRunRepository(fakeGDB)
+		db := GetSQLiteDB(t)
+		repo := NewRunRepository(db)
+		defer CloseDB(t, db)
 
-		var (
-			expectedID, expectedRows uint = 1, 1
-			actual                        = entity.Run{
-				ID: 1,
-			}
-		)
-		sqlMock.ExpectExec("UPDATE").
-			WillReturnResult(sqlmock.NewResult(int64(expectedID), int64(expectedRows)))
-		err = repo.Update(context.Background(), &actual)
+		run := newRun(t, repo, newTestStack(t, db), "dev", constant.RunStatusInProgress)
+		run.Status = constant.RunStatusSucceeded
+		run.Logs = "logs"
+		err := repo.Update(ctx, run)
 		require.NoError(t, err)
+		updated, err := repo.Get(ctx, run.ID)
+		require.NoError(t, err)
+		require.Equal(t, constant.RunStatusSucceeded, updated.Status)
+		require.Equal(t, "logs", updated.Logs)
 	})
 
 	t.Run("Update not existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewRunRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
+		db := GetSQLiteDB(t)
+		repo := NewRunRepository(db)
+		defer CloseDB(t, db)
 
 		actual := entity.Run{
-			Type: "Generate",
+			Type: constant.RunTypeGenerate,
 			Stack: &entity.Stack{
 				ID: 1,
 			},
 			Workspace: "test",
 		}
-		err = repo.Update(context.Background(), &actual)
+		err := repo.Update(ctx, &actual)
 		require.ErrorIs(t, err, gorm.ErrMissingWhereClause)
 	})
 
 	t.Run("Get", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewRunRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var (
-			expectedID        uint = 1
-			expectedWorkspace      = "test"
-			expectedType           = constant.RunTypeGenerate
-			expectedStatus         = "succeeded"
-		)
-		sqlMock.ExpectQuery("SELECT .* FROM `run`").
-			WillReturnRows(sqlmock.NewRows([]string{"id", "workspace", "status", "type"}).
-				AddRow(expectedID, expectedWorkspace, expectedStatus, expectedType))
-
-		actual, err := repo.Get(context.Background(), expectedID)
-		require.NoError(t, err)
-		require.Equal(t, expectedID, actual.ID)
-		require.Equal(t, expectedType, actual.Type)
+		db := GetSQLiteDB(t)
+		repo := NewRunRepository(db)
+		defer CloseDB(t, db)
+
+		run := newRun(t, repo, newTestStack(t, db), "test", constant.RunStatusSucceeded)
+		actual, err := repo.Get(ctx, run.ID)
+		require.NoError(t, err)
+		require.Equal(t, run.ID, actual.ID)
+		require.Equal(t, constant.RunTypeApply, actual.Type)
+		require.Equal(t, "test", actual.Workspace)
+		require.Equal(t, "project", actual.Stack.Project.Name)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		db := GetSQLiteDB(t)
+		repo := NewRunRepository(db)
+		defer CloseDB(t, db)
+
+		// The runs are listed along with their workspaces.
+		stack, workspace := newTestStack(t, db), newTestWorkspace(t, db)
+		run := newRun(t, repo, stack, workspace.Name, constant.RunStatusSucceeded)
+		result, err := repo.List(ctx, &entity.RunFilter{
+			StackID:    stack.ID,
+			Pagination: &entity.Pagination{Page: constant.CommonPageDefault, PageSize: constant.CommonPageSizeDefault},
+		}, &entity.SortOptions{Field: constant.SortByID})
+		require.NoError(t, err)
+		require.Equal(t, 1, result.Total)
+		require.Equal(t, run.ID, result.Runs[0].ID)
+		require.Equal(t, "project", result.Runs[0].Stack.Project.Name)
+	})
+
+	t.Run("Claim oldest runnable run", func(t *testing.T) {
+		db := GetSQLiteDB(t)
+		repo := NewRunRepository(db)
+		defer CloseDB(t, db)
+
+		stack := newTestStack(t, db)
+		first := newRun(t, repo, stack, "dev", constant.RunStatusQueued)
+		second := newRun(t, repo, stack, "dev", constant.RunStatusQueued)
+		other := newRun(t, repo, stack, "prod", constant.RunStatusQueued)
+
+		// The oldest run is claimed first.
+		actual, err := repo.Claim(ctx, "replica-a", time.Minute)
+		require.NoError(t, err)
+		require.NotNil(t, actual)
+		require.Equal(t, first.ID, actual.ID)
+		require.Equal(t, constant.RunStatusInProgress, actual.Status)
+		require.Equal(t, "replica-a", actual.LeaseOwner)
+		require.Equal(t, 1, actual.Attempts)
+
+		// The second run of the same stack and workspace waits for the first
+		// one, while the run of another workspace can be claimed.
+		actual, err = repo.Claim(ctx, "replica-b", time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, other.ID, actual.ID)
+		actual, err = repo.Claim(ctx, "replica-b", time.Minute)
+		require.NoError(t, err)
+		require.Nil(t, actual)
+
+		finished, err := repo.Finish(ctx, first.ID, "replica-a", constant.RunStatusSucceeded, "", "")
+		require.NoError(t, err)
+		require.True(t, finished)
+		actual, err = repo.Claim(ctx, "replica-b", time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, second.ID, actual.ID)
+	})
+
+	t.Run("Claim without pending runs", func(t *testing.T) {
+		db := GetSQLiteDB(t)
+		repo := NewRunRepository(db)
+		defer CloseDB(t, db)
+
+		newRun(t, repo, newTestStack(t, db), "dev", constant.RunStatusSucceeded)
+		actual, err := repo.Claim(ctx, "owner", time.Minute)
+		require.NoError(t, err)
+		require.Nil(t, actual)
+	})
+
+	t.Run("RenewLease", func(t *testing.T) {
+		db := GetSQLiteDB(t)
+		repo := NewRunRepository(db)
+		defer CloseDB(t, db)
+
+		run := newRun(t, repo, newTestStack(t, db), "dev", constant.RunStatusQueued)
+		claimed, err := repo.Claim(ctx, "owner", time.Minute)
+		require.NoError(t, err)
+		require.NoError(t, repo.RenewLease(ctx, run.ID, "owner", time.Hour))
+		renewed, err := repo.Get(ctx, run.ID)
+		require.NoError(t, err)
+		require.True(t, renewed.LeaseExpiresAt.After(*claimed.LeaseExpiresAt))
+	})
+
+	t.Run("RenewLease lost", func(t *testing.T) {
+		db := GetSQLiteDB(t)
+		repo := NewRunRepository(db)
+		defer CloseDB(t, db)
+
+		// Only the lease owner can renew the lease.
+		run := newRun(t, repo, newTestStack(t, db), "dev", constant.RunStatusQueued)
+		_, err := repo.Claim(ctx, "owner", time.Minute)
+		require.NoError(t, err)
+		err = repo.RenewLease(ctx, run.ID, "another", time.Minute)
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("ReleaseLease", func(t *testing.T) {
+		db := GetSQLiteDB(t)
+		repo := NewRunRepository(db)
+		defer CloseDB(t, db)
+
+		// Releasing the lease keeps the status of the run.
+		run := newRun(t, repo, newTestStack(t, db), "dev", constant.RunStatusQueued)
+		_, err := repo.Claim(ctx, "owner", time.Minute)
+		require.NoError(t, err)
+		require.NoError(t, repo.ReleaseLease(ctx, run.ID, "owner"))
+		released, err := repo.Get(ctx, run.ID)
+		require.NoError(t, err)
+		require.Empty(t, released.LeaseOwner)
+		require.Nil(t, released.LeaseExpiresAt)
+		require.Equal(t, constant.RunStatusInProgress, released.Status)
+	})
+
+	t.Run("ListExpired", func(t *testing.T) {
+		db := GetSQLiteDB(t)
+		repo := NewRunRepository(db)
+		defer CloseDB(t, db)
+
+		stack := newTestStack(t, db)
+		claimed := newRun(t, repo, stack, "dev", constant.RunStatusQueued)
+		_, err := repo.Claim(ctx, "replica-a", time.Minute)
+		require.NoError(t, err)
+		// A run in progress created before the lease columns existed has a
+		// NULL lease owner, which is expired as well.
+		legacy := newRun(t, repo, stack, "prod", constant.RunStatusInProgress)
+		require.NoError(t, db.Model(&RunModel{}).Where("id = ?", legacy.ID).Update("lease_owner", nil).Error)
+
+		// An active lease is never expired.
+		actual, err := repo.ListExpired(ctx, time.Now())
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		require.Equal(t, legacy.ID, actual[0].ID)
+
+		actual, err = repo.ListExpired(ctx, time.Now().Add(2*time.Minute))
+		require.NoError(t, err)
+		require.Len(t, actual, 2)
+		require.Equal(t, claimed.ID, actual[0].ID)
+		require.Equal(t, "replica-a", actual[0].LeaseOwner)
+	})
+
+	t.Run("Recover", func(t *testing.T) {
+		db := GetSQLiteDB(t)
+		repo := NewRunRepository(db)
+		defer CloseDB(t, db)
+
+		stack := newTestStack(t, db)
+		run := newRun(t, repo, stack, "dev", constant.RunStatusQueued)
+		_, err := repo.Claim(ctx, "replica-a", time.Minute)
+		require.NoError(t, err)
+
+		// An active lease is never recovered.
+		recovered, err := repo.Recover(ctx, run.ID, "replica-a", time.Now(), constant.RunStatusFailed, constant.RunResultFailed)
+		require.NoError(t, err)
+		require.False(t, recovered)
+
+		expired := time.Now().Add(2 * time.Minute)
+		recovered, err = repo.Recover(ctx, run.ID, "replica-a", expired, constant.RunStatusFailed, constant.RunResultFailed)
+		require.NoError(t, err)
+		require.True(t, recovered)
+		recovered, err = repo.Recover(ctx, run.ID, "replica-a", expired, constant.RunStatusFailed, constant.RunResultFailed)
+		require.NoError(t, err)
+		require.False(t, recovered)
+
+		// The runs with a NULL lease owner are recovered as well.
+		legacy := newRun(t, repo, stack, "prod", constant.RunStatusInProgress)
+		require.NoError(t, db.Model(&RunModel{}).Where("id = ?", legacy.ID).Update("lease_owner", nil).Error)
+		recovered, err = repo.Recover(ctx, legacy.ID, "", time.Now(), constant.RunStatusFailed, constant.RunResultFailed)
+		require.NoError(t, err)
+		require.True(t, recovered)
+	})
+
+	t.Run("Finish", func(t *testing.T) {
+		db := GetSQLiteDB(t)
+		repo := NewRunRepository(db)
+		defer CloseDB(t, db)
+
+		run := newRun(t, repo, newTestStack(t, db), "dev", constant.RunStatusQueued)
+		_, err := repo.Claim(ctx, "replica-a", time.Minute)
+		require.NoError(t, err)
+
+		// Only the lease owner can finish a run.
+		finished, err := repo.Finish(ctx, run.ID, "replica-b", constant.RunStatusCancelled, constant.RunResultCancelled, "")
+		require.NoError(t, err)
+		require.False(t, finished)
+
+		// A run re-queued after its owner lost the lease keeps the status set
+		// by the recovery.
+		recovered, err := repo.Recover(ctx, run.ID, "replica-a", time.Now().Add(2*time.Minute), constant.RunStatusQueued, "")
+		require.NoError(t, err)
+		require.True(t, recovered)
+		finished, err = repo.Finish(ctx, run.ID, "replica-a", constant.RunStatusCancelled, constant.RunResultCancelled, "")
+		require.NoError(t, err)
+		require.False(t, finished)
+
+		_, err = repo.Claim(ctx, "replica-b", time.Minute)
+		require.NoError(t, err)
+		finished, err = repo.Finish(ctx, run.ID, "replica-b", constant.RunStatusSucceeded, "", "logs")
+		require.NoError(t, err)
+		require.True(t, finished)
+		actual, err := repo.Get(ctx, run.ID)
+		require.NoError(t, err)
+		require.Equal(t, constant.RunStatusSucceeded, actual.Status)
+		require.Equal(t, "logs", actual.Logs)
+	})
+
+	t.Run("UpdateVariableSets", func(t *testing.T) {
+		db := GetSQLiteDB(t)
+		repo := NewRunRepository(db)
+		defer CloseDB(t, db)
+
+		run := newRun(t, repo, newTestStack(t, db), "dev", constant.RunStatusQueued)
+		_, err := repo.Claim(ctx, "replica-a", time.Minute)
+		require.NoError(t, err)
+		// The run finishes after the stale copy is read.
+		finished, err := repo.Finish(ctx, run.ID, "replica-a", constant.RunStatusSucceeded, "", "logs")
+		require.NoError(t, err)
+		require.True(t, finished)
+
+		variableSets := []entity.RunVariableSet{{Name: "common", Version: 2}}
+		require.NoError(t, repo.UpdateVariableSets(ctx, run.ID, variableSets))
+		actual, err := repo.Get(ctx, run.ID)
+		require.NoError(t, err)
+		require.Equal(t, variableSets, actual.VariableSets)
+		require.Equal(t, constant.RunStatusSucceeded, actual.Status)
+		require.Equal(t, "logs", actual.Logs)
 	})
 }
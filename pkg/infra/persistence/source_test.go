@@ -5,7 +5,6 @@ import (
 	"net/url"
 	"testing"
 
-	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 	"kusionstack.io/kusion/pkg/domain/constant"
@@ -13,175 +12,127 @@ import (
 )
 
 func TestSourceRepository(t *testing.T) {
+	ctx := context.Background()
 	mockRemote := "https://github.com/mockorg/mockrepo"
 	mockRemoteURL, err := url.Parse(mockRemote)
 	require.NoError(t, err)
+	newSource := func(name string, remote *url.URL) *entity.Source {
+		return &entity.Source{
+			Name:           name,
+			SourceProvider: constant.SourceProviderTypeOCI,
+			Remote:         remote,
+			Description:    "i am a description",
+			Labels:         []string{"testLabel"},
+			Owners:         []string{"hua.li", "xiaoming.li"},
+		}
+	}
 
 	t.Run("Create", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewSourceRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var (
-			expectedID, expectedRows uint = 1, 1
-			actual                        = entity.Source{
-				Name:           "mockedSource",
-				SourceProvider: constant.SourceProviderTypeOCI,
-				Remote:         mockRemoteURL,
-				Description:    "i am a description",
-				Labels:         []string{"testLabel"},
-				Owners:         []string{"hua.li", "xiaoming.li"},
-			}
-		)
-		sqlMock.ExpectBegin()
-		sqlMock.ExpectExec("INSERT").
-			WillReturnResult(sqlmock.NewResult(int64(expectedID), int64(expectedRows)))
-		sqlMock.ExpectCommit()
-		err = repo.Create(context.Background(), &actual)
+		db := GetSQLiteDB(t)
+		repo := NewSourceRepository(db)
+		defer CloseDB(t, db)
+
+		actual := newSource("mockedSource", mockRemoteURL)
+		err := repo.Create(ctx, actual)
 		require.NoError(t, err)
-		require.Equal(t, expectedID, actual.ID)
+		require.Equal(t, uint(1), actual.ID)
 	})
 
 	t.Run("Delete existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewSourceRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var expectedID uint = 1
-		sqlMock.ExpectBegin()
-		sqlMock.ExpectQuery("SELECT").
-			WillReturnRows(sqlmock.NewRows([]string{"id"}).
-				AddRow(1))
-		sqlMock.ExpectExec("DELETE").WillReturnResult(sqlmock.NewResult(int64(expectedID), int64(0)))
-		sqlMock.ExpectCommit()
-		err = repo.Delete(context.Background(), expectedID)
+		db := GetSQLiteDB(t)
+		repo := NewSourceRepository(db)
+		defer CloseDB(t, db)
+
+		source := newSource("mockedSource", mockRemoteURL)
+		require.NoError(t, repo.Create(ctx, source))
+		err := repo.Delete(ctx, source.ID)
 		require.NoError(t, err)
+		_, err = repo.Get(ctx, source.ID)
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
 	})
 
 	t.Run("Delete not existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewSourceRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		sqlMock.ExpectBegin()
-		sqlMock.ExpectQuery("SELECT").
-			WillReturnRows(sqlmock.NewRows([]string{"id"}))
-		err = repo.Delete(context.Background(), 1)
+		db := GetSQLiteDB(t)
+		repo := NewSourceRepository(db)
+		defer CloseDB(t, db)
+
+		err := repo.Delete(ctx, 1)
 		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
 	})
 
 	t.Run("Update existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
+		db := GetSQLiteDB(t)
+		repo := NewSourceRepository(db)
+		defer CloseDB(t, db)
+
+		source := newSource("mockedSource", mockRemoteURL)
+		require.NoError(t, repo.Create(ctx, source))
+		source.SourceProvider = constant.SourceProviderTypeGithub
+		err := repo.Update(ctx, source)
 		require.NoError(t, err)
-		repo := NewSourceRepository(fakeGDB)
-
-		var (
-			expectedID, expectedRows uint = 1, 1
-			actual                        = entity.Source{
-				ID:             1,
-				SourceProvider: constant.SourceProviderTypeGithub,
-				Remote:         mockRemoteURL,
-			}
-		)
-		sqlMock.ExpectExec("UPDATE").
-			WillReturnResult(sqlmock.NewResult(int64(expectedID), int64(expectedRows)))
-		err = repo.Update(context.Background(), &actual)
+		updated, err := repo.Get(ctx, source.ID)
 		require.NoError(t, err)
+		require.Equal(t, constant.SourceProviderTypeGithub, updated.SourceProvider)
 	})
 
 	t.Run("Update not existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewSourceRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
+		db := GetSQLiteDB(t)
+		repo := NewSourceRepository(db)
+		defer CloseDB(t, db)
 
 		actual := entity.Source{
 			SourceProvider: constant.SourceProviderTypeGithub,
 			Remote:         mockRemoteURL,
 		}
-		err = repo.Update(context.Background(), &actual)
+		err := repo.Update(ctx, &actual)
 		require.ErrorIs(t, err, gorm.ErrMissingWhereClause)
 	})
 
 	t.Run("Get", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewSourceRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var (
-			expectedID                 uint = 1
-			expectedSourceProviderType      = constant.SourceProviderTypeGithub
-			expectedRemote                  = mockRemote
-		)
-		sqlMock.ExpectQuery("SELECT").
-			WillReturnRows(sqlmock.NewRows([]string{"id", "source_provider", "remote"}).
-				AddRow(expectedID, string(expectedSourceProviderType), expectedRemote))
-		actual, err := repo.Get(context.Background(), expectedID)
+		db := GetSQLiteDB(t)
+		repo := NewSourceRepository(db)
+		defer CloseDB(t, db)
+
+		source := newSource("mockedSource", mockRemoteURL)
+		require.NoError(t, repo.Create(ctx, source))
+
+		actual, err := repo.Get(ctx, source.ID)
 		require.NoError(t, err)
-		require.Equal(t, expectedID, actual.ID)
-		require.Equal(t, expectedSourceProviderType, actual.SourceProvider)
-		require.Equal(t, expectedRemote, actual.Remote.String())
+		require.Equal(t, source.ID, actual.ID)
+		require.Equal(t, constant.SourceProviderTypeOCI, actual.SourceProvider)
+		require.Equal(t, mockRemote, actual.Remote.String())
 	})
 
 	t.Run("Get source entity by remote", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewSourceRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var (
-			expectedID                 uint = 1
-			expectedSourceProviderType      = constant.SourceProviderTypeOCI
-			expectedRemote                  = mockRemote
-		)
-		sqlMock.ExpectQuery("SELECT").
-			WillReturnRows(sqlmock.NewRows([]string{"id", "source_provider", "remote"}).
-				AddRow(expectedID, string(expectedSourceProviderType), expectedRemote))
-		actual, err := repo.GetByRemote(context.Background(), expectedRemote)
+		db := GetSQLiteDB(t)
+		repo := NewSourceRepository(db)
+		defer CloseDB(t, db)
+
+		source := newSource("mockedSource", mockRemoteURL)
+		require.NoError(t, repo.Create(ctx, source))
+
+		actual, err := repo.GetByRemote(ctx, mockRemote)
 		require.NoError(t, err)
-		require.Equal(t, expectedID, actual.ID)
-		require.Equal(t, expectedSourceProviderType, actual.SourceProvider)
-		require.Equal(t, expectedRemote, actual.Remote.String())
+		require.Equal(t, source.ID, actual.ID)
+		require.Equal(t, constant.SourceProviderTypeOCI, actual.SourceProvider)
+		require.Equal(t, mockRemote, actual.Remote.String())
+
+		_, err = repo.GetByRemote(ctx, "https://github.com/mockorg/missing")
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
 	})
 
 	t.Run("List", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewSourceRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var (
-			expectedIDFirst              uint = 1
-			expectedRemoteFirst               = "https://remote/Mocked/Source"
-			expectedSourceProviderFirst       = constant.SourceProviderTypeGithub
-			expectedIDSecond             uint = 2
-			expectedRemoteSecond              = "local://mockedSource"
-			expectedSourceProviderSecond      = constant.SourceProviderTypeGithub
-		)
-
-		sqlMock.ExpectQuery("SELECT count(.*) FROM `source`").
-			WillReturnRows(
-				sqlmock.NewRows([]string{"count"}).
-					AddRow(2))
-
-		sqlMock.ExpectQuery("SELECT .* FROM `source`").
-			WillReturnRows(
-				sqlmock.NewRows([]string{"id", "remote", "source_provider"}).
-					AddRow(expectedIDFirst, expectedRemoteFirst, expectedSourceProviderFirst).
-					AddRow(expectedIDSecond, expectedRemoteSecond, expectedSourceProviderSecond))
-
-		actual, err := repo.List(context.Background(), &entity.SourceFilter{
+		db := GetSQLiteDB(t)
+		repo := NewSourceRepository(db)
+		defer CloseDB(t, db)
+
+		for i, remote := range []string{"https://remote/Mocked/Source", "local://mockedSource"} {
+			remoteURL, err := url.Parse(remote)
+			require.NoError(t, err)
+			require.NoError(t, repo.Create(ctx, newSource("mockedSource"+string(rune('A'+i)), remoteURL)))
+		}
+
+		actual, err := repo.List(ctx, &entity.SourceFilter{
 			Pagination: &entity.Pagination{
 				Page:     constant.CommonPageDefault,
 				PageSize: constant.CommonPageSizeDefault,
@@ -191,5 +142,6 @@ func TestSourceRepository(t *testing.T) {
 		})
 		require.NoError(t, err)
 		require.Len(t, actual.Sources, 2)
+		require.Equal(t, 2, actual.Total)
 	})
 }
@@ -103,10 +103,7 @@ func (r *stackRepository) List(ctx context.Context, filter *entity.StackFilter,
 	stackEntityList := make([]*entity.Stack, 0)
 	pattern, args := GetStackQuery(filter)
 
-	sortArgs := sortOptions.Field
-	if !sortOptions.Ascending {
-		sortArgs += " DESC"
-	}
+	sortArgs := GetSortArgs("stack", sortOptions)
 
 	searchResult := r.db.WithContext(ctx).
 		Preload("Project").Preload("Project.Organization").Preload("Project.Source").
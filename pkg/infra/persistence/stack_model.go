@@ -12,12 +12,12 @@ import (
 // StackModel is a DO used to map the entity to the database.
 type StackModel struct {
 	gorm.Model
-	Name                  string `gorm:"index:unique_project,unique"`
+	Name                  string `gorm:"index:unique_stack,unique"`
 	ProjectID             uint
 	Project               *ProjectModel
 	Description           string
 	Type                  string
-	Path                  string `gorm:"index:unique_project,unique"`
+	Path                  string `gorm:"index:unique_stack,unique"`
 	DesiredVersion        string
 	Labels                MultiString
 	Owners                MultiString
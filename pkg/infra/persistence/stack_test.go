@@ -2,11 +2,9 @@ package persistence
 
 import (
 	"context"
-	"net/url"
 	"testing"
 	"time"
 
-	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 	"kusionstack.io/kusion/pkg/domain/constant"
@@ -14,170 +12,107 @@ import (
 )
 
 func TestStackRepository(t *testing.T) {
-	mockRemote := "https://github.com/mockorg/mockrepo"
-	mockRemoteURL, err := url.Parse(mockRemote)
-	require.NoError(t, err)
+	ctx := context.Background()
 
 	t.Run("Create", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewStackRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var (
-			expectedID, expectedRows uint = 1, 1
-			actual                        = entity.Stack{
-				Name: "mockedStack",
-				Project: &entity.Project{
-					ID:   1,
-					Name: "mockedProject",
-					Path: "/path/to/project",
-					Source: &entity.Source{
-						ID:             1,
-						Name:           "mockedSource",
-						SourceProvider: constant.SourceProviderTypeGithub,
-						Remote:         mockRemoteURL,
-					},
-					Organization: &entity.Organization{
-						Name:   "mockedOrg",
-						ID:     1,
-						Owners: []string{"hua.li", "xiaoming.li"},
-					},
-				},
-				Path:                 "/path/to/stack",
-				DesiredVersion:       "master",
-				Labels:               []string{"testLabel"},
-				Owners:               []string{"hua.li", "xiaoming.li"},
-				SyncState:            constant.StackStateUnSynced,
-				LastAppliedTimestamp: time.Now(),
-			}
-		)
-		sqlMock.ExpectBegin()
-		sqlMock.ExpectExec("INSERT").
-			WillReturnResult(sqlmock.NewResult(int64(expectedID), int64(expectedRows)))
-		sqlMock.ExpectCommit()
-		err = repo.Create(context.Background(), &actual)
+		db := GetSQLiteDB(t)
+		repo := NewStackRepository(db)
+		defer CloseDB(t, db)
+
+		actual := entity.Stack{
+			Name:                 "mockedStack",
+			Project:              newTestProject(t, db),
+			Path:                 "/path/to/stack",
+			DesiredVersion:       "master",
+			Labels:               []string{"testLabel"},
+			Owners:               []string{"hua.li", "xiaoming.li"},
+			SyncState:            constant.StackStateUnSynced,
+			LastAppliedTimestamp: time.Now(),
+		}
+		err := repo.Create(ctx, &actual)
 		require.NoError(t, err)
-		require.Equal(t, expectedID, actual.ID)
+		require.Equal(t, uint(1), actual.ID)
+
+		// The path of a stack is unique in its project, while a stack and a
+		// project may share a name.
+		duplicated := entity.Stack{Name: "mockedStack", Project: actual.Project, Path: "/path/to/stack", SyncState: constant.StackStateUnSynced}
+		require.Error(t, repo.Create(ctx, &duplicated))
+		sameName := entity.Stack{Name: "project", Project: actual.Project, Path: "/project/project", SyncState: constant.StackStateUnSynced}
+		require.NoError(t, repo.Create(ctx, &sameName))
 	})
 
 	t.Run("Delete existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewStackRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var expectedID uint = 1
-		sqlMock.ExpectBegin()
-		sqlMock.ExpectQuery("SELECT").
-			WillReturnRows(sqlmock.NewRows([]string{"id"}).
-				AddRow(1))
-		sqlMock.ExpectExec("DELETE").WillReturnResult(sqlmock.NewResult(int64(expectedID), int64(0)))
-		sqlMock.ExpectCommit()
-		err = repo.Delete(context.Background(), expectedID)
+		db := GetSQLiteDB(t)
+		repo := NewStackRepository(db)
+		defer CloseDB(t, db)
+
+		stack := newTestStack(t, db)
+		err := repo.Delete(ctx, stack.ID)
 		require.NoError(t, err)
+		_, err = repo.Get(ctx, stack.ID)
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
 	})
 
 	t.Run("Delete not existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewStackRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		sqlMock.ExpectBegin()
-		sqlMock.ExpectQuery("SELECT").
-			WillReturnRows(sqlmock.NewRows([]string{"id"}))
-		err = repo.Delete(context.Background(), 1)
+		db := GetSQLiteDB(t)
+		repo := NewStackRepository(db)
+		defer CloseDB(t, db)
+
+		err := repo.Delete(ctx, 1)
 		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
 	})
 
 	t.Run("Update existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
+		db := GetSQLiteDB(t)
+		repo := NewStackRepository(db)
+		defer CloseDB(t, db)
+
+		stack := newTestStack(t, db)
+		stack.Description = "updated"
+		err := repo.Update(ctx, stack)
 		require.NoError(t, err)
-		repo := NewStackRepository(fakeGDB)
-
-		var (
-			expectedID, expectedRows uint = 1, 1
-			actual                        = entity.Stack{
-				ID:        1,
-				SyncState: constant.StackStateUnSynced,
-			}
-		)
-		sqlMock.ExpectExec("UPDATE").
-			WillReturnResult(sqlmock.NewResult(int64(expectedID), int64(expectedRows)))
-		err = repo.Update(context.Background(), &actual)
+		updated, err := repo.Get(ctx, stack.ID)
 		require.NoError(t, err)
+		require.Equal(t, "updated", updated.Description)
+		require.Equal(t, stack.Project.ID, updated.Project.ID)
 	})
 
 	t.Run("Update not existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewStackRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
+		db := GetSQLiteDB(t)
+		repo := NewStackRepository(db)
+		defer CloseDB(t, db)
 
 		actual := entity.Stack{
-			SyncState: constant.StackStateUnSynced,
+			Name: "mockedStack",
 		}
-		err = repo.Update(context.Background(), &actual)
+		err := repo.Update(ctx, &actual)
 		require.ErrorIs(t, err, gorm.ErrMissingWhereClause)
 	})
 
 	t.Run("Get", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewStackRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var (
-			expectedID    uint = 1
-			expectedPath       = "/path/to/stack"
-			expectedState      = constant.StackStateUnSynced
-		)
-		sqlMock.ExpectQuery("SELECT .* FROM `stack`").
-			WillReturnRows(sqlmock.NewRows([]string{"id", "path", "sync_state", "Project__id", "Project__name", "Project__path"}).
-				AddRow(expectedID, expectedPath, string(expectedState), 1, "mockedProject", "/path/to/project"))
-
-		actual, err := repo.Get(context.Background(), expectedID)
+		db := GetSQLiteDB(t)
+		repo := NewStackRepository(db)
+		defer CloseDB(t, db)
+
+		stack := newTestStack(t, db)
+		actual, err := repo.Get(ctx, stack.ID)
 		require.NoError(t, err)
-		require.Equal(t, expectedID, actual.ID)
-		require.Equal(t, expectedState, actual.SyncState)
+		require.Equal(t, stack.ID, actual.ID)
+		require.Equal(t, "dev", actual.Name)
+		require.Equal(t, constant.StackStateUnSynced, actual.SyncState)
+		require.Equal(t, "project", actual.Project.Name)
 	})
 
 	t.Run("List", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewStackRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var (
-			expectedIDFirst         uint = 1
-			expectedNameFirst            = "mockedStack"
-			expectedPathFirst            = "/path/to/stack"
-			expectedSyncStateFirst       = constant.StackStateUnSynced
-			expectedIDSecond        uint = 2
-			expectedNameSecond           = "mockedStack2"
-			expectedPathSecond           = "/path/to/stack/2"
-			expectedSyncStateSecond      = constant.StackStateSynced
-		)
-
-		sqlMock.ExpectQuery("SELECT count(.*) FROM `stack`").
-			WillReturnRows(
-				sqlmock.NewRows([]string{"count"}).
-					AddRow(2))
-
-		sqlMock.ExpectQuery("SELECT .* FROM `stack`").
-			WillReturnRows(
-				sqlmock.NewRows([]string{"id", "name", "path", "sync_state", "Project__id", "Project__name", "Project__path"}).
-					AddRow(expectedIDFirst, expectedNameFirst, expectedPathFirst, expectedSyncStateFirst, 1, "mockedProject", "path/to/project").
-					AddRow(expectedIDSecond, expectedNameSecond, expectedPathSecond, expectedSyncStateSecond, 2, "mockedProject2", "path/to/project2"))
-
-		actual, err := repo.List(context.Background(), &entity.StackFilter{
+		db := GetSQLiteDB(t)
+		repo := NewStackRepository(db)
+		defer CloseDB(t, db)
+
+		first := newTestStack(t, db)
+		second := &entity.Stack{Name: "mockedStack2", Path: "/path/to/stack/2", Project: first.Project, SyncState: constant.StackStateSynced}
+		require.NoError(t, repo.Create(ctx, second))
+
+		actual, err := repo.List(ctx, &entity.StackFilter{
 			Pagination: &entity.Pagination{
 				Page:     constant.CommonPageDefault,
 				PageSize: constant.CommonPageSizeDefault,
@@ -187,46 +122,9 @@ func TestStackRepository(t *testing.T) {
 		})
 		require.NoError(t, err)
 		require.Len(t, actual.Stacks, 2)
+		require.Equal(t, 2, actual.Total)
+		require.Equal(t, second.ID, actual.Stacks[0].ID)
+		require.Equal(t, constant.StackStateSynced, actual.Stacks[0].SyncState)
+		require.Equal(t, "project", actual.Stacks[0].Project.Name)
 	})
-
-	// t.Run("Get stack entity by source id and path", func(t *testing.T) {
-	// 	fakeGDB, sqlMock, err := GetMockDB()
-	// 	require.NoError(t, err)
-	// 	repo := NewStackRepository(fakeGDB)
-	// 	defer CloseDB(t, fakeGDB)
-	// 	defer sqlMock.ExpectClose()
-
-	// 	var (
-	// 		expectedID        uint = 1
-	// 		expectedState          = constant.StackStateUnSynced
-	// 	)
-	// 	sqlMock.ExpectQuery("SELECT.*FROM "stack"").
-	// 		WillReturnRows(sqlmock.NewRows([]string{"id", "source_id", "path", "sync_state", "Source__source_provider"}).
-	// 			AddRow(expectedID, 2, "/path/to/ws", string(expectedState), string(constant.SourceProviderTypeGithub)))
-	// 	actual, err := repo.GetBy(context.Background(), 2, "/path/to/ws")
-	// 	require.NoError(t, err)
-	// 	require.Equal(t, expectedID, actual.ID)
-	// 	require.Equal(t, expectedState, actual.State)
-	// })
-
-	// t.Run("Find", func(t *testing.T) {
-	// 	fakeGDB, sqlMock, err := GetMockDB()
-	// 	require.NoError(t, err)
-	// 	repo := NewStackRepository(fakeGDB)
-	// 	defer CloseDB(t, fakeGDB)
-	// 	defer sqlMock.ExpectClose()
-
-	// 	sqlMock.ExpectQuery("SELECT").
-	// 		WillReturnRows(sqlmock.NewRows([]string{"id", "state", "framework", "Source__source_provider"}).
-	// 			AddRow(1, string(constant.StackStateUnSynced), string(constant.FrameworkTypeKusion), string(constant.SourceProviderTypeRepoServer)).
-	// 			AddRow(2, string(constant.StackStateUnSynced), string(constant.FrameworkTypeTerraform), string(constant.SourceProviderTypeRepoServer)))
-	// 	actuals, err := repo.Find(context.Background(), repository.StackQuery{
-	// 		Bound: repository.Bound{
-	// 			Offset: 1,
-	// 			Limit:  10,
-	// 		},
-	// 	})
-	// 	require.NoError(t, err)
-	// 	require.Equal(t, 2, len(actuals))
-	// })
 }
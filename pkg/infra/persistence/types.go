@@ -30,4 +30,7 @@ var (
 	ErrVariableModelNil               = errors.New("variable model can't be nil")
 	ErrFailedToGetRunType             = errors.New("failed to parse run type")
 	ErrFailedToGetRunStatus           = errors.New("failed to parse run status")
+	ErrWebhookModelNil                = errors.New("webhook model can't be nil")
+	ErrWebhookDeliveryModelNil        = errors.New("webhook delivery model can't be nil")
+	ErrFailedToGetWebhookFormat       = errors.New("failed to parse webhook format")
 )
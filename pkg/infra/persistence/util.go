@@ -8,9 +8,11 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/glebarez/sqlite"
 	"github.com/stretchr/testify/require"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 	"gorm.io/gorm/schema"
 
 	"kusionstack.io/kusion/pkg/domain/entity"
@@ -51,7 +53,7 @@ func (s MultiString) GormDataType() string {
 func (s MultiString) GormDBDataType(db *gorm.DB, field *schema.Field) string {
 	// returns different database type based on driver name
 	switch db.Dialector.Name() {
-	case "mysql", "sqlite":
+	case "mysql", "postgres", "sqlite":
 		return "text"
 	}
 	return ""
@@ -83,6 +85,24 @@ func GetMockDB() (*gorm.DB, sqlmock.Sqlmock, error) {
 	return fakeGDB, sqlMock, nil
 }
 
+// Create an in-memory SQLite database with the migrated schema, for the tests
+// that run against a real database instead of sqlmock. Every call returns a
+// separate database.
+func GetSQLiteDB(t *testing.T) *gorm.DB {
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared&_pragma=foreign_keys(1)", strings.ReplaceAll(t.Name(), "/", "_"))
+	gdb, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	// Each connection to a memory database sees its own database unless the
+	// connections share the cache, keep a single one to avoid lock contention.
+	db, err := gdb.DB()
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	require.NoError(t, Migrate(gdb))
+	return gdb
+}
+
 // Close the gorm database connection
 func CloseDB(t *testing.T, gdb *gorm.DB) {
 	db, err := gdb.DB()
@@ -252,9 +272,68 @@ func GetVariableQuery(filter *entity.VariableFilter) (string, []interface{}) {
 		pattern = append(pattern, "variable.variable_set = ?")
 		args = append(args, filter.VariableSet)
 	}
+	if filter.Type != "" {
+		pattern = append(pattern, "variable.type = ?")
+		args = append(args, filter.Type)
+	}
 	return CombineQueryParts(pattern), args
 }
 
+func GetWebhookQuery(filter *entity.WebhookFilter) (string, []interface{}) {
+	pattern := make([]string, 0)
+	args := make([]interface{}, 0)
+	if filter.OrganizationID != 0 {
+		pattern = append(pattern, "webhook.organization_id = ?")
+		args = append(args, filter.OrganizationID)
+	}
+	if filter.ProjectID != 0 {
+		pattern = append(pattern, "webhook.project_id = ?")
+		args = append(args, filter.ProjectID)
+	}
+	if filter.StackID != 0 {
+		pattern = append(pattern, "webhook.stack_id = ?")
+		args = append(args, filter.StackID)
+	}
+	if filter.Enabled != nil {
+		pattern = append(pattern, "webhook.enabled = ?")
+		args = append(args, *filter.Enabled)
+	}
+	return CombineQueryParts(pattern), args
+}
+
+func GetWebhookDeliveryQuery(filter *entity.WebhookDeliveryFilter) (string, []interface{}) {
+	pattern := make([]string, 0)
+	args := make([]interface{}, 0)
+	if filter.WebhookID != 0 {
+		pattern = append(pattern, "webhook_delivery.webhook_id = ?")
+		args = append(args, filter.WebhookID)
+	}
+	if filter.RunID != 0 {
+		pattern = append(pattern, "webhook_delivery.run_id = ?")
+		args = append(args, filter.RunID)
+	}
+	if filter.Status != "" {
+		pattern = append(pattern, "webhook_delivery.status = ?")
+		args = append(args, filter.Status)
+	}
+	return CombineQueryParts(pattern), args
+}
+
+// GetSortArgs builds the order clause of the sort options for a list query
+// that joins other tables. The field is qualified with the table name since
+// PostgreSQL and SQLite, unlike MySQL, do not resolve an ambiguous column
+// against the select list.
+func GetSortArgs(table string, sortOptions *entity.SortOptions) string {
+	sortArgs := sortOptions.Field
+	if !strings.Contains(sortArgs, ".") {
+		sortArgs = fmt.Sprintf("%s.%s", table, sortArgs)
+	}
+	if !sortOptions.Ascending {
+		sortArgs += " DESC"
+	}
+	return sortArgs
+}
+
 func CombineQueryParts(queryParts []string) string {
 	queryString := ""
 	if len(queryParts) > 0 {
@@ -294,5 +373,11 @@ func AutoMigrate(db *gorm.DB) error {
 	if err := db.AutoMigrate(&RunModel{}); err != nil {
 		return err
 	}
+	if err := db.AutoMigrate(&WebhookModel{}); err != nil {
+		return err
+	}
+	if err := db.AutoMigrate(&WebhookDeliveryModel{}); err != nil {
+		return err
+	}
 	return nil
 }
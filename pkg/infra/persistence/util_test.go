@@ -1,13 +1,68 @@
 package persistence
 
 import (
+	"context"
+	"net/url"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"kusionstack.io/kusion/pkg/domain/constant"
 	"kusionstack.io/kusion/pkg/domain/entity"
 )
 
+// newTestProject creates a project along with its organization and source.
+func newTestProject(t *testing.T, db *gorm.DB) *entity.Project {
+	ctx := context.Background()
+	remote, err := url.Parse("https://github.com/KusionStack/kusion")
+	require.NoError(t, err)
+
+	org := &entity.Organization{Name: "org", Owners: []string{"kusion"}}
+	require.NoError(t, NewOrganizationRepository(db).Create(ctx, org))
+	source := &entity.Source{Name: "source", SourceProvider: constant.SourceProviderTypeGit, Remote: remote}
+	require.NoError(t, NewSourceRepository(db).Create(ctx, source))
+	project := &entity.Project{Name: "project", Path: "/project", Organization: org, Source: source, Owners: []string{"kusion"}}
+	require.NoError(t, NewProjectRepository(db).Create(ctx, project))
+	return project
+}
+
+// newTestStack creates a stack along with its project.
+func newTestStack(t *testing.T, db *gorm.DB) *entity.Stack {
+	stack := &entity.Stack{Name: "dev", Path: "/project/dev", Project: newTestProject(t, db), SyncState: constant.StackStateUnSynced}
+	require.NoError(t, NewStackRepository(db).Create(context.Background(), stack))
+	return stack
+}
+
+// newTestWorkspace creates a workspace along with its backend.
+func newTestWorkspace(t *testing.T, db *gorm.DB) *entity.Workspace {
+	ctx := context.Background()
+	backend := &entity.Backend{Name: "backend"}
+	require.NoError(t, NewBackendRepository(db).Create(ctx, backend))
+	workspace := &entity.Workspace{Name: "dev", Backend: backend, Owners: []string{"kusion"}}
+	require.NoError(t, NewWorkspaceRepository(db).Create(ctx, workspace))
+	return workspace
+}
+
+func TestMigrate(t *testing.T) {
+	db := GetSQLiteDB(t)
+	defer CloseDB(t, db)
+
+	// Migrate is idempotent and records every versioned migration once.
+	require.NoError(t, Migrate(db))
+	var applied []SchemaMigrationModel
+	require.NoError(t, db.Find(&applied).Error)
+	require.Len(t, applied, len(migrations))
+
+	for _, model := range []any{&ProjectModel{}, &StackModel{}, &RunModel{}, &WebhookModel{}} {
+		require.True(t, db.Migrator().HasTable(model))
+	}
+	require.True(t, db.Migrator().HasIndex(&StackModel{}, "unique_stack"))
+	require.True(t, db.Migrator().HasColumn(&RunModel{}, "LeaseExpiresAt"))
+}
+
 func TestGetResourceQuery(t *testing.T) {
 	testcases := []struct {
 		name          string
@@ -44,6 +44,9 @@ func (v *variableRepository) Create(ctx context.Context, dataEntity *entity.Vari
 		if err := tx.WithContext(ctx).Create(&dataModel).Error; err != nil {
 			return err
 		}
+		if err := increaseVariableSetVersion(ctx, tx, dataModel.VariableSet); err != nil {
+			return err
+		}
 
 		// Map fresh record's data into Entity.
 		newEntity, err := dataModel.ToEntity()
@@ -65,7 +68,11 @@ func (v *variableRepository) Delete(ctx context.Context, name, variableSet strin
 			return err
 		}
 
-		return tx.WithContext(ctx).Unscoped().Delete(&dataModel).Error
+		if err := tx.WithContext(ctx).Unscoped().Delete(&dataModel).Error; err != nil {
+			return err
+		}
+
+		return increaseVariableSetVersion(ctx, tx, variableSet)
 	})
 }
 
@@ -77,12 +84,14 @@ func (v *variableRepository) Update(ctx context.Context, dataEntity *entity.Vari
 		return err
 	}
 
-	if err := v.db.WithContext(ctx).
-		Where("name = ?", dataModel.Name).Where("variable_set = ?", dataModel.VariableSet).Updates(&dataModel).Error; err != nil {
-		return err
-	}
+	return v.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.WithContext(ctx).
+			Where("name = ?", dataModel.Name).Where("variable_set = ?", dataModel.VariableSet).Updates(&dataModel).Error; err != nil {
+			return err
+		}
 
-	return nil
+		return increaseVariableSetVersion(ctx, tx, dataModel.VariableSet)
+	})
 }
 
 // Get retrieves a variable by its name and the variable set it belongs to.
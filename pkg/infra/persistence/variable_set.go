@@ -38,6 +38,7 @@ func (vs *variableSetRepository) Create(ctx context.Context, dataEntity *entity.
 	if err := dataModel.FromEntity(dataEntity); err != nil {
 		return err
 	}
+	dataModel.Version = 1
 
 	return vs.db.Transaction(func(tx *gorm.DB) error {
 		// Create new record in the storage.
@@ -69,7 +70,8 @@ func (vs *variableSetRepository) Delete(ctx context.Context, name string) error
 	})
 }
 
-// Update updates an existing variable set in the repository.
+// Update updates an existing variable set in the repository and increases
+// its version.
 func (vs *variableSetRepository) Update(ctx context.Context, dataEntity *entity.VariableSet) error {
 	// Map the data from Entity to DO.
 	var dataModel VariableSetModel
@@ -77,12 +79,14 @@ func (vs *variableSetRepository) Update(ctx context.Context, dataEntity *entity.
 		return err
 	}
 
-	if err := vs.db.WithContext(ctx).
-		Where("name = ?", dataModel.Name).Updates(&dataModel).Error; err != nil {
-		return err
-	}
+	return vs.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.WithContext(ctx).Omit("Version").
+			Where("name = ?", dataModel.Name).Updates(&dataModel).Error; err != nil {
+			return err
+		}
 
-	return nil
+		return increaseVariableSetVersion(ctx, tx, dataModel.Name)
+	})
 }
 
 // Get retrieves a variable set by its name.
@@ -145,3 +149,10 @@ func (vs *variableSetRepository) List(ctx context.Context,
 		Total:        int(totalRows),
 	}, nil
 }
+
+// increaseVariableSetVersion increases the version of the variable set, so
+// that runs can record the exact variables they were executed with.
+func increaseVariableSetVersion(ctx context.Context, tx *gorm.DB, name string) error {
+	return tx.WithContext(ctx).Model(&VariableSetModel{}).
+		Where("name = ?", name).UpdateColumn("version", gorm.Expr("version + 1")).Error
+}
@@ -12,6 +12,8 @@ type VariableSetModel struct {
 	Name string `gorm:"index:unique_variable_set,unique"`
 	// Labels clarifies the scope of the variable set.
 	Labels map[string]string `gorm:"serializer:json" json:"labels"`
+	// Version is the version of the variable set.
+	Version uint `gorm:"default:1"`
 }
 
 // The TableName method returns the name of the database table that the struct is mapped to.
@@ -26,8 +28,9 @@ func (vs *VariableSetModel) ToEntity() (*entity.VariableSet, error) {
 	}
 
 	return &entity.VariableSet{
-		Name:   vs.Name,
-		Labels: vs.Labels,
+		Name:    vs.Name,
+		Labels:  vs.Labels,
+		Version: vs.Version,
 	}, nil
 }
 
@@ -39,6 +42,7 @@ func (vs *VariableSetModel) FromEntity(e *entity.VariableSet) error {
 
 	vs.Name = e.Name
 	vs.Labels = e.Labels
+	vs.Version = e.Version
 
 	return nil
 }
@@ -0,0 +1,201 @@
+package persistence
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"kusionstack.io/kusion/pkg/domain/entity"
+	"kusionstack.io/kusion/pkg/domain/repository"
+)
+
+// The webhookRepository type implements the repository.WebhookRepository interface.
+// If the webhookRepository type does not implement all the methods of the interface,
+// the compiler will produce an error.
+var _ repository.WebhookRepository = &webhookRepository{}
+
+// webhookRepository is a repository that stores webhooks in a gorm database.
+type webhookRepository struct {
+	// db is the underlying gorm database where webhooks are stored.
+	db *gorm.DB
+}
+
+// NewWebhookRepository creates a new webhook repository.
+func NewWebhookRepository(db *gorm.DB) repository.WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+// Create saves a webhook to the repository.
+func (r *webhookRepository) Create(ctx context.Context, dataEntity *entity.Webhook) error {
+	if err := dataEntity.Validate(); err != nil {
+		return err
+	}
+
+	// Map the data from Entity to DO
+	var dataModel WebhookModel
+	if err := dataModel.FromEntity(dataEntity); err != nil {
+		return err
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.WithContext(ctx).Create(&dataModel).Error; err != nil {
+			return err
+		}
+
+		dataEntity.ID = dataModel.ID
+		dataEntity.CreationTimestamp = dataModel.CreatedAt
+		dataEntity.UpdateTimestamp = dataModel.UpdatedAt
+
+		return nil
+	})
+}
+
+// Delete removes a webhook from the repository.
+func (r *webhookRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var dataModel WebhookModel
+		if err := tx.WithContext(ctx).First(&dataModel, id).Error; err != nil {
+			return err
+		}
+
+		return tx.WithContext(ctx).Unscoped().Delete(&dataModel).Error
+	})
+}
+
+// Update updates an existing webhook in the repository.
+func (r *webhookRepository) Update(ctx context.Context, dataEntity *entity.Webhook) error {
+	// Map the data from Entity to DO
+	var dataModel WebhookModel
+	if err := dataModel.FromEntity(dataEntity); err != nil {
+		return err
+	}
+
+	// Select all the fields so that disabling a webhook or clearing its
+	// scope is persisted as well.
+	return r.db.WithContext(ctx).Select("*").Omit("CreatedAt").Updates(&dataModel).Error
+}
+
+// Get retrieves a webhook by its ID.
+func (r *webhookRepository) Get(ctx context.Context, id uint) (*entity.Webhook, error) {
+	var dataModel WebhookModel
+	if err := r.db.WithContext(ctx).First(&dataModel, id).Error; err != nil {
+		return nil, err
+	}
+
+	return dataModel.ToEntity()
+}
+
+// List retrieves existing webhooks with filter and sort options.
+func (r *webhookRepository) List(ctx context.Context,
+	filter *entity.WebhookFilter, sortOptions *entity.SortOptions,
+) (*entity.WebhookListResult, error) {
+	var dataModel []WebhookModel
+	webhookEntityList := make([]*entity.Webhook, 0)
+	pattern, args := GetWebhookQuery(filter)
+
+	sortArgs := sortOptions.Field
+	if !sortOptions.Ascending {
+		sortArgs += " DESC"
+	}
+
+	searchResult := r.db.WithContext(ctx).Order(sortArgs).Where(pattern, args...)
+
+	// Get total rows.
+	var totalRows int64
+	searchResult.Model(dataModel).Count(&totalRows)
+
+	// Fetch paginated data from searchResult with offset and limit.
+	offset := (filter.Pagination.Page - 1) * filter.Pagination.PageSize
+	result := searchResult.Offset(offset).Limit(filter.Pagination.PageSize).Find(&dataModel)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	for _, webhook := range dataModel {
+		webhookEntity, err := webhook.ToEntity()
+		if err != nil {
+			return nil, err
+		}
+		webhookEntityList = append(webhookEntityList, webhookEntity)
+	}
+
+	return &entity.WebhookListResult{
+		Webhooks: webhookEntityList,
+		Total:    int(totalRows),
+	}, nil
+}
+
+// The webhookDeliveryRepository type implements the repository.WebhookDeliveryRepository
+// interface. If the webhookDeliveryRepository type does not implement all the methods of
+// the interface, the compiler will produce an error.
+var _ repository.WebhookDeliveryRepository = &webhookDeliveryRepository{}
+
+// webhookDeliveryRepository is a repository that stores webhook deliveries in a gorm database.
+type webhookDeliveryRepository struct {
+	// db is the underlying gorm database where webhook deliveries are stored.
+	db *gorm.DB
+}
+
+// NewWebhookDeliveryRepository creates a new webhook delivery repository.
+func NewWebhookDeliveryRepository(db *gorm.DB) repository.WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{db: db}
+}
+
+// Create saves a webhook delivery to the repository.
+func (r *webhookDeliveryRepository) Create(ctx context.Context, dataEntity *entity.WebhookDelivery) error {
+	// Map the data from Entity to DO
+	var dataModel WebhookDeliveryModel
+	if err := dataModel.FromEntity(dataEntity); err != nil {
+		return err
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.WithContext(ctx).Create(&dataModel).Error; err != nil {
+			return err
+		}
+
+		dataEntity.ID = dataModel.ID
+		dataEntity.CreationTimestamp = dataModel.CreatedAt
+
+		return nil
+	})
+}
+
+// List retrieves existing webhook deliveries with filter and sort options.
+func (r *webhookDeliveryRepository) List(ctx context.Context,
+	filter *entity.WebhookDeliveryFilter, sortOptions *entity.SortOptions,
+) (*entity.WebhookDeliveryListResult, error) {
+	var dataModel []WebhookDeliveryModel
+	deliveryEntityList := make([]*entity.WebhookDelivery, 0)
+	pattern, args := GetWebhookDeliveryQuery(filter)
+
+	sortArgs := sortOptions.Field
+	if !sortOptions.Ascending {
+		sortArgs += " DESC"
+	}
+
+	searchResult := r.db.WithContext(ctx).Order(sortArgs).Where(pattern, args...)
+
+	// Get total rows.
+	var totalRows int64
+	searchResult.Model(dataModel).Count(&totalRows)
+
+	// Fetch paginated data from searchResult with offset and limit.
+	offset := (filter.Pagination.Page - 1) * filter.Pagination.PageSize
+	result := searchResult.Offset(offset).Limit(filter.Pagination.PageSize).Find(&dataModel)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	for _, delivery := range dataModel {
+		deliveryEntity, err := delivery.ToEntity()
+		if err != nil {
+			return nil, err
+		}
+		deliveryEntityList = append(deliveryEntityList, deliveryEntity)
+	}
+
+	return &entity.WebhookDeliveryListResult{
+		Deliveries: deliveryEntityList,
+		Total:      int(totalRows),
+	}, nil
+}
@@ -0,0 +1,164 @@
+package persistence
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"kusionstack.io/kusion/pkg/domain/constant"
+	"kusionstack.io/kusion/pkg/domain/entity"
+)
+
+// WebhookModel is a DO used to map the entity to the database.
+type WebhookModel struct {
+	gorm.Model
+	// Name is the name of the webhook.
+	Name string `gorm:"index:unique_webhook,unique"`
+	// Description is a human-readable description of the webhook.
+	Description string
+	// URL is the receiver address the events are posted to.
+	URL string
+	// Secret is the key used to sign the payloads.
+	Secret string
+	// Format is the payload format of the receiver.
+	Format string
+	// Events is the list of subscribed events.
+	Events MultiString
+	// OrganizationID restricts the webhook to the runs of an organization.
+	OrganizationID uint
+	// ProjectID restricts the webhook to the runs of a project.
+	ProjectID uint
+	// StackID restricts the webhook to the runs of a stack.
+	StackID uint
+	// Enabled indicates whether the events are delivered to the webhook.
+	Enabled bool
+}
+
+// The TableName method returns the name of the database table that the struct is mapped to.
+func (m *WebhookModel) TableName() string {
+	return "webhook"
+}
+
+// ToEntity converts the DO to an entity.
+func (m *WebhookModel) ToEntity() (*entity.Webhook, error) {
+	if m == nil {
+		return nil, ErrWebhookModelNil
+	}
+
+	format, err := constant.ParseWebhookFormat(m.Format)
+	if err != nil {
+		return nil, ErrFailedToGetWebhookFormat
+	}
+
+	return &entity.Webhook{
+		ID:                m.ID,
+		Name:              m.Name,
+		Description:       m.Description,
+		URL:               m.URL,
+		Secret:            m.Secret,
+		Format:            format,
+		Events:            []string(m.Events),
+		OrganizationID:    m.OrganizationID,
+		ProjectID:         m.ProjectID,
+		StackID:           m.StackID,
+		Enabled:           m.Enabled,
+		CreationTimestamp: m.CreatedAt,
+		UpdateTimestamp:   m.UpdatedAt,
+	}, nil
+}
+
+// FromEntity converts an entity to a DO.
+func (m *WebhookModel) FromEntity(e *entity.Webhook) error {
+	if m == nil {
+		return ErrWebhookModelNil
+	}
+
+	m.ID = e.ID
+	m.Name = e.Name
+	m.Description = e.Description
+	m.URL = e.URL
+	m.Secret = e.Secret
+	m.Format = string(e.Format)
+	m.Events = MultiString(e.Events)
+	m.OrganizationID = e.OrganizationID
+	m.ProjectID = e.ProjectID
+	m.StackID = e.StackID
+	m.Enabled = e.Enabled
+	m.CreatedAt = e.CreationTimestamp
+	m.UpdatedAt = e.UpdateTimestamp
+
+	return nil
+}
+
+// WebhookDeliveryModel is a DO used to map the entity to the database.
+type WebhookDeliveryModel struct {
+	gorm.Model
+	// WebhookID is the id of the webhook the event is delivered to.
+	WebhookID uint `gorm:"index"`
+	// Event is the event that triggered the delivery.
+	Event string
+	// RunID is the id of the run that triggered the delivery.
+	RunID uint `gorm:"index"`
+	// Payload is the request body sent to the receiver.
+	Payload string `gorm:"type:text"`
+	// Status is the final status of the delivery.
+	Status string
+	// Attempts is the number of requests sent to the receiver.
+	Attempts int
+	// ResponseCode is the HTTP status code of the last attempt.
+	ResponseCode int
+	// ResponseBody is the truncated response body of the last attempt.
+	ResponseBody string `gorm:"type:text"`
+	// Error is the error message of the last failed attempt.
+	Error string `gorm:"type:text"`
+	// Duration is the total time spent delivering the event.
+	Duration time.Duration
+}
+
+// The TableName method returns the name of the database table that the struct is mapped to.
+func (m *WebhookDeliveryModel) TableName() string {
+	return "webhook_delivery"
+}
+
+// ToEntity converts the DO to an entity.
+func (m *WebhookDeliveryModel) ToEntity() (*entity.WebhookDelivery, error) {
+	if m == nil {
+		return nil, ErrWebhookDeliveryModelNil
+	}
+
+	return &entity.WebhookDelivery{
+		ID:                m.ID,
+		WebhookID:         m.WebhookID,
+		Event:             constant.WebhookEvent(m.Event),
+		RunID:             m.RunID,
+		Payload:           m.Payload,
+		Status:            constant.WebhookDeliveryStatus(m.Status),
+		Attempts:          m.Attempts,
+		ResponseCode:      m.ResponseCode,
+		ResponseBody:      m.ResponseBody,
+		Error:             m.Error,
+		Duration:          m.Duration,
+		CreationTimestamp: m.CreatedAt,
+	}, nil
+}
+
+// FromEntity converts an entity to a DO.
+func (m *WebhookDeliveryModel) FromEntity(e *entity.WebhookDelivery) error {
+	if m == nil {
+		return ErrWebhookDeliveryModelNil
+	}
+
+	m.ID = e.ID
+	m.WebhookID = e.WebhookID
+	m.Event = string(e.Event)
+	m.RunID = e.RunID
+	m.Payload = e.Payload
+	m.Status = string(e.Status)
+	m.Attempts = e.Attempts
+	m.ResponseCode = e.ResponseCode
+	m.ResponseBody = e.ResponseBody
+	m.Error = e.Error
+	m.Duration = e.Duration
+	m.CreatedAt = e.CreationTimestamp
+
+	return nil
+}
@@ -0,0 +1,190 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"kusionstack.io/kusion/pkg/domain/constant"
+	"kusionstack.io/kusion/pkg/domain/entity"
+)
+
+func TestWebhookRepository(t *testing.T) {
+	ctx := context.Background()
+	newWebhook := func(name string) *entity.Webhook {
+		return &entity.Webhook{
+			Name:    name,
+			URL:     "https://hooks.example.com/kusion",
+			Format:  constant.WebhookFormatSlack,
+			Events:  []string{string(constant.WebhookEventRunFailed), string(constant.WebhookEventRunCancelled)},
+			Enabled: true,
+		}
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		db := GetSQLiteDB(t)
+		repo := NewWebhookRepository(db)
+		defer CloseDB(t, db)
+
+		actual := newWebhook("mockedWebhook")
+		err := repo.Create(ctx, actual)
+		require.NoError(t, err)
+		require.Equal(t, uint(1), actual.ID)
+	})
+
+	t.Run("Create invalid webhook", func(t *testing.T) {
+		db := GetSQLiteDB(t)
+		repo := NewWebhookRepository(db)
+		defer CloseDB(t, db)
+
+		actual := entity.Webhook{
+			Name:   "mockedWebhook",
+			URL:    "https://hooks.example.com/kusion",
+			Events: []string{"run.unknown"},
+		}
+		err := repo.Create(ctx, &actual)
+		require.ErrorIs(t, err, constant.ErrInvalidWebhookEvent)
+	})
+
+	t.Run("Delete existing record", func(t *testing.T) {
+		db := GetSQLiteDB(t)
+		repo := NewWebhookRepository(db)
+		defer CloseDB(t, db)
+
+		webhook := newWebhook("mockedWebhook")
+		require.NoError(t, repo.Create(ctx, webhook))
+		err := repo.Delete(ctx, webhook.ID)
+		require.NoError(t, err)
+		_, err = repo.Get(ctx, webhook.ID)
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Delete not existing record", func(t *testing.T) {
+		db := GetSQLiteDB(t)
+		repo := NewWebhookRepository(db)
+		defer CloseDB(t, db)
+
+		err := repo.Delete(ctx, 1)
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Update existing record", func(t *testing.T) {
+		db := GetSQLiteDB(t)
+		repo := NewWebhookRepository(db)
+		defer CloseDB(t, db)
+
+		webhook := newWebhook("mockedWebhook")
+		require.NoError(t, repo.Create(ctx, webhook))
+		webhook.URL = "https://hooks.example.com/updated"
+		err := repo.Update(ctx, webhook)
+		require.NoError(t, err)
+		updated, err := repo.Get(ctx, webhook.ID)
+		require.NoError(t, err)
+		require.Equal(t, "https://hooks.example.com/updated", updated.URL)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		db := GetSQLiteDB(t)
+		repo := NewWebhookRepository(db)
+		defer CloseDB(t, db)
+
+		webhook := newWebhook("mockedWebhook")
+		require.NoError(t, repo.Create(ctx, webhook))
+		actual, err := repo.Get(ctx, webhook.ID)
+		require.NoError(t, err)
+		require.Equal(t, webhook.ID, actual.ID)
+		require.Equal(t, constant.WebhookFormatSlack, actual.Format)
+		require.Equal(t, []string{"run.failed", "run.cancelled"}, actual.Events)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		db := GetSQLiteDB(t)
+		repo := NewWebhookRepository(db)
+		defer CloseDB(t, db)
+
+		stack := newTestStack(t, db)
+		first, second := newWebhook("mockedWebhook"), newWebhook("mockedWebhook2")
+		second.StackID = stack.ID
+		second.Enabled = false
+		require.NoError(t, repo.Create(ctx, first))
+		require.NoError(t, repo.Create(ctx, second))
+
+		actual, err := repo.List(ctx, &entity.WebhookFilter{
+			Pagination: &entity.Pagination{
+				Page:     constant.CommonPageDefault,
+				PageSize: constant.CommonPageSizeDefault,
+			},
+		}, &entity.SortOptions{
+			Field: constant.SortByID,
+		})
+		require.NoError(t, err)
+		require.Len(t, actual.Webhooks, 2)
+		require.Equal(t, 2, actual.Total)
+
+		// The webhooks are filtered by their scopes and whether enabled.
+		enabled := false
+		actual, err = repo.List(ctx, &entity.WebhookFilter{
+			StackID: stack.ID,
+			Enabled: &enabled,
+			Pagination: &entity.Pagination{
+				Page:     constant.CommonPageDefault,
+				PageSize: constant.CommonPageSizeDefault,
+			},
+		}, &entity.SortOptions{
+			Field: constant.SortByID,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, actual.Total)
+		require.Equal(t, second.ID, actual.Webhooks[0].ID)
+		require.Equal(t, second.Events, actual.Webhooks[0].Events)
+	})
+}
+
+func TestWebhookDeliveryRepository(t *testing.T) {
+	ctx := context.Background()
+	newDelivery := func(webhookID uint, status constant.WebhookDeliveryStatus) *entity.WebhookDelivery {
+		return &entity.WebhookDelivery{
+			WebhookID: webhookID,
+			Event:     constant.WebhookEventRunFailed,
+			RunID:     2,
+			Status:    status,
+			Attempts:  1,
+		}
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		db := GetSQLiteDB(t)
+		repo := NewWebhookDeliveryRepository(db)
+		defer CloseDB(t, db)
+
+		actual := newDelivery(1, constant.WebhookDeliverySucceeded)
+		err := repo.Create(ctx, actual)
+		require.NoError(t, err)
+		require.Equal(t, uint(1), actual.ID)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		db := GetSQLiteDB(t)
+		repo := NewWebhookDeliveryRepository(db)
+		defer CloseDB(t, db)
+
+		require.NoError(t, repo.Create(ctx, newDelivery(1, constant.WebhookDeliveryFailed)))
+		require.NoError(t, repo.Create(ctx, newDelivery(2, constant.WebhookDeliverySucceeded)))
+
+		actual, err := repo.List(ctx, &entity.WebhookDeliveryFilter{
+			WebhookID: 1,
+			Pagination: &entity.Pagination{
+				Page:     constant.CommonPageDefault,
+				PageSize: constant.CommonPageSizeDefault,
+			},
+		}, &entity.SortOptions{
+			Field: constant.SortByID,
+		})
+		require.NoError(t, err)
+		require.Len(t, actual.Deliveries, 1)
+		require.Equal(t, 1, actual.Total)
+		require.Equal(t, constant.WebhookDeliveryFailed, actual.Deliveries[0].Status)
+	})
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"testing"
 
-	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 	"kusionstack.io/kusion/pkg/domain/constant"
@@ -12,141 +11,100 @@ import (
 )
 
 func TestWorkspaceRepository(t *testing.T) {
+	ctx := context.Background()
+
 	t.Run("Create", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewWorkspaceRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var (
-			expectedID, expectedRows uint = 1, 1
-			actual                        = entity.Workspace{
-				Name:        "mockedWorkspace",
-				DisplayName: "mockedDisplayName",
-				Backend:     &entity.Backend{ID: 1},
-				Owners:      []string{"hua.li", "xiaoming.li"},
-			}
-		)
-		sqlMock.ExpectBegin()
-		sqlMock.ExpectExec("INSERT").
-			WillReturnResult(sqlmock.NewResult(int64(expectedID), int64(expectedRows)))
-		sqlMock.ExpectCommit()
-		err = repo.Create(context.Background(), &actual)
+		db := GetSQLiteDB(t)
+		repo := NewWorkspaceRepository(db)
+		defer CloseDB(t, db)
+
+		backend := &entity.Backend{Name: "mockedBackend"}
+		require.NoError(t, NewBackendRepository(db).Create(ctx, backend))
+		actual := entity.Workspace{
+			Name:        "mockedWorkspace",
+			DisplayName: "mockedDisplayName",
+			Backend:     backend,
+			Owners:      []string{"hua.li", "xiaoming.li"},
+		}
+		err := repo.Create(ctx, &actual)
 		require.NoError(t, err)
-		require.Equal(t, expectedID, actual.ID)
+		require.Equal(t, uint(1), actual.ID)
 	})
 
 	t.Run("Delete existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewWorkspaceRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var expectedID uint = 1
-		sqlMock.ExpectBegin()
-		sqlMock.ExpectQuery("SELECT").
-			WillReturnRows(sqlmock.NewRows([]string{"id"}).
-				AddRow(1))
-		sqlMock.ExpectExec("DELETE").WillReturnResult(sqlmock.NewResult(int64(expectedID), int64(0)))
-		sqlMock.ExpectCommit()
-		err = repo.Delete(context.Background(), expectedID)
+		db := GetSQLiteDB(t)
+		repo := NewWorkspaceRepository(db)
+		defer CloseDB(t, db)
+
+		workspace := newTestWorkspace(t, db)
+		err := repo.Delete(ctx, workspace.ID)
 		require.NoError(t, err)
+		_, err = repo.Get(ctx, workspace.ID)
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
 	})
 
 	t.Run("Delete not existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewWorkspaceRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		sqlMock.ExpectBegin()
-		sqlMock.ExpectQuery("SELECT").
-			WillReturnRows(sqlmock.NewRows([]string{"id"}))
-		err = repo.Delete(context.Background(), 1)
+		db := GetSQLiteDB(t)
+		repo := NewWorkspaceRepository(db)
+		defer CloseDB(t, db)
+
+		err := repo.Delete(ctx, 1)
 		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
 	})
 
 	t.Run("Update existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
+		db := GetSQLiteDB(t)
+		repo := NewWorkspaceRepository(db)
+		defer CloseDB(t, db)
+
+		workspace := newTestWorkspace(t, db)
+		workspace.Description = "updatedDescription"
+		err := repo.Update(ctx, workspace)
 		require.NoError(t, err)
-		repo := NewWorkspaceRepository(fakeGDB)
-
-		var (
-			expectedID, expectedRows uint = 1, 1
-			actual                        = entity.Workspace{
-				ID: 1,
-			}
-		)
-		sqlMock.ExpectExec("UPDATE").
-			WillReturnResult(sqlmock.NewResult(int64(expectedID), int64(expectedRows)))
-		err = repo.Update(context.Background(), &actual)
+		updated, err := repo.Get(ctx, workspace.ID)
 		require.NoError(t, err)
+		require.Equal(t, "updatedDescription", updated.Description)
 	})
 
 	t.Run("Update not existing record", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewWorkspaceRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
+		db := GetSQLiteDB(t)
+		repo := NewWorkspaceRepository(db)
+		defer CloseDB(t, db)
 
 		actual := entity.Workspace{
 			Name: "NonExistentWorkspace",
 		}
-		err = repo.Update(context.Background(), &actual)
+		err := repo.Update(ctx, &actual)
 		require.ErrorIs(t, err, gorm.ErrMissingWhereClause)
 	})
 
 	t.Run("Get", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
+		db := GetSQLiteDB(t)
+		repo := NewWorkspaceRepository(db)
+		defer CloseDB(t, db)
+
+		workspace := newTestWorkspace(t, db)
+		actual, err := repo.Get(ctx, workspace.ID)
 		require.NoError(t, err)
-		repo := NewWorkspaceRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var (
-			expectedID   uint = 1
-			expectedName      = "mockedWorkspace"
-		)
-		sqlMock.ExpectQuery("SELECT .* FROM `workspace`").
-			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "Backend__id"}).
-				AddRow(expectedID, expectedName, 1))
-
-		actual, err := repo.Get(context.Background(), expectedID)
+		require.Equal(t, workspace.ID, actual.ID)
+		require.Equal(t, "dev", actual.Name)
+		require.Equal(t, "backend", actual.Backend.Name)
+
+		actual, err = repo.GetByName(ctx, "dev")
 		require.NoError(t, err)
-		require.Equal(t, expectedID, actual.ID)
-		require.Equal(t, expectedName, actual.Name)
+		require.Equal(t, workspace.ID, actual.ID)
 	})
 
 	t.Run("List", func(t *testing.T) {
-		fakeGDB, sqlMock, err := GetMockDB()
-		require.NoError(t, err)
-		repo := NewWorkspaceRepository(fakeGDB)
-		defer CloseDB(t, fakeGDB)
-		defer sqlMock.ExpectClose()
-
-		var (
-			expectedIDFirst    uint = 1
-			expectedNameFirst       = "mockedWorkspace"
-			expectedIDSecond   uint = 2
-			expectedNameSecond      = "mockedWorkspace2"
-		)
-
-		sqlMock.ExpectQuery("SELECT count(.*) FROM `workspace`").
-			WillReturnRows(
-				sqlmock.NewRows([]string{"count"}).
-					AddRow(2))
-
-		sqlMock.ExpectQuery("SELECT .* FROM `workspace`").
-			WillReturnRows(
-				sqlmock.NewRows([]string{"id", "name", "Backend__id"}).
-					AddRow(expectedIDFirst, expectedNameFirst, 1).
-					AddRow(expectedIDSecond, expectedNameSecond, 2))
-
-		actual, err := repo.List(context.Background(), &entity.WorkspaceFilter{
+		db := GetSQLiteDB(t)
+		repo := NewWorkspaceRepository(db)
+		defer CloseDB(t, db)
+
+		first := newTestWorkspace(t, db)
+		second := &entity.Workspace{Name: "mockedWorkspace2", Backend: first.Backend, Owners: []string{"hua.li"}}
+		require.NoError(t, repo.Create(ctx, second))
+
+		actual, err := repo.List(ctx, &entity.WorkspaceFilter{
 			Pagination: &entity.Pagination{
 				Page:     constant.CommonPageDefault,
 				PageSize: constant.CommonPageSizeDefault,
@@ -156,5 +114,6 @@ func TestWorkspaceRepository(t *testing.T) {
 		})
 		require.NoError(t, err)
 		require.Len(t, actual.Workspaces, 2)
+		require.Equal(t, 2, actual.Total)
 	})
 }
@@ -25,6 +25,7 @@ var (
 // ClientOptions are options for configuring the client behavior.
 type ClientOptions struct {
 	craneOptions []crane.Option
+	platform     *v1.Platform
 }
 
 // ClientOption is a function for configuring ClientOptions.
@@ -69,6 +70,7 @@ func WithInsecure(insecure bool) ClientOption {
 func WithPlatform(platform *v1.Platform) ClientOption {
 	return func(o *ClientOptions) {
 		o.craneOptions = append(o.craneOptions, crane.WithPlatform(platform))
+		o.platform = platform
 	}
 }
 
@@ -0,0 +1,29 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+
+	"kusionstack.io/kusion/pkg/oci"
+)
+
+// Digest returns the digest of the artifact, which is the digest of the image of the client platform if the
+// artifact is an index and the platform is set.
+func (c *Client) Digest(ctx context.Context, ociURL string) (string, error) {
+	ref, err := oci.ParseArtifactRef(ociURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid OCI repository url: %w", err)
+	}
+
+	if ref, err = c.resolvePlatform(ctx, ref); err != nil {
+		return "", err
+	}
+
+	digest, err := crane.Digest(ref.String(), c.optionsWithContext(ctx)...)
+	if err != nil {
+		return "", fmt.Errorf("resolving artifact digest failed: %s, %w", ref.String(), err)
+	}
+	return digest, nil
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+
+	"kusionstack.io/kusion/pkg/oci"
+)
+
+var ErrPlatformNotFound = errors.New("no matching platform")
+
+// ParsePlatforms parses the platforms in the format of os/arch, e.g. linux/amd64, where the duplicates are dropped.
+func ParsePlatforms(osArchs []string) ([]*v1.Platform, error) {
+	seen := make(map[string]bool, len(osArchs))
+	platforms := make([]*v1.Platform, 0, len(osArchs))
+	for _, osArch := range osArchs {
+		parts := strings.Split(strings.TrimSpace(osArch), "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid platform %s, should be in the format of os/arch", osArch)
+		}
+		if seen[parts[0]+"/"+parts[1]] {
+			continue
+		}
+		seen[parts[0]+"/"+parts[1]] = true
+		platforms = append(platforms, &v1.Platform{OS: parts[0], Architecture: parts[1]})
+	}
+	return platforms, nil
+}
+
+// IndexPlatforms returns the platforms of the manifests in the image index in the format of os/arch, sorted.
+func IndexPlatforms(index *v1.IndexManifest) []string {
+	platforms := make([]string, 0, len(index.Manifests))
+	for _, desc := range index.Manifests {
+		if desc.Platform == nil {
+			continue
+		}
+		platforms = append(platforms, desc.Platform.OS+"/"+desc.Platform.Architecture)
+	}
+	sort.Strings(platforms)
+	return platforms
+}
+
+// SelectPlatform returns the manifest of the platform in the image index, or an error wrapping ErrPlatformNotFound
+// listing the available platforms if none matches.
+func SelectPlatform(index *v1.IndexManifest, platform v1.Platform) (*v1.Descriptor, error) {
+	for i, desc := range index.Manifests {
+		if desc.Platform != nil && desc.Platform.Satisfies(platform) {
+			return &index.Manifests[i], nil
+		}
+	}
+	available := IndexPlatforms(index)
+	if len(available) == 0 {
+		return nil, fmt.Errorf("%w for %s/%s, no platforms available", ErrPlatformNotFound, platform.OS, platform.Architecture)
+	}
+	return nil, fmt.Errorf("%w for %s/%s, available platforms: %s",
+		ErrPlatformNotFound, platform.OS, platform.Architecture, strings.Join(available, ", "))
+}
+
+// Platforms returns the platforms of the artifact in the format of os/arch, which is empty if the artifact is
+// not an image index.
+func (c *Client) Platforms(ctx context.Context, ociURL string) ([]string, error) {
+	ref, err := oci.ParseArtifactRef(ociURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OCI repository url: %w", err)
+	}
+	desc, err := crane.Get(ref.String(), c.optionsWithContext(ctx)...)
+	if err != nil {
+		return nil, fmt.Errorf("get manifest failed: %s, %w", ref.String(), err)
+	}
+	if !desc.MediaType.IsIndex() {
+		return nil, nil
+	}
+	index, err := v1.ParseIndexManifest(bytes.NewReader(desc.Manifest))
+	if err != nil {
+		return nil, fmt.Errorf("parsing image index failed: %s, %w", ref.String(), err)
+	}
+	return IndexPlatforms(index), nil
+}
+
+// resolvePlatform returns the reference of the manifest of the client platform if the referenced artifact is an
+// image index, or the reference itself otherwise.
+func (c *Client) resolvePlatform(ctx context.Context, ref name.Reference) (name.Reference, error) {
+	if c.opts.platform == nil {
+		return ref, nil
+	}
+	desc, err := crane.Get(ref.String(), c.optionsWithContext(ctx)...)
+	if err != nil {
+		return nil, fmt.Errorf("get manifest failed: %s, %w", ref.String(), err)
+	}
+	if !desc.MediaType.IsIndex() {
+		return ref, nil
+	}
+	index, err := v1.ParseIndexManifest(bytes.NewReader(desc.Manifest))
+	if err != nil {
+		return nil, fmt.Errorf("parsing image index failed: %s, %w", ref.String(), err)
+	}
+	selected, err := SelectPlatform(index, *c.opts.platform)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ref.String(), err)
+	}
+	return ref.Context().Digest(selected.Digest.String()), nil
+}
@@ -0,0 +1,44 @@
+package client
+
+import (
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePlatforms(t *testing.T) {
+	platforms, err := ParsePlatforms([]string{"linux/amd64", "darwin/arm64", "linux/amd64"})
+	assert.NoError(t, err)
+	assert.Equal(t, []*v1.Platform{
+		{OS: "linux", Architecture: "amd64"},
+		{OS: "darwin", Architecture: "arm64"},
+	}, platforms)
+
+	for _, invalid := range []string{"linux", "linux/", "/amd64", "linux/amd64/v8"} {
+		_, err = ParsePlatforms([]string{invalid})
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestSelectPlatform(t *testing.T) {
+	index := &v1.IndexManifest{
+		Manifests: []v1.Descriptor{
+			{Digest: v1.Hash{Algorithm: "sha256", Hex: "aaa"}, Platform: &v1.Platform{OS: "linux", Architecture: "amd64"}},
+			{Digest: v1.Hash{Algorithm: "sha256", Hex: "bbb"}, Platform: &v1.Platform{OS: "darwin", Architecture: "arm64"}},
+			{Digest: v1.Hash{Algorithm: "sha256", Hex: "ccc"}},
+		},
+	}
+	assert.Equal(t, []string{"darwin/arm64", "linux/amd64"}, IndexPlatforms(index))
+
+	desc, err := SelectPlatform(index, v1.Platform{OS: "darwin", Architecture: "arm64"})
+	assert.NoError(t, err)
+	assert.Equal(t, "bbb", desc.Digest.Hex)
+
+	_, err = SelectPlatform(index, v1.Platform{OS: "windows", Architecture: "amd64"})
+	assert.ErrorIs(t, err, ErrPlatformNotFound)
+	assert.ErrorContains(t, err, "available platforms: darwin/arm64, linux/amd64")
+
+	_, err = SelectPlatform(&v1.IndexManifest{}, v1.Platform{OS: "linux", Architecture: "amd64"})
+	assert.ErrorContains(t, err, "no platforms available")
+}
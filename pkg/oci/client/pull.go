@@ -0,0 +1,122 @@
+package client
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+
+	"kusionstack.io/kusion/pkg/oci"
+)
+
+// Pull downloads the artifact of the given version from the OCI repository, and extracts the content layer
+// into the given directory, returns the digest URL of the artifact.
+func (c *Client) Pull(ctx context.Context, ociURL, version, outDir string) (string, error) {
+	url := ociURL
+	if version != "" {
+		url = fmt.Sprintf("%s:%s", ociURL, version)
+	}
+	ref, err := oci.ParseArtifactRef(url)
+	if err != nil {
+		return "", fmt.Errorf("invalid OCI repository url: %w", err)
+	}
+
+	// Select the manifest of the client platform explicitly, to report the available platforms if none matches.
+	if ref, err = c.resolvePlatform(ctx, ref); err != nil {
+		return "", err
+	}
+
+	img, err := crane.Pull(ref.String(), c.optionsWithContext(ctx)...)
+	if err != nil {
+		return "", fmt.Errorf("pulling artifact failed: %s, %w", ref.String(), err)
+	}
+	digest, err := img.Digest()
+	if err != nil {
+		return "", fmt.Errorf("parsing artifact digest failed: %w", err)
+	}
+	layers, err := img.Layers()
+	if err != nil {
+		return "", fmt.Errorf("listing artifact layers failed: %w", err)
+	}
+	if len(layers) < 1 {
+		return "", errors.New("no layers found in artifact")
+	}
+
+	blob, err := layers[0].Compressed()
+	if err != nil {
+		return "", fmt.Errorf("extracting first layer failed: %w", err)
+	}
+	defer blob.Close()
+	if err = untar(blob, outDir); err != nil {
+		return "", fmt.Errorf("failed to untar first layer: %w", err)
+	}
+
+	return ref.Context().Digest(digest.String()).String(), nil
+}
+
+// untar extracts the gzipped tarball into the directory, where the entries escaping the directory and the
+// links are rejected.
+func untar(r io.Reader, dir string) error {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(absDir, filepath.Clean(string(filepath.Separator)+header.Name))
+		if target != absDir && !strings.HasPrefix(target, absDir+string(filepath.Separator)) {
+			return fmt.Errorf("invalid file path in tarball: %s", header.Name)
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err = os.MkdirAll(target, os.ModePerm); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err = os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+				return err
+			}
+			if err = writeFile(target, tr, os.FileMode(header.Mode).Perm()); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported file type %c in tarball: %s", header.Typeflag, header.Name)
+		}
+	}
+}
+
+func writeFile(path string, r io.Reader, mode os.FileMode) error {
+	if mode == 0 {
+		mode = 0o644
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func gzipTarball(t *testing.T, files map[string]string) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	for name, content := range files {
+		err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		assert.NoError(t, err)
+		_, err = tw.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, gw.Close())
+	return buf
+}
+
+func TestUntar(t *testing.T) {
+	t.Run("extract files", func(t *testing.T) {
+		dir := t.TempDir()
+		err := untar(gzipTarball(t, map[string]string{"a.txt": "a", "sub/b.txt": "b"}), dir)
+		assert.NoError(t, err)
+
+		content, err := os.ReadFile(filepath.Join(dir, "sub", "b.txt"))
+		assert.NoError(t, err)
+		assert.Equal(t, "b", string(content))
+	})
+
+	t.Run("confine escaping files", func(t *testing.T) {
+		dir := t.TempDir()
+		err := untar(gzipTarball(t, map[string]string{"../escaped.txt": "escaped"}), filepath.Join(dir, "out"))
+		assert.NoError(t, err)
+		_, err = os.Stat(filepath.Join(dir, "escaped.txt"))
+		assert.True(t, os.IsNotExist(err))
+		_, err = os.Stat(filepath.Join(dir, "out", "escaped.txt"))
+		assert.NoError(t, err)
+	})
+}
@@ -26,6 +26,12 @@ import (
 // ArtifactTarballFileName defines name for the generated artifact tarball file
 const ArtifactTarballFileName = "artifact.tgz"
 
+// PlatformSource is the source directory of the artifact of a platform.
+type PlatformSource struct {
+	Platform  *v1.Platform
+	SourceDir string
+}
+
 // Push takes care of the actual artifact push behavior. It performs following operations:
 // - builds tarball from given directory also corresponding layer
 // - adds this layer to an empty OpenContainers artifact
@@ -39,10 +45,35 @@ func (c *Client) Push(
 	metadata meta.Metadata,
 	ignorePaths []string,
 ) (string, string, error) {
+	if metadata.Platform == nil {
+		return "", "", fmt.Errorf("platform is not set")
+	}
+	idxURL, imgURLs, err := c.PushPlatforms(ctx, ociURL, version, []PlatformSource{
+		{Platform: metadata.Platform, SourceDir: sourceDir},
+	}, metadata, ignorePaths)
+	if err != nil {
+		return "", "", err
+	}
+	return idxURL, imgURLs[0], nil
+}
+
+// PushPlatforms pushes the artifacts of the platforms in the same way as Push, and appends all of them to the
+// image index in a single write, where the existing artifacts of the same platforms are replaced. Returns the
+// digest URLs of the index and the artifacts in the order of the sources.
+func (c *Client) PushPlatforms(
+	ctx context.Context,
+	ociURL, version string,
+	sources []PlatformSource,
+	metadata meta.Metadata,
+	ignorePaths []string,
+) (string, []string, error) {
+	if len(sources) == 0 {
+		return "", nil, fmt.Errorf("no platform to push")
+	}
 	idxURL := fmt.Sprintf("%s:%s", ociURL, version)
 	ref, err := oci.ParseArtifactRef(idxURL)
 	if err != nil {
-		return "", "", fmt.Errorf("invalid OCI repository url: %w", err)
+		return "", nil, fmt.Errorf("invalid OCI repository url: %w", err)
 	}
 
 	// Check if the target reference exists and if it is an image index
@@ -58,57 +89,115 @@ func (c *Client) Push(
 			exists = false
 			base = empty.Index
 		} else {
-			return "", "", fmt.Errorf("get manifest failed: %s, %w", refStr, err)
+			return "", nil, fmt.Errorf("get manifest failed: %s, %w", refStr, err)
 		}
 	}
 
 	if exists {
 		if !manifest.MediaType.IsIndex() {
-			return "", "", fmt.Errorf("expected %s to be an index, got %q", refStr, manifest.MediaType)
+			return "", nil, fmt.Errorf("expected %s to be an index, got %q", refStr, manifest.MediaType)
 		}
 		base, err = manifest.ImageIndex()
 		if err != nil {
-			return "", "", fmt.Errorf("get manifest image index failed: %s, %w", refStr, err)
+			return "", nil, fmt.Errorf("get manifest image index failed: %s, %w", refStr, err)
 		}
 	}
 
+	// Add missing metadata
+	if metadata.Created == "" {
+		ct := time.Now().UTC()
+		metadata.Created = ct.Format(time.RFC3339)
+	}
+
+	idx := base
+	imgURLs := make([]string, 0, len(sources))
+	for _, source := range sources {
+		image, err := c.pushImage(ctx, ociURL, version, source, metadata, ignorePaths)
+		if err != nil {
+			return "", nil, err
+		}
+		imgDigest, err := image.Digest()
+		if err != nil {
+			return "", nil, fmt.Errorf("parsing image digest failed: %w", err)
+		}
+
+		cf, err := image.ConfigFile()
+		if err != nil {
+			return "", nil, fmt.Errorf("parsing image config file failed: %w", err)
+		}
+
+		newDesc, err := partial.Descriptor(image)
+		if err != nil {
+			return "", nil, fmt.Errorf("parsing image descriptor file failed: %w", err)
+		}
+		newDesc.Platform = cf.Platform()
+		addendum := mutate.IndexAddendum{
+			Add:        image,
+			Descriptor: *newDesc,
+		}
+
+		// replace current platform image with the new one
+		// remove is removed before adds
+		idx = mutate.AppendManifests(mutate.RemoveManifests(idx, match.Platforms(*newDesc.Platform)), addendum)
+		imgURLs = append(imgURLs, fmt.Sprintf("%s%s", oci.OCIRepositoryPrefix, ref.Context().Digest(imgDigest.String()).String()))
+	}
+
+	idxDigest, err := idx.Digest()
+	if err != nil {
+		return "", nil, fmt.Errorf("parsing index digest failed: %w", err)
+	}
+
+	o := crane.GetOptions(c.opts.craneOptions...)
+	if err = remote.WriteIndex(ref, idx, o.Remote...); err != nil {
+		return "", nil, fmt.Errorf("pushing image index %s: %w", refStr, err)
+	}
+
+	idxDigestURL := ref.Context().Digest(idxDigest.String()).String()
+	idxURL = fmt.Sprintf("%s%s", oci.OCIRepositoryPrefix, idxDigestURL)
+	return idxURL, imgURLs, nil
+}
+
+// pushImage builds the artifact of the platform from the source directory and pushes it to the platform-specific
+// repository, e.g. <ociURL>-linux_amd64:<version>.
+func (c *Client) pushImage(
+	ctx context.Context,
+	ociURL, version string,
+	source PlatformSource,
+	metadata meta.Metadata,
+	ignorePaths []string,
+) (v1.Image, error) {
+	platform := source.Platform
+	if platform == nil {
+		return nil, fmt.Errorf("platform is not set")
+	}
+
 	// build image
 	tmpDir, err := os.MkdirTemp("", "oci")
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
 	defer os.RemoveAll(tmpDir)
 
 	tmpFile := filepath.Join(tmpDir, ArtifactTarballFileName)
-	if err = c.Build(tmpFile, sourceDir, ignorePaths); err != nil {
-		return "", "", err
-	}
-
-	// Add missing metadata
-	if metadata.Created == "" {
-		ct := time.Now().UTC()
-		metadata.Created = ct.Format(time.RFC3339)
+	if err = c.Build(tmpFile, source.SourceDir, ignorePaths); err != nil {
+		return nil, err
 	}
 
 	image := mutate.MediaType(empty.Image, types.OCIManifestSchema1)
 	image = mutate.ConfigMediaType(image, CanonicalConfigMediaType)
 	image = mutate.Annotations(image, metadata.ToAnnotations()).(v1.Image)
 
-	platform := metadata.Platform
-	if platform == nil {
-		return "", "", fmt.Errorf("platform is not set")
-	}
 	image, err = mutate.ConfigFile(image, &v1.ConfigFile{
 		Architecture: platform.Architecture,
 		OS:           platform.OS,
 	})
 	if err != nil {
-		return "", "", fmt.Errorf("setting image config file failed: %w", err)
+		return nil, fmt.Errorf("setting image config file failed: %w", err)
 	}
 
 	layer, err := tarball.LayerFromFile(tmpFile, tarball.WithMediaType(CanonicalContentMediaType))
 	if err != nil {
-		return "", "", fmt.Errorf("creating content layer failed: %w", err)
+		return nil, fmt.Errorf("creating content layer failed: %w", err)
 	}
 
 	image, err = mutate.Append(image, mutate.Addendum{
@@ -118,53 +207,16 @@ func (c *Client) Push(
 		},
 	})
 	if err != nil {
-		return "", "", fmt.Errorf("appeding content to artifact failed: %w", err)
+		return nil, fmt.Errorf("appeding content to artifact failed: %w", err)
 	}
 
 	imgURL := fmt.Sprintf("%s-%s_%s:%s", ociURL, platform.OS, platform.Architecture, version)
 	imgRef, err := oci.ParseArtifactRef(imgURL)
 	if err != nil {
-		return "", "", fmt.Errorf("invalid image repository url: %w", err)
+		return nil, fmt.Errorf("invalid image repository url: %w", err)
 	}
 	if err = crane.Push(image, imgRef.String(), c.optionsWithContext(ctx)...); err != nil {
-		return "", "", fmt.Errorf("pushing artifact failed: %w", err)
+		return nil, fmt.Errorf("pushing artifact failed: %w", err)
 	}
-	imgDigest, err := image.Digest()
-	if err != nil {
-		return "", "", fmt.Errorf("parsing image digest failed: %w", err)
-	}
-
-	cf, err := image.ConfigFile()
-	if err != nil {
-		return "", "", fmt.Errorf("parsing image config file failed: %w", err)
-	}
-
-	newDesc, err := partial.Descriptor(image)
-	if err != nil {
-		return "", "", fmt.Errorf("parsing image descriptor file failed: %w", err)
-	}
-	newDesc.Platform = cf.Platform()
-	addendum := mutate.IndexAddendum{
-		Add:        image,
-		Descriptor: *newDesc,
-	}
-
-	// replace current platform image with the new one
-	// remove is removed before adds
-	idx := mutate.AppendManifests(mutate.RemoveManifests(base, match.Platforms(*newDesc.Platform)), addendum)
-	idxDigest, err := idx.Digest()
-	if err != nil {
-		return "", "", fmt.Errorf("parsing index digest failed: %w", err)
-	}
-
-	o := crane.GetOptions(c.opts.craneOptions...)
-	if err = remote.WriteIndex(ref, idx, o.Remote...); err != nil {
-		return "", "", fmt.Errorf("pushing image index %s: %w", refStr, err)
-	}
-
-	idxDigestURL := ref.Context().Digest(idxDigest.String()).String()
-	imgDigestURL := ref.Context().Digest(imgDigest.String()).String()
-	idxURL = fmt.Sprintf("%s%s", oci.OCIRepositoryPrefix, idxDigestURL)
-	imgURL = fmt.Sprintf("%s%s", oci.OCIRepositoryPrefix, imgDigestURL)
-	return idxURL, imgURL, nil
+	return image, nil
 }
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/google/go-containerregistry/pkg/crane"
+
+	"kusionstack.io/kusion/pkg/oci"
+)
+
+// ListTags returns the tags of the OCI repository, where the tag or digest in the URL is ignored.
+func (c *Client) ListTags(ctx context.Context, ociURL string) ([]string, error) {
+	ref, err := oci.ParseArtifactRef(ociURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OCI repository url: %w", err)
+	}
+
+	tags, err := crane.ListTags(ref.Context().String(), c.optionsWithContext(ctx)...)
+	if err != nil {
+		return nil, fmt.Errorf("listing tags failed: %s, %w", ref.Context().String(), err)
+	}
+	return tags, nil
+}
+
+// SortVersions returns the tags in semver format sorted in ascending order, where the other tags are dropped,
+// and so are the prereleases unless prerelease is true.
+func SortVersions(tags []string, prerelease bool) []string {
+	versions := make([]*semver.Version, 0, len(tags))
+	originals := make(map[*semver.Version]string, len(tags))
+	for _, tag := range tags {
+		v, err := semver.NewVersion(tag)
+		if err != nil || (v.Prerelease() != "" && !prerelease) {
+			continue
+		}
+		versions = append(versions, v)
+		originals[v] = tag
+	}
+	sort.Stable(semver.Collection(versions))
+
+	sorted := make([]string, 0, len(versions))
+	for _, v := range versions {
+		sorted = append(sorted, originals[v])
+	}
+	return sorted
+}
+
+// LatestVersion returns the latest version of the tags, see SortVersions for the tags considered. An empty
+// string is returned if there is no tag in semver format.
+func LatestVersion(tags []string, prerelease bool) string {
+	sorted := SortVersions(tags, prerelease)
+	if len(sorted) == 0 {
+		return ""
+	}
+	return sorted[len(sorted)-1]
+}
+
+// IsNewerVersion reports whether the version is newer than the current version. A version not in semver format
+// is never newer, while any version in semver format is newer than a current version not in semver format.
+func IsNewerVersion(version, current string) bool {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false
+	}
+	c, err := semver.NewVersion(current)
+	if err != nil {
+		return true
+	}
+	return v.GreaterThan(c)
+}
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortVersions(t *testing.T) {
+	tags := []string{"latest", "v0.2.0", "0.10.0", "0.9.1", "1.0.0-rc.1", "sha256-abc.sig"}
+
+	assert.Equal(t, []string{"v0.2.0", "0.9.1", "0.10.0"}, SortVersions(tags, false))
+	assert.Equal(t, []string{"v0.2.0", "0.9.1", "0.10.0", "1.0.0-rc.1"}, SortVersions(tags, true))
+	assert.Empty(t, SortVersions([]string{"latest"}, true))
+}
+
+func TestLatestVersion(t *testing.T) {
+	tags := []string{"0.1.0", "0.10.0", "0.9.1", "1.0.0-rc.1"}
+
+	assert.Equal(t, "0.10.0", LatestVersion(tags, false))
+	assert.Equal(t, "1.0.0-rc.1", LatestVersion(tags, true))
+	assert.Equal(t, "", LatestVersion([]string{"latest"}, false))
+}
+
+func TestIsNewerVersion(t *testing.T) {
+	testcases := []struct {
+		version  string
+		current  string
+		expected bool
+	}{
+		{version: "0.10.0", current: "0.9.1", expected: true},
+		{version: "v0.2.0", current: "0.2.0", expected: false},
+		{version: "0.1.0", current: "0.2.0", expected: false},
+		{version: "0.1.0", current: "latest", expected: true},
+		{version: "latest", current: "0.1.0", expected: false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.version+" than "+tc.current, func(t *testing.T) {
+			assert.Equal(t, tc.expected, IsNewerVersion(tc.version, tc.current))
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package trust
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is the duration the verification results are trusted without verifying again.
+const DefaultCacheTTL = 24 * time.Hour
+
+// CacheEntry is the result of a successful verification of a module.
+type CacheEntry struct {
+	// Digest is the digest of the verified artifact.
+	Digest string `json:"digest"`
+
+	// BinarySum is the sha256 checksum of the module binary, which is the same as the one in the artifact.
+	BinarySum string `json:"binarySum"`
+
+	// Signers is the fingerprint of the trusted signers verified with.
+	Signers string `json:"signers"`
+
+	// VerifiedAt is the time of the verification.
+	VerifiedAt time.Time `json:"verifiedAt"`
+}
+
+// Cache keeps the verification results in a file, keyed by the artifact reference with the tag, so that the
+// modules are not verified every time they are executed.
+type Cache struct {
+	path string
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*CacheEntry
+}
+
+// NewCache returns a Cache stored in the file, where an absent or corrupted file is treated as empty.
+func NewCache(path string, ttl time.Duration) *Cache {
+	c := &Cache{
+		path:    path,
+		ttl:     ttl,
+		entries: map[string]*CacheEntry{},
+	}
+	if content, err := os.ReadFile(path); err == nil {
+		if err = json.Unmarshal(content, &c.entries); err != nil || c.entries == nil {
+			c.entries = map[string]*CacheEntry{}
+		}
+	}
+	return c
+}
+
+// Lookup returns the unexpired verification result of the artifact reference, which matches the binary
+// checksum and the signers fingerprint, or nil if not found.
+func (c *Cache) Lookup(ref, binarySum, signers string) *CacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[ref]
+	if !ok || entry.BinarySum != binarySum || entry.Signers != signers {
+		return nil
+	}
+	if c.ttl > 0 && time.Since(entry.VerifiedAt) > c.ttl {
+		return nil
+	}
+	return entry
+}
+
+// Store records the verification result of the artifact reference and saves the cache file.
+func (c *Cache) Store(ref string, entry *CacheEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[ref] = entry
+	content, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(c.path), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, content, 0o600)
+}
@@ -0,0 +1,42 @@
+package trust
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+// MatchSigners returns the trusted signers of the repository, e.g. ghcr.io/kusionstack/mysql, and the registry
+// key of the policy they are configured in. The key matches the repository if it equals the repository or is a
+// prefix of it on a path boundary, and the longest matching key applies. Nil is returned if no key matches.
+func MatchSigners(policy *v1.ModuleTrustPolicy, repository string) (*v1.ModuleSigners, string) {
+	if policy == nil {
+		return nil, ""
+	}
+
+	var matched string
+	for key := range policy.Registries {
+		k := strings.TrimSuffix(key, "/")
+		if k == "" || (repository != k && !strings.HasPrefix(repository, k+"/")) {
+			continue
+		}
+		if len(key) > len(matched) {
+			matched = key
+		}
+	}
+	if matched == "" {
+		return nil, ""
+	}
+	return policy.Registries[matched], matched
+}
+
+// fingerprint returns the fingerprint of the signers, which invalidates the cached verification results
+// when the signers are changed.
+func fingerprint(signers *v1.ModuleSigners) string {
+	content, _ := json.Marshal(signers)
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,43 @@
+package trust
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func TestMatchSigners(t *testing.T) {
+	registry := &v1.ModuleSigners{PublicKeys: []string{"registry.pub"}}
+	org := &v1.ModuleSigners{PublicKeys: []string{"org.pub"}}
+	policy := &v1.ModuleTrustPolicy{
+		Registries: map[string]*v1.ModuleSigners{
+			"ghcr.io":              registry,
+			"ghcr.io/kusionstack/": org,
+		},
+	}
+
+	testcases := []struct {
+		repository string
+		signers    *v1.ModuleSigners
+		key        string
+	}{
+		{repository: "ghcr.io/kusionstack/mysql", signers: org, key: "ghcr.io/kusionstack/"},
+		{repository: "ghcr.io/kusionstack", signers: org, key: "ghcr.io/kusionstack/"},
+		{repository: "ghcr.io/kusionstack-fork/mysql", signers: registry, key: "ghcr.io"},
+		{repository: "ghcr.io.example.com/mysql"},
+		{repository: "docker.io/library/mysql"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.repository, func(t *testing.T) {
+			signers, key := MatchSigners(policy, tc.repository)
+			assert.Equal(t, tc.signers, signers)
+			assert.Equal(t, tc.key, key)
+		})
+	}
+
+	signers, _ := MatchSigners(nil, "ghcr.io/kusionstack/mysql")
+	assert.Nil(t, signers)
+}
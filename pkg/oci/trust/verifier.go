@@ -0,0 +1,224 @@
+package trust
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	ocispec "github.com/google/go-containerregistry/pkg/v1"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/oci"
+	"kusionstack.io/kusion/pkg/oci/client"
+	"kusionstack.io/kusion/pkg/util/kfile"
+)
+
+// cacheFile is the file of the verification results in the modules directory of the kusion home.
+const cacheFile = ".verified.json"
+
+var ErrUntrustedModule = errors.New("untrusted module")
+
+// Module is a module artifact whose binary is about to be executed.
+type Module struct {
+	// Name is the name of the module.
+	Name string
+
+	// Repository is the OCI repository of the module without the scheme, e.g. ghcr.io/kusionstack/mysql.
+	Repository string
+
+	// Tag is the tag of the module artifact.
+	Tag string
+
+	// Binary is the path of the module binary.
+	Binary string
+}
+
+// Verifier verifies the modules with the trust policy of the workspace. A module is trusted if the artifact it
+// is pulled from is signed by a trusted signer of its registry, and the module binary is the same as the one
+// in the artifact.
+type Verifier struct {
+	// Policy is the trust policy, the modules are not verified if it's nil.
+	Policy *v1.ModuleTrustPolicy
+
+	// Cache keeps the verification results, the modules are verified every time if it's nil.
+	Cache *Cache
+
+	// Resolve returns the digest of the artifact reference with a tag.
+	Resolve func(ctx context.Context, ref string) (string, error)
+
+	// Fetch extracts the artifact referenced by the digest into the directory.
+	Fetch func(ctx context.Context, ref, dir string) error
+
+	// VerifySignature verifies the signature of the artifact referenced by the digest with the signers.
+	VerifySignature func(ref string, signers *v1.ModuleSigners) error
+}
+
+// NewVerifier returns a Verifier of the trust policy, which talks to the OCI registries of the current platform
+// and caches the verification results in the kusion home.
+func NewVerifier(policy *v1.ModuleTrustPolicy) (*Verifier, error) {
+	kusionHome, err := kfile.KusionDataFolder()
+	if err != nil {
+		return nil, err
+	}
+	ociClient := client.NewClient(
+		client.WithUserAgent(oci.UserAgent),
+		client.WithPlatform(&ocispec.Platform{OS: runtime.GOOS, Architecture: runtime.GOARCH}),
+	)
+
+	return &Verifier{
+		Policy: policy,
+		Cache:  NewCache(filepath.Join(kusionHome, "modules", cacheFile), DefaultCacheTTL),
+		Resolve: func(ctx context.Context, ref string) (string, error) {
+			return ociClient.Digest(ctx, oci.OCIRepositoryPrefix+ref)
+		},
+		Fetch: func(ctx context.Context, ref, dir string) error {
+			_, err := ociClient.Pull(ctx, oci.OCIRepositoryPrefix+ref, "", dir)
+			return err
+		},
+		VerifySignature: VerifyCosign,
+	}, nil
+}
+
+// VerifyCosign verifies the signature of the artifact by cosign, which succeeds if any of the signers verifies.
+func VerifyCosign(ref string, signers *v1.ModuleSigners) error {
+	var errs []error
+	for _, key := range signers.PublicKeys {
+		err := oci.VerifyCosign(ref, key, "", "")
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("public key %s: %w", key, err))
+	}
+	for _, identity := range signers.KeylessIdentities {
+		err := oci.VerifyCosign(ref, "", identity.Issuer, identity.Subject)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("identity %s of %s: %w", identity.Subject, identity.Issuer, err))
+	}
+	if len(errs) == 0 {
+		return errors.New("no trusted signers")
+	}
+	return errors.Join(errs...)
+}
+
+// Verify returns an error wrapping ErrUntrustedModule if the module is not trusted by the policy.
+func (v *Verifier) Verify(ctx context.Context, m *Module) error {
+	if v.Policy == nil {
+		return nil
+	}
+
+	signers, registry := MatchSigners(v.Policy, m.Repository)
+	if signers == nil {
+		if v.Policy.AllowUnlisted {
+			return nil
+		}
+		return fmt.Errorf("%w %s: no trusted signers configured for %s in the workspace", ErrUntrustedModule, m.Name, m.Repository)
+	}
+
+	binarySum, err := fileSum(m.Binary)
+	if err != nil {
+		return fmt.Errorf("checksum of module %s binary failed: %w", m.Name, err)
+	}
+	ref := m.Repository + ":" + m.Tag
+	signersFingerprint := fingerprint(signers)
+	if v.Cache != nil && v.Cache.Lookup(ref, binarySum, signersFingerprint) != nil {
+		return nil
+	}
+
+	digest, err := v.Resolve(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("resolve digest of module %s failed: %w", m.Name, err)
+	}
+	digestRef := m.Repository + "@" + digest
+	if err = v.VerifySignature(digestRef, signers); err != nil {
+		return fmt.Errorf("%w %s: signature of %s is not verified by the trusted signers of %s: %v",
+			ErrUntrustedModule, m.Name, digestRef, registry, err)
+	}
+	if err = v.verifyBinary(ctx, m, digestRef, binarySum); err != nil {
+		return err
+	}
+
+	if v.Cache != nil {
+		entry := &CacheEntry{
+			Digest:     digest,
+			BinarySum:  binarySum,
+			Signers:    signersFingerprint,
+			VerifiedAt: time.Now(),
+		}
+		if err = v.Cache.Store(ref, entry); err != nil {
+			return fmt.Errorf("cache verification result of module %s failed: %w", m.Name, err)
+		}
+	}
+	return nil
+}
+
+// verifyBinary checks the module binary is the same as the one in the verified artifact.
+func (v *Verifier) verifyBinary(ctx context.Context, m *Module, digestRef, binarySum string) error {
+	dir, err := os.MkdirTemp("", "kusion-module-verify")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	if err = v.Fetch(ctx, digestRef, dir); err != nil {
+		return fmt.Errorf("fetch module %s failed: %w", m.Name, err)
+	}
+	artifactBinary, err := findFile(dir, filepath.Base(m.Binary))
+	if err != nil {
+		return fmt.Errorf("%w %s: %v", ErrUntrustedModule, m.Name, err)
+	}
+	artifactSum, err := fileSum(artifactBinary)
+	if err != nil {
+		return err
+	}
+	if artifactSum != binarySum {
+		return fmt.Errorf("%w %s: binary %s does not match the one in %s", ErrUntrustedModule, m.Name, m.Binary, digestRef)
+	}
+	return nil
+}
+
+// findFile returns the path of the regular file with the name in the directory.
+func findFile(dir, name string) (string, error) {
+	var found string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() && d.Name() == name {
+			found = path
+			return fs.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if found == "" {
+		return "", fmt.Errorf("binary %s not found in the artifact", name)
+	}
+	return found, nil
+}
+
+// fileSum returns the hex encoded sha256 checksum of the file.
+func fileSum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,147 @@
+package trust
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+const (
+	testDigest      = "sha256:0123456789abcdef"
+	testBinaryName  = "kusion-module-mysql_0.1.0"
+	testBinaryValue = "module binary"
+)
+
+type fakeRegistry struct {
+	binary     string
+	signed     bool
+	verified   int
+	fetchedRef string
+}
+
+func (r *fakeRegistry) verifier(t *testing.T, policy *v1.ModuleTrustPolicy) *Verifier {
+	return &Verifier{
+		Policy: policy,
+		Cache:  NewCache(filepath.Join(t.TempDir(), cacheFile), time.Hour),
+		Resolve: func(_ context.Context, ref string) (string, error) {
+			return testDigest, nil
+		},
+		Fetch: func(_ context.Context, ref, dir string) error {
+			r.fetchedRef = ref
+			path := filepath.Join(dir, "_dist", "linux", "amd64", testBinaryName)
+			if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+				return err
+			}
+			return os.WriteFile(path, []byte(r.binary), 0o600)
+		},
+		VerifySignature: func(ref string, signers *v1.ModuleSigners) error {
+			r.verified++
+			if !r.signed {
+				return errors.New("no matching signatures")
+			}
+			return nil
+		},
+	}
+}
+
+func testModule(t *testing.T) *Module {
+	binary := filepath.Join(t.TempDir(), testBinaryName)
+	assert.NoError(t, os.WriteFile(binary, []byte(testBinaryValue), 0o600))
+	return &Module{
+		Name:       "mysql",
+		Repository: "ghcr.io/kusionstack/mysql",
+		Tag:        "0.1.0",
+		Binary:     binary,
+	}
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	policy := &v1.ModuleTrustPolicy{
+		Registries: map[string]*v1.ModuleSigners{
+			"ghcr.io/kusionstack": {PublicKeys: []string{"cosign.pub"}},
+		},
+	}
+
+	t.Run("trusted module is verified once", func(t *testing.T) {
+		r := &fakeRegistry{binary: testBinaryValue, signed: true}
+		v := r.verifier(t, policy)
+		m := testModule(t)
+
+		assert.NoError(t, v.Verify(context.Background(), m))
+		assert.Equal(t, "ghcr.io/kusionstack/mysql@"+testDigest, r.fetchedRef)
+		assert.NoError(t, v.Verify(context.Background(), m))
+		assert.Equal(t, 1, r.verified)
+
+		// The changed binary is verified again.
+		assert.NoError(t, os.WriteFile(m.Binary, []byte("tampered"), 0o600))
+		err := v.Verify(context.Background(), m)
+		assert.ErrorIs(t, err, ErrUntrustedModule)
+		assert.Equal(t, 2, r.verified)
+	})
+
+	t.Run("unsigned module", func(t *testing.T) {
+		r := &fakeRegistry{binary: testBinaryValue}
+		err := r.verifier(t, policy).Verify(context.Background(), testModule(t))
+		assert.ErrorIs(t, err, ErrUntrustedModule)
+		assert.ErrorContains(t, err, "no matching signatures")
+	})
+
+	t.Run("binary mismatches the artifact", func(t *testing.T) {
+		r := &fakeRegistry{binary: "another binary", signed: true}
+		err := r.verifier(t, policy).Verify(context.Background(), testModule(t))
+		assert.ErrorIs(t, err, ErrUntrustedModule)
+		assert.ErrorContains(t, err, "does not match")
+	})
+
+	t.Run("unlisted registry", func(t *testing.T) {
+		r := &fakeRegistry{binary: testBinaryValue, signed: true}
+		m := testModule(t)
+		m.Repository = "docker.io/kusionstack/mysql"
+		err := r.verifier(t, policy).Verify(context.Background(), m)
+		assert.ErrorIs(t, err, ErrUntrustedModule)
+
+		allowed := &v1.ModuleTrustPolicy{Registries: policy.Registries, AllowUnlisted: true}
+		assert.NoError(t, r.verifier(t, allowed).Verify(context.Background(), m))
+		assert.Equal(t, 0, r.verified)
+	})
+
+	t.Run("no policy", func(t *testing.T) {
+		r := &fakeRegistry{}
+		assert.NoError(t, r.verifier(t, nil).Verify(context.Background(), testModule(t)))
+		assert.Equal(t, 0, r.verified)
+	})
+}
+
+func TestCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "modules", cacheFile)
+	c := NewCache(path, time.Hour)
+	assert.Nil(t, c.Lookup("ghcr.io/kusionstack/mysql:0.1.0", "sum", "signers"))
+
+	assert.NoError(t, c.Store("ghcr.io/kusionstack/mysql:0.1.0", &CacheEntry{
+		Digest:     testDigest,
+		BinarySum:  "sum",
+		Signers:    "signers",
+		VerifiedAt: time.Now(),
+	}))
+	assert.NoError(t, c.Store("ghcr.io/kusionstack/redis:0.1.0", &CacheEntry{
+		BinarySum:  "sum",
+		Signers:    "signers",
+		VerifiedAt: time.Now().Add(-2 * time.Hour),
+	}))
+
+	loaded := NewCache(path, time.Hour)
+	assert.NotNil(t, loaded.Lookup("ghcr.io/kusionstack/mysql:0.1.0", "sum", "signers"))
+	assert.Nil(t, loaded.Lookup("ghcr.io/kusionstack/mysql:0.1.0", "another", "signers"))
+	assert.Nil(t, loaded.Lookup("ghcr.io/kusionstack/mysql:0.1.0", "sum", "another"))
+	assert.Nil(t, loaded.Lookup("ghcr.io/kusionstack/redis:0.1.0", "sum", "signers"))
+
+	assert.NoError(t, os.WriteFile(path, []byte("corrupted"), 0o600))
+	assert.Nil(t, NewCache(path, time.Hour).Lookup("ghcr.io/kusionstack/mysql:0.1.0", "sum", "signers"))
+}
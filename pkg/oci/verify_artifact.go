@@ -0,0 +1,37 @@
+package oci
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// VerifyCosign verifies the signature of an image (`imageRef`) using a cosign public key (`keyRef`), or the
+// certificate identity (`subject`) issued by the OIDC issuer (`issuer`) in keyless mode if the key is empty.
+func VerifyCosign(imageRef, keyRef, issuer, subject string) error {
+	cosignExecutable, err := exec.LookPath("cosign")
+	if err != nil {
+		return fmt.Errorf("executing cosign failed: %w", err)
+	}
+
+	cosignCmd := exec.Command(cosignExecutable, []string{"verify"}...)
+	cosignCmd.Env = os.Environ()
+
+	if keyRef != "" {
+		cosignCmd.Args = append(cosignCmd.Args, "--key", keyRef)
+	} else {
+		if issuer == "" || subject == "" {
+			return fmt.Errorf("both issuer and subject are required in keyless mode")
+		}
+		cosignCmd.Args = append(cosignCmd.Args, "--certificate-oidc-issuer", issuer, "--certificate-identity", subject)
+	}
+
+	cosignCmd.Args = append(cosignCmd.Args, imageRef)
+
+	err = processCosignIO(cosignCmd)
+	if err != nil {
+		return err
+	}
+
+	return cosignCmd.Wait()
+}
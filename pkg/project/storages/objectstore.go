@@ -0,0 +1,56 @@
+package storages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"kusionstack.io/kusion/pkg/infra/objectstore"
+)
+
+// ObjectStoreStorage is an implementation of project.Storage which uses a generic object store as storage,
+// such as the Azure Blob Storage, a HTTP server or a Kubernetes cluster.
+type ObjectStoreStorage struct {
+	store objectstore.Store
+
+	// The prefix to store the project folders' directory.
+	prefix string
+}
+
+// NewObjectStoreStorage creates a new ObjectStoreStorage instance.
+func NewObjectStoreStorage(store objectstore.Store, prefix string) *ObjectStoreStorage {
+	s := &ObjectStoreStorage{
+		store:  store,
+		prefix: prefix,
+	}
+
+	return s
+}
+
+// Get returns a project map which key is workspace name and value is its belonged project list.
+func (s *ObjectStoreStorage) Get() (map[string][]string, error) {
+	// the object store has no directories, so derive the projects and workspaces from the keys
+	// of the release files, which are in the form of {prefix}/{project}/{workspace}/{file}.
+	keys, err := s.store.List(context.Background(), s.prefix+"/")
+	if err != nil {
+		return nil, fmt.Errorf("list projects from object store failed: %w", err)
+	}
+
+	projects := map[string][]string{}
+	seen := map[string]bool{}
+	for _, key := range keys {
+		parts := strings.Split(strings.TrimPrefix(key, s.prefix+"/"), "/")
+		if len(parts) < 3 {
+			continue
+		}
+		project, workspace := parts[0], parts[1]
+		if seen[project+"/"+workspace] {
+			continue
+		}
+		seen[project+"/"+workspace] = true
+
+		// Store workspace name as key, project name as value
+		projects[workspace] = append(projects[workspace], project)
+	}
+	return projects, nil
+}
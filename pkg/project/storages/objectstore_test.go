@@ -0,0 +1,30 @@
+package storages
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"kusionstack.io/kusion/pkg/infra/objectstore"
+)
+
+func TestObjectStoreStorage_Get(t *testing.T) {
+	store := objectstore.NewMemoryStore()
+	for _, key := range []string{
+		"releases/project1/workspace1/.metadata.yml",
+		"releases/project1/workspace1/1.yaml",
+		"releases/project1/workspace2/.metadata.yml",
+		"releases/project2/workspace1/.metadata.yml",
+		"releases/invalid",
+	} {
+		assert.NoError(t, store.Put(context.Background(), key, []byte("content")))
+	}
+
+	got, err := NewObjectStoreStorage(store, "releases").Get()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"workspace1": {"project1", "project2"},
+		"workspace2": {"project1"},
+	}, got)
+}
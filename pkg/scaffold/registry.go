@@ -0,0 +1,188 @@
+package scaffold
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/gitutil"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/oci"
+	"kusionstack.io/kusion/pkg/oci/client"
+)
+
+// gitSourcePrefix forces the source to be regarded as a git repository, e.g. git::ssh://git@host/org/repo.
+const gitSourcePrefix = "git::"
+
+// RegistryTemplate is a template of a template registry.
+type RegistryTemplate struct {
+	*Template
+
+	// Registry is the name of the template registry, empty for the built-in template.
+	Registry string
+
+	// RegistryVersion is the version of the template registry.
+	RegistryVersion string
+}
+
+// ListRegistryTemplates returns the built-in template and the templates of the registries, sorted by the registry
+// name and the template name. The directories of the returned templates are removed after listing.
+func ListRegistryTemplates(registries map[string]*v1.TemplateRegistryConfig) ([]*RegistryTemplate, error) {
+	names := make([]string, 0, len(registries))
+	for name := range registries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	templates := []*RegistryTemplate{{
+		Template: &Template{Name: QuickstartTemplate, Description: "The demo project to quickly get started"},
+	}}
+	for _, name := range names {
+		registry := registries[name]
+		if registry == nil {
+			continue
+		}
+		if err := withSource(registry.Source, registry.Version, func(root string) error {
+			ts, err := ListTemplates(root)
+			if err != nil {
+				return err
+			}
+			for _, t := range ts {
+				templates = append(templates, &RegistryTemplate{Template: t, Registry: name, RegistryVersion: registry.Version})
+			}
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("list templates of registry %s failed: %w", name, err)
+		}
+	}
+	return templates, nil
+}
+
+// WithTemplate fetches the template referred by ref, and calls fn with the fetched template. The ref is either
+// <registry>/<template> referring to a template of the configured registries, or the source of a single template,
+// which is a git repository, an OCI artifact or a local directory. The version overrides the version of the
+// registry if not empty.
+func WithTemplate(ref, version string, registries map[string]*v1.TemplateRegistryConfig, fn func(t *Template) error) error {
+	source, name := ref, ""
+	if registryName, templateName, found := strings.Cut(ref, "/"); found && registries[registryName] != nil {
+		registry := registries[registryName]
+		source, name = registry.Source, templateName
+		if version == "" {
+			version = registry.Version
+		}
+	}
+
+	return withSource(source, version, func(root string) error {
+		templates, err := ListTemplates(root)
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			if len(templates) != 1 {
+				return fmt.Errorf("expect exactly one template in %s, got %d", ref, len(templates))
+			}
+			return fn(templates[0])
+		}
+		for _, t := range templates {
+			if t.Name == name {
+				return fn(t)
+			}
+		}
+		return fmt.Errorf("template %s not found", ref)
+	})
+}
+
+// withSource fetches the source of the specified version, and calls fn with the local directory of the source,
+// which is removed after calling fn if the source is remote.
+func withSource(source, version string, fn func(root string) error) error {
+	if !isOCISource(source) && !isGitSource(source) {
+		root, err := filepath.Abs(source)
+		if err != nil {
+			return err
+		}
+		if _, err = os.Stat(root); err != nil {
+			return fmt.Errorf("invalid template source %s: %w", source, err)
+		}
+		return fn(root)
+	}
+
+	dir, err := os.MkdirTemp("", "kusion-template")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	root, err := fetchSource(source, version, dir)
+	if err != nil {
+		return fmt.Errorf("fetch template source %s failed: %w", source, err)
+	}
+	return fn(root)
+}
+
+// fetchSource downloads the remote source of the specified version into the directory, and returns the local
+// directory of the source.
+func fetchSource(source, version, dir string) (string, error) {
+	if isOCISource(source) {
+		c := client.NewClient(client.WithUserAgent(oci.UserAgent))
+		if _, err := c.Pull(context.Background(), source, version, dir); err != nil {
+			return "", err
+		}
+		return dir, nil
+	}
+
+	url, subDir := splitSubDir(strings.TrimPrefix(source, gitSourcePrefix))
+	if err := cloneGit(url, version, dir); err != nil {
+		return "", err
+	}
+	root := filepath.Join(dir, filepath.FromSlash(subDir))
+	if rel, err := filepath.Rel(dir, root); err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("invalid subdirectory %s", subDir)
+	}
+	return root, nil
+}
+
+// cloneGit clones the git repository of the version, which is a tag or a branch, into the directory. The
+// default branch is cloned if the version is empty.
+func cloneGit(url, version, dir string) error {
+	if version == "" {
+		return gitutil.GitCloneOrPull(url, plumbing.HEAD, dir, true)
+	}
+	if err := gitutil.GitCloneOrPull(url, plumbing.NewTagReferenceName(version), dir, true); err == nil {
+		return nil
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	return gitutil.GitCloneOrPull(url, plumbing.NewBranchReferenceName(version), dir, true)
+}
+
+func isOCISource(source string) bool {
+	return strings.HasPrefix(source, oci.OCIRepositoryPrefix)
+}
+
+func isGitSource(source string) bool {
+	return strings.HasPrefix(source, gitSourcePrefix) ||
+		strings.HasPrefix(source, "https://") ||
+		strings.HasPrefix(source, "http://") ||
+		strings.HasPrefix(source, "ssh://") ||
+		strings.HasPrefix(source, "git@")
+}
+
+// splitSubDir splits the source into the repository url and the subdirectory, which is separated by "//", e.g.
+// https://github.com/org/templates.git//web-service.
+func splitSubDir(source string) (string, string) {
+	start := 0
+	if i := strings.Index(source, "://"); i >= 0 {
+		start = i + len("://")
+	}
+	i := strings.Index(source[start:], "//")
+	if i < 0 {
+		return source, ""
+	}
+	return source[:start+i], source[start+i+len("//"):]
+}
@@ -0,0 +1,113 @@
+package scaffold
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func TestListRegistryTemplates(t *testing.T) {
+	templates, err := ListRegistryTemplates(map[string]*v1.TemplateRegistryConfig{
+		"local": {Source: testTemplatesDir},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, templates, 3)
+	assert.Equal(t, QuickstartTemplate, templates[0].Name)
+	assert.Equal(t, "", templates[0].Registry)
+	assert.Equal(t, "job", templates[1].Name)
+	assert.Equal(t, "local", templates[1].Registry)
+
+	_, err = ListRegistryTemplates(map[string]*v1.TemplateRegistryConfig{
+		"local": {Source: "testdata/not-exist"},
+	})
+	assert.Error(t, err)
+}
+
+func TestWithTemplate(t *testing.T) {
+	registries := map[string]*v1.TemplateRegistryConfig{
+		"local": {Source: testTemplatesDir},
+	}
+
+	testcases := []struct {
+		name     string
+		success  bool
+		ref      string
+		expected string
+	}{
+		{
+			name:     "template of registry",
+			success:  true,
+			ref:      "local/web-service",
+			expected: "web-service",
+		},
+		{
+			name:     "template of source",
+			success:  true,
+			ref:      testTemplatesDir + "/job",
+			expected: "job",
+		},
+		{
+			name:    "template not found in registry",
+			success: false,
+			ref:     "local/not-exist",
+		},
+		{
+			name:    "multiple templates in source",
+			success: false,
+			ref:     testTemplatesDir,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var name string
+			err := WithTemplate(tc.ref, "", registries, func(tmpl *Template) error {
+				name = tmpl.Name
+				return nil
+			})
+			assert.Equal(t, tc.success, err == nil)
+			assert.Equal(t, tc.expected, name)
+		})
+	}
+}
+
+func TestSplitSubDir(t *testing.T) {
+	testcases := []struct {
+		source string
+		url    string
+		subDir string
+	}{
+		{
+			source: "https://github.com/org/templates.git",
+			url:    "https://github.com/org/templates.git",
+		},
+		{
+			source: "https://github.com/org/templates.git//golden/web",
+			url:    "https://github.com/org/templates.git",
+			subDir: "golden/web",
+		},
+		{
+			source: "git@github.com:org/templates.git//web",
+			url:    "git@github.com:org/templates.git",
+			subDir: "web",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.source, func(t *testing.T) {
+			url, subDir := splitSubDir(tc.source)
+			assert.Equal(t, tc.url, url)
+			assert.Equal(t, tc.subDir, subDir)
+		})
+	}
+}
+
+func TestSourceKind(t *testing.T) {
+	assert.True(t, isOCISource("oci://ghcr.io/org/templates"))
+	assert.True(t, isGitSource("https://github.com/org/templates.git"))
+	assert.True(t, isGitSource("git::ssh://git@example.com/org/templates.git"))
+	assert.False(t, isGitSource("./templates"))
+	assert.False(t, isOCISource("./templates"))
+}
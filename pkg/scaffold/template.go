@@ -0,0 +1,317 @@
+package scaffold
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/backend"
+	"kusionstack.io/kusion/pkg/util/terminal"
+)
+
+const (
+	// TemplateFile is the file declaring the metadata and parameters of a project template.
+	TemplateFile = "template.yaml"
+
+	// QuickstartTemplate is the name of the built-in template of the demo project.
+	QuickstartTemplate = "quickstart"
+
+	// projectNameKey is the key of the project name in the data to render the templates.
+	projectNameKey = "ProjectName"
+
+	// binarySniffLen is the length of the content sniffed to detect a binary file, as git does.
+	binarySniffLen = 8000
+)
+
+// vcsDirs are the directories of the version control systems, which are not part of the template.
+var vcsDirs = map[string]bool{".git": true, ".hg": true, ".svn": true, ".bzr": true}
+
+var (
+	ErrEmptyTemplateName    = errors.New("template name should not be empty")
+	ErrInvalidParameterName = errors.New("parameter name should be an identifier and not ProjectName")
+
+	parameterNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// Template is a project template, which is a directory containing the template file and the files of the project,
+// such as project.yaml, and stack.yaml, kcl.mod and main.k of each stack. The files are rendered with the
+// project name and the template parameters by text/template, e.g. {{ .ProjectName }} and {{ .image }}.
+type Template struct {
+	// Name is the name of the template.
+	Name string `yaml:"name" json:"name"`
+
+	// Description is the description of the template.
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+
+	// Version is the version of the template.
+	Version string `yaml:"version,omitempty" json:"version,omitempty"`
+
+	// Parameters are the parameters to render the template.
+	Parameters []*TemplateParameter `yaml:"parameters,omitempty" json:"parameters,omitempty"`
+
+	// Dir is the local directory of the template.
+	Dir string `yaml:"-" json:"-"`
+}
+
+// TemplateParameter is a parameter to render the template.
+type TemplateParameter struct {
+	// Name is the name of the parameter, which is referred in the template files as {{ .<Name> }}.
+	Name string `yaml:"name" json:"name"`
+
+	// Description is the description of the parameter, which is shown when prompting for the value.
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+
+	// Default is the default value of the parameter.
+	Default string `yaml:"default,omitempty" json:"default,omitempty"`
+
+	// Required indicates the parameter must have a non-empty value.
+	Required bool `yaml:"required,omitempty" json:"required,omitempty"`
+}
+
+// PromptFunc prompts for the value of the template parameter.
+type PromptFunc func(p *TemplateParameter) (string, error)
+
+// GenTemplateProject creates the project with a specified name in the specified directory from the template
+// referred by ref, see WithTemplate for the format of ref. The values of the template parameters not specified
+// are prompted by prompt if it is not nil.
+func GenTemplateProject(
+	ref, version string,
+	registries map[string]*v1.TemplateRegistryConfig,
+	dir, name string,
+	values map[string]string,
+	prompt PromptFunc,
+) error {
+	// Init default workspace for the initialized project if not exists.
+	if _, err := backend.NewWorkspaceStorage(""); err != nil {
+		return err
+	}
+
+	return WithTemplate(ref, version, registries, func(t *Template) error {
+		resolved, err := ResolveValues(t, values, prompt)
+		if err != nil {
+			return err
+		}
+		return RenderTemplate(t, dir, name, resolved)
+	})
+}
+
+// ParseValues parses the values of the template parameters in the format of key=value.
+func ParseValues(values []string) (map[string]string, error) {
+	parsed := make(map[string]string, len(values))
+	for _, value := range values {
+		k, v, found := strings.Cut(value, "=")
+		if !found || k == "" {
+			return nil, fmt.Errorf("invalid template parameter value %s, should be in the format of key=value", value)
+		}
+		parsed[k] = v
+	}
+	return parsed, nil
+}
+
+// TextInputPrompt returns a PromptFunc prompting for the value by the interactive text input of the UI, where
+// an empty input means the default value.
+func TextInputPrompt(ui *terminal.UI) PromptFunc {
+	return func(p *TemplateParameter) (string, error) {
+		text := p.Name
+		if p.Description != "" {
+			text = fmt.Sprintf("%s (%s)", p.Description, p.Name)
+		}
+		if p.Default != "" {
+			text = fmt.Sprintf("%s [%s]", text, p.Default)
+		}
+		value, err := ui.InteractiveTextInputPrinter.WithDefaultText(text).Show()
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(value), nil
+	}
+}
+
+// LoadTemplate loads the template in the directory.
+func LoadTemplate(dir string) (*Template, error) {
+	content, err := os.ReadFile(filepath.Join(dir, TemplateFile))
+	if err != nil {
+		return nil, fmt.Errorf("read template file of %s failed: %w", dir, err)
+	}
+	t := &Template{}
+	if err = yaml.Unmarshal(content, t); err != nil {
+		return nil, fmt.Errorf("unmarshal template file of %s failed: %w", dir, err)
+	}
+	if err = ValidateTemplate(t); err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", dir, err)
+	}
+	t.Dir = dir
+	return t, nil
+}
+
+// ValidateTemplate checks the template is valid or not.
+func ValidateTemplate(t *Template) error {
+	if t.Name == "" {
+		return ErrEmptyTemplateName
+	}
+	names := make(map[string]bool, len(t.Parameters))
+	for _, p := range t.Parameters {
+		if p == nil || !parameterNameRegexp.MatchString(p.Name) || p.Name == projectNameKey {
+			return ErrInvalidParameterName
+		}
+		if names[p.Name] {
+			return fmt.Errorf("duplicate parameter %s", p.Name)
+		}
+		names[p.Name] = true
+	}
+	return nil
+}
+
+// ListTemplates returns the templates in the directory, which is either a template itself, or contains the
+// templates as its subdirectories, sorted by the template name.
+func ListTemplates(dir string) ([]*Template, error) {
+	if _, err := os.Stat(filepath.Join(dir, TemplateFile)); err == nil {
+		t, err := LoadTemplate(dir)
+		if err != nil {
+			return nil, err
+		}
+		return []*Template{t}, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var templates []*Template
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		subDir := filepath.Join(dir, entry.Name())
+		if _, err = os.Stat(filepath.Join(subDir, TemplateFile)); err != nil {
+			continue
+		}
+		t, err := LoadTemplate(subDir)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
+// ResolveValues returns the values of the template parameters, which are the specified values, or the prompted
+// values if prompt is not nil, or the default values. An error is returned if a required parameter has no value,
+// or the specified value is not a parameter of the template.
+func ResolveValues(t *Template, values map[string]string, prompt PromptFunc) (map[string]string, error) {
+	params := make(map[string]bool, len(t.Parameters))
+	for _, p := range t.Parameters {
+		params[p.Name] = true
+	}
+	for name := range values {
+		if !params[name] {
+			return nil, fmt.Errorf("unknown parameter %s of template %s", name, t.Name)
+		}
+	}
+
+	resolved := make(map[string]string, len(t.Parameters))
+	for _, p := range t.Parameters {
+		value, ok := values[p.Name]
+		if !ok && prompt != nil {
+			var err error
+			if value, err = prompt(p); err != nil {
+				return nil, err
+			}
+		}
+		if value == "" {
+			value = p.Default
+		}
+		if value == "" && p.Required {
+			return nil, fmt.Errorf("parameter %s of template %s is required", p.Name, t.Name)
+		}
+		resolved[p.Name] = value
+	}
+	return resolved, nil
+}
+
+// RenderTemplate renders the template into the directory of the project with the specified name and the values
+// of the template parameters.
+func RenderTemplate(t *Template, dir, name string, values map[string]string) error {
+	data := make(map[string]any, len(values)+1)
+	for k, v := range values {
+		data[k] = v
+	}
+	data[projectNameKey] = name
+	return renderFS(os.DirFS(t.Dir), ".", dir, data)
+}
+
+// renderFS renders the files under the root of the file system into the directory with the data, where the
+// template file and the directories of the version control systems are skipped, and the binary files are copied
+// as is.
+func renderFS(fsys fs.FS, root, dir string, data any) error {
+	return fs.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Skip the top-level root directory and the template file.
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if relPath == "" || relPath == "." || relPath == TemplateFile {
+			return nil
+		}
+		if vcsDirs[d.Name()] {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			// A .git file links the work tree of a submodule or worktree to its repository.
+			return nil
+		}
+
+		dstPath := filepath.Join(dir, relPath)
+		if d.IsDir() {
+			return os.MkdirAll(dstPath, os.ModePerm)
+		}
+
+		srcFile, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+		if isBinary(srcFile) {
+			return os.WriteFile(dstPath, srcFile, 0o644)
+		}
+		tmpl, err := template.New(filepath.Base(path)).Option("missingkey=error").Parse(string(srcFile))
+		if err != nil {
+			return err
+		}
+
+		dstFile, err := os.Create(dstPath)
+		if err != nil {
+			return err
+		}
+		defer dstFile.Close()
+
+		return tmpl.Execute(dstFile, data)
+	})
+}
+
+// isBinary returns true if the content is not text, that is, it contains a NUL byte in the leading bytes, or is
+// not valid UTF-8.
+func isBinary(content []byte) bool {
+	sniff := content
+	if len(sniff) > binarySniffLen {
+		sniff = sniff[:binarySniffLen]
+	}
+	return bytes.IndexByte(sniff, 0) >= 0 || !utf8.Valid(content)
+}
@@ -0,0 +1,179 @@
+package scaffold
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testTemplatesDir = "testdata/templates"
+
+func TestLoadTemplate(t *testing.T) {
+	tmpl, err := LoadTemplate(filepath.Join(testTemplatesDir, "web-service"))
+	assert.NoError(t, err)
+	assert.Equal(t, "web-service", tmpl.Name)
+	assert.Equal(t, "1.0.0", tmpl.Version)
+	assert.Len(t, tmpl.Parameters, 2)
+
+	_, err = LoadTemplate(testTemplatesDir)
+	assert.Error(t, err)
+}
+
+func TestValidateTemplate(t *testing.T) {
+	testcases := []struct {
+		name    string
+		success bool
+		tmpl    *Template
+	}{
+		{
+			name:    "valid template",
+			success: true,
+			tmpl:    &Template{Name: "web", Parameters: []*TemplateParameter{{Name: "image"}, {Name: "replicas_2"}}},
+		},
+		{
+			name:    "invalid template empty name",
+			success: false,
+			tmpl:    &Template{},
+		},
+		{
+			name:    "invalid template parameter name",
+			success: false,
+			tmpl:    &Template{Name: "web", Parameters: []*TemplateParameter{{Name: "image-name"}}},
+		},
+		{
+			name:    "invalid template reserved parameter name",
+			success: false,
+			tmpl:    &Template{Name: "web", Parameters: []*TemplateParameter{{Name: "ProjectName"}}},
+		},
+		{
+			name:    "invalid template duplicate parameters",
+			success: false,
+			tmpl:    &Template{Name: "web", Parameters: []*TemplateParameter{{Name: "image"}, {Name: "image"}}},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateTemplate(tc.tmpl)
+			assert.Equal(t, tc.success, err == nil)
+		})
+	}
+}
+
+func TestListTemplates(t *testing.T) {
+	templates, err := ListTemplates(testTemplatesDir)
+	assert.NoError(t, err)
+	assert.Len(t, templates, 2)
+	assert.Equal(t, "job", templates[0].Name)
+	assert.Equal(t, "web-service", templates[1].Name)
+
+	templates, err = ListTemplates(filepath.Join(testTemplatesDir, "job"))
+	assert.NoError(t, err)
+	assert.Len(t, templates, 1)
+}
+
+func TestResolveValues(t *testing.T) {
+	tmpl, err := LoadTemplate(filepath.Join(testTemplatesDir, "web-service"))
+	assert.NoError(t, err)
+
+	t.Run("specified and default values", func(t *testing.T) {
+		values, err := ResolveValues(tmpl, map[string]string{"image": "nginx"}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"image": "nginx", "port": "8080"}, values)
+	})
+
+	t.Run("prompted values", func(t *testing.T) {
+		values, err := ResolveValues(tmpl, nil, func(p *TemplateParameter) (string, error) {
+			if p.Name == "image" {
+				return "nginx", nil
+			}
+			return "", nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"image": "nginx", "port": "8080"}, values)
+	})
+
+	t.Run("failed to prompt", func(t *testing.T) {
+		_, err := ResolveValues(tmpl, nil, func(p *TemplateParameter) (string, error) {
+			return "", errors.New("failed to prompt")
+		})
+		assert.ErrorContains(t, err, "failed to prompt")
+	})
+
+	t.Run("missing required value", func(t *testing.T) {
+		_, err := ResolveValues(tmpl, nil, nil)
+		assert.ErrorContains(t, err, "required")
+	})
+
+	t.Run("unknown parameter", func(t *testing.T) {
+		_, err := ResolveValues(tmpl, map[string]string{"image": "nginx", "replicas": "2"}, nil)
+		assert.ErrorContains(t, err, "unknown parameter")
+	})
+}
+
+func TestRenderTemplate(t *testing.T) {
+	tmpl, err := LoadTemplate(filepath.Join(testTemplatesDir, "web-service"))
+	assert.NoError(t, err)
+
+	dir := t.TempDir()
+	err = RenderTemplate(tmpl, dir, "my-app", map[string]string{"image": "nginx:1.25", "port": "80"})
+	assert.NoError(t, err)
+
+	_, err = os.Stat(filepath.Join(dir, TemplateFile))
+	assert.True(t, os.IsNotExist(err))
+	content, err := os.ReadFile(filepath.Join(dir, "project.yaml"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "name: my-app")
+	content, err = os.ReadFile(filepath.Join(dir, "dev", "main.k"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), `image: "nginx:1.25"`)
+	assert.Contains(t, string(content), "port: 80")
+
+	err = RenderTemplate(tmpl, t.TempDir(), "my-app", map[string]string{"image": "nginx:1.25"})
+	assert.Error(t, err)
+}
+
+func TestRenderFS(t *testing.T) {
+	png := []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n', 0, 0, '{', '{'}
+	fsys := fstest.MapFS{
+		"main.k":              {Data: []byte(`name = "{{ .ProjectName }}"`)},
+		"assets/logo.png":     {Data: png},
+		".git/HEAD":           {Data: []byte("ref: {{ refs/heads/main")},
+		"dev/.svn/entries":    {Data: []byte("{{ broken")},
+		"modules/nginx/.git":  {Data: []byte("gitdir: {{ ../.git/modules/nginx")},
+		"modules/nginx/kcl.k": {Data: []byte("{{ .ProjectName }}")},
+	}
+
+	dir := t.TempDir()
+	err := renderFS(fsys, ".", dir, map[string]any{projectNameKey: "my-app"})
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(dir, "main.k"))
+	assert.NoError(t, err)
+	assert.Equal(t, `name = "my-app"`, string(content))
+	content, err = os.ReadFile(filepath.Join(dir, "assets", "logo.png"))
+	assert.NoError(t, err)
+	assert.Equal(t, png, content)
+	content, err = os.ReadFile(filepath.Join(dir, "modules", "nginx", "kcl.k"))
+	assert.NoError(t, err)
+	assert.Equal(t, "my-app", string(content))
+	for _, path := range []string{".git", filepath.Join("dev", ".svn"), filepath.Join("modules", "nginx", ".git")} {
+		_, err = os.Stat(filepath.Join(dir, path))
+		assert.True(t, os.IsNotExist(err), path)
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	values, err := ParseValues([]string{"image=nginx:1.25", "args=a=b", "empty="})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"image": "nginx:1.25", "args": "a=b", "empty": ""}, values)
+
+	_, err = ParseValues([]string{"image"})
+	assert.Error(t, err)
+	_, err = ParseValues([]string{"=nginx"})
+	assert.Error(t, err)
+}
@@ -2,7 +2,9 @@ package server
 
 import (
 	"gorm.io/gorm"
+	"kusionstack.io/kusion/pkg/domain/constant"
 	"kusionstack.io/kusion/pkg/domain/entity"
+	"kusionstack.io/kusion/pkg/infra/encryption"
 )
 
 type Config struct {
@@ -16,9 +18,11 @@ type Config struct {
 	MaxConcurrent      int
 	MaxAsyncConcurrent int
 	MaxAsyncBuffer     int
+	RunRecoveryPolicy  constant.RunRecoveryPolicy
 	LogFilePath        string
 	AutoMigrate        bool
 	DevPortalEnabled   bool
+	VariableEncrypter  *encryption.Encrypter
 }
 
 func NewConfig() *Config {
@@ -106,6 +106,11 @@ func (h *Handler) GenerateStack() http.HandlerFunc {
 			render.Render(w, r, handler.FailureResponse(ctx, err))
 			return
 		}
+		// Mask the sensitive data before returning the spec.
+		if sp, err = stackmanager.MaskSpec(sp); err != nil {
+			render.Render(w, r, handler.FailureResponse(ctx, err))
+			return
+		}
 
 		yaml, err := yamlv2.Marshal(sp)
 		handler.HandleResult(w, r, ctx, err, string(yaml))
@@ -118,12 +123,15 @@ func (h *Handler) GenerateStack() http.HandlerFunc {
 // @Tags			stack
 // @Produce		json
 // @Param			stackID				path		int								true	"Stack ID"
-// @Param			importedResources	body		request.StackImportRequest		false	"The resources to import during the stack preview"
+// @Param			importedResources	body		request.StackImportRequest		false	"The resources to import during the stack preview, or the plan to apply"
 // @Param			workspace			query		string							true	"The target workspace to preview the spec in."
 // @Param			importResources		query		bool							false	"Import existing resources during the stack preview"
 // @Param			specID				query		string							false	"The Spec ID to use for the apply. Will generate a new spec if omitted."
 // @Param			force				query		bool							false	"Force the apply even when the stack is locked. May cause concurrency issues!!!"
 // @Param			dryrun				query		bool							false	"Apply in dry-run mode"
+// @Param			atomic				query		bool							false	"Roll back to the prior release if applying or watching any resource fails or times out"
+// @Param			continueOnError		query		bool							false	"Keep applying the resources not depending on the failed ones"
+// @Param			notes				query		string							false	"The change notes recorded in the release"
 // @Success		200					{object}	handler.Response{data=string}	"Success"
 // @Failure		400					{object}	error							"Bad Request"
 // @Failure		401					{object}	error							"Unauthorized"
@@ -141,15 +149,17 @@ func (h *Handler) ApplyStack() http.HandlerFunc {
 		}
 		logger.Info("Applying stack...", "stackID", params.StackID)
 
+		// The request body is required when importing resources, and optional
+		// otherwise to carry a plan to apply
 		var requestPayload request.StackImportRequest
-		if params.ExecuteParams.ImportResources {
+		if params.ExecuteParams.ImportResources || render.GetRequestContentType(r) == render.ContentTypeJSON {
 			if err := requestPayload.Decode(r); err != nil {
-				if err == io.EOF {
-					render.Render(w, r, handler.FailureResponse(ctx, fmt.Errorf("request body should not be empty when importResources is set to true")))
-					return
-				} else {
+				if err != io.EOF {
 					render.Render(w, r, handler.FailureResponse(ctx, err))
 					return
+				} else if params.ExecuteParams.ImportResources {
+					render.Render(w, r, handler.FailureResponse(ctx, fmt.Errorf("request body should not be empty when importResources is set to true")))
+					return
 				}
 			}
 		}
@@ -180,6 +190,7 @@ func (h *Handler) ApplyStack() http.HandlerFunc {
 // @Param			workspace	query		string							true	"The target workspace to preview the spec in."
 // @Param			force		query		bool							false	"Force the destroy even when the stack is locked. May cause concurrency issues!!!"
 // @Param			dryrun		query		bool							false	"Destroy in dry-run mode"
+// @Param			notes		query		string							false	"The change notes recorded in the release"
 // @Success		200			{object}	handler.Response{data=string}	"Success"
 // @Failure		400			{object}	error							"Bad Request"
 // @Failure		401			{object}	error							"Unauthorized"
@@ -0,0 +1,255 @@
+package stack
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"time"
+
+	yamlv2 "gopkg.in/yaml.v2"
+
+	apiv1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/domain/constant"
+	"kusionstack.io/kusion/pkg/domain/entity"
+	"kusionstack.io/kusion/pkg/engine/operation/models"
+	stackmanager "kusionstack.io/kusion/pkg/server/manager/stack"
+	appmiddleware "kusionstack.io/kusion/pkg/server/middleware"
+
+	logutil "kusionstack.io/kusion/pkg/server/util/logging"
+)
+
+// ExecuteRun executes a run claimed by the run queue with the parameters
+// persisted when the run was enqueued.
+func (h *Handler) ExecuteRun(ctx context.Context, run *entity.Run) {
+	parameters, err := stackmanager.ParseRunParameters(run)
+	if err != nil {
+		ctx = h.newRunContext(ctx, run, "")
+		logutil.LogToAll(logutil.GetLogger(ctx), logutil.GetRunLogger(ctx), "error", "Error parsing run parameters", "runID", run.ID, "error", err)
+		h.setRunToFailed(ctx, run)
+		return
+	}
+
+	ctx = h.newRunContext(ctx, run, parameters.Params.Operator)
+	params := &parameters.Params
+	params.RunID = run.ID
+	switch run.Type {
+	case constant.RunTypePreview:
+		h.previewRun(ctx, run, params, parameters)
+	case constant.RunTypeApply:
+		h.applyRun(ctx, run, params, parameters)
+	case constant.RunTypeGenerate:
+		h.generateRun(ctx, run, params)
+	case constant.RunTypeDestroy:
+		h.destroyRun(ctx, run, params)
+	default:
+		logutil.LogToAll(logutil.GetLogger(ctx), logutil.GetRunLogger(ctx), "error", "Unknown run type", "runID", run.ID, "type", run.Type)
+		h.setRunToFailed(ctx, run)
+	}
+}
+
+// RunRecovered notifies the webhooks of an orphaned run that has been marked
+// as failed or re-queued by the run queue.
+func (h *Handler) RunRecovered(ctx context.Context, run *entity.Run) {
+	h.notifyRunStatus(ctx, run)
+}
+
+// newRunContext rebuilds the request-scoped values of the run, which may be
+// executed long after the request or by another server replica. The run
+// logger starts with the logs persisted so far.
+func (h *Handler) newRunContext(ctx context.Context, run *entity.Run, operator string) context.Context {
+	traceID := run.Trace
+	if traceID == "" {
+		traceID = strconv.FormatUint(uint64(run.ID), 10)
+	}
+	ctx = context.WithValue(ctx, appmiddleware.TraceIDKey, traceID)
+	ctx = context.WithValue(ctx, appmiddleware.UserIDKey, operator)
+	if h.logFilePath != "" {
+		ctx = context.WithValue(ctx, appmiddleware.APILoggerKey, appmiddleware.InitLogger(h.logFilePath, traceID))
+	}
+	runLogger, runLoggerBuffer := appmiddleware.InitLoggerBuffer(traceID)
+	runLoggerBuffer.WriteString(run.Logs)
+	ctx = context.WithValue(ctx, appmiddleware.RunLoggerKey, runLogger)
+	ctx = context.WithValue(ctx, appmiddleware.RunLoggerBufferKey, runLoggerBuffer)
+	return ctx
+}
+
+func (h *Handler) previewRun(ctx context.Context, run *entity.Run, params *stackmanager.StackRequestParams, parameters *stackmanager.RunParameters) {
+	logger := logutil.GetLogger(ctx)
+	runLogger := logutil.GetRunLogger(ctx)
+	runLogger.Info("Starting previewing stack in StackManager ... This is a preview run.", "runID", run.ID)
+	logger.Info("Async preview in progress")
+
+	var err error
+	var previewChanges any
+	newCtx, cancel := context.WithTimeout(ctx, constant.RunTimeOut)
+	defer cancel()                                   // make sure the context is canceled to free resources
+	defer handleCrash(newCtx, h.setRunToFailed, run) // recover from possible panic
+
+	// update status of the run when exiting the async run
+	defer func() {
+		select {
+		case <-newCtx.Done():
+			logutil.LogToAll(logger, runLogger, "info", "preview execution timed out", "stackID", params.StackID, "time", time.Now(), "timeout", newCtx.Err())
+			h.setRunToCancelled(newCtx, run)
+		default:
+			if err != nil {
+				logutil.LogToAll(logger, runLogger, "error", "preview failed for stack", "stackID", params.StackID, "time", time.Now())
+				h.setRunToFailed(newCtx, run)
+			} else {
+				logutil.LogToAll(logger, runLogger, "info", "preview completed for stack", "stackID", params.StackID, "time", time.Now())
+				if pc, ok := previewChanges.(*models.Changes); ok {
+					h.setRunToSuccess(newCtx, run, pc)
+				} else {
+					logutil.LogToAll(logger, runLogger, "error", "Error casting preview changes to models.Changes", "error", "casting error")
+					h.setRunToFailed(newCtx, run)
+				}
+			}
+		}
+	}()
+
+	defer handleCrash(newCtx, h.setRunToFailed, run) // recover from possible panic
+
+	// Call preview stack
+	var changes *models.Changes
+	changes, err = h.stackManager.PreviewStack(newCtx, params, parameters.ImportedResources)
+	if err != nil {
+		logutil.LogToAll(logger, runLogger, "error", "Error previewing stack", "error", err)
+		return
+	}
+
+	// The summary table is only meaningful for a synchronous response
+	previewChanges, err = stackmanager.ProcessChanges(newCtx, io.Discard, changes, params.Format, params.ExecuteParams.Detail)
+	if err != nil {
+		logutil.LogToAll(logger, runLogger, "error", "Error processing preview changes", "error", err)
+		return
+	}
+}
+
+func (h *Handler) applyRun(ctx context.Context, run *entity.Run, params *stackmanager.StackRequestParams, parameters *stackmanager.RunParameters) {
+	logger := logutil.GetLogger(ctx)
+	runLogger := logutil.GetRunLogger(ctx)
+	runLogger.Info("Starting applying stack in StackManager ... This is an apply run.", "runID", run.ID)
+	logger.Info("Async apply in progress")
+
+	var err error
+	newCtx, cancel := context.WithTimeout(ctx, constant.RunTimeOut)
+	defer cancel()                                   // make sure the context is canceled to free resources
+	defer handleCrash(newCtx, h.setRunToFailed, run) // recover from possible panic
+
+	// update status of the run when exiting the async run
+	defer func() {
+		select {
+		case <-newCtx.Done():
+			logutil.LogToAll(logger, runLogger, "info", "apply execution timed out", "stackID", params.StackID, "time", time.Now(), "timeout", newCtx.Err())
+			h.setRunToCancelled(newCtx, run)
+		default:
+			if err != nil {
+				logutil.LogToAll(logger, runLogger, "error", "apply failed for stack", "stackID", params.StackID, "time", time.Now())
+				h.setRunToFailed(newCtx, run)
+			} else {
+				logutil.LogToAll(logger, runLogger, "info", "apply completed for stack", "stackID", params.StackID, "time", time.Now())
+				h.setRunToSuccess(newCtx, run, "apply completed")
+			}
+		}
+	}()
+
+	defer handleCrash(newCtx, h.setRunToFailed, run) // recover from possible panic
+
+	// call apply stack
+	err = h.stackManager.ApplyStack(newCtx, params, parameters.ImportedResources)
+	if err != nil {
+		if err == stackmanager.ErrDryrunDestroy {
+			logutil.LogToAll(logger, runLogger, "info", "Dry-run mode enabled, the above resources will be applied if dryrun is set to false")
+			return
+		} else {
+			logutil.LogToAll(logger, runLogger, "error", "Error applying stack", "error", err)
+			return
+		}
+	}
+}
+
+func (h *Handler) generateRun(ctx context.Context, run *entity.Run, params *stackmanager.StackRequestParams) {
+	logger := logutil.GetLogger(ctx)
+	runLogger := logutil.GetRunLogger(ctx)
+	runLogger.Info("Starting generating stack in StackManager ... This is a generate run.", "runID", run.ID)
+	logger.Info("Async generate in progress")
+
+	var err error
+	newCtx, cancel := context.WithTimeout(ctx, constant.RunTimeOut)
+	defer cancel()                                   // make sure the context is canceled to free resources
+	defer handleCrash(newCtx, h.setRunToFailed, run) // recover from possible panic
+
+	var sp *apiv1.Spec
+	// update status of the run when exiting the async run
+	defer func() {
+		select {
+		case <-newCtx.Done():
+			logutil.LogToAll(logger, runLogger, "info", "generate execution timed out", "stackID", params.StackID, "time", time.Now(), "timeout", newCtx.Err())
+			h.setRunToCancelled(newCtx, run)
+		default:
+			if err != nil {
+				logutil.LogToAll(logger, runLogger, "error", "generate failed for stack", "stackID", params.StackID, "time", time.Now())
+				h.setRunToFailed(newCtx, run)
+			} else {
+				logutil.LogToAll(logger, runLogger, "info", "generate completed for stack", "stackID", params.StackID, "time", time.Now())
+				if sp, err = stackmanager.MaskSpec(sp); err != nil {
+					logutil.LogToAll(logger, runLogger, "error", "Error masking generated spec", "error", err)
+					h.setRunToFailed(newCtx, run)
+				} else if yaml, err := yamlv2.Marshal(sp); err == nil {
+					h.setRunToSuccess(newCtx, run, string(yaml))
+				} else {
+					logutil.LogToAll(logger, runLogger, "error", "Error marshalling generated spec", "error", err)
+					h.setRunToFailed(newCtx, run)
+				}
+			}
+		}
+	}()
+
+	// Call generate stack
+	_, sp, err = h.stackManager.GenerateSpec(newCtx, params)
+	if err != nil {
+		logutil.LogToAll(logger, runLogger, "error", "Error generating stack", "error", err)
+		return
+	}
+}
+
+func (h *Handler) destroyRun(ctx context.Context, run *entity.Run, params *stackmanager.StackRequestParams) {
+	logger := logutil.GetLogger(ctx)
+	runLogger := logutil.GetRunLogger(ctx)
+	runLogger.Info("Starting destroying stack in StackManager ... This is a destroy run.", "runID", run.ID)
+	logger.Info("Async destroy in progress")
+
+	var err error
+	newCtx, cancel := context.WithTimeout(ctx, constant.RunTimeOut)
+	defer cancel()                                   // make sure the context is canceled to free resources
+	defer handleCrash(newCtx, h.setRunToFailed, run) // recover from possible panic
+
+	// update status of the run when exiting the async run
+	defer func() {
+		select {
+		case <-newCtx.Done():
+			logutil.LogToAll(logger, runLogger, "info", "destroy execution timed out", "stackID", params.StackID, "time", time.Now(), "timeout", newCtx.Err())
+			h.setRunToCancelled(newCtx, run)
+		default:
+			if err != nil {
+				logutil.LogToAll(logger, runLogger, "error", "destroy failed for stack", "stackID", params.StackID, "time", time.Now())
+				h.setRunToFailed(newCtx, run)
+			} else {
+				logutil.LogToAll(logger, runLogger, "info", "destroy completed for stack", "stackID", params.StackID, "time", time.Now())
+				h.setRunToSuccess(newCtx, run, "destroy completed")
+			}
+		}
+	}()
+
+	// Call destroy stack
+	err = h.stackManager.DestroyStack(newCtx, params, io.Discard)
+	if err != nil {
+		if err == stackmanager.ErrDryrunDestroy {
+			logutil.LogToAll(logger, runLogger, "info", "Dry-run mode enabled, the above resources will be destroyed if dryrun is set to false")
+			return
+		} else {
+			logutil.LogToAll(logger, runLogger, "error", "Error destroying stack", "error", err)
+			return
+		}
+	}
+}
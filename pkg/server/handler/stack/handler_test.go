@@ -318,6 +318,20 @@ func TestStackHandler(t *testing.T) {
 		assert.Equal(t, false, resp.Success)
 		assert.Equal(t, stackmanager.ErrUpdatingNonExistingStack.Error(), resp.Message)
 	})
+
+	t.Run("Set Run To Failed After Losing Lease", func(t *testing.T) {
+		sqlMock, fakeGDB, _, stackHandler := setupTest(t)
+		defer persistence.CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		// The run has been recovered or re-claimed by another replica, so
+		// no row is updated and the run is not read again to notify.
+		sqlMock.ExpectExec("UPDATE `run`").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		stackHandler.setRunToFailed(context.Background(), &entity.Run{ID: 1, LeaseOwner: "replica-a"})
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
 }
 
 func setupTest(t *testing.T) (sqlmock.Sqlmock, *gorm.DB, *httptest.ResponseRecorder, *Handler) {
@@ -328,8 +342,10 @@ func setupTest(t *testing.T) (sqlmock.Sqlmock, *gorm.DB, *httptest.ResponseRecor
 	workspaceRepo := persistence.NewWorkspaceRepository(fakeGDB)
 	resourceRepo := persistence.NewResourceRepository(fakeGDB)
 	runRepo := persistence.NewRunRepository(fakeGDB)
+	variableSetRepo := persistence.NewVariableSetRepository(fakeGDB)
+	variableRepo := persistence.NewVariableRepository(fakeGDB)
 	stackHandler := &Handler{
-		stackManager: stackmanager.NewStackManager(stackRepo, projectRepo, workspaceRepo, resourceRepo, runRepo, entity.Backend{}, constant.MaxConcurrent),
+		stackManager: stackmanager.NewStackManager(stackRepo, projectRepo, workspaceRepo, resourceRepo, runRepo, variableSetRepo, variableRepo, nil, entity.Backend{}, constant.MaxConcurrent),
 	}
 	recorder := httptest.NewRecorder()
 	return sqlMock, fakeGDB, recorder, stackHandler
@@ -0,0 +1,79 @@
+package stack
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/render"
+	"kusionstack.io/kusion/pkg/engine/release"
+	"kusionstack.io/kusion/pkg/server/handler"
+)
+
+// Output formats of the release diff
+const (
+	releaseDiffOutputHuman    = "human"
+	releaseDiffOutputMarkdown = "markdown"
+)
+
+// @Id				diffReleases
+// @Summary		Diff releases
+// @Description	Compare the spec and state of the resources between two releases of the stack in the workspace
+// @Tags			stack
+// @Produce		json
+// @Param			stackID		path		int							true	"Stack ID"
+// @Param			workspace	query		string						true	"The target workspace of the releases"
+// @Param			from		query		int							false	"The revision to compare from. Default to the one before the revision to compare to"
+// @Param			to			query		int							false	"The revision to compare to. Default to the latest revision"
+// @Param			type		query		[]string					false	"The resource types to compare. Default to all"
+// @Param			id			query		[]string					false	"Only compare the resources whose IDs contain any of the values. Default to all"
+// @Param			output		query		string						false	"Output format. Choices are: json, human, markdown. Default to json"
+// @Success		200			{object}	handler.Response{data=any}	"Success"
+// @Failure		400			{object}	error						"Bad Request"
+// @Failure		401			{object}	error						"Unauthorized"
+// @Failure		429			{object}	error						"Too Many Requests"
+// @Failure		404			{object}	error						"Not Found"
+// @Failure		500			{object}	error						"Internal Server Error"
+// @Router			/api/v1/stacks/{stackID}/releases/diff [get]
+func (h *Handler) DiffReleases() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Getting stuff from context
+		ctx, logger, params, err := requestHelper(r)
+		if err != nil {
+			render.Render(w, r, handler.FailureResponse(ctx, err))
+			return
+		}
+		logger.Info("Comparing releases...", "stackID", params.StackID)
+
+		query := r.URL.Query()
+		var from, to uint64
+		if v := query.Get("from"); v != "" {
+			if from, err = strconv.ParseUint(v, 10, 64); err != nil {
+				render.Render(w, r, handler.FailureResponse(ctx, err))
+				return
+			}
+		}
+		if v := query.Get("to"); v != "" {
+			if to, err = strconv.ParseUint(v, 10, 64); err != nil {
+				render.Render(w, r, handler.FailureResponse(ctx, err))
+				return
+			}
+		}
+		filter := &release.DiffFilter{Types: query["type"], IDs: query["id"]}
+
+		d, err := h.stackManager.DiffReleases(ctx, params, from, to, filter)
+		if err != nil {
+			render.Render(w, r, handler.FailureResponse(ctx, err))
+			return
+		}
+		switch params.Format {
+		case releaseDiffOutputHuman:
+			human, err := d.Human(true)
+			handler.HandleResult(w, r, ctx, err, human)
+		case releaseDiffOutputMarkdown:
+			md, err := d.Markdown()
+			handler.HandleResult(w, r, ctx, err, md)
+		default:
+			handler.HandleResult(w, r, ctx, nil, d)
+		}
+	}
+}
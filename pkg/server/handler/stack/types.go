@@ -1,28 +1,36 @@
 package stack
 
 import (
-	worker "kusionstack.io/kusion/pkg/infra/util/worker"
+	runqueue "kusionstack.io/kusion/pkg/server/manager/runqueue"
 	stackmanager "kusionstack.io/kusion/pkg/server/manager/stack"
+	webhookmanager "kusionstack.io/kusion/pkg/server/manager/webhook"
 )
 
 func NewHandler(
 	stackManager *stackmanager.StackManager,
-	maxAsyncConcurrent int,
-	maxAsyncBuffer int,
+	webhookManager *webhookmanager.WebhookManager,
+	runQueue *runqueue.RunQueue,
+	logFilePath string,
 ) (*Handler, error) {
 	return &Handler{
-		stackManager: stackManager,
-		workerPool:   worker.NewWorkerPool(maxAsyncConcurrent, maxAsyncBuffer),
+		stackManager:   stackManager,
+		webhookManager: webhookManager,
+		runQueue:       runQueue,
+		logFilePath:    logFilePath,
 	}, nil
 }
 
 type Handler struct {
-	stackManager *stackmanager.StackManager
-	workerPool   *worker.WorkerPool
+	stackManager   *stackmanager.StackManager
+	webhookManager *webhookmanager.WebhookManager
+	runQueue       *runqueue.RunQueue
+	logFilePath    string
 }
 
-// TODO: graceful shutdown of worker pool when exiting
-// Capture sigterm and sigint signals to shutdown the worker pool
+// Shutdown stops the run queue from claiming new runs and waits for the runs
+// in progress to complete. The queued runs are picked up after a restart.
 func (h *Handler) Shutdown() {
-	h.workerPool.Wait() // wait for all workers to finish
+	if h.runQueue != nil {
+		h.runQueue.Shutdown()
+	}
 }
@@ -4,24 +4,27 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"runtime"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/httplog/v2"
 	"kusionstack.io/kusion/pkg/domain/constant"
+	"kusionstack.io/kusion/pkg/domain/entity"
 	"kusionstack.io/kusion/pkg/domain/request"
+	"kusionstack.io/kusion/pkg/engine/operation/models"
 	stackmanager "kusionstack.io/kusion/pkg/server/manager/stack"
+	webhookmanager "kusionstack.io/kusion/pkg/server/manager/webhook"
 	appmiddleware "kusionstack.io/kusion/pkg/server/middleware"
 
 	authutil "kusionstack.io/kusion/pkg/server/util/auth"
 	logutil "kusionstack.io/kusion/pkg/server/util/logging"
 )
 
-func (h *Handler) setRunToSuccess(ctx context.Context, runID uint, result any) {
+func (h *Handler) setRunToSuccess(ctx context.Context, run *entity.Run, result any) {
 	logger := logutil.GetLogger(ctx)
 	runLogs := logutil.GetRunLoggerBuffer(ctx)
 	resultBytes, err := json.Marshal(result)
@@ -35,14 +38,21 @@ func (h *Handler) setRunToSuccess(ctx context.Context, runID uint, result any) {
 		Status: string(constant.RunStatusSucceeded),
 		Logs:   runLogs.String(),
 	}
-	_, err = h.stackManager.UpdateRunResultAndStatusByID(ctx, runID, updateRunResultPayload)
+	updatedRun, err := h.finishRun(ctx, run, updateRunResultPayload)
 	if err != nil {
 		logger.Error("Error updating run result after success", "error", err)
 		return
 	}
+	if updatedRun == nil {
+		return
+	}
+	h.notifyRunStatus(ctx, updatedRun)
+	if changes, ok := result.(*models.Changes); ok {
+		h.notifyStackOutOfSync(ctx, updatedRun, changes)
+	}
 }
 
-func (h *Handler) setRunToFailed(ctx context.Context, runID uint) {
+func (h *Handler) setRunToFailed(ctx context.Context, run *entity.Run) {
 	logger := logutil.GetLogger(ctx)
 	runLogs := logutil.GetRunLoggerBuffer(ctx)
 	logsNew := strings.ReplaceAll(runLogs.String(), "\n", "\n\n")
@@ -51,13 +61,17 @@ func (h *Handler) setRunToFailed(ctx context.Context, runID uint) {
 		Status: string(constant.RunStatusFailed),
 		Logs:   logsNew,
 	}
-	_, err := h.stackManager.UpdateRunResultAndStatusByID(ctx, runID, updateRunResultPayload)
+	updatedRun, err := h.finishRun(ctx, run, updateRunResultPayload)
 	if err != nil {
 		logger.Error("Error updating run result after failure", "error", err)
+		return
 	}
+	h.notifyRunStatus(ctx, updatedRun)
 }
 
-func (h *Handler) setRunToCancelled(ctx context.Context, runID uint) {
+// setRunToCancelled marks the run claimed by the run queue as cancelled,
+// unless the lease of the run has been lost on the way.
+func (h *Handler) setRunToCancelled(ctx context.Context, run *entity.Run) {
 	logger := logutil.GetLogger(ctx)
 	runLogs := logutil.GetRunLoggerBuffer(ctx)
 	updateRunResultPayload := request.UpdateRunResultRequest{
@@ -66,25 +80,86 @@ func (h *Handler) setRunToCancelled(ctx context.Context, runID uint) {
 		Logs:   runLogs.String(),
 	}
 	newCtx := CopyToNewContext(ctx)
-	_, err := h.stackManager.UpdateRunResultAndStatusByID(newCtx, runID, updateRunResultPayload)
+	updatedRun, err := h.finishRun(newCtx, run, updateRunResultPayload)
 	if err != nil {
 		logger.Error("Error updating run result after timeout", "error", err)
+		return
 	}
+	h.notifyRunStatus(newCtx, updatedRun)
 }
 
-func (h *Handler) setRunToQueued(ctx context.Context, runID uint) {
-	logger := logutil.GetLogger(ctx)
-	runLogs := logutil.GetRunLoggerBuffer(ctx)
-	updateRunResultPayload := request.UpdateRunResultRequest{
-		Result: "",
-		Status: string(constant.RunStatusQueued),
-		Logs:   runLogs.String(),
-	}
-	newCtx := CopyToNewContext(ctx)
-	_, err := h.stackManager.UpdateRunResultAndStatusByID(newCtx, runID, updateRunResultPayload)
+// finishRun moves the run claimed by the run queue to its terminal status.
+// It returns nil if the lease of the run has been lost, since the run then
+// belongs to the replica recovering or claiming it.
+func (h *Handler) finishRun(ctx context.Context, run *entity.Run, requestPayload request.UpdateRunResultRequest) (*entity.Run, error) {
+	updatedRun, err := h.stackManager.UpdateLeasedRunResultAndStatus(ctx, run, requestPayload)
 	if err != nil {
-		logger.Error("Error updating run result after queueing", "error", err)
+		return nil, err
+	}
+	if updatedRun == nil {
+		logutil.GetLogger(ctx).Info("Lost lease of run, leaving its status to the new owner", "runID", run.ID)
+	}
+	return updatedRun, nil
+}
+
+// notifyRunStatus fires the webhook event matching the new status of the run.
+func (h *Handler) notifyRunStatus(ctx context.Context, run *entity.Run) {
+	if h.webhookManager == nil || run == nil {
+		return
+	}
+	event, ok := constant.WebhookEventForRunStatus(run.Status)
+	if !ok {
+		return
 	}
+	h.webhookManager.Notify(ctx, &webhookmanager.Event{
+		Type:    event,
+		Run:     run,
+		Message: fmt.Sprintf("%s run %d of stack %s in workspace %s is %s", run.Type, run.ID, stackName(run), run.Workspace, run.Status),
+	})
+}
+
+// notifyStackOutOfSync fires the out-of-sync webhook event when a preview of
+// a stack that has been applied before detects changes, which means the live
+// resources have drifted from or no longer match the desired spec.
+func (h *Handler) notifyStackOutOfSync(ctx context.Context, run *entity.Run, changes *models.Changes) {
+	if h.webhookManager == nil || run == nil || changes == nil || changes.ChangeOrder == nil {
+		return
+	}
+	if run.Type != constant.RunTypePreview || run.Stack == nil || run.Stack.LastAppliedTimestamp.IsZero() {
+		return
+	}
+	if changes.AllUnChange() {
+		return
+	}
+
+	summary := &webhookmanager.ChangeSummary{}
+	for _, step := range changes.Values() {
+		switch step.Action {
+		case models.Create:
+			summary.Create++
+		case models.Update:
+			summary.Update++
+		case models.Delete:
+			summary.Delete++
+		default:
+			summary.UnChanged++
+			continue
+		}
+		summary.Resources = append(summary.Resources, step.ID)
+	}
+	h.webhookManager.Notify(ctx, &webhookmanager.Event{
+		Type:    constant.WebhookEventStackOutOfSync,
+		Run:     run,
+		Message: fmt.Sprintf("stack %s in workspace %s is out of sync with its spec", stackName(run), run.Workspace),
+		Changes: summary,
+	})
+}
+
+func stackName(run *entity.Run) string {
+	if run.Stack == nil {
+		return ""
+	}
+	return run.Stack.Name
 }
 
 func requestHelper(r *http.Request) (context.Context, *httplog.Logger, *stackmanager.StackRequestParams, error) {
@@ -104,6 +179,8 @@ func requestHelper(r *http.Request) (context.Context, *httplog.Logger, *stackman
 	noCacheParam, _ := strconv.ParseBool(r.URL.Query().Get("noCache"))
 	unlockParam, _ := strconv.ParseBool(r.URL.Query().Get("unlock"))
 	watchParam, _ := strconv.ParseBool(r.URL.Query().Get("watch"))
+	atomicParam, _ := strconv.ParseBool(r.URL.Query().Get("atomic"))
+	continueOnErrorParam, _ := strconv.ParseBool(r.URL.Query().Get("continueOnError"))
 	watchTimeoutStr := r.URL.Query().Get("watchTimeout")
 	if watchTimeoutStr == "" {
 		watchTimeoutStr = "120"
@@ -114,6 +191,7 @@ func requestHelper(r *http.Request) (context.Context, *httplog.Logger, *stackman
 	}
 	importResourcesParam, _ := strconv.ParseBool(r.URL.Query().Get("importResources"))
 	specIDParam := r.URL.Query().Get("specID")
+	notesParam := r.URL.Query().Get("notes")
 	// TODO: Should match automatically eventually???
 	workspaceParam := r.URL.Query().Get("workspace")
 	operatorParam, err := authutil.GetSubjectFromUnverifiedJWTToken(ctx, r)
@@ -134,6 +212,9 @@ func requestHelper(r *http.Request) (context.Context, *httplog.Logger, *stackman
 		Unlock:              unlockParam,
 		Watch:               watchParam,
 		WatchTimeoutSeconds: watchTimeoutParam,
+		Atomic:              atomicParam,
+		ContinueOnError:     continueOnErrorParam,
+		Notes:               notesParam,
 	}
 	params := stackmanager.StackRequestParams{
 		StackID:       uint(id),
@@ -176,12 +257,6 @@ func CopyToNewContext(ctx context.Context) context.Context {
 	return newCtx
 }
 
-func CopyToNewContextWithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
-	newCtx := CopyToNewContext(ctx)
-	newCtxWithTimeout, cancel := context.WithTimeout(newCtx, timeout)
-	return newCtxWithTimeout, cancel
-}
-
 func logStackTrace(runLogger *httplog.Logger) {
 	buf := make([]byte, 1<<16) // 64KB
 	stackSize := runtime.Stack(buf, true)
@@ -189,9 +264,9 @@ func logStackTrace(runLogger *httplog.Logger) {
 	runLogger.Error(string(buf[:stackSize]))
 }
 
-type SetRunToFailedFunc func(context.Context, uint)
+type SetRunToFailedFunc func(context.Context, *entity.Run)
 
-func handleCrash(ctx context.Context, statusHandlingFunc SetRunToFailedFunc, runID uint) {
+func handleCrash(ctx context.Context, statusHandlingFunc SetRunToFailedFunc, run *entity.Run) {
 	if r := recover(); r != nil {
 		logger := logutil.GetLogger(ctx)
 		runLogger := logutil.GetRunLogger(ctx)
@@ -199,7 +274,7 @@ func handleCrash(ctx context.Context, statusHandlingFunc SetRunToFailedFunc, run
 		logStackTrace(logger)
 		runLogger.Error("Panic recovered", "error", r)
 		logStackTrace(runLogger)
-		statusHandlingFunc(ctx, runID)
+		statusHandlingFunc(ctx, run)
 	}
 }
 
@@ -230,6 +230,30 @@ func (h *Handler) ListVariables() http.HandlerFunc {
 	}
 }
 
+// @Id				rotateVariableKeys
+// @Summary		Rotate variable keys
+// @Description	Re-encrypt the CipherText variables with the current key of the key provider
+// @Tags			variable
+// @Produce		json
+// @Success		200								{object}	handler.Response{data=response.VariableKeyRotationResponse}	"Success"
+// @Failure		400								{object}	error														"Bad Request"
+// @Failure		401								{object}	error														"Unauthorized"
+// @Failure		429								{object}	error														"Too Many Requests"
+// @Failure		404								{object}	error														"Not Found"
+// @Failure		500								{object}	error														"Internal Server Error"
+// @Router			/api/v1/variables/rotate-keys	[post]
+func (h *Handler) RotateVariableKeys() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Getting stuff from context.
+		ctx := r.Context()
+		logger := logutil.GetLogger(ctx)
+		logger.Info("Rotating variable keys...")
+
+		result, err := h.variableManager.RotateVariableKeys(ctx)
+		handler.HandleResult(w, r, ctx, err, result)
+	}
+}
+
 func requestHelper(r *http.Request) (context.Context, *httplog.Logger, *VariableRequestParams, error) {
 	ctx := r.Context()
 	logger := logutil.GetLogger(ctx)
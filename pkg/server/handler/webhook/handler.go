@@ -0,0 +1,299 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/httplog/v2"
+	"github.com/go-chi/render"
+	"kusionstack.io/kusion/pkg/domain/request"
+	"kusionstack.io/kusion/pkg/domain/response"
+	"kusionstack.io/kusion/pkg/server/handler"
+	webhookmanager "kusionstack.io/kusion/pkg/server/manager/webhook"
+	logutil "kusionstack.io/kusion/pkg/server/util/logging"
+)
+
+// @Id				createWebhook
+// @Summary		Create webhook
+// @Description	Create a new webhook subscribed to run lifecycle events
+// @Tags			webhook
+// @Accept			json
+// @Produce		json
+// @Param			webhook	body		request.CreateWebhookRequest			true	"Created webhook"
+// @Success		200		{object}	handler.Response{data=entity.Webhook}	"Success"
+// @Failure		400		{object}	error									"Bad Request"
+// @Failure		401		{object}	error									"Unauthorized"
+// @Failure		429		{object}	error									"Too Many Requests"
+// @Failure		404		{object}	error									"Not Found"
+// @Failure		500		{object}	error									"Internal Server Error"
+// @Router			/api/v1/webhooks [post]
+func (h *Handler) CreateWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Getting stuff from context
+		ctx := r.Context()
+		logger := logutil.GetLogger(ctx)
+		logger.Info("Creating webhook...")
+
+		// Decode the request body into the payload.
+		var requestPayload request.CreateWebhookRequest
+		if err := requestPayload.Decode(r); err != nil {
+			render.Render(w, r, handler.FailureResponse(ctx, err))
+			return
+		}
+
+		// Validate request payload
+		if err := requestPayload.Validate(); err != nil {
+			render.Render(w, r, handler.FailureResponse(ctx, err))
+			return
+		}
+
+		createdEntity, err := h.webhookManager.CreateWebhook(ctx, requestPayload)
+		handler.HandleResult(w, r, ctx, err, createdEntity)
+	}
+}
+
+// @Id				deleteWebhook
+// @Summary		Delete webhook
+// @Description	Delete specified webhook by ID
+// @Tags			webhook
+// @Produce		json
+// @Param			webhookID	path		int								true	"Webhook ID"
+// @Success		200			{object}	handler.Response{data=string}	"Success"
+// @Failure		400			{object}	error							"Bad Request"
+// @Failure		401			{object}	error							"Unauthorized"
+// @Failure		429			{object}	error							"Too Many Requests"
+// @Failure		404			{object}	error							"Not Found"
+// @Failure		500			{object}	error							"Internal Server Error"
+// @Router			/api/v1/webhooks/{webhookID} [delete]
+func (h *Handler) DeleteWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Getting stuff from context
+		ctx, logger, params, err := requestHelper(r)
+		if err != nil {
+			render.Render(w, r, handler.FailureResponse(ctx, err))
+			return
+		}
+		logger.Info("Deleting webhook...", "webhookID", params.WebhookID)
+
+		err = h.webhookManager.DeleteWebhookByID(ctx, params.WebhookID)
+		handler.HandleResult(w, r, ctx, err, "Deletion Success")
+	}
+}
+
+// @Id				updateWebhook
+// @Summary		Update webhook
+// @Description	Update the specified webhook
+// @Tags			webhook
+// @Accept			json
+// @Produce		json
+// @Param			webhookID	path		int										true	"Webhook ID"
+// @Param			webhook		body		request.UpdateWebhookRequest			true	"Updated webhook"
+// @Success		200			{object}	handler.Response{data=entity.Webhook}	"Success"
+// @Failure		400			{object}	error									"Bad Request"
+// @Failure		401			{object}	error									"Unauthorized"
+// @Failure		429			{object}	error									"Too Many Requests"
+// @Failure		404			{object}	error									"Not Found"
+// @Failure		500			{object}	error									"Internal Server Error"
+// @Router			/api/v1/webhooks/{webhookID} [put]
+func (h *Handler) UpdateWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Getting stuff from context
+		ctx, logger, params, err := requestHelper(r)
+		if err != nil {
+			render.Render(w, r, handler.FailureResponse(ctx, err))
+			return
+		}
+		logger.Info("Updating webhook...", "webhookID", params.WebhookID)
+
+		// Decode the request body into the payload.
+		var requestPayload request.UpdateWebhookRequest
+		if err := requestPayload.Decode(r); err != nil {
+			render.Render(w, r, handler.FailureResponse(ctx, err))
+			return
+		}
+
+		// Validate request payload
+		if err := requestPayload.Validate(); err != nil {
+			render.Render(w, r, handler.FailureResponse(ctx, err))
+			return
+		}
+
+		updatedEntity, err := h.webhookManager.UpdateWebhookByID(ctx, params.WebhookID, requestPayload)
+		handler.HandleResult(w, r, ctx, err, updatedEntity)
+	}
+}
+
+// @Id				getWebhook
+// @Summary		Get webhook
+// @Description	Get webhook information by webhook ID
+// @Tags			webhook
+// @Produce		json
+// @Param			webhookID	path		int										true	"Webhook ID"
+// @Success		200			{object}	handler.Response{data=entity.Webhook}	"Success"
+// @Failure		400			{object}	error									"Bad Request"
+// @Failure		401			{object}	error									"Unauthorized"
+// @Failure		429			{object}	error									"Too Many Requests"
+// @Failure		404			{object}	error									"Not Found"
+// @Failure		500			{object}	error									"Internal Server Error"
+// @Router			/api/v1/webhooks/{webhookID} [get]
+func (h *Handler) GetWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Getting stuff from context
+		ctx, logger, params, err := requestHelper(r)
+		if err != nil {
+			render.Render(w, r, handler.FailureResponse(ctx, err))
+			return
+		}
+		logger.Info("Getting webhook...", "webhookID", params.WebhookID)
+
+		existingEntity, err := h.webhookManager.GetWebhookByID(ctx, params.WebhookID)
+		handler.HandleResult(w, r, ctx, err, existingEntity)
+	}
+}
+
+// @Id				listWebhook
+// @Summary		List webhooks
+// @Description	List all webhooks
+// @Tags			webhook
+// @Produce		json
+// @Param			orgID		query		uint														false	"OrganizationID to filter webhooks by. Default to all"
+// @Param			projectID	query		uint														false	"ProjectID to filter webhooks by. Default to all"
+// @Param			stackID		query		uint														false	"StackID to filter webhooks by. Default to all"
+// @Param			page		query		uint														false	"The current page to fetch. Default to 1"
+// @Param			pageSize	query		uint														false	"The size of the page. Default to 10"
+// @Param			sortBy		query		string														false	"Which field to sort the list by. Default to id"
+// @Param			ascending	query		bool														false	"Whether to sort the list in ascending order. Default to false"
+// @Success		200			{object}	handler.Response{data=response.PaginatedWebhookResponse}	"Success"
+// @Failure		400			{object}	error														"Bad Request"
+// @Failure		401			{object}	error														"Unauthorized"
+// @Failure		429			{object}	error														"Too Many Requests"
+// @Failure		404			{object}	error														"Not Found"
+// @Failure		500			{object}	error														"Internal Server Error"
+// @Router			/api/v1/webhooks [get]
+func (h *Handler) ListWebhooks() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Getting stuff from context
+		ctx := r.Context()
+		logger := logutil.GetLogger(ctx)
+		logger.Info("Listing webhooks...")
+
+		query := r.URL.Query()
+		filter, webhookSortOptions, err := h.webhookManager.BuildWebhookFilterAndSortOptions(ctx, &query)
+		if err != nil {
+			render.Render(w, r, handler.FailureResponse(ctx, err))
+			return
+		}
+
+		// List paginated webhooks.
+		webhookEntities, err := h.webhookManager.ListWebhooks(ctx, filter, webhookSortOptions)
+		if err != nil {
+			render.Render(w, r, handler.FailureResponse(ctx, err))
+			return
+		}
+
+		paginatedResponse := response.PaginatedWebhookResponse{
+			Webhooks:    webhookEntities.Webhooks,
+			Total:       webhookEntities.Total,
+			CurrentPage: filter.Pagination.Page,
+			PageSize:    filter.Pagination.PageSize,
+		}
+		handler.HandleResult(w, r, ctx, err, paginatedResponse)
+	}
+}
+
+// @Id				listWebhookDeliveries
+// @Summary		List webhook deliveries
+// @Description	List the delivery log of the specified webhook
+// @Tags			webhook
+// @Produce		json
+// @Param			webhookID	path		int																true	"Webhook ID"
+// @Param			runID		query		uint															false	"RunID to filter deliveries by. Default to all"
+// @Param			status		query		string															false	"Status to filter deliveries by. Default to all"
+// @Param			page		query		uint															false	"The current page to fetch. Default to 1"
+// @Param			pageSize	query		uint															false	"The size of the page. Default to 10"
+// @Param			sortBy		query		string															false	"Which field to sort the list by. Default to id"
+// @Param			ascending	query		bool															false	"Whether to sort the list in ascending order. Default to false"
+// @Success		200			{object}	handler.Response{data=response.PaginatedWebhookDeliveryResponse}	"Success"
+// @Failure		400			{object}	error															"Bad Request"
+// @Failure		401			{object}	error															"Unauthorized"
+// @Failure		429			{object}	error															"Too Many Requests"
+// @Failure		404			{object}	error															"Not Found"
+// @Failure		500			{object}	error															"Internal Server Error"
+// @Router			/api/v1/webhooks/{webhookID}/deliveries [get]
+func (h *Handler) ListWebhookDeliveries() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Getting stuff from context
+		ctx, logger, params, err := requestHelper(r)
+		if err != nil {
+			render.Render(w, r, handler.FailureResponse(ctx, err))
+			return
+		}
+		logger.Info("Listing webhook deliveries...", "webhookID", params.WebhookID)
+
+		query := r.URL.Query()
+		filter, deliverySortOptions, err := h.webhookManager.BuildWebhookDeliveryFilterAndSortOptions(ctx, params.WebhookID, &query)
+		if err != nil {
+			render.Render(w, r, handler.FailureResponse(ctx, err))
+			return
+		}
+
+		deliveryEntities, err := h.webhookManager.ListWebhookDeliveries(ctx, filter, deliverySortOptions)
+		if err != nil {
+			render.Render(w, r, handler.FailureResponse(ctx, err))
+			return
+		}
+
+		paginatedResponse := response.PaginatedWebhookDeliveryResponse{
+			Deliveries:  deliveryEntities.Deliveries,
+			Total:       deliveryEntities.Total,
+			CurrentPage: filter.Pagination.Page,
+			PageSize:    filter.Pagination.PageSize,
+		}
+		handler.HandleResult(w, r, ctx, err, paginatedResponse)
+	}
+}
+
+// @Id				pingWebhook
+// @Summary		Ping webhook
+// @Description	Send a ping event to the specified webhook and return the delivery
+// @Tags			webhook
+// @Produce		json
+// @Param			webhookID	path		int												true	"Webhook ID"
+// @Success		200			{object}	handler.Response{data=entity.WebhookDelivery}	"Success"
+// @Failure		400			{object}	error											"Bad Request"
+// @Failure		401			{object}	error											"Unauthorized"
+// @Failure		429			{object}	error											"Too Many Requests"
+// @Failure		404			{object}	error											"Not Found"
+// @Failure		500			{object}	error											"Internal Server Error"
+// @Router			/api/v1/webhooks/{webhookID}/ping [post]
+func (h *Handler) PingWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Getting stuff from context
+		ctx, logger, params, err := requestHelper(r)
+		if err != nil {
+			render.Render(w, r, handler.FailureResponse(ctx, err))
+			return
+		}
+		logger.Info("Pinging webhook...", "webhookID", params.WebhookID)
+
+		delivery, err := h.webhookManager.Ping(ctx, params.WebhookID)
+		handler.HandleResult(w, r, ctx, err, delivery)
+	}
+}
+
+func requestHelper(r *http.Request) (context.Context, *httplog.Logger, *WebhookRequestParams, error) {
+	ctx := r.Context()
+	webhookID := chi.URLParam(r, "webhookID")
+	// Get webhook with repository
+	id, err := strconv.Atoi(webhookID)
+	if err != nil {
+		return ctx, nil, nil, webhookmanager.ErrInvalidWebhookID
+	}
+	logger := logutil.GetLogger(ctx)
+	params := WebhookRequestParams{
+		WebhookID: uint(id),
+	}
+	return ctx, logger, &params, nil
+}
@@ -0,0 +1,194 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"kusionstack.io/kusion/pkg/domain/request"
+	"kusionstack.io/kusion/pkg/infra/persistence"
+	"kusionstack.io/kusion/pkg/server/handler"
+	webhookmanager "kusionstack.io/kusion/pkg/server/manager/webhook"
+)
+
+func TestWebhookHandler(t *testing.T) {
+	webhookName := "test-webhook"
+	webhookURL := "https://hooks.example.com/kusion"
+
+	t.Run("ListWebhooks", func(t *testing.T) {
+		sqlMock, fakeGDB, recorder, webhookHandler := setupTest(t)
+		defer persistence.CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		sqlMock.ExpectQuery("SELECT count(.*) FROM `webhook`").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		sqlMock.ExpectQuery("SELECT .* FROM `webhook`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "secret"}).
+				AddRow(1, webhookName, "s3cr3t").
+				AddRow(2, "test-webhook-2", ""))
+
+		req, err := http.NewRequest("GET", "/webhooks", nil)
+		assert.NoError(t, err)
+
+		webhookHandler.ListWebhooks()(recorder, req)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		var resp handler.Response
+		err = json.Unmarshal(recorder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+
+		webhooks := resp.Data.(map[string]any)["webhooks"].([]any)
+		assert.Equal(t, 2, len(webhooks))
+		assert.Equal(t, "**********", webhooks[0].(map[string]any)["secret"])
+	})
+
+	t.Run("GetWebhook", func(t *testing.T) {
+		sqlMock, fakeGDB, recorder, webhookHandler := setupTest(t)
+		defer persistence.CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		sqlMock.ExpectQuery("SELECT").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "url"}).
+				AddRow(1, webhookName, webhookURL))
+
+		req, err := http.NewRequest("GET", "/webhooks/{webhookID}", nil)
+		assert.NoError(t, err)
+
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("webhookID", "1")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+		webhookHandler.GetWebhook()(recorder, req)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		var resp handler.Response
+		err = json.Unmarshal(recorder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+
+		assert.Equal(t, float64(1), resp.Data.(map[string]any)["id"])
+		assert.Equal(t, webhookURL, resp.Data.(map[string]any)["url"])
+	})
+
+	t.Run("CreateWebhook", func(t *testing.T) {
+		sqlMock, fakeGDB, recorder, webhookHandler := setupTest(t)
+		defer persistence.CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		req, err := http.NewRequest("POST", "/webhooks", nil)
+		assert.NoError(t, err)
+
+		requestPayload := request.CreateWebhookRequest{
+			Name:    webhookName,
+			URL:     webhookURL,
+			Secret:  "s3cr3t",
+			Format:  "slack",
+			Events:  []string{"run.failed", "stack.outOfSync"},
+			StackID: 1,
+			Enabled: true,
+		}
+		reqBody, err := json.Marshal(requestPayload)
+		assert.NoError(t, err)
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+		req.Header.Add("Content-Type", "application/json")
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectExec("INSERT").
+			WillReturnResult(sqlmock.NewResult(int64(1), int64(1)))
+		sqlMock.ExpectCommit()
+
+		webhookHandler.CreateWebhook()(recorder, req)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		var resp handler.Response
+		err = json.Unmarshal(recorder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+
+		assert.Equal(t, float64(1), resp.Data.(map[string]any)["id"])
+		assert.Equal(t, "slack", resp.Data.(map[string]any)["format"])
+		assert.Equal(t, "**********", resp.Data.(map[string]any)["secret"])
+	})
+
+	t.Run("CreateWebhookWithInvalidEvent", func(t *testing.T) {
+		sqlMock, fakeGDB, recorder, webhookHandler := setupTest(t)
+		defer persistence.CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		req, err := http.NewRequest("POST", "/webhooks", nil)
+		assert.NoError(t, err)
+
+		requestPayload := request.CreateWebhookRequest{
+			Name:   webhookName,
+			URL:    webhookURL,
+			Events: []string{"run.exploded"},
+		}
+		reqBody, err := json.Marshal(requestPayload)
+		assert.NoError(t, err)
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+		req.Header.Add("Content-Type", "application/json")
+
+		webhookHandler.CreateWebhook()(recorder, req)
+
+		var resp handler.Response
+		err = json.Unmarshal(recorder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+
+		assert.Equal(t, false, resp.Success)
+	})
+
+	t.Run("DeleteNonExistingWebhook", func(t *testing.T) {
+		sqlMock, fakeGDB, recorder, webhookHandler := setupTest(t)
+		defer persistence.CloseDB(t, fakeGDB)
+		defer sqlMock.ExpectClose()
+
+		req, err := http.NewRequest("DELETE", "/webhooks/{webhookID}", nil)
+		assert.NoError(t, err)
+
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("webhookID", "1")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectQuery("SELECT").
+			WillReturnError(gorm.ErrRecordNotFound)
+
+		webhookHandler.DeleteWebhook()(recorder, req)
+
+		var resp handler.Response
+		err = json.Unmarshal(recorder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+
+		assert.Equal(t, false, resp.Success)
+		assert.Equal(t, webhookmanager.ErrGettingNonExistingWebhook.Error(), resp.Message)
+	})
+}
+
+func setupTest(t *testing.T) (sqlmock.Sqlmock, *gorm.DB, *httptest.ResponseRecorder, *Handler) {
+	fakeGDB, sqlMock, err := persistence.GetMockDB()
+	require.NoError(t, err)
+	webhookRepo := persistence.NewWebhookRepository(fakeGDB)
+	webhookDeliveryRepo := persistence.NewWebhookDeliveryRepository(fakeGDB)
+	webhookHandler := &Handler{
+		webhookManager: webhookmanager.NewWebhookManager(webhookRepo, webhookDeliveryRepo, nil),
+	}
+	recorder := httptest.NewRecorder()
+	return sqlMock, fakeGDB, recorder, webhookHandler
+}
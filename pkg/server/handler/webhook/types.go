@@ -0,0 +1,21 @@
+package webhook
+
+import (
+	webhookmanager "kusionstack.io/kusion/pkg/server/manager/webhook"
+)
+
+func NewHandler(
+	webhookManager *webhookmanager.WebhookManager,
+) (*Handler, error) {
+	return &Handler{
+		webhookManager: webhookManager,
+	}, nil
+}
+
+type Handler struct {
+	webhookManager *webhookmanager.WebhookManager
+}
+
+type WebhookRequestParams struct {
+	WebhookID uint
+}
@@ -0,0 +1,216 @@
+package runqueue
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"kusionstack.io/kusion/pkg/domain/constant"
+	"kusionstack.io/kusion/pkg/domain/entity"
+
+	logutil "kusionstack.io/kusion/pkg/server/util/logging"
+)
+
+// Start recovers the runs orphaned by a previous server process and starts
+// the workers executing the pending runs with the given executor.
+func (q *RunQueue) Start(ctx context.Context, executor Executor) {
+	logger := logutil.GetLogger(ctx)
+	logger.Info("Starting run queue...", "owner", q.owner, "concurrency", q.concurrency)
+
+	q.executor = executor
+	q.recover(ctx)
+
+	for i := 0; i < q.concurrency; i++ {
+		q.wg.Add(1)
+		go q.work(ctx)
+	}
+
+	q.wg.Add(1)
+	go q.recoverPeriodically(ctx)
+}
+
+// Notify wakes up the idle workers to claim the newly enqueued runs. The
+// workers also poll the database, so the runs enqueued by other replicas are
+// picked up as well.
+func (q *RunQueue) Notify() {
+	for i := 0; i < q.concurrency; i++ {
+		select {
+		case q.wakeup <- struct{}{}:
+		default:
+			return
+		}
+	}
+}
+
+// Shutdown stops claiming new runs and waits for the runs in progress to
+// complete. The pending runs stay in the database for the next start.
+func (q *RunQueue) Shutdown() {
+	q.stopOnce.Do(func() {
+		close(q.stop)
+	})
+	q.wg.Wait()
+}
+
+func (q *RunQueue) stopped() bool {
+	select {
+	case <-q.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// work claims and executes runs until the queue is drained, then waits for a
+// notification or the next poll.
+func (q *RunQueue) work(ctx context.Context) {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(q.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		q.drain(ctx)
+		select {
+		case <-q.stop:
+			return
+		case <-ctx.Done():
+			return
+		case <-q.wakeup:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (q *RunQueue) drain(ctx context.Context) {
+	logger := logutil.GetLogger(ctx)
+	for !q.stopped() && ctx.Err() == nil {
+		run, err := q.runRepo.Claim(ctx, q.owner, q.leaseDuration)
+		if err != nil {
+			logger.Error("Error claiming run from the run queue", "error", err)
+			return
+		}
+		if run == nil {
+			return
+		}
+		q.execute(ctx, run)
+	}
+}
+
+// execute runs the claimed run while renewing its lease in the background.
+// The execution is not bound to the context of the queue, so that a graceful
+// shutdown lets the runs in progress complete.
+func (q *RunQueue) execute(ctx context.Context, run *entity.Run) {
+	logger := logutil.GetLogger(ctx)
+	logger.Info("Claimed run from the run queue", "runID", run.ID, "type", run.Type, "attempts", run.Attempts)
+
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+
+	renewed := make(chan struct{})
+	go func() {
+		defer close(renewed)
+		q.renew(runCtx, cancel, run.ID)
+	}()
+
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Recovered from panic during run execution", "runID", run.ID, "error", r)
+		}
+		cancel()
+		<-renewed
+		if err := q.runRepo.ReleaseLease(context.WithoutCancel(ctx), run.ID, q.owner); err != nil {
+			logger.Error("Error releasing lease of run", "runID", run.ID, "error", err)
+		}
+	}()
+
+	q.executor.ExecuteRun(runCtx, run)
+}
+
+// renew extends the lease of the run until the context is done. If the lease
+// has been taken over, the execution is cancelled.
+func (q *RunQueue) renew(ctx context.Context, cancel context.CancelFunc, runID uint) {
+	logger := logutil.GetLogger(ctx)
+	ticker := time.NewTicker(q.leaseDuration / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := q.runRepo.RenewLease(ctx, runID, q.owner, q.leaseDuration)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				logger.Error("Lost lease of run, cancelling the execution", "runID", runID)
+				cancel()
+				return
+			} else if err != nil {
+				// Keep executing and retry on the next tick, the lease only
+				// expires if the renewal keeps failing.
+				logger.Warn("Error renewing lease of run", "runID", runID, "error", err)
+			}
+		}
+	}
+}
+
+func (q *RunQueue) recoverPeriodically(ctx context.Context) {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(q.leaseDuration)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-q.stop:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			q.recover(ctx)
+		}
+	}
+}
+
+// recover moves the runs in progress whose lease has expired, which belong to
+// a crashed or restarted server, to failed or back to the queue depending on
+// the recovery policy. A run is never re-queued beyond the maximum attempts.
+func (q *RunQueue) recover(ctx context.Context) {
+	logger := logutil.GetLogger(ctx)
+	now := time.Now()
+	runs, err := q.runRepo.ListExpired(ctx, now)
+	if err != nil {
+		logger.Error("Error listing orphaned runs", "error", err)
+		return
+	}
+
+	requeued := false
+	for _, run := range runs {
+		status, result := constant.RunStatusFailed, constant.RunResultFailed
+		if q.recoveryPolicy == constant.RunRecoveryPolicyRequeue && run.Attempts < q.maxAttempts {
+			status, result = constant.RunStatusQueued, ""
+		}
+
+		recovered, err := q.runRepo.Recover(ctx, run.ID, run.LeaseOwner, now, status, result)
+		if err != nil {
+			logger.Error("Error recovering orphaned run", "runID", run.ID, "error", err)
+			continue
+		}
+		if !recovered {
+			// The run has been renewed or recovered by another replica.
+			continue
+		}
+		logger.Info("Recovered orphaned run", "runID", run.ID, "leaseOwner", run.LeaseOwner, "status", status)
+		requeued = requeued || status == constant.RunStatusQueued
+
+		if q.executor != nil {
+			if updated, err := q.runRepo.Get(ctx, run.ID); err == nil {
+				q.executor.RunRecovered(ctx, updated)
+			}
+		}
+	}
+
+	if requeued {
+		q.Notify()
+	}
+}
@@ -0,0 +1,320 @@
+package runqueue
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"kusionstack.io/kusion/pkg/domain/constant"
+	"kusionstack.io/kusion/pkg/domain/entity"
+	"kusionstack.io/kusion/pkg/domain/repository"
+)
+
+// fakeRunRepository is an in-memory run repository mirroring the lease
+// semantics of the gorm implementation.
+type fakeRunRepository struct {
+	repository.RunRepository
+	mu   sync.Mutex
+	runs map[uint]*entity.Run
+}
+
+func newFakeRunRepository(runs ...*entity.Run) *fakeRunRepository {
+	repo := &fakeRunRepository{runs: make(map[uint]*entity.Run)}
+	for _, run := range runs {
+		repo.runs[run.ID] = run
+	}
+	return repo
+}
+
+func (r *fakeRunRepository) sortedIDs() []uint {
+	ids := make([]uint, 0, len(r.runs))
+	for id := range r.runs {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func (r *fakeRunRepository) Claim(_ context.Context, owner string, leaseDuration time.Duration) (*entity.Run, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	busy := make(map[string]bool)
+	for _, run := range r.runs {
+		if run.Status == constant.RunStatusInProgress {
+			busy[fmt.Sprintf("%d/%s", run.Stack.ID, run.Workspace)] = true
+		}
+	}
+	for _, id := range r.sortedIDs() {
+		run := r.runs[id]
+		if run.Status != constant.RunStatusScheduling && run.Status != constant.RunStatusQueued {
+			continue
+		}
+		key := fmt.Sprintf("%d/%s", run.Stack.ID, run.Workspace)
+		if busy[key] {
+			continue
+		}
+		expiresAt := time.Now().Add(leaseDuration)
+		run.Status = constant.RunStatusInProgress
+		run.LeaseOwner = owner
+		run.LeaseExpiresAt = &expiresAt
+		run.Attempts++
+		claimed := *run
+		return &claimed, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeRunRepository) RenewLease(_ context.Context, id uint, owner string, leaseDuration time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	run, ok := r.runs[id]
+	if !ok || run.LeaseOwner != owner || run.Status != constant.RunStatusInProgress {
+		return gorm.ErrRecordNotFound
+	}
+	expiresAt := time.Now().Add(leaseDuration)
+	run.LeaseExpiresAt = &expiresAt
+	return nil
+}
+
+func (r *fakeRunRepository) ReleaseLease(_ context.Context, id uint, owner string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if run, ok := r.runs[id]; ok && run.LeaseOwner == owner {
+		run.LeaseOwner = ""
+		run.LeaseExpiresAt = nil
+	}
+	return nil
+}
+
+func (r *fakeRunRepository) ListExpired(_ context.Context, now time.Time) ([]*entity.Run, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var expired []*entity.Run
+	for _, id := range r.sortedIDs() {
+		run := r.runs[id]
+		if run.Status == constant.RunStatusInProgress && (run.LeaseExpiresAt == nil || run.LeaseExpiresAt.Before(now)) {
+			copied := *run
+			expired = append(expired, &copied)
+		}
+	}
+	return expired, nil
+}
+
+func (r *fakeRunRepository) Recover(_ context.Context, id uint, owner string, now time.Time, status constant.RunStatus, result string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	run, ok := r.runs[id]
+	if !ok || run.Status != constant.RunStatusInProgress || run.LeaseOwner != owner ||
+		(run.LeaseExpiresAt != nil && !run.LeaseExpiresAt.Before(now)) {
+		return false, nil
+	}
+	run.Status = status
+	run.Result = result
+	run.LeaseOwner = ""
+	run.LeaseExpiresAt = nil
+	return true, nil
+}
+
+func (r *fakeRunRepository) Get(_ context.Context, id uint) (*entity.Run, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	run, ok := r.runs[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *run
+	return &copied, nil
+}
+
+func (r *fakeRunRepository) setStatus(id uint, status constant.RunStatus) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.runs[id].Status = status
+}
+
+func (r *fakeRunRepository) status(id uint) constant.RunStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.runs[id].Status
+}
+
+// fakeExecutor records the executed runs and completes them as succeeded.
+type fakeExecutor struct {
+	repo      *fakeRunRepository
+	delay     time.Duration
+	mu        sync.Mutex
+	executed  []uint
+	recovered []*entity.Run
+	running   map[string]int
+	overlap   bool
+}
+
+func (e *fakeExecutor) ExecuteRun(_ context.Context, run *entity.Run) {
+	key := fmt.Sprintf("%d/%s", run.Stack.ID, run.Workspace)
+	e.mu.Lock()
+	e.running[key]++
+	if e.running[key] > 1 {
+		e.overlap = true
+	}
+	e.mu.Unlock()
+
+	time.Sleep(e.delay)
+
+	e.mu.Lock()
+	e.running[key]--
+	e.executed = append(e.executed, run.ID)
+	e.mu.Unlock()
+	e.repo.setStatus(run.ID, constant.RunStatusSucceeded)
+}
+
+func (e *fakeExecutor) RunRecovered(_ context.Context, run *entity.Run) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.recovered = append(e.recovered, run)
+}
+
+func (e *fakeExecutor) executedRuns() []uint {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]uint(nil), e.executed...)
+}
+
+func newTestRunQueue(repo repository.RunRepository, concurrency int, policy constant.RunRecoveryPolicy) *RunQueue {
+	q := NewRunQueue(repo, concurrency, policy)
+	q.pollInterval = 10 * time.Millisecond
+	q.leaseDuration = 300 * time.Millisecond
+	return q
+}
+
+func newRun(id, stackID uint, workspace string, status constant.RunStatus) *entity.Run {
+	return &entity.Run{
+		ID:        id,
+		Type:      constant.RunTypeApply,
+		Stack:     &entity.Stack{ID: stackID},
+		Workspace: workspace,
+		Status:    status,
+	}
+}
+
+func TestRunQueueExecutesPendingRuns(t *testing.T) {
+	repo := newFakeRunRepository(
+		newRun(1, 1, "dev", constant.RunStatusQueued),
+		newRun(2, 1, "dev", constant.RunStatusQueued),
+		newRun(3, 2, "dev", constant.RunStatusScheduling),
+		newRun(4, 1, "prod", constant.RunStatusQueued),
+	)
+	executor := &fakeExecutor{repo: repo, delay: 20 * time.Millisecond, running: make(map[string]int)}
+	q := newTestRunQueue(repo, 3, constant.RunRecoveryPolicyFail)
+	q.Start(context.Background(), executor)
+	q.Notify()
+
+	require.Eventually(t, func() bool {
+		return len(executor.executedRuns()) == 4
+	}, 5*time.Second, 10*time.Millisecond)
+	q.Shutdown()
+
+	executed := executor.executedRuns()
+	require.False(t, executor.overlap, "runs of the same stack and workspace must not overlap")
+	// The runs of the same stack and workspace keep their submission order.
+	require.Less(t, indexOf(executed, 1), indexOf(executed, 2))
+	for id := uint(1); id <= 4; id++ {
+		require.Equal(t, constant.RunStatusSucceeded, repo.status(id))
+		run, _ := repo.Get(context.Background(), id)
+		require.Empty(t, run.LeaseOwner)
+	}
+}
+
+func TestRunQueueRecoversOrphanedRuns(t *testing.T) {
+	expired := time.Now().Add(-time.Minute)
+
+	t.Run("fail", func(t *testing.T) {
+		orphan := newRun(1, 1, "dev", constant.RunStatusInProgress)
+		orphan.LeaseOwner = "crashed-replica"
+		orphan.LeaseExpiresAt = &expired
+		repo := newFakeRunRepository(orphan)
+		executor := &fakeExecutor{repo: repo, running: make(map[string]int)}
+		q := newTestRunQueue(repo, 1, constant.RunRecoveryPolicyFail)
+		q.Start(context.Background(), executor)
+		q.Shutdown()
+
+		require.Equal(t, constant.RunStatusFailed, repo.status(1))
+		require.Empty(t, executor.executedRuns())
+		require.Len(t, executor.recovered, 1)
+		require.Equal(t, constant.RunStatusFailed, executor.recovered[0].Status)
+	})
+
+	t.Run("requeue", func(t *testing.T) {
+		orphan := newRun(1, 1, "dev", constant.RunStatusInProgress)
+		orphan.LeaseOwner = "crashed-replica"
+		orphan.LeaseExpiresAt = &expired
+		orphan.Attempts = 1
+		exhausted := newRun(2, 2, "dev", constant.RunStatusInProgress)
+		exhausted.Attempts = constant.RunQueueDefaultMaxAttempts
+		repo := newFakeRunRepository(orphan, exhausted)
+		executor := &fakeExecutor{repo: repo, running: make(map[string]int)}
+		q := newTestRunQueue(repo, 1, constant.RunRecoveryPolicyRequeue)
+		q.Start(context.Background(), executor)
+
+		require.Eventually(t, func() bool {
+			return len(executor.executedRuns()) == 1
+		}, 5*time.Second, 10*time.Millisecond)
+		q.Shutdown()
+
+		require.Equal(t, []uint{1}, executor.executedRuns())
+		require.Equal(t, constant.RunStatusSucceeded, repo.status(1))
+		require.Equal(t, constant.RunStatusFailed, repo.status(2))
+	})
+
+	t.Run("active lease is kept", func(t *testing.T) {
+		active := time.Now().Add(time.Minute)
+		run := newRun(1, 1, "dev", constant.RunStatusInProgress)
+		run.LeaseOwner = "other-replica"
+		run.LeaseExpiresAt = &active
+		repo := newFakeRunRepository(run)
+		executor := &fakeExecutor{repo: repo, running: make(map[string]int)}
+		q := newTestRunQueue(repo, 1, constant.RunRecoveryPolicyFail)
+		q.Start(context.Background(), executor)
+		q.Shutdown()
+
+		require.Equal(t, constant.RunStatusInProgress, repo.status(1))
+		require.Empty(t, executor.recovered)
+	})
+}
+
+func TestRunQueueRenewsLease(t *testing.T) {
+	repo := newFakeRunRepository(newRun(1, 1, "dev", constant.RunStatusQueued))
+	// The execution outlasts several lease durations.
+	executor := &fakeExecutor{repo: repo, delay: time.Second, running: make(map[string]int)}
+	q := newTestRunQueue(repo, 1, constant.RunRecoveryPolicyFail)
+	q.Start(context.Background(), executor)
+	q.Notify()
+
+	require.Eventually(t, func() bool {
+		return len(executor.executedRuns()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	q.Shutdown()
+
+	require.Empty(t, executor.recovered)
+	require.Equal(t, constant.RunStatusSucceeded, repo.status(1))
+}
+
+func indexOf(ids []uint, id uint) int {
+	for i, v := range ids {
+		if v == id {
+			return i
+		}
+	}
+	return -1
+}
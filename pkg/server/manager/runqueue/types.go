@@ -0,0 +1,83 @@
+package runqueue
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"kusionstack.io/kusion/pkg/domain/constant"
+	"kusionstack.io/kusion/pkg/domain/entity"
+	"kusionstack.io/kusion/pkg/domain/repository"
+)
+
+// Executor executes the runs claimed by the run queue.
+type Executor interface {
+	// ExecuteRun executes a claimed run until it reaches a terminal status.
+	// The context is cancelled if the lease of the run is lost.
+	ExecuteRun(ctx context.Context, run *entity.Run)
+	// RunRecovered is called after an orphaned run has been marked as failed
+	// or re-queued by the recovery.
+	RunRecovered(ctx context.Context, run *entity.Run)
+}
+
+// RunQueue executes the async runs persisted in the database. Workers claim
+// pending runs with a lease that is renewed while the run executes, so that
+// the runs of a server that crashed or restarted are recovered by any replica
+// once the lease expires.
+type RunQueue struct {
+	runRepo        repository.RunRepository
+	owner          string
+	concurrency    int
+	leaseDuration  time.Duration
+	pollInterval   time.Duration
+	maxAttempts    int
+	recoveryPolicy constant.RunRecoveryPolicy
+
+	executor Executor
+	wakeup   chan struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+func NewRunQueue(
+	runRepo repository.RunRepository,
+	concurrency int,
+	recoveryPolicy constant.RunRecoveryPolicy,
+) *RunQueue {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if recoveryPolicy == "" {
+		recoveryPolicy = constant.RunQueueDefaultRecoveryPolicy
+	}
+	return &RunQueue{
+		runRepo:        runRepo,
+		owner:          newOwner(),
+		concurrency:    concurrency,
+		leaseDuration:  constant.RunQueueDefaultLeaseDuration,
+		pollInterval:   constant.RunQueueDefaultPollInterval,
+		maxAttempts:    constant.RunQueueDefaultMaxAttempts,
+		recoveryPolicy: recoveryPolicy,
+		wakeup:         make(chan struct{}, concurrency),
+		stop:           make(chan struct{}),
+	}
+}
+
+// Owner returns the identity the queue uses to lease runs.
+func (q *RunQueue) Owner() string {
+	return q.owner
+}
+
+// newOwner returns an identity unique to this server process.
+func newOwner() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "kusion"
+	}
+	return fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), uuid.NewString()[:8])
+}
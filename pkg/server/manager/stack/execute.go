@@ -3,7 +3,8 @@ package stack
 import (
 	"context"
 	"errors"
-	"net/http"
+	"fmt"
+	"io"
 	"os"
 	"sync"
 	"time"
@@ -11,17 +12,19 @@ import (
 	"gorm.io/gorm"
 
 	apiv1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/backend"
 	"kusionstack.io/kusion/pkg/domain/constant"
 	"kusionstack.io/kusion/pkg/domain/request"
 	"kusionstack.io/kusion/pkg/engine/release"
-	"kusionstack.io/kusion/pkg/engine/resource/graph"
 
 	engineapi "kusionstack.io/kusion/pkg/engine/api"
 	sourceapi "kusionstack.io/kusion/pkg/engine/api/source"
 	"kusionstack.io/kusion/pkg/engine/operation/models"
+	"kusionstack.io/kusion/pkg/engine/plan"
 
 	appmiddleware "kusionstack.io/kusion/pkg/server/middleware"
 	logutil "kusionstack.io/kusion/pkg/server/util/logging"
+	"kusionstack.io/kusion/pkg/util/mask"
 )
 
 func (m *StackManager) GenerateSpec(ctx context.Context, params *StackRequestParams) (string, *apiv1.Spec, error) {
@@ -101,8 +104,15 @@ func (m *StackManager) GenerateSpec(ctx context.Context, params *StackRequestPar
 		return "", nil, err
 	}
 
+	// Inject the variables of the matched variable sets, the generated spec is
+	// returned without the secret environment variables
+	arguments, _, err := m.prepareVariables(ctx, params, stackEntity, ws)
+	if err != nil {
+		return "", nil, err
+	}
+
 	// Generate spec
-	sp, err := engineapi.GenerateSpecWithSpinner(project, stack, ws, true)
+	sp, err := engineapi.GenerateSpecWithSpinner(project, stack, ws, arguments, true)
 	return "", sp, err
 }
 
@@ -192,8 +202,14 @@ func (m *StackManager) PreviewStack(ctx context.Context, params *StackRequestPar
 		}
 	}()
 
+	// Inject the variables of the matched variable sets
+	arguments, secretEnvs, err := m.prepareVariables(ctx, params, stackEntity, ws)
+	if err != nil {
+		return nil, err
+	}
+
 	// Generate spec using default generator
-	sp, err = engineapi.GenerateSpecWithSpinner(project, stack, ws, true)
+	sp, err = engineapi.GenerateSpecWithSpinner(project, stack, ws, arguments, true)
 	if err != nil {
 		return nil, err
 	}
@@ -203,6 +219,7 @@ func (m *StackManager) PreviewStack(ctx context.Context, params *StackRequestPar
 		logutil.LogToAll(logger, runLogger, "Warn", "Generated spec is nil, treating as empty spec...")
 		sp = &apiv1.Spec{}
 	}
+	sp.SecretEnvs = secretEnvs
 	if len(sp.Resources) == 0 {
 		logutil.LogToAll(logger, runLogger, "Info", "No resources found in spec. Proceeding with full diff.")
 	}
@@ -226,10 +243,23 @@ func (m *StackManager) PreviewStack(ctx context.Context, params *StackRequestPar
 	if params.ExecuteParams.ImportResources && len(requestPayload.ImportedResources) > 0 {
 		m.ImportTerraformResourceID(ctx, sp, requestPayload.ImportedResources)
 	}
-	logutil.LogToAll(logger, runLogger, "Info", "Final Spec is: ", "spec", sp)
+	maskedSpec, err := MaskSpec(sp)
+	if err != nil {
+		return nil, err
+	}
+	logutil.LogToAll(logger, runLogger, "Info", "Final Spec is: ", "spec", maskedSpec)
 
 	changes, err := engineapi.Preview(executeOptions, releaseStorage, sp, state, project, stack)
-	return changes, err
+	if err != nil {
+		return nil, err
+	}
+	// The changes are returned by the APIs, so mask the sensitive data following the rules of the workspace.
+	policy, err := mask.PolicyFromContext(sp.Context)
+	if err != nil {
+		return nil, err
+	}
+	maskChanges(policy, changes)
+	return changes, nil
 }
 
 func (m *StackManager) ApplyStack(ctx context.Context, params *StackRequestParams, requestPayload request.StackImportRequest) error {
@@ -267,9 +297,11 @@ func (m *StackManager) ApplyStack(ctx context.Context, params *StackRequestParam
 	}
 
 	var storage release.Storage
+	var stack *apiv1.Stack
 	rel := &apiv1.Release{}
 	relLock := &sync.Mutex{}
 	releaseCreated := false
+	applyStarted := false
 	// Ensure the state is updated properly
 	defer func() {
 		if err != nil {
@@ -280,6 +312,13 @@ func (m *StackManager) ApplyStack(ctx context.Context, params *StackRequestParam
 			}
 			release.UpdateReleasePhase(rel, apiv1.ReleasePhaseFailed, relLock)
 			_ = release.UpdateApplyRelease(storage, rel, params.ExecuteParams.Dryrun, relLock)
+			// Roll back the resources changed by the failed release, which must go on even
+			// if the failure is caused by the cancellation of the run.
+			if params.ExecuteParams.Atomic && applyStarted && !params.ExecuteParams.Dryrun {
+				if rollbackErr := m.rollbackRelease(context.WithoutCancel(ctx), storage, rel, project, stack, stackBackend); rollbackErr != nil {
+					logutil.LogToAll(logger, runLogger, "Error", rollbackErr.Error())
+				}
+			}
 		} else {
 			release.UpdateReleasePhase(rel, apiv1.ReleasePhaseSucceeded, relLock)
 			err = release.UpdateApplyRelease(storage, rel, params.ExecuteParams.Dryrun, relLock)
@@ -340,6 +379,18 @@ func (m *StackManager) ApplyStack(ctx context.Context, params *StackRequestParam
 	if priorState == nil {
 		priorState = &apiv1.State{}
 	}
+	// Refuse to apply the plan if a release has been created since the preview
+	var p *plan.Plan
+	if len(requestPayload.Plan) > 0 {
+		p, err = plan.Decode(requestPayload.Plan, plan.SigningKey())
+		if err != nil {
+			return err
+		}
+		if err = p.CheckBase(project.Name, stackEntity.Name, ws.Name, storage.GetLatestRevision()); err != nil {
+			return err
+		}
+		logutil.LogToAll(logger, runLogger, "Info", "Applying the plan", "baseRevision", p.BaseRevision)
+	}
 	// Create new release
 	rel, err = release.NewApplyRelease(storage, project.Name, stackEntity.Name, ws.Name)
 	if err != nil {
@@ -361,25 +412,48 @@ func (m *StackManager) ApplyStack(ctx context.Context, params *StackRequestParam
 	}
 	executeOptions := BuildOptions(params.ExecuteParams.Dryrun, m.maxConcurrent)
 
-	logutil.LogToAll(logger, runLogger, "Info", "Previewing using the default generator ...")
-
-	directory, workDir, err := m.GetWorkdirAndDirectory(ctx, params, stackEntity)
+	// Inject the variables of the matched variable sets, on both the plan and
+	// the generate path, so the workspace context and the secret environment
+	// variables of the spec are the same either way
+	arguments, secretEnvs, err := m.prepareVariables(ctx, params, stackEntity, ws)
 	if err != nil {
 		return err
 	}
-	stack.Path = workDir
 
-	// Cleanup
-	defer func() {
-		if params.ExecuteParams.NoCache {
-			sourceapi.Cleanup(ctx, directory)
+	// The work directory the stack is checked out to, which is empty when
+	// applying a plan
+	var workDir string
+	if p != nil {
+		// Use the spec in the plan as is
+		sp = p.Spec
+		if sp != nil {
+			sp.SecretEnvs = secretEnvs
 		}
-	}()
+	} else {
+		logutil.LogToAll(logger, runLogger, "Info", "Previewing using the default generator ...")
 
-	// Generate spec using default generator
-	sp, err = engineapi.GenerateSpecWithSpinner(project, stack, ws, true)
-	if err != nil {
-		return err
+		var directory string
+		directory, workDir, err = m.GetWorkdirAndDirectory(ctx, params, stackEntity)
+		if err != nil {
+			return err
+		}
+		stack.Path = workDir
+
+		// Cleanup
+		defer func() {
+			if params.ExecuteParams.NoCache {
+				sourceapi.Cleanup(ctx, directory)
+			}
+		}()
+
+		// Generate spec using default generator
+		sp, err = engineapi.GenerateSpecWithSpinner(project, stack, ws, arguments, true)
+		if err != nil {
+			return err
+		}
+		if sp != nil {
+			sp.SecretEnvs = secretEnvs
+		}
 	}
 
 	// return immediately if no resource found in stack
@@ -391,6 +465,7 @@ func (m *StackManager) ApplyStack(ctx context.Context, params *StackRequestParam
 
 	// update release phase to previewing
 	rel.Spec = sp
+	rel.Provenance = newReleaseProvenance(params, stack, workDir, sp)
 	release.UpdateReleasePhase(rel, apiv1.ReleasePhasePreviewing, relLock)
 	if err = release.UpdateApplyRelease(storage, rel, params.ExecuteParams.Dryrun, relLock); err != nil {
 		return err
@@ -425,7 +500,15 @@ func (m *StackManager) ApplyStack(ctx context.Context, params *StackRequestParam
 		return err
 	}
 
+	// Refuse to apply the plan if the live state has changed since the preview
+	if p != nil {
+		if err = p.CheckChanges(changes.ChangeOrder); err != nil {
+			return err
+		}
+	}
+
 	logutil.LogToAll(logger, runLogger, "Info", "Start applying diffs ...")
+	rel.Actions = changes.Actions()
 	release.UpdateReleasePhase(rel, apiv1.ReleasePhaseApplying, relLock)
 	if err = release.UpdateApplyRelease(storage, rel, params.ExecuteParams.Dryrun, relLock); err != nil {
 		return err
@@ -434,39 +517,16 @@ func (m *StackManager) ApplyStack(ctx context.Context, params *StackRequestParam
 	executeOptions = BuildOptions(params.ExecuteParams.Dryrun, m.maxConcurrent)
 	executeOptions.Watch = params.ExecuteParams.Watch
 	executeOptions.WatchTimeout = params.ExecuteParams.WatchTimeoutSeconds
+	executeOptions.Atomic = params.ExecuteParams.Atomic
+	executeOptions.ContinueOnError = params.ExecuteParams.ContinueOnError
 
-	// Get graph storage directory, create if not exist
-	graphStorage, err := stackBackend.GraphStorage(project.Name, ws.Name)
-	if err != nil {
-		return err
-	}
-
-	// Try to get existing graph, use the graph if exists
-	var gph *apiv1.Graph
-	if graphStorage.CheckGraphStorageExistence() {
-		gph, err = graphStorage.Get()
-		if err != nil {
-			return err
-		}
-		err = graph.ValidateGraph(gph)
-		if err != nil {
-			return err
-		}
-		// Put new resources from the generated spec to graph
-		gph, err = graph.GenerateGraph(sp.Resources, gph)
-	} else {
-		// Create a new graph to be used globally if no graph is stored in the storage
-		gph = &apiv1.Graph{
-			Project:   project.Name,
-			Workspace: ws.Name,
-		}
-		gph, err = graph.GenerateGraph(sp.Resources, gph)
-	}
+	gph, err := prepareGraph(stackBackend, project.Name, ws.Name, sp)
 	if err != nil {
 		return err
 	}
 
 	var upRel *apiv1.Release
+	applyStarted = true
 	if upRel, err = engineapi.Apply(ctx, executeOptions, storage, rel, gph, changes, os.Stdout); err != nil {
 		return err
 	}
@@ -484,7 +544,62 @@ func (m *StackManager) ApplyStack(ctx context.Context, params *StackRequestParam
 	return nil
 }
 
-func (m *StackManager) DestroyStack(ctx context.Context, params *StackRequestParams, w http.ResponseWriter) error {
+// rollbackRelease rolls back the resources changed by the failed release to the
+// state of the release before it, which is recorded as a new release.
+func (m *StackManager) rollbackRelease(
+	ctx context.Context,
+	storage release.Storage,
+	failed *apiv1.Release,
+	project *apiv1.Project,
+	stack *apiv1.Stack,
+	stackBackend backend.Backend,
+) (err error) {
+	logger := logutil.GetLogger(ctx)
+	runLogger := logutil.GetRunLogger(ctx)
+	logutil.LogToAll(logger, runLogger, "Info", "Rolling back the failed release ...", "revision", failed.Revision)
+
+	rollbackRel, err := release.CreateRollbackRelease(storage, failed)
+	if err != nil {
+		return fmt.Errorf("failed to roll back release %d: %w", failed.Revision, err)
+	}
+	relLock := &sync.Mutex{}
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("failed to roll back release %d: %w", failed.Revision, err)
+			release.UpdateReleasePhase(rollbackRel, apiv1.ReleasePhaseFailed, relLock)
+		} else {
+			release.UpdateReleasePhase(rollbackRel, apiv1.ReleasePhaseSucceeded, relLock)
+		}
+		err = errors.Join(err, release.UpdateApplyRelease(storage, rollbackRel, false, relLock))
+	}()
+
+	// Compute the changes back to the prior state
+	executeOptions := BuildOptions(false, m.maxConcurrent)
+	changes, err := engineapi.Preview(executeOptions, storage, rollbackRel.Spec, rollbackRel.State, project, stack)
+	if err != nil {
+		return err
+	}
+
+	rollbackRel.Actions = changes.Actions()
+	release.UpdateReleasePhase(rollbackRel, apiv1.ReleasePhaseApplying, relLock)
+	if err = release.UpdateApplyRelease(storage, rollbackRel, false, relLock); err != nil {
+		return err
+	}
+	gph, err := prepareGraph(stackBackend, project.Name, failed.Workspace, rollbackRel.Spec)
+	if err != nil {
+		return err
+	}
+
+	// The resources are not watched when rolling back, since there is nothing
+	// to fall back to anyway.
+	if _, err = engineapi.Apply(ctx, executeOptions, storage, rollbackRel, gph, changes, os.Stdout); err != nil {
+		return err
+	}
+	logutil.LogToAll(logger, runLogger, "Info", "Rolled back the failed release", "revision", failed.Revision, "rollbackRevision", rollbackRel.Revision)
+	return nil
+}
+
+func (m *StackManager) DestroyStack(ctx context.Context, params *StackRequestParams, w io.Writer) error {
 	logger := logutil.GetLogger(ctx)
 	runLogger := logutil.GetRunLogger(ctx)
 	logutil.LogToAll(logger, runLogger, "Info", "Starting destroying stack in StackManager ...")
@@ -571,8 +686,26 @@ func (m *StackManager) DestroyStack(ctx context.Context, params *StackRequestPar
 	if len(rel.Spec.Resources) == 0 {
 		return ErrNoManagedResourceToDestroy
 	}
+	// The stack is not checked out to destroy
+	rel.Provenance = newReleaseProvenance(params, stack, "", rel.Spec)
 	releaseCreated = true
 
+	// Inject the current variables of the matched variable sets into the
+	// context of the applied spec, which may hold outdated credentials
+	var secretEnvs map[string]string
+	if _, secretEnvs, err = m.prepareVariables(ctx, params, stackEntity, ws); err != nil {
+		return err
+	}
+	rel.Spec.SecretEnvs = secretEnvs
+	if len(ws.Context) > 0 {
+		if rel.Spec.Context == nil {
+			rel.Spec.Context = apiv1.GenericConfig{}
+		}
+		for k, v := range ws.Context {
+			rel.Spec.Context[k] = v
+		}
+	}
+
 	executeOptions := BuildOptions(params.ExecuteParams.Dryrun, m.maxConcurrent)
 	stack.Path = tempPath(stackEntity.Path)
 
@@ -596,6 +729,7 @@ func (m *StackManager) DestroyStack(ctx context.Context, params *StackRequestPar
 	}
 
 	// update release phase to destroying
+	rel.Actions = changes.Actions()
 	rel.Phase = apiv1.ReleasePhaseDestroying
 	if err = release.UpdateDestroyRelease(storage, rel); err != nil {
 		return err
@@ -0,0 +1,73 @@
+package stack
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"kusionstack.io/kusion/pkg/domain/constant"
+	"kusionstack.io/kusion/pkg/engine/release"
+	logutil "kusionstack.io/kusion/pkg/server/util/logging"
+)
+
+// DiffReleases compares the spec and state of the resources selected by the filter between the from and to
+// releases of the stack in the workspace. A zero to revision means the latest release of the stack, and a
+// zero from revision means the release of the stack before the to release. The releases of the other stacks
+// of the project in the workspace cannot be compared.
+func (m *StackManager) DiffReleases(
+	ctx context.Context,
+	params *StackRequestParams,
+	from, to uint64,
+	filter *release.DiffFilter,
+) (*release.ReleaseDiff, error) {
+	logger := logutil.GetLogger(ctx)
+	logger.Info("Comparing releases of the stack in StackManager ...")
+
+	if params.Workspace == "" {
+		return nil, ErrWorkspaceEmpty
+	}
+	stackEntity, err := m.stackRepo.Get(ctx, params.StackID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGettingNonExistingStack
+		}
+		return nil, err
+	}
+	stackBackend, err := m.getBackendFromWorkspaceName(ctx, params.Workspace)
+	if err != nil {
+		return nil, err
+	}
+	releasePath := getReleasePath(constant.DefaultReleaseNamespace, stackEntity.Project.Source.Name, stackEntity.Project.Path, params.Workspace)
+	storage, err := stackBackend.StateStorageWithPath(releasePath)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := storage.GetStackBoundRevisions(stackEntity.Name)
+	if to == 0 && len(revisions) > 0 {
+		to = revisions[len(revisions)-1]
+	}
+	if from == 0 {
+		for _, revision := range revisions {
+			if revision < to {
+				from = revision
+			}
+		}
+	}
+	if from == 0 || to == 0 {
+		return nil, ErrNoReleasesToDiff
+	}
+	fromRelease, err := storage.Get(from)
+	if err != nil {
+		return nil, err
+	}
+	toRelease, err := storage.Get(to)
+	if err != nil {
+		return nil, err
+	}
+	if fromRelease.Stack != stackEntity.Name || toRelease.Stack != stackEntity.Name {
+		return nil, ErrReleaseOfOtherStack
+	}
+	return release.Diff(fromRelease, toRelease, filter)
+}
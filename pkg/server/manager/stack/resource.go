@@ -7,6 +7,7 @@ import (
 	"kusionstack.io/kusion/pkg/domain/constant"
 	"kusionstack.io/kusion/pkg/domain/entity"
 	logutil "kusionstack.io/kusion/pkg/server/util/logging"
+	"kusionstack.io/kusion/pkg/util/mask"
 )
 
 func (m *StackManager) WriteResources(ctx context.Context, release *v1.Release, stack *entity.Stack, workspace, specID string) error {
@@ -15,7 +16,15 @@ func (m *StackManager) WriteResources(ctx context.Context, release *v1.Release,
 	resourceEntitiesToInsert := []*entity.Resource{}
 
 	if release.State != nil {
-		for _, resource := range release.State.Resources {
+		// The resources are only read by the APIs, so the sensitive data is masked before written.
+		policy := mask.Default()
+		if release.Spec != nil {
+			var err error
+			if policy, err = mask.PolicyFromContext(release.Spec.Context); err != nil {
+				return err
+			}
+		}
+		for _, resource := range policy.MaskResources(release.State.Resources) {
 			resourceEntity, err := convertV1ResourceToEntity(&resource)
 			if err != nil {
 				return err
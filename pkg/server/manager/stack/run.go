@@ -2,6 +2,7 @@ package stack
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -77,6 +78,36 @@ func (m *StackManager) UpdateRunByID(ctx context.Context, id uint, requestPayloa
 }
 
 func (m *StackManager) CreateRun(ctx context.Context, requestPayload request.CreateRunRequest) (*entity.Run, error) {
+	// The default status is InProgress
+	return m.createRun(ctx, requestPayload, constant.RunStatusInProgress, "")
+}
+
+// EnqueueRun creates a queued run with its parameters persisted, to be claimed
+// and executed by the run queue.
+func (m *StackManager) EnqueueRun(ctx context.Context, requestPayload request.CreateRunRequest, params *StackRequestParams) (*entity.Run, error) {
+	parameters, err := json.Marshal(RunParameters{
+		Params:            *params,
+		ImportedResources: requestPayload.ImportedResources,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m.createRun(ctx, requestPayload, constant.RunStatusQueued, string(parameters))
+}
+
+// ParseRunParameters decodes the parameters persisted by EnqueueRun.
+func ParseRunParameters(run *entity.Run) (*RunParameters, error) {
+	if run.Parameters == "" {
+		return nil, ErrRunParametersEmpty
+	}
+	var parameters RunParameters
+	if err := json.Unmarshal([]byte(run.Parameters), &parameters); err != nil {
+		return nil, err
+	}
+	return &parameters, nil
+}
+
+func (m *StackManager) createRun(ctx context.Context, requestPayload request.CreateRunRequest, status constant.RunStatus, parameters string) (*entity.Run, error) {
 	logger := logutil.GetLogger(ctx)
 	// Convert request payload to domain model
 	var createdEntity entity.Run
@@ -98,8 +129,8 @@ func (m *StackManager) CreateRun(ctx context.Context, requestPayload request.Cre
 
 	logger.Info("Creating new run for stack and workspace", "stack", fmt.Sprint(createdEntity.Stack.ID), "workspace", createdEntity.Workspace)
 
-	// The default status is InProgress
-	createdEntity.Status = constant.RunStatusInProgress
+	createdEntity.Status = status
+	createdEntity.Parameters = parameters
 	// Inject trace into run metadata
 	traceID := appmiddleware.GetTraceID(ctx)
 	createdEntity.Trace = traceID
@@ -113,6 +144,21 @@ func (m *StackManager) CreateRun(ctx context.Context, requestPayload request.Cre
 	return &createdEntity, nil
 }
 
+// UpdateLeasedRunResultAndStatus updates the result and status of a run
+// claimed by the run queue. It returns nil if the lease of the run has been
+// lost, since the run then belongs to the replica recovering or claiming it.
+func (m *StackManager) UpdateLeasedRunResultAndStatus(ctx context.Context, run *entity.Run, requestPayload request.UpdateRunResultRequest) (*entity.Run, error) {
+	status, err := constant.ParseRunStatus(requestPayload.Status)
+	if err != nil {
+		return nil, err
+	}
+	finished, err := m.runRepo.Finish(ctx, run.ID, run.LeaseOwner, status, requestPayload.Result, requestPayload.Logs)
+	if err != nil || !finished {
+		return nil, err
+	}
+	return m.runRepo.Get(ctx, run.ID)
+}
+
 func (m *StackManager) UpdateRunResultAndStatusByID(ctx context.Context, id uint, requestPayload request.UpdateRunResultRequest) (*entity.Run, error) {
 	// Convert request payload to domain model
 	var requestEntity entity.Run
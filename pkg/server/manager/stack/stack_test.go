@@ -540,16 +540,20 @@ func TestNewStackManager(t *testing.T) {
 	workspaceRepo := &mockWorkspaceRepository{}
 	resourceRepo := persistence.NewResourceRepository(fakeGDB)
 	runRepo := persistence.NewRunRepository(fakeGDB)
+	variableSetRepo := persistence.NewVariableSetRepository(fakeGDB)
+	variableRepo := persistence.NewVariableRepository(fakeGDB)
 	defaultBackend := entity.Backend{}
 	maxConcurrent := 10
 
-	manager := NewStackManager(stackRepo, projectRepo, workspaceRepo, resourceRepo, runRepo, defaultBackend, maxConcurrent)
+	manager := NewStackManager(stackRepo, projectRepo, workspaceRepo, resourceRepo, runRepo, variableSetRepo, variableRepo, nil, defaultBackend, maxConcurrent)
 
 	assert.NotNil(t, manager)
 	assert.Equal(t, stackRepo, manager.stackRepo)
 	assert.Equal(t, projectRepo, manager.projectRepo)
 	assert.Equal(t, workspaceRepo, manager.workspaceRepo)
 	assert.Equal(t, resourceRepo, manager.resourceRepo)
+	assert.Equal(t, variableSetRepo, manager.variableSetRepo)
+	assert.Equal(t, variableRepo, manager.variableRepo)
 	assert.Equal(t, defaultBackend, manager.defaultBackend)
 	assert.Equal(t, maxConcurrent, manager.maxConcurrent)
 }
@@ -6,6 +6,8 @@ import (
 	"kusionstack.io/kusion/pkg/domain/constant"
 	"kusionstack.io/kusion/pkg/domain/entity"
 	"kusionstack.io/kusion/pkg/domain/repository"
+	"kusionstack.io/kusion/pkg/domain/request"
+	"kusionstack.io/kusion/pkg/infra/encryption"
 	cache "kusionstack.io/kusion/pkg/server/util/cache"
 )
 
@@ -29,18 +31,25 @@ var (
 	ErrInvalidWatchTimeout                       = errors.New("watchTimeout should be a number")
 	ErrWorkspaceEmpty                            = errors.New("workspace should not be empty in query")
 	ErrRunRequestBodyEmpty                       = errors.New("run request body should not be empty")
+	ErrRunParametersEmpty                        = errors.New("the run has no parameters to execute with")
 	ErrRunCrashed                                = errors.New("run crashed")
+	ErrVariableDecryptionDisabled                = errors.New("no key provider is configured to decrypt CipherText variables")
+	ErrNoReleasesToDiff                          = errors.New("no two releases to compare found for the stack")
+	ErrReleaseOfOtherStack                       = errors.New("the release belongs to another stack")
 )
 
 type StackManager struct {
-	stackRepo      repository.StackRepository
-	projectRepo    repository.ProjectRepository
-	workspaceRepo  repository.WorkspaceRepository
-	resourceRepo   repository.ResourceRepository
-	runRepo        repository.RunRepository
-	defaultBackend entity.Backend
-	maxConcurrent  int
-	repoCache      *cache.Cache[uint, *StackCache]
+	stackRepo       repository.StackRepository
+	projectRepo     repository.ProjectRepository
+	workspaceRepo   repository.WorkspaceRepository
+	resourceRepo    repository.ResourceRepository
+	runRepo         repository.RunRepository
+	variableSetRepo repository.VariableSetRepository
+	variableRepo    repository.VariableRepository
+	encrypter       *encryption.Encrypter
+	defaultBackend  entity.Backend
+	maxConcurrent   int
+	repoCache       *cache.Cache[uint, *StackCache]
 }
 
 type StackCache struct {
@@ -54,6 +63,9 @@ type StackRequestParams struct {
 	Format        string
 	Operator      string
 	ExecuteParams StackExecuteParams
+	// RunID is the run being executed, if any, which records the versions of
+	// the variable sets injected into the execution.
+	RunID uint `json:"-"`
 }
 
 type StackExecuteParams struct {
@@ -66,6 +78,17 @@ type StackExecuteParams struct {
 	Unlock              bool
 	Watch               bool
 	WatchTimeoutSeconds int
+	Atomic              bool
+	ContinueOnError     bool
+	// Notes are the free-form change notes recorded in the release provenance.
+	Notes string
+}
+
+// RunParameters is the input of an async run persisted along with the run, so
+// that the run can be executed by any server replica once it is claimed.
+type RunParameters struct {
+	Params            StackRequestParams         `json:"params"`
+	ImportedResources request.StackImportRequest `json:"importedResources"`
 }
 
 type RunRequestParams struct {
@@ -77,17 +100,23 @@ func NewStackManager(stackRepo repository.StackRepository,
 	workspaceRepo repository.WorkspaceRepository,
 	resourceRepo repository.ResourceRepository,
 	runRepo repository.RunRepository,
+	variableSetRepo repository.VariableSetRepository,
+	variableRepo repository.VariableRepository,
+	encrypter *encryption.Encrypter,
 	defaultBackend entity.Backend,
 	maxConcurrent int,
 ) *StackManager {
 	return &StackManager{
-		stackRepo:      stackRepo,
-		projectRepo:    projectRepo,
-		workspaceRepo:  workspaceRepo,
-		resourceRepo:   resourceRepo,
-		runRepo:        runRepo,
-		defaultBackend: defaultBackend,
-		maxConcurrent:  maxConcurrent,
-		repoCache:      cache.NewCache[uint, *StackCache](constant.RepoCacheTTL),
+		stackRepo:       stackRepo,
+		projectRepo:     projectRepo,
+		workspaceRepo:   workspaceRepo,
+		resourceRepo:    resourceRepo,
+		runRepo:         runRepo,
+		variableSetRepo: variableSetRepo,
+		variableRepo:    variableRepo,
+		encrypter:       encrypter,
+		defaultBackend:  defaultBackend,
+		maxConcurrent:   maxConcurrent,
+		repoCache:       cache.NewCache[uint, *StackCache](constant.RepoCacheTTL),
 	}
 }
@@ -4,7 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"net/http"
+	"io"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -23,10 +23,13 @@ import (
 	sourceapi "kusionstack.io/kusion/pkg/engine/api/source"
 	"kusionstack.io/kusion/pkg/engine/operation/models"
 	"kusionstack.io/kusion/pkg/engine/release"
+	"kusionstack.io/kusion/pkg/engine/resource/graph"
 	"kusionstack.io/kusion/pkg/engine/runtime/terraform/tfops"
 	workspacemanager "kusionstack.io/kusion/pkg/server/manager/workspace"
 	logutil "kusionstack.io/kusion/pkg/server/util/logging"
 	"kusionstack.io/kusion/pkg/util/diff"
+	"kusionstack.io/kusion/pkg/util/mask"
+	"kusionstack.io/kusion/pkg/version"
 )
 
 func BuildOptions(dryrun bool, maxConcurrent int) *engineapi.APIOptions {
@@ -104,7 +107,7 @@ func (m *StackManager) GetWorkdirAndDirectory(ctx context.Context, params *Stack
 	return directory, workDir, nil
 }
 
-func ProcessChanges(ctx context.Context, w http.ResponseWriter, changes *models.Changes, format string, detail bool) (any, error) {
+func ProcessChanges(ctx context.Context, w io.Writer, changes *models.Changes, format string, detail bool) (any, error) {
 	logger := logutil.GetLogger(ctx)
 	logger.Info("Starting previewing stack in StackManager ...")
 
@@ -133,6 +136,32 @@ func ProcessChanges(ctx context.Context, w http.ResponseWriter, changes *models.
 	return "", nil
 }
 
+// MaskSpec returns a copy of the spec with the sensitive data in the resources and the context masked following
+// the rules of its workspace, to be logged or returned by the APIs.
+func MaskSpec(sp *v1.Spec) (*v1.Spec, error) {
+	if sp == nil {
+		return nil, nil
+	}
+	policy, err := mask.PolicyFromContext(sp.Context)
+	if err != nil {
+		return nil, err
+	}
+	masked := *sp
+	masked.Resources = policy.MaskResources(sp.Resources)
+	masked.Context = policy.MaskContext(sp.Context)
+	return &masked, nil
+}
+
+// maskChanges masks the sensitive data in the change steps in place.
+func maskChanges(policy *mask.Policy, changes *models.Changes) {
+	if changes == nil || changes.ChangeOrder == nil {
+		return
+	}
+	for _, v := range changes.ChangeSteps {
+		v.From, v.To = policy.Mask(v.From, v.To)
+	}
+}
+
 func (m *StackManager) getBackendFromWorkspaceName(ctx context.Context, workspaceName string) (backend.Backend, error) {
 	logger := logutil.GetLogger(ctx)
 	logger.Info("Getting backend based on workspace name...")
@@ -549,6 +578,20 @@ func unlockRelease(ctx context.Context, storage release.Storage) error {
 	return nil
 }
 
+// newReleaseProvenance returns the provenance metadata of the release executed with the request params. The
+// git and module versions are read from the workDir the stack is checked out to, and left empty if the stack
+// is not checked out, such as when applying a plan or destroying, since the path of the stack is relative to
+// the source then.
+func newReleaseProvenance(params *StackRequestParams, stack *v1.Stack, workDir string, sp *v1.Spec) *v1.ReleaseProvenance {
+	var checkedOut *v1.Stack
+	if workDir != "" {
+		checkedOut = &v1.Stack{Name: stack.Name, Path: workDir}
+	}
+	p := release.NewProvenance(version.ReleaseVersion(), checkedOut, sp, params.Operator, params.ExecuteParams.Notes)
+	p.RunID = params.RunID
+	return p
+}
+
 func validateExecuteRequestParams(params *StackRequestParams) error {
 	if params.Workspace == "" {
 		return ErrWorkspaceEmpty
@@ -571,3 +614,31 @@ func validateSortOptions(sortBy string) (string, error) {
 	}
 	return sortBy, nil
 }
+
+// prepareGraph gets the graph of the resources from the graph storage, or
+// creates a new one if not exists, and puts the resources of the spec into it.
+func prepareGraph(stackBackend backend.Backend, project, workspace string, sp *v1.Spec) (*v1.Graph, error) {
+	// Get graph storage directory, create if not exist
+	graphStorage, err := stackBackend.GraphStorage(project, workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	// Try to get existing graph, use the graph if exists
+	if graphStorage.CheckGraphStorageExistence() {
+		gph, err := graphStorage.Get()
+		if err != nil {
+			return nil, err
+		}
+		if err = graph.ValidateGraph(gph); err != nil {
+			return nil, err
+		}
+		// Put new resources from the generated spec to graph
+		return graph.GenerateGraph(sp.Resources, gph)
+	}
+	// Create a new graph to be used globally if no graph is stored in the storage
+	return graph.GenerateGraph(sp.Resources, &v1.Graph{
+		Project:   project,
+		Workspace: workspace,
+	})
+}
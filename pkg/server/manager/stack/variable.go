@@ -0,0 +1,230 @@
+package stack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+	"k8s.io/apimachinery/pkg/labels"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/domain/constant"
+	"kusionstack.io/kusion/pkg/domain/entity"
+	"kusionstack.io/kusion/pkg/infra/encryption"
+	logutil "kusionstack.io/kusion/pkg/server/util/logging"
+)
+
+// ResolvedVariables are the variables of the variable sets matching a stack
+// and workspace.
+type ResolvedVariables struct {
+	// Variables are the values of the variables keyed by their names.
+	Variables map[string]string
+	// Sensitive marks the variables whose values are decrypted from CipherText
+	// variables, which are kept out of the workspace context.
+	Sensitive map[string]bool
+	// VariableSets are the versions of the matched variable sets, from the
+	// lowest precedence to the highest.
+	VariableSets []entity.RunVariableSet
+}
+
+// ResolveVariables resolves the variables of the variable sets matching the
+// labels of the stack and workspace. A variable set matches when all of its
+// labels are found in the labels of the project, stack and workspace, or in
+// the built-in project, stack and workspace name labels.
+//
+// When several variable sets define the same variable, the variable set with
+// more labels, which is the more specific one, takes precedence. Variable sets
+// with the same number of labels take precedence in the order of their names.
+func (m *StackManager) ResolveVariables(ctx context.Context, stackEntity *entity.Stack, workspaceName string) (*ResolvedVariables, error) {
+	logger := logutil.GetLogger(ctx)
+	runLogger := logutil.GetRunLogger(ctx)
+
+	runLabels, err := m.runLabels(ctx, stackEntity, workspaceName)
+	if err != nil {
+		return nil, err
+	}
+
+	variableSets, err := m.variableSetRepo.List(ctx, &entity.VariableSetFilter{
+		Pagination: &entity.Pagination{
+			Page:     constant.CommonPageDefault,
+			PageSize: constant.CommonMaxResultLimit,
+		},
+		FetchAll: true,
+	}, &entity.SortOptions{Field: constant.SortByID})
+	if err != nil {
+		return nil, err
+	}
+	if len(variableSets.VariableSets) < variableSets.Total {
+		logutil.LogToAll(logger, runLogger, "Warn", "The amount of variable sets exceeds the maximum result limit, only part of them are matched", "total", variableSets.Total)
+	}
+
+	matched := make([]*entity.VariableSet, 0)
+	for _, vs := range variableSets.VariableSets {
+		if labels.SelectorFromSet(vs.Labels).Matches(runLabels) {
+			matched = append(matched, vs)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		if len(matched[i].Labels) != len(matched[j].Labels) {
+			return len(matched[i].Labels) < len(matched[j].Labels)
+		}
+		return matched[i].Name < matched[j].Name
+	})
+
+	resolved := &ResolvedVariables{
+		Variables:    make(map[string]string),
+		Sensitive:    make(map[string]bool),
+		VariableSets: make([]entity.RunVariableSet, 0, len(matched)),
+	}
+	for _, vs := range matched {
+		variables, err := m.variableRepo.List(ctx, &entity.VariableFilter{
+			VariableSet: vs.Name,
+			Pagination: &entity.Pagination{
+				Page:     constant.CommonPageDefault,
+				PageSize: constant.CommonMaxResultLimit,
+			},
+			FetchAll: true,
+		}, &entity.SortOptions{Field: constant.SortByID})
+		if err != nil {
+			return nil, err
+		}
+		for _, variable := range variables.Variables {
+			value, err := m.variableValue(ctx, variable)
+			if err != nil {
+				return nil, err
+			}
+			resolved.Variables[variable.Name] = value
+			resolved.Sensitive[variable.Name] = variable.Type == entity.CipherTextType
+		}
+		resolved.VariableSets = append(resolved.VariableSets, entity.RunVariableSet{
+			Name:    vs.Name,
+			Version: vs.Version,
+		})
+	}
+	logutil.LogToAll(logger, runLogger, "Info", "Variable sets resolved", "variableSets", resolved.VariableSets)
+
+	return resolved, nil
+}
+
+// variableValue returns the value of the variable, decrypting the value of a
+// CipherText variable. This is the only place the values of CipherText
+// variables are decrypted, so that they are in plain text only when injected
+// into a run.
+func (m *StackManager) variableValue(ctx context.Context, variable *entity.Variable) (string, error) {
+	if variable.Type != entity.CipherTextType || !encryption.IsEncrypted(variable.Value) {
+		return variable.Value, nil
+	}
+	if m.encrypter == nil {
+		return "", ErrVariableDecryptionDisabled
+	}
+	value, err := m.encrypter.Decrypt(ctx, variable.Value)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt variable %s in variable set %s: %w", variable.Name, variable.VariableSet, err)
+	}
+	return value, nil
+}
+
+// runLabels returns the labels the variable sets are matched against. The
+// labels are in the form of key=value, a label without a value matches a
+// variable set label with an empty value.
+func (m *StackManager) runLabels(ctx context.Context, stackEntity *entity.Stack, workspaceName string) (labels.Set, error) {
+	var labelList []string
+	if stackEntity.Project != nil {
+		labelList = append(labelList, stackEntity.Project.Labels...)
+	}
+	labelList = append(labelList, stackEntity.Labels...)
+
+	// The default workspace may not be registered
+	workspaceEntity, err := m.workspaceRepo.GetByName(ctx, workspaceName)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if workspaceEntity != nil {
+		labelList = append(labelList, workspaceEntity.Labels...)
+	}
+
+	runLabels := labels.Set{}
+	for _, label := range labelList {
+		key, value, _ := strings.Cut(label, "=")
+		runLabels[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	if stackEntity.Project != nil {
+		runLabels[constant.VariableSetLabelProject] = stackEntity.Project.Name
+	}
+	runLabels[constant.VariableSetLabelStack] = stackEntity.Name
+	runLabels[constant.VariableSetLabelWorkspace] = workspaceName
+	return runLabels, nil
+}
+
+// injectVariables injects the resolved variables into the workspace context,
+// both as entries of the context and as the environment variables of the
+// Terraform providers, and returns them as the arguments of the configuration
+// code. The entries configured in the workspace take precedence over the
+// variables.
+//
+// The sensitive variables are not injected into the workspace context, which
+// ends up in the persisted spec. They are returned as the secret environment
+// variables of the spec instead, which are only seen by the runtimes.
+func injectVariables(ws *v1.Workspace, resolved *ResolvedVariables) (map[string]string, map[string]string) {
+	arguments := make(map[string]string, len(resolved.Variables))
+	secretEnvs := make(map[string]string)
+	if len(resolved.Variables) == 0 {
+		return arguments, secretEnvs
+	}
+	if ws.Context == nil {
+		ws.Context = v1.GenericConfig{}
+	}
+
+	providerEnvs := v1.GenericConfig{}
+	if existing, ok := ws.Context[v1.ProviderEnvs].(v1.GenericConfig); ok {
+		providerEnvs = existing
+	} else if existing, ok := ws.Context[v1.ProviderEnvs].(map[string]any); ok {
+		providerEnvs = existing
+	}
+	for name, value := range resolved.Variables {
+		arguments[name] = value
+		if resolved.Sensitive[name] {
+			secretEnvs[name] = value
+			continue
+		}
+		if _, ok := ws.Context[name]; !ok {
+			ws.Context[name] = value
+		}
+		if _, ok := providerEnvs[name]; !ok {
+			providerEnvs[name] = value
+		}
+	}
+	if len(providerEnvs) > 0 {
+		ws.Context[v1.ProviderEnvs] = providerEnvs
+	}
+
+	return arguments, secretEnvs
+}
+
+// recordRunVariableSets records the versions of the variable sets injected
+// into the run being executed, if any.
+func (m *StackManager) recordRunVariableSets(ctx context.Context, runID uint, resolved *ResolvedVariables) error {
+	if runID == 0 || len(resolved.VariableSets) == 0 {
+		return nil
+	}
+	return m.runRepo.UpdateVariableSets(ctx, runID, resolved.VariableSets)
+}
+
+// prepareVariables resolves the variables of the stack and workspace, injects
+// them into the workspace and records them in the run being executed. It
+// returns the arguments of the configuration code and the secret environment
+// variables of the spec.
+func (m *StackManager) prepareVariables(ctx context.Context, params *StackRequestParams, stackEntity *entity.Stack, ws *v1.Workspace) (map[string]string, map[string]string, error) {
+	resolved, err := m.ResolveVariables(ctx, stackEntity, params.Workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = m.recordRunVariableSets(ctx, params.RunID, resolved); err != nil {
+		return nil, nil, err
+	}
+	arguments, secretEnvs := injectVariables(ws, resolved)
+	return arguments, secretEnvs, nil
+}
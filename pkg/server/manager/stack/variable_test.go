@@ -0,0 +1,124 @@
+package stack
+
+import (
+	"context"
+	"encoding/base64"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/domain/constant"
+	"kusionstack.io/kusion/pkg/domain/entity"
+	"kusionstack.io/kusion/pkg/infra/encryption"
+	"kusionstack.io/kusion/pkg/infra/persistence"
+)
+
+func TestResolveVariables(t *testing.T) {
+	ctx := context.Background()
+	db := persistence.GetSQLiteDB(t)
+	variableSetRepo := persistence.NewVariableSetRepository(db)
+	variableRepo := persistence.NewVariableRepository(db)
+	runRepo := persistence.NewRunRepository(db)
+	workspaceRepo := persistence.NewWorkspaceRepository(db)
+	m := &StackManager{
+		workspaceRepo:   workspaceRepo,
+		runRepo:         runRepo,
+		variableSetRepo: variableSetRepo,
+		variableRepo:    variableRepo,
+	}
+
+	remote, _ := url.Parse("https://github.com/KusionStack/kusion")
+	org := &entity.Organization{Name: "org", Owners: []string{"kusion"}}
+	require.NoError(t, persistence.NewOrganizationRepository(db).Create(ctx, org))
+	source := &entity.Source{Name: "source", SourceProvider: constant.SourceProviderTypeGit, Remote: remote}
+	require.NoError(t, persistence.NewSourceRepository(db).Create(ctx, source))
+	project := &entity.Project{Name: "project", Path: "/project", Organization: org, Source: source, Owners: []string{"kusion"}, Labels: []string{"team=payments"}}
+	require.NoError(t, persistence.NewProjectRepository(db).Create(ctx, project))
+	stackEntity := &entity.Stack{Name: "dev", Path: "/project/dev", Project: project, SyncState: constant.StackStateUnSynced}
+	require.NoError(t, persistence.NewStackRepository(db).Create(ctx, stackEntity))
+	backend := &entity.Backend{Name: "backend"}
+	require.NoError(t, persistence.NewBackendRepository(db).Create(ctx, backend))
+	require.NoError(t, workspaceRepo.Create(ctx, &entity.Workspace{Name: "prod", Backend: backend, Owners: []string{"kusion"}, Labels: []string{"env=prod"}}))
+
+	createVariableSet := func(name string, labels map[string]string, variables map[string]string) {
+		require.NoError(t, variableSetRepo.Create(ctx, &entity.VariableSet{Name: name, Labels: labels}))
+		for k, v := range variables {
+			require.NoError(t, variableRepo.Create(ctx, &entity.Variable{Name: k, Value: v, Type: entity.PlainTextType, VariableSet: name}))
+		}
+	}
+	createVariableSet("team", map[string]string{"team": "payments"}, map[string]string{"REGION": "us-east-1", "TOKEN": "team"})
+	createVariableSet("team_prod", map[string]string{"team": "payments", "env": "prod"}, map[string]string{"TOKEN": "prod"})
+	createVariableSet("stack", map[string]string{constant.VariableSetLabelStack: "dev"}, map[string]string{"STACK": "dev"})
+	createVariableSet("staging", map[string]string{"env": "staging"}, map[string]string{"TOKEN": "staging"})
+
+	t.Run("resolve", func(t *testing.T) {
+		resolved, err := m.ResolveVariables(ctx, stackEntity, "prod")
+		require.NoError(t, err)
+		// The more specific variable set takes precedence.
+		require.Equal(t, map[string]string{"REGION": "us-east-1", "TOKEN": "prod", "STACK": "dev"}, resolved.Variables)
+		// Creating each variable increases the version of its variable set.
+		require.Equal(t, []entity.RunVariableSet{
+			{Name: "stack", Version: 2},
+			{Name: "team", Version: 3},
+			{Name: "team_prod", Version: 2},
+		}, resolved.VariableSets)
+	})
+
+	t.Run("unregistered workspace", func(t *testing.T) {
+		resolved, err := m.ResolveVariables(ctx, stackEntity, constant.DefaultWorkspace)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"REGION": "us-east-1", "TOKEN": "team", "STACK": "dev"}, resolved.Variables)
+	})
+
+	t.Run("inject and record", func(t *testing.T) {
+		run := &entity.Run{Type: constant.RunTypeApply, Stack: stackEntity, Workspace: "prod", Status: constant.RunStatusInProgress}
+		require.NoError(t, runRepo.Create(ctx, run))
+
+		ws := &v1.Workspace{Name: "prod", Context: v1.GenericConfig{"REGION": "eu-west-1"}}
+		arguments, secretEnvs, err := m.prepareVariables(ctx, &StackRequestParams{StackID: stackEntity.ID, Workspace: "prod", RunID: run.ID}, stackEntity, ws)
+		require.NoError(t, err)
+		require.Empty(t, secretEnvs)
+		require.Equal(t, map[string]string{"REGION": "us-east-1", "TOKEN": "prod", "STACK": "dev"}, arguments)
+		// The workspace context takes precedence over the variables.
+		require.Equal(t, "eu-west-1", ws.Context["REGION"])
+		require.Equal(t, "prod", ws.Context["TOKEN"])
+		require.Equal(t, v1.GenericConfig{"REGION": "us-east-1", "TOKEN": "prod", "STACK": "dev"}, ws.Context[v1.ProviderEnvs])
+
+		recorded, err := runRepo.Get(ctx, run.ID)
+		require.NoError(t, err)
+		require.Len(t, recorded.VariableSets, 3)
+		require.Equal(t, "team_prod", recorded.VariableSets[2].Name)
+	})
+
+	t.Run("decrypt cipher text", func(t *testing.T) {
+		keyFile := filepath.Join(t.TempDir(), "variable.key")
+		require.NoError(t, os.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(make([]byte, 32))), 0o600))
+		provider, err := encryption.NewLocalKeyProvider(keyFile)
+		require.NoError(t, err)
+		encrypter := encryption.NewEncrypter(provider)
+		encrypted, err := encrypter.Encrypt(ctx, "s3cr3t")
+		require.NoError(t, err)
+		require.NoError(t, variableSetRepo.Create(ctx, &entity.VariableSet{Name: "secrets", Labels: map[string]string{constant.VariableSetLabelStack: "dev"}}))
+		require.NoError(t, variableRepo.Create(ctx, &entity.Variable{Name: "PASSWORD", Value: encrypted, Type: entity.CipherTextType, VariableSet: "secrets"}))
+
+		_, err = m.ResolveVariables(ctx, stackEntity, "prod")
+		require.ErrorIs(t, err, ErrVariableDecryptionDisabled)
+
+		m.encrypter = encrypter
+		resolved, err := m.ResolveVariables(ctx, stackEntity, "prod")
+		require.NoError(t, err)
+		require.Equal(t, "s3cr3t", resolved.Variables["PASSWORD"])
+
+		// The decrypted value is kept out of the workspace context.
+		ws := &v1.Workspace{Name: "prod"}
+		arguments, secretEnvs := injectVariables(ws, resolved)
+		require.Equal(t, "s3cr3t", arguments["PASSWORD"])
+		require.Equal(t, map[string]string{"PASSWORD": "s3cr3t"}, secretEnvs)
+		require.NotContains(t, ws.Context, "PASSWORD")
+		require.NotContains(t, ws.Context[v1.ProviderEnvs], "PASSWORD")
+	})
+}
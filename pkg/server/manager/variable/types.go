@@ -4,22 +4,34 @@ import (
 	"errors"
 
 	"kusionstack.io/kusion/pkg/domain/repository"
+	"kusionstack.io/kusion/pkg/infra/encryption"
 )
 
 var (
 	ErrGettingNonExistingVariable  = errors.New("the variable does not exist")
 	ErrUpdatingNonExistingVariable = errors.New("the variable to update does not exist")
 	ErrEmptyVariableName           = errors.New("the variable name should not be empty")
+	ErrVariableEncryptionDisabled  = errors.New("no key provider is configured to encrypt CipherText variables, please set --variable-key-provider")
 )
 
+// MaskedValue is returned in place of the values of CipherText variables in
+// API responses, and keeps the existing value when sent back in an update.
+const MaskedValue = "******"
+
 type VariableManager struct {
 	variableRepo repository.VariableRepository
+	encrypter    *encryption.Encrypter
 }
 
+// NewVariableManager creates a variable manager encrypting the values of
+// CipherText variables with the given encrypter. CipherText variables cannot
+// be created or updated if the encrypter is nil.
 func NewVariableManager(
 	variableRepo repository.VariableRepository,
+	encrypter *encryption.Encrypter,
 ) *VariableManager {
 	return &VariableManager{
 		variableRepo: variableRepo,
+		encrypter:    encrypter,
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"kusionstack.io/kusion/pkg/domain/constant"
+	"kusionstack.io/kusion/pkg/domain/entity"
 )
 
 func validateVariableSortOptions(sortBy string) (string, error) {
@@ -15,3 +16,13 @@ func validateVariableSortOptions(sortBy string) (string, error) {
 	}
 	return sortBy, nil
 }
+
+// maskVariableSensitiveData returns a copy of the variable with the value
+// masked if it is a CipherText variable.
+func maskVariableSensitiveData(variable *entity.Variable) *entity.Variable {
+	masked := *variable
+	if masked.Type == entity.CipherTextType {
+		masked.Value = MaskedValue
+	}
+	return &masked
+}
@@ -3,6 +3,7 @@ package variable
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/url"
 	"strconv"
 
@@ -11,6 +12,8 @@ import (
 	"kusionstack.io/kusion/pkg/domain/constant"
 	"kusionstack.io/kusion/pkg/domain/entity"
 	"kusionstack.io/kusion/pkg/domain/request"
+	"kusionstack.io/kusion/pkg/domain/response"
+	"kusionstack.io/kusion/pkg/infra/encryption"
 	logutil "kusionstack.io/kusion/pkg/server/util/logging"
 )
 
@@ -23,12 +26,19 @@ func (v *VariableManager) CreateVariable(ctx context.Context,
 		return nil, err
 	}
 
+	// Encrypt the value of the CipherText variable.
+	if createdEntity.Type == entity.CipherTextType {
+		if err := v.encryptValue(ctx, &createdEntity); err != nil {
+			return nil, err
+		}
+	}
+
 	// Create variable with repository.
 	if err := v.variableRepo.Create(ctx, &createdEntity); err != nil {
 		return nil, err
 	}
 
-	return &createdEntity, nil
+	return maskVariableSensitiveData(&createdEntity), nil
 }
 
 func (v *VariableManager) DeleteVariableByNameAndVariableSet(ctx context.Context,
@@ -63,15 +73,39 @@ func (v *VariableManager) UpdateVariableByNameAndVariableSet(ctx context.Context
 		return nil, err
 	}
 
+	// The masked value sent back by the clients keeps the existing value.
+	if requestEntity.Value == MaskedValue {
+		requestEntity.Value = ""
+	}
+	valueChanged := requestEntity.Value != ""
+	previousType := updatedEntity.Type
+
 	// Overwrite non-zero values in request entity to existing entity.
 	copier.CopyWithOption(updatedEntity, requestEntity, copier.Option{IgnoreEmpty: true})
 
+	// Decrypt the existing value if the variable is no longer a CipherText
+	// variable, and encrypt the value if it is a new or changed secret.
+	if !valueChanged && previousType == entity.CipherTextType &&
+		updatedEntity.Type != entity.CipherTextType && encryption.IsEncrypted(updatedEntity.Value) {
+		if v.encrypter == nil {
+			return nil, ErrVariableEncryptionDisabled
+		}
+		if updatedEntity.Value, err = v.encrypter.Decrypt(ctx, updatedEntity.Value); err != nil {
+			return nil, err
+		}
+	}
+	if updatedEntity.Type == entity.CipherTextType && (valueChanged || previousType != entity.CipherTextType) {
+		if err = v.encryptValue(ctx, updatedEntity); err != nil {
+			return nil, err
+		}
+	}
+
 	// Update variable with repository.
 	if err = v.variableRepo.Update(ctx, updatedEntity); err != nil {
 		return nil, err
 	}
 
-	return updatedEntity, nil
+	return maskVariableSensitiveData(updatedEntity), nil
 }
 
 func (v *VariableManager) GetVariableByNameAndVariableSet(ctx context.Context,
@@ -86,7 +120,7 @@ func (v *VariableManager) GetVariableByNameAndVariableSet(ctx context.Context,
 		return nil, err
 	}
 
-	return existingEntity, nil
+	return maskVariableSensitiveData(existingEntity), nil
 }
 
 func (v *VariableManager) ListVariables(ctx context.Context,
@@ -101,9 +135,73 @@ func (v *VariableManager) ListVariables(ctx context.Context,
 		return nil, err
 	}
 
+	for i, variable := range variableEntities.Variables {
+		variableEntities.Variables[i] = maskVariableSensitiveData(variable)
+	}
+
 	return variableEntities, nil
 }
 
+// RotateVariableKeys re-encrypts the CipherText variables which are not
+// encrypted with the current key, including the ones stored in plain text
+// before the encryption is enabled.
+func (v *VariableManager) RotateVariableKeys(ctx context.Context) (*response.VariableKeyRotationResponse, error) {
+	logger := logutil.GetLogger(ctx)
+	if v.encrypter == nil {
+		return nil, ErrVariableEncryptionDisabled
+	}
+
+	variables, err := v.variableRepo.List(ctx, &entity.VariableFilter{
+		Type: entity.CipherTextType,
+		Pagination: &entity.Pagination{
+			Page:     constant.CommonPageDefault,
+			PageSize: constant.CommonMaxResultLimit,
+		},
+		FetchAll: true,
+	}, &entity.SortOptions{Field: constant.SortByID})
+	if err != nil {
+		return nil, err
+	}
+	if len(variables.Variables) < variables.Total {
+		logger.Warn("The amount of CipherText variables exceeds the maximum result limit, only part of them are rotated", "total", variables.Total)
+	}
+
+	result := &response.VariableKeyRotationResponse{Total: variables.Total}
+	for _, variable := range variables.Variables {
+		if !v.encrypter.NeedsRotation(variable.Value) {
+			continue
+		}
+		if encryption.IsEncrypted(variable.Value) {
+			if variable.Value, err = v.encrypter.Decrypt(ctx, variable.Value); err != nil {
+				return result, fmt.Errorf("failed to decrypt variable %s in variable set %s: %w", variable.Name, variable.VariableSet, err)
+			}
+		}
+		if err = v.encryptValue(ctx, variable); err != nil {
+			return result, err
+		}
+		if err = v.variableRepo.Update(ctx, variable); err != nil {
+			return result, err
+		}
+		result.Rotated++
+	}
+	logger.Info("Variable keys rotated", "rotated", result.Rotated, "total", result.Total)
+
+	return result, nil
+}
+
+// encryptValue encrypts the value of the variable with the current key.
+func (v *VariableManager) encryptValue(ctx context.Context, variable *entity.Variable) error {
+	if v.encrypter == nil {
+		return ErrVariableEncryptionDisabled
+	}
+	encrypted, err := v.encrypter.Encrypt(ctx, variable.Value)
+	if err != nil {
+		return err
+	}
+	variable.Value = encrypted
+	return nil
+}
+
 func (v *VariableManager) BuildVariableFilterAndSortOptions(ctx context.Context,
 	query *url.Values,
 ) (*entity.VariableFilter, *entity.SortOptions, error) {
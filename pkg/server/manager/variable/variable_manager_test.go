@@ -0,0 +1,127 @@
+package variable
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"kusionstack.io/kusion/pkg/domain/entity"
+	"kusionstack.io/kusion/pkg/domain/request"
+	"kusionstack.io/kusion/pkg/infra/encryption"
+	"kusionstack.io/kusion/pkg/infra/persistence"
+)
+
+func newEncrypter(t *testing.T, keyFile string) *encryption.Encrypter {
+	t.Helper()
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	f, err := os.OpenFile(keyFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString(base64.StdEncoding.EncodeToString(key) + "\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	provider, err := encryption.NewLocalKeyProvider(keyFile)
+	require.NoError(t, err)
+	return encryption.NewEncrypter(provider)
+}
+
+func TestCipherTextVariables(t *testing.T) {
+	ctx := context.Background()
+	db := persistence.GetSQLiteDB(t)
+	variableRepo := persistence.NewVariableRepository(db)
+	require.NoError(t, persistence.NewVariableSetRepository(db).Create(ctx, &entity.VariableSet{Name: "secrets", Labels: map[string]string{"env": "prod"}}))
+	keyFile := filepath.Join(t.TempDir(), "variable.key")
+	encrypter := newEncrypter(t, keyFile)
+	m := NewVariableManager(variableRepo, encrypter)
+
+	t.Run("encryption disabled", func(t *testing.T) {
+		_, err := NewVariableManager(variableRepo, nil).CreateVariable(ctx, request.CreateVariableRequest{
+			Name: "PASSWORD", Value: "s3cr3t", Type: entity.CipherTextType, VariableSet: "secrets",
+		})
+		require.ErrorIs(t, err, ErrVariableEncryptionDisabled)
+	})
+
+	t.Run("encrypt and mask", func(t *testing.T) {
+		created, err := m.CreateVariable(ctx, request.CreateVariableRequest{
+			Name: "PASSWORD", Value: "s3cr3t", Type: entity.CipherTextType, VariableSet: "secrets",
+		})
+		require.NoError(t, err)
+		require.Equal(t, MaskedValue, created.Value)
+
+		stored, err := variableRepo.Get(ctx, "PASSWORD", "secrets")
+		require.NoError(t, err)
+		require.True(t, encryption.IsEncrypted(stored.Value))
+		decrypted, err := encrypter.Decrypt(ctx, stored.Value)
+		require.NoError(t, err)
+		require.Equal(t, "s3cr3t", decrypted)
+
+		got, err := m.GetVariableByNameAndVariableSet(ctx, "PASSWORD", "secrets")
+		require.NoError(t, err)
+		require.Equal(t, MaskedValue, got.Value)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		// The masked value keeps the existing value.
+		before, err := variableRepo.Get(ctx, "PASSWORD", "secrets")
+		require.NoError(t, err)
+		_, err = m.UpdateVariableByNameAndVariableSet(ctx, "PASSWORD", "secrets", request.UpdateVariableRequest{Value: MaskedValue})
+		require.NoError(t, err)
+		after, err := variableRepo.Get(ctx, "PASSWORD", "secrets")
+		require.NoError(t, err)
+		require.Equal(t, before.Value, after.Value)
+
+		// Changing to a PlainText variable decrypts the value.
+		updated, err := m.UpdateVariableByNameAndVariableSet(ctx, "PASSWORD", "secrets", request.UpdateVariableRequest{Type: entity.PlainTextType})
+		require.NoError(t, err)
+		require.Equal(t, "s3cr3t", updated.Value)
+
+		// Changing back to a CipherText variable encrypts the value.
+		updated, err = m.UpdateVariableByNameAndVariableSet(ctx, "PASSWORD", "secrets", request.UpdateVariableRequest{Type: entity.CipherTextType})
+		require.NoError(t, err)
+		require.Equal(t, MaskedValue, updated.Value)
+		stored, err := variableRepo.Get(ctx, "PASSWORD", "secrets")
+		require.NoError(t, err)
+		require.True(t, encryption.IsEncrypted(stored.Value))
+	})
+
+	t.Run("rotate keys", func(t *testing.T) {
+		// A CipherText variable stored in plain text before the encryption is
+		// enabled.
+		require.NoError(t, variableRepo.Create(ctx, &entity.Variable{Name: "TOKEN", Value: "t0k3n", Type: entity.CipherTextType, VariableSet: "secrets"}))
+		require.NoError(t, variableRepo.Create(ctx, &entity.Variable{Name: "REGION", Value: "us-east-1", Type: entity.PlainTextType, VariableSet: "secrets"}))
+
+		rotated := NewVariableManager(variableRepo, newEncrypter(t, keyFile))
+		result, err := rotated.RotateVariableKeys(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 2, result.Rotated)
+		require.Equal(t, 2, result.Total)
+
+		result, err = rotated.RotateVariableKeys(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 0, result.Rotated)
+
+		list, err := rotated.ListVariables(ctx, &entity.VariableFilter{
+			VariableSet: "secrets",
+			Pagination:  &entity.Pagination{Page: 1, PageSize: 10},
+		}, &entity.SortOptions{Field: "name"})
+		require.NoError(t, err)
+		values := make(map[string]string)
+		for _, variable := range list.Variables {
+			values[variable.Name] = variable.Value
+		}
+		require.Equal(t, map[string]string{"PASSWORD": MaskedValue, "TOKEN": MaskedValue, "REGION": "us-east-1"}, values)
+
+		stored, err := variableRepo.Get(ctx, "TOKEN", "secrets")
+		require.NoError(t, err)
+		decrypted, err := rotated.encrypter.Decrypt(ctx, stored.Value)
+		require.NoError(t, err)
+		require.Equal(t, "t0k3n", decrypted)
+	})
+}
@@ -0,0 +1,124 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"kusionstack.io/kusion/pkg/domain/constant"
+)
+
+// Formatter renders a payload into the request body expected by a kind of
+// webhook receiver.
+type Formatter interface {
+	// ContentType returns the content type of the rendered body.
+	ContentType() string
+	// Format renders the payload into the request body.
+	Format(payload *Payload) ([]byte, error)
+}
+
+// formatterFor returns the built-in formatter for the webhook format.
+func formatterFor(format constant.WebhookFormat) Formatter {
+	switch format {
+	case constant.WebhookFormatSlack:
+		return &slackFormatter{}
+	default:
+		return &jsonFormatter{}
+	}
+}
+
+// jsonFormatter posts the payload as is, for generic JSON receivers.
+type jsonFormatter struct{}
+
+func (f *jsonFormatter) ContentType() string {
+	return "application/json"
+}
+
+func (f *jsonFormatter) Format(payload *Payload) ([]byte, error) {
+	return json.Marshal(payload)
+}
+
+// slackFormatter renders the payload as a Slack-compatible incoming webhook
+// message with a colored attachment.
+type slackFormatter struct{}
+
+type slackMessage struct {
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments,omitempty"`
+}
+
+type slackAttachment struct {
+	Color  string       `json:"color,omitempty"`
+	Fields []slackField `json:"fields,omitempty"`
+	Footer string       `json:"footer,omitempty"`
+	Ts     int64        `json:"ts,omitempty"`
+}
+
+type slackField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+func (f *slackFormatter) ContentType() string {
+	return "application/json"
+}
+
+func (f *slackFormatter) Format(payload *Payload) ([]byte, error) {
+	attachment := slackAttachment{
+		Color:  slackColor(payload.Event),
+		Footer: "Kusion",
+		Ts:     payload.Timestamp.Unix(),
+	}
+	if payload.Organization != nil {
+		attachment.Fields = append(attachment.Fields, slackField{Title: "Organization", Value: payload.Organization.Name, Short: true})
+	}
+	if payload.Project != nil {
+		attachment.Fields = append(attachment.Fields, slackField{Title: "Project", Value: payload.Project.Name, Short: true})
+	}
+	if payload.Stack != nil {
+		attachment.Fields = append(attachment.Fields, slackField{Title: "Stack", Value: payload.Stack.Name, Short: true})
+	}
+	if payload.Run != nil {
+		attachment.Fields = append(attachment.Fields,
+			slackField{Title: "Workspace", Value: payload.Run.Workspace, Short: true},
+			slackField{Title: "Run", Value: fmt.Sprintf("#%d %s", payload.Run.ID, payload.Run.Type), Short: true},
+			slackField{Title: "Status", Value: payload.Run.Status, Short: true},
+		)
+	}
+	if payload.Changes != nil {
+		attachment.Fields = append(attachment.Fields, slackField{
+			Title: "Changes",
+			Value: fmt.Sprintf("%d to create, %d to update, %d to delete",
+				payload.Changes.Create, payload.Changes.Update, payload.Changes.Delete),
+		})
+	}
+
+	return json.Marshal(&slackMessage{
+		Text:        slackText(payload),
+		Attachments: []slackAttachment{attachment},
+	})
+}
+
+func slackText(payload *Payload) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("*[%s]*", payload.Event))
+	if payload.Message != "" {
+		sb.WriteString(" ")
+		sb.WriteString(payload.Message)
+	}
+	return sb.String()
+}
+
+func slackColor(event constant.WebhookEvent) string {
+	switch event {
+	case constant.WebhookEventRunSucceeded:
+		return "good"
+	case constant.WebhookEventRunFailed:
+		return "danger"
+	case constant.WebhookEventRunCancelled, constant.WebhookEventStackOutOfSync:
+		return "warning"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,266 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"kusionstack.io/kusion/pkg/domain/constant"
+	"kusionstack.io/kusion/pkg/domain/entity"
+	logutil "kusionstack.io/kusion/pkg/server/util/logging"
+)
+
+// Notify delivers the event to every enabled webhook that subscribes to it and
+// whose scope covers the stack of the run. Deliveries are sent in background
+// goroutines so that the caller is never blocked by slow receivers. It is safe
+// to call Notify on a nil manager.
+func (m *WebhookManager) Notify(ctx context.Context, event *Event) {
+	if m == nil || event == nil {
+		return
+	}
+	logger := logutil.GetLogger(ctx)
+
+	webhooks, err := m.matchWebhooks(ctx, event)
+	if err != nil {
+		logger.Error("Error listing webhooks to notify", "event", event.Type, "error", err)
+		return
+	}
+
+	// Deliveries outlive the run, so they must not be cancelled along with it.
+	deliveryCtx := context.WithoutCancel(ctx)
+	for _, webhook := range webhooks {
+		go m.deliver(deliveryCtx, webhook, newPayload(event), m.maxAttempts)
+	}
+}
+
+// Ping synchronously delivers a ping event to the webhook without retries and
+// returns the recorded delivery, so that users can check the receiver
+// configuration.
+func (m *WebhookManager) Ping(ctx context.Context, id uint) (*entity.WebhookDelivery, error) {
+	webhook, err := m.webhookRepo.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGettingNonExistingWebhook
+		}
+		return nil, err
+	}
+	if !webhook.Enabled {
+		return nil, ErrWebhookDisabled
+	}
+
+	return m.deliver(ctx, webhook, newPayload(&Event{
+		Type:    constant.WebhookEventPing,
+		Message: fmt.Sprintf("webhook %s is configured correctly", webhook.Name),
+	}), 1), nil
+}
+
+// matchWebhooks returns the enabled webhooks the event should be sent to.
+func (m *WebhookManager) matchWebhooks(ctx context.Context, event *Event) ([]*entity.Webhook, error) {
+	var stack *entity.Stack
+	if event.Run != nil {
+		stack = event.Run.Stack
+	}
+
+	enabled := true
+	matched := make([]*entity.Webhook, 0)
+	for page, listed := constant.CommonPageDefault, 0; ; page++ {
+		webhookEntities, err := m.webhookRepo.List(ctx, &entity.WebhookFilter{
+			Enabled: &enabled,
+			Pagination: &entity.Pagination{
+				Page:     page,
+				PageSize: constant.CommonMaxResultLimit,
+			},
+		}, &entity.SortOptions{
+			Field:     constant.SortByID,
+			Ascending: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, webhook := range webhookEntities.Webhooks {
+			if webhook.Subscribes(event.Type) && webhook.Matches(stack) {
+				matched = append(matched, webhook)
+			}
+		}
+		listed += len(webhookEntities.Webhooks)
+		if len(webhookEntities.Webhooks) == 0 || listed >= webhookEntities.Total {
+			return matched, nil
+		}
+	}
+}
+
+// deliver posts the payload to the webhook, retrying with exponential backoff
+// on network errors and retryable status codes, and records the delivery.
+func (m *WebhookManager) deliver(ctx context.Context, webhook *entity.Webhook, payload *Payload, maxAttempts int) *entity.WebhookDelivery {
+	logger := logutil.GetLogger(ctx)
+	start := time.Now()
+	delivery := &entity.WebhookDelivery{
+		WebhookID: webhook.ID,
+		Event:     payload.Event,
+		Status:    constant.WebhookDeliveryFailed,
+	}
+	if payload.Run != nil {
+		delivery.RunID = payload.Run.ID
+	}
+
+	formatter := formatterFor(webhook.Format)
+	body, err := formatter.Format(payload)
+	if err != nil {
+		delivery.Error = err.Error()
+		m.recordDelivery(ctx, delivery, start)
+		return delivery
+	}
+	delivery.Payload = string(body)
+	secret, err := m.signingSecret(ctx, webhook)
+	if err != nil {
+		delivery.Error = err.Error()
+		m.recordDelivery(ctx, delivery, start)
+		return delivery
+	}
+
+	backoff := m.initialBackoff
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		delivery.Attempts = attempt
+		code, respBody, err := m.send(ctx, webhook, secret, payload, body, formatter.ContentType())
+		delivery.ResponseCode = code
+		delivery.ResponseBody = respBody
+		if err == nil {
+			delivery.Status = constant.WebhookDeliverySucceeded
+			delivery.Error = ""
+			break
+		}
+		delivery.Error = err.Error()
+		logger.Warn("Webhook delivery attempt failed", "webhook", webhook.Name, "event", payload.Event, "attempt", attempt, "error", err)
+		if !retryable(code) || attempt == maxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			delivery.Error = ctx.Err().Error()
+			m.recordDelivery(ctx, delivery, start)
+			return delivery
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > m.maxBackoff {
+			backoff = m.maxBackoff
+		}
+	}
+
+	m.recordDelivery(ctx, delivery, start)
+	return delivery
+}
+
+// send performs a single delivery attempt, signing the body with the plain
+// text secret. A non-2xx response is returned as an error together with its
+// status code.
+func (m *WebhookManager) send(ctx context.Context, webhook *entity.Webhook, secret string, payload *Payload, body []byte, contentType string) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	timestamp := strconv.FormatInt(payload.Timestamp.Unix(), 10)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", "Kusion-Webhook")
+	req.Header.Set(constant.WebhookEventHeader, string(payload.Event))
+	req.Header.Set(constant.WebhookDeliveryHeader, payload.ID)
+	req.Header.Set(constant.WebhookTimestampHeader, timestamp)
+	if secret != "" {
+		req.Header.Set(constant.WebhookSignatureHeader, Sign(secret, timestamp, body))
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, constant.WebhookMaxResponseBodyLength))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, string(respBody), fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return resp.StatusCode, string(respBody), nil
+}
+
+// recordDelivery persists the delivery log, logging instead of failing since
+// the delivery itself has already happened.
+func (m *WebhookManager) recordDelivery(ctx context.Context, delivery *entity.WebhookDelivery, start time.Time) {
+	delivery.Duration = time.Since(start)
+	if m.deliveryRepo == nil {
+		return
+	}
+	if err := m.deliveryRepo.Create(ctx, delivery); err != nil {
+		logutil.GetLogger(ctx).Error("Error recording webhook delivery", "webhookID", delivery.WebhookID, "error", err)
+	}
+}
+
+// Sign computes the value of the signature header for a payload. Receivers
+// verify a delivery by computing the HMAC-SHA256 of "<timestamp>.<body>" with
+// the shared secret and comparing it to the header in constant time.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// retryable reports whether a failed attempt with the status code should be
+// retried. Network errors have no status code and are always retried.
+func retryable(code int) bool {
+	switch {
+	case code == 0:
+		return true
+	case code == http.StatusRequestTimeout, code == http.StatusTooManyRequests:
+		return true
+	case code >= 500:
+		return true
+	default:
+		return false
+	}
+}
+
+func newPayload(event *Event) *Payload {
+	payload := &Payload{
+		ID:        uuid.New().String(),
+		Event:     event.Type,
+		Timestamp: time.Now().UTC(),
+		Message:   event.Message,
+		Changes:   event.Changes,
+	}
+	if event.Run == nil {
+		return payload
+	}
+
+	run := event.Run
+	payload.Run = &PayloadRun{
+		ID:                run.ID,
+		Type:              string(run.Type),
+		Status:            string(run.Status),
+		Workspace:         run.Workspace,
+		CreationTimestamp: run.CreationTimestamp,
+		UpdateTimestamp:   run.UpdateTimestamp,
+	}
+	if run.Stack != nil {
+		payload.Stack = &PayloadObject{ID: run.Stack.ID, Name: run.Stack.Name, Path: run.Stack.Path}
+		if project := run.Stack.Project; project != nil {
+			payload.Project = &PayloadObject{ID: project.ID, Name: project.Name, Path: project.Path}
+			if org := project.Organization; org != nil {
+				payload.Organization = &PayloadObject{ID: org.ID, Name: org.Name}
+			}
+		}
+	}
+	return payload
+}
@@ -0,0 +1,284 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"kusionstack.io/kusion/pkg/domain/constant"
+	"kusionstack.io/kusion/pkg/domain/entity"
+	"kusionstack.io/kusion/pkg/infra/encryption"
+)
+
+type fakeWebhookRepository struct {
+	webhooks []*entity.Webhook
+}
+
+func (r *fakeWebhookRepository) Create(ctx context.Context, webhook *entity.Webhook) error {
+	r.webhooks = append(r.webhooks, webhook)
+	return nil
+}
+
+func (r *fakeWebhookRepository) Delete(ctx context.Context, id uint) error { return nil }
+
+func (r *fakeWebhookRepository) Update(ctx context.Context, webhook *entity.Webhook) error {
+	return nil
+}
+
+func (r *fakeWebhookRepository) Get(ctx context.Context, id uint) (*entity.Webhook, error) {
+	for _, webhook := range r.webhooks {
+		if webhook.ID == id {
+			return webhook, nil
+		}
+	}
+	return nil, ErrGettingNonExistingWebhook
+}
+
+func (r *fakeWebhookRepository) List(ctx context.Context, filter *entity.WebhookFilter, sortOptions *entity.SortOptions) (*entity.WebhookListResult, error) {
+	result := &entity.WebhookListResult{}
+	for _, webhook := range r.webhooks {
+		if filter.Enabled != nil && webhook.Enabled != *filter.Enabled {
+			continue
+		}
+		result.Webhooks = append(result.Webhooks, webhook)
+	}
+	result.Total = len(result.Webhooks)
+	if p := filter.Pagination; p != nil {
+		start := min((p.Page-1)*p.PageSize, result.Total)
+		result.Webhooks = result.Webhooks[start:min(start+p.PageSize, result.Total)]
+	}
+	return result, nil
+}
+
+type fakeWebhookDeliveryRepository struct {
+	mu         sync.Mutex
+	deliveries []*entity.WebhookDelivery
+}
+
+func (r *fakeWebhookDeliveryRepository) Create(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries = append(r.deliveries, delivery)
+	return nil
+}
+
+func (r *fakeWebhookDeliveryRepository) List(ctx context.Context, filter *entity.WebhookDeliveryFilter, sortOptions *entity.SortOptions) (*entity.WebhookDeliveryListResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &entity.WebhookDeliveryListResult{Deliveries: r.deliveries, Total: len(r.deliveries)}, nil
+}
+
+func (r *fakeWebhookDeliveryRepository) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.deliveries)
+}
+
+func newTestManager(webhooks ...*entity.Webhook) (*WebhookManager, *fakeWebhookDeliveryRepository) {
+	deliveryRepo := &fakeWebhookDeliveryRepository{}
+	m := NewWebhookManager(&fakeWebhookRepository{webhooks: webhooks}, deliveryRepo, nil)
+	m.initialBackoff = time.Millisecond
+	m.maxBackoff = time.Millisecond
+	return m, deliveryRepo
+}
+
+func newEncrypter(t *testing.T) *encryption.Encrypter {
+	t.Helper()
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	keyFile := filepath.Join(t.TempDir(), "webhook.key")
+	require.NoError(t, os.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0o600))
+
+	provider, err := encryption.NewLocalKeyProvider(keyFile)
+	require.NoError(t, err)
+	return encryption.NewEncrypter(provider)
+}
+
+func testRun() *entity.Run {
+	return &entity.Run{
+		ID:        7,
+		Type:      constant.RunTypeApply,
+		Status:    constant.RunStatusFailed,
+		Workspace: "dev",
+		Stack: &entity.Stack{
+			ID:   3,
+			Name: "dev",
+			Project: &entity.Project{
+				ID:           2,
+				Name:         "wordpress",
+				Organization: &entity.Organization{ID: 1, Name: "kusion"},
+			},
+		},
+	}
+}
+
+func TestWebhookManager_Deliver(t *testing.T) {
+	t.Run("signed json payload", func(t *testing.T) {
+		var received *http.Request
+		var body []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r
+			body, _ = io.ReadAll(r.Body)
+		}))
+		defer server.Close()
+
+		webhook := &entity.Webhook{ID: 1, Name: "ci", URL: server.URL, Secret: "s3cr3t", Enabled: true}
+		m, _ := newTestManager(webhook)
+		delivery := m.deliver(context.Background(), webhook,
+			newPayload(&Event{Type: constant.WebhookEventRunFailed, Run: testRun()}), m.maxAttempts)
+
+		assert.Equal(t, constant.WebhookDeliverySucceeded, delivery.Status)
+		assert.Equal(t, 1, delivery.Attempts)
+		assert.Equal(t, uint(7), delivery.RunID)
+		require.NotNil(t, received)
+		assert.Equal(t, "run.failed", received.Header.Get(constant.WebhookEventHeader))
+		assert.Equal(t, Sign("s3cr3t", received.Header.Get(constant.WebhookTimestampHeader), body),
+			received.Header.Get(constant.WebhookSignatureHeader))
+
+		var payload Payload
+		require.NoError(t, json.Unmarshal(body, &payload))
+		assert.Equal(t, "wordpress", payload.Project.Name)
+		assert.Equal(t, "kusion", payload.Organization.Name)
+		assert.Equal(t, uint(7), payload.Run.ID)
+	})
+
+	t.Run("encrypted secret", func(t *testing.T) {
+		var received *http.Request
+		var body []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r
+			body, _ = io.ReadAll(r.Body)
+		}))
+		defer server.Close()
+
+		webhook := &entity.Webhook{ID: 1, Name: "ci", URL: server.URL, Secret: "s3cr3t", Enabled: true}
+		m, deliveryRepo := newTestManager(webhook)
+		m.encrypter = newEncrypter(t)
+		require.NoError(t, m.encryptSecret(context.Background(), webhook))
+		require.True(t, encryption.IsEncrypted(webhook.Secret))
+
+		delivery := m.deliver(context.Background(), webhook,
+			newPayload(&Event{Type: constant.WebhookEventRunFailed}), m.maxAttempts)
+		assert.Equal(t, constant.WebhookDeliverySucceeded, delivery.Status)
+		require.NotNil(t, received)
+		assert.Equal(t, Sign("s3cr3t", received.Header.Get(constant.WebhookTimestampHeader), body),
+			received.Header.Get(constant.WebhookSignatureHeader))
+
+		// The encrypted secret cannot be used without the key provider.
+		m.encrypter = nil
+		delivery = m.deliver(context.Background(), webhook,
+			newPayload(&Event{Type: constant.WebhookEventRunFailed}), m.maxAttempts)
+		assert.Equal(t, constant.WebhookDeliveryFailed, delivery.Status)
+		assert.Equal(t, ErrWebhookEncryptionDisabled.Error(), delivery.Error)
+		assert.Equal(t, 2, deliveryRepo.count())
+	})
+
+	t.Run("retry on server errors", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+			}
+		}))
+		defer server.Close()
+
+		webhook := &entity.Webhook{ID: 1, Name: "ci", URL: server.URL, Enabled: true}
+		m, deliveryRepo := newTestManager(webhook)
+		delivery := m.deliver(context.Background(), webhook,
+			newPayload(&Event{Type: constant.WebhookEventRunFailed}), m.maxAttempts)
+
+		assert.Equal(t, constant.WebhookDeliverySucceeded, delivery.Status)
+		assert.Equal(t, 3, delivery.Attempts)
+		assert.Equal(t, 1, deliveryRepo.count())
+	})
+
+	t.Run("no retry on client errors", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		webhook := &entity.Webhook{ID: 1, Name: "ci", URL: server.URL, Enabled: true}
+		m, _ := newTestManager(webhook)
+		delivery := m.deliver(context.Background(), webhook,
+			newPayload(&Event{Type: constant.WebhookEventRunFailed}), m.maxAttempts)
+
+		assert.Equal(t, constant.WebhookDeliveryFailed, delivery.Status)
+		assert.Equal(t, http.StatusNotFound, delivery.ResponseCode)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+}
+
+func TestWebhookManager_Notify(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	m, deliveryRepo := newTestManager(
+		// Matches: subscribed to the event and scoped to the project.
+		&entity.Webhook{ID: 1, Name: "project", URL: server.URL, ProjectID: 2, Enabled: true},
+		// Matches: no events means all events.
+		&entity.Webhook{ID: 2, Name: "all", URL: server.URL, Enabled: true},
+		// Skipped: scoped to another stack.
+		&entity.Webhook{ID: 3, Name: "other-stack", URL: server.URL, StackID: 4, Enabled: true},
+		// Skipped: not subscribed to failures.
+		&entity.Webhook{ID: 4, Name: "success", URL: server.URL, Events: []string{"run.succeeded"}, Enabled: true},
+		// Skipped: disabled.
+		&entity.Webhook{ID: 5, Name: "disabled", URL: server.URL, Enabled: false},
+	)
+	m.Notify(context.Background(), &Event{Type: constant.WebhookEventRunFailed, Run: testRun()})
+
+	require.Eventually(t, func() bool { return deliveryRepo.count() == 2 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	// A nil manager is a no-op.
+	var nilManager *WebhookManager
+	nilManager.Notify(context.Background(), &Event{Type: constant.WebhookEventRunFailed})
+}
+
+func TestWebhookManager_MatchWebhooks(t *testing.T) {
+	webhooks := make([]*entity.Webhook, 0, constant.CommonMaxResultLimit+2)
+	for i := 1; i <= constant.CommonMaxResultLimit+2; i++ {
+		webhooks = append(webhooks, &entity.Webhook{ID: uint(i), Name: fmt.Sprintf("webhook-%d", i), Enabled: true})
+	}
+	m, _ := newTestManager(webhooks...)
+
+	matched, err := m.matchWebhooks(context.Background(), &Event{Type: constant.WebhookEventRunFailed, Run: testRun()})
+	require.NoError(t, err)
+	assert.Len(t, matched, constant.CommonMaxResultLimit+2)
+}
+
+func TestSlackFormatter(t *testing.T) {
+	body, err := formatterFor(constant.WebhookFormatSlack).Format(newPayload(&Event{
+		Type:    constant.WebhookEventStackOutOfSync,
+		Run:     testRun(),
+		Message: "stack dev is out of sync",
+		Changes: &ChangeSummary{Update: 2},
+	}))
+	require.NoError(t, err)
+
+	var message slackMessage
+	require.NoError(t, json.Unmarshal(body, &message))
+	assert.Equal(t, "*[stack.outOfSync]* stack dev is out of sync", message.Text)
+	require.Len(t, message.Attachments, 1)
+	assert.Equal(t, "warning", message.Attachments[0].Color)
+	assert.Contains(t, string(body), "0 to create, 2 to update, 0 to delete")
+}
@@ -0,0 +1,106 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"kusionstack.io/kusion/pkg/domain/constant"
+	"kusionstack.io/kusion/pkg/domain/entity"
+	"kusionstack.io/kusion/pkg/domain/repository"
+	"kusionstack.io/kusion/pkg/infra/encryption"
+)
+
+var (
+	ErrGettingNonExistingWebhook  = errors.New("the webhook does not exist")
+	ErrUpdatingNonExistingWebhook = errors.New("the webhook to update does not exist")
+	ErrInvalidWebhookID           = errors.New("the webhook ID should be an unsigned integer")
+	ErrWebhookDisabled            = errors.New("the webhook is disabled")
+	ErrInvalidRunID               = errors.New("the run ID should be an unsigned integer")
+	ErrInternalServerError        = errors.New("internal server error")
+	ErrWebhookEncryptionDisabled  = errors.New("the webhook secret is encrypted but no key provider is configured, please set --variable-key-provider")
+)
+
+// maskedSecret is returned in place of the webhook secret in API responses.
+const maskedSecret = "**********"
+
+type WebhookManager struct {
+	webhookRepo    repository.WebhookRepository
+	deliveryRepo   repository.WebhookDeliveryRepository
+	encrypter      *encryption.Encrypter
+	client         *http.Client
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+// NewWebhookManager creates a webhook manager encrypting the webhook secrets
+// with the given encrypter. The secrets are stored in plain text if the
+// encrypter is nil.
+func NewWebhookManager(
+	webhookRepo repository.WebhookRepository,
+	deliveryRepo repository.WebhookDeliveryRepository,
+	encrypter *encryption.Encrypter,
+) *WebhookManager {
+	return &WebhookManager{
+		webhookRepo:    webhookRepo,
+		deliveryRepo:   deliveryRepo,
+		encrypter:      encrypter,
+		client:         &http.Client{Timeout: constant.WebhookDefaultRequestTimeout},
+		maxAttempts:    constant.WebhookDefaultMaxAttempts,
+		initialBackoff: constant.WebhookDefaultInitialBackoff,
+		maxBackoff:     constant.WebhookDefaultMaxBackoff,
+	}
+}
+
+// Event describes a run lifecycle event to be delivered to the subscribed
+// webhooks.
+type Event struct {
+	// Type is the type of the event.
+	Type constant.WebhookEvent
+	// Run is the run that triggered the event.
+	Run *entity.Run
+	// Message is a short human-readable description of the event.
+	Message string
+	// Changes summarizes the changes detected by a preview run, if any.
+	Changes *ChangeSummary
+}
+
+// ChangeSummary summarizes the resource changes detected by a run.
+type ChangeSummary struct {
+	Create    int      `json:"create"`
+	Update    int      `json:"update"`
+	Delete    int      `json:"delete"`
+	UnChanged int      `json:"unchanged"`
+	Resources []string `json:"resources,omitempty"`
+}
+
+// Payload is the generic JSON body posted to the webhook receivers.
+type Payload struct {
+	ID           string                `json:"id"`
+	Event        constant.WebhookEvent `json:"event"`
+	Timestamp    time.Time             `json:"timestamp"`
+	Message      string                `json:"message,omitempty"`
+	Run          *PayloadRun           `json:"run,omitempty"`
+	Stack        *PayloadObject        `json:"stack,omitempty"`
+	Project      *PayloadObject        `json:"project,omitempty"`
+	Organization *PayloadObject        `json:"organization,omitempty"`
+	Changes      *ChangeSummary        `json:"changes,omitempty"`
+}
+
+// PayloadRun is the run information carried by the payload.
+type PayloadRun struct {
+	ID                uint      `json:"id"`
+	Type              string    `json:"type"`
+	Status            string    `json:"status"`
+	Workspace         string    `json:"workspace"`
+	CreationTimestamp time.Time `json:"creationTimestamp,omitempty"`
+	UpdateTimestamp   time.Time `json:"updateTimestamp,omitempty"`
+}
+
+// PayloadObject identifies the stack, project or organization of the run.
+type PayloadObject struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	Path string `json:"path,omitempty"`
+}
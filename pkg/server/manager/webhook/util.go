@@ -0,0 +1,47 @@
+package webhook
+
+import (
+	"fmt"
+
+	"kusionstack.io/kusion/pkg/domain/constant"
+	"kusionstack.io/kusion/pkg/domain/entity"
+)
+
+// maskWebhookSensitiveData returns a copy of the webhook with the secret masked.
+func maskWebhookSensitiveData(webhook *entity.Webhook) (*entity.Webhook, error) {
+	if webhook == nil {
+		return nil, ErrInternalServerError
+	}
+
+	masked := *webhook
+	if masked.Secret != "" {
+		masked.Secret = maskedSecret
+	}
+	return &masked, nil
+}
+
+func validateWebhookSortOptions(sortBy string) (string, error) {
+	if sortBy == "" {
+		return constant.SortByID, nil
+	}
+	if sortBy != constant.SortByID && sortBy != constant.SortByName && sortBy != constant.SortByCreateTimestamp {
+		return "", fmt.Errorf("invalid sort option: %s. Can only sort by id, name or create timestamp", sortBy)
+	}
+	if sortBy == constant.SortByCreateTimestamp {
+		return "created_at", nil
+	}
+	return sortBy, nil
+}
+
+func validateWebhookDeliverySortOptions(sortBy string) (string, error) {
+	if sortBy == "" {
+		return constant.SortByID, nil
+	}
+	if sortBy != constant.SortByID && sortBy != constant.SortByCreateTimestamp {
+		return "", fmt.Errorf("invalid sort option: %s. Can only sort by id or create timestamp", sortBy)
+	}
+	if sortBy == constant.SortByCreateTimestamp {
+		return "created_at", nil
+	}
+	return sortBy, nil
+}
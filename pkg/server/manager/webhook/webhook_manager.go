@@ -0,0 +1,277 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+
+	"github.com/jinzhu/copier"
+	"gorm.io/gorm"
+	"kusionstack.io/kusion/pkg/domain/constant"
+	"kusionstack.io/kusion/pkg/domain/entity"
+	"kusionstack.io/kusion/pkg/domain/request"
+	"kusionstack.io/kusion/pkg/infra/encryption"
+	logutil "kusionstack.io/kusion/pkg/server/util/logging"
+)
+
+func (m *WebhookManager) ListWebhooks(ctx context.Context, filter *entity.WebhookFilter, sortOptions *entity.SortOptions) (*entity.WebhookListResult, error) {
+	webhookEntities, err := m.webhookRepo.List(ctx, filter, sortOptions)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGettingNonExistingWebhook
+		}
+		return nil, err
+	}
+
+	for i, webhookEntity := range webhookEntities.Webhooks {
+		masked, err := maskWebhookSensitiveData(webhookEntity)
+		if err != nil {
+			return nil, err
+		}
+		webhookEntities.Webhooks[i] = masked
+	}
+	return webhookEntities, nil
+}
+
+func (m *WebhookManager) GetWebhookByID(ctx context.Context, id uint) (*entity.Webhook, error) {
+	existingEntity, err := m.webhookRepo.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGettingNonExistingWebhook
+		}
+		return nil, err
+	}
+
+	return maskWebhookSensitiveData(existingEntity)
+}
+
+func (m *WebhookManager) DeleteWebhookByID(ctx context.Context, id uint) error {
+	err := m.webhookRepo.Delete(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrGettingNonExistingWebhook
+		}
+		return err
+	}
+	return nil
+}
+
+func (m *WebhookManager) UpdateWebhookByID(ctx context.Context, id uint, requestPayload request.UpdateWebhookRequest) (*entity.Webhook, error) {
+	// Convert request payload to domain model
+	var requestEntity entity.Webhook
+	if err := copier.Copy(&requestEntity, &requestPayload); err != nil {
+		return nil, err
+	}
+	// Ignore the masked secret echoed back by clients.
+	if requestEntity.Secret == maskedSecret {
+		requestEntity.Secret = ""
+	}
+	if err := m.encryptSecret(ctx, &requestEntity); err != nil {
+		return nil, err
+	}
+
+	// Get the existing webhook by id
+	updatedEntity, err := m.webhookRepo.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUpdatingNonExistingWebhook
+		}
+		return nil, err
+	}
+
+	// Overwrite non-zero values in request entity to existing entity
+	copier.CopyWithOption(updatedEntity, requestEntity, copier.Option{IgnoreEmpty: true})
+
+	// Zero values are meaningful for the scope and the enabled flag, so they
+	// are only skipped when omitted from the request.
+	if requestPayload.OrganizationID != nil {
+		updatedEntity.OrganizationID = *requestPayload.OrganizationID
+	}
+	if requestPayload.ProjectID != nil {
+		updatedEntity.ProjectID = *requestPayload.ProjectID
+	}
+	if requestPayload.StackID != nil {
+		updatedEntity.StackID = *requestPayload.StackID
+	}
+	if requestPayload.Enabled != nil {
+		updatedEntity.Enabled = *requestPayload.Enabled
+	}
+	if err = updatedEntity.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Update webhook with repository
+	err = m.webhookRepo.Update(ctx, updatedEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	return maskWebhookSensitiveData(updatedEntity)
+}
+
+func (m *WebhookManager) CreateWebhook(ctx context.Context, requestPayload request.CreateWebhookRequest) (*entity.Webhook, error) {
+	// Convert request payload to domain model
+	var createdEntity entity.Webhook
+	if err := copier.Copy(&createdEntity, &requestPayload); err != nil {
+		return nil, err
+	}
+	format, err := constant.ParseWebhookFormat(requestPayload.Format)
+	if err != nil {
+		return nil, err
+	}
+	createdEntity.Format = format
+	if err = m.encryptSecret(ctx, &createdEntity); err != nil {
+		return nil, err
+	}
+
+	// Create webhook with repository
+	if err = m.webhookRepo.Create(ctx, &createdEntity); err != nil {
+		return nil, err
+	}
+
+	return maskWebhookSensitiveData(&createdEntity)
+}
+
+// encryptSecret encrypts the secret of the webhook if the encryption is
+// enabled, an empty secret is kept as is.
+func (m *WebhookManager) encryptSecret(ctx context.Context, webhook *entity.Webhook) error {
+	if m.encrypter == nil || webhook.Secret == "" {
+		return nil
+	}
+	encrypted, err := m.encrypter.Encrypt(ctx, webhook.Secret)
+	if err != nil {
+		return err
+	}
+	webhook.Secret = encrypted
+	return nil
+}
+
+// signingSecret returns the plain text secret to sign the payloads with. The
+// secrets stored before the encryption is enabled are in plain text.
+func (m *WebhookManager) signingSecret(ctx context.Context, webhook *entity.Webhook) (string, error) {
+	if !encryption.IsEncrypted(webhook.Secret) {
+		return webhook.Secret, nil
+	}
+	if m.encrypter == nil {
+		return "", ErrWebhookEncryptionDisabled
+	}
+	return m.encrypter.Decrypt(ctx, webhook.Secret)
+}
+
+func (m *WebhookManager) ListWebhookDeliveries(ctx context.Context, filter *entity.WebhookDeliveryFilter, sortOptions *entity.SortOptions) (*entity.WebhookDeliveryListResult, error) {
+	// Make sure the webhook exists before listing its deliveries.
+	if _, err := m.webhookRepo.Get(ctx, filter.WebhookID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGettingNonExistingWebhook
+		}
+		return nil, err
+	}
+
+	return m.deliveryRepo.List(ctx, filter, sortOptions)
+}
+
+func (m *WebhookManager) BuildWebhookFilterAndSortOptions(ctx context.Context, query *url.Values) (*entity.WebhookFilter, *entity.SortOptions, error) {
+	logger := logutil.GetLogger(ctx)
+	logger.Info("Building webhook filter...")
+
+	filter := entity.WebhookFilter{}
+
+	orgIDParam := query.Get("orgID")
+	if orgIDParam != "" {
+		orgID, err := strconv.Atoi(orgIDParam)
+		if err != nil {
+			return nil, nil, constant.ErrInvalidOrganizationID
+		}
+		filter.OrganizationID = uint(orgID)
+	}
+	projectIDParam := query.Get("projectID")
+	if projectIDParam != "" {
+		projectID, err := strconv.Atoi(projectIDParam)
+		if err != nil {
+			return nil, nil, constant.ErrInvalidProjectID
+		}
+		filter.ProjectID = uint(projectID)
+	}
+	stackIDParam := query.Get("stackID")
+	if stackIDParam != "" {
+		stackID, err := strconv.Atoi(stackIDParam)
+		if err != nil {
+			return nil, nil, constant.ErrInvalidStackID
+		}
+		filter.StackID = uint(stackID)
+	}
+
+	// Set pagination parameters.
+	page, _ := strconv.Atoi(query.Get("page"))
+	if page <= 0 {
+		page = constant.CommonPageDefault
+	}
+	pageSize, _ := strconv.Atoi(query.Get("pageSize"))
+	if pageSize <= 0 {
+		pageSize = constant.CommonPageSizeDefault
+	}
+	filter.Pagination = &entity.Pagination{
+		Page:     page,
+		PageSize: pageSize,
+	}
+
+	// Build sort options
+	sortBy, err := validateWebhookSortOptions(query.Get("sortBy"))
+	if err != nil {
+		return nil, nil, err
+	}
+	SortOrderAscending, _ := strconv.ParseBool(query.Get("ascending"))
+	webhookSortOptions := &entity.SortOptions{
+		Field:     sortBy,
+		Ascending: SortOrderAscending,
+	}
+
+	return &filter, webhookSortOptions, nil
+}
+
+func (m *WebhookManager) BuildWebhookDeliveryFilterAndSortOptions(ctx context.Context, webhookID uint, query *url.Values) (*entity.WebhookDeliveryFilter, *entity.SortOptions, error) {
+	logger := logutil.GetLogger(ctx)
+	logger.Info("Building webhook delivery filter...")
+
+	filter := entity.WebhookDeliveryFilter{
+		WebhookID: webhookID,
+		Status:    query.Get("status"),
+	}
+
+	runIDParam := query.Get("runID")
+	if runIDParam != "" {
+		runID, err := strconv.Atoi(runIDParam)
+		if err != nil {
+			return nil, nil, ErrInvalidRunID
+		}
+		filter.RunID = uint(runID)
+	}
+
+	// Set pagination parameters.
+	page, _ := strconv.Atoi(query.Get("page"))
+	if page <= 0 {
+		page = constant.CommonPageDefault
+	}
+	pageSize, _ := strconv.Atoi(query.Get("pageSize"))
+	if pageSize <= 0 {
+		pageSize = constant.CommonPageSizeDefault
+	}
+	filter.Pagination = &entity.Pagination{
+		Page:     page,
+		PageSize: pageSize,
+	}
+
+	// Build sort options
+	sortBy, err := validateWebhookDeliverySortOptions(query.Get("sortBy"))
+	if err != nil {
+		return nil, nil, err
+	}
+	SortOrderAscending, _ := strconv.ParseBool(query.Get("ascending"))
+	deliverySortOptions := &entity.SortOptions{
+		Field:     sortBy,
+		Ascending: SortOrderAscending,
+	}
+
+	return &filter, deliverySortOptions, nil
+}
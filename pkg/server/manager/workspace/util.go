@@ -20,7 +20,10 @@ func NewBackendFromEntity(backendEntity entity.Backend) (backend.Backend, error)
 		if err = storages.CompleteLocalConfig(bkConfig); err != nil {
 			return nil, fmt.Errorf("complete local config failed, %w", err)
 		}
-		return storages.NewLocalStorage(bkConfig), nil
+		storage, err = storages.NewLocalStorage(bkConfig)
+		if err != nil {
+			return nil, fmt.Errorf("new local storage of backend %s failed, %w", backendEntity.Name, err)
+		}
 	case v1.BackendTypeOss:
 		bkConfig := backendEntity.BackendConfig.ToOssBackend()
 		storages.CompleteOssConfig(bkConfig)
@@ -47,6 +50,32 @@ func NewBackendFromEntity(backendEntity entity.Backend) (backend.Backend, error)
 		if err != nil {
 			return nil, fmt.Errorf("new google storage of backend %s failed, %w", backendEntity.Name, err)
 		}
+	case v1.BackendTypeAzure:
+		bkConfig := backendEntity.BackendConfig.ToAzureBackend()
+		storages.CompleteAzureConfig(bkConfig)
+		if err = storages.ValidateAzureConfig(bkConfig); err != nil {
+			return nil, fmt.Errorf("invalid config of backend %s, %w", backendEntity.Name, err)
+		}
+		storage, err = storages.NewAzureStorage(bkConfig)
+		if err != nil {
+			return nil, fmt.Errorf("new azure storage of backend %s failed, %w", backendEntity.Name, err)
+		}
+	case v1.BackendTypeHTTP:
+		bkConfig := backendEntity.BackendConfig.ToHTTPBackend()
+		if err = storages.ValidateHTTPConfig(bkConfig); err != nil {
+			return nil, fmt.Errorf("invalid config of backend %s, %w", backendEntity.Name, err)
+		}
+		storage, err = storages.NewHTTPStorage(bkConfig)
+		if err != nil {
+			return nil, fmt.Errorf("new http storage of backend %s failed, %w", backendEntity.Name, err)
+		}
+	case v1.BackendTypeKubernetes:
+		bkConfig := backendEntity.BackendConfig.ToKubernetesBackend()
+		storages.CompleteKubernetesConfig(bkConfig)
+		storage, err = storages.NewKubernetesStorage(bkConfig)
+		if err != nil {
+			return nil, fmt.Errorf("new kubernetes storage of backend %s failed, %w", backendEntity.Name, err)
+		}
 	default:
 		return nil, fmt.Errorf("invalid type %s of backend %s", backendEntity.BackendConfig.Type, backendEntity.Name)
 	}
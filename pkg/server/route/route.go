@@ -3,6 +3,7 @@ package route
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"expvar"
 	"fmt"
 	"io"
@@ -27,16 +28,19 @@ import (
 	"kusionstack.io/kusion/pkg/server/handler/stack"
 	"kusionstack.io/kusion/pkg/server/handler/variable"
 	"kusionstack.io/kusion/pkg/server/handler/variableset"
+	"kusionstack.io/kusion/pkg/server/handler/webhook"
 	"kusionstack.io/kusion/pkg/server/handler/workspace"
 	backendmanager "kusionstack.io/kusion/pkg/server/manager/backend"
 	modulemanager "kusionstack.io/kusion/pkg/server/manager/module"
 	organizationmanager "kusionstack.io/kusion/pkg/server/manager/organization"
 	projectmanager "kusionstack.io/kusion/pkg/server/manager/project"
 	resourcemanager "kusionstack.io/kusion/pkg/server/manager/resource"
+	runqueue "kusionstack.io/kusion/pkg/server/manager/runqueue"
 	sourcemanager "kusionstack.io/kusion/pkg/server/manager/source"
 	stackmanager "kusionstack.io/kusion/pkg/server/manager/stack"
 	variablemanager "kusionstack.io/kusion/pkg/server/manager/variable"
 	variablesetmanager "kusionstack.io/kusion/pkg/server/manager/variableset"
+	webhookmanager "kusionstack.io/kusion/pkg/server/manager/webhook"
 	workspacemanager "kusionstack.io/kusion/pkg/server/manager/workspace"
 	appmiddleware "kusionstack.io/kusion/pkg/server/middleware"
 	authutil "kusionstack.io/kusion/pkg/server/util/auth"
@@ -45,8 +49,9 @@ import (
 )
 
 // NewCoreRoute creates and configures an instance of chi.Mux with the given
-// configuration and extra configuration parameters.
-func NewCoreRoute(config *server.Config) (*chi.Mux, error) {
+// configuration and extra configuration parameters. The server is shut down
+// gracefully once the context is done.
+func NewCoreRoute(ctx context.Context, config *server.Config) (*chi.Mux, error) {
 	router := chi.NewRouter()
 	logger := logutil.GetLogger(context.TODO())
 
@@ -70,8 +75,9 @@ func NewCoreRoute(config *server.Config) (*chi.Mux, error) {
 	router.Mount("/debug", middleware.Profiler())
 
 	// Set up the API routes for version 1 of the API.
+	var shutdownAPIV1 func()
 	router.Route("/api/v1", func(r chi.Router) {
-		setupRestAPIV1(r, config)
+		shutdownAPIV1 = setupRestAPIV1(ctx, r, config)
 	})
 
 	// Set up the root routes.
@@ -128,11 +134,23 @@ func NewCoreRoute(config *server.Config) (*chi.Mux, error) {
 		})
 	}
 
-	err := http.ListenAndServe(fmt.Sprintf(":%d", config.Port), router)
-	if err != nil {
+	srv := &http.Server{Addr: fmt.Sprintf(":%d", config.Port), Handler: router}
+	go func() {
+		<-ctx.Done()
+		logger.Info("Shutting down server...")
+		if err := srv.Shutdown(context.Background()); err != nil {
+			logger.Error(fmt.Sprintf("Error shutting down server: %s", err))
+		}
+	}()
+	err := srv.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		logger.Error(fmt.Sprintf("Error starting server: %s", err))
 		return router, err
 	}
+	// Wait for the runs in progress before exiting.
+	if shutdownAPIV1 != nil {
+		shutdownAPIV1()
+	}
 
 	logger.Info(fmt.Sprintf("Listening on :%d", config.Port))
 	logger.Info("Server Started...")
@@ -141,11 +159,13 @@ func NewCoreRoute(config *server.Config) (*chi.Mux, error) {
 }
 
 // setupRestAPIV1 configures routing for the API version 1, grouping routes by
-// resource type and setting up proper handlers.
+// resource type and setting up proper handlers. It returns the function to
+// shut down the handlers, or nil if the setup fails.
 func setupRestAPIV1(
+	ctx context.Context,
 	r chi.Router,
 	config *server.Config,
-) {
+) func() {
 	// Set up the logger for the API.
 	logger := logutil.GetLogger(context.TODO())
 	logger.Info("Setting up REST API v1...")
@@ -153,12 +173,12 @@ func setupRestAPIV1(
 	if config.AuthEnabled {
 		if len(config.AuthWhitelist) == 0 {
 			logger.Info("Auth enabled but whitelist is not set up. Exiting...")
-			return
+			return nil
 		}
 		keyMap, err := authutil.GetJWKSMapFromIAM(context.TODO(), config.AuthKeyType)
 		if err != nil {
 			logger.Info("Error getting JWKS Map from IAM...")
-			return
+			return nil
 		}
 		r.Use(appmiddleware.TokenAuthMiddleware(keyMap, config.AuthWhitelist, config.LogFilePath))
 		logger.Info("Token authorization enabled for REST API v1...")
@@ -166,10 +186,10 @@ func setupRestAPIV1(
 
 	// Set up the persistence layer.
 	if config.DB != nil && config.AutoMigrate {
-		err := persistence.AutoMigrate(config.DB)
+		err := persistence.Migrate(config.DB)
 		if err != nil {
 			logger.Error(err.Error(), "error", "Error auto migrating...")
-			return
+			return nil
 		}
 	}
 	organizationRepo := persistence.NewOrganizationRepository(config.DB)
@@ -183,8 +203,11 @@ func setupRestAPIV1(
 	runRepo := persistence.NewRunRepository(config.DB)
 	variablesetRepo := persistence.NewVariableSetRepository(config.DB)
 	variableRepo := persistence.NewVariableRepository(config.DB)
+	webhookRepo := persistence.NewWebhookRepository(config.DB)
+	webhookDeliveryRepo := persistence.NewWebhookDeliveryRepository(config.DB)
 
-	stackManager := stackmanager.NewStackManager(stackRepo, projectRepo, workspaceRepo, resourceRepo, runRepo, config.DefaultBackend, config.MaxConcurrent)
+	webhookManager := webhookmanager.NewWebhookManager(webhookRepo, webhookDeliveryRepo, config.VariableEncrypter)
+	stackManager := stackmanager.NewStackManager(stackRepo, projectRepo, workspaceRepo, resourceRepo, runRepo, variablesetRepo, variableRepo, config.VariableEncrypter, config.DefaultBackend, config.MaxConcurrent)
 	sourceManager := sourcemanager.NewSourceManager(sourceRepo)
 	organizationManager := organizationmanager.NewOrganizationManager(organizationRepo)
 	backendManager := backendmanager.NewBackendManager(backendRepo)
@@ -193,58 +216,69 @@ func setupRestAPIV1(
 	resourceManager := resourcemanager.NewResourceManager(resourceRepo)
 	moduleManager := modulemanager.NewModuleManager(moduleRepo, workspaceRepo, backendRepo)
 	variableSetManager := variablesetmanager.NewVariableSetManager(variablesetRepo)
-	variableManager := variablemanager.NewVariableManager(variableRepo)
+	variableManager := variablemanager.NewVariableManager(variableRepo, config.VariableEncrypter)
 
 	// Set up the handlers for the resources.
 	sourceHandler, err := source.NewHandler(sourceManager)
 	if err != nil {
 		logger.Error(err.Error(), "Error creating source handler...", "error", err)
-		return
+		return nil
 	}
 	orgHandler, err := organization.NewHandler(organizationManager)
 	if err != nil {
 		logger.Error(err.Error(), "Error creating org handler...", "error", err)
-		return
+		return nil
 	}
 	projectHandler, err := project.NewHandler(projectManager)
 	if err != nil {
 		logger.Error(err.Error(), "Error creating project handler...", "error", err)
-		return
+		return nil
 	}
-	stackHandler, err := stack.NewHandler(stackManager, config.MaxAsyncConcurrent, config.MaxAsyncBuffer)
+	runQueue := runqueue.NewRunQueue(runRepo, config.MaxAsyncConcurrent, config.RunRecoveryPolicy)
+	stackHandler, err := stack.NewHandler(stackManager, webhookManager, runQueue, config.LogFilePath)
 	if err != nil {
 		logger.Error(err.Error(), "Error creating stack handler...", "error", err)
-		return
+		return nil
+	}
+	// Recover the runs orphaned by a previous server and start executing the
+	// queued runs once the persistence layer is available.
+	if config.DB != nil {
+		runQueue.Start(ctx, stackHandler)
 	}
 	workspaceHandler, err := workspace.NewHandler(workspaceManager)
 	if err != nil {
 		logger.Error(err.Error(), "Error creating workspace handler...", "error", err)
-		return
+		return nil
 	}
 	backendHandler, err := backend.NewHandler(backendManager)
 	if err != nil {
 		logger.Error(err.Error(), "Error creating backend handler...", "error", err)
-		return
+		return nil
 	}
 	resourceHandler, err := resource.NewHandler(resourceManager)
 	if err != nil {
 		logger.Error(err.Error(), "Error creating resource handler...", "error", err)
-		return
+		return nil
 	}
 	moduleHandler, err := module.NewHandler(moduleManager)
 	if err != nil {
 		logger.Error(err.Error(), "Error creating module handler", "error", err)
-		return
+		return nil
 	}
 	variableSetHandler, err := variableset.NewHandler(variableSetManager)
 	if err != nil {
 		logger.Error(err.Error(), "Error creating variable set handler", "error", err)
-		return
+		return nil
 	}
 	variableHandler, err := variable.NewHandler(variableManager)
 	if err != nil {
 		logger.Error(err.Error(), "Error creating variable handler", "error", err)
-		return
+		return nil
+	}
+	webhookHandler, err := webhook.NewHandler(webhookManager)
+	if err != nil {
+		logger.Error(err.Error(), "Error creating webhook handler", "error", err)
+		return nil
 	}
 
 	// Set up the routes for the resources.
@@ -275,6 +309,7 @@ func setupRestAPIV1(
 			r.Post("/apply/async", stackHandler.ApplyStackAsync())
 			r.Post("/destroy", stackHandler.DestroyStack())
 			r.Post("/destroy/async", stackHandler.DestroyStackAsync())
+			r.Get("/releases/diff", stackHandler.DiffReleases())
 			// r.Route("/variable", func(r chi.Router) {
 			// 	r.Post("/", stackHandler.UpdateStackVariable())
 			// })
@@ -366,6 +401,7 @@ func setupRestAPIV1(
 	r.Route("/variables", func(r chi.Router) {
 		r.Post("/", variableHandler.CreateVariable())
 		r.Get("/", variableHandler.ListVariables())
+		r.Post("/rotate-keys", variableHandler.RotateVariableKeys())
 		r.Route("/{variableSetName}", func(r chi.Router) {
 			r.Route("/{variableName}", func(r chi.Router) {
 				r.Delete("/", variableHandler.DeleteVariable())
@@ -374,4 +410,17 @@ func setupRestAPIV1(
 			})
 		})
 	})
+	r.Route("/webhooks", func(r chi.Router) {
+		r.Route("/{webhookID}", func(r chi.Router) {
+			r.Get("/", webhookHandler.GetWebhook())
+			r.Put("/", webhookHandler.UpdateWebhook())
+			r.Delete("/", webhookHandler.DeleteWebhook())
+			r.Get("/deliveries", webhookHandler.ListWebhookDeliveries())
+			r.Post("/ping", webhookHandler.PingWebhook())
+		})
+		r.Post("/", webhookHandler.CreateWebhook())
+		r.Get("/", webhookHandler.ListWebhooks())
+	})
+
+	return stackHandler.Shutdown
 }
@@ -1,6 +1,7 @@
 package route
 
 import (
+	"context"
 	"testing"
 
 	"github.com/go-chi/chi/v5"
@@ -78,7 +79,7 @@ func TestSetupRestAPIV1(t *testing.T) {
 	}
 
 	r := chi.NewRouter()
-	setupRestAPIV1(r, config)
+	setupRestAPIV1(context.Background(), r, config)
 
 	// Add your assertions here
 }
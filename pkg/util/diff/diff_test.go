@@ -309,3 +309,47 @@ func TestMaskSensitiveData(t *testing.T) {
 		})
 	}
 }
+
+func TestToUnifiedString(t *testing.T) {
+	oldData := &v1.Resource{
+		Type: v1.Kubernetes,
+		Attributes: map[string]interface{}{
+			"kind": "Secret",
+			"data": map[string]interface{}{
+				"key": "dmFsdWUK",
+			},
+		},
+	}
+	newData := &v1.Resource{
+		Type: v1.Kubernetes,
+		Attributes: map[string]interface{}{
+			"kind": "Secret",
+			"data": map[string]interface{}{
+				"key": "dmFsdWUtY2hhbmdlZAo=",
+			},
+		},
+	}
+
+	t.Run("update", func(t *testing.T) {
+		actual, err := ToUnifiedString(oldData, newData, "a/secret", "b/secret")
+		assert.Nil(t, err)
+		assert.Contains(t, actual, "--- a/secret\n+++ b/secret\n")
+		assert.Contains(t, actual, "-    key: '***before***'\n")
+		assert.Contains(t, actual, "+    key: '***after****'\n")
+		assert.NotContains(t, actual, "dmFsdWUK")
+	})
+
+	t.Run("create", func(t *testing.T) {
+		var nilResource *v1.Resource
+		actual, err := ToUnifiedString(nilResource, newData, "/dev/null", "b/secret")
+		assert.Nil(t, err)
+		assert.Contains(t, actual, "--- /dev/null\n+++ b/secret\n@@ -0,0 +1,")
+		assert.NotContains(t, actual, "\n-")
+	})
+
+	t.Run("no diff", func(t *testing.T) {
+		actual, err := ToUnifiedString(oldData, oldData, "a/secret", "b/secret")
+		assert.Nil(t, err)
+		assert.Empty(t, actual)
+	})
+}
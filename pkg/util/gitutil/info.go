@@ -13,6 +13,8 @@ type Info struct {
 	RemoteURL  string
 	Commit     string
 	CommitDate string
+	Branch     string
+	Dirty      bool
 }
 
 // Get returns the overall codebase version.
@@ -41,5 +43,17 @@ func Get(repoRoot string) (info Info) {
 		info.Commit = strings.TrimSuffix(string(commit), "\n")
 	}
 
+	branchCmd := exec.CommandContext(ctx, "git", "symbolic-ref", "--short", "-q", "HEAD")
+	branchCmd.Dir = repoRoot
+	if branch, err := branchCmd.Output(); err == nil && len(branch) > 1 {
+		info.Branch = strings.TrimSuffix(string(branch), "\n")
+	}
+
+	statusCmd := exec.CommandContext(ctx, "git", "status", "--porcelain")
+	statusCmd.Dir = repoRoot
+	if status, err := statusCmd.Output(); err == nil {
+		info.Dirty = strings.TrimSpace(string(status)) != ""
+	}
+
 	return info
 }
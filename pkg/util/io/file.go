@@ -72,6 +72,40 @@ func RenamePath(oldPath, newPath string) error {
 	return nil
 }
 
+// WriteFileAtomic writes the content to the file at path through a temporary
+// file in the same directory renamed to path, so that the file is never left
+// partially written. The mode of the existing file is kept, otherwise the file
+// mode is set to perm as is rather than masked by the umask.
+func WriteFileAtomic(path string, content []byte, perm os.FileMode) (err error) {
+	if info, statErr := os.Stat(path); statErr == nil {
+		perm = info.Mode().Perm()
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = f.Close()
+			_ = os.Remove(f.Name())
+		}
+	}()
+
+	if _, err = f.Write(content); err != nil {
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		return err
+	}
+	if err = f.Chmod(perm); err != nil {
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
 // CopyFile copies the file at source to dest
 func CopyFile(source, dest string) error {
 	sf, err := os.Open(source)
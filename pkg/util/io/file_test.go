@@ -2,6 +2,7 @@ package io
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/bytedance/mockey"
@@ -169,3 +170,29 @@ func TestRenamePath(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "1.yaml")
+	assert.NoError(t, WriteFileAtomic(path, []byte("revision: 1"), 0o600))
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// The mode of the existing file is kept.
+	assert.NoError(t, os.Chmod(path, 0o755))
+	assert.NoError(t, WriteFileAtomic(path, []byte("revision: 1\nphase: succeeded"), 0o644))
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "revision: 1\nphase: succeeded", string(content))
+	info, err = os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o755), info.Mode().Perm())
+
+	// No temporary file is left.
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	assert.Error(t, WriteFileAtomic(filepath.Join(dir, "missing", "1.yaml"), []byte("revision: 1"), 0o644))
+}
@@ -0,0 +1,481 @@
+// Copyright 2024 KusionStack Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mask replaces the sensitive values of resources with placeholders
+// before they are shown in diffs, written to logs or returned by the APIs.
+//
+// The sensitive values are located by the paths in the masking rules, which
+// come from three sources: the defaults of the well-known resource kinds, the
+// sensitive attributes reported by the Terraform providers, and the rules
+// configured in the workspace context.
+package mask
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	apiv1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+// ContextKey is the key of the workspace context holding the masking rules,
+// which are applied in addition to the default ones.
+//
+// Example:
+//
+//	context:
+//	  masking:
+//	    rules:
+//	      - type: Terraform
+//	        kind: alicloud_db_account
+//	        paths: [attributes.account_password]
+//	      - type: Kubernetes
+//	        kind: ConfigMap
+//	        paths: [attributes.data.token]
+//	      - type: Context
+//	        paths: [database.password]
+const ContextKey = "masking"
+
+// SensitiveAttributesKey is the key of the resource extensions holding the
+// paths of the sensitive attributes, relative to the attributes, which are
+// reported by the Terraform provider schema.
+const SensitiveAttributesKey = "sensitiveAttributes"
+
+// Wildcard matches any key of a map or any element of a list in a path.
+const Wildcard = "*"
+
+// ContextType is the type of the rules locating the sensitive values in the
+// workspace context carried by the spec, whose paths are relative to the
+// context, such as `providerEnvs.*`.
+const ContextType apiv1.Type = "Context"
+
+// Placeholders for masking sensitive information.
+const (
+	maskStr       = "*******"
+	maskStrBefore = "***before***"
+	maskStrAfter  = "***after****"
+)
+
+// Rule locates the sensitive values of a kind of resources.
+type Rule struct {
+	// Type is the type of the resources, matching all types if empty.
+	Type apiv1.Type `json:"type,omitempty" yaml:"type,omitempty"`
+	// Kind is the kind of the Kubernetes resources or the resource type of
+	// the Terraform resources, matching all kinds if empty.
+	Kind string `json:"kind,omitempty" yaml:"kind,omitempty"`
+	// Paths are the dot-separated paths of the sensitive values in the
+	// resource, such as `attributes.data.*`, where `*` matches any key or
+	// element. A path to a map or list masks it as a whole.
+	Paths []string `json:"paths" yaml:"paths"`
+}
+
+// Policy is the set of the masking rules.
+type Policy struct {
+	Rules []Rule `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
+// DefaultRules are the masking rules always applied.
+var DefaultRules = []Rule{
+	{
+		Type:  apiv1.Kubernetes,
+		Kind:  "Secret",
+		Paths: []string{"attributes.data.*", "attributes.stringData.*"},
+	},
+	{
+		Type: apiv1.Terraform,
+		Paths: []string{
+			"extensions.providerMeta.access_key",
+			"extensions.providerMeta.secret_key",
+			"extensions.providerMeta.token",
+			"extensions.providerMeta.password",
+			"extensions.providerMeta.client_secret",
+			"extensions.providerMeta.credentials",
+		},
+	},
+	{
+		Type:  ContextType,
+		Paths: []string{apiv1.ProviderEnvs + "." + Wildcard},
+	},
+}
+
+var (
+	defaultPolicy     = &Policy{Rules: DefaultRules}
+	defaultPolicyLock sync.RWMutex
+)
+
+// Default returns the policy used where the workspace is unknown, which has
+// only the default rules unless set by SetDefault.
+func Default() *Policy {
+	defaultPolicyLock.RLock()
+	defer defaultPolicyLock.RUnlock()
+	return defaultPolicy
+}
+
+// SetDefault sets the policy used where the workspace is unknown. It is meant
+// for the CLI, which works on a single workspace in a process.
+func SetDefault(p *Policy) {
+	if p == nil {
+		p = &Policy{Rules: DefaultRules}
+	}
+	defaultPolicyLock.Lock()
+	defer defaultPolicyLock.Unlock()
+	defaultPolicy = p
+}
+
+// PolicyFromContext returns the policy of the default rules and the rules in
+// the workspace context carried by the spec.
+func PolicyFromContext(ctx apiv1.GenericConfig) (*Policy, error) {
+	p := &Policy{Rules: append([]Rule{}, DefaultRules...)}
+	raw, ok := ctx[ContextKey]
+	if !ok || raw == nil {
+		return p, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	configured := &Policy{}
+	if err = json.Unmarshal(data, configured); err != nil {
+		return nil, fmt.Errorf("invalid %s in the workspace context: %w", ContextKey, err)
+	}
+	for _, rule := range configured.Rules {
+		if len(rule.Paths) == 0 {
+			return nil, fmt.Errorf("invalid %s in the workspace context: no paths in the rule of %s %s", ContextKey, rule.Type, rule.Kind)
+		}
+	}
+	p.Rules = append(p.Rules, configured.Rules...)
+	return p, nil
+}
+
+// Mask masks the sensitive values of the resources before and after a change,
+// and returns the masked copies. The different values at the same path are
+// masked with different placeholders, so that the change is still visible.
+// Anything other than a resource is returned as is.
+func (p *Policy) Mask(oldData, newData interface{}) (interface{}, interface{}) {
+	if p == nil {
+		p = Default()
+	}
+	from, ok1 := asResource(oldData)
+	to, ok2 := asResource(newData)
+	if !ok1 || !ok2 {
+		return oldData, newData
+	}
+
+	fromObj, fromPaths, err1 := p.sensitive(from)
+	toObj, toPaths, err2 := p.sensitive(to)
+	if err1 != nil || err2 != nil {
+		// Show nothing but the identities if the sensitive values can't be
+		// located, rather than the values as they are.
+		return redact(oldData, from), redact(newData, to)
+	}
+	if fromObj == nil && toObj == nil {
+		return oldData, newData
+	}
+
+	// Decide all the placeholders before masking, since the values at the
+	// same path on the other side are compared.
+	fromMasks := placeholders(fromObj, fromPaths, toObj, maskStrBefore)
+	toMasks := placeholders(toObj, toPaths, fromObj, maskStrAfter)
+
+	var maskedOld, maskedNew interface{} = oldData, newData
+	if fromObj != nil {
+		for i, path := range fromPaths {
+			set(fromObj, path, fromMasks[i])
+		}
+		maskedOld = toResource(fromObj)
+	}
+	if toObj != nil {
+		for i, path := range toPaths {
+			set(toObj, path, toMasks[i])
+		}
+		maskedNew = toResource(toObj)
+	}
+	return maskedOld, maskedNew
+}
+
+// placeholders returns the placeholder of the value at each path, which is
+// changed if the other object has a different value at the path.
+func placeholders(obj map[string]interface{}, paths [][]string, other map[string]interface{}, changed string) []string {
+	masks := make([]string, len(paths))
+	for i, path := range paths {
+		masks[i] = maskStr
+		v, _ := get(obj, path)
+		if otherV, ok := get(other, path); ok && !equal(v, otherV) {
+			masks[i] = changed
+		}
+	}
+	return masks
+}
+
+// MaskResource returns a copy of the resource with the sensitive values masked.
+func (p *Policy) MaskResource(r *apiv1.Resource) *apiv1.Resource {
+	masked, _ := p.Mask(r, r)
+	return masked.(*apiv1.Resource)
+}
+
+// MaskResources returns a copy of the resources with the sensitive values masked.
+func (p *Policy) MaskResources(resources apiv1.Resources) apiv1.Resources {
+	if resources == nil {
+		return nil
+	}
+	masked := make(apiv1.Resources, len(resources))
+	for i := range resources {
+		masked[i] = *p.MaskResource(&resources[i])
+	}
+	return masked
+}
+
+// MaskContext returns a copy of the workspace context with the sensitive
+// values masked by the rules of ContextType. All the values are masked if the
+// context can't be inspected.
+func (p *Policy) MaskContext(ctx apiv1.GenericConfig) apiv1.GenericConfig {
+	if ctx == nil {
+		return nil
+	}
+	if p == nil {
+		p = Default()
+	}
+	obj := map[string]interface{}{}
+	data, err := json.Marshal(ctx)
+	if err == nil {
+		err = json.Unmarshal(data, &obj)
+	}
+	if err != nil {
+		masked := make(apiv1.GenericConfig, len(ctx))
+		for k := range ctx {
+			masked[k] = maskStr
+		}
+		return masked
+	}
+	for _, rule := range p.Rules {
+		if rule.Type != ContextType {
+			continue
+		}
+		for _, path := range rule.Paths {
+			for _, concrete := range expand(obj, strings.Split(path, ".")) {
+				set(obj, concrete, maskStr)
+			}
+		}
+	}
+	return obj
+}
+
+// redact returns a copy of the resource without anything but its identity,
+// or the data as is for a nil resource.
+func redact(data interface{}, r *apiv1.Resource) interface{} {
+	if r == nil {
+		return data
+	}
+	return &apiv1.Resource{ID: r.ID, Type: r.Type, DependsOn: r.DependsOn}
+}
+
+// asResource converts the data to a resource, regarding an untyped nil as a
+// nil resource.
+func asResource(data interface{}) (*apiv1.Resource, bool) {
+	if data == nil {
+		return nil, true
+	}
+	r, ok := data.(*apiv1.Resource)
+	return r, ok
+}
+
+// sensitive returns the generic object of a deep copy of the resource and the
+// concrete paths of the sensitive values in it, or nil if there are none. An
+// error is returned if the resource can't be converted to the object.
+func (p *Policy) sensitive(r *apiv1.Resource) (map[string]interface{}, [][]string, error) {
+	if r == nil {
+		return nil, nil, nil
+	}
+	patterns := p.patterns(r)
+	_, hasAttrs := r.Extensions[SensitiveAttributesKey]
+	if len(patterns) == 0 && !hasAttrs {
+		return nil, nil, nil
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	obj := map[string]interface{}{}
+	if err = json.Unmarshal(data, &obj); err != nil {
+		return nil, nil, err
+	}
+	// The sensitive attributes are the metadata of masking, rather than a
+	// part of the resource to show.
+	if ext, ok := obj["extensions"].(map[string]interface{}); ok {
+		delete(ext, SensitiveAttributesKey)
+		if len(ext) == 0 {
+			delete(obj, "extensions")
+		}
+	}
+
+	var paths [][]string
+	seen := map[string]bool{}
+	for _, pattern := range patterns {
+		for _, path := range expand(obj, pattern) {
+			key := strings.Join(path, ".")
+			if !seen[key] {
+				seen[key] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+	return obj, paths, nil
+}
+
+// patterns returns the paths of the rules matching the resource, and those of
+// its sensitive attributes.
+func (p *Policy) patterns(r *apiv1.Resource) [][]string {
+	var patterns [][]string
+	kind := kindOf(r)
+	for _, rule := range p.Rules {
+		if (rule.Type != "" && rule.Type != r.Type) || (rule.Kind != "" && rule.Kind != kind) {
+			continue
+		}
+		for _, path := range rule.Paths {
+			patterns = append(patterns, strings.Split(path, "."))
+		}
+	}
+	for _, path := range SensitiveAttributes(r) {
+		patterns = append(patterns, append([]string{"attributes"}, strings.Split(path, ".")...))
+	}
+	return patterns
+}
+
+// SensitiveAttributes returns the paths of the sensitive attributes recorded
+// in the extensions of the resource.
+func SensitiveAttributes(r *apiv1.Resource) []string {
+	if r == nil {
+		return nil
+	}
+	var paths []string
+	switch v := r.Extensions[SensitiveAttributesKey].(type) {
+	case []string:
+		paths = v
+	case []interface{}:
+		for _, path := range v {
+			if s, ok := path.(string); ok {
+				paths = append(paths, s)
+			}
+		}
+	}
+	return paths
+}
+
+// kindOf returns the kind of the Kubernetes resource, or the resource type of
+// the Terraform resource.
+func kindOf(r *apiv1.Resource) string {
+	switch r.Type {
+	case apiv1.Kubernetes:
+		kind, _ := r.Attributes["kind"].(string)
+		return kind
+	case apiv1.Terraform:
+		kind, _ := r.Extensions["resourceType"].(string)
+		return kind
+	}
+	return ""
+}
+
+// expand returns the concrete paths in the object matching the pattern.
+func expand(obj interface{}, pattern []string) [][]string {
+	if len(pattern) == 0 {
+		return [][]string{{}}
+	}
+	var paths [][]string
+	head, rest := pattern[0], pattern[1:]
+	switch o := obj.(type) {
+	case map[string]interface{}:
+		for k, v := range o {
+			if head != Wildcard && head != k {
+				continue
+			}
+			for _, path := range expand(v, rest) {
+				paths = append(paths, append([]string{k}, path...))
+			}
+		}
+	case []interface{}:
+		for i, v := range o {
+			if head != Wildcard && head != strconv.Itoa(i) {
+				continue
+			}
+			for _, path := range expand(v, rest) {
+				paths = append(paths, append([]string{strconv.Itoa(i)}, path...))
+			}
+		}
+	}
+	return paths
+}
+
+// get returns the value at the path in the object.
+func get(obj interface{}, path []string) (interface{}, bool) {
+	if obj == nil {
+		return nil, false
+	}
+	for _, key := range path {
+		switch o := obj.(type) {
+		case map[string]interface{}:
+			v, ok := o[key]
+			if !ok {
+				return nil, false
+			}
+			obj = v
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(o) {
+				return nil, false
+			}
+			obj = o[i]
+		default:
+			return nil, false
+		}
+	}
+	return obj, true
+}
+
+// set replaces the value at the path in the object, which must exist.
+func set(obj interface{}, path []string, value interface{}) {
+	parent, ok := get(obj, path[:len(path)-1])
+	if !ok {
+		return
+	}
+	key := path[len(path)-1]
+	switch o := parent.(type) {
+	case map[string]interface{}:
+		o[key] = value
+	case []interface{}:
+		if i, err := strconv.Atoi(key); err == nil {
+			o[i] = value
+		}
+	}
+}
+
+func equal(a, b interface{}) bool {
+	da, err1 := json.Marshal(a)
+	db, err2 := json.Marshal(b)
+	return err1 == nil && err2 == nil && string(da) == string(db)
+}
+
+func toResource(obj map[string]interface{}) *apiv1.Resource {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil
+	}
+	r := &apiv1.Resource{}
+	if err = json.Unmarshal(data, r); err != nil {
+		return nil
+	}
+	return r
+}
@@ -0,0 +1,151 @@
+package mask
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apiv1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+)
+
+func newTFResource(password string) *apiv1.Resource {
+	return &apiv1.Resource{
+		ID:   "hashicorp:random:random_password:foo",
+		Type: apiv1.Terraform,
+		Attributes: map[string]interface{}{
+			"length":   16,
+			"password": password,
+			"keepers":  []interface{}{map[string]interface{}{"token": "foo"}},
+		},
+		Extensions: map[string]interface{}{
+			"provider":     "registry.terraform.io/hashicorp/random/3.6.0",
+			"resourceType": "random_password",
+			"providerMeta": map[string]interface{}{
+				"region":     "us-east-1",
+				"secret_key": "bar",
+			},
+			SensitiveAttributesKey: []interface{}{"password"},
+		},
+	}
+}
+
+func TestPolicyFromContext(t *testing.T) {
+	t.Run("default rules", func(t *testing.T) {
+		p, err := PolicyFromContext(nil)
+		require.NoError(t, err)
+		assert.Equal(t, DefaultRules, p.Rules)
+	})
+
+	t.Run("workspace rules", func(t *testing.T) {
+		p, err := PolicyFromContext(apiv1.GenericConfig{
+			ContextKey: map[string]interface{}{
+				"rules": []interface{}{
+					map[string]interface{}{
+						"type":  "Kubernetes",
+						"kind":  "ConfigMap",
+						"paths": []interface{}{"attributes.data.token"},
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, p.Rules, len(DefaultRules)+1)
+		assert.Equal(t, Rule{Type: apiv1.Kubernetes, Kind: "ConfigMap", Paths: []string{"attributes.data.token"}}, p.Rules[len(DefaultRules)])
+	})
+
+	t.Run("invalid rules", func(t *testing.T) {
+		_, err := PolicyFromContext(apiv1.GenericConfig{ContextKey: "foo"})
+		assert.Error(t, err)
+
+		_, err = PolicyFromContext(apiv1.GenericConfig{
+			ContextKey: map[string]interface{}{
+				"rules": []interface{}{map[string]interface{}{"kind": "ConfigMap"}},
+			},
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestPolicy_Mask(t *testing.T) {
+	t.Run("terraform sensitive attributes and provider credentials", func(t *testing.T) {
+		from, to := Default().Mask(newTFResource("foo"), newTFResource("bar"))
+		maskedFrom, maskedTo := from.(*apiv1.Resource), to.(*apiv1.Resource)
+		assert.Equal(t, maskStrBefore, maskedFrom.Attributes["password"])
+		assert.Equal(t, maskStrAfter, maskedTo.Attributes["password"])
+		assert.Equal(t, maskStr, maskedTo.Extensions["providerMeta"].(map[string]interface{})["secret_key"])
+		assert.Equal(t, "us-east-1", maskedTo.Extensions["providerMeta"].(map[string]interface{})["region"])
+		assert.NotContains(t, maskedTo.Extensions, SensitiveAttributesKey)
+		// The original resources are kept as is.
+		assert.Equal(t, "bar", newTFResource("bar").Attributes["password"])
+	})
+
+	t.Run("workspace rules with wildcards", func(t *testing.T) {
+		p := &Policy{Rules: []Rule{{Type: apiv1.Terraform, Kind: "random_password", Paths: []string{"attributes.keepers.*.token"}}}}
+		masked := p.MaskResource(newTFResource("foo"))
+		assert.Equal(t, maskStr, masked.Attributes["keepers"].([]interface{})[0].(map[string]interface{})["token"])
+
+		// The rules of other kinds are not applied.
+		p = &Policy{Rules: []Rule{{Kind: "random_string", Paths: []string{"attributes.length"}}}}
+		masked = p.MaskResource(newTFResource("foo"))
+		assert.EqualValues(t, 16, masked.Attributes["length"])
+	})
+
+	t.Run("created secret", func(t *testing.T) {
+		secret := &apiv1.Resource{
+			Type: apiv1.Kubernetes,
+			Attributes: map[string]interface{}{
+				"kind": "Secret",
+				"data": map[string]interface{}{"key": "dmFsdWUK"},
+			},
+		}
+		from, to := Default().Mask(nil, secret)
+		assert.Nil(t, from)
+		assert.Equal(t, maskStr, to.(*apiv1.Resource).Attributes["data"].(map[string]interface{})["key"])
+	})
+
+	t.Run("unmarshalable resource", func(t *testing.T) {
+		r := newTFResource("foo")
+		r.Attributes["ratio"] = math.NaN()
+		masked := Default().MaskResource(r)
+		assert.Equal(t, r.ID, masked.ID)
+		assert.Nil(t, masked.Attributes)
+		assert.Nil(t, masked.Extensions)
+	})
+
+	t.Run("not resources", func(t *testing.T) {
+		from, to := Default().Mask("foo", map[string]interface{}{"password": "bar"})
+		assert.Equal(t, "foo", from)
+		assert.Equal(t, map[string]interface{}{"password": "bar"}, to)
+	})
+}
+
+func TestPolicy_MaskResources(t *testing.T) {
+	resources := apiv1.Resources{*newTFResource("foo")}
+	masked := Default().MaskResources(resources)
+	require.Len(t, masked, 1)
+	assert.Equal(t, maskStr, masked[0].Attributes["password"])
+	assert.Equal(t, "foo", resources[0].Attributes["password"])
+	assert.Nil(t, Default().MaskResources(nil))
+}
+
+func TestPolicy_MaskContext(t *testing.T) {
+	ctx := apiv1.GenericConfig{
+		apiv1.ProviderEnvs: map[string]interface{}{"AWS_SECRET_ACCESS_KEY": "foo"},
+		"region":           "us-east-1",
+		"db":               map[string]interface{}{"password": "bar"},
+	}
+	p := &Policy{Rules: append(DefaultRules, Rule{Type: ContextType, Paths: []string{"db.password"}})}
+	masked := p.MaskContext(ctx)
+	assert.Equal(t, maskStr, masked[apiv1.ProviderEnvs].(map[string]interface{})["AWS_SECRET_ACCESS_KEY"])
+	assert.Equal(t, maskStr, masked["db"].(map[string]interface{})["password"])
+	assert.Equal(t, "us-east-1", masked["region"])
+	// The original context is kept as is.
+	assert.Equal(t, "foo", ctx[apiv1.ProviderEnvs].(map[string]interface{})["AWS_SECRET_ACCESS_KEY"])
+
+	// Everything is masked if the context can't be inspected.
+	masked = Default().MaskContext(apiv1.GenericConfig{"region": "us-east-1", "ratio": math.NaN()})
+	assert.Equal(t, apiv1.GenericConfig{"region": maskStr, "ratio": maskStr}, masked)
+	assert.Nil(t, Default().MaskContext(nil))
+}
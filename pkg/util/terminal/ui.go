@@ -1,24 +1,42 @@
 package terminal
 
 import (
+	"io"
+
 	"github.com/liu-hm19/pterm"
 	"kusionstack.io/kusion/pkg/util/pretty"
 )
 
 type UI struct {
-	SpinnerPrinter           *pterm.SpinnerPrinter
-	ProgressbarPrinter       *pterm.ProgressbarPrinter
-	InteractiveSelectPrinter *pterm.InteractiveSelectPrinter
-	MultiPrinter             *pterm.MultiPrinter
+	SpinnerPrinter                *pterm.SpinnerPrinter
+	ProgressbarPrinter            *pterm.ProgressbarPrinter
+	InteractiveSelectPrinter      *pterm.InteractiveSelectPrinter
+	InteractiveMultiselectPrinter *pterm.InteractiveMultiselectPrinter
+	InteractiveTextInputPrinter   *pterm.InteractiveTextInputPrinter
+	MultiPrinter                  *pterm.MultiPrinter
 }
 
 // DefaultUI returns a UI for Kusion CLI display with default
-// SpinnerPrinter, ProgressbarPrinter and InteractiveSelectPrinter.
+// SpinnerPrinter, ProgressbarPrinter, InteractiveSelectPrinter,
+// InteractiveMultiselectPrinter and InteractiveTextInputPrinter.
 func DefaultUI() *UI {
 	return &UI{
-		SpinnerPrinter:           &pretty.SpinnerT,
-		ProgressbarPrinter:       &pterm.DefaultProgressbar,
-		InteractiveSelectPrinter: &pterm.DefaultInteractiveSelect,
-		MultiPrinter:             &pterm.DefaultMultiPrinter,
+		SpinnerPrinter:                &pretty.SpinnerT,
+		ProgressbarPrinter:            &pterm.DefaultProgressbar,
+		InteractiveSelectPrinter:      &pterm.DefaultInteractiveSelect,
+		InteractiveMultiselectPrinter: &pterm.DefaultInteractiveMultiselect,
+		InteractiveTextInputPrinter:   &pterm.DefaultInteractiveTextInput,
+		MultiPrinter:                  &pterm.DefaultMultiPrinter,
 	}
 }
+
+// WithWriter returns a copy of the UI whose SpinnerPrinter, ProgressbarPrinter
+// and MultiPrinter write to the writer, e.g. to keep the progress out of the
+// structured output written to the stdout.
+func (ui *UI) WithWriter(writer io.Writer) *UI {
+	u := *ui
+	u.SpinnerPrinter = ui.SpinnerPrinter.WithWriter(writer)
+	u.ProgressbarPrinter = ui.ProgressbarPrinter.WithWriter(writer)
+	u.MultiPrinter = ui.MultiPrinter.WithWriter(writer)
+	return &u
+}
@@ -1,6 +1,7 @@
 package terminal
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -12,3 +13,16 @@ func TestDefaultUI(t *testing.T) {
 		assert.NotNil(t, ui)
 	})
 }
+
+func TestUIWithWriter(t *testing.T) {
+	t.Run("write progress to the writer", func(t *testing.T) {
+		ui := DefaultUI()
+		var buf bytes.Buffer
+		actual := ui.WithWriter(&buf)
+		assert.Equal(t, &buf, actual.SpinnerPrinter.Writer)
+		assert.Equal(t, &buf, actual.ProgressbarPrinter.Writer)
+		assert.Equal(t, &buf, actual.MultiPrinter.Writer)
+		// The default printers are left as is.
+		assert.NotEqual(t, &buf, ui.MultiPrinter.Writer)
+	})
+}
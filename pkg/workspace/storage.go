@@ -32,3 +32,10 @@ type Storage interface {
 	// RenameWorkspace renames the workspace.
 	RenameWorkspace(oldName, newName string) error
 }
+
+// Rotator is implemented by the storages encrypting the workspace files, so that the files already encrypted
+// with the current key are not rewritten when rotating the key.
+type Rotator interface {
+	// NeedsRotation reports whether the workspace file is not encrypted with the current key.
+	NeedsRotation(name string) (bool, error)
+}
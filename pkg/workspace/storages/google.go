@@ -10,6 +10,7 @@ import (
 	googlestorage "cloud.google.com/go/storage"
 
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/infra/encryption"
 )
 
 // GoogleStorage is an implementation of workspace.Storage which uses google cloud as storage.
@@ -20,6 +21,9 @@ type GoogleStorage struct {
 	prefix string
 
 	meta *workspacesMetaData
+
+	// encrypter encrypts the workspace files, nil means the encryption is not enabled.
+	encrypter *encryption.Encrypter
 }
 
 // NewGoogleStorage news google cloud workspace storage and init default workspace.
@@ -38,6 +42,30 @@ func (s *GoogleStorage) Get(name string) (*v1.Workspace, error) {
 	if name == "" {
 		name = s.meta.Current
 	}
+	content, err := s.readWorkspace(name)
+	if err != nil {
+		return nil, err
+	}
+
+	ws, err := decodeWorkspace(s.encrypter, content)
+	if err != nil {
+		return nil, err
+	}
+	ws.Name = name
+	return ws, nil
+}
+
+// NeedsRotation reports whether the workspace file is not encrypted with the current key.
+func (s *GoogleStorage) NeedsRotation(name string) (bool, error) {
+	content, err := s.readWorkspace(name)
+	if err != nil {
+		return false, err
+	}
+	return s.encrypter.FileNeedsRotation(content), nil
+}
+
+// readWorkspace reads the content of the workspace file.
+func (s *GoogleStorage) readWorkspace(name string) ([]byte, error) {
 	if !checkWorkspaceExistence(s.meta, name) {
 		return nil, ErrWorkspaceNotExist
 	}
@@ -52,13 +80,7 @@ func (s *GoogleStorage) Get(name string) (*v1.Workspace, error) {
 	if err != nil {
 		return nil, fmt.Errorf("read workspace failed: %w", err)
 	}
-
-	ws := &v1.Workspace{}
-	if err = yaml.Unmarshal(content, ws); err != nil {
-		return nil, fmt.Errorf("yaml unmarshal workspace failed: %w", err)
-	}
-	ws.Name = name
-	return ws, nil
+	return content, nil
 }
 
 func (s *GoogleStorage) Create(ws *v1.Workspace) error {
@@ -149,6 +171,12 @@ func (s *GoogleStorage) RenameWorkspace(oldName, newName string) (err error) {
 	return nil
 }
 
+// SetEncrypter enables the client-side encryption of the workspace files with the encrypter. The workspace
+// files written before are still readable, and get encrypted when updated.
+func (s *GoogleStorage) SetEncrypter(encrypter *encryption.Encrypter) {
+	s.encrypter = encrypter
+}
+
 func (s *GoogleStorage) initDefaultWorkspaceIf() error {
 	if !checkWorkspaceExistence(s.meta, DefaultWorkspace) {
 		// if there is no default workspace, create one with empty workspace.
@@ -213,9 +241,9 @@ func (s *GoogleStorage) writeMeta() error {
 }
 
 func (s *GoogleStorage) writeWorkspace(ws *v1.Workspace) error {
-	content, err := yaml.Marshal(ws)
+	content, err := encodeWorkspace(s.encrypter, ws)
 	if err != nil {
-		return fmt.Errorf("yaml marshal workspace failed: %w", err)
+		return err
 	}
 
 	obj := s.bucket.Object(s.prefix + "/" + ws.Name + yamlSuffix)
@@ -8,6 +8,8 @@ import (
 	"gopkg.in/yaml.v3"
 
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/infra/encryption"
+	ioutil "kusionstack.io/kusion/pkg/util/io"
 )
 
 // LocalStorage is an implementation of workspace.Storage which uses local filesystem as storage.
@@ -16,6 +18,9 @@ type LocalStorage struct {
 	path string
 
 	meta *workspacesMetaData
+
+	// encrypter encrypts the workspace files, nil means the encryption is not enabled.
+	encrypter *encryption.Encrypter
 }
 
 // NewLocalStorage news local workspace storage and init default workspace.
@@ -38,6 +43,30 @@ func (s *LocalStorage) Get(name string) (*v1.Workspace, error) {
 	if name == "" {
 		name = s.meta.Current
 	}
+	content, err := s.readWorkspace(name)
+	if err != nil {
+		return nil, err
+	}
+
+	ws, err := decodeWorkspace(s.encrypter, content)
+	if err != nil {
+		return nil, err
+	}
+	ws.Name = name
+	return ws, nil
+}
+
+// NeedsRotation reports whether the workspace file is not encrypted with the current key.
+func (s *LocalStorage) NeedsRotation(name string) (bool, error) {
+	content, err := s.readWorkspace(name)
+	if err != nil {
+		return false, err
+	}
+	return s.encrypter.FileNeedsRotation(content), nil
+}
+
+// readWorkspace reads the content of the workspace file.
+func (s *LocalStorage) readWorkspace(name string) ([]byte, error) {
 	if !checkWorkspaceExistence(s.meta, name) {
 		return nil, ErrWorkspaceNotExist
 	}
@@ -46,13 +75,7 @@ func (s *LocalStorage) Get(name string) (*v1.Workspace, error) {
 	if err != nil {
 		return nil, fmt.Errorf("read workspace file failed: %w", err)
 	}
-
-	ws := &v1.Workspace{}
-	if err = yaml.Unmarshal(content, ws); err != nil {
-		return nil, fmt.Errorf("yaml unmarshal workspace failed: %w", err)
-	}
-	ws.Name = name
-	return ws, nil
+	return content, nil
 }
 
 func (s *LocalStorage) Create(ws *v1.Workspace) error {
@@ -142,6 +165,12 @@ func (s *LocalStorage) RenameWorkspace(oldName, newName string) (err error) {
 	return nil
 }
 
+// SetEncrypter enables the client-side encryption of the workspace files with the encrypter. The workspace
+// files written before are still readable, and get encrypted when updated.
+func (s *LocalStorage) SetEncrypter(encrypter *encryption.Encrypter) {
+	s.encrypter = encrypter
+}
+
 func (s *LocalStorage) initDefaultWorkspaceIf() error {
 	if !checkWorkspaceExistence(s.meta, DefaultWorkspace) {
 		// if there is no default workspace, create one with empty workspace.
@@ -180,19 +209,19 @@ func (s *LocalStorage) writeMeta() error {
 		return fmt.Errorf("yaml marshal workspaces metadata failed: %w", err)
 	}
 
-	if err = os.WriteFile(filepath.Join(s.path, metadataFile), content, os.ModePerm); err != nil {
+	if err = ioutil.WriteFileAtomic(filepath.Join(s.path, metadataFile), content, 0o644); err != nil {
 		return fmt.Errorf("write workspaces metadata file failed: %w", err)
 	}
 	return nil
 }
 
 func (s *LocalStorage) writeWorkspace(ws *v1.Workspace) error {
-	content, err := yaml.Marshal(ws)
+	content, err := encodeWorkspace(s.encrypter, ws)
 	if err != nil {
-		return fmt.Errorf("yaml marshal workspace failed: %w", err)
+		return err
 	}
 
-	if err = os.WriteFile(filepath.Join(s.path, ws.Name+yamlSuffix), content, os.ModePerm); err != nil {
+	if err = ioutil.WriteFileAtomic(filepath.Join(s.path, ws.Name+yamlSuffix), content, 0o644); err != nil {
 		return fmt.Errorf("write workspace file failed: %w", err)
 	}
 	return nil
@@ -1,12 +1,17 @@
 package storages
 
 import (
+	"crypto/rand"
+	"encoding/base64"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/infra/encryption"
+	ioutil "kusionstack.io/kusion/pkg/util/io"
 )
 
 func testDataFolder(path string) string {
@@ -14,6 +19,21 @@ func testDataFolder(path string) string {
 	return filepath.Join(pwd, "testdata", path)
 }
 
+// testDataCopy copies the test data folder to a temporary directory, so that
+// the checked-in test data is never changed by the tests writing workspaces.
+func testDataCopy(t *testing.T, path string) string {
+	dest := filepath.Join(t.TempDir(), path)
+	entries, err := os.ReadDir(testDataFolder(path))
+	if os.IsNotExist(err) {
+		return dest
+	}
+	require.NoError(t, err)
+	for _, entry := range entries {
+		require.NoError(t, ioutil.CopyFile(filepath.Join(testDataFolder(path), entry.Name()), filepath.Join(dest, entry.Name())))
+	}
+	return dest
+}
+
 func mockWorkspace(name string) *v1.Workspace {
 	return &v1.Workspace{
 		Name: name,
@@ -85,31 +105,27 @@ func TestLocalStorageOperation(t *testing.T) {
 		success      bool
 		path         string
 		expectedMeta *workspacesMetaData
-		deletePath   bool
 	}{
 		{
 			name:    "new local storage with empty directory",
 			success: true,
-			path:    testDataFolder("empty_workspaces"),
+			path:    testDataCopy(t, "empty_workspaces"),
 			expectedMeta: &workspacesMetaData{
 				Current:             "default",
 				AvailableWorkspaces: []string{"default"},
 			},
-			deletePath: true,
 		},
 		{
 			name:         "new local storage with exist directory",
 			success:      true,
-			path:         testDataFolder("workspaces"),
+			path:         testDataCopy(t, "workspaces"),
 			expectedMeta: mockWorkspacesMetaData(),
-			deletePath:   false,
 		},
 		{
 			name:         "new local storage failed",
 			success:      false,
-			path:         testDataFolder("invalid_metadata_workspaces"),
+			path:         testDataCopy(t, "invalid_metadata_workspaces"),
 			expectedMeta: nil,
-			deletePath:   false,
 		},
 	}
 
@@ -120,9 +136,6 @@ func TestLocalStorageOperation(t *testing.T) {
 			if tc.success {
 				assert.Equal(t, tc.expectedMeta, s.meta)
 			}
-			if tc.deletePath {
-				_ = os.RemoveAll(tc.path)
-			}
 		})
 	}
 }
@@ -150,7 +163,7 @@ func TestLocalStorage_Get(t *testing.T) {
 
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
-			s, err := NewLocalStorage(testDataFolder("workspaces"))
+			s, err := NewLocalStorage(testDataCopy(t, "workspaces"))
 			assert.NoError(t, err)
 			workspace, err := s.Get(tc.wsName)
 			assert.Equal(t, tc.success, err == nil)
@@ -172,7 +185,7 @@ func TestLocalStorage_Create(t *testing.T) {
 		{
 			name:      "create workspace successfully",
 			success:   true,
-			path:      testDataFolder("for_create_workspaces"),
+			path:      testDataCopy(t, "for_create_workspaces"),
 			workspace: mockWorkspace("dev"),
 			expectedMeta: &workspacesMetaData{
 				Current:             "default",
@@ -182,7 +195,7 @@ func TestLocalStorage_Create(t *testing.T) {
 		{
 			name:         "create workspace failed already exist",
 			success:      false,
-			path:         testDataFolder("workspaces"),
+			path:         testDataCopy(t, "workspaces"),
 			workspace:    mockWorkspace("prod"),
 			expectedMeta: nil,
 		},
@@ -225,7 +238,7 @@ func TestLocalStorage_Update(t *testing.T) {
 
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
-			s, err := NewLocalStorage(testDataFolder("workspaces"))
+			s, err := NewLocalStorage(testDataCopy(t, "workspaces"))
 			assert.NoError(t, err)
 			err = s.Update(tc.workspace)
 			assert.Equal(t, tc.success, err == nil)
@@ -250,7 +263,7 @@ func TestLocalStorage_Delete(t *testing.T) {
 		{
 			name:    "delete workspace successfully",
 			success: true,
-			path:    testDataFolder("for_delete_workspaces"),
+			path:    testDataCopy(t, "for_delete_workspaces"),
 			wsName:  "dev",
 			expectedMeta: &workspacesMetaData{
 				Current:             "default",
@@ -288,7 +301,7 @@ func TestLocalStorage_GetNames(t *testing.T) {
 
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
-			s, err := NewLocalStorage(testDataFolder("workspaces"))
+			s, err := NewLocalStorage(testDataCopy(t, "workspaces"))
 			assert.NoError(t, err)
 			names, err := s.GetNames()
 			assert.Equal(t, tc.success, err == nil)
@@ -314,7 +327,7 @@ func TestLocalStorage_GetCurrent(t *testing.T) {
 
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
-			s, err := NewLocalStorage(testDataFolder("workspaces"))
+			s, err := NewLocalStorage(testDataCopy(t, "workspaces"))
 			assert.NoError(t, err)
 			current, err := s.GetCurrent()
 			assert.Equal(t, tc.success, err == nil)
@@ -345,7 +358,7 @@ func TestLocalStorage_SetCurrent(t *testing.T) {
 
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
-			s, err := NewLocalStorage(testDataFolder("for_set_current_workspaces"))
+			s, err := NewLocalStorage(testDataCopy(t, "for_set_current_workspaces"))
 			assert.NoError(t, err)
 			err = s.SetCurrent(tc.wsName)
 			assert.Equal(t, tc.success, err == nil)
@@ -383,8 +396,7 @@ func TestLocalStorage_RenameWorkspace(t *testing.T) {
 
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
-			os.RemoveAll(testDataFolder("for_rename_workspaces"))
-			s, err := NewLocalStorage(testDataFolder("for_rename_workspaces"))
+			s, err := NewLocalStorage(t.TempDir())
 			assert.NoError(t, err)
 			err = s.Create(mockWorkspace(tc.oldName))
 			assert.NoError(t, err)
@@ -399,3 +411,28 @@ func TestLocalStorage_RenameWorkspace(t *testing.T) {
 		})
 	}
 }
+
+func TestLocalStorage_Encryption(t *testing.T) {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	t.Setenv(encryption.DefaultKeyEnvVar, base64.StdEncoding.EncodeToString(key))
+	provider, err := encryption.NewKeyProvider(encryption.EnvKeyProviderName, nil)
+	assert.NoError(t, err)
+
+	path := t.TempDir()
+	s, err := NewLocalStorage(path)
+	assert.NoError(t, err)
+	s.SetEncrypter(encryption.NewEncrypter(provider))
+	assert.NoError(t, s.Create(mockWorkspace("dev")))
+
+	content, err := os.ReadFile(filepath.Join(path, "dev"+yamlSuffix))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "db.t3.micro")
+	ws, err := s.Get("dev")
+	assert.NoError(t, err)
+	assert.Equal(t, mockWorkspace("dev"), ws)
+
+	// The default workspace written before the encryption is enabled is still readable.
+	_, err = s.Get(DefaultWorkspace)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,240 @@
+package storages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/infra/encryption"
+	"kusionstack.io/kusion/pkg/infra/objectstore"
+)
+
+// ObjectStoreStorage is an implementation of workspace.Storage which uses a generic object store as storage,
+// such as the Azure Blob Storage, a HTTP server or a Kubernetes cluster.
+type ObjectStoreStorage struct {
+	store objectstore.Store
+
+	// The prefix to store the workspaces files.
+	prefix string
+
+	meta *workspacesMetaData
+
+	// encrypter encrypts the workspace files, nil means the encryption is not enabled.
+	encrypter *encryption.Encrypter
+}
+
+// NewObjectStoreStorage news object store workspace storage and init default workspace.
+func NewObjectStoreStorage(store objectstore.Store, prefix string) (*ObjectStoreStorage, error) {
+	s := &ObjectStoreStorage{
+		store:  store,
+		prefix: prefix,
+	}
+	if err := s.readMeta(); err != nil {
+		return nil, err
+	}
+	return s, s.initDefaultWorkspaceIf()
+}
+
+func (s *ObjectStoreStorage) Get(name string) (*v1.Workspace, error) {
+	if name == "" {
+		name = s.meta.Current
+	}
+	content, err := s.readWorkspace(name)
+	if err != nil {
+		return nil, err
+	}
+
+	ws, err := decodeWorkspace(s.encrypter, content)
+	if err != nil {
+		return nil, err
+	}
+	ws.Name = name
+	return ws, nil
+}
+
+// NeedsRotation reports whether the workspace file is not encrypted with the current key.
+func (s *ObjectStoreStorage) NeedsRotation(name string) (bool, error) {
+	content, err := s.readWorkspace(name)
+	if err != nil {
+		return false, err
+	}
+	return s.encrypter.FileNeedsRotation(content), nil
+}
+
+// readWorkspace reads the content of the workspace file.
+func (s *ObjectStoreStorage) readWorkspace(name string) ([]byte, error) {
+	if !checkWorkspaceExistence(s.meta, name) {
+		return nil, ErrWorkspaceNotExist
+	}
+
+	content, err := s.store.Get(context.Background(), s.prefix+"/"+name+yamlSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("get workspace from object store failed: %w", err)
+	}
+	return content, nil
+}
+
+func (s *ObjectStoreStorage) Create(ws *v1.Workspace) error {
+	if checkWorkspaceExistence(s.meta, ws.Name) {
+		return ErrWorkspaceAlreadyExist
+	}
+
+	if err := s.writeWorkspace(ws); err != nil {
+		return err
+	}
+
+	addAvailableWorkspaces(s.meta, ws.Name)
+	return s.writeMeta()
+}
+
+func (s *ObjectStoreStorage) Update(ws *v1.Workspace) error {
+	if ws.Name == "" {
+		ws.Name = s.meta.Current
+	}
+	if !checkWorkspaceExistence(s.meta, ws.Name) {
+		return ErrWorkspaceNotExist
+	}
+
+	return s.writeWorkspace(ws)
+}
+
+func (s *ObjectStoreStorage) Delete(name string) error {
+	if name == "" {
+		name = s.meta.Current
+	}
+	if !checkWorkspaceExistence(s.meta, name) {
+		return nil
+	}
+
+	if err := s.store.Delete(context.Background(), s.prefix+"/"+name+yamlSuffix); err != nil {
+		return fmt.Errorf("remove workspace in object store failed: %w", err)
+	}
+
+	removeAvailableWorkspaces(s.meta, name)
+	return s.writeMeta()
+}
+
+func (s *ObjectStoreStorage) GetNames() ([]string, error) {
+	return s.meta.AvailableWorkspaces, nil
+}
+
+func (s *ObjectStoreStorage) GetCurrent() (string, error) {
+	return s.meta.Current, nil
+}
+
+func (s *ObjectStoreStorage) SetCurrent(name string) error {
+	if !checkWorkspaceExistence(s.meta, name) {
+		return ErrWorkspaceNotExist
+	}
+	s.meta.Current = name
+	return s.writeMeta()
+}
+
+func (s *ObjectStoreStorage) RenameWorkspace(oldName, newName string) (err error) {
+	if oldName == "" || newName == "" {
+		return fmt.Errorf("given name is empty")
+	}
+
+	// restore the old workspace name if the rename failed
+	defer func() {
+		if err != nil {
+			removeAvailableWorkspaces(s.meta, newName)
+			addAvailableWorkspaces(s.meta, oldName)
+			s.writeMeta()
+		}
+	}()
+
+	// update the meta file
+	removeAvailableWorkspaces(s.meta, oldName)
+	addAvailableWorkspaces(s.meta, newName)
+	if err = s.writeMeta(); err != nil {
+		return err
+	}
+
+	// rename the workspace file by copying and deleting
+	ctx := context.Background()
+	oldKey := s.prefix + "/" + oldName + yamlSuffix
+	newKey := s.prefix + "/" + newName + yamlSuffix
+
+	content, err := s.store.Get(ctx, oldKey)
+	if err != nil {
+		return fmt.Errorf("get old workspace failed: %w", err)
+	}
+	if err = s.store.Put(ctx, newKey, content); err != nil {
+		return fmt.Errorf("copy workspace failed: %w", err)
+	}
+	if err = s.store.Delete(ctx, oldKey); err != nil {
+		return fmt.Errorf("delete old workspace failed: %w", err)
+	}
+
+	return nil
+}
+
+// SetEncrypter enables the client-side encryption of the workspace files with the encrypter. The workspace
+// files written before are still readable, and get encrypted when updated.
+func (s *ObjectStoreStorage) SetEncrypter(encrypter *encryption.Encrypter) {
+	s.encrypter = encrypter
+}
+
+func (s *ObjectStoreStorage) initDefaultWorkspaceIf() error {
+	if !checkWorkspaceExistence(s.meta, DefaultWorkspace) {
+		// if there is no default workspace, create one with empty workspace.
+		if err := s.writeWorkspace(&v1.Workspace{Name: DefaultWorkspace}); err != nil {
+			return err
+		}
+		addAvailableWorkspaces(s.meta, DefaultWorkspace)
+	}
+
+	if s.meta.Current == "" {
+		s.meta.Current = DefaultWorkspace
+	}
+	return s.writeMeta()
+}
+
+func (s *ObjectStoreStorage) readMeta() error {
+	content, err := s.store.Get(context.Background(), s.prefix+"/"+metadataFile)
+	if errors.Is(err, objectstore.ErrNotFound) {
+		s.meta = &workspacesMetaData{}
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("get workspaces metadata from object store failed: %w", err)
+	}
+	if len(content) == 0 {
+		s.meta = &workspacesMetaData{}
+		return nil
+	}
+
+	meta := &workspacesMetaData{}
+	if err = yaml.Unmarshal(content, meta); err != nil {
+		return fmt.Errorf("yaml unmarshal workspaces metadata failed: %w", err)
+	}
+	s.meta = meta
+	return nil
+}
+
+func (s *ObjectStoreStorage) writeMeta() error {
+	content, err := yaml.Marshal(s.meta)
+	if err != nil {
+		return fmt.Errorf("yaml marshal workspaces metadata failed: %w", err)
+	}
+
+	if err = s.store.Put(context.Background(), s.prefix+"/"+metadataFile, content); err != nil {
+		return fmt.Errorf("put workspaces metadata to object store failed: %w", err)
+	}
+	return nil
+}
+
+func (s *ObjectStoreStorage) writeWorkspace(ws *v1.Workspace) error {
+	content, err := encodeWorkspace(s.encrypter, ws)
+	if err != nil {
+		return err
+	}
+
+	if err = s.store.Put(context.Background(), s.prefix+"/"+ws.Name+yamlSuffix, content); err != nil {
+		return fmt.Errorf("put workspace to object store failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package storages
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"kusionstack.io/kusion/pkg/infra/objectstore"
+)
+
+func TestObjectStoreStorageOperation(t *testing.T) {
+	store := objectstore.NewMemoryStore()
+	s, err := NewObjectStoreStorage(store, "workspaces")
+	assert.NoError(t, err)
+
+	names, err := s.GetNames()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{DefaultWorkspace}, names)
+	current, err := s.GetCurrent()
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultWorkspace, current)
+
+	assert.NoError(t, s.Create(mockWorkspace("dev")))
+	assert.ErrorIs(t, s.Create(mockWorkspace("dev")), ErrWorkspaceAlreadyExist)
+	assert.NoError(t, s.SetCurrent("dev"))
+	assert.ErrorIs(t, s.SetCurrent("prod"), ErrWorkspaceNotExist)
+
+	// The metadata is persisted in the store.
+	s, err = NewObjectStoreStorage(store, "workspaces")
+	assert.NoError(t, err)
+	ws, err := s.Get("")
+	assert.NoError(t, err)
+	assert.Equal(t, mockWorkspace("dev"), ws)
+
+	updated := mockWorkspace("dev")
+	updated.Context = nil
+	assert.NoError(t, s.Update(updated))
+	ws, err = s.Get("dev")
+	assert.NoError(t, err)
+	assert.Equal(t, updated, ws)
+	assert.ErrorIs(t, s.Update(mockWorkspace("prod")), ErrWorkspaceNotExist)
+
+	assert.NoError(t, s.RenameWorkspace("dev", "test"))
+	_, err = s.Get("dev")
+	assert.ErrorIs(t, err, ErrWorkspaceNotExist)
+	ws, err = s.Get("test")
+	assert.NoError(t, err)
+	assert.Equal(t, "test", ws.Name)
+
+	assert.NoError(t, s.Delete("test"))
+	names, err = s.GetNames()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{DefaultWorkspace}, names)
+	keys, err := store.List(context.Background(), "workspaces/")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"workspaces/" + metadataFile, "workspaces/" + DefaultWorkspace + yamlSuffix}, keys)
+}
@@ -9,6 +9,7 @@ import (
 	"gopkg.in/yaml.v3"
 
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/infra/encryption"
 )
 
 // OssStorage is an implementation of workspace.Storage which uses oss as storage.
@@ -19,6 +20,9 @@ type OssStorage struct {
 	prefix string
 
 	meta *workspacesMetaData
+
+	// encrypter encrypts the workspace files, nil means the encryption is not enabled.
+	encrypter *encryption.Encrypter
 }
 
 // NewOssStorage news oss workspace storage and init default workspace.
@@ -37,6 +41,30 @@ func (s *OssStorage) Get(name string) (*v1.Workspace, error) {
 	if name == "" {
 		name = s.meta.Current
 	}
+	content, err := s.readWorkspace(name)
+	if err != nil {
+		return nil, err
+	}
+
+	ws, err := decodeWorkspace(s.encrypter, content)
+	if err != nil {
+		return nil, err
+	}
+	ws.Name = name
+	return ws, nil
+}
+
+// NeedsRotation reports whether the workspace file is not encrypted with the current key.
+func (s *OssStorage) NeedsRotation(name string) (bool, error) {
+	content, err := s.readWorkspace(name)
+	if err != nil {
+		return false, err
+	}
+	return s.encrypter.FileNeedsRotation(content), nil
+}
+
+// readWorkspace reads the content of the workspace file.
+func (s *OssStorage) readWorkspace(name string) ([]byte, error) {
 	if !checkWorkspaceExistence(s.meta, name) {
 		return nil, ErrWorkspaceNotExist
 	}
@@ -52,13 +80,7 @@ func (s *OssStorage) Get(name string) (*v1.Workspace, error) {
 	if err != nil {
 		return nil, fmt.Errorf("read workspace failed: %w", err)
 	}
-
-	ws := &v1.Workspace{}
-	if err = yaml.Unmarshal(content, ws); err != nil {
-		return nil, fmt.Errorf("yaml unmarshal workspace failed: %w", err)
-	}
-	ws.Name = name
-	return ws, nil
+	return content, nil
 }
 
 func (s *OssStorage) Create(ws *v1.Workspace) error {
@@ -162,6 +184,12 @@ func (s *OssStorage) RenameWorkspace(oldName, newName string) (err error) {
 	return nil
 }
 
+// SetEncrypter enables the client-side encryption of the workspace files with the encrypter. The workspace
+// files written before are still readable, and get encrypted when updated.
+func (s *OssStorage) SetEncrypter(encrypter *encryption.Encrypter) {
+	s.encrypter = encrypter
+}
+
 func (s *OssStorage) initDefaultWorkspaceIf() error {
 	if !checkWorkspaceExistence(s.meta, DefaultWorkspace) {
 		// if there is no default workspace, create one with empty workspace.
@@ -222,9 +250,9 @@ func (s *OssStorage) writeMeta() error {
 }
 
 func (s *OssStorage) writeWorkspace(ws *v1.Workspace) error {
-	content, err := yaml.Marshal(ws)
+	content, err := encodeWorkspace(s.encrypter, ws)
 	if err != nil {
-		return fmt.Errorf("yaml marshal workspace failed: %w", err)
+		return err
 	}
 
 	if err = s.bucket.PutObject(s.prefix+"/"+ws.Name+yamlSuffix, bytes.NewReader(content)); err != nil {
@@ -11,6 +11,7 @@ import (
 	"gopkg.in/yaml.v3"
 
 	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/infra/encryption"
 )
 
 // S3Storage is an implementation of workspace.Storage which uses s3 as storage.
@@ -22,6 +23,9 @@ type S3Storage struct {
 	prefix string
 
 	meta *workspacesMetaData
+
+	// encrypter encrypts the workspace files, nil means the encryption is not enabled.
+	encrypter *encryption.Encrypter
 }
 
 // NewS3Storage news s3 workspace storage and init default workspace.
@@ -41,6 +45,30 @@ func (s *S3Storage) Get(name string) (*v1.Workspace, error) {
 	if name == "" {
 		name = s.meta.Current
 	}
+	content, err := s.readWorkspace(name)
+	if err != nil {
+		return nil, err
+	}
+
+	ws, err := decodeWorkspace(s.encrypter, content)
+	if err != nil {
+		return nil, err
+	}
+	ws.Name = name
+	return ws, nil
+}
+
+// NeedsRotation reports whether the workspace file is not encrypted with the current key.
+func (s *S3Storage) NeedsRotation(name string) (bool, error) {
+	content, err := s.readWorkspace(name)
+	if err != nil {
+		return false, err
+	}
+	return s.encrypter.FileNeedsRotation(content), nil
+}
+
+// readWorkspace reads the content of the workspace file.
+func (s *S3Storage) readWorkspace(name string) ([]byte, error) {
 	if !checkWorkspaceExistence(s.meta, name) {
 		return nil, ErrWorkspaceNotExist
 	}
@@ -61,13 +89,7 @@ func (s *S3Storage) Get(name string) (*v1.Workspace, error) {
 	if err != nil {
 		return nil, fmt.Errorf("read workspace failed: %w", err)
 	}
-
-	ws := &v1.Workspace{}
-	if err = yaml.Unmarshal(content, ws); err != nil {
-		return nil, fmt.Errorf("yaml unmarshal workspace failed: %w", err)
-	}
-	ws.Name = name
-	return ws, nil
+	return content, nil
 }
 
 func (s *S3Storage) Create(ws *v1.Workspace) error {
@@ -174,6 +196,12 @@ func (s *S3Storage) RenameWorkspace(oldName, newName string) (err error) {
 	return nil
 }
 
+// SetEncrypter enables the client-side encryption of the workspace files with the encrypter. The workspace
+// files written before are still readable, and get encrypted when updated.
+func (s *S3Storage) SetEncrypter(encrypter *encryption.Encrypter) {
+	s.encrypter = encrypter
+}
+
 func (s *S3Storage) initDefaultWorkspaceIf() error {
 	if !checkWorkspaceExistence(s.meta, DefaultWorkspace) {
 		// if there is no default workspace, create one with empty workspace.
@@ -243,9 +271,9 @@ func (s *S3Storage) writeMeta() error {
 }
 
 func (s *S3Storage) writeWorkspace(ws *v1.Workspace) error {
-	content, err := yaml.Marshal(ws)
+	content, err := encodeWorkspace(s.encrypter, ws)
 	if err != nil {
-		return fmt.Errorf("yaml marshal workspace failed: %w", err)
+		return err
 	}
 
 	input := &s3.PutObjectInput{
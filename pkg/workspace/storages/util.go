@@ -1,10 +1,16 @@
 package storages
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	v1 "kusionstack.io/kusion/pkg/apis/api.kusion.io/v1"
+	"kusionstack.io/kusion/pkg/infra/encryption"
 )
 
 const (
@@ -74,3 +80,30 @@ func removeAvailableWorkspaces(meta *workspacesMetaData, name string) {
 		meta.Current = DefaultWorkspace
 	}
 }
+
+// encodeWorkspace marshals the workspace to the content of the workspace file, which is encrypted if the
+// encrypter is not nil.
+func encodeWorkspace(encrypter *encryption.Encrypter, ws *v1.Workspace) ([]byte, error) {
+	content, err := yaml.Marshal(ws)
+	if err != nil {
+		return nil, fmt.Errorf("yaml marshal workspace failed: %w", err)
+	}
+	if content, err = encrypter.EncryptFile(context.Background(), content); err != nil {
+		return nil, fmt.Errorf("encrypt workspace failed: %w", err)
+	}
+	return content, nil
+}
+
+// decodeWorkspace unmarshals the workspace from the content of the workspace file, which is decrypted
+// first if encrypted.
+func decodeWorkspace(encrypter *encryption.Encrypter, content []byte) (*v1.Workspace, error) {
+	content, err := encrypter.DecryptFile(context.Background(), content)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt workspace failed: %w", err)
+	}
+	ws := &v1.Workspace{}
+	if err = yaml.Unmarshal(content, ws); err != nil {
+		return nil, fmt.Errorf("yaml unmarshal workspace failed: %w", err)
+	}
+	return ws, nil
+}
@@ -34,6 +34,9 @@ var (
 	ErrEmptyAlicloudRegion                  = errors.New("region must be provided when using Alicloud Secrets Manager")
 	ErrMissingProviderType                  = errors.New("must specify a provider type")
 	ErrInvalidViettelCloudProjectID         = errors.New("invalid format project id for ViettelCloud Secrets Manager")
+	ErrEmptyModuleTrustRegistry             = errors.New("empty registry in module trust policy")
+	ErrEmptyModuleSigners                   = errors.New("no public key or keyless identity of the registry in module trust policy")
+	ErrInvalidKeylessIdentity               = errors.New("both issuer and subject must be provided of a keyless identity")
 )
 
 // ValidateWorkspace is used to validate the workspace get or set in the storage.
@@ -51,6 +54,11 @@ func ValidateWorkspace(ws *v1.Workspace) error {
 			return utilerrors.NewAggregate(allErrs)
 		}
 	}
+	if ws.ModuleTrust != nil {
+		if err := ValidateModuleTrustPolicy(ws.ModuleTrust); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -85,6 +93,29 @@ func ValidateModuleConfig(name string, config *v1.ModuleConfig) error {
 	return nil
 }
 
+// ValidateModuleTrustPolicy validates the module trust policy is valid or not.
+func ValidateModuleTrustPolicy(policy *v1.ModuleTrustPolicy) error {
+	for registry, signers := range policy.Registries {
+		if registry == "" {
+			return ErrEmptyModuleTrustRegistry
+		}
+		if signers == nil || (len(signers.PublicKeys) == 0 && len(signers.KeylessIdentities) == 0) {
+			return fmt.Errorf("%w, registry: %s", ErrEmptyModuleSigners, registry)
+		}
+		for _, key := range signers.PublicKeys {
+			if key == "" {
+				return fmt.Errorf("empty public key of registry %s in module trust policy", registry)
+			}
+		}
+		for _, identity := range signers.KeylessIdentities {
+			if identity == nil || identity.Issuer == "" || identity.Subject == "" {
+				return fmt.Errorf("%w, registry: %s", ErrInvalidKeylessIdentity, registry)
+			}
+		}
+	}
+	return nil
+}
+
 func ValidateModuleMetadata(name string, config *v1.ModuleConfig) error {
 	if config.Version == "" {
 		return fmt.Errorf("empty version of module:%s in the workspacek config", name)
@@ -299,6 +299,66 @@ func TestValidateModuleMetadata(t *testing.T) {
 	})
 }
 
+func TestValidateModuleTrustPolicy(t *testing.T) {
+	testcases := []struct {
+		name    string
+		success bool
+		policy  *v1.ModuleTrustPolicy
+	}{
+		{
+			name:    "valid module trust policy",
+			success: true,
+			policy: &v1.ModuleTrustPolicy{
+				Registries: map[string]*v1.ModuleSigners{
+					"ghcr.io/kusionstack": {
+						KeylessIdentities: []*v1.KeylessIdentity{
+							{Issuer: "https://token.actions.githubusercontent.com", Subject: "https://github.com/KusionStack/catalog"},
+						},
+					},
+					"registry.example.com": {PublicKeys: []string{"/etc/kusion/cosign.pub"}},
+				},
+			},
+		},
+		{
+			name:    "invalid module trust policy empty registry",
+			success: false,
+			policy: &v1.ModuleTrustPolicy{
+				Registries: map[string]*v1.ModuleSigners{"": {PublicKeys: []string{"cosign.pub"}}},
+			},
+		},
+		{
+			name:    "invalid module trust policy empty signers",
+			success: false,
+			policy: &v1.ModuleTrustPolicy{
+				Registries: map[string]*v1.ModuleSigners{"ghcr.io": {}},
+			},
+		},
+		{
+			name:    "invalid module trust policy empty public key",
+			success: false,
+			policy: &v1.ModuleTrustPolicy{
+				Registries: map[string]*v1.ModuleSigners{"ghcr.io": {PublicKeys: []string{""}}},
+			},
+		},
+		{
+			name:    "invalid module trust policy keyless identity without subject",
+			success: false,
+			policy: &v1.ModuleTrustPolicy{
+				Registries: map[string]*v1.ModuleSigners{
+					"ghcr.io": {KeylessIdentities: []*v1.KeylessIdentity{{Issuer: "https://accounts.google.com"}}},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateModuleTrustPolicy(tc.policy)
+			assert.Equal(t, tc.success, err == nil)
+		})
+	}
+}
+
 func TestValidateAWSSecretStore(t *testing.T) {
 	type args struct {
 		ss *v1.AWSProvider